@@ -6,6 +6,7 @@ import (
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"go.uber.org/zap"
+	"time"
 
 	api "github.com/attestantio/go-eth2-client/api/v1"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
@@ -19,6 +20,10 @@ type Options struct {
 	BeaconNodeAddr string `yaml:"BeaconNodeAddr" env:"BEACON_NODE_ADDR" env-required:"true"`
 	Graffiti       []byte
 	DB             basedb.IDb
+
+	SubmissionRetries       int           `yaml:"SubmissionRetries" env:"BEACON_SUBMISSION_RETRIES" env-default:"3" env-description:"max retries of a failed submission (attestation, voluntary exit) to the beacon node, attestations are retried until the end of their slot"`
+	CircuitBreakerThreshold int           `yaml:"CircuitBreakerThreshold" env:"BEACON_CIRCUIT_BREAKER_THRESHOLD" env-default:"5" env-description:"consecutive failed submissions that trip the circuit breaker, submissions fail fast while it is open (0 disables)"`
+	CircuitBreakerCooldown  time.Duration `yaml:"CircuitBreakerCooldown" env:"BEACON_CIRCUIT_BREAKER_COOLDOWN" env-default:"30s" env-description:"how long the circuit breaker stays open before a trial submission is allowed"`
 }
 
 // Beacon represents the behavior of the beacon node connector
@@ -35,14 +40,20 @@ type Beacon interface {
 	// GetValidatorData returns metadata (balance, index, status, more) for each pubkey from the node
 	GetValidatorData(validatorPubKeys []spec.BLSPubKey) (map[spec.ValidatorIndex]*api.Validator, error)
 
-	// GetAttestationData returns attestation data by the given slot and committee index
-	GetAttestationData(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error)
+	// GetAttestationData returns attestation data by the given slot and committee index, the context bounds the duty
+	GetAttestationData(ctx context.Context, slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error)
 
-	// SubmitAttestation submit the attestation to the node
-	SubmitAttestation(attestation *spec.Attestation) error
+	// SubmitAttestation submit the attestation to the node, the context bounds the duty
+	SubmitAttestation(ctx context.Context, attestation *spec.Attestation) error
 
 	// SubscribeToCommitteeSubnet subscribe committee to subnet (p2p topic)
 	SubscribeToCommitteeSubnet(subscription []*api.BeaconCommitteeSubscription) error
+
+	// SubmitVoluntaryExit submit the signed voluntary exit to the node, the context bounds the duty
+	SubmitVoluntaryExit(ctx context.Context, exit *spec.SignedVoluntaryExit) error
+
+	// GetSyncState returns the sync state (head slot, sync distance) of the node
+	GetSyncState() (*api.SyncState, error)
 }
 
 // KeyManager is an interface responsible for all key manager functions
@@ -58,10 +69,25 @@ type Signer interface {
 	SignIBFTMessage(message *proto.Message, pk []byte) ([]byte, error)
 	// SignAttestation signs the given attestation
 	SignAttestation(data *spec.AttestationData, duty *Duty, pk []byte) (*spec.Attestation, []byte, error)
+	// SignVoluntaryExit signs the given voluntary exit, returns the signature and the signing root
+	SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error)
+}
+
+// SlashingChecker is an optional interface of signers, used to check values before consensus
+type SlashingChecker interface {
+	// IsAttestationSlashable returns an error if signing the given attestation data with the given key is slashable
+	IsAttestationSlashable(data *spec.AttestationData, pk []byte) error
+}
+
+// BlockAttestationsProvider is an optional interface of beacon clients, used to verify the inclusion of attestations
+type BlockAttestationsProvider interface {
+	// GetBlockAttestations returns the attestations of the block in the given slot, returns false if the slot has no block
+	GetBlockAttestations(slot spec.Slot) ([]*spec.Attestation, bool, error)
 }
 
 // SigningUtil is an interface for beacon node signing specific methods
 type SigningUtil interface {
 	GetDomain(data *spec.AttestationData) ([]byte, error)
+	GetVoluntaryExitDomain(epoch spec.Epoch) ([]byte, error)
 	ComputeSigningRoot(object interface{}, domain []byte) ([32]byte, error)
 }
@@ -15,6 +15,7 @@ type DutyData struct {
 	//	*InputValueAttestation
 	//	*InputValue_Aggregation
 	//	*InputValue_Block
+	//	*InputValueVoluntaryExit
 	SignedData IsInputValueSignedData `protobuf_oneof:"signed_data"`
 }
 
@@ -75,3 +76,19 @@ func (m *DutyData) GetAttestation() *phase0.Attestation {
 	}
 	return nil
 }
+
+// InputValueVoluntaryExit implementing IsInputValueSignedData
+type InputValueVoluntaryExit struct {
+	VoluntaryExit *phase0.SignedVoluntaryExit
+}
+
+// isInputValueSignedData implementation
+func (*InputValueVoluntaryExit) isInputValueSignedData() {}
+
+// GetVoluntaryExit return cast voluntary exit input data
+func (m *DutyData) GetVoluntaryExit() *phase0.SignedVoluntaryExit {
+	if x, ok := m.GetSignedData().(*InputValueVoluntaryExit); ok {
+		return x.VoluntaryExit
+	}
+	return nil
+}
@@ -1,13 +1,20 @@
 package goclient
 
 import (
+	"context"
 	eth2client "github.com/attestantio/go-eth2-client"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/pkg/errors"
 )
 
-func (gc *goClient) GetAttestationData(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+// GetAttestationData returns attestation data, which is fetched once per slot and committee index
+// and shared among validators
+func (gc *goClient) GetAttestationData(ctx context.Context, slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+	return gc.attestationData.Get(ctx, slot, committeeIndex)
+}
+
+func (gc *goClient) fetchAttestationData(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
 	if provider, isProvider := gc.client.(eth2client.AttestationDataProvider); isProvider {
 		gc.waitOneThirdOrValidBlock(uint64(slot))
 		attestationData, err := provider.AttestationData(gc.ctx, slot, committeeIndex)
@@ -24,18 +31,22 @@ func (gc *goClient) SignAttestation(data *spec.AttestationData, duty *beacon.Dut
 }
 
 // SubmitAttestation implements Beacon interface
-func (gc *goClient) SubmitAttestation(attestation *spec.Attestation) error {
+func (gc *goClient) SubmitAttestation(ctx context.Context, attestation *spec.Attestation) error {
 	if provider, isProvider := gc.client.(eth2client.AttestationsSubmitter); isProvider {
 		signingRoot, err := gc.getSigningRoot(attestation.Data)
 		if err != nil {
 			return errors.Wrap(err, "failed to get signing root")
 		}
 
-		if err := gc.slashableAttestationCheck(gc.ctx, signingRoot); err != nil {
+		if err := gc.slashableAttestationCheck(ctx, signingRoot); err != nil {
 			return errors.Wrap(err, "failed attestation slashing protection check")
 		}
 
-		return provider.SubmitAttestations(gc.ctx, []*spec.Attestation{attestation})
+		// the attestation is retried until the end of its slot
+		deadline := gc.slotStartTime(uint64(attestation.Data.Slot) + 1)
+		return gc.submitter.submit(ctx, "attestation", deadline, func() error {
+			return provider.SubmitAttestations(ctx, []*spec.Attestation{attestation})
+		})
 	}
 	return nil
 }
@@ -0,0 +1,112 @@
+package goclient
+
+import (
+	"context"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// attestationDataSlotsToKeep is the number of slots that cached attestation data is kept for
+const attestationDataSlotsToKeep = 2
+
+type attestationDataFetcher func(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error)
+
+type attestationDataKey struct {
+	slot           spec.Slot
+	committeeIndex spec.CommitteeIndex
+}
+
+// attestationDataEntry holds the result of a single fetch, done is closed once the fetch is over
+type attestationDataEntry struct {
+	done chan struct{}
+	data *spec.AttestationData
+	err  error
+}
+
+// attestationDataCache shares attestation data among validators that attest in the same slot and committee,
+// concurrent requests for the same (slot, committee index) wait for a single beacon call.
+// failed fetches are not cached
+type attestationDataCache struct {
+	fetch   attestationDataFetcher
+	lock    sync.Mutex
+	entries map[attestationDataKey]*attestationDataEntry
+}
+
+func newAttestationDataCache(fetch attestationDataFetcher) *attestationDataCache {
+	return &attestationDataCache{
+		fetch:   fetch,
+		entries: make(map[attestationDataKey]*attestationDataEntry),
+	}
+}
+
+// Get returns a copy of the attestation data of the given slot and committee index.
+// the fetch is shared by all the callers, therefore the given context cancels only the wait of this caller
+func (c *attestationDataCache) Get(ctx context.Context, slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+	key := attestationDataKey{slot: slot, committeeIndex: committeeIndex}
+
+	c.lock.Lock()
+	entry, found := c.entries[key]
+	if !found {
+		entry = &attestationDataEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+		c.pruneUnsafe(slot)
+	}
+	c.lock.Unlock()
+
+	if found {
+		metricsAttestationDataRequests.WithLabelValues("cache").Inc()
+	} else {
+		metricsAttestationDataRequests.WithLabelValues("beacon").Inc()
+		go c.fetchEntry(key, entry)
+	}
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "could not get attestation data")
+	}
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return copyAttestationData(entry.data), nil
+}
+
+// fetchEntry fetches the data of the given entry, failed fetches are removed from the cache
+func (c *attestationDataCache) fetchEntry(key attestationDataKey, entry *attestationDataEntry) {
+	entry.data, entry.err = c.fetch(key.slot, key.committeeIndex)
+	if entry.err != nil {
+		c.lock.Lock()
+		delete(c.entries, key)
+		c.lock.Unlock()
+	}
+	close(entry.done)
+}
+
+// pruneUnsafe removes entries of old slots, must be called under lock
+func (c *attestationDataCache) pruneUnsafe(slot spec.Slot) {
+	if slot < attestationDataSlotsToKeep {
+		return
+	}
+	for key := range c.entries {
+		if key.slot <= slot-attestationDataSlotsToKeep {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// copyAttestationData returns a copy of the given data, so validators won't share the same object
+func copyAttestationData(data *spec.AttestationData) *spec.AttestationData {
+	if data == nil {
+		return nil
+	}
+	cp := *data
+	if data.Source != nil {
+		source := *data.Source
+		cp.Source = &source
+	}
+	if data.Target != nil {
+		target := *data.Target
+		cp.Target = &target
+	}
+	return &cp
+}
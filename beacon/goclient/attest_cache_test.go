@@ -0,0 +1,99 @@
+package goclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttestationDataCache(t *testing.T) {
+	var calls int64
+	cache := newAttestationDataCache(func(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+		atomic.AddInt64(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		return &spec.AttestationData{
+			Slot:   slot,
+			Index:  committeeIndex,
+			Source: &spec.Checkpoint{Epoch: 1},
+			Target: &spec.Checkpoint{Epoch: 2},
+		}, nil
+	})
+
+	t.Run("concurrent requests share a single fetch", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				data, err := cache.Get(context.Background(), 10, 1)
+				require.NoError(t, err)
+				require.EqualValues(t, 10, data.Slot)
+				require.EqualValues(t, 1, data.Index)
+			}()
+		}
+		wg.Wait()
+		require.EqualValues(t, 1, atomic.LoadInt64(&calls))
+
+		_, err := cache.Get(context.Background(), 10, 2)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, atomic.LoadInt64(&calls))
+	})
+
+	t.Run("returns copies", func(t *testing.T) {
+		data, err := cache.Get(context.Background(), 10, 1)
+		require.NoError(t, err)
+		data.Source.Epoch = 100
+		data, err = cache.Get(context.Background(), 10, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, data.Source.Epoch)
+	})
+
+	t.Run("prunes old slots", func(t *testing.T) {
+		_, err := cache.Get(context.Background(), 12, 1)
+		require.NoError(t, err)
+		cache.lock.Lock()
+		defer cache.lock.Unlock()
+		require.Len(t, cache.entries, 1)
+	})
+}
+
+func TestAttestationDataCache_Error(t *testing.T) {
+	fail := true
+	cache := newAttestationDataCache(func(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+		if fail {
+			return nil, errors.New("test error")
+		}
+		return &spec.AttestationData{Slot: slot}, nil
+	})
+	_, err := cache.Get(context.Background(), 1, 1)
+	require.EqualError(t, err, "test error")
+	// errors are not cached
+	fail = false
+	data, err := cache.Get(context.Background(), 1, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, data.Slot)
+}
+
+func TestAttestationDataCache_Canceled(t *testing.T) {
+	release := make(chan struct{})
+	cache := newAttestationDataCache(func(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+		<-release
+		return &spec.AttestationData{Slot: slot}, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := cache.Get(ctx, 1, 1)
+	require.EqualError(t, err, "could not get attestation data: context deadline exceeded")
+
+	// the fetch is not canceled, other callers get its result
+	close(release)
+	data, err := cache.Get(context.Background(), 1, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, data.Slot)
+}
@@ -0,0 +1,28 @@
+package goclient
+
+import (
+	"fmt"
+	eth2client "github.com/attestantio/go-eth2-client"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// GetBlockAttestations implements beacon.BlockAttestationsProvider
+func (gc *goClient) GetBlockAttestations(slot spec.Slot) ([]*spec.Attestation, bool, error) {
+	if provider, isProvider := gc.client.(eth2client.SignedBeaconBlockProvider); isProvider {
+		block, err := provider.SignedBeaconBlock(gc.ctx, fmt.Sprintf("%d", slot))
+		if err != nil {
+			return nil, false, err
+		}
+		if block == nil {
+			// no block in the slot
+			return nil, false, nil
+		}
+		attestations, err := block.Attestations()
+		if err != nil {
+			return nil, false, errors.Wrap(err, "could not get block attestations")
+		}
+		return attestations, true, nil
+	}
+	return nil, false, errors.New("client does not support SignedBeaconBlockProvider")
+}
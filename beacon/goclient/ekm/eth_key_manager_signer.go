@@ -77,8 +77,12 @@ func (km *ethKeyManagerSigner) AddShare(shareKey *bls.SecretKey) error {
 		return errors.Wrap(err, "could not check share existence")
 	}
 	if acc == nil {
-		if err := km.storage.SaveHighestAttestation(shareKey.GetPublicKey().Serialize(), zeroSlotAttestation); err != nil {
-			return errors.Wrap(err, "could not save zero highest attestation")
+		// keeping an existing (e.g. imported) slashing protection history
+		pk := shareKey.GetPublicKey().Serialize()
+		if km.storage.RetrieveHighestAttestation(pk) == nil {
+			if err := km.storage.SaveHighestAttestation(pk, zeroSlotAttestation); err != nil {
+				return errors.Wrap(err, "could not save zero highest attestation")
+			}
 		}
 		if err := km.saveShare(shareKey); err != nil {
 			return errors.Wrap(err, "could not save share")
@@ -137,6 +141,44 @@ func (km *ethKeyManagerSigner) SignAttestation(data *spec.AttestationData, duty
 	}, root[:], nil
 }
 
+// IsAttestationSlashable checks the given attestation data against the slashing protection history of the share
+func (km *ethKeyManagerSigner) IsAttestationSlashable(data *spec.AttestationData, pk []byte) error {
+	protector := slashingprotection.NewNormalProtection(km.storage)
+	status, err := protector.IsSlashableAttestation(pk, specAttDataToPrysmAttData(data))
+	if err != nil {
+		return errors.Wrap(err, "could not check slashing protection")
+	}
+	if status != nil {
+		return errors.Errorf("slashable attestation (%s)", status.Status)
+	}
+	return nil
+}
+
+// SignVoluntaryExit signs the given voluntary exit with the share key.
+// voluntary exits are not slashable, therefore no slashing protection is applied
+func (km *ethKeyManagerSigner) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	km.walletLock.RLock()
+	defer km.walletLock.RUnlock()
+
+	domain, err := km.signingUtils.GetVoluntaryExitDomain(exit.Epoch)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get domain for signing")
+	}
+	root, err := km.signingUtils.ComputeSigningRoot(exit, domain)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get root for signing")
+	}
+	account, err := km.wallet.AccountByPublicKey(hex.EncodeToString(pk))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not get signing account")
+	}
+	sig, err := account.ValidationKeySign(root[:])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not sign voluntary exit")
+	}
+	return sig, root[:], nil
+}
+
 func (km *ethKeyManagerSigner) saveShare(shareKey *bls.SecretKey) error {
 	key, err := core.NewHDKeyFromPrivateKey(shareKey.Serialize(), "")
 	if err != nil {
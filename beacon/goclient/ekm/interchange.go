@@ -0,0 +1,238 @@
+package ekm
+
+import (
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
+	types "github.com/prysmaticlabs/eth2-types"
+	eth "github.com/prysmaticlabs/prysm/proto/prysm/v1alpha1"
+)
+
+// InterchangeFormatVersion is the supported version of EIP-3076 interchange format
+const InterchangeFormatVersion = "5"
+
+// genesisValidatorsRoots are the genesis validators roots of the supported networks
+var genesisValidatorsRoots = map[core.Network]string{
+	core.MainNetwork:   "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95",
+	core.PraterNetwork: "0x043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb",
+}
+
+// Interchange is the EIP-3076 slashing protection interchange format
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []InterchangeData   `json:"data"`
+}
+
+// InterchangeMetadata is the metadata of Interchange
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// InterchangeData holds the signing history of a single key, numbers are encoded as decimal strings
+type InterchangeData struct {
+	Pubkey             string                         `json:"pubkey"`
+	SignedBlocks       []InterchangeSignedBlock       `json:"signed_blocks"`
+	SignedAttestations []InterchangeSignedAttestation `json:"signed_attestations"`
+}
+
+// InterchangeSignedBlock is a block that was signed
+type InterchangeSignedBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// InterchangeSignedAttestation is an attestation that was signed
+type InterchangeSignedAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// ExportSlashingProtection exports the slashing protection of all the share keys in the given db.
+// only the highest attestation and proposal are kept in storage, therefore the minimal format is produced
+func ExportSlashingProtection(db basedb.IDb, network core.Network) (*Interchange, error) {
+	root, ok := genesisValidatorsRoots[network]
+	if !ok {
+		return nil, errors.Errorf("unsupported network: %s", network)
+	}
+	s := newSignerStorage(db, network)
+	// keys may have only a highest attestation or only a highest proposal
+	keys := make(map[string][]byte)
+	for _, prefix := range []string{highestAttPrefix, highestProposalPrefix} {
+		objs, err := db.GetAllByCollection(s.objPrefix(prefix))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get slashing protection keys")
+		}
+		for _, obj := range objs {
+			keys[hex.EncodeToString(obj.Key)] = obj.Key
+		}
+	}
+	res := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    root,
+		},
+		Data: make([]InterchangeData, 0, len(keys)),
+	}
+	for pkHex, pk := range keys {
+		item := InterchangeData{
+			Pubkey:             "0x" + pkHex,
+			SignedBlocks:       []InterchangeSignedBlock{},
+			SignedAttestations: []InterchangeSignedAttestation{},
+		}
+		if att := s.RetrieveHighestAttestation(pk); att != nil {
+			item.SignedAttestations = append(item.SignedAttestations, InterchangeSignedAttestation{
+				SourceEpoch: strconv.FormatUint(uint64(att.Source.Epoch), 10),
+				TargetEpoch: strconv.FormatUint(uint64(att.Target.Epoch), 10),
+			})
+		}
+		if block := s.RetrieveHighestProposal(pk); block != nil {
+			item.SignedBlocks = append(item.SignedBlocks, InterchangeSignedBlock{
+				Slot: strconv.FormatUint(uint64(block.Slot), 10),
+			})
+		}
+		res.Data = append(res.Data, item)
+	}
+	sort.Slice(res.Data, func(i, j int) bool {
+		return res.Data[i].Pubkey < res.Data[j].Pubkey
+	})
+	return res, nil
+}
+
+// ImportSlashingProtection imports the given slashing protection into the db.
+// the stored history is merged with the imported one, i.e. the highest attestation and proposal of a key
+// are only raised, so an import never makes slashable messages signable.
+// returns the number of keys that were imported
+func ImportSlashingProtection(db basedb.IDb, network core.Network, interchange *Interchange) (int, error) {
+	if err := validateInterchangeMetadata(interchange.Metadata, network); err != nil {
+		return 0, err
+	}
+	s := newSignerStorage(db, network)
+	// validating all the data before saving anything
+	type keyHistory struct {
+		pk    []byte
+		att   *eth.AttestationData
+		block *eth.BeaconBlock
+	}
+	histories := make([]keyHistory, 0, len(interchange.Data))
+	for _, item := range interchange.Data {
+		pk, err := hex.DecodeString(strings.TrimPrefix(item.Pubkey, "0x"))
+		if err != nil || len(pk) != 48 {
+			return 0, errors.Errorf("invalid pubkey: %s", item.Pubkey)
+		}
+		att, err := highestInterchangeAttestation(item.SignedAttestations)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid attestations of %s", item.Pubkey)
+		}
+		block, err := highestInterchangeBlock(item.SignedBlocks)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid blocks of %s", item.Pubkey)
+		}
+		histories = append(histories, keyHistory{pk: pk, att: att, block: block})
+	}
+	for _, h := range histories {
+		if h.att != nil {
+			if stored := s.RetrieveHighestAttestation(h.pk); stored != nil {
+				h.att.Source.Epoch = maxEpoch(h.att.Source.Epoch, stored.Source.Epoch)
+				h.att.Target.Epoch = maxEpoch(h.att.Target.Epoch, stored.Target.Epoch)
+			}
+			if err := s.SaveHighestAttestation(h.pk, h.att); err != nil {
+				return 0, errors.Wrap(err, "could not save highest attestation")
+			}
+		}
+		if h.block != nil {
+			if stored := s.RetrieveHighestProposal(h.pk); stored == nil || stored.Slot < h.block.Slot {
+				if err := s.SaveHighestProposal(h.pk, h.block); err != nil {
+					return 0, errors.Wrap(err, "could not save highest proposal")
+				}
+			}
+		}
+	}
+	return len(histories), nil
+}
+
+// validateInterchangeMetadata checks the version and the network of the interchange
+func validateInterchangeMetadata(metadata InterchangeMetadata, network core.Network) error {
+	if metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return errors.Errorf("unsupported interchange format version: %s", metadata.InterchangeFormatVersion)
+	}
+	root, ok := genesisValidatorsRoots[network]
+	if !ok {
+		return errors.Errorf("unsupported network: %s", network)
+	}
+	if !strings.EqualFold(metadata.GenesisValidatorsRoot, root) {
+		return errors.Errorf("genesis validators root %s doesn't match network %s", metadata.GenesisValidatorsRoot, network)
+	}
+	return nil
+}
+
+// highestInterchangeAttestation returns an attestation with the highest source and target epochs, or nil if empty
+func highestInterchangeAttestation(atts []InterchangeSignedAttestation) (*eth.AttestationData, error) {
+	if len(atts) == 0 {
+		return nil, nil
+	}
+	highest := &eth.AttestationData{
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &eth.Checkpoint{Root: make([]byte, 32)},
+		Target:          &eth.Checkpoint{Root: make([]byte, 32)},
+	}
+	for _, att := range atts {
+		source, err := strconv.ParseUint(att.SourceEpoch, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid source epoch")
+		}
+		target, err := strconv.ParseUint(att.TargetEpoch, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid target epoch")
+		}
+		if source > target {
+			return nil, errors.Errorf("source epoch %d is higher than target epoch %d", source, target)
+		}
+		highest.Source.Epoch = maxEpoch(highest.Source.Epoch, types.Epoch(source))
+		highest.Target.Epoch = maxEpoch(highest.Target.Epoch, types.Epoch(target))
+	}
+	return highest, nil
+}
+
+// highestInterchangeBlock returns a block with the highest slot, or nil if empty
+func highestInterchangeBlock(blocks []InterchangeSignedBlock) (*eth.BeaconBlock, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	var highest uint64
+	for _, block := range blocks {
+		slot, err := strconv.ParseUint(block.Slot, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid slot")
+		}
+		if slot > highest {
+			highest = slot
+		}
+	}
+	return &eth.BeaconBlock{
+		Slot:       types.Slot(highest),
+		ParentRoot: make([]byte, 32),
+		StateRoot:  make([]byte, 32),
+		Body: &eth.BeaconBlockBody{
+			RandaoReveal: make([]byte, 96),
+			Eth1Data: &eth.Eth1Data{
+				DepositRoot: make([]byte, 32),
+				BlockHash:   make([]byte, 32),
+			},
+			Graffiti: make([]byte, 32),
+		},
+	}, nil
+}
+
+func maxEpoch(a, b types.Epoch) types.Epoch {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,112 @@
+package ekm
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func testInterchange(pk string, source, target, slot string) *Interchange {
+	return &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    genesisValidatorsRoots[core.PraterNetwork],
+		},
+		Data: []InterchangeData{{
+			Pubkey:             pk,
+			SignedBlocks:       []InterchangeSignedBlock{{Slot: slot}},
+			SignedAttestations: []InterchangeSignedAttestation{{SourceEpoch: "1", TargetEpoch: "2"}, {SourceEpoch: source, TargetEpoch: target}},
+		}},
+	}
+}
+
+func TestImportExportSlashingProtection(t *testing.T) {
+	threshold.Init()
+	sk := &bls.SecretKey{}
+	sk.SetByCSPRNG()
+	pk := "0x" + sk.GetPublicKey().SerializeToHexStr()
+	db := getStorage(t)
+	defer db.Close()
+
+	n, err := ImportSlashingProtection(db, core.PraterNetwork, testInterchange(pk, "10", "11", "100"))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// adding the share keeps the imported history
+	km, err := NewETHKeyManagerSigner(db, nil, core.PraterNetwork)
+	require.NoError(t, err)
+	require.NoError(t, km.AddShare(sk))
+
+	exported, err := ExportSlashingProtection(db, core.PraterNetwork)
+	require.NoError(t, err)
+	require.Len(t, exported.Data, 1)
+	require.Equal(t, pk, exported.Data[0].Pubkey)
+	require.Equal(t, []InterchangeSignedAttestation{{SourceEpoch: "10", TargetEpoch: "11"}}, exported.Data[0].SignedAttestations)
+	require.Equal(t, []InterchangeSignedBlock{{Slot: "100"}}, exported.Data[0].SignedBlocks)
+
+	// lower history doesn't override the stored one
+	_, err = ImportSlashingProtection(db, core.PraterNetwork, testInterchange(pk, "12", "5", "50"))
+	require.EqualError(t, err, "invalid attestations of "+pk+": source epoch 12 is higher than target epoch 5")
+	_, err = ImportSlashingProtection(db, core.PraterNetwork, testInterchange(pk, "3", "20", "50"))
+	require.NoError(t, err)
+	exported, err = ExportSlashingProtection(db, core.PraterNetwork)
+	require.NoError(t, err)
+	require.Equal(t, []InterchangeSignedAttestation{{SourceEpoch: "10", TargetEpoch: "20"}}, exported.Data[0].SignedAttestations)
+	require.Equal(t, []InterchangeSignedBlock{{Slot: "100"}}, exported.Data[0].SignedBlocks)
+}
+
+func TestImportSlashingProtection_Invalid(t *testing.T) {
+	db := getStorage(t)
+	defer db.Close()
+	pk := "0x" + hex.EncodeToString(make([]byte, 48))
+
+	interchange := testInterchange(pk, "1", "2", "1")
+	interchange.Metadata.InterchangeFormatVersion = "4"
+	_, err := ImportSlashingProtection(db, core.PraterNetwork, interchange)
+	require.EqualError(t, err, "unsupported interchange format version: 4")
+
+	_, err = ImportSlashingProtection(db, core.MainNetwork, testInterchange(pk, "1", "2", "1"))
+	require.EqualError(t, err, "genesis validators root "+genesisValidatorsRoots[core.PraterNetwork]+" doesn't match network mainnet")
+
+	_, err = ImportSlashingProtection(db, core.PraterNetwork, testInterchange("0x0102", "1", "2", "1"))
+	require.EqualError(t, err, "invalid pubkey: 0x0102")
+
+	_, err = ImportSlashingProtection(db, core.PraterNetwork, testInterchange(pk, "1", "2", "x"))
+	require.EqualError(t, err, "invalid blocks of "+pk+": invalid slot: strconv.ParseUint: parsing \"x\": invalid syntax")
+
+	exported, err := ExportSlashingProtection(db, core.PraterNetwork)
+	require.NoError(t, err)
+	require.Len(t, exported.Data, 0)
+}
+
+func TestExportSlashingProtection_ProposalOnly(t *testing.T) {
+	db := getStorage(t)
+	defer db.Close()
+	attPk := "0x" + hex.EncodeToString(append(make([]byte, 47), 1))
+	propPk := "0x" + hex.EncodeToString(append(make([]byte, 47), 2))
+
+	interchange := testInterchange(attPk, "10", "11", "100")
+	interchange.Data[0].SignedBlocks = []InterchangeSignedBlock{}
+	interchange.Data = append(interchange.Data, InterchangeData{
+		Pubkey:             propPk,
+		SignedBlocks:       []InterchangeSignedBlock{{Slot: "200"}},
+		SignedAttestations: []InterchangeSignedAttestation{},
+	})
+	n, err := ImportSlashingProtection(db, core.PraterNetwork, interchange)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	exported, err := ExportSlashingProtection(db, core.PraterNetwork)
+	require.NoError(t, err)
+	require.Len(t, exported.Data, 2)
+	require.Equal(t, attPk, exported.Data[0].Pubkey)
+	require.Equal(t, []InterchangeSignedAttestation{{SourceEpoch: "10", TargetEpoch: "11"}}, exported.Data[0].SignedAttestations)
+	require.Len(t, exported.Data[0].SignedBlocks, 0)
+	require.Equal(t, propPk, exported.Data[1].Pubkey)
+	require.Len(t, exported.Data[1].SignedAttestations, 0)
+	require.Equal(t, []InterchangeSignedBlock{{Slot: "200"}}, exported.Data[1].SignedBlocks)
+}
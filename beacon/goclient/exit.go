@@ -0,0 +1,23 @@
+package goclient
+
+import (
+	"context"
+	eth2client "github.com/attestantio/go-eth2-client"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+	"time"
+)
+
+func (gc *goClient) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return gc.keyManager.SignVoluntaryExit(exit, pk)
+}
+
+// SubmitVoluntaryExit implements Beacon interface
+func (gc *goClient) SubmitVoluntaryExit(ctx context.Context, exit *spec.SignedVoluntaryExit) error {
+	if provider, isProvider := gc.client.(eth2client.VoluntaryExitSubmitter); isProvider {
+		return gc.submitter.submit(ctx, "voluntary exit", time.Time{}, func() error {
+			return provider.SubmitVoluntaryExit(ctx, exit)
+		})
+	}
+	return errors.New("client does not support VoluntaryExitSubmitter")
+}
@@ -35,6 +35,10 @@ var (
 		Name: "ssv:beacon:node_status",
 		Help: "Status of the connected beacon node",
 	})
+	metricsAttestationDataRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:beacon:attestation_data_requests",
+		Help: "Count attestation data requests by source (beacon or cache)",
+	}, []string{"source"})
 	statusUnknown beaconNodeStatus = 0
 	statusSyncing beaconNodeStatus = 1
 	statusOK      beaconNodeStatus = 2
@@ -44,6 +48,9 @@ func init() {
 	if err := prometheus.Register(metricsBeaconNodeStatus); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricsAttestationDataRequests); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
 
 // goClient implementing Beacon struct
@@ -55,6 +62,9 @@ type goClient struct {
 	indicesMapLock sync.Mutex
 	graffiti       []byte
 	keyManager     beacon.KeyManager
+
+	attestationData *attestationDataCache
+	submitter       *submitter
 }
 
 // verifies that the client implements HealthCheckAgent
@@ -87,6 +97,9 @@ func New(opt beacon.Options) (beacon.Beacon, error) {
 		indicesMapLock: sync.Mutex{},
 		graffiti:       opt.Graffiti,
 	}
+	_client.attestationData = newAttestationDataCache(_client.fetchAttestationData)
+	_client.submitter = newSubmitter(logger, opt.SubmissionRetries, opt.CircuitBreakerThreshold,
+		opt.CircuitBreakerCooldown)
 
 	_client.keyManager, err = ekm.NewETHKeyManagerSigner(opt.DB, _client, core.PraterNetwork) // TODO need to set dynemic network
 	if err != nil {
@@ -101,24 +114,42 @@ func (gc *goClient) HealthCheck() []string {
 	if gc.client == nil {
 		return []string{"not connected to beacon node"}
 	}
-	if provider, isProvider := gc.client.(eth2client.NodeSyncingProvider); isProvider {
-		ctx, cancel := context.WithTimeout(gc.ctx, healthCheckTimeout)
-		defer cancel()
-		syncState, err := provider.NodeSyncing(ctx)
-		if err != nil {
-			metricsBeaconNodeStatus.Set(float64(statusUnknown))
-			return []string{"could not get beacon node sync state"}
-		}
-		if syncState != nil && syncState.IsSyncing {
-			metricsBeaconNodeStatus.Set(float64(statusSyncing))
-			return []string{fmt.Sprintf("beacon node is currently syncing: head=%d, distance=%d",
-				syncState.HeadSlot, syncState.SyncDistance)}
-		}
+	syncState, err := gc.GetSyncState()
+	if err != nil {
+		metricsBeaconNodeStatus.Set(float64(statusUnknown))
+		return []string{"could not get beacon node sync state"}
+	}
+	if syncState.IsSyncing {
+		metricsBeaconNodeStatus.Set(float64(statusSyncing))
+		return []string{fmt.Sprintf("beacon node is currently syncing: head=%d, distance=%d",
+			syncState.HeadSlot, syncState.SyncDistance)}
 	}
 	metricsBeaconNodeStatus.Set(float64(statusOK))
+	if errs := gc.submitter.healthCheck(); len(errs) > 0 {
+		return errs
+	}
 	return []string{}
 }
 
+// GetSyncState returns the sync state of the beacon node,
+// nodes that don't provide their sync state are assumed to be synced
+func (gc *goClient) GetSyncState() (*api.SyncState, error) {
+	provider, isProvider := gc.client.(eth2client.NodeSyncingProvider)
+	if !isProvider {
+		return &api.SyncState{}, nil
+	}
+	ctx, cancel := context.WithTimeout(gc.ctx, healthCheckTimeout)
+	defer cancel()
+	syncState, err := provider.NodeSyncing(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get sync state")
+	}
+	if syncState == nil {
+		return &api.SyncState{}, nil
+	}
+	return syncState, nil
+}
+
 func (gc *goClient) ExtendIndexMap(index spec.ValidatorIndex, pubKey spec.BLSPubKey) {
 	gc.indicesMapLock.Lock()
 	defer gc.indicesMapLock.Unlock()
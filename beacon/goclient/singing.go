@@ -37,6 +37,19 @@ func (gc *goClient) GetDomain(data *phase0spec.AttestationData) ([]byte, error)
 	return domain[:], nil
 }
 
+// GetVoluntaryExitDomain returns the domain of voluntary exits in the given epoch
+func (gc *goClient) GetVoluntaryExitDomain(epoch phase0spec.Epoch) ([]byte, error) {
+	domainType, err := gc.getDomainType(beacon.RoleTypeVoluntaryExit)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := gc.getDomainData(domainType, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return domain[:], nil
+}
+
 // getDomainType returns domain type by role type
 func (gc *goClient) getDomainType(roleType beacon.RoleType) (*phase0spec.DomainType, error) {
 	if provider, isProvider := gc.client.(eth2client.SpecProvider); isProvider {
@@ -53,6 +66,8 @@ func (gc *goClient) getDomainType(roleType beacon.RoleType) (*phase0spec.DomainT
 			val, exists = spec["DOMAIN_AGGREGATE_AND_PROOF"]
 		case beacon.RoleTypeProposer:
 			val, exists = spec["DOMAIN_BEACON_PROPOSER"]
+		case beacon.RoleTypeVoluntaryExit:
+			val, exists = spec["DOMAIN_VOLUNTARY_EXIT"]
 		default:
 			return nil, errors.New("role type domain is not implemented")
 		}
@@ -0,0 +1,109 @@
+package goclient
+
+import (
+	"context"
+	"fmt"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+	"time"
+)
+
+const (
+	// submissionBackoff is the delay before the first retry of a submission, doubled on every retry
+	submissionBackoff = 250 * time.Millisecond
+	// submissionMaxBackoff caps the delay between submission attempts
+	submissionMaxBackoff = 2 * time.Second
+)
+
+var (
+	metricsSubmissions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:beacon:submissions",
+		Help: "Count submissions to the beacon node by type and result (success, failed or rejected by the circuit breaker)",
+	}, []string{"type", "result"})
+	metricsSubmissionAttempts = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv:beacon:submission_attempts",
+		Help:    "Attempts of submissions to the beacon node",
+		Buckets: []float64{1, 2, 3, 4, 5, 8},
+	}, []string{"type"})
+	metricsCircuitBreakerState = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:beacon:circuit_breaker_state",
+		Help: "State of the circuit breaker of beacon submissions (0 closed, 1 open, 2 half-open)",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsSubmissions); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsSubmissionAttempts); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsCircuitBreakerState); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// submitter submits objects to the beacon node, failed submissions are retried with backoff until the deadline.
+// consecutive failures trip a circuit breaker, so submissions fail fast while the beacon node is unavailable
+type submitter struct {
+	logger  *zap.Logger
+	retries int
+	breaker *tasks.CircuitBreaker
+}
+
+// newSubmitter creates a new submitter
+func newSubmitter(logger *zap.Logger, retries, breakerThreshold int, breakerCooldown time.Duration) *submitter {
+	logger = logger.With(zap.String("who", "submitter"))
+	return &submitter{
+		logger:  logger,
+		retries: retries,
+		breaker: tasks.NewCircuitBreaker(breakerThreshold, breakerCooldown, func(state tasks.CircuitState) {
+			metricsCircuitBreakerState.Set(float64(state))
+			logger.Warn("beacon submissions circuit breaker state was changed", zap.String("state", state.String()))
+		}),
+	}
+}
+
+// submit calls the given function until successful, the retries are exhausted, the context is done
+// or the deadline (if not zero) has passed. the first attempt is made regardless of the deadline
+func (s *submitter) submit(ctx context.Context, name string, deadline time.Time, fn tasks.Fn) error {
+	if err := s.breaker.Allow(); err != nil {
+		metricsSubmissions.WithLabelValues(name, "rejected").Inc()
+		return errors.Wrapf(err, "could not submit %s", name)
+	}
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	attempts, err := tasks.RetryWithPolicy(ctx, fn, tasks.RetryPolicy{
+		MaxAttempts: s.retries + 1,
+		Backoff:     submissionBackoff,
+		MaxBackoff:  submissionMaxBackoff,
+	})
+	s.breaker.Done(err)
+	metricsSubmissionAttempts.WithLabelValues(name).Observe(float64(attempts))
+	if err != nil {
+		metricsSubmissions.WithLabelValues(name, "failed").Inc()
+		return errors.Wrapf(err, "could not submit %s after %d attempts", name, attempts)
+	}
+	metricsSubmissions.WithLabelValues(name, "success").Inc()
+	if attempts > 1 {
+		s.logger.Debug("submitted after retries", zap.String("type", name), zap.Int("attempts", attempts))
+	}
+	return nil
+}
+
+// healthCheck returns an error if the circuit breaker is not closed
+func (s *submitter) healthCheck() []string {
+	state, failures := s.breaker.State()
+	if state == tasks.CircuitClosed {
+		return nil
+	}
+	return []string{fmt.Sprintf("beacon submissions circuit breaker is %s: %d consecutive failures", state, failures)}
+}
@@ -0,0 +1,54 @@
+package goclient
+
+import (
+	"context"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSubmitter(t *testing.T) {
+	s := newSubmitter(zap.L(), 2, 2, time.Minute)
+	testErr := errors.New("test-error")
+
+	t.Run("retries", func(t *testing.T) {
+		calls := 0
+		require.NoError(t, s.submit(context.Background(), "test", time.Time{}, func() error {
+			calls++
+			if calls < 3 {
+				return testErr
+			}
+			return nil
+		}))
+		require.Equal(t, 3, calls)
+		require.Len(t, s.healthCheck(), 0)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		calls := 0
+		err := s.submit(context.Background(), "test", time.Now().Add(-time.Second), func() error {
+			calls++
+			return testErr
+		})
+		require.EqualError(t, err, "could not submit test after 1 attempts: test-error")
+		// a single attempt is made after the deadline
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("circuit breaker", func(t *testing.T) {
+		err := s.submit(context.Background(), "test", time.Now().Add(-time.Second), func() error {
+			return testErr
+		})
+		require.Error(t, err)
+		require.Equal(t, []string{"beacon submissions circuit breaker is open: 2 consecutive failures"}, s.healthCheck())
+
+		err = s.submit(context.Background(), "test", time.Time{}, func() error {
+			t.Fatal("submission should be rejected")
+			return nil
+		})
+		require.True(t, errors.Is(err, tasks.ErrCircuitOpen))
+	})
+}
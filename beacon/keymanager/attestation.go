@@ -0,0 +1,20 @@
+package keymanager
+
+import (
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// newAttestation returns the attestation of the duty with the given (partial) signature
+func newAttestation(data *spec.AttestationData, duty *beacon.Duty, sig []byte) *spec.Attestation {
+	aggregationBitfield := bitfield.NewBitlist(duty.CommitteeLength)
+	aggregationBitfield.SetBitAt(duty.ValidatorCommitteeIndex, true)
+	blsSig := spec.BLSSignature{}
+	copy(blsSig[:], sig)
+	return &spec.Attestation{
+		AggregationBits: aggregationBitfield,
+		Data:            data,
+		Signature:       blsSig,
+	}
+}
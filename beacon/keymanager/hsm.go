@@ -0,0 +1,43 @@
+package keymanager
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/pkg/errors"
+	"net/url"
+	"time"
+)
+
+// HSMOptions holds the configuration of a remote signer that keeps the share keys in an HSM,
+// e.g. web3signer with a YubiHSM or AWS CloudHSM key store.
+// as the keys never leave the HSM, the signer must be reached over https with a client certificate (mTLS)
+type HSMOptions struct {
+	URL       string        `yaml:"URL" env:"HSM_SIGNER_URL" env-description:"Base url (https) of the HSM backed signer, the hsm backend is disabled if empty"`
+	Token     string        `yaml:"Token" env:"HSM_SIGNER_TOKEN" env-description:"Bearer token for authenticating HSM signer requests"`
+	Timeout   time.Duration `yaml:"Timeout" env:"HSM_SIGNER_TIMEOUT" env-default:"5s" env-description:"Timeout of HSM signer requests"`
+	TLSCert   string        `yaml:"TLSCert" env:"HSM_SIGNER_TLS_CERT" env-description:"Path to the client certificate (PEM) for mTLS with the HSM signer"`
+	TLSKey    string        `yaml:"TLSKey" env:"HSM_SIGNER_TLS_KEY" env-description:"Path to the client private key (PEM) for mTLS with the HSM signer"`
+	TLSCACert string        `yaml:"TLSCACert" env:"HSM_SIGNER_TLS_CA_CERT" env-description:"Path to the CA certificate (PEM) of the HSM signer"`
+}
+
+// NewHSMKeyManager creates a key manager that delegates signing to an HSM backed signer,
+// it uses the remote signer API and requires https and a client certificate
+func NewHSMKeyManager(opts HSMOptions, signingUtils beacon.SigningUtil) (beacon.KeyManager, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse HSM signer url")
+	}
+	if u.Scheme != "https" {
+		return nil, errors.New("HSM signer url must use https")
+	}
+	if len(opts.TLSCert) == 0 || len(opts.TLSKey) == 0 {
+		return nil, errors.New("HSM signer requires a client certificate and key")
+	}
+	return NewRemoteKeyManager(RemoteOptions{
+		URL:       opts.URL,
+		Token:     opts.Token,
+		Timeout:   opts.Timeout,
+		TLSCert:   opts.TLSCert,
+		TLSKey:    opts.TLSKey,
+		TLSCACert: opts.TLSCACert,
+	}, signingUtils)
+}
@@ -0,0 +1,120 @@
+package keymanager
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestClientCert writes a self signed client certificate and its key to dir
+func writeTestClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ssv-node"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	require.NoError(t, ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return cert, certPath, keyPath
+}
+
+// newTestHSMSigner creates an https signer that holds the given keys and requires the given client certificate,
+// returns the server and the path of its CA certificate
+func newTestHSMSigner(t *testing.T, dir string, clientCert *x509.Certificate, keys ...*bls.SecretKey) (*httptest.Server, string) {
+	server := httptest.NewUnstartedServer(newTestSignerHandler(t, "", keys...))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+
+	caPath := filepath.Join(dir, "ca.crt")
+	require.NoError(t, ioutil.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	return server, caPath
+}
+
+func TestNewHSMKeyManager(t *testing.T) {
+	dir := t.TempDir()
+	_, certPath, keyPath := writeTestClientCert(t, dir)
+
+	_, err := NewHSMKeyManager(HSMOptions{URL: "http://127.0.0.1:9000", TLSCert: certPath, TLSKey: keyPath}, &testSigningUtil{})
+	require.EqualError(t, err, "HSM signer url must use https")
+
+	_, err = NewHSMKeyManager(HSMOptions{URL: "https://127.0.0.1:9000"}, &testSigningUtil{})
+	require.EqualError(t, err, "HSM signer requires a client certificate and key")
+
+	_, err = NewHSMKeyManager(HSMOptions{URL: "https://127.0.0.1:9000", TLSCert: certPath, TLSKey: keyPath,
+		TLSCACert: filepath.Join(dir, "missing.crt")}, &testSigningUtil{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not read remote signer CA certificate")
+}
+
+func TestHSMKeyManager(t *testing.T) {
+	threshold.Init()
+	sk := &bls.SecretKey{}
+	sk.SetByCSPRNG()
+	dir := t.TempDir()
+	clientCert, certPath, keyPath := writeTestClientCert(t, dir)
+	server, caPath := newTestHSMSigner(t, dir, clientCert, sk)
+	defer server.Close()
+
+	m, err := New(Options{HSM: HSMOptions{
+		URL:       server.URL,
+		Timeout:   5 * time.Second,
+		TLSCert:   certPath,
+		TLSKey:    keyPath,
+		TLSCACert: caPath,
+	}}, &testKeyManager{}, &testSigningUtil{}, func(pk []byte) (Backend, bool) {
+		if hex.EncodeToString(pk) == sk.GetPublicKey().SerializeToHexStr() {
+			return BackendHSM, true
+		}
+		return "", false
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.AddShare(sk))
+
+	msg := &proto.Message{Type: proto.RoundState_Commit, Lambda: []byte("lambda")}
+	sigBytes, err := m.SignIBFTMessage(msg, sk.GetPublicKey().Serialize())
+	require.NoError(t, err)
+	root, err := msg.SigningRoot()
+	require.NoError(t, err)
+	sig := &bls.Sign{}
+	require.NoError(t, sig.Deserialize(sigBytes))
+	require.True(t, sig.VerifyByte(sk.GetPublicKey(), root))
+
+	// the signer rejects clients w/o a certificate
+	noClientCert, err := NewRemoteKeyManager(RemoteOptions{URL: server.URL, TLSCACert: caPath}, &testSigningUtil{})
+	require.NoError(t, err)
+	require.Error(t, noClientCert.AddShare(sk))
+}
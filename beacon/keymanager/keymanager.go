@@ -0,0 +1,174 @@
+package keymanager
+
+import (
+	"encoding/hex"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// Backend is the name of a key manager implementation
+type Backend string
+
+const (
+	// BackendLocal keeps share keys in the node's db (eth2-key-manager wallet)
+	BackendLocal Backend = "local"
+	// BackendRemote delegates signing to a remote signer
+	BackendRemote Backend = "remote"
+	// BackendHSM delegates signing to a remote signer that keeps the keys in an HSM
+	BackendHSM Backend = "hsm"
+)
+
+// Options holds the configuration of the key manager backends
+type Options struct {
+	Default Backend       `yaml:"Default" env:"KEY_MANAGER" env-default:"local" env-description:"Key manager backend of shares that don't specify one (local, remote or hsm)"`
+	Remote  RemoteOptions `yaml:"Remote"`
+	HSM     HSMOptions    `yaml:"HSM"`
+}
+
+// ShareBackendResolver returns the backend that was chosen for the share with the given public key,
+// returns false if the share doesn't specify a backend
+type ShareBackendResolver func(pk []byte) (Backend, bool)
+
+// Invalidator is implemented by key managers that cache the backends of shares,
+// it should be called once the backend of a share might have changed, i.e. the share was saved or removed
+type Invalidator interface {
+	Invalidate(pk []byte)
+}
+
+// Manager is a beacon.KeyManager that routes every operation to the backend of the share
+type Manager struct {
+	backends       map[Backend]beacon.KeyManager
+	defaultBackend Backend
+	resolve        ShareBackendResolver
+
+	// cache holds the resolved backends by public key (hex)
+	cache     map[string]Backend
+	cacheLock sync.RWMutex
+}
+
+// New creates a Manager with the local backend and the backends that were configured
+func New(opts Options, local beacon.KeyManager, signingUtil beacon.SigningUtil, resolve ShareBackendResolver) (*Manager, error) {
+	backends := map[Backend]beacon.KeyManager{
+		BackendLocal: local,
+	}
+	if len(opts.Remote.URL) > 0 {
+		km, err := NewRemoteKeyManager(opts.Remote, signingUtil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create remote key manager")
+		}
+		backends[BackendRemote] = km
+	}
+	if len(opts.HSM.URL) > 0 {
+		km, err := NewHSMKeyManager(opts.HSM, signingUtil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create hsm key manager")
+		}
+		backends[BackendHSM] = km
+	}
+	defaultBackend := opts.Default
+	if len(defaultBackend) == 0 {
+		defaultBackend = BackendLocal
+	}
+	if _, ok := backends[defaultBackend]; !ok {
+		return nil, errors.Errorf("default key manager backend %s is not configured", defaultBackend)
+	}
+	return &Manager{
+		backends:       backends,
+		defaultBackend: defaultBackend,
+		resolve:        resolve,
+		cache:          map[string]Backend{},
+	}, nil
+}
+
+// AddShare adds the share key to the backend of the share
+func (m *Manager) AddShare(shareKey *bls.SecretKey) error {
+	km, err := m.backend(shareKey.GetPublicKey().Serialize())
+	if err != nil {
+		return err
+	}
+	return km.AddShare(shareKey)
+}
+
+// SignIBFTMessage implements beacon.Signer
+func (m *Manager) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte, error) {
+	km, err := m.backend(pk)
+	if err != nil {
+		return nil, err
+	}
+	return km.SignIBFTMessage(message, pk)
+}
+
+// SignAttestation implements beacon.Signer
+func (m *Manager) SignAttestation(data *spec.AttestationData, duty *beacon.Duty, pk []byte) (*spec.Attestation, []byte, error) {
+	km, err := m.backend(pk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return km.SignAttestation(data, duty, pk)
+}
+
+// SignVoluntaryExit implements beacon.Signer
+func (m *Manager) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	km, err := m.backend(pk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return km.SignVoluntaryExit(exit, pk)
+}
+
+// IsAttestationSlashable implements beacon.SlashingChecker, the check is skipped if the backend doesn't support it
+func (m *Manager) IsAttestationSlashable(data *spec.AttestationData, pk []byte) error {
+	km, err := m.backend(pk)
+	if err != nil {
+		return err
+	}
+	if checker, ok := km.(beacon.SlashingChecker); ok {
+		return checker.IsAttestationSlashable(data, pk)
+	}
+	return nil
+}
+
+// Invalidate implements Invalidator, it drops the cached backend of the share with the given public key
+func (m *Manager) Invalidate(pk []byte) {
+	m.cacheLock.Lock()
+	defer m.cacheLock.Unlock()
+
+	delete(m.cache, hex.EncodeToString(pk))
+}
+
+// backend returns the key manager of the share with the given public key
+func (m *Manager) backend(pk []byte) (beacon.KeyManager, error) {
+	name := m.backendName(pk)
+	km, ok := m.backends[name]
+	if !ok {
+		return nil, errors.Errorf("key manager backend %s is not configured", name)
+	}
+	return km, nil
+}
+
+// backendName resolves the backend of the given public key, shares w/o a backend use the default one.
+// only shares that specify a backend are cached, as other shares might not be saved yet
+func (m *Manager) backendName(pk []byte) Backend {
+	key := hex.EncodeToString(pk)
+	m.cacheLock.RLock()
+	name, ok := m.cache[key]
+	m.cacheLock.RUnlock()
+	if ok {
+		return name
+	}
+	if m.resolve == nil {
+		return m.defaultBackend
+	}
+	name, ok = m.resolve(pk)
+	if !ok || len(name) == 0 {
+		return m.defaultBackend
+	}
+	m.cacheLock.Lock()
+	m.cache[key] = name
+	m.cacheLock.Unlock()
+	return name
+}
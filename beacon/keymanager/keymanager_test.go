@@ -0,0 +1,181 @@
+package keymanager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testSigningUtil struct{}
+
+func (t *testSigningUtil) GetDomain(data *spec.AttestationData) ([]byte, error) {
+	return make([]byte, 32), nil
+}
+
+func (t *testSigningUtil) GetVoluntaryExitDomain(epoch spec.Epoch) ([]byte, error) {
+	return make([]byte, 32), nil
+}
+
+func (t *testSigningUtil) ComputeSigningRoot(object interface{}, domain []byte) ([32]byte, error) {
+	return [32]byte{1, 2, 3}, nil
+}
+
+// testKeyManager signs with a fixed signature and records the added shares
+type testKeyManager struct {
+	added []string
+}
+
+func (km *testKeyManager) AddShare(shareKey *bls.SecretKey) error {
+	km.added = append(km.added, shareKey.GetPublicKey().SerializeToHexStr())
+	return nil
+}
+
+func (km *testKeyManager) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte, error) {
+	return []byte("local"), nil
+}
+
+func (km *testKeyManager) SignAttestation(data *spec.AttestationData, duty *beacon.Duty, pk []byte) (*spec.Attestation, []byte, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (km *testKeyManager) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// newTestRemoteSigner creates a remote signer that holds the given keys
+func newTestRemoteSigner(t *testing.T, token string, keys ...*bls.SecretKey) *httptest.Server {
+	return httptest.NewServer(newTestSignerHandler(t, token, keys...))
+}
+
+// newTestSignerHandler creates the handler of a remote signer that holds the given keys
+func newTestSignerHandler(t *testing.T, token string, keys ...*bls.SecretKey) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(token) > 0 && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/api/v1/keys" {
+			var pks []string
+			for _, sk := range keys {
+				pks = append(pks, "0x"+sk.GetPublicKey().SerializeToHexStr())
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(pks))
+			return
+		}
+		pk := strings.TrimPrefix(r.URL.Path, "/api/v1/sign/")
+		var req signRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		for _, sk := range keys {
+			if sk.GetPublicKey().SerializeToHexStr() != pk {
+				continue
+			}
+			root, err := hex.DecodeString(req.SigningRoot)
+			require.NoError(t, err)
+			sig := sk.SignByte(root)
+			require.NoError(t, json.NewEncoder(w).Encode(&signResponse{Signature: "0x" + sig.SerializeToHexStr()}))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+}
+
+func TestRemoteKeyManager(t *testing.T) {
+	threshold.Init()
+	sk := &bls.SecretKey{}
+	sk.SetByCSPRNG()
+	unknown := &bls.SecretKey{}
+	unknown.SetByCSPRNG()
+	server := newTestRemoteSigner(t, "secret", sk)
+	defer server.Close()
+
+	km, err := NewRemoteKeyManager(RemoteOptions{URL: server.URL + "/", Token: "secret"}, &testSigningUtil{})
+	require.NoError(t, err)
+	require.NoError(t, km.AddShare(sk))
+	require.EqualError(t, km.AddShare(unknown), "share key was not found on remote signer")
+
+	msg := &proto.Message{Type: proto.RoundState_Prepare, Round: 1, Lambda: []byte("lambda"), SeqNumber: 1, Value: []byte("value")}
+	sigBytes, err := km.SignIBFTMessage(msg, sk.GetPublicKey().Serialize())
+	require.NoError(t, err)
+	root, err := msg.SigningRoot()
+	require.NoError(t, err)
+	sig := &bls.Sign{}
+	require.NoError(t, sig.Deserialize(sigBytes))
+	require.True(t, sig.VerifyByte(sk.GetPublicKey(), root))
+
+	data := &spec.AttestationData{Slot: 1, Source: &spec.Checkpoint{}, Target: &spec.Checkpoint{}}
+	att, attRoot, err := km.SignAttestation(data, &beacon.Duty{CommitteeLength: 4, ValidatorCommitteeIndex: 2}, sk.GetPublicKey().Serialize())
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, attRoot[:3])
+	require.True(t, att.AggregationBits.BitAt(2))
+
+	_, err = km.SignIBFTMessage(msg, unknown.GetPublicKey().Serialize())
+	require.EqualError(t, err, "could not sign message: unexpected status 404")
+
+	unauthorized, err := NewRemoteKeyManager(RemoteOptions{URL: server.URL}, &testSigningUtil{})
+	require.NoError(t, err)
+	require.EqualError(t, unauthorized.AddShare(sk), "could not list remote signer keys: unexpected status 401")
+}
+
+func TestManager(t *testing.T) {
+	threshold.Init()
+	localSk := &bls.SecretKey{}
+	localSk.SetByCSPRNG()
+	remoteSk := &bls.SecretKey{}
+	remoteSk.SetByCSPRNG()
+	server := newTestRemoteSigner(t, "", remoteSk)
+	defer server.Close()
+
+	local := &testKeyManager{}
+	resolved := 0
+	m, err := New(Options{Remote: RemoteOptions{URL: server.URL}}, local, &testSigningUtil{}, func(pk []byte) (Backend, bool) {
+		resolved++
+		if hex.EncodeToString(pk) == remoteSk.GetPublicKey().SerializeToHexStr() {
+			return BackendRemote, true
+		}
+		return "", false
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.AddShare(localSk))
+	require.NoError(t, m.AddShare(remoteSk))
+	require.Equal(t, []string{localSk.GetPublicKey().SerializeToHexStr()}, local.added)
+
+	msg := &proto.Message{Type: proto.RoundState_Commit, Lambda: []byte("lambda")}
+	sig, err := m.SignIBFTMessage(msg, localSk.GetPublicKey().Serialize())
+	require.NoError(t, err)
+	require.Equal(t, []byte("local"), sig)
+	sig, err = m.SignIBFTMessage(msg, remoteSk.GetPublicKey().Serialize())
+	require.NoError(t, err)
+	require.Len(t, sig, 96)
+	// the backend of the remote share was cached
+	require.Equal(t, 3, resolved)
+
+	// the backend is resolved again once it was invalidated
+	m.Invalidate(remoteSk.GetPublicKey().Serialize())
+	_, err = m.SignIBFTMessage(msg, remoteSk.GetPublicKey().Serialize())
+	require.NoError(t, err)
+	require.Equal(t, 4, resolved)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Options{Default: BackendRemote}, &testKeyManager{}, &testSigningUtil{}, nil)
+	require.EqualError(t, err, "default key manager backend remote is not configured")
+
+	m, err := New(Options{}, &testKeyManager{}, &testSigningUtil{}, func(pk []byte) (Backend, bool) {
+		return "pkcs11", true
+	})
+	require.NoError(t, err)
+	_, err = m.SignIBFTMessage(&proto.Message{}, []byte{1})
+	require.EqualError(t, err, "key manager backend pkcs11 is not configured")
+}
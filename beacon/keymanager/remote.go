@@ -0,0 +1,234 @@
+package keymanager
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+)
+
+const (
+	// remoteMaxResponseSize is the max size of a response from the remote signer
+	remoteMaxResponseSize = 1 << 20
+
+	signTypeIBFTMessage   = "IBFT_MESSAGE"
+	signTypeAttestation   = "ATTESTATION"
+	signTypeVoluntaryExit = "VOLUNTARY_EXIT"
+)
+
+// RemoteOptions holds the configuration of the remote signer
+type RemoteOptions struct {
+	URL     string        `yaml:"URL" env:"REMOTE_SIGNER_URL" env-description:"Base url of the remote signer, the remote backend is disabled if empty"`
+	Token   string        `yaml:"Token" env:"REMOTE_SIGNER_TOKEN" env-description:"Bearer token for authenticating remote signer requests"`
+	Timeout time.Duration `yaml:"Timeout" env:"REMOTE_SIGNER_TIMEOUT" env-default:"5s" env-description:"Timeout of remote signer requests"`
+	// TLS is used for https urls, the system roots are trusted if no CA cert is given
+	TLSCert   string `yaml:"TLSCert" env:"REMOTE_SIGNER_TLS_CERT" env-description:"Path to the client certificate (PEM) for mTLS with the remote signer"`
+	TLSKey    string `yaml:"TLSKey" env:"REMOTE_SIGNER_TLS_KEY" env-description:"Path to the client private key (PEM) for mTLS with the remote signer"`
+	TLSCACert string `yaml:"TLSCACert" env:"REMOTE_SIGNER_TLS_CA_CERT" env-description:"Path to the CA certificate (PEM) of the remote signer"`
+}
+
+// signRequest is the body of a remote sign request.
+// the signed object is sent along with the signing root, so the remote signer can apply slashing protection
+type signRequest struct {
+	Type          string                `json:"type"`
+	SigningRoot   string                `json:"signingRoot"`
+	Attestation   *spec.AttestationData `json:"attestation,omitempty"`
+	VoluntaryExit *spec.VoluntaryExit   `json:"voluntaryExit,omitempty"`
+}
+
+// signResponse is the body of a remote sign response
+type signResponse struct {
+	Signature string `json:"signature"`
+}
+
+// remoteKeyManager signs with keys that are kept by a remote signer, using the following API:
+//
+//	GET  {url}/api/v1/keys       returns a JSON array of the (hex) public keys of the signer
+//	POST {url}/api/v1/sign/{pk}  signs a signRequest with the key of pk, returns a signResponse
+type remoteKeyManager struct {
+	url          string
+	token        string
+	client       *http.Client
+	signingUtils beacon.SigningUtil
+}
+
+// NewRemoteKeyManager creates a key manager that delegates signing to a remote signer
+func NewRemoteKeyManager(opts RemoteOptions, signingUtils beacon.SigningUtil) (beacon.KeyManager, error) {
+	client, err := newRemoteClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteKeyManager{
+		url:          strings.TrimSuffix(opts.URL, "/"),
+		token:        opts.Token,
+		client:       client,
+		signingUtils: signingUtils,
+	}, nil
+}
+
+// newRemoteClient creates the http client of the remote signer, with the configured client certificate and CA
+func newRemoteClient(opts RemoteOptions) (*http.Client, error) {
+	client := &http.Client{Timeout: opts.Timeout}
+	if len(opts.TLSCert) == 0 && len(opts.TLSKey) == 0 && len(opts.TLSCACert) == 0 {
+		return client, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(opts.TLSCert) > 0 || len(opts.TLSKey) > 0 {
+		cert, err := tls.LoadX509KeyPair(opts.TLSCert, opts.TLSKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load remote signer client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if len(opts.TLSCACert) > 0 {
+		raw, err := ioutil.ReadFile(opts.TLSCACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read remote signer CA certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return nil, errors.New("could not parse remote signer CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client.Transport = transport
+	return client, nil
+}
+
+// AddShare checks that the share key is kept by the remote signer, secret keys are never sent to the signer
+func (km *remoteKeyManager) AddShare(shareKey *bls.SecretKey) error {
+	var keys []string
+	if err := km.do(http.MethodGet, "/api/v1/keys", nil, &keys); err != nil {
+		return errors.Wrap(err, "could not list remote signer keys")
+	}
+	pk := shareKey.GetPublicKey().SerializeToHexStr()
+	for _, k := range keys {
+		if strings.EqualFold(strings.TrimPrefix(k, "0x"), pk) {
+			return nil
+		}
+	}
+	return errors.New("share key was not found on remote signer")
+}
+
+// SignIBFTMessage implements beacon.Signer
+func (km *remoteKeyManager) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte, error) {
+	root, err := message.SigningRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get message signing root")
+	}
+	sig, err := km.sign(pk, &signRequest{Type: signTypeIBFTMessage, SigningRoot: hex.EncodeToString(root)})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign message")
+	}
+	return sig, nil
+}
+
+// SignAttestation implements beacon.Signer
+func (km *remoteKeyManager) SignAttestation(data *spec.AttestationData, duty *beacon.Duty, pk []byte) (*spec.Attestation, []byte, error) {
+	domain, err := km.signingUtils.GetDomain(data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get domain for signing")
+	}
+	root, err := km.signingUtils.ComputeSigningRoot(data, domain)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get root for signing")
+	}
+	sig, err := km.sign(pk, &signRequest{
+		Type:        signTypeAttestation,
+		SigningRoot: hex.EncodeToString(root[:]),
+		Attestation: data,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to sign attestation")
+	}
+	return newAttestation(data, duty, sig), root[:], nil
+}
+
+// SignVoluntaryExit implements beacon.Signer
+func (km *remoteKeyManager) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	domain, err := km.signingUtils.GetVoluntaryExitDomain(exit.Epoch)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get domain for signing")
+	}
+	root, err := km.signingUtils.ComputeSigningRoot(exit, domain)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get root for signing")
+	}
+	sig, err := km.sign(pk, &signRequest{
+		Type:          signTypeVoluntaryExit,
+		SigningRoot:   hex.EncodeToString(root[:]),
+		VoluntaryExit: exit,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not sign voluntary exit")
+	}
+	return sig, root[:], nil
+}
+
+// sign sends the given request to the remote signer and returns the decoded signature
+func (km *remoteKeyManager) sign(pk []byte, req *signRequest) ([]byte, error) {
+	var res signResponse
+	if err := km.do(http.MethodPost, fmt.Sprintf("/api/v1/sign/%x", pk), req, &res); err != nil {
+		return nil, err
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(res.Signature, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode signature")
+	}
+	if len(sig) != len(spec.BLSSignature{}) {
+		return nil, errors.Errorf("invalid signature length %d", len(sig))
+	}
+	return sig, nil
+}
+
+// do sends a request to the remote signer and decodes the JSON response into res
+func (km *remoteKeyManager) do(method, path string, body interface{}, res interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "could not encode request")
+		}
+		reqBody = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, km.url+path, reqBody)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(km.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+km.token)
+	}
+	resp, err := km.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "remote signer request failed")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, remoteMaxResponseSize)).Decode(res); err != nil {
+		return errors.Wrap(err, "could not decode response")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package beacon
+
+import (
+	"math"
+	"time"
+)
+
+// MetadataSchedule is the schedule of a round of metadata updates of all the validators,
+// the batches of a round are spread evenly across its interval to avoid load spikes on the beacon node
+type MetadataSchedule struct {
+	// Interval is the duration of a round
+	Interval time.Duration
+	// Batches is the number of batches (i.e. beacon requests) in a round
+	Batches int
+	// BatchInterval is the pause before each batch
+	BatchInterval time.Duration
+}
+
+// NewMetadataSchedule computes the schedule of updating the given count of validators in batches of the given size.
+// a round lasts the given min interval, unless the request budget (beacon requests per minute, zero is unlimited)
+// doesn't allow updating all the validators within it, then the round is extended accordingly
+func NewMetadataSchedule(count, batchSize int, minInterval time.Duration, requestsPerMinute int) MetadataSchedule {
+	schedule := MetadataSchedule{Interval: minInterval}
+	if count <= 0 || batchSize <= 0 {
+		return schedule
+	}
+	schedule.Batches = int(math.Ceil(float64(count) / float64(batchSize)))
+	if requestsPerMinute > 0 {
+		required := time.Duration(schedule.Batches) * time.Minute / time.Duration(requestsPerMinute)
+		if required > schedule.Interval {
+			schedule.Interval = required
+		}
+	}
+	schedule.BatchInterval = schedule.Interval / time.Duration(schedule.Batches)
+	return schedule
+}
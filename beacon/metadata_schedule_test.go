@@ -0,0 +1,31 @@
+package beacon
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewMetadataSchedule(t *testing.T) {
+	// 4 batches within the min interval
+	schedule := NewMetadataSchedule(100, 25, 12*time.Minute, 10)
+	require.Equal(t, MetadataSchedule{Interval: 12 * time.Minute, Batches: 4, BatchInterval: 3 * time.Minute}, schedule)
+
+	// 400 batches require 40 minutes with a budget of 10 requests per minute
+	schedule = NewMetadataSchedule(10000, 25, 12*time.Minute, 10)
+	require.Equal(t, 40*time.Minute, schedule.Interval)
+	require.Equal(t, 400, schedule.Batches)
+	require.Equal(t, 6*time.Second, schedule.BatchInterval)
+
+	// unlimited budget
+	schedule = NewMetadataSchedule(10000, 25, 12*time.Minute, 0)
+	require.Equal(t, 12*time.Minute, schedule.Interval)
+	require.Equal(t, 1800*time.Millisecond, schedule.BatchInterval)
+
+	// a partial batch
+	require.Equal(t, 2, NewMetadataSchedule(26, 25, time.Minute, 10).Batches)
+
+	// no validators
+	schedule = NewMetadataSchedule(0, 25, 12*time.Minute, 10)
+	require.Equal(t, MetadataSchedule{Interval: 12 * time.Minute}, schedule)
+}
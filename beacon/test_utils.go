@@ -1,6 +1,7 @@
 package beacon
 
 import (
+	"context"
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv/ibft/proto"
@@ -50,7 +51,7 @@ func (m *mockBeacon) GetValidatorData(validatorPubKeys []spec.BLSPubKey) (map[sp
 	return results, nil
 }
 
-func (m *mockBeacon) GetAttestationData(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+func (m *mockBeacon) GetAttestationData(ctx context.Context, slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
 	return nil, nil
 }
 
@@ -58,7 +59,7 @@ func (m *mockBeacon) SignAttestation(data *spec.AttestationData, duty *Duty, pk
 	return nil, nil, nil
 }
 
-func (m *mockBeacon) SubmitAttestation(attestation *spec.Attestation) error {
+func (m *mockBeacon) SubmitAttestation(ctx context.Context, attestation *spec.Attestation) error {
 	return nil
 }
 
@@ -66,6 +67,18 @@ func (m *mockBeacon) SubscribeToCommitteeSubnet(subscription []*v1.BeaconCommitt
 	return nil
 }
 
+func (m *mockBeacon) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return nil, nil, nil
+}
+
+func (m *mockBeacon) SubmitVoluntaryExit(ctx context.Context, exit *spec.SignedVoluntaryExit) error {
+	return nil
+}
+
+func (m *mockBeacon) GetSyncState() (*v1.SyncState, error) {
+	return &v1.SyncState{}, nil
+}
+
 func (m *mockBeacon) AddShare(shareKey *bls.SecretKey) error {
 	return nil
 }
@@ -77,6 +90,9 @@ func (m *mockBeacon) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte,
 func (m *mockBeacon) GetDomain(data *spec.AttestationData) ([]byte, error) {
 	panic("implement")
 }
+func (m *mockBeacon) GetVoluntaryExitDomain(epoch spec.Epoch) ([]byte, error) {
+	panic("implement")
+}
 func (m *mockBeacon) ComputeSigningRoot(object interface{}, domain []byte) ([32]byte, error) {
 	panic("implement")
 }
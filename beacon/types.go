@@ -1,5 +1,10 @@
 package beacon
 
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
 // RoleType type of the validator role for a specific duty
 type RoleType int
 
@@ -14,6 +19,8 @@ func (r RoleType) String() string {
 		return "AGGREGATOR"
 	case RoleTypeProposer:
 		return "PROPOSER"
+	case RoleTypeVoluntaryExit:
+		return "VOLUNTARY_EXIT"
 	default:
 		return "UNDEFINED"
 	}
@@ -25,4 +32,15 @@ const (
 	RoleTypeAttester
 	RoleTypeAggregator
 	RoleTypeProposer
+	RoleTypeVoluntaryExit
 )
+
+// ParseRoleType returns the role with the given name (e.g. "ATTESTER"), the name is case-insensitive
+func ParseRoleType(name string) (RoleType, error) {
+	for _, r := range []RoleType{RoleTypeAttester, RoleTypeAggregator, RoleTypeProposer, RoleTypeVoluntaryExit} {
+		if strings.EqualFold(r.String(), name) {
+			return r, nil
+		}
+	}
+	return RoleTypeUnknown, errors.Errorf("unknown role: %s", name)
+}
@@ -2,25 +2,82 @@ package valcheck
 
 import (
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
 	"github.com/pkg/errors"
 )
 
+// MaxCommitteesPerSlot is the max number of committees in a slot (MAX_COMMITTEES_PER_SLOT)
+const MaxCommitteesPerSlot = 64
+
 // AttestationValueCheck checks for an Attestation type value
 type AttestationValueCheck struct {
+	// Duty is optional, if provided the attestation data should match the duty
+	Duty *beacon.Duty
 }
 
 // Check returns error if value is invalid
 func (v *AttestationValueCheck) Check(value []byte) error {
-	// try and parse to attestation data
-	inputValue := &spec.AttestationData{}
-	if err := inputValue.UnmarshalSSZ(value); err != nil {
-		return errors.Wrap(err, "could not parse input value storing attestation data")
+	inputValue, err := parseAttestationData(value)
+	if err != nil {
+		return err
 	}
-
-	if inputValue.Slot == 100 {
-		return errors.New("TEST - failed on slot 100")
+	if inputValue.Source == nil || inputValue.Target == nil {
+		return errors.New("missing source or target checkpoint")
+	}
+	if inputValue.Source.Epoch > inputValue.Target.Epoch {
+		return errors.Errorf("source epoch %d is higher than target epoch %d",
+			inputValue.Source.Epoch, inputValue.Target.Epoch)
+	}
+	if v.Duty == nil {
+		return nil
+	}
+	if inputValue.Slot != v.Duty.Slot {
+		return errors.Errorf("attestation data slot %d doesn't match duty slot %d", inputValue.Slot, v.Duty.Slot)
 	}
+	if inputValue.Index != v.Duty.CommitteeIndex {
+		return errors.Errorf("attestation data committee index %d doesn't match duty committee index %d",
+			inputValue.Index, v.Duty.CommitteeIndex)
+	}
+	return nil
+}
 
-	// TODO - test for slashing protection
+// MaxCommitteeIndexValueCheck checks that the committee index of an Attestation type value is in range
+type MaxCommitteeIndexValueCheck struct {
+	Max spec.CommitteeIndex
+}
+
+// Check returns error if value is invalid
+func (v *MaxCommitteeIndexValueCheck) Check(value []byte) error {
+	inputValue, err := parseAttestationData(value)
+	if err != nil {
+		return err
+	}
+	if inputValue.Index >= v.Max {
+		return errors.Errorf("committee index %d is out of range (max %d)", inputValue.Index, v.Max)
+	}
 	return nil
 }
+
+// AttestationSlashingValueCheck checks an Attestation type value against the slashing protection history
+type AttestationSlashingValueCheck struct {
+	Checker beacon.SlashingChecker
+	// PubKey is the public key of the share that signs the attestation
+	PubKey []byte
+}
+
+// Check returns error if value is invalid
+func (v *AttestationSlashingValueCheck) Check(value []byte) error {
+	inputValue, err := parseAttestationData(value)
+	if err != nil {
+		return err
+	}
+	return v.Checker.IsAttestationSlashable(inputValue, v.PubKey)
+}
+
+func parseAttestationData(value []byte) (*spec.AttestationData, error) {
+	inputValue := &spec.AttestationData{}
+	if err := inputValue.UnmarshalSSZ(value); err != nil {
+		return nil, errors.Wrap(err, "could not parse input value storing attestation data")
+	}
+	return inputValue, nil
+}
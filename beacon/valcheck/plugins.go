@@ -0,0 +1,123 @@
+package valcheck
+
+import (
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/valcheck"
+	"sync"
+)
+
+// Plugin provides a value check of consensus inputs, the check is invoked before proposing or accepting a value
+type Plugin interface {
+	// ValueCheck returns the value check for the given duty, or nil if the plugin is not relevant for the duty
+	ValueCheck(duty *beacon.Duty) valcheck.ValueCheck
+}
+
+// PluginFunc is an adapter to use functions as plugins
+type PluginFunc func(duty *beacon.Duty) valcheck.ValueCheck
+
+// ValueCheck implements Plugin
+func (f PluginFunc) ValueCheck(duty *beacon.Duty) valcheck.ValueCheck {
+	return f(duty)
+}
+
+// Registry holds the value check plugins per duty role
+type Registry struct {
+	lock    sync.RWMutex
+	plugins map[beacon.RoleType][]Plugin
+	// common plugins are checked for all roles, before the role plugins
+	common []Plugin
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		plugins: map[beacon.RoleType][]Plugin{},
+	}
+}
+
+// New returns a registry with the default plugins:
+// attestation data sanity, max committee index and slashing protection (if the signer supports it) for attesters,
+// and validator index check for voluntary exits.
+// pubKey is the (operator's share) public key that is used for signing
+func New(signer beacon.Signer, pubKey []byte) *Registry {
+	r := NewRegistry()
+	r.Register(beacon.RoleTypeAttester, AttestationDataPlugin())
+	r.Register(beacon.RoleTypeAttester, MaxCommitteeIndexPlugin(MaxCommitteesPerSlot))
+	if checker, ok := signer.(beacon.SlashingChecker); ok && len(pubKey) > 0 {
+		r.Register(beacon.RoleTypeAttester, AttestationSlashingPlugin(checker, pubKey))
+	}
+	r.Register(beacon.RoleTypeVoluntaryExit, VoluntaryExitPlugin())
+	return r
+}
+
+// Register adds a plugin for the given role, plugins are checked in registration order
+func (r *Registry) Register(role beacon.RoleType, plugin Plugin) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.plugins[role] = append(r.plugins[role], plugin)
+}
+
+// RegisterCommon adds a plugin for all roles, common plugins are checked before the plugins of the role
+func (r *Registry) RegisterCommon(plugin Plugin) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.common = append(r.common, plugin)
+}
+
+// ValueCheck returns the combined value check of the plugins of the duty role
+func (r *Registry) ValueCheck(duty *beacon.Duty) valcheck.ValueCheck {
+	r.lock.RLock()
+	plugins := make([]Plugin, 0, len(r.common)+len(r.plugins[duty.Type]))
+	plugins = append(plugins, r.common...)
+	plugins = append(plugins, r.plugins[duty.Type]...)
+	r.lock.RUnlock()
+
+	checks := make([]valcheck.ValueCheck, 0, len(plugins))
+	for _, p := range plugins {
+		if check := p.ValueCheck(duty); check != nil {
+			checks = append(checks, check)
+		}
+	}
+	return valcheck.Combine(checks...)
+}
+
+// AttestationDataPlugin checks that attestation data is sane and matches the duty
+func AttestationDataPlugin() Plugin {
+	return PluginFunc(func(duty *beacon.Duty) valcheck.ValueCheck {
+		return &AttestationValueCheck{Duty: duty}
+	})
+}
+
+// MaxCommitteeIndexPlugin checks that the committee index of attestation data is lower than max
+func MaxCommitteeIndexPlugin(max spec.CommitteeIndex) Plugin {
+	return PluginFunc(func(duty *beacon.Duty) valcheck.ValueCheck {
+		return &MaxCommitteeIndexValueCheck{Max: max}
+	})
+}
+
+// AttestationSlashingPlugin checks attestation data against the slashing protection history of the given key
+func AttestationSlashingPlugin(checker beacon.SlashingChecker, pubKey []byte) Plugin {
+	return PluginFunc(func(duty *beacon.Duty) valcheck.ValueCheck {
+		return &AttestationSlashingValueCheck{Checker: checker, PubKey: pubKey}
+	})
+}
+
+// VoluntaryExitPlugin checks that a voluntary exit refers the validator of the duty
+func VoluntaryExitPlugin() Plugin {
+	return PluginFunc(func(duty *beacon.Duty) valcheck.ValueCheck {
+		return &VoluntaryExitValueCheck{ValidatorIndex: duty.ValidatorIndex}
+	})
+}
+
+// DutyRolePlugin rejects values of duties which role is not enabled
+func DutyRolePlugin(enabled func(role beacon.RoleType) bool) Plugin {
+	return PluginFunc(func(duty *beacon.Duty) valcheck.ValueCheck {
+		if enabled(duty.Type) {
+			return nil
+		}
+		return &DisabledRoleValueCheck{Role: duty.Type}
+	})
+}
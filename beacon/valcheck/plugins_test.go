@@ -0,0 +1,112 @@
+package valcheck
+
+import (
+	"testing"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/valcheck"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testSlashingChecker struct {
+	beacon.Signer
+	slashable map[spec.Slot]bool
+}
+
+func (c *testSlashingChecker) IsAttestationSlashable(data *spec.AttestationData, pk []byte) error {
+	if c.slashable[data.Slot] {
+		return errors.New("slashable attestation")
+	}
+	return nil
+}
+
+func attestationDataBytes(t *testing.T, slot spec.Slot, index spec.CommitteeIndex, source, target spec.Epoch) []byte {
+	data := &spec.AttestationData{
+		Slot:   slot,
+		Index:  index,
+		Source: &spec.Checkpoint{Epoch: source},
+		Target: &spec.Checkpoint{Epoch: target},
+	}
+	byts, err := data.MarshalSSZ()
+	require.NoError(t, err)
+	return byts
+}
+
+func TestRegistry_Attester(t *testing.T) {
+	checker := &testSlashingChecker{slashable: map[spec.Slot]bool{12: true}}
+	r := New(checker, []byte{1, 2, 3})
+
+	tests := []struct {
+		name  string
+		duty  *beacon.Duty
+		value []byte
+		err   string
+	}{
+		{"valid", &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 10, CommitteeIndex: 2},
+			attestationDataBytes(t, 10, 2, 1, 2), ""},
+		{"invalid value", &beacon.Duty{Type: beacon.RoleTypeAttester},
+			[]byte{1, 2, 3}, "could not parse input value storing attestation data: incorrect size"},
+		{"slot mismatch", &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 11, CommitteeIndex: 2},
+			attestationDataBytes(t, 10, 2, 1, 2), "attestation data slot 10 doesn't match duty slot 11"},
+		{"committee index mismatch", &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 10, CommitteeIndex: 3},
+			attestationDataBytes(t, 10, 2, 1, 2), "attestation data committee index 2 doesn't match duty committee index 3"},
+		{"source after target", &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 10, CommitteeIndex: 2},
+			attestationDataBytes(t, 10, 2, 3, 2), "source epoch 3 is higher than target epoch 2"},
+		{"committee index out of range", &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 10, CommitteeIndex: 64},
+			attestationDataBytes(t, 10, 64, 1, 2), "committee index 64 is out of range (max 64)"},
+		{"slashable", &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 12},
+			attestationDataBytes(t, 12, 0, 1, 2), "slashable attestation"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := r.ValueCheck(test.duty).Check(test.value)
+			if len(test.err) > 0 {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	duty := &beacon.Duty{Type: beacon.RoleTypeProposer, Slot: 5}
+	// no plugins
+	require.NoError(t, r.ValueCheck(duty).Check([]byte{1}))
+
+	var checkedSlot spec.Slot
+	r.Register(beacon.RoleTypeProposer, PluginFunc(func(duty *beacon.Duty) valcheck.ValueCheck {
+		checkedSlot = duty.Slot
+		return nil
+	}))
+	r.Register(beacon.RoleTypeProposer, PluginFunc(func(duty *beacon.Duty) valcheck.ValueCheck {
+		return &VoluntaryExitValueCheck{}
+	}))
+	require.EqualError(t, r.ValueCheck(duty).Check([]byte{1}),
+		"could not parse input value storing voluntary exit: incorrect size")
+	require.EqualValues(t, 5, checkedSlot)
+	// other roles are not affected
+	require.NoError(t, r.ValueCheck(&beacon.Duty{Type: beacon.RoleTypeAggregator}).Check([]byte{1}))
+}
+
+func TestRegistry_DutyRolePlugin(t *testing.T) {
+	r := New(nil, nil)
+	r.RegisterCommon(DutyRolePlugin(func(role beacon.RoleType) bool {
+		return role == beacon.RoleTypeAttester
+	}))
+	attester := &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 12}
+	require.NoError(t, r.ValueCheck(attester).Check(attestationDataBytes(t, 12, 0, 1, 2)))
+
+	exit := &beacon.Duty{Type: beacon.RoleTypeVoluntaryExit}
+	require.EqualError(t, r.ValueCheck(exit).Check([]byte{1}), "duty role VOLUNTARY_EXIT is disabled")
+}
+
+func TestNew_NoSlashingChecker(t *testing.T) {
+	r := New(nil, nil)
+	duty := &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 12}
+	require.NoError(t, r.ValueCheck(duty).Check(attestationDataBytes(t, 12, 0, 1, 2)))
+}
@@ -0,0 +1,16 @@
+package valcheck
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/pkg/errors"
+)
+
+// DisabledRoleValueCheck rejects any value of a disabled duty role
+type DisabledRoleValueCheck struct {
+	Role beacon.RoleType
+}
+
+// Check returns an error as the role is disabled
+func (v *DisabledRoleValueCheck) Check(value []byte) error {
+	return errors.Errorf("duty role %s is disabled", v.Role.String())
+}
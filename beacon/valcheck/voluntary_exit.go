@@ -0,0 +1,24 @@
+package valcheck
+
+import (
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// VoluntaryExitValueCheck checks for a VoluntaryExit type value
+type VoluntaryExitValueCheck struct {
+	ValidatorIndex spec.ValidatorIndex
+}
+
+// Check returns error if value is invalid
+func (v *VoluntaryExitValueCheck) Check(value []byte) error {
+	// try and parse to voluntary exit
+	inputValue := &spec.VoluntaryExit{}
+	if err := inputValue.UnmarshalSSZ(value); err != nil {
+		return errors.Wrap(err, "could not parse input value storing voluntary exit")
+	}
+	if inputValue.ValidatorIndex != v.ValidatorIndex {
+		return errors.Errorf("voluntary exit of a different validator (%d)", inputValue.ValidatorIndex)
+	}
+	return nil
+}
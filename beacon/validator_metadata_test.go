@@ -161,4 +161,22 @@ func TestBatch(t *testing.T) {
 		}, 4)
 		time.Sleep(10 * time.Millisecond) // to be sure the function has finished
 	})
+
+	t.Run("spread batches", func(t *testing.T) {
+		var queued []time.Time
+		start := time.Now()
+		spreadBatch(decodeds, tasks.NewExecutionQueue(time.Millisecond), func(pks [][]byte) func() error {
+			queued = append(queued, time.Now())
+			return func() error {
+				return nil
+			}
+		}, 2, 20*time.Millisecond)
+		require.Len(t, queued, 3)
+		// each batch is queued after a pause
+		prev := start
+		for _, at := range queued {
+			require.True(t, at.Sub(prev) >= 20*time.Millisecond)
+			prev = at
+		}
+	})
 }
@@ -2,6 +2,7 @@ package beacon
 
 import (
 	"encoding/hex"
+	"fmt"
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv/utils/logex"
@@ -9,8 +10,17 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"math"
+	"time"
 )
 
+// metadataRetryPolicy is used to recover from transient beacon errors when fetching metadata in batches
+var metadataRetryPolicy = tasks.RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     2 * time.Second,
+	MaxBackoff:  10 * time.Second,
+	Timeout:     time.Minute,
+}
+
 // ValidatorMetadataStorage interface for validator metadata
 type ValidatorMetadataStorage interface {
 	UpdateValidatorMetadata(pk string, metadata *ValidatorMetadata) error
@@ -21,6 +31,8 @@ type ValidatorMetadata struct {
 	Balance spec.Gwei           `json:"balance"`
 	Status  v1.ValidatorState   `json:"status"`
 	Index   spec.ValidatorIndex `json:"index"` // pointer in order to support nil
+
+	ActivationEpoch spec.Epoch `json:"activationEpoch,omitempty"`
 }
 
 // Equals returns true if the given metadata is equal to current
@@ -113,6 +125,8 @@ func FetchValidatorsMetadata(bc Beacon, pubKeys [][]byte) (map[string]*Validator
 			Balance: v.Balance,
 			Status:  v.Status,
 			Index:   v.Index,
+
+			ActivationEpoch: v.Validator.ActivationEpoch,
 		}
 		ret[pk] = meta
 		// once fetched, the internal map in go-client should be updated
@@ -135,9 +149,34 @@ func UpdateValidatorsMetadataBatch(pubKeys [][]byte,
 	}, batchSize)
 }
 
+// UpdateValidatorsMetadataRound updates the given public keys in batches, a batch is queued every batch interval
+// of the given schedule so the requests to the beacon node are spread across the round. blocks until the round is over
+func UpdateValidatorsMetadataRound(pubKeys [][]byte,
+	queue tasks.Queue,
+	collection ValidatorMetadataStorage,
+	bc Beacon,
+	onUpdated OnUpdated,
+	batchSize int,
+	schedule MetadataSchedule) {
+	if len(pubKeys) == 0 {
+		time.Sleep(schedule.Interval)
+		return
+	}
+	spreadBatch(pubKeys, queue, func(pks [][]byte) func() error {
+		return func() error {
+			return UpdateValidatorsMetadata(pks, collection, bc, onUpdated)
+		}
+	}, batchSize, schedule.BatchInterval)
+}
+
 type batchTask func(pks [][]byte) func() error
 
 func batch(pubKeys [][]byte, queue tasks.Queue, task batchTask, batchSize int) {
+	spreadBatch(pubKeys, queue, task, batchSize, 0)
+}
+
+// spreadBatch queues the given public keys in batches, pausing before each batch
+func spreadBatch(pubKeys [][]byte, queue tasks.Queue, task batchTask, batchSize int, pause time.Duration) {
 	n := float64(len(pubKeys))
 	// in case the amount of public keys is lower than the batch size
 	batchSize = int(math.Min(n, float64(batchSize)))
@@ -146,8 +185,12 @@ func batch(pubKeys [][]byte, queue tasks.Queue, task batchTask, batchSize int) {
 	end := int(math.Min(n, float64(batchSize)))
 
 	for i := 0; i < batches; i++ {
+		if pause > 0 {
+			time.Sleep(pause)
+		}
 		// run task
-		queue.Queue(task(pubKeys[start:end]))
+		queue.QueueWithPolicy(task(pubKeys[start:end]), fmt.Sprintf("update metadata batch [%d:%d]", start, end),
+			metadataRetryPolicy)
 		// reset start and end
 		start = end
 		end = int(math.Min(n, float64(start+batchSize)))
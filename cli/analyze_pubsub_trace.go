@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bloxapp/ssv/network/p2p/tracing"
+	"github.com/bloxapp/ssv/utils/logex"
+)
+
+// analyzePubSubTraceCmd is the command to summarize the mesh health out of pubsub trace files
+var analyzePubSubTraceCmd = &cobra.Command{
+	Use:   "analyze-pubsub-trace [trace files]",
+	Short: "summarizes grafts, prunes and duplicates in pubsub trace files (pb or json)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logex.Build(RootCmd.Short, zapcore.InfoLevel, nil)
+
+		summary, err := tracing.Analyze(args...)
+		if err != nil {
+			logger.Fatal("failed to analyze pubsub traces", zap.Error(err))
+		}
+		fmt.Print(summary.String())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(analyzePubSubTraceCmd)
+}
@@ -2,8 +2,10 @@ package cli
 
 import (
 	"github.com/bloxapp/ssv/cli/bootnode"
+	"github.com/bloxapp/ssv/cli/db"
 	"github.com/bloxapp/ssv/cli/exporter"
 	"github.com/bloxapp/ssv/cli/operator"
+	"github.com/bloxapp/ssv/utils/commons"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"log"
@@ -22,9 +24,10 @@ var RootCmd = &cobra.Command{
 }
 
 // Execute executes the root command
-func Execute(appName, version string) {
+func Execute(appName, version, commit string) {
 	RootCmd.Short = appName
 	RootCmd.Version = version
+	commons.SetCommit(commit)
 
 	if err := RootCmd.Execute(); err != nil {
 		log.Fatal("failed to execute root command", zap.Error(err))
@@ -35,4 +38,5 @@ func init() {
 	RootCmd.AddCommand(bootnode.StartBootNodeCmd)
 	RootCmd.AddCommand(exporter.StartExporterNodeCmd)
 	RootCmd.AddCommand(operator.StartNodeCmd)
+	RootCmd.AddCommand(db.DBCmd)
 }
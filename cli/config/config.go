@@ -16,6 +16,7 @@ type GlobalConfig struct {
 	LogLevel       string `yaml:"LogLevel" env:"LOG_LEVEL" env-default:"info" env-description:"Defines logger's log level'"`
 	LogFormat      string `yaml:"LogFormat" env:"LOG_FORMAT" env-default:"console" env-description:"Defines logger's encoding, valid values are 'console' (default) and 'json''"`
 	LogLevelFormat string `yaml:"LogLevelFormat" env:"LOG_LEVEL_FORMAT" env-default:"capitalColor" env-description:"Defines logger's level format, valid values are 'capitalColor' (default), 'capital' or 'lowercase''"`
+	Network        string `yaml:"Network" env:"SSV_NETWORK" env-description:"Named network profile (prater-stage, prater-prod, mainnet) that sets the eth2 network, registry contract, sync offset, bootnodes and forks"`
 }
 
 // ProcessArgs processes and handles CLI arguments
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/network/p2p"
+	"github.com/pkg/errors"
+)
+
+// NetworkProfile holds the parameters of an ssv network, so a single config value selects all of them
+type NetworkProfile struct {
+	Name string
+	// ETH2Network is the beacon chain network
+	ETH2Network core.Network
+	// RegistryContractAddr is the address of the registry contract
+	RegistryContractAddr string
+	// RegistrySyncOffset is the block number (hex) of the contract genesis, used as the default sync offset
+	RegistrySyncOffset string
+	// Bootnodes are the ENRs of the network bootnodes
+	Bootnodes []string
+	// ForkEpochs are the activation epochs of the network forks by fork id, overriding the default schedule
+	ForkEpochs map[string]uint64
+}
+
+// networkProfiles are the supported network profiles
+var networkProfiles = map[string]NetworkProfile{
+	"prater-stage": {
+		Name:                 "prater-stage",
+		ETH2Network:          core.PraterNetwork,
+		RegistryContractAddr: "0x9573C41F0Ed8B72f3bD6A9bA6E3e15426A0aa65B",
+		RegistrySyncOffset:   "49e08f",
+		Bootnodes: []string{
+			"enr:-LK4QHVq6HEA2KVnAw593SRMqUOvMGlkP8Jb-qHn4yPLHx--cStvWc38Or2xLcWgDPynVxXPT9NWIEXRzrBUsLmcFkUBh2F0dG5ldHOIAAAAAAAAAACEZXRoMpD1pf1CAAAAAP__________gmlkgnY0gmlwhDbUHcyJc2VjcDI1NmsxoQO8KQz5L1UEXzEr-CXFFq1th0eG6gopbdul2OQVMuxfMoN0Y3CCE4iDdWRwgg-g",
+		},
+	},
+	"prater-prod": {
+		Name:                 "prater-prod",
+		ETH2Network:          core.PraterNetwork,
+		RegistryContractAddr: "0x687fb596F3892904F879118e2113e1EEe8746C2E",
+		RegistrySyncOffset:   "4e706f",
+		Bootnodes: []string{
+			"enr:-LK4QMmL9hLJ1csDN4rQoSjlJGE2SvsXOETfcLH8uAVrxlHaELF0u3NeKCTY2eO_X1zy5eEKcHruyaAsGNiyyG4QWUQBh2F0dG5ldHOIAAAAAAAAAACEZXRoMpD1pf1CAAAAAP__________gmlkgnY0gmlwhCLdu_SJc2VjcDI1NmsxoQO8KQz5L1UEXzEr-CXFFq1th0eG6gopbdul2OQVMuxfMoN0Y3CCE4iDdWRwgg-g",
+		},
+	},
+	// the registry contract is not deployed on mainnet yet, the contract and bootnodes must be configured explicitly
+	"mainnet": {
+		Name:        "mainnet",
+		ETH2Network: core.MainNetwork,
+	},
+}
+
+// GetNetworkProfile returns the profile with the given name
+func GetNetworkProfile(name string) (NetworkProfile, error) {
+	profile, ok := networkProfiles[strings.ToLower(name)]
+	if !ok {
+		return NetworkProfile{}, errors.Errorf("unknown network %s, supported networks: %s", name,
+			strings.Join(NetworkProfileNames(), ", "))
+	}
+	return profile, nil
+}
+
+// NetworkProfileNames returns the names of the supported network profiles
+func NetworkProfileNames() []string {
+	names := make([]string, 0, len(networkProfiles))
+	for name := range networkProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyNetwork applies the profile of the given network (if configured) and returns it.
+// w/o a network profile the options are used as configured, with the default registry contract
+func ApplyNetwork(network string, eth1Options *eth1.Options, eth2Options *beacon.Options, p2pConfig *p2p.Config) (NetworkProfile, error) {
+	if len(network) == 0 {
+		if len(eth1Options.RegistryContractAddr) == 0 {
+			eth1Options.RegistryContractAddr = eth1.DefaultRegistryContractAddr
+		}
+		return NetworkProfile{}, nil
+	}
+	profile, err := GetNetworkProfile(network)
+	if err != nil {
+		return NetworkProfile{}, err
+	}
+	return profile, profile.Apply(eth1Options, eth2Options, p2pConfig)
+}
+
+// Apply sets the parameters of the profile in the given options.
+// the eth2 network is taken from the profile, a configured registry contract must match the one of the profile
+// (to avoid mixing environments), while the sync offset and bootnodes are only set if they were not configured
+func (p NetworkProfile) Apply(eth1Options *eth1.Options, eth2Options *beacon.Options, p2pConfig *p2p.Config) error {
+	eth2Options.Network = string(p.ETH2Network)
+	switch {
+	case len(p.RegistryContractAddr) == 0:
+		if len(eth1Options.RegistryContractAddr) == 0 {
+			return errors.Errorf("registry contract address of network %s must be configured", p.Name)
+		}
+	case len(eth1Options.RegistryContractAddr) == 0:
+		eth1Options.RegistryContractAddr = p.RegistryContractAddr
+	case !strings.EqualFold(eth1Options.RegistryContractAddr, p.RegistryContractAddr):
+		return errors.Errorf("registry contract address %s doesn't match network %s",
+			eth1Options.RegistryContractAddr, p.Name)
+	}
+	if len(eth1Options.ETH1SyncOffset) == 0 {
+		eth1Options.ETH1SyncOffset = p.RegistrySyncOffset
+	}
+	if p2pConfig != nil && len(p2pConfig.Enr) == 0 {
+		if len(p.Bootnodes) == 0 {
+			return errors.Errorf("bootnodes of network %s must be configured", p.Name)
+		}
+		p2pConfig.Enr = strings.Join(p.Bootnodes, ",")
+	}
+	return nil
+}
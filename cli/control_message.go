@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bloxapp/ssv/cli/flags"
+	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/control"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+// signControlMessageCmd signs a control message with the admin key of the exporters,
+// the output is a request that can be pushed to the api of an exporter
+var signControlMessageCmd = &cobra.Command{
+	Use:   "sign-control-message",
+	Short: "signs a control message that updates the sync whitelist/blacklist of the exporters",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logex.Build(RootCmd.Short, zapcore.InfoLevel, nil)
+		adminKey, err := flags.GetAdminKeyFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get admin key flag value", zap.Error(err))
+		}
+		sk, err := rsaencryption.ConvertEncodedPemToPrivateKey(adminKey)
+		if err != nil {
+			logger.Fatal("failed to load admin key", zap.Error(err))
+		}
+		nonce, err := flags.GetNonceFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get nonce flag value", zap.Error(err))
+		}
+		whitelist, blacklist, err := flags.GetSyncListsFlagValues(cmd)
+		if err != nil {
+			logger.Fatal("failed to get sync lists flag values", zap.Error(err))
+		}
+
+		msg := &network.ControlMessage{
+			Nonce:         nonce,
+			Timestamp:     time.Now().UnixNano() / int64(time.Millisecond),
+			SyncWhitelist: whitelist,
+			SyncBlacklist: blacklist,
+		}
+		if err := control.Sign(sk, msg); err != nil {
+			logger.Fatal("failed to sign control message", zap.Error(err))
+		}
+		raw, err := json.Marshal(&api.Message{Type: api.TypeControl, Data: msg})
+		if err != nil {
+			logger.Fatal("failed to marshal control message", zap.Error(err))
+		}
+		fmt.Println(string(raw))
+	},
+}
+
+func init() {
+	flags.AddAdminKeyFlag(signControlMessageCmd)
+	flags.AddNonceFlag(signControlMessageCmd)
+	flags.AddSyncListsFlags(signControlMessageCmd)
+
+	RootCmd.AddCommand(signControlMessageCmd)
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bloxapp/ssv/cli/flags"
+	"github.com/bloxapp/ssv/utils/keystore"
+	"github.com/bloxapp/ssv/utils/logex"
+)
+
+// createKeystoreCmd is the command to encrypt the operator or network key into a keystore file
+var createKeystoreCmd = &cobra.Command{
+	Use:   "create-keystore",
+	Short: "encrypts an operator or network key into a keystore file",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logex.Build(RootCmd.Short, zapcore.InfoLevel, nil)
+
+		secret, err := flags.GetSecretFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get secret flag value", zap.Error(err))
+		}
+		output, err := flags.GetOutputFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get output flag value", zap.Error(err))
+		}
+		password, err := flags.GetKeystorePasswordFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get keystore password flag value", zap.Error(err))
+		}
+		if len(password) == 0 {
+			if password, err = promptNewPassword(); err != nil {
+				logger.Fatal("failed to read keystore passphrase", zap.Error(err))
+			}
+		}
+
+		raw, err := keystore.Encrypt([]byte(secret), password)
+		if err != nil {
+			logger.Fatal("failed to encrypt key", zap.Error(err))
+		}
+		if err := ioutil.WriteFile(output, raw, 0600); err != nil {
+			logger.Fatal("failed to write keystore", zap.Error(err))
+		}
+		fmt.Println("Keystore was created:", output)
+	},
+}
+
+func promptNewPassword() (string, error) {
+	password, err := prompt.Stdin.PromptPassword("Keystore passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	confirm, err := prompt.Stdin.PromptPassword("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirm {
+		return "", errors.New("passphrases do not match")
+	}
+	return password, nil
+}
+
+func init() {
+	flags.AddSecretFlag(createKeystoreCmd)
+	flags.AddOutputFlag(createKeystoreCmd)
+	flags.AddKeystorePasswordFlag(createKeystoreCmd)
+
+	RootCmd.AddCommand(createKeystoreCmd)
+}
@@ -0,0 +1,255 @@
+package db
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/beacon"
+	exporterstorage "github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/operator"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/cliflag"
+	"github.com/bloxapp/ssv/utils/format"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"os"
+	"strings"
+)
+
+// Flag names.
+const (
+	dbPathFlag       = "db-path"
+	pubKeyFlag       = "pubkey"
+	roleFlag         = "role"
+	instanceTypeFlag = "instance-type"
+	fromFlag         = "from"
+	toFlag           = "to"
+	networkFlag      = "network"
+	fileFlag         = "file"
+	seqFlag          = "seq"
+	inferTimeoutFlag = "infer-timeouts"
+	pubKeysFlag      = "pubkeys"
+	purgeFlag        = "purge"
+	addrFlag         = "addr"
+	writableFlag     = "writable"
+)
+
+// DBCmd is the parent command of the database inspection commands,
+// the database is opened in read only mode (except of imports), anyway it must not be used by a running node
+var DBCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspects a (closed) node database",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		threshold.Init()
+	},
+}
+
+var sharesCmd = &cobra.Command{
+	Use:   "shares",
+	Short: "Lists the validators shares",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(cmd, listShares)
+	},
+}
+
+var highestDecidedCmd = &cobra.Command{
+	Use:   "highest-decided",
+	Short: "Prints the highest decided message of a validator",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(cmd, printHighestDecided)
+	},
+}
+
+var decidedCmd = &cobra.Command{
+	Use:   "decided",
+	Short: "Dumps a range of decided messages of a validator as JSON",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(cmd, dumpDecided)
+	},
+}
+
+var syncOffsetCmd = &cobra.Command{
+	Use:   "sync-offset",
+	Short: "Prints the eth1 sync offset of the operator node and of the exporter",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(cmd, printSyncOffset)
+	},
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [dump files]",
+	Short: "Replays dumped consensus messages of an ibft instance, using the share in the database",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		run(cmd, replayInstance)
+	},
+}
+
+var verifyDecidedCmd = &cobra.Command{
+	Use:   "verify-decided",
+	Short: "Re-verifies the aggregated signatures of the stored decided messages against the committees of the shares",
+	Run: func(cmd *cobra.Command, args []string) {
+		// the database is opened for writing only if invalid messages are purged
+		purge, _ := cmd.Flags().GetBool(purgeFlag)
+		runWithDB(cmd, !purge, verifyDecided)
+	},
+}
+
+var slashingProtectionCmd = &cobra.Command{
+	Use:   "slashing-protection",
+	Short: "Exports and imports the slashing protection of share keys (EIP-3076)",
+}
+
+var slashingProtectionExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the slashing protection of all share keys as EIP-3076 interchange JSON",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(cmd, exportSlashingProtection)
+	},
+}
+
+var slashingProtectionImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports EIP-3076 interchange JSON, merged with the stored slashing protection",
+	Run: func(cmd *cobra.Command, args []string) {
+		runWithDB(cmd, false, importSlashingProtection)
+	},
+}
+
+// run opens the database in read only mode and runs the given command
+func run(cmd *cobra.Command, f func(cmd *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error) {
+	runWithDB(cmd, true, f)
+}
+
+// runWithDB opens the database and runs the given command, the output is written to stdout
+func runWithDB(cmd *cobra.Command, readOnly bool, f func(cmd *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error) {
+	// only warnings are logged so the output can be piped
+	logger := logex.Build(cmd.Root().Short, zapcore.WarnLevel, nil)
+	path, err := cmd.Flags().GetString(dbPathFlag)
+	if err != nil {
+		logger.Fatal("failed to get db path flag value", zap.Error(err))
+	}
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:     "badger-db",
+		Path:     path,
+		ReadOnly: readOnly,
+		Logger:   logger,
+	})
+	if err != nil {
+		logger.Fatal("failed to open db", zap.Error(err))
+	}
+	defer db.Close()
+
+	if err := f(cmd, db, logger, os.Stdout); err != nil {
+		logger.Fatal(fmt.Sprintf("failed to run %s", cmd.Name()), zap.Error(err))
+	}
+}
+
+func init() {
+	cliflag.AddPersistentStringFlag(DBCmd, dbPathFlag, "./data/db", "Path of the database", false)
+
+	for _, c := range []*cobra.Command{highestDecidedCmd, decidedCmd} {
+		cliflag.AddPersistentStringFlag(c, pubKeyFlag, "", "Hex encoded validator public key", true)
+		cliflag.AddPersistentStringFlag(c, roleFlag, beacon.RoleTypeAttester.String(), "Role of the ibft instance", false)
+		cliflag.AddPersistentStringFlag(c, instanceTypeFlag, "",
+			"Prefix of the ibft storage, defaults to the role ('attestation' for exporter databases)", false)
+	}
+	cliflag.AddPersistentIntFlag(decidedCmd, fromFlag, 0, "First sequence number of the range", false)
+	cliflag.AddPersistentIntFlag(decidedCmd, toFlag, 0, "Last sequence number of the range, defaults to the highest decided", false)
+
+	cliflag.AddPersistentStringFlag(slashingProtectionCmd, networkFlag, string(core.PraterNetwork), "Beacon network of the share keys", false)
+	cliflag.AddPersistentStringFlag(slashingProtectionImportCmd, fileFlag, "", "Path of the interchange JSON file", true)
+	slashingProtectionCmd.AddCommand(slashingProtectionExportCmd, slashingProtectionImportCmd)
+
+	cliflag.AddPersistentStringFlag(replayCmd, pubKeyFlag, "", "Hex encoded validator public key", true)
+	cliflag.AddPersistentStringFlag(replayCmd, roleFlag, beacon.RoleTypeAttester.String(), "Role of the ibft instance", false)
+	cliflag.AddPersistentIntFlag(replayCmd, seqFlag, 0, "Sequence number of the ibft instance", true)
+	cliflag.AddPersistentBoolFlag(replayCmd, inferTimeoutFlag, true,
+		"Trigger round timeouts before round changes of this operator, as timeouts are not dumped")
+
+	cliflag.AddPersistentStringFlag(verifyDecidedCmd, pubKeysFlag, "",
+		"Comma separated hex encoded validator public keys, defaults to all the validators", false)
+	cliflag.AddPersistentStringFlag(verifyDecidedCmd, roleFlag, beacon.RoleTypeAttester.String(), "Role of the ibft instances", false)
+	cliflag.AddPersistentStringFlag(verifyDecidedCmd, instanceTypeFlag, "",
+		"Prefix of the ibft storage, defaults to the role ('attestation' for exporter databases)", false)
+	cliflag.AddPersistentBoolFlag(verifyDecidedCmd, purgeFlag, false,
+		"Remove invalid decided messages and move the highest decided below them, so they are synced once again")
+
+	cliflag.AddPersistentStringFlag(serveCmd, addrFlag, "127.0.0.1:5050", "Address that the db is served on", false)
+	cliflag.AddPersistentBoolFlag(serveCmd, writableFlag, false,
+		"Allow write operations, otherwise only reads and the leader lease (compare and swap) are allowed")
+
+	DBCmd.AddCommand(sharesCmd, highestDecidedCmd, decidedCmd, syncOffsetCmd, slashingProtectionCmd, replayCmd,
+		verifyDecidedCmd, serveCmd)
+}
+
+// identifierFlagsValue returns the ibft storage instance type and the identifier of the validator in the flags
+func identifierFlagsValue(cmd *cobra.Command) (string, []byte, error) {
+	pkBytes, role, err := validatorFlagsValue(cmd)
+	if err != nil {
+		return "", nil, err
+	}
+	instanceType, err := cmd.Flags().GetString(instanceTypeFlag)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(instanceType) == 0 {
+		instanceType = role
+	}
+	return instanceType, []byte(format.IdentifierFormat(pkBytes, role)), nil
+}
+
+// validatorFlagsValue returns the validator public key and the role in the flags
+func validatorFlagsValue(cmd *cobra.Command) ([]byte, string, error) {
+	pk, err := cmd.Flags().GetString(pubKeyFlag)
+	if err != nil {
+		return nil, "", err
+	}
+	pkBytes, err := hex.DecodeString(strings.TrimPrefix(pk, "0x"))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "invalid public key")
+	}
+	role, err := cmd.Flags().GetString(roleFlag)
+	if err != nil {
+		return nil, "", err
+	}
+	return pkBytes, role, nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// syncOffset is the output of sync-offset command
+type syncOffset struct {
+	Operator string `json:"operator,omitempty"`
+	Exporter string `json:"exporter,omitempty"`
+}
+
+func printSyncOffset(_ *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error {
+	res := syncOffset{}
+	offset, found, err := operator.NewOperatorNodeStorage(db, logger).GetSyncOffset()
+	if err != nil {
+		return errors.Wrap(err, "could not get operator sync offset")
+	}
+	if found {
+		res.Operator = offset.String()
+	}
+	offset, found, err = exporterstorage.NewExporterStorage(db, logger).GetSyncOffset()
+	if err != nil {
+		return errors.Wrap(err, "could not get exporter sync offset")
+	}
+	if found {
+		res.Exporter = offset.String()
+	}
+	return writeJSON(w, res)
+}
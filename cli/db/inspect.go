@@ -0,0 +1,109 @@
+package db
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/collections"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"io"
+	"sort"
+)
+
+// share is the output item of shares command
+type share struct {
+	PublicKey string                    `json:"publicKey"`
+	NodeID    uint64                    `json:"nodeId"`
+	Committee []uint64                  `json:"committee"`
+	Metadata  *beacon.ValidatorMetadata `json:"metadata,omitempty"`
+	Version   uint64                    `json:"version"`
+}
+
+func listShares(_ *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error {
+	shares, err := validatorstorage.NewCollection(validatorstorage.CollectionOptions{
+		DB:     db,
+		Logger: logger,
+	}).GetAllValidatorsShare()
+	if err != nil {
+		return errors.Wrap(err, "could not get shares")
+	}
+	res := make([]share, 0, len(shares))
+	for _, s := range shares {
+		item := share{
+			PublicKey: s.PublicKey.SerializeToHexStr(),
+			NodeID:    s.NodeID,
+			Committee: make([]uint64, 0, len(s.Committee)),
+			Metadata:  s.Metadata,
+			Version:   s.Version,
+		}
+		for id := range s.Committee {
+			item.Committee = append(item.Committee, id)
+		}
+		sort.Slice(item.Committee, func(i, j int) bool {
+			return item.Committee[i] < item.Committee[j]
+		})
+		res = append(res, item)
+	}
+	return writeJSON(w, res)
+}
+
+func printHighestDecided(cmd *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error {
+	instanceType, identifier, err := identifierFlagsValue(cmd)
+	if err != nil {
+		return err
+	}
+	ibftStorage := collections.NewIbft(db, logger, instanceType)
+	msg, found, err := ibftStorage.GetHighestDecidedInstance(identifier)
+	if err != nil {
+		return errors.Wrap(err, "could not get highest decided")
+	}
+	if !found {
+		return errors.Errorf("could not find highest decided of %s", string(identifier))
+	}
+	return writeJSON(w, msg)
+}
+
+func dumpDecided(cmd *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error {
+	instanceType, identifier, err := identifierFlagsValue(cmd)
+	if err != nil {
+		return err
+	}
+	from, err := cmd.Flags().GetUint64(fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := cmd.Flags().GetUint64(toFlag)
+	if err != nil {
+		return err
+	}
+	ibftStorage := collections.NewIbft(db, logger, instanceType)
+	if to == 0 {
+		highest, found, err := ibftStorage.GetHighestDecidedInstance(identifier)
+		if err != nil {
+			return errors.Wrap(err, "could not get highest decided")
+		}
+		if !found {
+			return errors.Errorf("could not find highest decided of %s", string(identifier))
+		}
+		to = highest.Message.SeqNumber
+	}
+	if from > to {
+		return errors.Errorf("invalid range %d - %d", from, to)
+	}
+	res := make([]*proto.SignedMessage, 0)
+	for seq := from; seq <= to; seq++ {
+		msg, found, err := ibftStorage.GetDecided(identifier, seq)
+		if err != nil {
+			return errors.Wrapf(err, "could not get decided %d", seq)
+		}
+		if !found {
+			logger.Warn("missing decided", zap.Uint64("seq", seq))
+			continue
+		}
+		res = append(res, msg)
+	}
+	return writeJSON(w, res)
+}
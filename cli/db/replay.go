@@ -0,0 +1,78 @@
+package db
+
+import (
+	"github.com/bloxapp/ssv/ibft/replay"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/format"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"sort"
+)
+
+func replayInstance(cmd *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error {
+	pk, role, err := validatorFlagsValue(cmd)
+	if err != nil {
+		return err
+	}
+	seq, err := cmd.Flags().GetUint64(seqFlag)
+	if err != nil {
+		return err
+	}
+	inferTimeouts, err := cmd.Flags().GetBool(inferTimeoutFlag)
+	if err != nil {
+		return err
+	}
+	share, found, err := validatorstorage.NewCollection(validatorstorage.CollectionOptions{
+		DB:     db,
+		Logger: logger,
+	}).GetValidatorShare(pk)
+	if err != nil {
+		return errors.Wrap(err, "could not get share")
+	}
+	if !found {
+		return errors.New("could not find share")
+	}
+	records, err := readDumps(cmd.Flags().Args())
+	if err != nil {
+		return err
+	}
+	res, err := replay.Replay(replay.Options{
+		Logger:        logger,
+		Share:         share,
+		Identifier:    []byte(format.IdentifierFormat(pk, role)),
+		SeqNumber:     seq,
+		InferTimeouts: inferTimeouts,
+	}, records)
+	if err != nil {
+		return errors.Wrap(err, "could not replay instance")
+	}
+	return writeJSON(w, res)
+}
+
+// readDumps reads the records of the given dump files, ordered by time
+func readDumps(paths []string) ([]*msgqueue.DumpRecord, error) {
+	var records []*msgqueue.DumpRecord
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not open dump file")
+		}
+		err = msgqueue.ReadDump(f, func(record *msgqueue.DumpRecord) error {
+			records = append(records, record)
+			return nil
+		})
+		_ = f.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", path)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time < records[j].Time
+	})
+	return records, nil
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"github.com/bloxapp/ssv/exporter/leader"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"os"
+	"time"
+)
+
+// serveTokenEnv is the env var of the bearer token that db requests are authenticated with,
+// which is not a flag in order to keep it out of the process arguments
+const serveTokenEnv = "DB_TOKEN"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves the database to other processes (remote-db), e.g. exporter replicas that share a database",
+	Long: "Serves the database to other processes (remote-db), e.g. exporter replicas that share a database.\n" +
+		"Requests are authenticated with the bearer token in " + serveTokenEnv + ", " +
+		"write operations are allowed only with --" + writableFlag,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logex.Build(cmd.Root().Short, zapcore.InfoLevel, nil)
+		path, err := cmd.Flags().GetString(dbPathFlag)
+		if err != nil {
+			logger.Fatal("failed to get db path flag value", zap.Error(err))
+		}
+		addr, err := cmd.Flags().GetString(addrFlag)
+		if err != nil {
+			logger.Fatal("failed to get addr flag value", zap.Error(err))
+		}
+		writable, err := cmd.Flags().GetBool(writableFlag)
+		if err != nil {
+			logger.Fatal("failed to get writable flag value", zap.Error(err))
+		}
+		db, err := storage.GetStorageFactory(basedb.Options{
+			Type:       "badger-db",
+			Path:       path,
+			Logger:     logger,
+			Ctx:        context.Background(),
+			GCInterval: 10 * time.Minute,
+		})
+		if err != nil {
+			logger.Fatal("failed to open db", zap.Error(err))
+		}
+		defer db.Close()
+
+		handler, err := kv.NewServer(logger, db, kv.ServerOptions{
+			Token:       os.Getenv(serveTokenEnv),
+			Writable:    writable,
+			CASPrefixes: [][]byte{leader.LeasePrefix},
+		})
+		if err != nil {
+			logger.Fatal("failed to create db server", zap.Error(err))
+		}
+
+		logger.Info("serving db", zap.String("addr", addr), zap.String("path", path), zap.Bool("writable", writable))
+		if err := http.ListenAndServe(addr, handler); err != nil {
+			logger.Fatal("failed to serve db", zap.Error(err))
+		}
+	},
+}
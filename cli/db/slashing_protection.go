@@ -0,0 +1,64 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/beacon/goclient/ekm"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
+)
+
+// networkFlagValue returns the beacon network in the flags
+func networkFlagValue(cmd *cobra.Command) (core.Network, error) {
+	name, err := cmd.Flags().GetString(networkFlag)
+	if err != nil {
+		return "", err
+	}
+	network := core.NetworkFromString(name)
+	if len(network) == 0 {
+		return "", errors.Errorf("unknown network: %s", name)
+	}
+	return network, nil
+}
+
+func exportSlashingProtection(cmd *cobra.Command, db basedb.IDb, _ *zap.Logger, w io.Writer) error {
+	network, err := networkFlagValue(cmd)
+	if err != nil {
+		return err
+	}
+	interchange, err := ekm.ExportSlashingProtection(db, network)
+	if err != nil {
+		return errors.Wrap(err, "could not export slashing protection")
+	}
+	return writeJSON(w, interchange)
+}
+
+func importSlashingProtection(cmd *cobra.Command, db basedb.IDb, _ *zap.Logger, w io.Writer) error {
+	network, err := networkFlagValue(cmd)
+	if err != nil {
+		return err
+	}
+	path, err := cmd.Flags().GetString(fileFlag)
+	if err != nil {
+		return err
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "could not read interchange file")
+	}
+	var interchange ekm.Interchange
+	if err := json.Unmarshal(raw, &interchange); err != nil {
+		return errors.Wrap(err, "could not decode interchange file")
+	}
+	n, err := ekm.ImportSlashingProtection(db, network, &interchange)
+	if err != nil {
+		return errors.Wrap(err, "could not import slashing protection")
+	}
+	_, err = fmt.Fprintf(w, "slashing protection of %d keys was imported\n", n)
+	return err
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"encoding/hex"
+	"github.com/bloxapp/ssv/ibft/integrity"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/collections"
+	"github.com/bloxapp/ssv/utils/format"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"io"
+	"strings"
+)
+
+// verifiedDecided is the output item of verify-decided command
+type verifiedDecided struct {
+	PublicKey string `json:"publicKey"`
+	*integrity.Report
+}
+
+func verifyDecided(cmd *cobra.Command, db basedb.IDb, logger *zap.Logger, w io.Writer) error {
+	pubKeys, err := cmd.Flags().GetString(pubKeysFlag)
+	if err != nil {
+		return err
+	}
+	role, err := cmd.Flags().GetString(roleFlag)
+	if err != nil {
+		return err
+	}
+	instanceType, err := cmd.Flags().GetString(instanceTypeFlag)
+	if err != nil {
+		return err
+	}
+	if len(instanceType) == 0 {
+		instanceType = role
+	}
+	purge, err := cmd.Flags().GetBool(purgeFlag)
+	if err != nil {
+		return err
+	}
+	shares, err := selectShares(db, logger, pubKeys)
+	if err != nil {
+		return err
+	}
+	ibftStorage := collections.NewIbft(db, logger, instanceType)
+	res := make([]verifiedDecided, 0, len(shares))
+	for _, share := range shares {
+		identifier := []byte(format.IdentifierFormat(share.PublicKey.Serialize(), role))
+		report, err := integrity.VerifyDecided(integrity.Options{
+			Logger:     logger,
+			Storage:    &ibftStorage,
+			Share:      share,
+			Identifier: identifier,
+			Purge:      purge,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "could not verify decided of %s", share.PublicKey.SerializeToHexStr())
+		}
+		res = append(res, verifiedDecided{PublicKey: share.PublicKey.SerializeToHexStr(), Report: report})
+	}
+	return writeJSON(w, res)
+}
+
+// selectShares returns the shares of the given (comma separated) public keys, or all the shares if empty
+func selectShares(db basedb.IDb, logger *zap.Logger, pubKeys string) ([]*validatorstorage.Share, error) {
+	collection := validatorstorage.NewCollection(validatorstorage.CollectionOptions{
+		DB:     db,
+		Logger: logger,
+	})
+	if len(pubKeys) == 0 {
+		shares, err := collection.GetAllValidatorsShare()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get shares")
+		}
+		return shares, nil
+	}
+	var shares []*validatorstorage.Share
+	for _, pk := range strings.Split(pubKeys, ",") {
+		pkBytes, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(pk), "0x"))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid public key")
+		}
+		share, found, err := collection.GetValidatorShare(pkBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get share")
+		}
+		if !found {
+			return nil, errors.Errorf("could not find share of %s", pk)
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
@@ -3,6 +3,7 @@ package exporter
 import (
 	"crypto/rsa"
 	"fmt"
+	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/beacon/goclient"
 	global_config "github.com/bloxapp/ssv/cli/config"
@@ -11,13 +12,18 @@ import (
 	"github.com/bloxapp/ssv/exporter"
 	"github.com/bloxapp/ssv/exporter/api"
 	"github.com/bloxapp/ssv/exporter/api/adapters/gorilla"
+	"github.com/bloxapp/ssv/exporter/ibft"
+	"github.com/bloxapp/ssv/exporter/sink"
+	exporterstorage "github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/exporter/webhooks"
 	"github.com/bloxapp/ssv/monitoring/metrics"
 	"github.com/bloxapp/ssv/network"
-	networkForkV0 "github.com/bloxapp/ssv/network/forks/v0"
+	"github.com/bloxapp/ssv/network/forks/schedule"
 	"github.com/bloxapp/ssv/network/p2p"
 	"github.com/bloxapp/ssv/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/utils"
+	"github.com/bloxapp/ssv/utils/batchverifier"
 	"github.com/bloxapp/ssv/utils/commons"
 	"github.com/bloxapp/ssv/utils/logex"
 	"github.com/bloxapp/ssv/utils/migrationutils"
@@ -26,22 +32,38 @@ import (
 	"go.uber.org/zap"
 	"log"
 	"net/http"
+	"os"
 	"time"
 )
 
 type config struct {
 	global_config.GlobalConfig `yaml:"global"`
-	DBOptions                  basedb.Options `yaml:"db"`
-	P2pNetworkConfig           p2p.Config     `yaml:"p2p"`
-	ETH1Options                eth1.Options   `yaml:"eth1"`
-	ETH2Options                beacon.Options `yaml:"eth2"`
-
-	WsAPIPort                       int           `yaml:"WebSocketAPIPort" env:"WS_API_PORT" env-default:"14000" env-description:"port of exporter WS api"`
-	MetricsAPIPort                  int           `yaml:"MetricsAPIPort" env:"METRICS_API_PORT" env-description:"port of metrics api"`
-	EnableProfile                   bool          `yaml:"EnableProfile" env:"ENABLE_PROFILE" env-description:"flag that indicates whether go profiling tools are enabled"`
-	IbftSyncEnabled                 bool          `yaml:"IbftSyncEnabled" env:"IBFT_SYNC_ENABLED" env-default:"false" env-description:"enable ibft sync for all topics"`
-	ValidatorMetaDataUpdateInterval time.Duration `yaml:"ValidatorMetaDataUpdateInterval" env:"VALIDATOR_METADATA_UPDATE_INTERVAL" env-default:"12m" env-description:"set the interval at which validator metadata gets updated"`
-	NetworkPrivateKey               string        `yaml:"NetworkPrivateKey" env:"NETWORK_PRIVATE_KEY" env-description:"private key for network identity"`
+	DBOptions                  basedb.Options        `yaml:"db"`
+	P2pNetworkConfig           p2p.Config            `yaml:"p2p"`
+	ETH1Options                eth1.Options          `yaml:"eth1"`
+	ETH2Options                beacon.Options        `yaml:"eth2"`
+	BatchVerifierOptions       batchverifier.Options `yaml:"batchVerifier"`
+	WebhooksOptions            webhooks.Options      `yaml:"webhooks"`
+	SinkOptions                sink.Options          `yaml:"sink"`
+
+	WsAPIPort                          int           `yaml:"WebSocketAPIPort" env:"WS_API_PORT" env-default:"14000" env-description:"port of exporter WS api"`
+	MetricsAPIPort                     int           `yaml:"MetricsAPIPort" env:"METRICS_API_PORT" env-description:"port of metrics api"`
+	EnableProfile                      bool          `yaml:"EnableProfile" env:"ENABLE_PROFILE" env-description:"flag that indicates whether go profiling tools are enabled"`
+	IbftSyncEnabled                    bool          `yaml:"IbftSyncEnabled" env:"IBFT_SYNC_ENABLED" env-default:"false" env-description:"enable ibft sync for all topics"`
+	ValidatorMetaDataUpdateInterval    time.Duration `yaml:"ValidatorMetaDataUpdateInterval" env:"VALIDATOR_METADATA_UPDATE_INTERVAL" env-default:"12m" env-description:"set the min interval at which validator metadata gets updated, extended if the beacon request budget doesn't allow it"`
+	ValidatorMetaDataRequestsPerMinute int           `yaml:"ValidatorMetaDataRequestsPerMinute" env:"VALIDATOR_METADATA_REQUESTS_PER_MINUTE" env-default:"10" env-description:"max beacon requests per minute of periodic metadata updates (0 is unlimited), the batches are spread evenly across the update interval"`
+	ValidatorMetaDataTTL               time.Duration `yaml:"ValidatorMetaDataTTL" env:"VALIDATOR_METADATA_TTL" env-default:"15m" env-description:"age of validator metadata that triggers a background refresh when read by queries"`
+	NetworkPrivateKey                  string        `yaml:"NetworkPrivateKey" env:"NETWORK_PRIVATE_KEY" env-description:"private key for network identity, generated and persisted in db if not provided"`
+	ReadersWorkers                     int           `yaml:"ReadersWorkers" env:"READERS_WORKERS" env-default:"32" env-description:"number of workers that handle incoming messages of all validators"`
+	ReplicaID                          string        `yaml:"ReplicaID" env:"REPLICA_ID" env-description:"unique id of this replica, defaults to the hostname"`
+	LeaderLeaseDuration                time.Duration `yaml:"LeaderLeaseDuration" env:"LEADER_LEASE_DURATION" env-description:"enables leader election among replicas that share the db, the leader runs the sync role"`
+	OperatorsMetadataURL               string        `yaml:"OperatorsMetadataURL" env:"OPERATORS_METADATA_URL" env-description:"HTTPS endpoint that serves display metadata (name, logo, description) of operators"`
+	OperatorsMetadataInterval          time.Duration `yaml:"OperatorsMetadataInterval" env:"OPERATORS_METADATA_INTERVAL" env-default:"10m" env-description:"interval of operators metadata updates"`
+	PeersGeoDBPath                     string        `yaml:"PeersGeoDBPath" env:"PEERS_GEO_DB" env-description:"path of an ip2asn (tsv) database (https://iptoasn.com) that peers countries and ASNs are resolved from"`
+	ReadOnly                           bool          `yaml:"ReadOnly" env:"READ_ONLY" env-default:"false" env-description:"serve api queries from the existing db w/o eth1 sync, p2p or beacon connections"`
+	AdminPublicKey                     string        `yaml:"AdminPublicKey" env:"ADMIN_PUBLIC_KEY" env-description:"public key (base64 encoded pem) that signed control messages are verified against, control messages are ignored if not provided"`
+
+	DecidedCheckpoints []ibft.DecidedCheckpoint `yaml:"DecidedCheckpoints"`
 }
 
 var cfg config
@@ -74,9 +96,19 @@ var StartExporterNodeCmd = &cobra.Command{
 		if errLogLevel != nil {
 			Logger.Warn(fmt.Sprintf("Default log level set to %s", loggerLevel), zap.Error(errLogLevel))
 		}
+		profile, err := global_config.ApplyNetwork(cfg.Network, &cfg.ETH1Options, &cfg.ETH2Options, &cfg.P2pNetworkConfig)
+		if err != nil {
+			Logger.Fatal("failed to apply network profile", zap.Error(err))
+		}
+
 		cfg.DBOptions.Logger = Logger
 		cfg.DBOptions.Ctx = cmd.Context()
 
+		if cfg.ReadOnly {
+			startReadOnly(cmd, Logger)
+			return
+		}
+
 		// TODO remove once all operators updated to vXXX
 		ok, err := migrationutils.E2kmMigration(Logger, cfg.DBOptions.Path)
 		if err != nil {
@@ -91,10 +123,20 @@ var StartExporterNodeCmd = &cobra.Command{
 			Logger.Fatal("failed to create db!", zap.Error(err))
 		}
 
-		cfg.P2pNetworkConfig.NetworkPrivateKey = utils.ECDSAPrivateKey(Logger, cfg.NetworkPrivateKey)
+		networkPrivateKey, err := utils.NetworkPrivateKey(Logger, db, cfg.NetworkPrivateKey)
+		if err != nil {
+			Logger.Fatal("failed to setup network private key", zap.Error(err))
+		}
+		cfg.P2pNetworkConfig.NetworkPrivateKey = networkPrivateKey
+		cfg.P2pNetworkConfig.DB = db
 		cfg.P2pNetworkConfig.ReportLastMsg = true
-		// TODO add fork interface for exporter or use the same forks as in operator
-		cfg.P2pNetworkConfig.Fork = networkForkV0.New()
+		eth2Network := core.NetworkFromString(cfg.ETH2Options.Network)
+		forkManager, err := schedule.NewManager(Logger, eth2Network, profile.ForkEpochs)
+		if err != nil {
+			Logger.Fatal("failed to create network fork manager", zap.Error(err))
+		}
+		go forkManager.Start(cmd.Context(), eth2Network.SlotDurationSec())
+		cfg.P2pNetworkConfig.Fork = forkManager
 		network, err := p2p.New(cmd.Context(), Logger, &cfg.P2pNetworkConfig)
 		if err != nil {
 			Logger.Fatal("failed to create network", zap.Error(err))
@@ -114,6 +156,9 @@ var StartExporterNodeCmd = &cobra.Command{
 			ContractABI:          eth1.ContractABI(),
 			ConnectionTimeout:    cfg.ETH1Options.ETH1ConnectionTimeout,
 			RegistryContractAddr: cfg.ETH1Options.RegistryContractAddr,
+			HTTPFallbackAddr:     cfg.ETH1Options.ETH1HTTPAddr,
+			PollInterval:         cfg.ETH1Options.ETH1PollInterval,
+			QuarantineStorage:    exporterstorage.NewExporterStorage(db, Logger),
 			// using an empty private key provider
 			// because the exporter doesn't run in the context of an operator
 			ShareEncryptionKeyProvider: func() (*rsa.PrivateKey, bool, error) {
@@ -137,6 +182,7 @@ var StartExporterNodeCmd = &cobra.Command{
 		exporterOptions := new(exporter.Options)
 		exporterOptions.Eth1Client = eth1Client
 		exporterOptions.Beacon = beaconClient
+		exporterOptions.ETHNetwork = &eth2Network
 		exporterOptions.Logger = Logger
 		exporterOptions.Network = network
 		exporterOptions.DB = db
@@ -146,6 +192,24 @@ var StartExporterNodeCmd = &cobra.Command{
 		exporterOptions.IbftSyncEnabled = cfg.IbftSyncEnabled
 		exporterOptions.CleanRegistryData = cfg.ETH1Options.CleanRegistryData
 		exporterOptions.ValidatorMetaDataUpdateInterval = cfg.ValidatorMetaDataUpdateInterval
+		exporterOptions.ValidatorMetaDataRequestsPerMinute = cfg.ValidatorMetaDataRequestsPerMinute
+		exporterOptions.ValidatorMetaDataTTL = cfg.ValidatorMetaDataTTL
+		exporterOptions.BatchVerifierOptions = cfg.BatchVerifierOptions
+		exporterOptions.DecidedCheckpoints = cfg.DecidedCheckpoints
+		exporterOptions.ReadersWorkers = cfg.ReadersWorkers
+		exporterOptions.LeaderLeaseDuration = cfg.LeaderLeaseDuration
+		exporterOptions.ReplicaID = cfg.ReplicaID
+		exporterOptions.WebhooksOptions = cfg.WebhooksOptions
+		exporterOptions.SinkOptions = cfg.SinkOptions
+		exporterOptions.OperatorsMetadataURL = cfg.OperatorsMetadataURL
+		exporterOptions.OperatorsMetadataInterval = cfg.OperatorsMetadataInterval
+		exporterOptions.PeersGeoDBPath = cfg.PeersGeoDBPath
+		exporterOptions.AdminPublicKey = cfg.AdminPublicKey
+		if len(exporterOptions.ReplicaID) == 0 {
+			if exporterOptions.ReplicaID, err = os.Hostname(); err != nil {
+				Logger.Fatal("failed to get hostname for replica id", zap.Error(err))
+			}
+		}
 
 		exporterNode = exporter.New(*exporterOptions)
 
@@ -168,6 +232,35 @@ func init() {
 	global_config.ProcessArgs(&cfg, &globalArgs, StartExporterNodeCmd)
 }
 
+// startReadOnly starts an exporter that serves api queries from the existing db,
+// eth1, p2p and beacon clients are not created
+func startReadOnly(cmd *cobra.Command, logger *zap.Logger) {
+	cfg.DBOptions.ReadOnly = true
+	db, err := storage.GetStorageFactory(cfg.DBOptions)
+	if err != nil {
+		logger.Fatal("failed to create db!", zap.Error(err))
+	}
+
+	exporterOptions := new(exporter.Options)
+	eth2Network := core.NetworkFromString(cfg.ETH2Options.Network)
+	exporterOptions.ETHNetwork = &eth2Network
+	exporterOptions.Logger = logger
+	exporterOptions.DB = db
+	exporterOptions.Ctx = cmd.Context()
+	exporterOptions.WS = api.NewWsServer(logger, gorilla.NewGorillaAdapter(logger), nil, http.NewServeMux())
+	exporterOptions.WsAPIPort = cfg.WsAPIPort
+	exporterOptions.ReadOnly = true
+
+	exporterNode = exporter.New(*exporterOptions)
+
+	if cfg.MetricsAPIPort > 0 {
+		go startMetricsHandler(logger, nil, cfg.MetricsAPIPort, cfg.EnableProfile)
+	}
+	if err := exporterNode.Start(); err != nil {
+		logger.Fatal("failed to start exporter", zap.Error(err))
+	}
+}
+
 func startMetricsHandler(logger *zap.Logger, net network.Network, port int, enableProf bool) {
 	// init and start HTTP handler
 	metricsHandler := metrics.NewMetricsHandler(logger, enableProf, exporterNode.(metrics.HealthCheckAgent))
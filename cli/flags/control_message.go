@@ -0,0 +1,66 @@
+package flags
+
+import (
+	"github.com/spf13/cobra"
+	"strings"
+
+	"github.com/bloxapp/ssv/utils/cliflag"
+)
+
+// Flag names.
+const (
+	adminKeyFlag      = "admin-key"
+	nonceFlag         = "nonce"
+	syncWhitelistFlag = "sync-whitelist"
+	syncBlacklistFlag = "sync-blacklist"
+)
+
+// AddAdminKeyFlag adds the admin key flag to the command
+func AddAdminKeyFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, adminKeyFlag, "", "Admin private key (base64 encoded pem) of the exporters", true)
+}
+
+// GetAdminKeyFlagValue gets the admin key flag from the command
+func GetAdminKeyFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(adminKeyFlag)
+}
+
+// AddNonceFlag adds the nonce flag to the command
+func AddNonceFlag(c *cobra.Command) {
+	cliflag.AddPersistentIntFlag(c, nonceFlag, 0, "Nonce of the control message, must be higher than the nonce of the previous message", true)
+}
+
+// GetNonceFlagValue gets the nonce flag from the command
+func GetNonceFlagValue(c *cobra.Command) (uint64, error) {
+	return c.Flags().GetUint64(nonceFlag)
+}
+
+// AddSyncListsFlags adds the sync whitelist and blacklist flags to the command
+func AddSyncListsFlags(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, syncWhitelistFlag, "", "Comma separated validators (hex public keys) to sync even if ibft sync is disabled", false)
+	cliflag.AddPersistentStringFlag(c, syncBlacklistFlag, "", "Comma separated validators (hex public keys) not to sync", false)
+}
+
+// GetSyncListsFlagValues gets the sync whitelist and blacklist flags from the command
+func GetSyncListsFlagValues(c *cobra.Command) ([]string, []string, error) {
+	whitelist, err := c.Flags().GetString(syncWhitelistFlag)
+	if err != nil {
+		return nil, nil, err
+	}
+	blacklist, err := c.Flags().GetString(syncBlacklistFlag)
+	if err != nil {
+		return nil, nil, err
+	}
+	return splitList(whitelist), splitList(blacklist), nil
+}
+
+// splitList splits the given comma separated list, empty items are omitted
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
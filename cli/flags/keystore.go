@@ -0,0 +1,44 @@
+package flags
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/ssv/utils/cliflag"
+)
+
+// Flag names.
+const (
+	secretFlag           = "secret"
+	outputFlag           = "output"
+	keystorePasswordFlag = "keystore-password"
+)
+
+// AddSecretFlag adds the secret flag to the command
+func AddSecretFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, secretFlag, "", "Key to encrypt, in the format of the config (OperatorPrivateKey or NetworkPrivateKey)", true)
+}
+
+// GetSecretFlagValue gets the secret flag from the command
+func GetSecretFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(secretFlag)
+}
+
+// AddOutputFlag adds the output flag to the command
+func AddOutputFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, outputFlag, "", "Path of the output file", true)
+}
+
+// GetOutputFlagValue gets the output flag from the command
+func GetOutputFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(outputFlag)
+}
+
+// AddKeystorePasswordFlag adds the keystore password flag to the command
+func AddKeystorePasswordFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, keystorePasswordFlag, "", "Passphrase of the keystore, prompted if not provided", false)
+}
+
+// GetKeystorePasswordFlagValue gets the keystore password flag from the command
+func GetKeystorePasswordFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(keystorePasswordFlag)
+}
@@ -0,0 +1,55 @@
+package flags
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/ssv/utils/cliflag"
+)
+
+// Flag names.
+const (
+	operatorKeyFlag         = "operator-key"
+	operatorKeystoreFlag    = "keystore"
+	newKeystorePasswordFlag = "new-keystore-password"
+	enrFlag                 = "enr"
+)
+
+// AddOperatorKeyFlag adds the operator key flag to the command
+func AddOperatorKeyFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, operatorKeyFlag, "", "Operator private key (base64 encoded pem, as in OPERATOR_KEY), if not loaded from a keystore", false)
+}
+
+// GetOperatorKeyFlagValue gets the operator key flag from the command
+func GetOperatorKeyFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(operatorKeyFlag)
+}
+
+// AddOperatorKeystoreFlag adds the operator keystore flag to the command
+func AddOperatorKeystoreFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, operatorKeystoreFlag, "", "Path to an encrypted keystore of the operator private key", false)
+}
+
+// GetOperatorKeystoreFlagValue gets the operator keystore flag from the command
+func GetOperatorKeystoreFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(operatorKeystoreFlag)
+}
+
+// AddNewKeystorePasswordFlag adds the new keystore password flag to the command
+func AddNewKeystorePasswordFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, newKeystorePasswordFlag, "", "Passphrase of the new keystore, prompted if not provided", false)
+}
+
+// GetNewKeystorePasswordFlagValue gets the new keystore password flag from the command
+func GetNewKeystorePasswordFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(newKeystorePasswordFlag)
+}
+
+// AddENRFlag adds the enr flag to the command
+func AddENRFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, enrFlag, "", "ENR of the operator node", true)
+}
+
+// GetENRFlagValue gets the enr flag from the command
+func GetENRFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(enrFlag)
+}
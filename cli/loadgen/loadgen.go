@@ -0,0 +1,81 @@
+package loadgen
+
+import (
+	"fmt"
+	global_config "github.com/bloxapp/ssv/cli/config"
+	networkForkV0 "github.com/bloxapp/ssv/network/forks/v0"
+	"github.com/bloxapp/ssv/network/p2p"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/loadgen"
+	"github.com/bloxapp/ssv/utils/logex"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"log"
+)
+
+type config struct {
+	global_config.GlobalConfig `yaml:"global"`
+	P2pNetworkConfig           p2p.Config      `yaml:"p2p"`
+	Options                    loadgen.Options `yaml:"loadgen"`
+	DBOptions                  basedb.Options  `yaml:"db"`
+	// Register enables the registration of the validators in the exporter db, which must be shared (remote-db)
+	// or not in use. the exporter reads the registered validators once it (re)starts, therefore a seed should be used
+	Register bool `yaml:"Register" env:"LOADGEN_REGISTER" env-description:"register the validators (as shares) in the exporter db before publishing"`
+}
+
+var cfg config
+
+var globalArgs global_config.Args
+
+// LoadGenCmd is the command to generate ibft traffic of fake validators, used to load-test an exporter
+var LoadGenCmd = &cobra.Command{
+	Use:   "ssv-loadgen",
+	Short: "Generates signed decided/ibft traffic of fake validators on test topics",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cleanenv.ReadConfig(globalArgs.ConfigPath, &cfg); err != nil {
+			log.Fatal(err)
+		}
+
+		loggerLevel, err := logex.GetLoggerLevelValue(cfg.LogLevel)
+		Logger := logex.Build(cmd.Short, loggerLevel, &logex.EncodingConfig{Format: cfg.GlobalConfig.LogFormat})
+		if err != nil {
+			Logger.Warn(fmt.Sprintf("Default log level set to %s", loggerLevel), zap.Error(err))
+		}
+
+		cfg.P2pNetworkConfig.Fork = networkForkV0.New()
+		network, err := p2p.New(cmd.Context(), Logger, &cfg.P2pNetworkConfig)
+		if err != nil {
+			Logger.Fatal("failed to create network", zap.Error(err))
+		}
+
+		cfg.Options.Logger = Logger
+		cfg.Options.Network = network
+		if cfg.Register {
+			cfg.DBOptions.Logger = Logger
+			cfg.DBOptions.Ctx = cmd.Context()
+			db, err := storage.GetStorageFactory(cfg.DBOptions)
+			if err != nil {
+				Logger.Fatal("failed to create db", zap.Error(err))
+			}
+			defer db.Close()
+			cfg.Options.Registry = validatorstorage.NewCollection(validatorstorage.CollectionOptions{
+				DB:     db,
+				Logger: Logger,
+			})
+		}
+		generator, err := loadgen.New(cfg.Options)
+		if err != nil {
+			Logger.Fatal("failed to create generator", zap.Error(err))
+		}
+		if err := generator.Start(cmd.Context()); err != nil {
+			Logger.Fatal("failed to generate traffic", zap.Error(err))
+		}
+	},
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, LoadGenCmd)
+}
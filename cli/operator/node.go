@@ -5,20 +5,30 @@ import (
 	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/beacon/goclient"
+	"github.com/bloxapp/ssv/beacon/keymanager"
 	global_config "github.com/bloxapp/ssv/cli/config"
+	"github.com/bloxapp/ssv/dkg"
 	"github.com/bloxapp/ssv/eth1"
 	"github.com/bloxapp/ssv/eth1/goeth"
 	"github.com/bloxapp/ssv/monitoring/metrics"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/forks/schedule"
 	"github.com/bloxapp/ssv/network/p2p"
 	"github.com/bloxapp/ssv/operator"
+	"github.com/bloxapp/ssv/operator/admin"
+	"github.com/bloxapp/ssv/operator/failover"
 	v0 "github.com/bloxapp/ssv/operator/forks/v0"
+	"github.com/bloxapp/ssv/operator/heartbeat"
+	"github.com/bloxapp/ssv/operator/resources"
 	"github.com/bloxapp/ssv/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/utils"
 	"github.com/bloxapp/ssv/utils/commons"
+	"github.com/bloxapp/ssv/utils/keystore"
 	"github.com/bloxapp/ssv/utils/logex"
 	"github.com/bloxapp/ssv/utils/migrationutils"
 	"github.com/bloxapp/ssv/validator"
+	"github.com/ethereum/go-ethereum/console/prompt"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -28,16 +38,24 @@ import (
 
 type config struct {
 	global_config.GlobalConfig `yaml:"global"`
-	DBOptions                  basedb.Options   `yaml:"db"`
-	SSVOptions                 operator.Options `yaml:"ssv"`
-	ETH1Options                eth1.Options     `yaml:"eth1"`
-	ETH2Options                beacon.Options   `yaml:"eth2"`
-	P2pNetworkConfig           p2p.Config       `yaml:"p2p"`
+	DBOptions                  basedb.Options        `yaml:"db"`
+	SSVOptions                 operator.Options      `yaml:"ssv"`
+	ETH1Options                eth1.Options          `yaml:"eth1"`
+	ETH2Options                beacon.Options        `yaml:"eth2"`
+	P2pNetworkConfig           p2p.Config            `yaml:"p2p"`
+	DKGOptions                 dkg.ControllerOptions `yaml:"dkg"`
+	FailoverOptions            failover.Options      `yaml:"failover"`
+	HeartbeatOptions           heartbeat.Options     `yaml:"heartbeat"`
+	ResourcesOptions           resources.Options     `yaml:"resources"`
+	KeystoreOptions            keystore.Options      `yaml:"keystore"`
+	KeyManagerOptions          keymanager.Options    `yaml:"keyManager"`
 
 	OperatorPrivateKey string `yaml:"OperatorPrivateKey" env:"OPERATOR_KEY" env-description:"Operator private key, used to decrypt contract events"`
 	MetricsAPIPort     int    `yaml:"MetricsAPIPort" env:"METRICS_API_PORT" env-description:"port of metrics api"`
 	EnableProfile      bool   `yaml:"EnableProfile" env:"ENABLE_PROFILE" env-description:"flag that indicates whether go profiling tools are enabled"`
-	NetworkPrivateKey  string `yaml:"NetworkPrivateKey" env:"NETWORK_PRIVATE_KEY" env-description:"private key for network identity"`
+	NetworkPrivateKey  string `yaml:"NetworkPrivateKey" env:"NETWORK_PRIVATE_KEY" env-description:"private key for network identity, generated and persisted in db if not provided"`
+	AdminAPIPort       int    `yaml:"AdminAPIPort" env:"ADMIN_API_PORT" env-description:"port of admin api"`
+	AdminAPIToken      string `yaml:"AdminAPIToken" env:"ADMIN_API_TOKEN" env-description:"bearer token for authenticating admin api requests"`
 }
 
 var cfg config
@@ -70,6 +88,27 @@ var StartNodeCmd = &cobra.Command{
 			Logger.Warn(fmt.Sprintf("Default log level set to %s", loggerLevel), zap.Error(errLogLevel))
 		}
 
+		profile, err := global_config.ApplyNetwork(cfg.Network, &cfg.ETH1Options, &cfg.ETH2Options, &cfg.P2pNetworkConfig)
+		if err != nil {
+			Logger.Fatal("failed to apply network profile", zap.Error(err))
+		}
+
+		if err := cfg.KeystoreOptions.Validate(cfg.OperatorPrivateKey, cfg.NetworkPrivateKey); err != nil {
+			Logger.Fatal("invalid keys config", zap.Error(err))
+		}
+		if cfg.KeystoreOptions.Enabled() {
+			operatorKey, networkKey, err := keystore.LoadKeys(cfg.KeystoreOptions, prompt.Stdin.PromptPassword)
+			if err != nil {
+				Logger.Fatal("failed to load keys from keystore", zap.Error(err))
+			}
+			if len(operatorKey) > 0 {
+				cfg.OperatorPrivateKey = operatorKey
+			}
+			if len(networkKey) > 0 {
+				cfg.NetworkPrivateKey = networkKey
+			}
+		}
+
 		// TODO remove once all operators updated to vXXX
 		ok, err := migrationutils.E2kmMigration(Logger, cfg.DBOptions.Path)
 		if err != nil {
@@ -101,6 +140,11 @@ var StartNodeCmd = &cobra.Command{
 			Logger.Fatal("failed to create beacon go-client", zap.Error(err),
 				zap.String("addr", cfg.ETH2Options.BeaconNodeAddr))
 		}
+		keyManager, err := keymanager.New(cfg.KeyManagerOptions, beaconClient, beaconClient,
+			validator.ShareKeyManagerResolver(db, Logger))
+		if err != nil {
+			Logger.Fatal("failed to create key manager", zap.Error(err))
+		}
 
 		operatorStorage := operator.NewOperatorNodeStorage(db, Logger)
 		if err := operatorStorage.SetupPrivateKey(cfg.OperatorPrivateKey); err != nil {
@@ -111,8 +155,18 @@ var StartNodeCmd = &cobra.Command{
 			Logger.Fatal("failed to get operator private key", zap.Error(err))
 		}
 		cfg.P2pNetworkConfig.OperatorPrivateKey = operatorPrivKey
-		cfg.P2pNetworkConfig.NetworkPrivateKey = utils.ECDSAPrivateKey(Logger, cfg.NetworkPrivateKey)
-		cfg.P2pNetworkConfig.Fork = fork.NetworkFork()
+		networkPrivateKey, err := utils.NetworkPrivateKey(Logger, db, cfg.NetworkPrivateKey)
+		if err != nil {
+			Logger.Fatal("failed to setup network private key", zap.Error(err))
+		}
+		cfg.P2pNetworkConfig.NetworkPrivateKey = networkPrivateKey
+		cfg.P2pNetworkConfig.DB = db
+		forkManager, err := schedule.NewManager(Logger, eth2Network, profile.ForkEpochs)
+		if err != nil {
+			Logger.Fatal("failed to create network fork manager", zap.Error(err))
+		}
+		go forkManager.Start(cmd.Context(), eth2Network.SlotDurationSec())
+		cfg.P2pNetworkConfig.Fork = forkManager
 		p2pNet, err := p2p.New(cmd.Context(), Logger, &cfg.P2pNetworkConfig)
 		if err != nil {
 			Logger.Fatal("failed to create network", zap.Error(err))
@@ -135,7 +189,7 @@ var StartNodeCmd = &cobra.Command{
 		cfg.SSVOptions.ValidatorOptions.Network = p2pNet
 		cfg.SSVOptions.ValidatorOptions.Beacon = beaconClient // TODO need to be pointer?
 		cfg.SSVOptions.ValidatorOptions.CleanRegistryData = cfg.ETH1Options.CleanRegistryData
-		cfg.SSVOptions.ValidatorOptions.KeyManager = beaconClient
+		cfg.SSVOptions.ValidatorOptions.KeyManager = keyManager
 
 		cfg.SSVOptions.ValidatorOptions.ShareEncryptionKeyProvider = operatorStorage.GetPrivateKey
 
@@ -154,14 +208,77 @@ var StartNodeCmd = &cobra.Command{
 			ContractABI:                eth1.ContractABI(),
 			RegistryContractAddr:       cfg.ETH1Options.RegistryContractAddr,
 			ShareEncryptionKeyProvider: operatorStorage.GetPrivateKey,
+			HTTPFallbackAddr:           cfg.ETH1Options.ETH1HTTPAddr,
+			PollInterval:               cfg.ETH1Options.ETH1PollInterval,
+			QuarantineStorage:          operatorStorage,
 		})
 		if err != nil {
 			Logger.Fatal("failed to create eth1 client", zap.Error(err))
 		}
 
+		// the validators check the failover coordinator before signing, so it is created first
+		if cfg.FailoverOptions.Mode != failover.ModeDisabled {
+			failoverNet, ok := p2pNet.(network.Failover)
+			if !ok {
+				Logger.Fatal("network does not support failover")
+			}
+			cfg.FailoverOptions.Context = ctx
+			cfg.FailoverOptions.Logger = Logger
+			cfg.FailoverOptions.Network = failoverNet
+			cfg.FailoverOptions.ShareEncryptionKeyProvider = operatorStorage.GetPrivateKey
+			failoverCoordinator := failover.NewCoordinator(cfg.FailoverOptions)
+			if err := failoverCoordinator.Start(); err != nil {
+				Logger.Fatal("failed to start failover coordinator", zap.Error(err))
+			}
+			cfg.SSVOptions.Failover = failoverCoordinator
+			cfg.SSVOptions.ValidatorOptions.Failover = failoverCoordinator
+		}
+
 		validatorCtrl := validator.NewController(cfg.SSVOptions.ValidatorOptions)
 		cfg.SSVOptions.ValidatorController = validatorCtrl
 
+		// shares that are created by dkg are picked up by the validator controller once the validator is registered
+		var dkgCtrl dkg.Controller
+		if dkgNet, ok := p2pNet.(network.DKG); ok {
+			cfg.DKGOptions.Context = ctx
+			cfg.DKGOptions.Logger = Logger
+			cfg.DKGOptions.DB = db
+			cfg.DKGOptions.Network = dkgNet
+			cfg.DKGOptions.KeyManager = keyManager
+			cfg.DKGOptions.ShareEncryptionKeyProvider = operatorStorage.GetPrivateKey
+			dkgCtrl = dkg.NewController(cfg.DKGOptions)
+			if err := dkgCtrl.Start(); err != nil {
+				Logger.Fatal("failed to start dkg controller", zap.Error(err))
+			}
+		}
+
+		var resourcesSampler *resources.Sampler
+		if cfg.ResourcesOptions.Enabled {
+			cfg.ResourcesOptions.Context = ctx
+			cfg.ResourcesOptions.Logger = Logger
+			if sizer, ok := db.(basedb.Sizer); ok {
+				cfg.ResourcesOptions.DBSize = sizer.Size
+			}
+			resourcesSampler = resources.NewSampler(cfg.ResourcesOptions)
+			resourcesSampler.Start()
+		}
+
+		if heartbeatNet, ok := p2pNet.(network.OperatorHeartbeats); ok {
+			cfg.HeartbeatOptions.Context = ctx
+			cfg.HeartbeatOptions.Logger = Logger
+			cfg.HeartbeatOptions.Network = heartbeatNet
+			cfg.HeartbeatOptions.ShareEncryptionKeyProvider = operatorStorage.GetPrivateKey
+			cfg.HeartbeatOptions.Version = commons.GetBuildData()
+			cfg.HeartbeatOptions.Commit = commons.GetCommit()
+			cfg.HeartbeatOptions.ForkID = forkManager.ID
+			if resourcesSampler != nil {
+				cfg.HeartbeatOptions.Resources = resourcesSampler.Last
+			}
+			if err := heartbeat.NewPublisher(cfg.HeartbeatOptions).Start(); err != nil {
+				Logger.Fatal("failed to start operator heartbeats", zap.Error(err))
+			}
+		}
+
 		operatorNode = operator.New(cfg.SSVOptions)
 
 		metrics.WaitUntilHealthy(Logger, cfg.SSVOptions.Eth1Client, "eth1 node")
@@ -173,6 +290,19 @@ var StartNodeCmd = &cobra.Command{
 		if cfg.MetricsAPIPort > 0 {
 			go startMetricsHandler(Logger, cfg.MetricsAPIPort, cfg.EnableProfile)
 		}
+		if cfg.AdminAPIPort > 0 {
+			quarantine, _ := cfg.SSVOptions.Eth1Client.(eth1.Quarantine)
+			auditor, _ := p2pNet.(network.MessageAuditor)
+			var dkgAdmin admin.DKGController
+			if dkgCtrl != nil {
+				dkgAdmin = dkgCtrl
+			}
+			adminHandler := admin.NewAdminHandler(Logger, cfg.AdminAPIToken, validatorCtrl,
+				operatorNode.(admin.StorageInspector), quarantine, auditor, dkgAdmin)
+			if err := adminHandler.Start(http.NewServeMux(), fmt.Sprintf(":%d", cfg.AdminAPIPort)); err != nil {
+				Logger.Fatal("failed to start admin api", zap.Error(err))
+			}
+		}
 		if err := operatorNode.Start(); err != nil {
 			Logger.Fatal("failed to start SSV node", zap.Error(err))
 		}
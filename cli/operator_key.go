@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bloxapp/ssv/cli/flags"
+	"github.com/bloxapp/ssv/network/p2p"
+	"github.com/bloxapp/ssv/utils/keystore"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+// operatorKeyCmd is the parent command of operator key management
+var operatorKeyCmd = &cobra.Command{
+	Use:   "operator-key",
+	Short: "manages the operator RSA key",
+}
+
+// operatorKeyGenerateCmd generates a new operator key into an encrypted keystore file
+var operatorKeyGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "generates a new operator key into an encrypted keystore file",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := operatorKeyLogger()
+		_, skPem, err := rsaencryption.GenerateKeys()
+		if err != nil {
+			logger.Fatal("failed to generate operator keys", zap.Error(err))
+		}
+		sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+		if err != nil {
+			logger.Fatal("failed to parse operator key", zap.Error(err))
+		}
+		password, err := flags.GetKeystorePasswordFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get keystore password flag value", zap.Error(err))
+		}
+		writeOperatorKeystore(logger, cmd, sk, password)
+		printPublicKey(logger, sk)
+	},
+}
+
+// operatorKeyPublicKeyCmd prints the public key that is used to register the operator
+var operatorKeyPublicKeyCmd = &cobra.Command{
+	Use:   "public-key",
+	Short: "prints the operator public key (base64) that is used for registration",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := operatorKeyLogger()
+		printPublicKey(logger, loadOperatorKey(logger, cmd))
+	},
+}
+
+// operatorKeyVerifyENRCmd verifies that an ENR was created by a node that runs with the operator key
+var operatorKeyVerifyENRCmd = &cobra.Command{
+	Use:   "verify-enr",
+	Short: "verifies that the given ENR holds the public key of the operator key",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := operatorKeyLogger()
+		sk := loadOperatorKey(logger, cmd)
+		record, err := flags.GetENRFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get enr flag value", zap.Error(err))
+		}
+		pk, err := rsaencryption.ExtractPublicKey(sk)
+		if err != nil {
+			logger.Fatal("failed to extract operator public key", zap.Error(err))
+		}
+		if err := p2p.VerifyOperatorENR(record, pk); err != nil {
+			logger.Fatal("ENR doesn't match the operator key", zap.Error(err))
+		}
+		fmt.Println("ENR matches the operator key")
+	},
+}
+
+// operatorKeyEncryptCmd encrypts the operator key into a new keystore file, e.g. to change the passphrase
+var operatorKeyEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "encrypts the operator key into a new keystore file",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := operatorKeyLogger()
+		sk := loadOperatorKey(logger, cmd)
+		password, err := flags.GetNewKeystorePasswordFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get new keystore password flag value", zap.Error(err))
+		}
+		writeOperatorKeystore(logger, cmd, sk, password)
+	},
+}
+
+func operatorKeyLogger() *zap.Logger {
+	return logex.Build(RootCmd.Short, zapcore.InfoLevel, nil)
+}
+
+// loadOperatorKey decodes the operator key flag or decrypts the operator keystore
+func loadOperatorKey(logger *zap.Logger, cmd *cobra.Command) *rsa.PrivateKey {
+	operatorKey, err := flags.GetOperatorKeyFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get operator key flag value", zap.Error(err))
+	}
+	keystorePath, err := flags.GetOperatorKeystoreFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get keystore flag value", zap.Error(err))
+	}
+	switch {
+	case len(operatorKey) > 0 && len(keystorePath) > 0:
+		logger.Fatal("either an operator key or a keystore should be provided, not both")
+	case len(keystorePath) > 0:
+		password, err := flags.GetKeystorePasswordFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get keystore password flag value", zap.Error(err))
+		}
+		operatorKey, _, err = keystore.LoadKeys(keystore.Options{OperatorKeyPath: keystorePath, Password: password},
+			prompt.Stdin.PromptPassword)
+		if err != nil {
+			logger.Fatal("failed to load operator keystore", zap.Error(err))
+		}
+	case len(operatorKey) == 0:
+		logger.Fatal("an operator key or a keystore is required")
+	}
+	sk, err := rsaencryption.ConvertEncodedPemToPrivateKey(operatorKey)
+	if err != nil {
+		logger.Fatal("failed to load operator key", zap.Error(err))
+	}
+	return sk
+}
+
+// writeOperatorKeystore encrypts the operator key (base64, as in OperatorPrivateKey) into the output file
+func writeOperatorKeystore(logger *zap.Logger, cmd *cobra.Command, sk *rsa.PrivateKey, password string) {
+	output, err := flags.GetOutputFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get output flag value", zap.Error(err))
+	}
+	if len(password) == 0 {
+		if password, err = promptNewPassword(); err != nil {
+			logger.Fatal("failed to read keystore passphrase", zap.Error(err))
+		}
+	}
+	secret := base64.StdEncoding.EncodeToString(rsaencryption.PrivateKeyToByte(sk))
+	if err := keystore.WriteFile(output, []byte(secret), password); err != nil {
+		logger.Fatal("failed to write operator keystore", zap.Error(err))
+	}
+	fmt.Println("Keystore was created:", output)
+}
+
+func printPublicKey(logger *zap.Logger, sk *rsa.PrivateKey) {
+	pk, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		logger.Fatal("failed to extract operator public key", zap.Error(err))
+	}
+	fmt.Println("Public Key:", pk)
+}
+
+func init() {
+	flags.AddOutputFlag(operatorKeyGenerateCmd)
+	flags.AddKeystorePasswordFlag(operatorKeyGenerateCmd)
+
+	flags.AddOperatorKeyFlag(operatorKeyPublicKeyCmd)
+	flags.AddOperatorKeystoreFlag(operatorKeyPublicKeyCmd)
+	flags.AddKeystorePasswordFlag(operatorKeyPublicKeyCmd)
+
+	flags.AddOperatorKeyFlag(operatorKeyVerifyENRCmd)
+	flags.AddOperatorKeystoreFlag(operatorKeyVerifyENRCmd)
+	flags.AddKeystorePasswordFlag(operatorKeyVerifyENRCmd)
+	flags.AddENRFlag(operatorKeyVerifyENRCmd)
+
+	flags.AddOperatorKeyFlag(operatorKeyEncryptCmd)
+	flags.AddOperatorKeystoreFlag(operatorKeyEncryptCmd)
+	flags.AddKeystorePasswordFlag(operatorKeyEncryptCmd)
+	flags.AddOutputFlag(operatorKeyEncryptCmd)
+	flags.AddNewKeystorePasswordFlag(operatorKeyEncryptCmd)
+
+	operatorKeyCmd.AddCommand(operatorKeyGenerateCmd, operatorKeyPublicKeyCmd, operatorKeyVerifyENRCmd, operatorKeyEncryptCmd)
+	RootCmd.AddCommand(operatorKeyCmd)
+}
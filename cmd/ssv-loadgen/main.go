@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/bloxapp/ssv/cli/loadgen"
+	"go.uber.org/zap"
+	"log"
+)
+
+func main() {
+	if err := loadgen.LoadGenCmd.Execute(); err != nil {
+		log.Fatal("failed to execute loadgen command", zap.Error(err))
+	}
+}
@@ -10,8 +10,11 @@ var (
 
 	// Version is the app version
 	Version = "latest"
+
+	// Commit is the commit hash of the build
+	Commit = ""
 )
 
 func main() {
-	cli.Execute(AppName, Version)
+	cli.Execute(AppName, Version, Commit)
 }
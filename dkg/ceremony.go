@@ -0,0 +1,395 @@
+package dkg
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// State represents the state of a ceremony
+type State int32
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateInit:
+		return "INIT"
+	case StateDealt:
+		return "DEALT"
+	case StateCompleted:
+		return "COMPLETED"
+	case StateFailed:
+		return "FAILED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// List of ceremony states
+const (
+	// StateInit means the ceremony was created but no deal was sent yet
+	StateInit State = iota
+	// StateDealt means this operator sent its deal and is waiting for the other deals
+	StateDealt
+	// StateCompleted means all deals were received and verified
+	StateCompleted
+	// StateFailed means the ceremony could not be completed
+	StateFailed
+)
+
+// minOperators is the min number of operators in a ceremony (3f+1, f=1)
+const minOperators = 4
+
+// MinThreshold returns the min threshold of a ceremony with the given number of operators (2f+1),
+// so a single operator (or f operators) can't reconstruct the validator key
+func MinThreshold(operators int) uint64 {
+	f := (operators - 1) / 3
+	return uint64(2*f + 1)
+}
+
+// validateParams validates the operators count and the threshold of a ceremony
+func validateParams(operators []Operator, threshold uint64) error {
+	if len(operators) < minOperators {
+		return errors.Errorf("at least %d operators are required, got %d", minOperators, len(operators))
+	}
+	if threshold < MinThreshold(len(operators)) || threshold > uint64(len(operators)) {
+		return errors.Errorf("invalid threshold %d for %d operators", threshold, len(operators))
+	}
+	return nil
+}
+
+// CeremonyOptions holds the needed params for creating a ceremony
+type CeremonyOptions struct {
+	ID                 string
+	Operators          []Operator
+	Threshold          uint64
+	OperatorPrivateKey *rsa.PrivateKey
+}
+
+// Result is the output of a completed ceremony
+type Result struct {
+	// ValidatorPubKey is the jointly generated validator public key
+	ValidatorPubKey *bls.PublicKey
+	// NodeID is the node id of this operator
+	NodeID uint64
+	// ShareKey is the secret share of this operator
+	ShareKey *bls.SecretKey
+	// Committee holds the public shares of all the operators
+	Committee map[uint64]*proto.Node
+}
+
+// ToShare creates a validator share from the result
+func (r *Result) ToShare() *validatorstorage.Share {
+	return &validatorstorage.Share{
+		NodeID:    r.NodeID,
+		PublicKey: r.ValidatorPubKey,
+		Committee: r.Committee,
+	}
+}
+
+// Ceremony is the state machine of a single distributed key generation (joint feldman) for one operator.
+// Every operator acts as a dealer of a random polynomial, and the resulting share is the sum of all the
+// shares it received. The validator key is never reconstructed by any party.
+type Ceremony struct {
+	id           string
+	operators    map[uint64]Operator
+	participants []Operator
+	threshold    uint64
+	nodeID       uint64
+	operatorSK   *rsa.PrivateKey
+
+	lock        sync.Mutex
+	state       State
+	shares      map[uint64]*bls.SecretKey
+	commitments map[uint64][]bls.PublicKey
+	result      *Result
+}
+
+// NewCeremony creates a new ceremony, returns an error if this operator is not part of it
+func NewCeremony(opts CeremonyOptions) (*Ceremony, error) {
+	if opts.OperatorPrivateKey == nil {
+		return nil, errors.New("missing operator private key")
+	}
+	if err := validateParams(opts.Operators, opts.Threshold); err != nil {
+		return nil, err
+	}
+	pk, err := rsaencryption.ExtractPublicKey(opts.OperatorPrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not extract operator public key")
+	}
+	c := &Ceremony{
+		id:           opts.ID,
+		operators:    make(map[uint64]Operator),
+		participants: opts.Operators,
+		threshold:    opts.Threshold,
+		operatorSK:   opts.OperatorPrivateKey,
+		state:        StateInit,
+		shares:       make(map[uint64]*bls.SecretKey),
+		commitments:  make(map[uint64][]bls.PublicKey),
+	}
+	for _, op := range opts.Operators {
+		if op.ID == 0 {
+			return nil, errors.New("operator id must be positive")
+		}
+		if _, exist := c.operators[op.ID]; exist {
+			return nil, errors.Errorf("duplicated operator id %d", op.ID)
+		}
+		c.operators[op.ID] = op
+		if op.PublicKey == pk {
+			c.nodeID = op.ID
+		}
+	}
+	if c.nodeID == 0 {
+		return nil, errors.New("operator is not a participant of the ceremony")
+	}
+	return c, nil
+}
+
+// ID returns the ceremony id
+func (c *Ceremony) ID() string {
+	return c.id
+}
+
+// NodeID returns the node id of this operator
+func (c *Ceremony) NodeID() uint64 {
+	return c.nodeID
+}
+
+// State returns the current state
+func (c *Ceremony) State() State {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.state
+}
+
+// Deal creates a random polynomial and returns a signed message with its commitments
+// and the encrypted shares of all the participants
+func (c *Ceremony) Deal() (*network.DKGMessage, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.state != StateInit {
+		return nil, errors.Errorf("could not deal in state %s", c.state)
+	}
+
+	msk := make([]bls.SecretKey, c.threshold)
+	for i := range msk {
+		msk[i].SetByCSPRNG()
+	}
+	mpk := bls.GetMasterPublicKey(msk)
+
+	payload := dealPayload{
+		DealerID:        c.nodeID,
+		Operators:       c.participants,
+		EncryptedShares: make(map[uint64]string),
+	}
+	for i := range mpk {
+		payload.Commitments = append(payload.Commitments, mpk[i].Serialize())
+	}
+	for id, op := range c.operators {
+		share, err := evaluate(msk, id)
+		if err != nil {
+			return nil, err
+		}
+		pk, err := rsaencryption.ConvertEncodedPemToPublicKey(op.PublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode public key of operator %d", id)
+		}
+		encrypted, err := rsaencryption.EncodeKey(pk, share.SerializeToHexStr())
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not encrypt share of operator %d", id)
+		}
+		payload.EncryptedShares[id] = encrypted
+	}
+
+	msg, err := newMessage(c.id, network.DKGDealType, c.operatorSK, &payload)
+	if err != nil {
+		return nil, err
+	}
+	c.state = StateDealt
+	return msg, nil
+}
+
+// ProcessDeal verifies the given deal and stores the share of this operator.
+// Once the deals of all operators were processed, the ceremony is completed.
+func (c *Ceremony) ProcessDeal(msg *network.DKGMessage) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.state == StateCompleted || c.state == StateFailed {
+		return errors.Errorf("could not process deal in state %s", c.state)
+	}
+	if msg.Type != network.DKGDealType {
+		return errors.Errorf("unexpected message type %s", msg.Type)
+	}
+	if msg.CeremonyID != c.id {
+		return errors.New("wrong ceremony id")
+	}
+	payload := dealPayload{}
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		return errors.Wrap(err, "could not unmarshal deal")
+	}
+	dealer, ok := c.operators[payload.DealerID]
+	if !ok || dealer.PublicKey != msg.Sender {
+		return errors.Errorf("unknown dealer %d", payload.DealerID)
+	}
+	if !c.sameOperators(payload.Operators) {
+		return errors.New("participants of the deal don't match the ceremony")
+	}
+	if err := verifyMessage(msg); err != nil {
+		return errors.Wrap(err, "could not verify deal")
+	}
+	if _, exist := c.shares[payload.DealerID]; exist {
+		return errors.Errorf("deal of dealer %d was already processed", payload.DealerID)
+	}
+	if uint64(len(payload.Commitments)) != c.threshold {
+		return errors.Errorf("invalid number of commitments: %d", len(payload.Commitments))
+	}
+
+	commitments := make([]bls.PublicKey, len(payload.Commitments))
+	for i, raw := range payload.Commitments {
+		if err := commitments[i].Deserialize(raw); err != nil {
+			return errors.Wrap(err, "could not deserialize commitment")
+		}
+	}
+	encrypted, ok := payload.EncryptedShares[c.nodeID]
+	if !ok {
+		return errors.New("missing share")
+	}
+	decrypted, err := rsaencryption.DecodeKey(c.operatorSK, encrypted)
+	if err != nil {
+		return errors.Wrap(err, "could not decrypt share")
+	}
+	share := &bls.SecretKey{}
+	if err := share.SetHexString(decrypted); err != nil {
+		return errors.Wrap(err, "could not deserialize share")
+	}
+	// feldman verification: the public key of the share must match the evaluation of the commitments
+	expected, err := evaluatePublic(commitments, c.nodeID)
+	if err != nil {
+		return err
+	}
+	if !share.GetPublicKey().IsEqual(expected) {
+		return errors.Errorf("share of dealer %d does not match its commitments", payload.DealerID)
+	}
+
+	c.shares[payload.DealerID] = share
+	c.commitments[payload.DealerID] = commitments
+
+	if len(c.shares) == len(c.operators) {
+		if err := c.complete(); err != nil {
+			c.state = StateFailed
+			return errors.Wrap(err, "could not complete ceremony")
+		}
+		c.state = StateCompleted
+	}
+	return nil
+}
+
+// sameOperators returns true if the given operators are the participants of the ceremony
+func (c *Ceremony) sameOperators(operators []Operator) bool {
+	if len(operators) != len(c.operators) {
+		return false
+	}
+	for _, op := range operators {
+		if existing, ok := c.operators[op.ID]; !ok || existing.PublicKey != op.PublicKey {
+			return false
+		}
+	}
+	return true
+}
+
+// Fail marks the ceremony as failed
+func (c *Ceremony) Fail() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.state != StateCompleted {
+		c.state = StateFailed
+	}
+}
+
+// Result returns the result of a completed ceremony
+func (c *Ceremony) Result() (*Result, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.state != StateCompleted {
+		return nil, errors.Errorf("ceremony is not completed, current state %s", c.state)
+	}
+	return c.result, nil
+}
+
+// complete sums up the received shares and commitments, should be called after lock was acquired
+func (c *Ceremony) complete() error {
+	shareKey := &bls.SecretKey{}
+	validatorPk := &bls.PublicKey{}
+	for dealerID, share := range c.shares {
+		shareKey.Add(share)
+		validatorPk.Add(&c.commitments[dealerID][0])
+	}
+	committee := make(map[uint64]*proto.Node)
+	for id := range c.operators {
+		pubShare := &bls.PublicKey{}
+		for _, commitments := range c.commitments {
+			pk, err := evaluatePublic(commitments, id)
+			if err != nil {
+				return err
+			}
+			pubShare.Add(pk)
+		}
+		committee[id] = &proto.Node{
+			IbftId: id,
+			Pk:     pubShare.Serialize(),
+		}
+	}
+	c.result = &Result{
+		ValidatorPubKey: validatorPk,
+		NodeID:          c.nodeID,
+		ShareKey:        shareKey,
+		Committee:       committee,
+	}
+	return nil
+}
+
+// evaluate returns the share of the given id from the polynomial
+func evaluate(msk []bls.SecretKey, id uint64) (*bls.SecretKey, error) {
+	blsID, err := toBlsID(id)
+	if err != nil {
+		return nil, err
+	}
+	sk := &bls.SecretKey{}
+	if err := sk.Set(msk, blsID); err != nil {
+		return nil, errors.Wrap(err, "could not evaluate share")
+	}
+	return sk, nil
+}
+
+// evaluatePublic returns the public share of the given id from the commitments
+func evaluatePublic(mpk []bls.PublicKey, id uint64) (*bls.PublicKey, error) {
+	blsID, err := toBlsID(id)
+	if err != nil {
+		return nil, err
+	}
+	pk := &bls.PublicKey{}
+	if err := pk.Set(mpk, blsID); err != nil {
+		return nil, errors.Wrap(err, "could not evaluate public share")
+	}
+	return pk, nil
+}
+
+func toBlsID(id uint64) (*bls.ID, error) {
+	blsID := &bls.ID{}
+	if err := blsID.SetDecString(fmt.Sprintf("%d", id)); err != nil {
+		return nil, errors.Wrap(err, "could not create bls id")
+	}
+	return blsID, nil
+}
@@ -0,0 +1,153 @@
+package dkg
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func generateOperators(t *testing.T, n int) ([]Operator, []*rsa.PrivateKey) {
+	var operators []Operator
+	var sks []*rsa.PrivateKey
+	for i := 1; i <= n; i++ {
+		_, skPem, err := rsaencryption.GenerateKeys()
+		require.NoError(t, err)
+		sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+		require.NoError(t, err)
+		pk, err := rsaencryption.ExtractPublicKey(sk)
+		require.NoError(t, err)
+		operators = append(operators, Operator{ID: uint64(i), PublicKey: pk})
+		sks = append(sks, sk)
+	}
+	return operators, sks
+}
+
+func generateCeremonies(t *testing.T, operators []Operator, sks []*rsa.PrivateKey, thresholdSize uint64) []*Ceremony {
+	var ceremonies []*Ceremony
+	for _, sk := range sks {
+		c, err := NewCeremony(CeremonyOptions{
+			ID:                 "test-ceremony",
+			Operators:          operators,
+			Threshold:          thresholdSize,
+			OperatorPrivateKey: sk,
+		})
+		require.NoError(t, err)
+		ceremonies = append(ceremonies, c)
+	}
+	return ceremonies
+}
+
+func TestCeremony(t *testing.T) {
+	threshold.Init()
+	operators, sks := generateOperators(t, 4)
+	ceremonies := generateCeremonies(t, operators, sks, 3)
+
+	var deals []*network.DKGMessage
+	for _, c := range ceremonies {
+		deal, err := c.Deal()
+		require.NoError(t, err)
+		require.Equal(t, StateDealt, c.State())
+		deals = append(deals, deal)
+	}
+	for _, c := range ceremonies {
+		for _, deal := range deals {
+			require.NoError(t, c.ProcessDeal(deal))
+		}
+		require.Equal(t, StateCompleted, c.State())
+	}
+
+	var results []*Result
+	for _, c := range ceremonies {
+		res, err := c.Result()
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+	// all operators derived the same validator key and committee
+	for _, res := range results {
+		require.True(t, results[0].ValidatorPubKey.IsEqual(res.ValidatorPubKey))
+		require.Equal(t, res.ShareKey.GetPublicKey().Serialize(), results[0].Committee[res.NodeID].Pk)
+	}
+
+	// threshold of the shares creates a valid validator signature
+	msg := []byte("dkg test message")
+	var sigs []bls.Sign
+	var ids []bls.ID
+	for _, res := range results[:3] {
+		sigs = append(sigs, *res.ShareKey.SignByte(msg))
+		id, err := toBlsID(res.NodeID)
+		require.NoError(t, err)
+		ids = append(ids, *id)
+	}
+	sig := &bls.Sign{}
+	require.NoError(t, sig.Recover(sigs, ids))
+	require.True(t, sig.VerifyByte(results[0].ValidatorPubKey, msg))
+}
+
+func TestCeremony_InvalidDeal(t *testing.T) {
+	threshold.Init()
+	operators, sks := generateOperators(t, 4)
+	ceremonies := generateCeremonies(t, operators, sks, 3)
+
+	deal, err := ceremonies[0].Deal()
+	require.NoError(t, err)
+
+	t.Run("duplicated deal", func(t *testing.T) {
+		require.NoError(t, ceremonies[1].ProcessDeal(deal))
+		require.EqualError(t, ceremonies[1].ProcessDeal(deal), "deal of dealer 1 was already processed")
+	})
+
+	t.Run("tampered deal", func(t *testing.T) {
+		tampered := *deal
+		tampered.Data = append([]byte{}, deal.Data...)
+		tampered.Data[len(tampered.Data)-2] = '9'
+		require.Error(t, ceremonies[2].ProcessDeal(&tampered))
+	})
+
+	t.Run("unknown sender", func(t *testing.T) {
+		_, others := generateOperators(t, 1)
+		msg, err := newMessage("test-ceremony", network.DKGDealType, others[0], &dealPayload{DealerID: 1})
+		require.NoError(t, err)
+		require.EqualError(t, ceremonies[2].ProcessDeal(msg), "unknown dealer 1")
+	})
+
+	t.Run("other participants", func(t *testing.T) {
+		msg, err := newMessage("test-ceremony", network.DKGDealType, sks[0], &dealPayload{DealerID: 1, Operators: operators[:3]})
+		require.NoError(t, err)
+		require.EqualError(t, ceremonies[3].ProcessDeal(msg), "participants of the deal don't match the ceremony")
+	})
+
+	t.Run("result before completion", func(t *testing.T) {
+		_, err := ceremonies[3].Result()
+		require.EqualError(t, err, fmt.Sprintf("ceremony is not completed, current state %s", StateInit))
+	})
+}
+
+func TestNewCeremony(t *testing.T) {
+	operators, sks := generateOperators(t, 4)
+
+	_, err := NewCeremony(CeremonyOptions{ID: "x", Operators: operators[:3], Threshold: 2, OperatorPrivateKey: sks[0]})
+	require.EqualError(t, err, "at least 4 operators are required, got 3")
+	_, err = NewCeremony(CeremonyOptions{ID: "x", Operators: operators, Threshold: 5, OperatorPrivateKey: sks[0]})
+	require.EqualError(t, err, "invalid threshold 5 for 4 operators")
+	// a threshold below 2f+1 would allow a minority of the operators to reconstruct the key
+	_, err = NewCeremony(CeremonyOptions{ID: "x", Operators: operators, Threshold: 1, OperatorPrivateKey: sks[0]})
+	require.EqualError(t, err, "invalid threshold 1 for 4 operators")
+	_, err = NewCeremony(CeremonyOptions{ID: "x", Operators: operators, Threshold: 2, OperatorPrivateKey: sks[0]})
+	require.EqualError(t, err, "invalid threshold 2 for 4 operators")
+
+	_, others := generateOperators(t, 1)
+	_, err = NewCeremony(CeremonyOptions{ID: "x", Operators: operators, Threshold: 3, OperatorPrivateKey: others[0]})
+	require.EqualError(t, err, "operator is not a participant of the ceremony")
+}
+
+func TestMinThreshold(t *testing.T) {
+	require.EqualValues(t, 3, MinThreshold(4))
+	require.EqualValues(t, 3, MinThreshold(5))
+	require.EqualValues(t, 5, MinThreshold(7))
+	require.EqualValues(t, 9, MinThreshold(13))
+}
@@ -0,0 +1,524 @@
+package dkg
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	// maxPendingDealsPerCeremony is the max number of deals that are kept for a ceremony that wasn't initiated yet
+	maxPendingDealsPerCeremony = 16
+	// maxPendingDeals is the max number of deals that are kept for all the ceremonies that weren't initiated yet
+	maxPendingDeals = 256
+)
+
+// OnShare is called once a ceremony was completed and the resulting share was saved
+type OnShare func(share *validatorstorage.Share)
+
+// ControllerOptions holds the needed dependencies for the dkg controller
+type ControllerOptions struct {
+	Context                    context.Context
+	Logger                     *zap.Logger
+	DB                         basedb.IDb
+	Network                    network.DKG
+	KeyManager                 beacon.KeyManager
+	ShareEncryptionKeyProvider eth1.ShareEncryptionKeyProvider
+	CeremonyTimeout            time.Duration `yaml:"CeremonyTimeout" env:"DKG_CEREMONY_TIMEOUT" env-default:"2m" env-description:"Timeout for a dkg ceremony to complete"`
+	Initiator                  string        `yaml:"Initiator" env:"DKG_INITIATOR" env-description:"Public key of the operator that is allowed to initiate dkg ceremonies"`
+	OnShare                    OnShare
+}
+
+// Controller manages the dkg ceremonies of this operator
+type Controller interface {
+	// Start starts to listen to dkg messages
+	Start() error
+	// StartCeremony initiates a new ceremony with the given operators, only the configured initiator can start ceremonies
+	StartCeremony(id string, operators []Operator, threshold uint64) error
+	// GetCeremony returns the record of the given ceremony
+	GetCeremony(id string) (*CeremonyRecord, bool, error)
+}
+
+type controller struct {
+	ctx          context.Context
+	logger       *zap.Logger
+	network      network.DKG
+	storage      Storage
+	shareStorage validatorstorage.ICollection
+	keyManager   beacon.KeyManager
+	keyProvider  eth1.ShareEncryptionKeyProvider
+	timeout      time.Duration
+	initiator    string
+	onShare      OnShare
+
+	lock       sync.Mutex
+	ceremonies map[string]*Ceremony
+	// pending holds deals that arrived before the init message of the ceremony,
+	// they are dropped once the ceremony timeout has passed
+	pending      map[string]*pendingDeals
+	pendingCount int
+}
+
+// pendingDeals are the deals of a ceremony that wasn't initiated yet, by sender
+type pendingDeals struct {
+	created time.Time
+	deals   map[string]*network.DKGMessage
+}
+
+// NewController creates a new dkg controller
+func NewController(opts ControllerOptions) Controller {
+	timeout := opts.CeremonyTimeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+	return &controller{
+		ctx:     opts.Context,
+		logger:  opts.Logger.With(zap.String("component", "dkg/controller")),
+		network: opts.Network,
+		storage: NewStorage(opts.DB, opts.Logger),
+		shareStorage: validatorstorage.NewCollection(validatorstorage.CollectionOptions{
+			DB:     opts.DB,
+			Logger: opts.Logger,
+		}),
+		keyManager:  opts.KeyManager,
+		keyProvider: opts.ShareEncryptionKeyProvider,
+		timeout:     timeout,
+		initiator:   opts.Initiator,
+		onShare:     opts.OnShare,
+		lock:        sync.Mutex{},
+		ceremonies:  make(map[string]*Ceremony),
+		pending:     make(map[string]*pendingDeals),
+	}
+}
+
+// Start starts to listen to dkg messages
+func (c *controller) Start() error {
+	cn := c.network.ReceivedDKGMsgChan()
+	if err := c.network.SubscribeToDKGTopic(); err != nil {
+		return errors.Wrap(err, "could not subscribe to dkg topic")
+	}
+	go func() {
+		for msg := range cn {
+			if err := c.handleMessage(msg); err != nil {
+				c.logger.Warn("could not handle dkg message", zap.String("ceremonyID", msg.CeremonyID),
+					zap.String("type", msg.Type.String()), zap.Error(err))
+			}
+		}
+	}()
+	c.logger.Info("dkg controller started")
+	return nil
+}
+
+// StartCeremony initiates a new ceremony with the given operators, only the configured initiator can start ceremonies
+func (c *controller) StartCeremony(id string, operators []Operator, threshold uint64) error {
+	if len(id) == 0 {
+		return errors.New("ceremony id is required")
+	}
+	sk, err := c.operatorKey()
+	if err != nil {
+		return err
+	}
+	self, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		return errors.Wrap(err, "could not extract operator public key")
+	}
+	if self != c.initiator {
+		return errors.New("this operator is not the dkg initiator")
+	}
+	if err := validateParams(operators, threshold); err != nil {
+		return err
+	}
+	if _, found, err := c.storage.GetCeremony(id); err != nil {
+		return errors.Wrap(err, "could not get ceremony")
+	} else if found {
+		return errors.New("ceremony already exist")
+	}
+	msg, err := newMessage(id, network.DKGInitType, sk, &initPayload{
+		Operators: operators,
+		Threshold: threshold,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not create init message")
+	}
+	if err := c.network.BroadcastDKG(msg); err != nil {
+		return errors.Wrap(err, "could not broadcast init message")
+	}
+	return c.handleInit(msg, sk)
+}
+
+// GetCeremony returns the record of the given ceremony
+func (c *controller) GetCeremony(id string) (*CeremonyRecord, bool, error) {
+	return c.storage.GetCeremony(id)
+}
+
+func (c *controller) handleMessage(msg *network.DKGMessage) error {
+	if msg == nil {
+		return errors.New("nil message")
+	}
+	sk, err := c.operatorKey()
+	if err != nil {
+		return err
+	}
+	self, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		return errors.Wrap(err, "could not extract operator public key")
+	}
+	if msg.Sender == self {
+		// own messages are processed upon creation
+		return nil
+	}
+	switch msg.Type {
+	case network.DKGInitType:
+		return c.handleInit(msg, sk)
+	case network.DKGDealType:
+		return c.handleDeal(msg, self)
+	case network.DKGCompleteType:
+		return c.handleComplete(msg)
+	default:
+		return errors.Errorf("unknown message type %d", msg.Type)
+	}
+}
+
+// handleInit creates the ceremony (if this operator participates) and broadcasts its deal.
+// init messages are accepted only from the configured initiator, and only for new ceremony ids
+func (c *controller) handleInit(msg *network.DKGMessage, sk *rsa.PrivateKey) error {
+	if err := verifyMessage(msg); err != nil {
+		return errors.Wrap(err, "could not verify init message")
+	}
+	if len(c.initiator) == 0 || msg.Sender != c.initiator {
+		return errors.New("init message was not sent by the initiator")
+	}
+	payload := initPayload{}
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		return errors.Wrap(err, "could not unmarshal init message")
+	}
+	if !isParticipant(payload.Operators, msg.Sender) {
+		return errors.New("init message was not sent by a participant")
+	}
+	self, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		return errors.Wrap(err, "could not extract operator public key")
+	}
+	if !isParticipant(payload.Operators, self) {
+		// not part of this ceremony
+		return nil
+	}
+
+	c.lock.Lock()
+	if _, exist := c.ceremonies[msg.CeremonyID]; exist {
+		c.lock.Unlock()
+		return errors.New("ceremony already exist")
+	}
+	// ceremonies are removed from memory once done, the record prevents replayed init messages
+	if _, found, err := c.storage.GetCeremony(msg.CeremonyID); err != nil {
+		c.lock.Unlock()
+		return errors.Wrap(err, "could not get ceremony")
+	} else if found {
+		c.lock.Unlock()
+		return errors.New("ceremony already exist")
+	}
+	ceremony, err := NewCeremony(CeremonyOptions{
+		ID:                 msg.CeremonyID,
+		Operators:          payload.Operators,
+		Threshold:          payload.Threshold,
+		OperatorPrivateKey: sk,
+	})
+	if err != nil {
+		c.lock.Unlock()
+		return errors.Wrap(err, "could not create ceremony")
+	}
+	c.ceremonies[msg.CeremonyID] = ceremony
+	pending := c.takePendingDeals(msg.CeremonyID)
+	c.lock.Unlock()
+
+	logger := c.logger.With(zap.String("ceremonyID", msg.CeremonyID), zap.Uint64("nodeID", ceremony.NodeID()))
+	logger.Info("starting dkg ceremony", zap.Int("operators", len(payload.Operators)),
+		zap.Uint64("threshold", payload.Threshold))
+	if err := c.storage.SaveCeremony(&CeremonyRecord{
+		ID:        msg.CeremonyID,
+		Operators: payload.Operators,
+		Threshold: payload.Threshold,
+		State:     StateInit,
+	}); err != nil {
+		return errors.Wrap(err, "could not save ceremony")
+	}
+	go c.watchTimeout(ceremony)
+
+	deal, err := ceremony.Deal()
+	if err != nil {
+		c.onFailure(ceremony, err)
+		return errors.Wrap(err, "could not create deal")
+	}
+	if err := c.network.BroadcastDKG(deal); err != nil {
+		c.onFailure(ceremony, err)
+		return errors.Wrap(err, "could not broadcast deal")
+	}
+	c.updateState(ceremony.ID(), StateDealt, "")
+
+	// processing own deal and the deals that arrived earlier
+	for _, d := range append([]*network.DKGMessage{deal}, pending...) {
+		if err := c.processDeal(ceremony, d); err != nil {
+			logger.Warn("could not process deal", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// handleDeal processes the given deal, or keep it for later in case the ceremony was not created yet
+func (c *controller) handleDeal(msg *network.DKGMessage, self string) error {
+	c.lock.Lock()
+	ceremony, exist := c.ceremonies[msg.CeremonyID]
+	c.lock.Unlock()
+	if exist {
+		return c.processDeal(ceremony, msg)
+	}
+	if err := verifyPendingDeal(msg, self); err != nil {
+		return errors.Wrap(err, "could not keep deal")
+	}
+
+	c.lock.Lock()
+	// the ceremony might have been created in the meanwhile
+	ceremony, exist = c.ceremonies[msg.CeremonyID]
+	if !exist {
+		err := c.addPendingDeal(msg, time.Now())
+		c.lock.Unlock()
+		return err
+	}
+	c.lock.Unlock()
+
+	return c.processDeal(ceremony, msg)
+}
+
+// addPendingDeal keeps the given deal until the init message arrives, should be called after lock was acquired
+func (c *controller) addPendingDeal(msg *network.DKGMessage, now time.Time) error {
+	for id, pd := range c.pending {
+		if now.Sub(pd.created) > c.timeout {
+			c.pendingCount -= len(pd.deals)
+			delete(c.pending, id)
+		}
+	}
+	pd, exist := c.pending[msg.CeremonyID]
+	if !exist {
+		pd = &pendingDeals{created: now, deals: make(map[string]*network.DKGMessage)}
+	}
+	if _, exist := pd.deals[msg.Sender]; exist {
+		return errors.New("a deal of the sender is already pending")
+	}
+	if len(pd.deals) >= maxPendingDealsPerCeremony {
+		return errors.New("too many pending deals for the ceremony")
+	}
+	if c.pendingCount >= maxPendingDeals {
+		return errors.New("too many pending deals")
+	}
+	pd.deals[msg.Sender] = msg
+	c.pending[msg.CeremonyID] = pd
+	c.pendingCount++
+	return nil
+}
+
+// takePendingDeals removes and returns the pending deals of the given ceremony, should be called after lock was acquired
+func (c *controller) takePendingDeals(id string) []*network.DKGMessage {
+	pd, exist := c.pending[id]
+	if !exist {
+		return nil
+	}
+	delete(c.pending, id)
+	c.pendingCount -= len(pd.deals)
+	deals := make([]*network.DKGMessage, 0, len(pd.deals))
+	for _, d := range pd.deals {
+		deals = append(deals, d)
+	}
+	return deals
+}
+
+func (c *controller) processDeal(ceremony *Ceremony, msg *network.DKGMessage) error {
+	if err := ceremony.ProcessDeal(msg); err != nil {
+		if ceremony.State() == StateFailed {
+			c.onFailure(ceremony, err)
+		}
+		return err
+	}
+	if ceremony.State() == StateCompleted {
+		return c.onCompleted(ceremony)
+	}
+	return nil
+}
+
+// handleComplete marks the sender as completed in the ceremony record
+func (c *controller) handleComplete(msg *network.DKGMessage) error {
+	if err := verifyMessage(msg); err != nil {
+		return errors.Wrap(err, "could not verify complete message")
+	}
+	payload := completePayload{}
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		return errors.Wrap(err, "could not unmarshal complete message")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	record, found, err := c.storage.GetCeremony(msg.CeremonyID)
+	if err != nil || !found {
+		return errors.Wrap(err, "could not find ceremony")
+	}
+	if !isParticipant(record.Operators, msg.Sender) {
+		return errors.New("complete message was not sent by a participant")
+	}
+	for _, id := range record.Completed {
+		if id == payload.NodeID {
+			return nil
+		}
+	}
+	record.Completed = append(record.Completed, payload.NodeID)
+	return c.storage.SaveCeremony(record)
+}
+
+// onCompleted saves the resulting share and notifies the other participants
+func (c *controller) onCompleted(ceremony *Ceremony) error {
+	res, err := ceremony.Result()
+	if err != nil {
+		return err
+	}
+	logger := c.logger.With(zap.String("ceremonyID", ceremony.ID()),
+		zap.String("pubKey", res.ValidatorPubKey.SerializeToHexStr()))
+
+	if err := c.saveShare(res); err != nil {
+		c.onFailure(ceremony, err)
+		return err
+	}
+	logger.Info("dkg ceremony completed, share was saved")
+
+	c.lock.Lock()
+	record, found, err := c.storage.GetCeremony(ceremony.ID())
+	if err == nil && found {
+		record.State = StateCompleted
+		record.ValidatorPubKey = res.ValidatorPubKey.SerializeToHexStr()
+		record.Completed = append(record.Completed, res.NodeID)
+		err = c.storage.SaveCeremony(record)
+	}
+	c.lock.Unlock()
+	if err != nil {
+		logger.Warn("could not update ceremony record", zap.Error(err))
+	}
+
+	sk, err := c.operatorKey()
+	if err != nil {
+		return err
+	}
+	msg, err := newMessage(ceremony.ID(), network.DKGCompleteType, sk, &completePayload{
+		NodeID:          res.NodeID,
+		ValidatorPubKey: res.ValidatorPubKey.Serialize(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not create complete message")
+	}
+	if err := c.network.BroadcastDKG(msg); err != nil {
+		return errors.Wrap(err, "could not broadcast complete message")
+	}
+
+	if c.onShare != nil {
+		c.onShare(res.ToShare())
+	}
+	return nil
+}
+
+// saveShare saves the share key in the key manager and the share itself in validators storage
+func (c *controller) saveShare(res *Result) error {
+	if err := c.keyManager.AddShare(res.ShareKey); err != nil {
+		return errors.Wrap(err, "could not save share key")
+	}
+	if err := c.shareStorage.SaveValidatorShare(res.ToShare()); err != nil {
+		return errors.Wrap(err, "could not save share")
+	}
+	return nil
+}
+
+// watchTimeout fails the given ceremony if it wasn't completed in time
+func (c *controller) watchTimeout(ceremony *Ceremony) {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case <-c.ctx.Done():
+	case <-timer.C:
+		if ceremony.State() != StateCompleted {
+			c.onFailure(ceremony, errors.New("ceremony timed out"))
+		}
+	}
+	c.lock.Lock()
+	delete(c.ceremonies, ceremony.ID())
+	c.lock.Unlock()
+}
+
+func (c *controller) onFailure(ceremony *Ceremony, err error) {
+	ceremony.Fail()
+	c.logger.Warn("dkg ceremony failed", zap.String("ceremonyID", ceremony.ID()), zap.Error(err))
+	c.updateState(ceremony.ID(), StateFailed, err.Error())
+}
+
+func (c *controller) updateState(id string, state State, errMsg string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	record, found, err := c.storage.GetCeremony(id)
+	if err != nil || !found {
+		c.logger.Warn("could not find ceremony record", zap.String("ceremonyID", id), zap.Error(err))
+		return
+	}
+	if record.State == StateCompleted {
+		return
+	}
+	record.State = state
+	record.Error = errMsg
+	if err := c.storage.SaveCeremony(record); err != nil {
+		c.logger.Warn("could not save ceremony record", zap.String("ceremonyID", id), zap.Error(err))
+	}
+}
+
+func (c *controller) operatorKey() (*rsa.PrivateKey, error) {
+	sk, found, err := c.keyProvider()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get operator private key")
+	}
+	if !found || sk == nil {
+		return nil, errors.New("could not find operator private key")
+	}
+	return sk, nil
+}
+
+// verifyPendingDeal verifies a deal of a ceremony that wasn't initiated yet, the sender and this operator
+// must be participants as declared in the signed deal. the declared participants are checked against
+// the init message once it arrives (see Ceremony.ProcessDeal)
+func verifyPendingDeal(msg *network.DKGMessage, self string) error {
+	payload := dealPayload{}
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		return errors.Wrap(err, "could not unmarshal deal")
+	}
+	if !isParticipant(payload.Operators, msg.Sender) {
+		return errors.New("deal was not sent by a participant")
+	}
+	if !isParticipant(payload.Operators, self) {
+		return errors.New("not a participant of the ceremony")
+	}
+	if err := verifyMessage(msg); err != nil {
+		return errors.Wrap(err, "could not verify deal")
+	}
+	return nil
+}
+
+func isParticipant(operators []Operator, pk string) bool {
+	for _, op := range operators {
+		if op.PublicKey == pk {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package dkg
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockDKGNetwork delivers broadcasted messages to all the other networks of the same group
+type mockDKGNetwork struct {
+	lock  *sync.Mutex
+	group *[]*mockDKGNetwork
+	ch    chan *network.DKGMessage
+	sent  []*network.DKGMessage
+}
+
+func newMockDKGNetworks(n int) []*mockDKGNetwork {
+	group := make([]*mockDKGNetwork, 0, n)
+	lock := &sync.Mutex{}
+	for i := 0; i < n; i++ {
+		group = append(group, &mockDKGNetwork{lock: lock, group: &group, ch: make(chan *network.DKGMessage, 128)})
+	}
+	return group
+}
+
+func (n *mockDKGNetwork) SubscribeToDKGTopic() error {
+	return nil
+}
+
+func (n *mockDKGNetwork) BroadcastDKG(msg *network.DKGMessage) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.sent = append(n.sent, msg)
+	for _, other := range *n.group {
+		if other != n {
+			other.ch <- msg
+		}
+	}
+	return nil
+}
+
+func (n *mockDKGNetwork) ReceivedDKGMsgChan() <-chan *network.DKGMessage {
+	return n.ch
+}
+
+func TestController_Ceremony(t *testing.T) {
+	threshold.Init()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	operators, sks := generateOperators(t, 5)
+	participants := operators[:4]
+	nets := newMockDKGNetworks(len(participants))
+	newCtrl := func(sk *rsa.PrivateKey, net network.DKG) *controller {
+		db, err := storage.GetStorageFactory(basedb.Options{
+			Type:   "badger-memory",
+			Logger: zap.L(),
+		})
+		require.NoError(t, err)
+		t.Cleanup(db.Close)
+		return NewController(ControllerOptions{
+			Context:    ctx,
+			Logger:     zap.L(),
+			DB:         db,
+			Network:    net,
+			KeyManager: beacon.NewMockBeacon(nil, nil),
+			ShareEncryptionKeyProvider: func() (*rsa.PrivateKey, bool, error) {
+				return sk, true, nil
+			},
+			CeremonyTimeout: time.Minute,
+			Initiator:       operators[0].PublicKey,
+		}).(*controller)
+	}
+	var ctrls []*controller
+	for i := range participants {
+		ctrl := newCtrl(sks[i], nets[i])
+		require.NoError(t, ctrl.Start())
+		ctrls = append(ctrls, ctrl)
+	}
+
+	require.EqualError(t, ctrls[1].StartCeremony("c1", participants, 3), "this operator is not the dkg initiator")
+	require.EqualError(t, ctrls[0].StartCeremony("c1", participants, 1), "invalid threshold 1 for 4 operators")
+	require.NoError(t, ctrls[0].StartCeremony("c1", participants, 3))
+	// waiting for the complete messages of all the participants, so no messages are in flight
+	require.Eventually(t, func() bool {
+		for _, ctrl := range ctrls {
+			record, found, err := ctrl.GetCeremony("c1")
+			if err != nil || !found || record.State != StateCompleted || len(record.Completed) != len(participants) {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 50*time.Millisecond)
+	record, _, err := ctrls[0].GetCeremony("c1")
+	require.NoError(t, err)
+	for _, ctrl := range ctrls[1:] {
+		other, _, err := ctrl.GetCeremony("c1")
+		require.NoError(t, err)
+		require.Equal(t, record.ValidatorPubKey, other.ValidatorPubKey)
+	}
+
+	// replayed init messages are rejected, also after the ceremony was removed from memory
+	require.EqualError(t, ctrls[0].StartCeremony("c1", participants, 3), "ceremony already exist")
+	init := nets[0].sent[0]
+	require.Equal(t, network.DKGInitType, init.Type)
+	ctrls[1].lock.Lock()
+	delete(ctrls[1].ceremonies, "c1")
+	ctrls[1].lock.Unlock()
+	require.EqualError(t, ctrls[1].handleMessage(init), "ceremony already exist")
+
+	// init messages are accepted only from the initiator
+	msg, err := newMessage("c3", network.DKGInitType, sks[1], &initPayload{Operators: participants, Threshold: 3})
+	require.NoError(t, err)
+	require.EqualError(t, ctrls[2].handleMessage(msg), "init message was not sent by the initiator")
+
+	// an operator that doesn't participate ignores the ceremony
+	other := newCtrl(sks[4], newMockDKGNetworks(1)[0])
+	require.NoError(t, other.handleMessage(init))
+	_, found, err := other.GetCeremony("c1")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestController_PendingDeals(t *testing.T) {
+	operators, sks := generateOperators(t, 5)
+	ceremonies := generateCeremonies(t, operators[:4], sks[:4], 3)
+	self, err := rsaencryption.ExtractPublicKey(sks[0])
+	require.NoError(t, err)
+	c := &controller{
+		logger:     zap.L(),
+		timeout:    time.Minute,
+		ceremonies: make(map[string]*Ceremony),
+		pending:    make(map[string]*pendingDeals),
+	}
+
+	deal, err := ceremonies[1].Deal()
+	require.NoError(t, err)
+	require.NoError(t, c.handleDeal(deal, self))
+	require.Equal(t, 1, c.pendingCount)
+	require.EqualError(t, c.handleDeal(deal, self), "a deal of the sender is already pending")
+
+	// this operator is not a participant
+	others := generateCeremonies(t, operators[1:], sks[1:], 3)
+	deal, err = others[1].Deal()
+	require.NoError(t, err)
+	require.EqualError(t, c.handleDeal(deal, self), "could not keep deal: not a participant of the ceremony")
+	// invalid signature
+	deal, err = ceremonies[2].Deal()
+	require.NoError(t, err)
+	deal.Signature[0]++
+	require.Error(t, c.handleDeal(deal, self))
+	require.Equal(t, 1, c.pendingCount)
+
+	deals := c.takePendingDeals("test-ceremony")
+	require.Len(t, deals, 1)
+	require.Equal(t, 0, c.pendingCount)
+}
+
+func TestController_PendingDealsLimits(t *testing.T) {
+	c := &controller{
+		logger:     zap.L(),
+		timeout:    time.Minute,
+		ceremonies: make(map[string]*Ceremony),
+		pending:    make(map[string]*pendingDeals),
+	}
+	now := time.Now()
+	deal := func(ceremonyID string, sender int) *network.DKGMessage {
+		return &network.DKGMessage{CeremonyID: ceremonyID, Type: network.DKGDealType, Sender: fmt.Sprintf("%d", sender)}
+	}
+	for i := 0; i < maxPendingDealsPerCeremony; i++ {
+		require.NoError(t, c.addPendingDeal(deal("a", i), now))
+	}
+	require.EqualError(t, c.addPendingDeal(deal("a", maxPendingDealsPerCeremony), now),
+		"too many pending deals for the ceremony")
+
+	for i := maxPendingDealsPerCeremony; i < maxPendingDeals; i++ {
+		require.NoError(t, c.addPendingDeal(deal(fmt.Sprintf("c%d", i), i), now))
+	}
+	require.EqualError(t, c.addPendingDeal(deal("b", 0), now), "too many pending deals")
+
+	// pending deals are dropped after the ceremony timeout
+	require.NoError(t, c.addPendingDeal(deal("b", 0), now.Add(c.timeout+time.Second)))
+	require.Equal(t, 1, c.pendingCount)
+	require.Len(t, c.pending, 1)
+}
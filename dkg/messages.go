@@ -0,0 +1,99 @@
+package dkg
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/pkg/errors"
+)
+
+// Operator is a participant in a dkg ceremony
+type Operator struct {
+	// ID is the node id of the operator in the resulting committee (starts from 1)
+	ID uint64 `json:"id"`
+	// PublicKey is the operator public key (base64 encoded pem)
+	PublicKey string `json:"publicKey"`
+}
+
+// initPayload is the payload of DKGInitType messages
+type initPayload struct {
+	Operators []Operator `json:"operators"`
+	Threshold uint64     `json:"threshold"`
+}
+
+// dealPayload is the payload of DKGDealType messages
+type dealPayload struct {
+	// DealerID is the node id of the dealer
+	DealerID uint64 `json:"dealerId"`
+	// Operators are the participants of the ceremony, they are used to verify the dealer of a deal
+	// that arrived before the init message, and must match the participants of the init message
+	Operators []Operator `json:"operators"`
+	// Commitments are the serialized public keys of the dealer's polynomial coefficients
+	Commitments [][]byte `json:"commitments"`
+	// EncryptedShares maps node id to the share of that node, encrypted with its operator key
+	EncryptedShares map[uint64]string `json:"encryptedShares"`
+}
+
+// completePayload is the payload of DKGCompleteType messages
+type completePayload struct {
+	NodeID          uint64 `json:"nodeId"`
+	ValidatorPubKey []byte `json:"validatorPubKey"`
+}
+
+// newMessage creates a signed dkg message with the given payload
+func newMessage(ceremonyID string, t network.DKGMsgType, sk *rsa.PrivateKey, payload interface{}) (*network.DKGMessage, error) {
+	sender, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not extract operator public key")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal payload")
+	}
+	msg := &network.DKGMessage{
+		CeremonyID: ceremonyID,
+		Type:       t,
+		Sender:     sender,
+		Data:       data,
+	}
+	root, err := signingRoot(msg)
+	if err != nil {
+		return nil, err
+	}
+	msg.Signature, err = rsaencryption.SignData(sk, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign dkg message")
+	}
+	return msg, nil
+}
+
+// verifyMessage verifies the signature of the given message against the sender's operator key
+func verifyMessage(msg *network.DKGMessage) error {
+	if len(msg.Signature) == 0 {
+		return errors.New("missing signature")
+	}
+	pk, err := rsaencryption.ConvertEncodedPemToPublicKey(msg.Sender)
+	if err != nil {
+		return errors.Wrap(err, "could not decode sender public key")
+	}
+	root, err := signingRoot(msg)
+	if err != nil {
+		return err
+	}
+	if err := rsaencryption.VerifySignedData(pk, root, msg.Signature); err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	return nil
+}
+
+// signingRoot returns the bytes that are signed by the sender, i.e. the message w/o signature
+func signingRoot(msg *network.DKGMessage) ([]byte, error) {
+	toSign := *msg
+	toSign.Signature = nil
+	data, err := json.Marshal(&toSign)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal dkg message")
+	}
+	return data, nil
+}
@@ -0,0 +1,97 @@
+package dkg
+
+import (
+	"encoding/json"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// CeremonyRecord is the persisted information of a ceremony
+type CeremonyRecord struct {
+	ID        string     `json:"id"`
+	Operators []Operator `json:"operators"`
+	Threshold uint64     `json:"threshold"`
+	State     State      `json:"state"`
+	// ValidatorPubKey is the hex encoded validator public key, available once the ceremony was completed
+	ValidatorPubKey string `json:"validatorPubKey,omitempty"`
+	// Completed holds the node ids of operators that reported completion
+	Completed []uint64 `json:"completed,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Storage is the interface for persisting dkg ceremonies
+type Storage interface {
+	SaveCeremony(record *CeremonyRecord) error
+	GetCeremony(id string) (*CeremonyRecord, bool, error)
+	ListCeremonies() ([]*CeremonyRecord, error)
+}
+
+type dkgStorage struct {
+	db     basedb.IDb
+	logger *zap.Logger
+	prefix []byte
+	lock   sync.RWMutex
+}
+
+// NewStorage creates a new instance of Storage
+func NewStorage(db basedb.IDb, logger *zap.Logger) Storage {
+	return &dkgStorage{
+		db:     db,
+		logger: logger.With(zap.String("component", "dkg/storage")),
+		prefix: []byte("dkg-"),
+		lock:   sync.RWMutex{},
+	}
+}
+
+// SaveCeremony saves the given ceremony record
+func (s *dkgStorage) SaveCeremony(record *CeremonyRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal ceremony record")
+	}
+	return s.db.Set(s.prefix, []byte(record.ID), raw)
+}
+
+// GetCeremony returns the ceremony record of the given id
+func (s *dkgStorage) GetCeremony(id string) (*CeremonyRecord, bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	obj, found, err := s.db.Get(s.prefix, []byte(id))
+	if !found {
+		return nil, found, nil
+	}
+	if err != nil {
+		return nil, found, err
+	}
+	record := &CeremonyRecord{}
+	if err := json.Unmarshal(obj.Value, record); err != nil {
+		return nil, found, errors.Wrap(err, "could not unmarshal ceremony record")
+	}
+	return record, found, nil
+}
+
+// ListCeremonies returns all the known ceremonies
+func (s *dkgStorage) ListCeremonies() ([]*CeremonyRecord, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	objs, err := s.db.GetAllByCollection(s.prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get ceremonies")
+	}
+	var res []*CeremonyRecord
+	for _, obj := range objs {
+		record := &CeremonyRecord{}
+		if err := json.Unmarshal(obj.Value, record); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal ceremony record")
+		}
+		res = append(res, record)
+	}
+	return res, nil
+}
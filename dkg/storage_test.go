@@ -0,0 +1,42 @@
+package dkg
+
+import (
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	s := NewStorage(db, zap.L())
+	record := &CeremonyRecord{
+		ID:        "ceremony-1",
+		Operators: []Operator{{ID: 1, PublicKey: "pk1"}, {ID: 2, PublicKey: "pk2"}},
+		Threshold: 2,
+		State:     StateDealt,
+	}
+	require.NoError(t, s.SaveCeremony(record))
+	require.NoError(t, s.SaveCeremony(&CeremonyRecord{ID: "ceremony-2", State: StateFailed, Error: "timeout"}))
+
+	res, found, err := s.GetCeremony("ceremony-1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, record, res)
+
+	_, found, err = s.GetCeremony("unknown")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	all, err := s.ListCeremonies()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+}
@@ -0,0 +1,311 @@
+//go:build integration
+// +build integration
+
+package e2e
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/proto"
+	fssz "github.com/ferranbt/fastssz"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dutyDelaySlots is the number of slots between the first duties request of an epoch and the first duty,
+	// it leaves time for the iBFT controllers of a new validator to init
+	dutyDelaySlots = 2
+	// dutySlots is the number of consecutive slots with an attestation duty of each validator
+	dutySlots = 2
+)
+
+var (
+	attesterDomainType      = spec.DomainType{0x01, 0x00, 0x00, 0x00}
+	voluntaryExitDomainType = spec.DomainType{0x04, 0x00, 0x00, 0x00}
+)
+
+// simulatedBeacon is an in-memory beacon node that serves duties and attestation data of the registered
+// validators and verifies the attestations that are submitted by the operators
+type simulatedBeacon struct {
+	network core.Network
+
+	lock       sync.Mutex
+	validators map[spec.ValidatorIndex]*v1.Validator
+	// dutySlots holds the slot of the first attestation duty of each validator and epoch, decided on the first request
+	dutySlots map[dutyKey]spec.Slot
+	// firstDutySlots holds the slot of the first duty in each epoch, checkpoints are advanced in every duty slot after it
+	firstDutySlots map[spec.Epoch]spec.Slot
+	// attestations holds the verified attestations that were submitted, by validator public key (hex)
+	attestations map[string][]*spec.Attestation
+	// invalid counts submitted attestations with a signature that doesn't match any validator
+	invalid int
+}
+
+type dutyKey struct {
+	epoch spec.Epoch
+	index spec.ValidatorIndex
+}
+
+func newSimulatedBeacon(network core.Network) *simulatedBeacon {
+	return &simulatedBeacon{
+		network:        network,
+		validators:     map[spec.ValidatorIndex]*v1.Validator{},
+		dutySlots:      map[dutyKey]spec.Slot{},
+		firstDutySlots: map[spec.Epoch]spec.Slot{},
+		attestations:   map[string][]*spec.Attestation{},
+	}
+}
+
+// addValidator makes the given validator known (and active) on the beacon chain
+func (b *simulatedBeacon) addValidator(index spec.ValidatorIndex, pk *bls.PublicKey) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	blsPubKey := spec.BLSPubKey{}
+	copy(blsPubKey[:], pk.Serialize())
+	b.validators[index] = &v1.Validator{
+		Index:   index,
+		Balance: 32000000000,
+		Status:  v1.ValidatorStateActiveOngoing,
+		Validator: &spec.Validator{
+			PublicKey:        blsPubKey,
+			EffectiveBalance: 32000000000,
+		},
+	}
+}
+
+// submittedAttestations returns the verified attestations of the given validator
+func (b *simulatedBeacon) submittedAttestations(pk *bls.PublicKey) []*spec.Attestation {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.attestations[hex.EncodeToString(pk.Serialize())]
+}
+
+// invalidAttestations returns the number of submitted attestations that couldn't be verified
+func (b *simulatedBeacon) invalidAttestations() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.invalid
+}
+
+func (b *simulatedBeacon) ExtendIndexMap(index spec.ValidatorIndex, pubKey spec.BLSPubKey) {}
+
+// GetDuties returns attestation duties of each validator in consecutive slots of the given epoch.
+// the duties are placed shortly after the current epoch is requested, so they are executed w/o waiting a full epoch
+func (b *simulatedBeacon) GetDuties(epoch spec.Epoch, validatorIndices []spec.ValidatorIndex) ([]*beacon.Duty, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var duties []*beacon.Duty
+	for i, index := range validatorIndices {
+		v, ok := b.validators[index]
+		if !ok {
+			continue
+		}
+		first, ok := b.dutySlot(epoch, index)
+		if !ok {
+			continue
+		}
+		for slot := first; slot < first+dutySlots; slot++ {
+			if b.epochAtSlot(slot) != epoch {
+				break
+			}
+			duties = append(duties, &beacon.Duty{
+				Type:                    beacon.RoleTypeAttester,
+				PubKey:                  v.Validator.PublicKey,
+				Slot:                    slot,
+				ValidatorIndex:          index,
+				CommitteeIndex:          spec.CommitteeIndex(i),
+				CommitteeLength:         1,
+				CommitteesAtSlot:        uint64(len(validatorIndices)),
+				ValidatorCommitteeIndex: 0,
+			})
+		}
+	}
+	return duties, nil
+}
+
+// dutySlot returns the slot of the first duty of the given validator in the given epoch,
+// returns false if the epoch is over before the validator was requested. the caller must hold the lock
+func (b *simulatedBeacon) dutySlot(epoch spec.Epoch, index spec.ValidatorIndex) (spec.Slot, bool) {
+	key := dutyKey{epoch: epoch, index: index}
+	if slot, ok := b.dutySlots[key]; ok {
+		return slot, true
+	}
+	slotsPerEpoch := b.network.SlotsPerEpoch()
+	slot := spec.Slot(uint64(epoch) * slotsPerEpoch)
+	if next := spec.Slot(b.network.EstimatedCurrentSlot() + dutyDelaySlots); next > slot {
+		slot = next
+	}
+	if b.epochAtSlot(slot) != epoch {
+		return 0, false
+	}
+	b.dutySlots[key] = slot
+	if first, ok := b.firstDutySlots[epoch]; !ok || slot < first {
+		b.firstDutySlots[epoch] = slot
+	}
+	return slot, true
+}
+
+func (b *simulatedBeacon) epochAtSlot(slot spec.Slot) spec.Epoch {
+	return spec.Epoch(uint64(slot) / b.network.SlotsPerEpoch())
+}
+
+func (b *simulatedBeacon) GetValidatorData(validatorPubKeys []spec.BLSPubKey) (map[spec.ValidatorIndex]*v1.Validator, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	results := map[spec.ValidatorIndex]*v1.Validator{}
+	for _, pk := range validatorPubKeys {
+		for index, v := range b.validators {
+			if v.Validator.PublicKey == pk {
+				results[index] = v
+			}
+		}
+	}
+	return results, nil
+}
+
+// GetAttestationData returns deterministic attestation data, therefore all the operators propose the same value.
+// the target checkpoint is advanced in each duty slot of the epoch, so the slashing protection allows duties
+// in consecutive slots
+func (b *simulatedBeacon) GetAttestationData(ctx context.Context, slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	epoch := b.epochAtSlot(slot)
+	target := epoch
+	if first, ok := b.firstDutySlots[epoch]; ok && slot > first {
+		target += spec.Epoch(slot - first)
+	}
+	source := target
+	if source > 0 {
+		source--
+	}
+	return &spec.AttestationData{
+		Slot:            slot,
+		Index:           committeeIndex,
+		BeaconBlockRoot: simulatedRoot("block", uint64(slot)),
+		Source: &spec.Checkpoint{
+			Epoch: source,
+			Root:  simulatedRoot("checkpoint", uint64(source)),
+		},
+		Target: &spec.Checkpoint{
+			Epoch: target,
+			Root:  simulatedRoot("checkpoint", uint64(target)),
+		},
+	}, nil
+}
+
+// SubmitAttestation verifies the (reconstructed) signature of the given attestation with the public keys
+// of the registered validators and keeps the attestation
+func (b *simulatedBeacon) SubmitAttestation(ctx context.Context, attestation *spec.Attestation) error {
+	domain, err := b.GetDomain(attestation.Data)
+	if err != nil {
+		return err
+	}
+	root, err := b.ComputeSigningRoot(attestation.Data, domain)
+	if err != nil {
+		return err
+	}
+	// bls deserialization passes the bytes to cgo, so they are copied out of the attestation
+	sig := &bls.Sign{}
+	if err := sig.Deserialize(append([]byte{}, attestation.Signature[:]...)); err != nil {
+		return errors.Wrap(err, "could not deserialize signature")
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, v := range b.validators {
+		pk := &bls.PublicKey{}
+		if err := pk.Deserialize(append([]byte{}, v.Validator.PublicKey[:]...)); err != nil {
+			continue
+		}
+		if sig.VerifyByte(pk, append([]byte{}, root[:]...)) {
+			pkHex := hex.EncodeToString(v.Validator.PublicKey[:])
+			b.attestations[pkHex] = append(b.attestations[pkHex], attestation)
+			return nil
+		}
+	}
+	b.invalid++
+	return errors.New("invalid attestation signature")
+}
+
+func (b *simulatedBeacon) SubscribeToCommitteeSubnet(subscription []*v1.BeaconCommitteeSubscription) error {
+	return nil
+}
+
+func (b *simulatedBeacon) SubmitVoluntaryExit(ctx context.Context, exit *spec.SignedVoluntaryExit) error {
+	return nil
+}
+
+func (b *simulatedBeacon) GetSyncState() (*v1.SyncState, error) {
+	return &v1.SyncState{HeadSlot: spec.Slot(b.network.EstimatedCurrentSlot())}, nil
+}
+
+// signing is done by the key manager of each operator, the simulated beacon doesn't hold keys
+
+func (b *simulatedBeacon) AddShare(shareKey *bls.SecretKey) error {
+	return errors.New("simulated beacon doesn't hold keys")
+}
+
+func (b *simulatedBeacon) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte, error) {
+	return nil, errors.New("simulated beacon doesn't hold keys")
+}
+
+func (b *simulatedBeacon) SignAttestation(data *spec.AttestationData, duty *beacon.Duty, pk []byte) (*spec.Attestation, []byte, error) {
+	return nil, nil, errors.New("simulated beacon doesn't hold keys")
+}
+
+func (b *simulatedBeacon) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return nil, nil, errors.New("simulated beacon doesn't hold keys")
+}
+
+func (b *simulatedBeacon) GetDomain(data *spec.AttestationData) ([]byte, error) {
+	return simulatedDomain(attesterDomainType), nil
+}
+
+func (b *simulatedBeacon) GetVoluntaryExitDomain(epoch spec.Epoch) ([]byte, error) {
+	return simulatedDomain(voluntaryExitDomainType), nil
+}
+
+// ComputeSigningRoot computes the signing root as defined in the spec (see goclient)
+func (b *simulatedBeacon) ComputeSigningRoot(object interface{}, domain []byte) ([32]byte, error) {
+	hr, ok := object.(fssz.HashRoot)
+	if !ok {
+		return [32]byte{}, errors.New("object doesn't support hash tree root")
+	}
+	objRoot, err := hr.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	container := &spec.SigningData{ObjectRoot: objRoot}
+	copy(container.Domain[:], domain)
+	return container.HashTreeRoot()
+}
+
+// simulatedDomain returns a domain of the given type, w/o fork data
+func simulatedDomain(domainType spec.DomainType) []byte {
+	domain := make([]byte, 32)
+	copy(domain, domainType[:])
+	return domain
+}
+
+// simulatedRoot returns a deterministic root for the given kind and number
+func simulatedRoot(kind string, n uint64) spec.Root {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, n)
+	return sha256.Sum256(append([]byte(kind), buf...))
+}
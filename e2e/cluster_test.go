@@ -0,0 +1,291 @@
+//go:build integration
+// +build integration
+
+package e2e
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/beacon/goclient/ekm"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/exporter"
+	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/api/adapters/gorilla"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/bloxapp/ssv/operator"
+	v0 "github.com/bloxapp/ssv/operator/forks/v0"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/bloxapp/ssv/validator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	operatorsCount = 4
+	// signersThreshold is the number of shares that are needed to reconstruct a signature (2f+1)
+	signersThreshold = 3
+)
+
+// operatorNode is an operator that runs in the cluster
+type operatorNode struct {
+	id     uint64
+	sk     *rsa.PrivateKey
+	pubKey string
+	db     basedb.IDb
+	eth1   *simulatedEth1
+	ctrl   validator.IController
+	node   operator.Node
+}
+
+// exporterNode is the exporter that runs in the cluster
+type exporterNode struct {
+	db   basedb.IDb
+	eth1 *simulatedEth1
+	node exporter.Exporter
+}
+
+// cluster runs operator nodes and an exporter in a single process, connected by a local network,
+// with a simulated beacon node and a simulated registry contract
+type cluster struct {
+	t          *testing.T
+	ctx        context.Context
+	logger     *zap.Logger
+	ethNetwork core.Network
+	network    *committeeNetwork
+	beacon     *simulatedBeacon
+
+	operators []*operatorNode
+	exporter  *exporterNode
+
+	// block is the block number of the last registry event
+	block uint64
+}
+
+// newCluster creates the nodes of the cluster, nodes are started with start()
+func newCluster(ctx context.Context, t *testing.T, logger *zap.Logger) *cluster {
+	threshold.Init()
+
+	c := &cluster{
+		t:          t,
+		ctx:        ctx,
+		logger:     logger,
+		ethNetwork: core.PraterNetwork,
+		network:    &committeeNetwork{Local: local.NewLocalNetwork()},
+	}
+	c.beacon = newSimulatedBeacon(c.ethNetwork)
+	for i := uint64(1); i <= operatorsCount; i++ {
+		c.operators = append(c.operators, c.newOperator(i))
+	}
+	c.exporter = c.newExporter()
+	// operators are registered before the nodes start, therefore the events are picked by the sync
+	for _, op := range c.operators {
+		c.addOperator(op)
+	}
+	return c
+}
+
+func (c *cluster) newDB(logger *zap.Logger) basedb.IDb {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: logger,
+		Ctx:    c.ctx,
+	})
+	require.NoError(c.t, err)
+	return db
+}
+
+func (c *cluster) newOperator(id uint64) *operatorNode {
+	logger := c.logger.With(zap.Uint64("operator", id))
+	db := c.newDB(logger)
+
+	_, skPem, err := rsaencryption.GenerateKeys()
+	require.NoError(c.t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+	require.NoError(c.t, err)
+	pubKey, err := rsaencryption.ExtractPublicKey(sk)
+	require.NoError(c.t, err)
+	keyProvider := func() (*rsa.PrivateKey, bool, error) {
+		return sk, true, nil
+	}
+
+	keyManager, err := ekm.NewETHKeyManagerSigner(db, c.beacon, c.ethNetwork)
+	require.NoError(c.t, err)
+
+	eth1Client := newSimulatedEth1()
+	ctrlOptions := validator.ControllerOptions{
+		Context:                       c.ctx,
+		DB:                            db,
+		Logger:                        logger,
+		SignatureCollectionTimeout:    5 * time.Second,
+		MetadataUpdateInterval:        10 * time.Minute,
+		MsgQueueTTL:                   10 * time.Minute,
+		MsgQueueCleanupInterval:       11 * time.Minute,
+		ETHNetwork:                    &c.ethNetwork,
+		Network:                       c.network,
+		Beacon:                        c.beacon,
+		ShareEncryptionKeyProvider:    keyProvider,
+		Fork:                          v0.New(),
+		KeyManager:                    keyManager,
+		CommitteeConnectivityInterval: time.Minute,
+		ActivationPollInterval:        time.Minute,
+		DutyDeadlineSlots:             1,
+		ConsensusSeqWindow:            32,
+	}
+	ctrl := validator.NewController(ctrlOptions)
+	node := operator.New(operator.Options{
+		ETHNetwork:            &c.ethNetwork,
+		Beacon:                c.beacon,
+		Network:               c.network,
+		Context:               c.ctx,
+		Logger:                logger,
+		Eth1Client:            eth1Client,
+		DB:                    db,
+		ValidatorController:   ctrl,
+		DutyLimit:             32,
+		ValidatorOptions:      ctrlOptions,
+		Fork:                  v0.New(),
+		SyncDistanceTolerance: 4,
+	})
+
+	return &operatorNode{
+		id:     id,
+		sk:     sk,
+		pubKey: pubKey,
+		db:     db,
+		eth1:   eth1Client,
+		ctrl:   ctrl,
+		node:   node,
+	}
+}
+
+func (c *cluster) newExporter() *exporterNode {
+	logger := c.logger.With(zap.String("node", "exporter"))
+	db := c.newDB(logger)
+	eth1Client := newSimulatedEth1()
+	node := exporter.New(exporter.Options{
+		Ctx:                             c.ctx,
+		Logger:                          logger,
+		ETHNetwork:                      &c.ethNetwork,
+		Eth1Client:                      eth1Client,
+		Beacon:                          c.beacon,
+		Network:                         c.network,
+		DB:                              db,
+		WS:                              api.NewWsServer(logger, gorilla.NewGorillaAdapter(logger), nil, http.NewServeMux()),
+		IbftSyncEnabled:                 true,
+		ValidatorMetaDataUpdateInterval: 10 * time.Minute,
+		ReplicaID:                       "e2e",
+	})
+	return &exporterNode{
+		db:   db,
+		eth1: eth1Client,
+		node: node,
+	}
+}
+
+// start syncs the registry and starts all the nodes
+func (c *cluster) start() {
+	for _, op := range c.operators {
+		require.NoError(c.t, op.node.StartEth1(nil))
+		go func(op *operatorNode) {
+			if err := op.node.Start(); err != nil {
+				c.logger.Error("failed to start operator node", zap.Uint64("operator", op.id), zap.Error(err))
+			}
+		}(op)
+	}
+	require.NoError(c.t, c.exporter.node.StartEth1(nil))
+	go func() {
+		// blocks while serving the ws api (on a random port)
+		if err := c.exporter.node.Start(); err != nil {
+			c.logger.Error("failed to start exporter node", zap.Error(err))
+		}
+	}()
+}
+
+// waitForEpochSlots blocks until at least the given number of slots are left in the current epoch,
+// so a duty that is requested now is placed in the current epoch
+func (c *cluster) waitForEpochSlots(slots uint64) {
+	slotsPerEpoch := c.ethNetwork.SlotsPerEpoch()
+	for slotsPerEpoch-uint64(c.ethNetwork.EstimatedCurrentSlot())%slotsPerEpoch < slots {
+		time.Sleep(time.Second)
+	}
+}
+
+// nextLog returns the log of a new registry event
+func (c *cluster) nextLog() types.Log {
+	c.block++
+	return types.Log{
+		BlockNumber: c.block,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(c.block)),
+	}
+}
+
+// fire sends the given event to all the nodes, the event of each operator is created in the context of the operator
+func (c *cluster) fire(name string, newEvent func(op *operatorNode) interface{}) {
+	vLog := c.nextLog()
+	for _, op := range c.operators {
+		op.eth1.fire(&eth1.Event{Log: vLog, Name: name, Data: newEvent(op)})
+	}
+	c.exporter.eth1.fire(&eth1.Event{Log: vLog, Name: name, Data: newEvent(nil)})
+}
+
+// addOperator fires an OperatorAdded event of the given operator
+func (c *cluster) addOperator(op *operatorNode) {
+	c.fire("OperatorAdded", func(*operatorNode) interface{} {
+		return eth1.OperatorAddedEvent{
+			Name:         fmt.Sprintf("operator-%d", op.id),
+			PublicKey:    []byte(op.pubKey),
+			OwnerAddress: common.BigToAddress(new(big.Int).SetUint64(op.id)),
+		}
+	})
+}
+
+// registerValidator creates a new validator, splits its key among all the operators and fires a ValidatorAdded event.
+// the validator is activated on the simulated beacon with the given index
+func (c *cluster) registerValidator(index spec.ValidatorIndex) *bls.PublicKey {
+	sk := &bls.SecretKey{}
+	sk.SetByCSPRNG()
+	shares, err := threshold.Create(sk.Serialize(), signersThreshold, operatorsCount)
+	require.NoError(c.t, err)
+	c.beacon.addValidator(index, sk.GetPublicKey())
+
+	c.fire("ValidatorAdded", func(self *operatorNode) interface{} {
+		var oessList []eth1.Oess
+		for i, op := range c.operators {
+			share := shares[op.id]
+			oess := eth1.Oess{
+				Index:             big.NewInt(int64(i)),
+				OperatorPublicKey: []byte(op.pubKey),
+				SharedPublicKey:   share.GetPublicKey().Serialize(),
+			}
+			if op == self {
+				// the share of the operator is decrypted by its eth1 client
+				oess.EncryptedKey = []byte(share.SerializeToHexStr())
+			} else {
+				encrypted, err := rsaencryption.EncodeKey(&op.sk.PublicKey, share.SerializeToHexStr())
+				require.NoError(c.t, err)
+				oess.EncryptedKey = []byte(encrypted)
+			}
+			oessList = append(oessList, oess)
+		}
+		return eth1.ValidatorAddedEvent{
+			PublicKey:    sk.GetPublicKey().Serialize(),
+			OwnerAddress: common.BigToAddress(big.NewInt(int64(index))),
+			OessList:     oessList,
+		}
+	})
+	return sk.GetPublicKey()
+}
@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package e2e
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prysmaticlabs/prysm/async/event"
+)
+
+// simulatedEth1 is an eth1.Client of a single node, events are fired by the simulated registry
+// after they were decoded in the context of the node (i.e. with the share of the operator)
+type simulatedEth1 struct {
+	feed *event.Feed
+
+	lock   sync.Mutex
+	synced []*eth1.Event
+}
+
+func newSimulatedEth1() *simulatedEth1 {
+	return &simulatedEth1{feed: new(event.Feed)}
+}
+
+// EventsFeed returns the contract events feed
+func (ec *simulatedEth1) EventsFeed() *event.Feed {
+	return ec.feed
+}
+
+// Start does nothing, events are fired once they are added to the registry
+func (ec *simulatedEth1) Start() error {
+	return nil
+}
+
+// Sync fires the events that were added before the node started, followed by SyncEndedEvent
+func (ec *simulatedEth1) Sync(fromBlock *big.Int) error {
+	ec.lock.Lock()
+	events := ec.synced
+	ec.synced = nil
+	ec.lock.Unlock()
+
+	var logs []types.Log
+	for _, e := range events {
+		ec.feed.Send(e)
+		logs = append(logs, e.Log)
+	}
+	ec.feed.Send(&eth1.Event{Data: eth1.SyncEndedEvent{Success: true, Logs: logs}})
+	return nil
+}
+
+// fire sends the given event to the subscribers, or keeps it for the sync if there are none yet
+func (ec *simulatedEth1) fire(e *eth1.Event) {
+	ec.lock.Lock()
+	defer ec.lock.Unlock()
+
+	if ec.feed.Send(e) == 0 {
+		ec.synced = append(ec.synced, e)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package e2e
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+// committeeNetwork is the local network as seen by the nodes of the cluster, it behaves like a real network where:
+//  1. each node receives its own copy of a message, the local network shares the broadcasted message among receivers
+//  2. a peer of the committee responds to sync requests of a new validator w/o history. the local network treats
+//     each iBFT controller as a peer, while controllers of other identifiers don't respond
+type committeeNetwork struct {
+	*local.Local
+}
+
+// ReceivedMsgChan returns a channel of copies of the broadcasted iBFT messages
+func (n *committeeNetwork) ReceivedMsgChan() <-chan *proto.SignedMessage {
+	return copyMessages(n.Local.ReceivedMsgChan())
+}
+
+// ReceivedSignatureChan returns a channel of copies of the broadcasted signatures
+func (n *committeeNetwork) ReceivedSignatureChan() <-chan *proto.SignedMessage {
+	return copyMessages(n.Local.ReceivedSignatureChan())
+}
+
+// ReceivedDecidedChan returns a channel of copies of the broadcasted decided messages
+func (n *committeeNetwork) ReceivedDecidedChan() <-chan *proto.SignedMessage {
+	return copyMessages(n.Local.ReceivedDecidedChan())
+}
+
+// GetHighestDecidedInstance returns a not-found response if the peer didn't respond
+func (n *committeeNetwork) GetHighestDecidedInstance(peerStr string, msg *network.SyncMessage) (*network.SyncMessage, error) {
+	res, err := n.Local.GetHighestDecidedInstance(peerStr, msg)
+	if err != nil {
+		return &network.SyncMessage{
+			Lambda:     msg.Lambda,
+			FromPeerID: peerStr,
+			Type:       msg.Type,
+			Error:      kv.EntryNotFoundError,
+		}, nil
+	}
+	return res, nil
+}
+
+// copyMessages forwards a copy of each message of the given channel
+func copyMessages(in <-chan *proto.SignedMessage) <-chan *proto.SignedMessage {
+	out := make(chan *proto.SignedMessage)
+	go func() {
+		for msg := range in {
+			cp, err := msg.DeepCopy()
+			if err != nil {
+				continue
+			}
+			out <- cp
+		}
+	}()
+	return out
+}
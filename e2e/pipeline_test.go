@@ -0,0 +1,79 @@
+//go:build integration
+// +build integration
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	exporterstorage "github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/storage/collections"
+	"github.com/bloxapp/ssv/utils/format"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestPipeline_AttestationDuty registers a validator with a committee of 4 operators and asserts
+// that its attestation duty is executed (decided, signed and submitted) and visible in the exporter
+func TestPipeline_AttestationDuty(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := logex.Build("e2e", zapcore.InfoLevel, nil)
+
+	c := newCluster(ctx, t, logger)
+	// the validator is registered before the nodes start, therefore it is picked by the registry sync.
+	// the duties are placed a few slots after the nodes start, they must be in the current epoch
+	c.waitForEpochSlots(dutyDelaySlots + dutySlots + 1)
+	pk := c.registerValidator(1)
+	c.start()
+
+	t.Run("operators picked the validator", func(t *testing.T) {
+		for _, op := range c.operators {
+			require.Eventually(t, func() bool {
+				v, found := op.ctrl.GetValidator(pk.SerializeToHexStr())
+				return found && v.Share.NodeID == op.id && v.Share.HasMetadata()
+			}, 30*time.Second, 100*time.Millisecond, "operator %d didn't pick the validator", op.id)
+		}
+	})
+
+	t.Run("exporter indexed the registry", func(t *testing.T) {
+		expStorage := exporterstorage.NewExporterStorage(c.exporter.db, logger)
+		require.Eventually(t, func() bool {
+			vi, found, err := expStorage.GetValidatorInformation(pk.SerializeToHexStr())
+			return err == nil && found && len(vi.Operators) == operatorsCount
+		}, 30*time.Second, 100*time.Millisecond)
+		operators, err := expStorage.ListOperators(0, 0)
+		require.NoError(t, err)
+		require.Len(t, operators, operatorsCount)
+	})
+
+	// consensus and signature collection are done within the slot of the duty
+	slotDuration := c.ethNetwork.SlotDurationSec()
+
+	t.Run("attestations were submitted", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			slots := map[spec.Slot]bool{}
+			for _, att := range c.beacon.submittedAttestations(pk) {
+				slots[att.Data.Slot] = true
+			}
+			return len(slots) == dutySlots
+		}, (dutyDelaySlots+dutySlots+1)*slotDuration, 200*time.Millisecond)
+		require.Zero(t, c.beacon.invalidAttestations())
+	})
+
+	t.Run("decided is visible in the exporter", func(t *testing.T) {
+		identifier := []byte(format.IdentifierFormat(pk.Serialize(), beacon.RoleTypeAttester.String()))
+		// the exporter keeps decided messages in the attestation collection, the first sequence is not exported
+		ibftStorage := collections.NewIbft(c.exporter.db, logger, "attestation")
+		require.Eventually(t, func() bool {
+			decided, found, err := ibftStorage.GetHighestDecidedInstance(identifier)
+			return err == nil && found && decided.Message.SeqNumber == dutySlots-1 &&
+				len(decided.SignerIds) >= signersThreshold
+		}, slotDuration, 200*time.Millisecond)
+	})
+}
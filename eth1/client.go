@@ -8,19 +8,26 @@ import (
 	"time"
 )
 
+// DefaultRegistryContractAddr is the registry contract that is used when no network profile was selected
+const DefaultRegistryContractAddr = "0x9573C41F0Ed8B72f3bD6A9bA6E3e15426A0aa65B"
+
 // Options configurations related to eth1
 type Options struct {
 	ETH1Addr              string        `yaml:"ETH1Addr" env:"ETH_1_ADDR" env-required:"true" env-description:"ETH1 node WebSocket address"`
 	ETH1SyncOffset        string        `yaml:"ETH1SyncOffset" env:"ETH_1_SYNC_OFFSET" env-description:"block number to start the sync from"`
+	ETH1HTTPAddr          string        `yaml:"ETH1HTTPAddr" env:"ETH_1_HTTP_ADDR" env-description:"ETH1 node HTTP address, used for polling events while the WebSocket connection is down"`
+	ETH1PollInterval      time.Duration `yaml:"ETH1PollInterval" env:"ETH_1_POLL_INTERVAL" env-default:"12s" env-description:"interval of polling events over HTTP"`
 	ETH1ConnectionTimeout time.Duration `yaml:"ETH1ConnectionTimeout" env:"ETH_1_CONNECTION_TIMEOUT" env-default:"10s" env-description:"eth1 node connection timeout"`
-	RegistryContractAddr  string        `yaml:"RegistryContractAddr" env:"REGISTRY_CONTRACT_ADDR_KEY" env-default:"0x9573C41F0Ed8B72f3bD6A9bA6E3e15426A0aa65B" env-description:"registry contract address"`
+	RegistryContractAddr  string        `yaml:"RegistryContractAddr" env:"REGISTRY_CONTRACT_ADDR_KEY" env-description:"registry contract address, defaults to the contract of the selected network"`
 	RegistryContractABI   string        `yaml:"RegistryContractABI" env:"REGISTRY_CONTRACT_ABI" env-description:"registry contract abi json file"`
 	CleanRegistryData     bool          `yaml:"CleanRegistryData" env:"CLEAN_REGISTRY_DATA" env-default:"false" env-description:"cleans registry contract data (validator shares) and forces re-sync"`
 }
 
 // Event represents an eth1 event log in the system
 type Event struct {
-	Log  types.Log
+	Log types.Log
+	// Name is the name of the contract event (e.g. "ValidatorAdded")
+	Name string
 	Data interface{}
 }
 
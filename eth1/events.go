@@ -18,7 +18,7 @@ import (
 )
 
 var (
-	contractABI = `[{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes","name":"validatorPublicKey","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"index","type":"uint256"},{"indexed":false,"internalType":"bytes","name":"operatorPublicKey","type":"bytes"},{"indexed":false,"internalType":"bytes","name":"sharedPublicKey","type":"bytes"},{"indexed":false,"internalType":"bytes","name":"encryptedKey","type":"bytes"}],"name":"OessAdded","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"string","name":"name","type":"string"},{"indexed":false,"internalType":"address","name":"ownerAddress","type":"address"},{"indexed":false,"internalType":"bytes","name":"publicKey","type":"bytes"}],"name":"OperatorAdded","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address","name":"ownerAddress","type":"address"},{"indexed":false,"internalType":"bytes","name":"publicKey","type":"bytes"},{"components":[{"internalType":"uint256","name":"index","type":"uint256"},{"internalType":"bytes","name":"operatorPublicKey","type":"bytes"},{"internalType":"bytes","name":"sharedPublicKey","type":"bytes"},{"internalType":"bytes","name":"encryptedKey","type":"bytes"}],"indexed":false,"internalType":"struct ISSVNetwork.Oess[]","name":"oessList","type":"tuple[]"}],"name":"ValidatorAdded","type":"event"},{"inputs":[{"internalType":"string","name":"_name","type":"string"},{"internalType":"address","name":"_ownerAddress","type":"address"},{"internalType":"bytes","name":"_publicKey","type":"bytes"}],"name":"addOperator","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"_ownerAddress","type":"address"},{"internalType":"bytes","name":"_publicKey","type":"bytes"},{"internalType":"bytes[]","name":"_operatorPublicKeys","type":"bytes[]"},{"internalType":"bytes[]","name":"_sharesPublicKeys","type":"bytes[]"},{"internalType":"bytes[]","name":"_encryptedKeys","type":"bytes[]"}],"name":"addValidator","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"operatorCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes","name":"","type":"bytes"}],"name":"operators","outputs":[{"internalType":"string","name":"name","type":"string"},{"internalType":"address","name":"ownerAddress","type":"address"},{"internalType":"bytes","name":"publicKey","type":"bytes"},{"internalType":"uint256","name":"score","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"validatorCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+	contractABI = `[{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes","name":"validatorPublicKey","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"index","type":"uint256"},{"indexed":false,"internalType":"bytes","name":"operatorPublicKey","type":"bytes"},{"indexed":false,"internalType":"bytes","name":"sharedPublicKey","type":"bytes"},{"indexed":false,"internalType":"bytes","name":"encryptedKey","type":"bytes"}],"name":"OessAdded","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"string","name":"name","type":"string"},{"indexed":false,"internalType":"address","name":"ownerAddress","type":"address"},{"indexed":false,"internalType":"bytes","name":"publicKey","type":"bytes"}],"name":"OperatorAdded","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address","name":"ownerAddress","type":"address"},{"indexed":false,"internalType":"bytes","name":"publicKey","type":"bytes"},{"components":[{"internalType":"uint256","name":"index","type":"uint256"},{"internalType":"bytes","name":"operatorPublicKey","type":"bytes"},{"internalType":"bytes","name":"sharedPublicKey","type":"bytes"},{"internalType":"bytes","name":"encryptedKey","type":"bytes"}],"indexed":false,"internalType":"struct ISSVNetwork.Oess[]","name":"oessList","type":"tuple[]"}],"name":"ValidatorAdded","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address","name":"ownerAddress","type":"address"},{"indexed":false,"internalType":"bytes","name":"publicKey","type":"bytes"}],"name":"ValidatorExitRequested","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address","name":"ownerAddress","type":"address"},{"indexed":false,"internalType":"bytes","name":"publicKey","type":"bytes"}],"name":"ValidatorRemoved","type":"event"},{"inputs":[{"internalType":"string","name":"_name","type":"string"},{"internalType":"address","name":"_ownerAddress","type":"address"},{"internalType":"bytes","name":"_publicKey","type":"bytes"}],"name":"addOperator","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"_ownerAddress","type":"address"},{"internalType":"bytes","name":"_publicKey","type":"bytes"},{"internalType":"bytes[]","name":"_operatorPublicKeys","type":"bytes[]"},{"internalType":"bytes[]","name":"_sharesPublicKeys","type":"bytes[]"},{"internalType":"bytes[]","name":"_encryptedKeys","type":"bytes[]"}],"name":"addValidator","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"operatorCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes","name":"","type":"bytes"}],"name":"operators","outputs":[{"internalType":"string","name":"name","type":"string"},{"internalType":"address","name":"ownerAddress","type":"address"},{"internalType":"bytes","name":"publicKey","type":"bytes"},{"internalType":"uint256","name":"score","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"validatorCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
 )
 
 // LoadABI enables to load a custom abi json
@@ -71,6 +71,18 @@ type ValidatorAddedEvent struct {
 	OessList     []Oess
 }
 
+// ValidatorExitRequestedEvent struct represents a request of the validator owner to exit the validator
+type ValidatorExitRequestedEvent struct {
+	PublicKey    []byte
+	OwnerAddress common.Address
+}
+
+// ValidatorRemovedEvent struct represents the removal of the validator from the network by its owner
+type ValidatorRemovedEvent struct {
+	PublicKey    []byte
+	OwnerAddress common.Address
+}
+
 // OperatorAddedEvent struct represents event received by the smart contract
 type OperatorAddedEvent struct {
 	Name           string
@@ -95,6 +107,9 @@ func ParseOperatorAddedEvent(logger *zap.Logger, operatorPrivateKey *rsa.Private
 		return nil, false, err
 	}
 	operatorAddedEvent.PublicKey = []byte(pubKey)
+	if err := validateOperatorAddedEvent(&operatorAddedEvent); err != nil {
+		return nil, false, errors.Wrap(err, "invalid OperatorAdded event")
+	}
 	logger.Debug("OperatorAdded Event",
 		zap.String("Operator PublicKey", pubKey),
 		zap.String("Payment Address", operatorAddedEvent.PaymentAddress.String()))
@@ -116,6 +131,9 @@ func ParseValidatorAddedEvent(logger *zap.Logger, operatorPrivateKey *rsa.Privat
 	if err != nil {
 		return nil, false, errors.Wrap(err, "Failed to unpack ValidatorAdded event")
 	}
+	if err := validateValidatorAddedEvent(&validatorAddedEvent); err != nil {
+		return nil, false, errors.Wrap(err, "invalid ValidatorAdded event")
+	}
 
 	logger.Debug("ValidatorAdded Event",
 		zap.String("Validator PublicKey", hex.EncodeToString(validatorAddedEvent.PublicKey)),
@@ -153,7 +171,10 @@ func ParseValidatorAddedEvent(logger *zap.Logger, operatorPrivateKey *rsa.Privat
 				decryptedSharePrivateKey, err := rsaencryption.DecodeKey(operatorPrivateKey, encryptedSharePrivateKey)
 				decryptedSharePrivateKey = strings.Replace(decryptedSharePrivateKey, "0x", "", 1)
 				if err != nil {
-					return nil, false, errors.Wrap(err, "failed to decrypt share private key")
+					return nil, false, &ShareDecryptionError{
+						PublicKey: validatorAddedEvent.PublicKey,
+						Err:       errors.Wrap(err, "failed to decrypt share private key"),
+					}
 				}
 				validatorShare.EncryptedKey = []byte(decryptedSharePrivateKey)
 				isEventBelongsToOperator = true
@@ -164,6 +185,42 @@ func ParseValidatorAddedEvent(logger *zap.Logger, operatorPrivateKey *rsa.Privat
 	return &validatorAddedEvent, isEventBelongsToOperator, nil
 }
 
+// ParseValidatorExitRequestedEvent parses ValidatorExitRequestedEvent
+func ParseValidatorExitRequestedEvent(logger *zap.Logger, data []byte, contractAbi abi.ABI) (*ValidatorExitRequestedEvent, error) {
+	var exitEvent ValidatorExitRequestedEvent
+	err := contractAbi.UnpackIntoInterface(&exitEvent, "ValidatorExitRequested", data)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to unpack ValidatorExitRequested event")
+	}
+	if err := validateValidatorExitRequestedEvent(&exitEvent); err != nil {
+		return nil, errors.Wrap(err, "invalid ValidatorExitRequested event")
+	}
+
+	logger.Debug("ValidatorExitRequested Event",
+		zap.String("Validator PublicKey", hex.EncodeToString(exitEvent.PublicKey)),
+		zap.String("Owner Address", exitEvent.OwnerAddress.String()))
+
+	return &exitEvent, nil
+}
+
+// ParseValidatorRemovedEvent parses ValidatorRemovedEvent
+func ParseValidatorRemovedEvent(logger *zap.Logger, data []byte, contractAbi abi.ABI) (*ValidatorRemovedEvent, error) {
+	var removedEvent ValidatorRemovedEvent
+	err := contractAbi.UnpackIntoInterface(&removedEvent, "ValidatorRemoved", data)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to unpack ValidatorRemoved event")
+	}
+	if err := validateValidatorRemovedEvent(&removedEvent); err != nil {
+		return nil, errors.Wrap(err, "invalid ValidatorRemoved event")
+	}
+
+	logger.Debug("ValidatorRemoved Event",
+		zap.String("Validator PublicKey", hex.EncodeToString(removedEvent.PublicKey)),
+		zap.String("Owner Address", removedEvent.OwnerAddress.String()))
+
+	return &removedEvent, nil
+}
+
 func readOperatorPubKey(operatorPublicKey []byte, outAbi abi.ABI) (string, error) {
 	outOperatorPublicKey, err := outAbi.Unpack("method", operatorPublicKey)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -68,3 +69,38 @@ func TestParseValidatorAddedEvent(t *testing.T) {
 	require.Equal(t, "91db3a13ab428a6c9c20e7104488cb6961abeab60e56cf4ba199eed3b5f6e7ced670ecb066c9704dc2fa93133792381c",
 		hex.EncodeToString(parsed.PublicKey))
 }
+
+func TestParseValidatorExitRequestedEvent(t *testing.T) {
+	contractAbi, err := abi.JSON(strings.NewReader(ContractABI()))
+	require.NoError(t, err)
+
+	pk, _ := hex.DecodeString("91db3a13ab428a6c9c20e7104488cb6961abeab60e56cf4ba199eed3b5f6e7ced670ecb066c9704dc2fa93133792381c")
+	owner := common.HexToAddress("0xfeedb14d8b2c76fdf808c29818b06b830e8c2c0e")
+	data, err := contractAbi.Events["ValidatorExitRequested"].Inputs.Pack(owner, pk)
+	require.NoError(t, err)
+
+	parsed, err := ParseValidatorExitRequestedEvent(zap.L(), data, contractAbi)
+	require.NoError(t, err)
+	require.Equal(t, pk, parsed.PublicKey)
+	require.Equal(t, owner, parsed.OwnerAddress)
+}
+
+func TestParseValidatorRemovedEvent(t *testing.T) {
+	contractAbi, err := abi.JSON(strings.NewReader(ContractABI()))
+	require.NoError(t, err)
+
+	pk, _ := hex.DecodeString("91db3a13ab428a6c9c20e7104488cb6961abeab60e56cf4ba199eed3b5f6e7ced670ecb066c9704dc2fa93133792381c")
+	owner := common.HexToAddress("0xfeedb14d8b2c76fdf808c29818b06b830e8c2c0e")
+	data, err := contractAbi.Events["ValidatorRemoved"].Inputs.Pack(owner, pk)
+	require.NoError(t, err)
+
+	parsed, err := ParseValidatorRemovedEvent(zap.L(), data, contractAbi)
+	require.NoError(t, err)
+	require.Equal(t, pk, parsed.PublicKey)
+	require.Equal(t, owner, parsed.OwnerAddress)
+
+	data, err = contractAbi.Events["ValidatorRemoved"].Inputs.Pack(owner, pk[:20])
+	require.NoError(t, err)
+	_, err = ParseValidatorRemovedEvent(zap.L(), data, contractAbi)
+	require.EqualError(t, err, "invalid ValidatorRemoved event: invalid validator public key size: 20")
+}
@@ -0,0 +1,159 @@
+package goeth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval    = 12 * time.Second
+	processedLogsCacheSize = 1024
+)
+
+// startHTTPFallback polls contract logs over HTTP from the last processed block,
+// until the returned function is called (once the WebSocket subscription was restored)
+func (ec *eth1Client) startHTTPFallback(contractAbi abi.ABI) func() {
+	if len(ec.httpFallbackAddr) == 0 {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ec.ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		metricsEth1HTTPFallbackActive.Set(1)
+		defer metricsEth1HTTPFallbackActive.Set(0)
+		logger := ec.logger.With(zap.String("component", "eth1HTTPFallback"))
+		logger.Info("polling contract events over HTTP", zap.Uint64("fromBlock", ec.getLastBlock()))
+
+		var conn *ethclient.Client
+		defer func() {
+			if conn != nil {
+				conn.Close()
+			}
+		}()
+		ticker := time.NewTicker(ec.pollInterval)
+		defer ticker.Stop()
+		for {
+			if conn == nil {
+				dialCtx, dialCancel := context.WithTimeout(ctx, ec.connectionTimeout)
+				c, err := ethclient.DialContext(dialCtx, ec.httpFallbackAddr)
+				dialCancel()
+				if err != nil {
+					logger.Warn("could not connect to eth1 node over HTTP", zap.Error(err))
+				}
+				conn = c
+			}
+			if conn != nil {
+				if err := ec.pollLogs(conn, contractAbi); err != nil {
+					logger.Warn("could not poll contract events", zap.Error(err))
+				}
+			}
+			select {
+			case <-ctx.Done():
+				logger.Info("stopped polling contract events over HTTP")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// pollLogs fetches and processes the logs between the last processed block and the current block
+func (ec *eth1Client) pollLogs(conn *ethclient.Client, contractAbi abi.ABI) error {
+	currentBlock, err := conn.BlockNumber(ec.ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current block")
+	}
+	// starting from the last block (inclusive) as it might contain more logs, duplicates are filtered
+	fromBlock := ec.getLastBlock()
+	for fromBlock <= currentBlock {
+		toBlock := fromBlock + blocksInBatch
+		if toBlock > currentBlock {
+			toBlock = currentBlock
+		}
+		logs, err := conn.FilterLogs(ec.ctx, ethereum.FilterQuery{
+			Addresses: []common.Address{common.HexToAddress(ec.registryContractAddr)},
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get event logs")
+		}
+		for _, vLog := range logs {
+			processed, err := ec.processLog(vLog, contractAbi)
+			if err != nil {
+				ec.logger.Error("Failed to handle polled event", zap.Error(err))
+				continue
+			}
+			if processed {
+				metricsEth1FallbackEvents.Inc()
+			}
+		}
+		ec.setLastBlock(toBlock)
+		fromBlock = toBlock + 1
+	}
+	return nil
+}
+
+// processLog handles the given log if it wasn't processed yet, returns true if the log was processed
+func (ec *eth1Client) processLog(vLog types.Log, contractAbi abi.ABI) (bool, error) {
+	ec.processLock.Lock()
+	defer ec.processLock.Unlock()
+
+	if ec.processedLogs.Contains(logKey(vLog)) {
+		return false, nil
+	}
+	ec.processedLogs.Add(logKey(vLog), true)
+	ec.updateLastBlock(vLog.BlockNumber)
+	return true, ec.handleEvent(vLog, contractAbi)
+}
+
+// markProcessed marks the given log as processed
+func (ec *eth1Client) markProcessed(vLog types.Log) {
+	ec.processLock.Lock()
+	defer ec.processLock.Unlock()
+
+	ec.processedLogs.Add(logKey(vLog), true)
+	ec.updateLastBlock(vLog.BlockNumber)
+}
+
+// setLastBlock raises the last processed block
+func (ec *eth1Client) setLastBlock(block uint64) {
+	ec.processLock.Lock()
+	defer ec.processLock.Unlock()
+
+	ec.updateLastBlock(block)
+}
+
+// updateLastBlock raises the last processed block, the caller must hold processLock
+func (ec *eth1Client) updateLastBlock(block uint64) {
+	if block > ec.lastBlock {
+		ec.lastBlock = block
+	}
+}
+
+func (ec *eth1Client) getLastBlock() uint64 {
+	ec.processLock.Lock()
+	defer ec.processLock.Unlock()
+
+	return ec.lastBlock
+}
+
+// logKey returns a unique key of the given log
+func logKey(vLog types.Log) string {
+	return fmt.Sprintf("%s:%d", vLog.TxHash.Hex(), vLog.Index)
+}
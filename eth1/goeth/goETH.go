@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -20,6 +21,7 @@ import (
 	"log"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -35,14 +37,34 @@ var (
 		Name: "ssv:eth1:node_status",
 		Help: "Status of the connected eth1 node",
 	})
+	metricsEth1Reconnections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:eth1:reconnections",
+		Help: "Count of reconnection attempts to the eth1 node",
+	}, []string{"result"})
+	metricsEth1HTTPFallbackActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:eth1:http_fallback_active",
+		Help: "Whether events are polled over HTTP while the WebSocket subscription is down (0/1)",
+	})
+	metricsEth1FallbackEvents = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv:eth1:fallback_events",
+		Help: "Count of contract events that were recovered by polling",
+	})
 	statusUnknown eth1NodeStatus = 0
 	statusSyncing eth1NodeStatus = 1
 	statusOK      eth1NodeStatus = 2
 )
 
 func init() {
-	if err := prometheus.Register(metricsEth1NodeStatus); err != nil {
-		log.Println("could not register prometheus collector")
+	allMetrics := []prometheus.Collector{
+		metricsEth1NodeStatus,
+		metricsEth1Reconnections,
+		metricsEth1HTTPFallbackActive,
+		metricsEth1FallbackEvents,
+	}
+	for _, c := range allMetrics {
+		if err := prometheus.Register(c); err != nil {
+			log.Println("could not register prometheus collector")
+		}
 	}
 }
 
@@ -55,6 +77,12 @@ type ClientOptions struct {
 	ContractABI                string
 	ConnectionTimeout          time.Duration
 	ShareEncryptionKeyProvider eth1.ShareEncryptionKeyProvider
+	// HTTPFallbackAddr is an optional HTTP address of the eth1 node,
+	// used for polling events while the WebSocket subscription is down
+	HTTPFallbackAddr string
+	PollInterval     time.Duration
+	// QuarantineStorage is optional, malformed events are quarantined instead of failing the sync
+	QuarantineStorage eth1.QuarantineStorage
 }
 
 // eth1Client is the internal implementation of Client
@@ -69,8 +97,18 @@ type eth1Client struct {
 	registryContractAddr string
 	contractABI          string
 	connectionTimeout    time.Duration
-
-	eventsFeed *event.Feed
+	httpFallbackAddr     string
+	pollInterval         time.Duration
+
+	eventsFeed        *event.Feed
+	quarantineStorage eth1.QuarantineStorage
+
+	// processLock serializes the handling of logs from the subscription, polling and catch-up
+	processLock sync.Mutex
+	// lastBlock is the last block that its logs were processed
+	lastBlock uint64
+	// processedLogs holds the keys of recently processed logs to avoid handling a log twice
+	processedLogs *lru.Cache
 }
 
 // verifies that the client implements HealthCheckAgent
@@ -82,6 +120,15 @@ func NewEth1Client(opts ClientOptions) (eth1.Client, error) {
 		zap.String("address", opts.RegistryContractAddr))
 	logger.Info("eth1 addresses", zap.String("address", opts.NodeAddr))
 
+	processedLogs, err := lru.New(processedLogsCacheSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create processed logs cache")
+	}
+	pollInterval := opts.PollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultPollInterval
+	}
+
 	ec := eth1Client{
 		ctx:                        opts.Ctx,
 		logger:                     logger,
@@ -90,7 +137,11 @@ func NewEth1Client(opts ClientOptions) (eth1.Client, error) {
 		registryContractAddr:       opts.RegistryContractAddr,
 		contractABI:                opts.ContractABI,
 		connectionTimeout:          opts.ConnectionTimeout,
+		httpFallbackAddr:           opts.HTTPFallbackAddr,
+		pollInterval:               pollInterval,
 		eventsFeed:                 new(event.Feed),
+		processedLogs:              processedLogs,
+		quarantineStorage:          opts.QuarantineStorage,
 	}
 
 	if err := ec.connect(); err != nil {
@@ -168,14 +219,17 @@ func (ec *eth1Client) reconnect() {
 	tasks.ExecWithInterval(func(lastTick time.Duration) (stop bool, cont bool) {
 		ec.logger.Info("reconnecting to eth1 node")
 		if err := ec.connect(); err != nil {
-			// continue until reaching to limit, and then panic as eth1 connection is required
-			if lastTick >= limit {
+			metricsEth1Reconnections.WithLabelValues("failure").Inc()
+			// continue until reaching to limit, and then panic as eth1 connection is required.
+			// if events are polled over HTTP, keep trying as events are not missed in the meanwhile
+			if lastTick >= limit && len(ec.httpFallbackAddr) == 0 {
 				ec.logger.Panic("failed to reconnect to eth1 node", zap.Error(err))
 			} else {
 				ec.logger.Warn("could not reconnect to eth1 node, still trying", zap.Error(err))
 			}
 			return false, false
 		}
+		metricsEth1Reconnections.WithLabelValues("success").Inc()
 		return true, false
 	}, 1*time.Second, limit+(1*time.Second))
 	ec.logger.Debug("managed to reconnect to eth1 node")
@@ -186,8 +240,8 @@ func (ec *eth1Client) reconnect() {
 }
 
 // fireEvent notifies observers about some contract event
-func (ec *eth1Client) fireEvent(log types.Log, data interface{}) {
-	e := eth1.Event{Log: log, Data: data}
+func (ec *eth1Client) fireEvent(log types.Log, name string, data interface{}) {
+	e := eth1.Event{Log: log, Name: name, Data: data}
 	_ = ec.eventsFeed.Send(&e)
 	// TODO: add trace
 	//ec.logger.Debug("events was sent to subscribers", zap.Int("num of subscribers", n))
@@ -207,8 +261,17 @@ func (ec *eth1Client) streamSmartContractEvents() error {
 		return errors.Wrap(err, "Failed to subscribe to logs")
 	}
 
+	// catching up with logs that were emitted before the subscription was created, e.g. after reconnection
+	if ec.getLastBlock() > 0 {
+		if err := ec.pollLogs(ec.conn, contractAbi); err != nil {
+			ec.logger.Warn("could not catch up with contract events", zap.Error(err))
+		}
+	}
+
 	go func() {
 		if err := ec.listenToSubscription(logs, sub, contractAbi); err != nil {
+			stopFallback := ec.startHTTPFallback(contractAbi)
+			defer stopFallback()
 			ec.reconnect()
 		}
 	}()
@@ -240,7 +303,7 @@ func (ec *eth1Client) listenToSubscription(logs chan types.Log, sub ethereum.Sub
 			return err
 		case vLog := <-logs:
 			ec.logger.Debug("received contract event from stream")
-			err := ec.handleEvent(vLog, contractAbi)
+			_, err := ec.processLog(vLog, contractAbi)
 			if err != nil {
 				ec.logger.Error("Failed to handle event", zap.Error(err))
 				continue
@@ -303,10 +366,15 @@ func (ec *eth1Client) syncSmartContractsEvents(fromBlock *big.Int) error {
 		}
 		fromBlock = toBlock
 	}
+	ec.setLastBlock(currentBlock)
+	// shares that couldn't be decrypted are retried before the sync ends, so they are handled as part of the sync
+	if err := ec.retryPendingDecrypt(contractAbi); err != nil {
+		ec.logger.Warn("could not retry pending-decrypt events", zap.Error(err))
+	}
 	ec.logger.Debug("finished syncing registry contract",
 		zap.Int("total events", len(logs)), zap.Int("total success", nSuccess))
 	// publishing SyncEndedEvent so other components could track the sync
-	ec.fireEvent(types.Log{}, eth1.SyncEndedEvent{Logs: logs, Success: nSuccess == len(logs)})
+	ec.fireEvent(types.Log{}, "", eth1.SyncEndedEvent{Logs: logs, Success: nSuccess == len(logs)})
 
 	return nil
 }
@@ -333,6 +401,7 @@ func (ec *eth1Client) fetchAndProcessEvents(fromBlock, toBlock *big.Int, contrac
 
 	for _, vLog := range logs {
 		err := ec.handleEvent(vLog, contractAbi)
+		ec.markProcessed(vLog)
 		if err != nil {
 			nSuccess--
 			ec.logger.Error("Failed to handle event during sync", zap.Error(err))
@@ -345,45 +414,84 @@ func (ec *eth1Client) fetchAndProcessEvents(fromBlock, toBlock *big.Int, contrac
 	return logs, nSuccess, nil
 }
 
+// handleEvent decodes the given log and fires the event,
+// events that couldn't be decoded or decrypted are quarantined (if possible)
 func (ec *eth1Client) handleEvent(vLog types.Log, contractAbi abi.ABI) error {
+	name, data, fire, err := ec.decodeEvent(vLog, contractAbi)
+	if err != nil {
+		reason, pubKey, ok := quarantineReason(err)
+		if !ok {
+			return err
+		}
+		if len(name) == 0 && ec.quarantineStorage == nil { // unknown event -> ignored
+			ec.logger.Warn("failed to find event type", zap.Error(err), zap.String("txHash", vLog.TxHash.Hex()))
+			return nil
+		}
+		return ec.quarantine(vLog, name, reason, pubKey, err)
+	}
+	if fire {
+		ec.fireEvent(vLog, name, data)
+	}
+	return nil
+}
+
+// decodeEvent decodes the given log, returns the event name, the parsed data and whether it should be fired.
+// decoding and validation errors are returned as eth1.MalformedEventError
+func (ec *eth1Client) decodeEvent(vLog types.Log, contractAbi abi.ABI) (string, interface{}, bool, error) {
+	if len(vLog.Topics) == 0 {
+		return "", nil, false, &eth1.MalformedEventError{Err: errors.New("missing event topic")}
+	}
 	eventType, err := contractAbi.EventByID(vLog.Topics[0])
-	if err != nil { // unknown event -> ignored
-		ec.logger.Warn("failed to find event type", zap.Error(err), zap.String("txHash", vLog.TxHash.Hex()))
-		return nil
+	if err != nil {
+		return "", nil, false, &eth1.MalformedEventError{Err: errors.Wrap(err, "unknown event")}
 	}
 	shareEncryptionKey, found, err := ec.shareEncryptionKeyProvider()
 	if !found {
-		return errors.New("failed to find operator private key")
+		return "", nil, false, errors.New("failed to find operator private key")
 	}
 	if err != nil {
-		return errors.Wrap(err, "failed to get operator private key")
+		return "", nil, false, errors.Wrap(err, "failed to get operator private key")
 	}
 
 	switch eventName := eventType.Name; eventName {
 	case "OperatorAdded":
 		parsed, isEventBelongsToOperator, err := eth1.ParseOperatorAddedEvent(ec.logger, shareEncryptionKey, vLog.Data, contractAbi)
 		if err != nil {
-			return errors.Wrap(err, "failed to parse OperatorAdded event")
+			return eventName, nil, false, &eth1.MalformedEventError{Err: errors.Wrap(err, "failed to parse OperatorAdded event")}
 		}
 		// if there is no operator-private-key --> assuming that the event should be triggered (e.g. exporter)
-		if isEventBelongsToOperator || shareEncryptionKey == nil {
-			ec.fireEvent(vLog, *parsed)
-		}
+		return eventName, *parsed, isEventBelongsToOperator || shareEncryptionKey == nil, nil
 	case "ValidatorAdded":
 		parsed, isEventBelongsToOperator, err := eth1.ParseValidatorAddedEvent(ec.logger, shareEncryptionKey, vLog.Data, contractAbi)
 		if err != nil {
-			return errors.Wrap(err, "failed to parse ValidatorAdded event")
+			var decryptionErr *eth1.ShareDecryptionError
+			if errors.As(err, &decryptionErr) {
+				return eventName, nil, false, err
+			}
+			return eventName, nil, false, &eth1.MalformedEventError{Err: errors.Wrap(err, "failed to parse ValidatorAdded event")}
 		}
 		if isEventBelongsToOperator {
 			ec.logger.Debug("validator is assigned to this operator",
 				zap.String("pubKey", hex.EncodeToString(parsed.PublicKey)))
 		}
 		// if there is no operator-private-key --> assuming that the event should be triggered (e.g. exporter)
-		if isEventBelongsToOperator || shareEncryptionKey == nil {
-			ec.fireEvent(vLog, *parsed)
+		return eventName, *parsed, isEventBelongsToOperator || shareEncryptionKey == nil, nil
+	case "ValidatorExitRequested":
+		parsed, err := eth1.ParseValidatorExitRequestedEvent(ec.logger, vLog.Data, contractAbi)
+		if err != nil {
+			return eventName, nil, false, &eth1.MalformedEventError{Err: errors.Wrap(err, "failed to parse ValidatorExitRequested event")}
 		}
+		// the validator controller decides whether the validator belongs to this operator
+		return eventName, *parsed, true, nil
+	case "ValidatorRemoved":
+		parsed, err := eth1.ParseValidatorRemovedEvent(ec.logger, vLog.Data, contractAbi)
+		if err != nil {
+			return eventName, nil, false, &eth1.MalformedEventError{Err: errors.Wrap(err, "failed to parse ValidatorRemoved event")}
+		}
+		// the validator controller decides whether the validator belongs to this operator
+		return eventName, *parsed, true, nil
 	default:
 		ec.logger.Debug("unknown contract event was received")
+		return eventName, nil, false, nil
 	}
-	return nil
 }
@@ -3,13 +3,20 @@ package goeth
 import (
 	"context"
 	"crypto/rsa"
+	"encoding/hex"
 	"encoding/json"
 	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/prysmaticlabs/prysm/async/event"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"math/big"
 	"strings"
 	"sync"
 	"testing"
@@ -60,6 +67,166 @@ func TestEth1Client_handleEvent(t *testing.T) {
 	eventsWg.Wait()
 }
 
+func TestEth1Client_processLog(t *testing.T) {
+	ec := newEth1Client()
+	contractAbi, err := abi.JSON(strings.NewReader(eth1.ContractABI()))
+	require.NoError(t, err)
+	var vLogOperatorAdded types.Log
+	require.NoError(t, json.Unmarshal([]byte(rawOperatorAdded), &vLogOperatorAdded))
+	var vLogValidatorAdded types.Log
+	require.NoError(t, json.Unmarshal([]byte(rawValidatorAdded), &vLogValidatorAdded))
+
+	cn := make(chan *eth1.Event, 10)
+	sub := ec.EventsFeed().Subscribe(cn)
+	defer sub.Unsubscribe()
+
+	// logs that were processed during sync are not handled again
+	ec.markProcessed(vLogOperatorAdded)
+	require.Equal(t, vLogOperatorAdded.BlockNumber, ec.getLastBlock())
+	processed, err := ec.processLog(vLogOperatorAdded, contractAbi)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	processed, err = ec.processLog(vLogValidatorAdded, contractAbi)
+	require.NoError(t, err)
+	require.True(t, processed)
+	require.Equal(t, vLogValidatorAdded.BlockNumber, ec.getLastBlock())
+	processed, err = ec.processLog(vLogValidatorAdded, contractAbi)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	// last block is never lowered
+	ec.setLastBlock(vLogOperatorAdded.BlockNumber)
+	require.Equal(t, vLogValidatorAdded.BlockNumber, ec.getLastBlock())
+
+	require.Len(t, cn, 1)
+	e := <-cn
+	require.Equal(t, "ValidatorAdded", e.Name)
+}
+
+func TestEth1Client_Quarantine(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	ec := newEth1Client()
+	ec.contractABI = eth1.ContractABI()
+	ec.quarantineStorage = eth1.NewQuarantineStorage(db, []byte("test-quarantine-"))
+
+	var vLogOperatorAdded types.Log
+	require.NoError(t, json.Unmarshal([]byte(rawOperatorAdded), &vLogOperatorAdded))
+	var vLogValidatorAdded types.Log
+	require.NoError(t, json.Unmarshal([]byte(rawValidatorAdded), &vLogValidatorAdded))
+	// truncated data can't be decoded
+	vLogValidatorAdded.Data = vLogValidatorAdded.Data[:64]
+
+	cn := make(chan *eth1.Event, 10)
+	sub := ec.EventsFeed().Subscribe(cn)
+	defer sub.Unsubscribe()
+
+	// an outdated abi (w/o any event) is used to simulate a decoder that is fixed later
+	outdatedAbi, err := abi.JSON(strings.NewReader("[]"))
+	require.NoError(t, err)
+	require.NoError(t, ec.handleEvent(vLogOperatorAdded, outdatedAbi))
+	contractAbi, err := abi.JSON(strings.NewReader(eth1.ContractABI()))
+	require.NoError(t, err)
+	require.NoError(t, ec.handleEvent(vLogValidatorAdded, contractAbi))
+	require.Len(t, cn, 0)
+
+	events, err := ec.GetQuarantinedEvents("")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, vLogOperatorAdded.TxHash, events[0].Log.TxHash)
+	require.Equal(t, "", events[0].Name)
+	require.Equal(t, eth1.QuarantineMalformed, events[0].Reason)
+	require.Equal(t, "ValidatorAdded", events[1].Name)
+	require.Contains(t, events[1].Error, "failed to parse ValidatorAdded event")
+
+	replayed, err := ec.ReplayQuarantinedEvents("")
+	require.NoError(t, err)
+	require.Equal(t, 1, replayed)
+	require.Len(t, cn, 1)
+	e := <-cn
+	require.Equal(t, "OperatorAdded", e.Name)
+
+	events, err = ec.GetQuarantinedEvents("")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, vLogValidatorAdded.TxHash, events[0].Log.TxHash)
+}
+
+func TestEth1Client_PendingDecrypt(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	contractAbi, err := abi.JSON(strings.NewReader(eth1.ContractABI()))
+	require.NoError(t, err)
+
+	newKey := func() *rsa.PrivateKey {
+		_, skPem, err := rsaencryption.GenerateKeys()
+		require.NoError(t, err)
+		sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+		require.NoError(t, err)
+		return sk
+	}
+	sk, otherSk := newKey(), newKey()
+	operatorPubKey, err := rsaencryption.ExtractPublicKey(sk)
+	require.NoError(t, err)
+	// the share is assigned to the operator but encrypted with another key
+	encryptedKey, err := rsaencryption.EncodeKey(&otherSk.PublicKey, "share")
+	require.NoError(t, err)
+	stringArgs := abi.Arguments{{Type: contractAbi.Events["OperatorAdded"].Inputs[0].Type}}
+	packedOperatorPubKey, err := stringArgs.Pack(operatorPubKey)
+	require.NoError(t, err)
+	packedEncryptedKey, err := stringArgs.Pack(encryptedKey)
+	require.NoError(t, err)
+	validatorPubKey := make([]byte, 48)
+	validatorPubKey[0] = 1
+	data, err := contractAbi.Events["ValidatorAdded"].Inputs.Pack(common.HexToAddress("0x01"), validatorPubKey, []eth1.Oess{{
+		Index:             big.NewInt(0),
+		OperatorPublicKey: packedOperatorPubKey,
+		SharedPublicKey:   make([]byte, 48),
+		EncryptedKey:      packedEncryptedKey,
+	}})
+	require.NoError(t, err)
+	vLog := types.Log{Topics: []common.Hash{contractAbi.Events["ValidatorAdded"].ID}, Data: data, BlockNumber: 10}
+
+	ec := newEth1Client()
+	ec.quarantineStorage = eth1.NewQuarantineStorage(db, []byte("test-quarantine-"))
+	ec.shareEncryptionKeyProvider = func() (*rsa.PrivateKey, bool, error) {
+		return sk, true, nil
+	}
+	require.NoError(t, ec.handleEvent(vLog, contractAbi))
+	events, err := ec.GetQuarantinedEvents(eth1.QuarantinePendingDecrypt)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, hex.EncodeToString(validatorPubKey), events[0].PublicKey)
+	require.Equal(t, eth1.KeyFingerprint(sk), events[0].KeyFingerprint)
+	require.Equal(t, vLog.Data, events[0].Log.Data)
+
+	// not retried with the same operator key
+	require.NoError(t, ec.retryPendingDecrypt(contractAbi))
+	events, err = ec.GetQuarantinedEvents(eth1.QuarantinePendingDecrypt)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// retried after the operator key was changed
+	ec.shareEncryptionKeyProvider = func() (*rsa.PrivateKey, bool, error) {
+		return otherSk, true, nil
+	}
+	require.NoError(t, ec.retryPendingDecrypt(contractAbi))
+	events, err = ec.GetQuarantinedEvents("")
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+}
+
 func newEth1Client() *eth1Client {
 	ec := eth1Client{
 		ctx:    context.TODO(),
@@ -70,6 +237,7 @@ func newEth1Client() *eth1Client {
 		},
 		eventsFeed: new(event.Feed),
 	}
+	ec.processedLogs, _ = lru.New(processedLogsCacheSize)
 	return &ec
 }
 
@@ -0,0 +1,176 @@
+package goeth
+
+import (
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	metricsQuarantinedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:eth1:quarantined_events",
+		Help: "Count of contract events that were quarantined as they couldn't be decoded, validated or decrypted",
+	}, []string{"event", "reason"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsQuarantinedEvents); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// verifies that the client implements Quarantine
+var _ eth1.Quarantine = &eth1Client{}
+
+// quarantineReason returns the quarantine reason of the given decoding error,
+// and the validator public key in case of share decryption error
+func quarantineReason(err error) (eth1.QuarantineReason, []byte, bool) {
+	var decryptionErr *eth1.ShareDecryptionError
+	if errors.As(err, &decryptionErr) {
+		return eth1.QuarantinePendingDecrypt, decryptionErr.PublicKey, true
+	}
+	var malformedErr *eth1.MalformedEventError
+	if errors.As(err, &malformedErr) {
+		return eth1.QuarantineMalformed, nil, true
+	}
+	return "", nil, false
+}
+
+// quarantine saves the given event, returns the cause if the event couldn't be quarantined
+func (ec *eth1Client) quarantine(vLog types.Log, name string, reason eth1.QuarantineReason, pubKey []byte, cause error) error {
+	if ec.quarantineStorage == nil {
+		return cause
+	}
+	logger := ec.logger.With(zap.String("txHash", vLog.TxHash.Hex()), zap.Uint("logIndex", vLog.Index),
+		zap.Uint64("blockNumber", vLog.BlockNumber), zap.String("event", name), zap.String("reason", string(reason)))
+	e := &eth1.QuarantinedEvent{
+		Log:           vLog,
+		Reason:        reason,
+		Name:          name,
+		Error:         cause.Error(),
+		QuarantinedAt: time.Now().Unix(),
+	}
+	if reason == eth1.QuarantinePendingDecrypt {
+		e.PublicKey = hex.EncodeToString(pubKey)
+		e.KeyFingerprint = ec.keyFingerprint()
+	}
+	if err := ec.quarantineStorage.SaveQuarantinedEvent(e); err != nil {
+		logger.Error("could not quarantine event", zap.Error(err))
+		return cause
+	}
+	label := name
+	if len(label) == 0 {
+		label = "unknown"
+	}
+	metricsQuarantinedEvents.WithLabelValues(label, string(reason)).Inc()
+	logger.Warn("event was quarantined", zap.Error(cause))
+	return nil
+}
+
+// keyFingerprint returns the fingerprint of the current operator key, or an empty string if not available
+func (ec *eth1Client) keyFingerprint() string {
+	sk, found, err := ec.shareEncryptionKeyProvider()
+	if err != nil || !found || sk == nil {
+		return ""
+	}
+	return eth1.KeyFingerprint(sk)
+}
+
+// GetQuarantinedEvents returns the quarantined events of the given reason, or all events if reason is empty
+func (ec *eth1Client) GetQuarantinedEvents(reason eth1.QuarantineReason) ([]*eth1.QuarantinedEvent, error) {
+	if ec.quarantineStorage == nil {
+		return nil, errors.New("events quarantine is not configured")
+	}
+	events, err := ec.quarantineStorage.GetQuarantinedEvents()
+	if err != nil {
+		return nil, err
+	}
+	if len(reason) == 0 {
+		return events, nil
+	}
+	filtered := make([]*eth1.QuarantinedEvent, 0, len(events))
+	for _, e := range events {
+		if e.Reason == reason {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered, nil
+}
+
+// ReplayQuarantinedEvents decodes the quarantined events of the given reason once again, in the order of the chain.
+// events that were decoded successfully are fired and removed from quarantine,
+// the error of events that still can't be decoded is updated
+func (ec *eth1Client) ReplayQuarantinedEvents(reason eth1.QuarantineReason) (int, error) {
+	events, err := ec.GetQuarantinedEvents(reason)
+	if err != nil {
+		return 0, err
+	}
+	contractAbi, err := abi.JSON(strings.NewReader(ec.contractABI))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse ABI interface")
+	}
+	return ec.replay(events, contractAbi)
+}
+
+// retryPendingDecrypt replays the pending-decrypt events that failed with a different operator key,
+// i.e. shares are retried automatically after an operator key rotation
+func (ec *eth1Client) retryPendingDecrypt(contractAbi abi.ABI) error {
+	if ec.quarantineStorage == nil {
+		return nil
+	}
+	events, err := ec.GetQuarantinedEvents(eth1.QuarantinePendingDecrypt)
+	if err != nil {
+		return err
+	}
+	fingerprint := ec.keyFingerprint()
+	var toRetry []*eth1.QuarantinedEvent
+	for _, e := range events {
+		if e.KeyFingerprint != fingerprint {
+			toRetry = append(toRetry, e)
+		}
+	}
+	if len(toRetry) == 0 {
+		return nil
+	}
+	ec.logger.Info("operator key was changed, retrying pending-decrypt events", zap.Int("count", len(toRetry)))
+	_, err = ec.replay(toRetry, contractAbi)
+	return err
+}
+
+// replay decodes the given quarantined events, returns the number of events that were replayed
+func (ec *eth1Client) replay(events []*eth1.QuarantinedEvent, contractAbi abi.ABI) (int, error) {
+	var replayed int
+	for _, e := range events {
+		logger := ec.logger.With(zap.String("txHash", e.Log.TxHash.Hex()), zap.Uint("logIndex", e.Log.Index))
+		name, data, fire, err := ec.decodeEvent(e.Log, contractAbi)
+		if err != nil {
+			reason, pubKey, ok := quarantineReason(err)
+			if !ok {
+				return replayed, err
+			}
+			logger.Debug("quarantined event still can't be decoded", zap.Error(err))
+			if err := ec.quarantine(e.Log, name, reason, pubKey, err); err != nil {
+				return replayed, errors.Wrap(err, "could not update quarantined event")
+			}
+			continue
+		}
+		if err := ec.quarantineStorage.RemoveQuarantinedEvent(e.Log.TxHash, e.Log.Index); err != nil {
+			return replayed, errors.Wrap(err, "could not remove quarantined event")
+		}
+		if fire {
+			ec.fireEvent(e.Log, name, data)
+		}
+		replayed++
+		logger.Info("quarantined event was replayed", zap.String("event", name), zap.String("reason", string(e.Reason)))
+	}
+	return replayed, nil
+}
@@ -0,0 +1,157 @@
+package eth1
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// QuarantineReason is the reason of an event to be quarantined
+type QuarantineReason string
+
+const (
+	// QuarantineMalformed is the reason of events that couldn't be decoded or validated
+	QuarantineMalformed QuarantineReason = "malformed"
+	// QuarantinePendingDecrypt is the reason of ValidatorAdded events with a share
+	// that couldn't be decrypted with the operator key
+	QuarantinePendingDecrypt QuarantineReason = "pending-decrypt"
+)
+
+// QuarantinedEvent is a contract event that couldn't be handled
+type QuarantinedEvent struct {
+	Log    types.Log        `json:"log"`
+	Reason QuarantineReason `json:"reason"`
+	// Name is the name of the contract event, empty if the event is unknown
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+	// QuarantinedAt is the unix time (seconds) of the last failure
+	QuarantinedAt int64 `json:"quarantinedAt"`
+	// PublicKey is the validator public key (hex), available for pending-decrypt events
+	PublicKey string `json:"publicKey,omitempty"`
+	// KeyFingerprint is the fingerprint of the operator key that failed to decrypt the share
+	KeyFingerprint string `json:"keyFingerprint,omitempty"`
+}
+
+// ShareDecryptionError is returned when a share that was assigned to this operator couldn't be decrypted
+type ShareDecryptionError struct {
+	// PublicKey is the validator public key
+	PublicKey []byte
+	Err       error
+}
+
+func (e *ShareDecryptionError) Error() string {
+	return e.Err.Error()
+}
+
+// MalformedEventError is returned when a contract event couldn't be decoded or validated
+type MalformedEventError struct {
+	Err error
+}
+
+func (e *MalformedEventError) Error() string {
+	return e.Err.Error()
+}
+
+// QuarantineStorage represents the interface for storing quarantined events
+type QuarantineStorage interface {
+	// SaveQuarantinedEvent saves the given event, overrides existing entry of the same log
+	SaveQuarantinedEvent(e *QuarantinedEvent) error
+	// GetQuarantinedEvents returns all the quarantined events
+	GetQuarantinedEvents() ([]*QuarantinedEvent, error)
+	// RemoveQuarantinedEvent removes the event of the given log
+	RemoveQuarantinedEvent(txHash common.Hash, logIndex uint) error
+}
+
+// Quarantine is implemented by clients that quarantine events that couldn't be handled
+type Quarantine interface {
+	// GetQuarantinedEvents returns the quarantined events of the given reason, or all events if reason is empty
+	GetQuarantinedEvents(reason QuarantineReason) ([]*QuarantinedEvent, error)
+	// ReplayQuarantinedEvents decodes the quarantined events of the given reason (or all if empty) once again,
+	// e.g. after a decoder fix. events that were decoded successfully are fired and removed from quarantine.
+	// returns the number of events that were replayed
+	ReplayQuarantinedEvents(reason QuarantineReason) (int, error)
+}
+
+// KeyFingerprint returns the fingerprint (hex of sha256) of the public key of the given operator key
+func KeyFingerprint(sk *rsa.PrivateKey) string {
+	h := sha256.Sum256(x509.MarshalPKCS1PublicKey(&sk.PublicKey))
+	return hex.EncodeToString(h[:])
+}
+
+type quarantineStorage struct {
+	db     basedb.IDb
+	prefix []byte
+	lock   sync.RWMutex
+}
+
+// NewQuarantineStorage creates a new instance of QuarantineStorage, events are stored under the given prefix
+func NewQuarantineStorage(db basedb.IDb, prefix []byte) QuarantineStorage {
+	return &quarantineStorage{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+// SaveQuarantinedEvent saves the given event, overrides existing entry of the same log
+func (qs *quarantineStorage) SaveQuarantinedEvent(e *QuarantinedEvent) error {
+	qs.lock.Lock()
+	defer qs.lock.Unlock()
+
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal quarantined event")
+	}
+	return qs.db.Set(qs.prefix, quarantineKey(e.Log.TxHash, e.Log.Index), raw)
+}
+
+// GetQuarantinedEvents returns all the quarantined events, ordered by block number and log index
+func (qs *quarantineStorage) GetQuarantinedEvents() ([]*QuarantinedEvent, error) {
+	qs.lock.RLock()
+	defer qs.lock.RUnlock()
+
+	objs, err := qs.db.GetAllByCollection(qs.prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get quarantined events")
+	}
+	events := make([]*QuarantinedEvent, 0, len(objs))
+	for _, obj := range objs {
+		e := &QuarantinedEvent{}
+		if err := json.Unmarshal(obj.Value, e); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal quarantined event")
+		}
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Log.BlockNumber != events[j].Log.BlockNumber {
+			return events[i].Log.BlockNumber < events[j].Log.BlockNumber
+		}
+		return events[i].Log.Index < events[j].Log.Index
+	})
+	return events, nil
+}
+
+// RemoveQuarantinedEvent removes the event of the given log
+func (qs *quarantineStorage) RemoveQuarantinedEvent(txHash common.Hash, logIndex uint) error {
+	qs.lock.Lock()
+	defer qs.lock.Unlock()
+
+	return qs.db.Delete(qs.prefix, quarantineKey(txHash, logIndex))
+}
+
+// quarantineKey returns the key of the event of the given log
+func quarantineKey(txHash common.Hash, logIndex uint) []byte {
+	key := make([]byte, common.HashLength+8)
+	copy(key, txHash.Bytes())
+	binary.BigEndian.PutUint64(key[common.HashLength:], uint64(logIndex))
+	return key
+}
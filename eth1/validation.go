@@ -0,0 +1,66 @@
+package eth1
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// blsPubKeySize is the size of a serialized bls public key
+const blsPubKeySize = 48
+
+// validateOperatorAddedEvent checks the fields of the given (decoded) event
+func validateOperatorAddedEvent(e *OperatorAddedEvent) error {
+	if len(e.PublicKey) == 0 {
+		return errors.New("missing operator public key")
+	}
+	if e.OwnerAddress == (common.Address{}) {
+		return errors.New("missing owner address")
+	}
+	return nil
+}
+
+// validateValidatorAddedEvent checks the fields of the given event, before the shares are decrypted
+func validateValidatorAddedEvent(e *ValidatorAddedEvent) error {
+	if len(e.PublicKey) != blsPubKeySize {
+		return errors.Errorf("invalid validator public key size: %d", len(e.PublicKey))
+	}
+	if len(e.OessList) == 0 {
+		return errors.New("empty oess list")
+	}
+	indices := make(map[string]bool, len(e.OessList))
+	for _, oess := range e.OessList {
+		if oess.Index == nil {
+			return errors.New("missing oess index")
+		}
+		if indices[oess.Index.String()] {
+			return errors.Errorf("duplicated oess index: %s", oess.Index.String())
+		}
+		indices[oess.Index.String()] = true
+		if len(oess.OperatorPublicKey) == 0 {
+			return errors.Errorf("missing operator public key of oess %s", oess.Index.String())
+		}
+		if len(oess.SharedPublicKey) != blsPubKeySize {
+			return errors.Errorf("invalid shared public key size of oess %s: %d", oess.Index.String(), len(oess.SharedPublicKey))
+		}
+		if len(oess.EncryptedKey) == 0 {
+			return errors.Errorf("missing encrypted key of oess %s", oess.Index.String())
+		}
+	}
+	return nil
+}
+
+// validateValidatorExitRequestedEvent checks the fields of the given event
+func validateValidatorExitRequestedEvent(e *ValidatorExitRequestedEvent) error {
+	if len(e.PublicKey) != blsPubKeySize {
+		return errors.Errorf("invalid validator public key size: %d", len(e.PublicKey))
+	}
+	return nil
+}
+
+// validateValidatorRemovedEvent checks the fields of the given event
+func validateValidatorRemovedEvent(e *ValidatorRemovedEvent) error {
+	if len(e.PublicKey) != blsPubKeySize {
+		return errors.Errorf("invalid validator public key size: %d", len(e.PublicKey))
+	}
+	return nil
+}
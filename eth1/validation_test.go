@@ -0,0 +1,47 @@
+package eth1
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateValidatorAddedEvent(t *testing.T) {
+	validOess := func(index int64) Oess {
+		return Oess{
+			Index:             big.NewInt(index),
+			OperatorPublicKey: []byte("operator"),
+			SharedPublicKey:   make([]byte, blsPubKeySize),
+			EncryptedKey:      []byte("encrypted"),
+		}
+	}
+	tests := []struct {
+		name string
+		e    *ValidatorAddedEvent
+		err  string
+	}{
+		{"valid", &ValidatorAddedEvent{PublicKey: make([]byte, blsPubKeySize), OessList: []Oess{validOess(0), validOess(1)}}, ""},
+		{"invalid public key", &ValidatorAddedEvent{PublicKey: make([]byte, 20), OessList: []Oess{validOess(0)}},
+			"invalid validator public key size: 20"},
+		{"empty oess list", &ValidatorAddedEvent{PublicKey: make([]byte, blsPubKeySize)}, "empty oess list"},
+		{"duplicated index", &ValidatorAddedEvent{PublicKey: make([]byte, blsPubKeySize), OessList: []Oess{validOess(1), validOess(1)}},
+			"duplicated oess index: 1"},
+		{"invalid shared public key", &ValidatorAddedEvent{PublicKey: make([]byte, blsPubKeySize), OessList: []Oess{{
+			Index: big.NewInt(2), OperatorPublicKey: []byte("operator"), SharedPublicKey: []byte{1}, EncryptedKey: []byte("encrypted"),
+		}}}, "invalid shared public key size of oess 2: 1"},
+		{"missing encrypted key", &ValidatorAddedEvent{PublicKey: make([]byte, blsPubKeySize), OessList: []Oess{{
+			Index: big.NewInt(3), OperatorPublicKey: []byte("operator"), SharedPublicKey: make([]byte, blsPubKeySize),
+		}}}, "missing encrypted key of oess 3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateValidatorAddedEvent(test.e)
+			if len(test.err) == 0 {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
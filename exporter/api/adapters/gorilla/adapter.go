@@ -1,6 +1,7 @@
 package gorilla
 
 import (
+	"encoding/json"
 	"github.com/bloxapp/ssv/exporter/api"
 	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
@@ -8,6 +9,15 @@ import (
 	"net/http"
 )
 
+const (
+	// ProtocolJSON is the default sub-protocol, messages are sent as json text frames
+	ProtocolJSON = "json"
+	// ProtocolMsgPack is a sub-protocol where messages are sent as msgpack binary frames
+	ProtocolMsgPack = "msgpack"
+	// maxRequestSize is the max size of inbound messages (requests), it also bounds the nesting of decoded messages
+	maxRequestSize = 64 << 10
+)
+
 type gorillaAdapter struct {
 	logger *zap.Logger
 }
@@ -16,6 +26,10 @@ type gorillaAdapter struct {
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 4096,
+	// permessage-deflate is used if the client supports it
+	EnableCompression: true,
+	// the frame format is negotiated with the client by Sec-WebSocket-Protocol, json is the default
+	Subprotocols: []string{ProtocolJSON, ProtocolMsgPack},
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
@@ -37,7 +51,8 @@ func (ga *gorillaAdapter) RegisterHandler(mux *http.ServeMux, endPoint string, h
 			logger.Error("could not upgrade connection", zap.Error(err))
 			return
 		}
-		logger.Debug("new websocket connection")
+		logger.Debug("new websocket connection", zap.String("protocol", conn.Subprotocol()))
+		conn.SetReadLimit(maxRequestSize)
 		defer func() {
 			logger.Debug("closing connection")
 			err := conn.Close()
@@ -54,6 +69,13 @@ func (ga *gorillaAdapter) Send(conn api.Connection, v interface{}) error {
 	if !ok {
 		return errors.Errorf("connection object does not fit to adapter")
 	}
+	if c.Subprotocol() == ProtocolMsgPack {
+		data, err := marshalMsgPack(v)
+		if err != nil {
+			return errors.Wrap(err, "could not encode msgpack")
+		}
+		return c.WriteMessage(websocket.BinaryMessage, data)
+	}
 	return c.WriteJSON(v)
 }
 
@@ -62,7 +84,15 @@ func (ga *gorillaAdapter) Receive(conn api.Connection, v interface{}) error {
 	if !ok {
 		return errors.Errorf("connection object does not fit to adapter")
 	}
-	return c.ReadJSON(v)
+	msgType, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	// binary frames are accepted from any client, regardless of the negotiated sub-protocol
+	if msgType == websocket.BinaryMessage {
+		return unmarshalMsgPack(data, v)
+	}
+	return json.Unmarshal(data, v)
 }
 
 // IsCloseError returns true if the error originate as part of some close procedure
@@ -0,0 +1,107 @@
+package gorilla
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMsgPack(t *testing.T) {
+	msg := api.Message{
+		Type:   api.TypeDecided,
+		Filter: api.MessageFilter{From: 1, To: 1 << 40, PublicKey: strings.Repeat("a", 96)},
+		Data: []interface{}{
+			map[string]interface{}{"round": -3, "ratio": 0.5, "valid": true, "missing": nil},
+			strings.Repeat("b", 70000),
+		},
+	}
+	data, err := marshalMsgPack(&msg)
+	require.NoError(t, err)
+
+	var decoded api.Message
+	require.NoError(t, unmarshalMsgPack(data, &decoded))
+	require.Equal(t, msg.Type, decoded.Type)
+	require.Equal(t, msg.Filter, decoded.Filter)
+	items := decoded.Data.([]interface{})
+	require.Len(t, items, 2)
+	require.Equal(t, map[string]interface{}{"round": int64(-3), "ratio": 0.5, "valid": true, "missing": nil}, items[0])
+	require.Equal(t, msg.Data.([]interface{})[1], items[1])
+
+	// raw json is encoded by its value
+	type event struct {
+		Data json.RawMessage `json:"data"`
+	}
+	data, err = marshalMsgPack(&event{Data: json.RawMessage(`{"index":12,"keys":["a","b"]}`)})
+	require.NoError(t, err)
+	var decodedEvent map[string]interface{}
+	require.NoError(t, unmarshalMsgPack(data, &decodedEvent))
+	require.Equal(t, map[string]interface{}{"index": int64(12), "keys": []interface{}{"a", "b"}}, decodedEvent["data"])
+	var decodedRaw event
+	require.NoError(t, unmarshalMsgPack(data, &decodedRaw))
+	require.JSONEq(t, `{"index":12,"keys":["a","b"]}`, string(decodedRaw.Data))
+
+	require.Error(t, unmarshalMsgPack(data[:len(data)/2], &decoded))
+}
+
+func TestAdapter_Protocols(t *testing.T) {
+	adapter := NewGorillaAdapter(zap.L())
+	mux := http.NewServeMux()
+	adapter.RegisterHandler(mux, "/echo", func(conn api.Connection) {
+		var msg api.Message
+		require.NoError(t, adapter.Receive(conn, &msg))
+		require.NoError(t, adapter.Send(conn, &msg))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/echo"
+
+	tests := []struct {
+		protocol string
+		msgType  int
+	}{
+		{"", websocket.TextMessage},
+		{ProtocolJSON, websocket.TextMessage},
+		{ProtocolMsgPack, websocket.BinaryMessage},
+	}
+	for _, test := range tests {
+		t.Run("protocol "+test.protocol, func(t *testing.T) {
+			dialer := websocket.Dialer{EnableCompression: true}
+			if len(test.protocol) > 0 {
+				dialer.Subprotocols = []string{test.protocol}
+			}
+			conn, res, err := dialer.Dial(url, nil)
+			require.NoError(t, err)
+			defer func() {
+				_ = conn.Close()
+			}()
+			require.Equal(t, test.protocol, conn.Subprotocol())
+			require.Contains(t, res.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+
+			req := api.Message{Type: api.TypeValidator, Filter: api.MessageFilter{From: 1, To: 10}}
+			if test.msgType == websocket.BinaryMessage {
+				data, err := marshalMsgPack(&req)
+				require.NoError(t, err)
+				require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, data))
+			} else {
+				require.NoError(t, conn.WriteJSON(&req))
+			}
+			msgType, data, err := conn.ReadMessage()
+			require.NoError(t, err)
+			require.Equal(t, test.msgType, msgType)
+			var reply api.Message
+			if msgType == websocket.BinaryMessage {
+				require.NoError(t, unmarshalMsgPack(data, &reply))
+			} else {
+				require.NoError(t, json.Unmarshal(data, &reply))
+			}
+			require.Equal(t, req, reply)
+		})
+	}
+}
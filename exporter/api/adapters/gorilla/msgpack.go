@@ -0,0 +1,66 @@
+package gorilla
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	// raw json (e.g. the data of contract events) is sent as its msgpack equivalent rather than as bytes
+	msgpack.Register(json.RawMessage{}, encodeRawJSON, decodeRawJSON)
+}
+
+// marshalMsgPack encodes the given value as msgpack, json tags are respected so the structure matches json frames
+func marshalMsgPack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	// json doesn't distinguish integral floats from integers
+	enc.UseCompactFloats(true)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalMsgPack decodes the given msgpack data into v, json tags are respected.
+// generic values are decoded as in json, except for integers that are decoded as int64 (or uint64)
+func unmarshalMsgPack(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	dec.UseLooseInterfaceDecoding(true)
+	if err := dec.Decode(v); err != nil {
+		return errors.Wrap(err, "could not decode msgpack")
+	}
+	return nil
+}
+
+func encodeRawJSON(enc *msgpack.Encoder, v reflect.Value) error {
+	raw := v.Bytes()
+	if len(raw) == 0 {
+		return enc.EncodeNil()
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return err
+	}
+	return enc.Encode(generic)
+}
+
+func decodeRawJSON(dec *msgpack.Decoder, v reflect.Value) error {
+	generic, err := dec.DecodeInterfaceLoose()
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	v.SetBytes(raw)
+	return nil
+}
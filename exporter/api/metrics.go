@@ -1,6 +1,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -18,11 +20,61 @@ var (
 		Name: "ssv:exporter:stream_outbound_errors",
 		Help: "count the outbound messages failures on stream channel",
 	}, []string{"cid"})
+	metricClientsCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:exporter:ws_clients",
+		Help: "count the connected websocket clients by end point",
+	}, []string{"endPoint"})
+	metricQueryInboundCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:exporter:query_inbound",
+		Help: "count the inbound messages on query channel by client",
+	}, []string{"cid"})
+	metricQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv:exporter:query_duration_seconds",
+		Help:    "Time to handle and respond to a query by query type",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"type"})
+	metricErrorsCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:exporter:ws_errors",
+		Help: "count the websocket errors by end point and error class",
+	}, []string{"endPoint", "class"})
+)
+
+const (
+	endPointQuery  = "query"
+	endPointStream = "stream"
+
+	// errClassRead is a failure to read or decode an incoming message
+	errClassRead = "read"
+	// errClassSend is a failure to send an outgoing message
+	errClassSend = "send"
+	// errClassQuery is a query that was responded with an error message
+	errClassQuery = "query"
+	// errClassQueueFull is a stream client that didn't keep up with the outbound messages
+	errClassQueueFull = "queue_full"
 )
 
+// queryTypes are the known query types, other types are reported as unknown to keep the labels bounded
+var queryTypes = map[MessageType]bool{
+	TypeValidator:          true,
+	TypeValidatorsCount:    true,
+	TypeOperator:           true,
+	TypeCommittee:          true,
+	TypeDecided:            true,
+	TypeEvent:              true,
+	TypeBalanceHistory:     true,
+	TypeDecidedProof:       true,
+	TypeDecidedLatency:     true,
+	TypeGossipStats:        true,
+	TypeVersions:           true,
+	TypePeersSummary:       true,
+	TypeControl:            true,
+	TypeDecidedHistoryRoot: true,
+}
+
 func reportStreamOutbound(cid string, err error) {
 	if err != nil {
 		metricStreamOutboundErrorsCount.WithLabelValues(cid).Inc()
+		reportError(endPointStream, errClassSend)
 	} else {
 		metricStreamOutboundCount.WithLabelValues(cid).Inc()
 	}
@@ -35,3 +87,40 @@ func reportStreamOutboundQueueCount(cid string, inc bool) {
 		metricStreamOutboundQueueCount.WithLabelValues(cid).Dec()
 	}
 }
+
+// reportClientConnected tracks the connected clients, the returned func should be called once the client disconnects
+// in order to remove the per-client series
+func reportClientConnected(endPoint, cid string) func() {
+	metricClientsCount.WithLabelValues(endPoint).Inc()
+	return func() {
+		metricClientsCount.WithLabelValues(endPoint).Dec()
+		switch endPoint {
+		case endPointQuery:
+			metricQueryInboundCount.DeleteLabelValues(cid)
+		case endPointStream:
+			metricStreamOutboundQueueCount.DeleteLabelValues(cid)
+			metricStreamOutboundCount.DeleteLabelValues(cid)
+			metricStreamOutboundErrorsCount.DeleteLabelValues(cid)
+		}
+	}
+}
+
+func reportQueryInbound(cid string) {
+	metricQueryInboundCount.WithLabelValues(cid).Inc()
+}
+
+// reportQuery reports the duration of the given query, and counts queries that were responded with an error
+func reportQuery(msgType MessageType, start time.Time, res *Message) {
+	label := "unknown"
+	if queryTypes[msgType] {
+		label = string(msgType)
+	}
+	metricQueryDuration.WithLabelValues(label).Observe(time.Since(start).Seconds())
+	if res.Type == TypeError {
+		reportError(endPointQuery, errClassQuery)
+	}
+}
+
+func reportError(endPoint, class string) {
+	metricErrorsCount.WithLabelValues(endPoint, class).Inc()
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHandleQuery_Metrics(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	adapter := NewAdapterMock(logger).(*AdapterMock)
+	_, ipAddr, err := net.ParseCIDR("192.0.2.1/27")
+	require.NoError(t, err)
+	conn := connectionMock{addr: ipAddr}
+
+	ws := NewWsServer(logger, adapter, func(nm *NetworkMessage) {
+		if nm.Msg.Type != TypeOperator {
+			nm.Msg = Message{Type: TypeError, Data: []string{"bad request"}}
+		}
+	}, nil).(*wsServer)
+	queryErrors := testutil.ToFloat64(metricErrorsCount.WithLabelValues(endPointQuery, errClassQuery))
+	go ws.handleQuery(&conn)
+
+	adapter.In <- Message{Type: TypeOperator}
+	<-adapter.Out
+	adapter.In <- Message{Type: "unknown-type"}
+	<-adapter.Out
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metricErrorsCount.WithLabelValues(endPointQuery, errClassQuery)) == queryErrors+1
+	}, time.Second, 10*time.Millisecond)
+	// the unknown query type is reported with a bounded label
+	require.Equal(t, 2, testutil.CollectAndCount(metricQueryDuration))
+}
+
+func TestReportClientConnected(t *testing.T) {
+	cid := "conn-test"
+	done := reportClientConnected(endPointStream, cid)
+	clients := testutil.ToFloat64(metricClientsCount.WithLabelValues(endPointStream))
+	reportStreamOutbound(cid, nil)
+	require.Equal(t, float64(1), testutil.ToFloat64(metricStreamOutboundCount.WithLabelValues(cid)))
+
+	done()
+	require.Equal(t, clients-1, testutil.ToFloat64(metricClientsCount.WithLabelValues(endPointStream)))
+	// per-client series are removed once the client disconnects
+	require.Equal(t, float64(0), testutil.ToFloat64(metricStreamOutboundCount.WithLabelValues(cid)))
+}
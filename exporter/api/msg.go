@@ -24,6 +24,14 @@ type MessageFilter struct {
 	Role DutyRole `json:"role,omitempty"`
 	// PublicKey is optional, used for fetching decided messages or information about specific validator/operator
 	PublicKey string `json:"publicKey,omitempty"`
+	// OwnerAddress is optional, used for fetching the operators of the given owner (eth1) address
+	OwnerAddress string `json:"ownerAddress,omitempty"`
+	// OperatorPublicKey is optional, used for fetching the validators of the given operator
+	OperatorPublicKey string `json:"operatorPublicKey,omitempty"`
+	// Status is optional, used for fetching validators with the given status (unknown/pending/active/exited/slashed)
+	Status string `json:"status,omitempty"`
+	// HasIndex is optional, used for fetching validators with (or without) a beacon chain index
+	HasIndex *bool `json:"hasIndex,omitempty"`
 }
 
 // MessageType is the type of message being sent
@@ -32,10 +40,32 @@ type MessageType string
 const (
 	// TypeValidator is an enum for validator type messages
 	TypeValidator MessageType = "validator"
+	// TypeValidatorsCount is an enum for validators count (by status) messages
+	TypeValidatorsCount MessageType = "validatorsCount"
 	// TypeOperator is an enum for operator type messages
 	TypeOperator MessageType = "operator"
+	// TypeCommittee is an enum for the committee (operators, status and last decided sequence) of a validator
+	TypeCommittee MessageType = "committee"
 	// TypeDecided is an enum for ibft type messages
 	TypeDecided MessageType = "decided"
+	// TypeEvent is an enum for eth1 contract event type messages
+	TypeEvent MessageType = "event"
+	// TypeBalanceHistory is an enum for validator balance history messages, where from/to are epochs
+	TypeBalanceHistory MessageType = "balanceHistory"
+	// TypeDecidedProof is an enum for decided inclusion proof messages, where from is the sequence number
+	TypeDecidedProof MessageType = "decidedProof"
+	// TypeDecidedHistoryRoot is an enum for the merkle root over the decided history of a validator, where to is the last sequence number (optional)
+	TypeDecidedHistoryRoot MessageType = "decidedHistoryRoot"
+	// TypeDecidedLatency is an enum for decided messages timings and latency percentiles, where from/to are sequence numbers
+	TypeDecidedLatency MessageType = "decidedLatency"
+	// TypeGossipStats is an enum for gossipsub peer scores and mesh stats messages
+	TypeGossipStats MessageType = "gossipStats"
+	// TypeVersions is an enum for the versions distribution of operators and peers
+	TypeVersions MessageType = "versions"
+	// TypePeersSummary is an enum for the versions, countries and ASNs distribution of the peers that were recently seen
+	TypePeersSummary MessageType = "peersSummary"
+	// TypeControl is an enum for signed control messages that update the runtime configuration of exporters
+	TypeControl MessageType = "control"
 	// TypeError is an enum for error type messages
 	TypeError MessageType = "error"
 )
@@ -61,3 +91,79 @@ type ValidatorsMessage struct {
 type OperatorsMessage struct {
 	Data []storage.OperatorInformation `json:"data,omitempty"`
 }
+
+// Committee represents the data of committee response
+type Committee struct {
+	PublicKey string `json:"publicKey"`
+	Index     int64  `json:"index"`
+	// Status is the (simplified) status of the validator, according to its metadata
+	Status storage.ValidatorStatus `json:"status"`
+	// MetadataUpdatedAt is the time (unix milliseconds) of the last metadata update, 0 if was never updated
+	MetadataUpdatedAt int64               `json:"metadataUpdatedAt"`
+	Operators         []CommitteeOperator `json:"operators"`
+	// LastDecidedSeq is the sequence number of the highest decided instance of the role, nil if nothing was decided
+	LastDecidedSeq *uint64 `json:"lastDecidedSeq"`
+}
+
+// CommitteeOperator is an operator of a validator committee, name and metadata are empty if the operator is unknown
+type CommitteeOperator struct {
+	ID        uint64                    `json:"nodeId"`
+	PublicKey string                    `json:"publicKey"`
+	Name      string                    `json:"name"`
+	Metadata  *storage.OperatorMetadata `json:"metadata,omitempty"`
+}
+
+// DecidedLatency represents the data of decided latency response
+type DecidedLatency struct {
+	Timings []storage.DecidedTiming `json:"timings"`
+	Stats   storage.LatencyStats    `json:"stats"`
+}
+
+// DecidedHistoryRoot represents the data of decided history root response
+type DecidedHistoryRoot struct {
+	// Count is the number of decided instances in the history, i.e. sequences [0, Count)
+	Count uint64 `json:"count"`
+	// Root is the hex encoded merkle root over the history
+	Root string `json:"root"`
+}
+
+// VersionsSummary represents the data of versions response
+type VersionsSummary struct {
+	// Operators is based on the heartbeats of live operators
+	Operators []VersionCount `json:"operators"`
+	// Peers is based on the user agents of the peers that are connected to the exporter
+	Peers []VersionCount `json:"peers"`
+}
+
+// VersionCount is the number of nodes that run the given version, commit and fork
+type VersionCount struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	ForkID  string `json:"forkId,omitempty"`
+	Count   int    `json:"count"`
+}
+
+// PeersSummary represents the data of peers summary response
+type PeersSummary struct {
+	// Peers is the number of peers that were seen in the summary window
+	Peers int `json:"peers"`
+	// Connected is the number of peers that are currently connected
+	Connected int            `json:"connected"`
+	Versions  []VersionCount `json:"versions"`
+	// Countries and ASNs are available only if a geo db was configured
+	Countries []CountryCount `json:"countries,omitempty"`
+	ASNs      []ASNCount     `json:"asns,omitempty"`
+}
+
+// CountryCount is the number of peers in the given country
+type CountryCount struct {
+	Country string `json:"country"`
+	Count   int    `json:"count"`
+}
+
+// ASNCount is the number of peers in the given autonomous system
+type ASNCount struct {
+	ASN         uint32 `json:"asn"`
+	Description string `json:"description,omitempty"`
+	Count       int    `json:"count"`
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/hex"
+	"github.com/bloxapp/ssv/utils/errs"
+
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+)
+
+// ProofSigner is a committee member of a decided proof
+type ProofSigner struct {
+	// ID is the node id of the operator in the committee
+	ID uint64 `json:"nodeId"`
+	// PublicKey is the (hex) public key of the operator's share
+	PublicKey string `json:"publicKey"`
+	// OperatorPublicKey is the public key of the operator, as appeared in the contract
+	OperatorPublicKey string `json:"operatorPublicKey,omitempty"`
+}
+
+// DecidedProof is an inclusion proof of a decided message,
+// it holds everything that is needed to verify the message w/o trusting the exporter.
+// the committee should be cross checked with the shares that were published in the contract (ValidatorAdded event)
+type DecidedProof struct {
+	// Message is the decided message
+	Message *proto.SignedMessage `json:"message"`
+	// SigningRoot is the (hex) root that was signed by the committee
+	SigningRoot string `json:"signingRoot"`
+	// Signers are the committee members that signed the message
+	Signers []ProofSigner `json:"signers"`
+	// AggregatedPublicKey is the (hex) aggregation of the signers public keys, used to verify the message signature
+	AggregatedPublicKey string `json:"aggregatedPublicKey"`
+	// Committee holds all the members of the validator's committee
+	Committee []ProofSigner `json:"committee"`
+	// Threshold is the number of signers that is needed for a quorum
+	Threshold int `json:"threshold"`
+}
+
+// Verify checks that the proof is consistent and the message was signed by a quorum of the committee
+func (p *DecidedProof) Verify() error {
+	if p.Message == nil || p.Message.Message == nil {
+		return errors.New("missing decided message")
+	}
+	// decided messages are commit messages that are signed by a quorum
+	if p.Message.Message.Type != proto.RoundState_Commit {
+		return errors.New("message is not a decided message")
+	}
+	root, err := p.Message.Message.SigningRoot()
+	if err != nil {
+		return errors.Wrap(err, "could not compute signing root")
+	}
+	if hex.EncodeToString(root) != p.SigningRoot {
+		return errors.New("signing root mismatch")
+	}
+	if len(p.Signers) < p.Threshold || len(p.Signers) != len(p.Message.SignerIds) {
+		return errors.Errorf("not enough signers (%d/%d)", len(p.Signers), p.Threshold)
+	}
+	committee := make(map[uint64]string, len(p.Committee))
+	for _, member := range p.Committee {
+		committee[member.ID] = member.PublicKey
+	}
+	aggPK := bls.PublicKey{}
+	for i, signer := range p.Signers {
+		if signer.ID != p.Message.SignerIds[i] {
+			return errors.Errorf("unexpected signer %d", signer.ID)
+		}
+		if pk, ok := committee[signer.ID]; !ok || pk != signer.PublicKey {
+			return errors.Errorf("signer %d is not a committee member", signer.ID)
+		}
+		delete(committee, signer.ID) // signers must be unique
+		pk := bls.PublicKey{}
+		if err := pk.DeserializeHexStr(signer.PublicKey); err != nil {
+			return errors.Wrapf(err, "could not deserialize public key of signer %d", signer.ID)
+		}
+		aggPK.Add(&pk)
+	}
+	if aggPK.SerializeToHexStr() != p.AggregatedPublicKey {
+		return errors.New("aggregated public key mismatch")
+	}
+	sig := bls.Sign{}
+	if err := sig.Deserialize(p.Message.Signature); err != nil {
+		return errors.Wrap(err, "could not deserialize signature")
+	}
+	if !sig.VerifyByte(&aggPK, root) {
+		return errs.Mark(errors.New("invalid signature"), errs.ErrInvalidSignature)
+	}
+	return nil
+}
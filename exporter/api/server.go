@@ -76,6 +76,7 @@ func (ws *wsServer) handleQuery(conn Connection) {
 	}
 	cid := ConnectionID(conn)
 	logger := ws.logger.With(zap.String("cid", cid))
+	defer reportClientConnected(endPointQuery, cid)()
 
 	for {
 		var nm NetworkMessage
@@ -87,18 +88,23 @@ func (ws *wsServer) handleQuery(conn Connection) {
 				return
 			}
 			ws.logger.Warn("could not read incoming message", zap.Error(err))
+			reportError(endPointQuery, errClassRead)
 			nm = NetworkMessage{incoming, err, conn}
 		} else {
 			nm = NetworkMessage{incoming, nil, conn}
 		}
+		reportQueryInbound(cid)
+		start := time.Now()
 		// handler is processing the request
 		ws.handler(&nm)
 
 		err = tasks.Retry(func() error {
 			return ws.adapter.Send(conn, &nm.Msg)
 		}, 3)
+		reportQuery(incoming.Type, start, &nm.Msg)
 		if err != nil {
 			logger.Error("could not send message", zap.Error(err))
+			reportError(endPointQuery, errClassSend)
 			break
 		}
 	}
@@ -110,6 +116,7 @@ func (ws *wsServer) handleStream(conn Connection) {
 	logger := ws.logger.
 		With(zap.String("cid", cid))
 	defer logger.Debug("stream handler done")
+	defer reportClientConnected(endPointStream, cid)()
 	// messages are being collected into a slice and picked up in another goroutine.
 	//
 	// the reason is that messages cannot be sent on a different goroutine,
@@ -132,6 +139,7 @@ func (ws *wsServer) handleStream(conn Connection) {
 			case nm := <-cn:
 				if !q.enqueue(nm) {
 					logger.Error("queue is full, closing connection", zap.Any("msg", nm.Msg))
+					reportError(endPointStream, errClassQueueFull)
 					return
 				}
 				reportStreamOutboundQueueCount(cid, true)
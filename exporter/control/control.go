@@ -0,0 +1,119 @@
+package control
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// Sign signs the given control message with the admin key
+func Sign(sk *rsa.PrivateKey, msg *network.ControlMessage) error {
+	root, err := signingRoot(msg)
+	if err != nil {
+		return err
+	}
+	msg.Signature, err = rsaencryption.SignData(sk, root)
+	if err != nil {
+		return errors.Wrap(err, "could not sign control message")
+	}
+	return nil
+}
+
+// Verify verifies the signature of the given control message against the admin public key
+func Verify(pk *rsa.PublicKey, msg *network.ControlMessage) error {
+	if len(msg.Signature) == 0 {
+		return errors.New("missing signature")
+	}
+	root, err := signingRoot(msg)
+	if err != nil {
+		return err
+	}
+	if err := rsaencryption.VerifySignedData(pk, root, msg.Signature); err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	return nil
+}
+
+// signingRoot returns the bytes that are signed by the admin, i.e. the message w/o signature
+func signingRoot(msg *network.ControlMessage) ([]byte, error) {
+	toSign := *msg
+	toSign.Signature = nil
+	data, err := json.Marshal(&toSign)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal control message")
+	}
+	return data, nil
+}
+
+// SyncFilter decides which validators are synced, based on a static whitelist and the last applied control message
+type SyncFilter struct {
+	lock sync.RWMutex
+
+	// static is the whitelist from config, it is kept across control messages
+	static    map[string]bool
+	whitelist map[string]bool
+	blacklist map[string]bool
+	nonce     uint64
+}
+
+// NewSyncFilter creates a new filter with the given static whitelist
+func NewSyncFilter(whitelist []string) *SyncFilter {
+	return &SyncFilter{
+		static:    toSet(whitelist),
+		whitelist: map[string]bool{},
+		blacklist: map[string]bool{},
+	}
+}
+
+// Apply replaces the runtime whitelist and blacklist with the lists of the given (verified) message,
+// returns the validators that were added to the whitelist. messages with an old nonce are rejected
+func (f *SyncFilter) Apply(msg *network.ControlMessage) ([]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if msg.Nonce <= f.nonce {
+		return nil, errs.Mark(errors.Errorf("control message nonce %d is not higher than %d", msg.Nonce, f.nonce), errs.ErrStaleMessage)
+	}
+	var added []string
+	for _, pk := range msg.SyncWhitelist {
+		if !f.whitelist[pk] && !f.static[pk] {
+			added = append(added, pk)
+		}
+	}
+	f.whitelist = toSet(msg.SyncWhitelist)
+	f.blacklist = toSet(msg.SyncBlacklist)
+	f.nonce = msg.Nonce
+	return added, nil
+}
+
+// Nonce returns the nonce of the last applied message
+func (f *SyncFilter) Nonce() uint64 {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.nonce
+}
+
+// ShouldSync returns true if the given validator should be synced,
+// blacklisted validators are never synced while whitelisted validators are synced even if sync is disabled
+func (f *SyncFilter) ShouldSync(pubKey string, syncEnabled bool) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.blacklist[pubKey] {
+		return false
+	}
+	return syncEnabled || f.static[pubKey] || f.whitelist[pubKey]
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
@@ -0,0 +1,59 @@
+package control
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	_, skPem, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+	require.NoError(t, err)
+
+	msg := &network.ControlMessage{Nonce: 1, Timestamp: 1000, SyncWhitelist: []string{"aa"}}
+	require.EqualError(t, Verify(&sk.PublicKey, msg), "missing signature")
+	require.NoError(t, Sign(sk, msg))
+	require.NoError(t, Verify(&sk.PublicKey, msg))
+
+	tampered := *msg
+	tampered.SyncBlacklist = []string{"bb"}
+	require.Error(t, Verify(&sk.PublicKey, &tampered))
+
+	_, otherPem, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	other, err := rsaencryption.ConvertPemToPrivateKey(string(otherPem))
+	require.NoError(t, err)
+	require.Error(t, Verify(&other.PublicKey, msg))
+}
+
+func TestSyncFilter(t *testing.T) {
+	f := NewSyncFilter([]string{"static"})
+	require.True(t, f.ShouldSync("static", false))
+	require.False(t, f.ShouldSync("aa", false))
+	require.True(t, f.ShouldSync("aa", true))
+
+	added, err := f.Apply(&network.ControlMessage{Nonce: 1, SyncWhitelist: []string{"aa", "static"}, SyncBlacklist: []string{"bb"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"aa"}, added)
+	require.EqualValues(t, 1, f.Nonce())
+	require.True(t, f.ShouldSync("aa", false))
+	require.False(t, f.ShouldSync("bb", true))
+
+	// old messages are rejected
+	_, err = f.Apply(&network.ControlMessage{Nonce: 1})
+	require.True(t, errors.Is(err, errs.ErrStaleMessage))
+	require.True(t, f.ShouldSync("aa", false))
+
+	// lists are replaced, the static whitelist is kept unless blacklisted
+	added, err = f.Apply(&network.ControlMessage{Nonce: 2, SyncBlacklist: []string{"static"}})
+	require.NoError(t, err)
+	require.Len(t, added, 0)
+	require.False(t, f.ShouldSync("aa", false))
+	require.True(t, f.ShouldSync("bb", true))
+	require.False(t, f.ShouldSync("static", true))
+}
@@ -0,0 +1,182 @@
+package exporter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/control"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+	"time"
+)
+
+const (
+	// maxControlMsgAge is the max age of a control message, older messages are dropped
+	maxControlMsgAge = 10 * time.Minute
+	// maxControlMsgSkew is the max clock skew of control messages that were sent in the future
+	maxControlMsgSkew = 30 * time.Second
+)
+
+var (
+	metricsControlMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:exporter:control_messages",
+		Help: "Count control messages by result (applied or the class of rejection)",
+	}, []string{"result"})
+	metricsControlNonce = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:exporter:control_nonce",
+		Help: "The nonce of the last applied control message",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsControlMessages); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsControlNonce); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// loadControlMessage applies the last control message that was applied before the exporter restarted
+func (exp *exporter) loadControlMessage() {
+	msg, found, err := exp.storage.GetControlMessage()
+	if err != nil {
+		exp.logger.Error("could not load control message", zap.Error(err))
+		return
+	}
+	if !found {
+		return
+	}
+	if _, err := exp.syncFilter.Apply(msg); err != nil {
+		exp.logger.Error("could not apply saved control message", zap.Error(err))
+		return
+	}
+	metricsControlNonce.Set(float64(msg.Nonce))
+	exp.logger.Info("applied saved control message", zap.Uint64("nonce", msg.Nonce))
+}
+
+// listenToControlMessages applies control messages that are received on the main topic
+func (exp *exporter) listenToControlMessages() {
+	if exp.adminPubKey == nil {
+		return
+	}
+	controlNet, ok := exp.network.(network.ControlMessages)
+	if !ok {
+		exp.logger.Warn("network does not support control messages")
+		return
+	}
+	for msg := range controlNet.ReceivedControlMessageChan() {
+		if err := exp.handleControlMessage(msg, time.Now()); err != nil {
+			exp.logger.Debug("dropping control message", zap.Error(err))
+		}
+	}
+}
+
+// handleControlMessage verifies the given control message against the admin key, applies and saves it.
+// validators that were added to the whitelist are triggered
+func (exp *exporter) handleControlMessage(msg *network.ControlMessage, now time.Time) (err error) {
+	defer func() {
+		if err != nil {
+			metricsControlMessages.WithLabelValues(errs.Label(err)).Inc()
+		} else {
+			metricsControlMessages.WithLabelValues("applied").Inc()
+		}
+	}()
+
+	if exp.adminPubKey == nil {
+		return errors.New("control messages are disabled")
+	}
+	sent := time.Unix(0, msg.Timestamp*int64(time.Millisecond))
+	if now.Sub(sent) > maxControlMsgAge {
+		return errs.Mark(errors.New("stale control message"), errs.ErrStaleMessage)
+	}
+	if sent.Sub(now) > maxControlMsgSkew {
+		return errs.Mark(errors.New("control message from the future"), errs.ErrStaleMessage)
+	}
+	if err := control.Verify(exp.adminPubKey, msg); err != nil {
+		return errs.Mark(err, errs.ErrInvalidSignature)
+	}
+	added, err := exp.syncFilter.Apply(msg)
+	if err != nil {
+		return err
+	}
+	metricsControlNonce.Set(float64(msg.Nonce))
+	exp.logger.Info("applied control message", zap.Uint64("nonce", msg.Nonce),
+		zap.Strings("whitelist", msg.SyncWhitelist), zap.Strings("blacklist", msg.SyncBlacklist))
+	if err := exp.storage.SaveControlMessage(msg); err != nil {
+		exp.logger.Error("could not save control message", zap.Error(err))
+	}
+	for _, pkHex := range added {
+		exp.triggerWhitelisted(pkHex)
+	}
+	return nil
+}
+
+// triggerWhitelisted triggers the sync of a validator that was added to the whitelist
+func (exp *exporter) triggerWhitelisted(pkHex string) {
+	pkBytes, err := hex.DecodeString(pkHex)
+	if err != nil {
+		exp.logger.Warn("invalid whitelisted public key", zap.String("pubKey", pkHex), zap.Error(err))
+		return
+	}
+	pk := &bls.PublicKey{}
+	if err := pk.Deserialize(pkBytes); err != nil {
+		exp.logger.Warn("invalid whitelisted public key", zap.String("pubKey", pkHex), zap.Error(err))
+		return
+	}
+	if err := exp.triggerValidator(pk); err != nil {
+		exp.logger.Warn("could not trigger whitelisted validator", zap.String("pubKey", pkHex), zap.Error(err))
+	}
+}
+
+// handleControlQuery applies a control message that was pushed over the api,
+// once applied it is broadcasted on the main topic so it reaches the rest of the exporters
+func (exp *exporter) handleControlQuery(nm *api.NetworkMessage) {
+	exp.logger.Debug("handles control request")
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	defer func() {
+		nm.Msg = res
+	}()
+
+	if exp.readOnly {
+		res.Data = []string{"bad request - control messages are not supported in read only mode"}
+		return
+	}
+	msg, err := decodeControlMessage(nm.Msg.Data)
+	if err != nil {
+		res.Data = []string{"bad request - could not decode control message"}
+		return
+	}
+	if err := exp.handleControlMessage(msg, time.Now()); err != nil {
+		res.Data = []string{"bad request - " + err.Error()}
+		return
+	}
+	if controlNet, ok := exp.network.(network.ControlMessages); ok {
+		if err := controlNet.BroadcastControlMessage(msg); err != nil {
+			exp.logger.Warn("could not broadcast control message", zap.Error(err))
+		}
+	}
+	res.Data = map[string]uint64{"nonce": msg.Nonce}
+}
+
+// decodeControlMessage decodes the data of a control request, which was parsed from json
+func decodeControlMessage(data interface{}) (*network.ControlMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	msg := new(network.ControlMessage)
+	if err := json.Unmarshal(raw, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,107 @@
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Info is the geographic and network data of an ip address
+type Info struct {
+	Country     string `json:"country"`
+	ASN         uint32 `json:"asn"`
+	Description string `json:"description,omitempty"`
+}
+
+type ipRange struct {
+	start net.IP
+	end   net.IP
+	info  Info
+}
+
+// Resolver resolves the country and ASN of ip addresses, based on an ip2asn database (https://iptoasn.com)
+type Resolver struct {
+	// ranges are sorted by start address
+	ranges []ipRange
+}
+
+// Load loads the resolver from the given ip2asn (tsv) file
+func Load(path string) (*Resolver, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open geo db")
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return Parse(f)
+}
+
+// Parse parses an ip2asn (tsv) database, where each line is: range_start, range_end, AS_number, country_code, AS_description.
+// ranges that are not routed (AS number 0) are skipped
+func Parse(r io.Reader) (*Resolver, error) {
+	var ranges []ipRange
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 4 {
+			return nil, errors.Errorf("invalid geo db line %d: expected at least 4 fields", line)
+		}
+		start, end := net.ParseIP(fields[0]).To16(), net.ParseIP(fields[1]).To16()
+		if start == nil || end == nil || bytes.Compare(start, end) > 0 {
+			return nil, errors.Errorf("invalid geo db line %d: invalid range", line)
+		}
+		asn, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid geo db line %d: invalid AS number", line)
+		}
+		if asn == 0 {
+			continue
+		}
+		info := Info{Country: fields[3], ASN: uint32(asn)}
+		if len(fields) > 4 {
+			info.Description = fields[4]
+		}
+		ranges = append(ranges, ipRange{start: start, end: end, info: info})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read geo db")
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return bytes.Compare(ranges[i].start, ranges[j].start) < 0
+	})
+	return &Resolver{ranges: ranges}, nil
+}
+
+// Lookup returns the info of the given ip address, false if the address was not found
+func (r *Resolver) Lookup(ip string) (Info, bool) {
+	addr := net.ParseIP(ip).To16()
+	if addr == nil {
+		return Info{}, false
+	}
+	// the first range that starts after the address
+	i := sort.Search(len(r.ranges), func(i int) bool {
+		return bytes.Compare(r.ranges[i].start, addr) > 0
+	})
+	if i == 0 {
+		return Info{}, false
+	}
+	ipr := r.ranges[i-1]
+	if bytes.Compare(addr, ipr.end) > 0 {
+		return Info{}, false
+	}
+	return ipr.info, true
+}
@@ -0,0 +1,43 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDB = `1.0.0.0	1.0.0.255	13335	US	CLOUDFLARENET
+1.0.4.0	1.0.7.255	38803	AU	WPL-AS-AP Wirefreebroadband Pty Ltd
+1.0.1.0	1.0.3.255	0	None	Not routed
+2001:db8::	2001:db8::ffff	64512	DE	TEST-AS
+`
+
+func TestResolver(t *testing.T) {
+	r, err := Parse(strings.NewReader(testDB))
+	require.NoError(t, err)
+	require.Len(t, r.ranges, 3)
+
+	info, ok := r.Lookup("1.0.0.1")
+	require.True(t, ok)
+	require.Equal(t, Info{Country: "US", ASN: 13335, Description: "CLOUDFLARENET"}, info)
+
+	info, ok = r.Lookup("1.0.7.255")
+	require.True(t, ok)
+	require.Equal(t, "AU", info.Country)
+
+	info, ok = r.Lookup("2001:db8::1")
+	require.True(t, ok)
+	require.Equal(t, uint32(64512), info.ASN)
+
+	// not routed, before the first range, after the last range and invalid addresses
+	for _, ip := range []string{"1.0.2.1", "0.0.0.1", "1.0.8.0", "2001:db9::1", "x"} {
+		_, ok = r.Lookup(ip)
+		require.False(t, ok, ip)
+	}
+
+	_, err = Parse(strings.NewReader("1.0.0.0\t1.0.0.255\tx\tUS"))
+	require.EqualError(t, err, "invalid geo db line 1: invalid AS number: strconv.ParseUint: parsing \"x\": invalid syntax")
+	_, err = Parse(strings.NewReader("1.0.0.255\t1.0.0.0\t1\tUS"))
+	require.EqualError(t, err, "invalid geo db line 1: invalid range")
+}
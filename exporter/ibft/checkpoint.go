@@ -0,0 +1,35 @@
+package ibft
+
+import (
+	"encoding/hex"
+	"github.com/bloxapp/ssv/ibft/sync/history"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// DecidedCheckpoint is a trusted decided message (attester) of some validator, used to verify the synced history
+type DecidedCheckpoint struct {
+	// PublicKey is the validator public key (hex)
+	PublicKey string `yaml:"PublicKey"`
+	// SeqNumber is the sequence number of the decided message
+	SeqNumber uint64 `yaml:"SeqNumber"`
+	// Root is the signing root (hex) of the decided message
+	Root string `yaml:"Root"`
+}
+
+// ParseCheckpoints returns the given checkpoints mapped by validator public key
+func ParseCheckpoints(checkpoints []DecidedCheckpoint) (map[string]*history.Checkpoint, error) {
+	res := make(map[string]*history.Checkpoint, len(checkpoints))
+	for _, cp := range checkpoints {
+		pk := strings.TrimPrefix(cp.PublicKey, "0x")
+		if len(pk) == 0 {
+			return nil, errors.New("missing checkpoint public key")
+		}
+		root, err := hex.DecodeString(strings.TrimPrefix(cp.Root, "0x"))
+		if err != nil || len(root) == 0 {
+			return nil, errors.Errorf("invalid checkpoint root of %s", pk)
+		}
+		res[pk] = &history.Checkpoint{SeqNumber: cp.SeqNumber, Root: root}
+	}
+	return res, nil
+}
@@ -0,0 +1,24 @@
+package ibft
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseCheckpoints(t *testing.T) {
+	checkpoints, err := ParseCheckpoints([]DecidedCheckpoint{
+		{PublicKey: "0x8111b36feb", SeqNumber: 10, Root: "0x0102"},
+		{PublicKey: "8222b36feb", SeqNumber: 0, Root: "0304"},
+	})
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	require.Equal(t, uint64(10), checkpoints["8111b36feb"].SeqNumber)
+	require.Equal(t, []byte{1, 2}, checkpoints["8111b36feb"].Root)
+	require.Equal(t, []byte{3, 4}, checkpoints["8222b36feb"].Root)
+
+	_, err = ParseCheckpoints([]DecidedCheckpoint{{PublicKey: "8111b36feb", Root: "xx"}})
+	require.EqualError(t, err, "invalid checkpoint root of 8111b36feb")
+
+	_, err = ParseCheckpoints([]DecidedCheckpoint{{Root: "0102"}})
+	require.EqualError(t, err, "missing checkpoint public key")
+}
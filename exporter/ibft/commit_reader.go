@@ -23,6 +23,8 @@ type CommitReaderOptions struct {
 	ValidatorStorage validatorstorage.ICollection
 	IbftStorage      collections.Iibft
 	Out              *event.Feed
+	// Verify is used to verify signatures, if nil the signatures are verified directly
+	Verify proto.SigVerifier
 }
 
 // commitReader responsible for reading all commit messages
@@ -33,6 +35,7 @@ type commitReader struct {
 	validatorStorage validatorstorage.ICollection
 	ibftStorage      collections.Iibft
 	out              *event.Feed
+	verify           proto.SigVerifier
 }
 
 // NewCommitReader creates new instance
@@ -43,6 +46,7 @@ func NewCommitReader(opts CommitReaderOptions) Reader {
 		validatorStorage: opts.ValidatorStorage,
 		ibftStorage:      opts.IbftStorage,
 		out:              opts.Out,
+		verify:           opts.Verify,
 	}
 	return r
 }
@@ -93,7 +97,7 @@ func (cr *commitReader) onCommitMessage(msg *proto.SignedMessage) error {
 		logger.Debug("could not find share")
 		return nil
 	}
-	if err := validateCommitMsg(msg, share); err != nil {
+	if err := validateCommitMsg(msg, share, cr.verify); err != nil {
 		return errors.Wrap(err, "invalid commit message")
 	}
 	updated, err := ibftinstance.ProcessLateCommitMsg(msg, cr.ibftStorage, pkHex)
@@ -108,13 +112,13 @@ func (cr *commitReader) onCommitMessage(msg *proto.SignedMessage) error {
 }
 
 // validateCommitMsg validates commit message
-func validateCommitMsg(msg *proto.SignedMessage, share *validatorstorage.Share) error {
+func validateCommitMsg(msg *proto.SignedMessage, share *validatorstorage.Share, verify proto.SigVerifier) error {
 	identifier := []byte(format.IdentifierFormat(share.PublicKey.Serialize(), beacon.RoleTypeAttester.String()))
 	p := pipeline.Combine(
 		auth.BasicMsgValidation(),
 		auth.MsgTypeCheck(proto.RoundState_Commit),
 		auth.ValidateLambdas(identifier),
-		auth.AuthorizeMsg(share),
+		auth.AuthorizeMsgWith(share, verify),
 	)
 	return p.Run(msg)
 }
@@ -2,8 +2,11 @@ package ibft
 
 import (
 	"context"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/exporter/api"
+	exporterstorage "github.com/bloxapp/ssv/exporter/storage"
 	"github.com/bloxapp/ssv/ibft"
 	"github.com/bloxapp/ssv/ibft/pipeline"
 	"github.com/bloxapp/ssv/ibft/pipeline/auth"
@@ -12,6 +15,7 @@ import (
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/commons"
 	"github.com/bloxapp/ssv/storage/collections"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/bloxapp/ssv/utils/format"
 	"github.com/bloxapp/ssv/utils/tasks"
 	"github.com/bloxapp/ssv/validator"
@@ -23,25 +27,50 @@ import (
 	"time"
 )
 
+// syncRetryPolicy is used to recover from transient network errors when syncing decided history
+var syncRetryPolicy = tasks.RetryPolicy{
+	MaxAttempts: 5,
+	Backoff:     time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+// syncTimeout is the max duration of a single history sync attempt
+const syncTimeout = 5 * time.Minute
+
 // DecidedReaderOptions defines the required parameters to create an instance
 type DecidedReaderOptions struct {
+	// Ctx cancels ongoing syncs once the exporter stops, defaults to the background context
+	Ctx            context.Context
 	Logger         *zap.Logger
 	Storage        collections.Iibft
 	Network        network.Network
 	Config         *proto.InstanceConfig
 	ValidatorShare *storage.Share
+	// Checkpoint is optional, used to verify the synced history
+	Checkpoint *history.Checkpoint
+	// Dispatcher routes the decided messages of the validator to the reader
+	Dispatcher Dispatcher
+	// Timings is optional, used to record the receive time and latency of decided messages
+	Timings exporterstorage.DecidedTimingsCollection
+	// ETHNetwork is used to calculate the latency since the start of the duty slot
+	ETHNetwork *core.Network
 
 	Out *event.Feed
 }
 
 // decidedReader reads decided messages history
 type decidedReader struct {
+	ctx     context.Context
 	logger  *zap.Logger
 	storage collections.Iibft
 	network network.Network
 
 	config         *proto.InstanceConfig
 	validatorShare *storage.Share
+	checkpoint     *history.Checkpoint
+	dispatcher     Dispatcher
+	timings        exporterstorage.DecidedTimingsCollection
+	ethNetwork     *core.Network
 
 	out *event.Feed
 
@@ -50,7 +79,12 @@ type decidedReader struct {
 
 // newDecidedReader creates new instance of DecidedReader
 func newDecidedReader(opts DecidedReaderOptions) Reader {
+	ctx := opts.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	r := decidedReader{
+		ctx: ctx,
 		logger: opts.Logger.With(
 			zap.String("pubKey", opts.ValidatorShare.PublicKey.SerializeToHexStr()),
 			zap.String("ibft", "decided_reader")),
@@ -58,6 +92,10 @@ func newDecidedReader(opts DecidedReaderOptions) Reader {
 		network:        opts.Network,
 		config:         opts.Config,
 		validatorShare: opts.ValidatorShare,
+		checkpoint:     opts.Checkpoint,
+		dispatcher:     opts.Dispatcher,
+		timings:        opts.Timings,
+		ethNetwork:     opts.ETHNetwork,
 		out:            opts.Out,
 		identifier: []byte(format.IdentifierFormat(opts.ValidatorShare.PublicKey.Serialize(),
 			beacon.RoleTypeAttester.String())),
@@ -75,8 +113,13 @@ func (r *decidedReader) sync() error {
 
 	r.logger.Debug("syncing ibft data")
 	// creating HistorySync and starts it
+	ctx, cancel := context.WithTimeout(r.ctx, syncTimeout)
+	defer cancel()
 	hs := history.New(r.logger, r.validatorShare.PublicKey.Serialize(), r.identifier, r.network,
-		r.storage, r.validateDecidedMsg)
+		r.storage, r.validateDecidedMsg).WithCommittee(r.validatorShare.CommitteeOperators()).WithContext(ctx)
+	if r.checkpoint != nil {
+		hs = hs.WithCheckpoint(r.checkpoint)
+	}
 	err := hs.Start()
 	if err != nil {
 		r.logger.Error("could not sync validator's data", zap.Error(err))
@@ -84,24 +127,23 @@ func (r *decidedReader) sync() error {
 	return err
 }
 
-// Start starts to listen to decided messages
+// Start syncs decided messages and registers the reader in the dispatcher to listen to new decided messages,
+// returns once done
 func (r *decidedReader) Start() error {
+	if _, err := r.storage.MigrateLegacyKeys(r.identifier); err != nil {
+		r.logger.Error("failed to migrate ibft storage keys", zap.Error(err))
+	}
 	if err := r.network.SubscribeToValidatorNetwork(r.validatorShare.PublicKey); err != nil {
 		return errors.Wrap(err, "failed to subscribe topic")
 	}
-	defer func() {
-		if err := r.network.UnSubscribeValidatorNetwork(r.validatorShare.PublicKey); err != nil {
-			r.logger.Error("failed to unsubscribe topic", zap.Error(err))
-		}
-	}()
 
-	if err := tasks.Retry(func() error {
+	if _, err := tasks.RetryWithPolicy(r.ctx, func() error {
 		if err := r.sync(); err != nil {
 			r.logger.Error("could not sync validator", zap.Error(err))
 			return err
 		}
 		return nil
-	}, 3); err != nil {
+	}, syncRetryPolicy); err != nil {
 		validator.ReportIBFTStatus(r.validatorShare.PublicKey.SerializeToHexStr(), false, true)
 		r.logger.Error("could not setup validator, sync failed", zap.Error(err))
 		return err
@@ -113,38 +155,39 @@ func (r *decidedReader) Start() error {
 	if err := r.waitForMinPeers(r.validatorShare.PublicKey, 1); err != nil {
 		return errors.Wrap(err, "could not wait for min peers")
 	}
-	r.listenToNetwork(r.network.ReceivedDecidedChan())
+	r.dispatcher.Register(string(r.identifier), "decided", r.onMessage)
+	r.logger.Debug("listening to decided messages")
 	return nil
 }
 
-func (r *decidedReader) listenToNetwork(cn <-chan *proto.SignedMessage) {
-	r.logger.Debug("listening to decided messages")
-	for msg := range cn {
-		if err := validateMsg(msg, string(r.identifier)); err != nil {
-			continue
-		}
-		logger := r.logger.With(messageFields(msg)...)
-		if err := validateDecidedMsg(msg, r.validatorShare); err != nil {
-			logger.Debug("received invalid decided message")
-			continue
-		}
-		if msg.Message.SeqNumber == 0 {
-			logger.Debug("received invalid sequence")
-			continue
-		}
-		go func(msg *proto.SignedMessage) {
-			defer logger.Debug("done with decided msg")
-			if saved, err := r.handleNewDecidedMessage(msg); err != nil && !saved {
-				logger.Error("could not handle decided message", zap.Error(err))
-			} else if err != nil {
-				logger.Error("could not check highest decided", zap.Error(err))
-			}
-		}(msg)
+// onMessage is called by the dispatcher (on a worker) for decided messages of the validator
+func (r *decidedReader) onMessage(msg *proto.SignedMessage) {
+	receivedAt := time.Now()
+	if err := validateMsg(msg, string(r.identifier)); err != nil {
+		network.ReportRejectedMessage(err)
+		return
+	}
+	logger := r.logger.With(messageFields(msg)...)
+	if err := validateDecidedMsg(msg, r.validatorShare); err != nil {
+		metricsDecidedRejected.WithLabelValues(errs.Label(err)).Inc()
+		network.ReportRejectedMessage(err)
+		logger.Debug("received invalid decided message", zap.Error(err))
+		return
+	}
+	if msg.Message.SeqNumber == 0 {
+		logger.Debug("received invalid sequence")
+		return
+	}
+	defer logger.Debug("done with decided msg")
+	if saved, err := r.handleNewDecidedMessage(msg, receivedAt); err != nil && !saved {
+		logger.Error("could not handle decided message", zap.Error(err))
+	} else if err != nil {
+		logger.Error("could not check highest decided", zap.Error(err))
 	}
 }
 
 // handleNewDecidedMessage saves an incoming (valid) decided message
-func (r *decidedReader) handleNewDecidedMessage(msg *proto.SignedMessage) (bool, error) {
+func (r *decidedReader) handleNewDecidedMessage(msg *proto.SignedMessage, receivedAt time.Time) (bool, error) {
 	logger := r.logger.With(messageFields(msg)...)
 	if decided, found, _ := r.storage.GetDecided(r.identifier, msg.Message.SeqNumber); found && decided != nil {
 		logger.Debug("received known sequence")
@@ -154,11 +197,27 @@ func (r *decidedReader) handleNewDecidedMessage(msg *proto.SignedMessage) (bool,
 		return false, errors.Wrap(err, "could not save decided")
 	}
 	logger.Debug("decided saved")
+	if err := r.saveTiming(msg, receivedAt); err != nil {
+		logger.Warn("could not save decided timing", zap.Error(err))
+	}
 	ibft.ReportDecided(r.validatorShare.PublicKey.SerializeToHexStr(), msg)
 	go r.out.Send(newDecidedNetworkMsg(msg, r.validatorShare.PublicKey.SerializeToHexStr()))
 	return true, r.checkHighestDecided(msg)
 }
 
+// saveTiming records the receive time of the decided message, and the latency if the duty slot is known
+func (r *decidedReader) saveTiming(msg *proto.SignedMessage, receivedAt time.Time) error {
+	if r.timings == nil {
+		return nil
+	}
+	timing := newDecidedTiming(msg, receivedAt, r.ethNetwork)
+	if timing.Latency != nil {
+		metricsDecidedLatency.WithLabelValues(r.validatorShare.PublicKey.SerializeToHexStr()).
+			Observe(float64(*timing.Latency) / 1000)
+	}
+	return r.timings.SaveDecidedTiming(r.validatorShare.PublicKey.SerializeToHexStr(), timing)
+}
+
 // checkHighestDecided check if highest decided should be updated
 func (r *decidedReader) checkHighestDecided(msg *proto.SignedMessage) error {
 	logger := r.logger.With(messageFields(msg)...)
@@ -228,6 +287,31 @@ func validateMsg(msg *proto.SignedMessage, identifier string) error {
 	return p.Run(msg)
 }
 
+// newDecidedTiming creates the timing of the given message, the slot is taken from the decided value
+// in case it is an attestation data
+func newDecidedTiming(msg *proto.SignedMessage, receivedAt time.Time, ethNetwork *core.Network) *exporterstorage.DecidedTiming {
+	timing := exporterstorage.DecidedTiming{
+		SeqNumber:  msg.Message.SeqNumber,
+		ReceivedAt: receivedAt.UnixNano() / int64(time.Millisecond),
+	}
+	attData := spec.AttestationData{}
+	if err := attData.UnmarshalSSZ(msg.Message.Value); err != nil {
+		return &timing
+	}
+	slot := uint64(attData.Slot)
+	timing.Slot = &slot
+	if ethNetwork != nil {
+		latency := receivedAt.Sub(slotStartTime(ethNetwork, slot)).Milliseconds()
+		timing.Latency = &latency
+	}
+	return &timing
+}
+
+func slotStartTime(ethNetwork *core.Network, slot uint64) time.Time {
+	timeSinceGenesisStart := slot * uint64(ethNetwork.SlotDurationSec().Seconds())
+	return time.Unix(int64(ethNetwork.MinGenesisTime()+timeSinceGenesisStart), 0)
+}
+
 func newDecidedNetworkMsg(msg *proto.SignedMessage, pk string) *api.NetworkMessage {
 	return &api.NetworkMessage{Msg: api.Message{
 		Type: api.TypeDecided,
@@ -0,0 +1,47 @@
+package ibft
+
+import (
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewDecidedTiming(t *testing.T) {
+	network := core.NetworkFromString("prater")
+	attData := &spec.AttestationData{
+		Slot:   100,
+		Source: &spec.Checkpoint{},
+		Target: &spec.Checkpoint{},
+	}
+	value, err := attData.MarshalSSZ()
+	require.NoError(t, err)
+
+	receivedAt := slotStartTime(&network, 100).Add(2500 * time.Millisecond)
+	timing := newDecidedTiming(&proto.SignedMessage{
+		Message: &proto.Message{SeqNumber: 7, Value: value},
+	}, receivedAt, &network)
+	require.Equal(t, uint64(7), timing.SeqNumber)
+	require.Equal(t, receivedAt.UnixNano()/int64(time.Millisecond), timing.ReceivedAt)
+	require.Equal(t, uint64(100), *timing.Slot)
+	require.Equal(t, int64(2500), *timing.Latency)
+
+	t.Run("unknown value", func(t *testing.T) {
+		timing := newDecidedTiming(&proto.SignedMessage{
+			Message: &proto.Message{SeqNumber: 8, Value: []byte("value")},
+		}, receivedAt, &network)
+		require.Equal(t, uint64(8), timing.SeqNumber)
+		require.Nil(t, timing.Slot)
+		require.Nil(t, timing.Latency)
+	})
+
+	t.Run("no network", func(t *testing.T) {
+		timing := newDecidedTiming(&proto.SignedMessage{
+			Message: &proto.Message{SeqNumber: 9, Value: value},
+		}, receivedAt, nil)
+		require.Equal(t, uint64(100), *timing.Slot)
+		require.Nil(t, timing.Latency)
+	})
+}
@@ -0,0 +1,109 @@
+package ibft
+
+import (
+	"sync"
+
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWorkers   = 32
+	defaultQueueSize = 1024
+)
+
+// MessageHandler handles a message of a specific identifier
+type MessageHandler func(msg *proto.SignedMessage)
+
+// DispatcherOptions defines the required parameters to create an instance
+type DispatcherOptions struct {
+	Logger  *zap.Logger
+	Network network.Network
+	// Workers is the number of goroutines that run message handlers
+	Workers int
+	// QueueSize is the number of messages that can wait for a worker
+	QueueSize int
+}
+
+// Dispatcher listens to decided messages and routes them to the handlers of the message identifier.
+// it replaces a listener goroutine per reader, handlers are executed on a bounded worker pool
+type Dispatcher interface {
+	// Start starts to listen to messages, blocking
+	Start()
+	// Register adds a handler (identified by name) of messages of the given identifier
+	Register(identifier string, name string, handler MessageHandler)
+}
+
+type dispatcher struct {
+	logger  *zap.Logger
+	network network.Network
+	pool    *tasks.WorkerPool
+
+	lock     sync.RWMutex
+	handlers map[string]map[string]MessageHandler
+}
+
+// NewDispatcher creates a new instance
+func NewDispatcher(opts DispatcherOptions) Dispatcher {
+	workers := opts.Workers
+	if workers == 0 {
+		workers = defaultWorkers
+	}
+	queueSize := opts.QueueSize
+	if queueSize == 0 {
+		queueSize = defaultQueueSize
+	}
+	return &dispatcher{
+		logger:   opts.Logger.With(zap.String("who", "dispatcher")),
+		network:  opts.Network,
+		pool:     tasks.NewWorkerPool(workers, queueSize),
+		handlers: map[string]map[string]MessageHandler{},
+	}
+}
+
+func (d *dispatcher) Start() {
+	d.pool.Start()
+	defer d.pool.Stop()
+
+	cn := d.network.ReceivedDecidedChan()
+	d.logger.Debug("listening to decided messages")
+	for msg := range cn {
+		if msg == nil || msg.Message == nil {
+			continue
+		}
+		for _, handler := range d.getHandlers(string(msg.Message.Lambda)) {
+			h := handler
+			m := msg
+			d.pool.Queue(func() {
+				h(m)
+			})
+		}
+		metricsDispatcherPending.Set(float64(d.pool.Pending()))
+	}
+}
+
+func (d *dispatcher) Register(identifier string, name string, handler MessageHandler) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	handlers, ok := d.handlers[identifier]
+	if !ok {
+		handlers = map[string]MessageHandler{}
+		d.handlers[identifier] = handlers
+	}
+	handlers[name] = handler
+}
+
+func (d *dispatcher) getHandlers(identifier string) []MessageHandler {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	handlers := d.handlers[identifier]
+	res := make([]MessageHandler, 0, len(handlers))
+	for _, h := range handlers {
+		res = append(res, h)
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+package ibft
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcher(t *testing.T) {
+	net := local.NewLocalNetwork()
+	d := NewDispatcher(DispatcherOptions{
+		Logger:  zap.L(),
+		Network: net,
+		Workers: 2,
+	})
+	go d.Start()
+
+	var lock sync.Mutex
+	received := map[string][]uint64{}
+	handler := func(name string) MessageHandler {
+		return func(msg *proto.SignedMessage) {
+			lock.Lock()
+			defer lock.Unlock()
+			received[name] = append(received[name], msg.Message.SeqNumber)
+		}
+	}
+	d.Register("a", "decided", handler("a/decided"))
+	d.Register("a", "network", handler("a/network"))
+	d.Register("b", "decided", handler("b/decided"))
+	// registering the same name again replaces the handler
+	d.Register("b", "decided", handler("b/decided"))
+
+	// wait for dispatcher to listen
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, net.BroadcastDecided(nil, &proto.SignedMessage{Message: &proto.Message{Lambda: []byte("a"), SeqNumber: 1}}))
+	require.NoError(t, net.BroadcastDecided(nil, &proto.SignedMessage{Message: &proto.Message{Lambda: []byte("b"), SeqNumber: 2}}))
+	require.NoError(t, net.BroadcastDecided(nil, &proto.SignedMessage{Message: &proto.Message{Lambda: []byte("c"), SeqNumber: 3}}))
+	require.NoError(t, net.BroadcastDecided(nil, &proto.SignedMessage{}))
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(received["a/decided"]) == 1 && len(received["a/network"]) == 1 && len(received["b/decided"]) == 1
+	}, time.Second, 5*time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, []uint64{2}, received["b/decided"])
+	require.Len(t, received, 3)
+}
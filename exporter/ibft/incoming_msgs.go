@@ -19,6 +19,8 @@ type IncomingMsgsReaderOptions struct {
 	Network network.Network
 	Config  *proto.InstanceConfig
 	PK      *bls.PublicKey
+	// Dispatcher routes the messages of the validator to the reader
+	Dispatcher Dispatcher
 }
 
 type incomingMsgsReader struct {
@@ -26,6 +28,8 @@ type incomingMsgsReader struct {
 	network   network.Network
 	config    *proto.InstanceConfig
 	publicKey *bls.PublicKey
+
+	dispatcher Dispatcher
 }
 
 // newIncomingMsgsReader creates new instance
@@ -36,54 +40,41 @@ func newIncomingMsgsReader(opts IncomingMsgsReaderOptions) Reader {
 		network:   opts.Network,
 		config:    opts.Config,
 		publicKey: opts.PK,
+
+		dispatcher: opts.Dispatcher,
 	}
 	return r
 }
 
+// Start subscribes to the validator topic and registers the reader in the dispatcher, returns once done
 func (i *incomingMsgsReader) Start() error {
 	if err := i.network.SubscribeToValidatorNetwork(i.publicKey); err != nil {
 		return errors.Wrap(err, "failed to subscribe topic")
 	}
-	defer func() {
-		if err := i.network.UnSubscribeValidatorNetwork(i.publicKey); err != nil {
-			i.logger.Error("failed to unsubscribe topic", zap.Error(err))
-		}
-	}()
 	if err := i.waitForMinPeers(i.publicKey, 1); err != nil {
 		return errors.Wrap(err, "could not wait for min peers")
 	}
-	i.listenToNetwork(i.network.ReceivedDecidedChan())
-	return nil
-}
-
-func (i *incomingMsgsReader) listenToNetwork(cn <-chan *proto.SignedMessage) {
+	// TODO: handle other types of roles
 	identifier := format.IdentifierFormat(i.publicKey.Serialize(), beacon.RoleTypeAttester.String())
+	i.dispatcher.Register(identifier, "network", i.onMessage)
 	i.logger.Debug("listening to network messages")
-	for msg := range cn {
-		if msg == nil || msg.Message == nil {
-			i.logger.Info("received invalid msg")
-			continue
-		}
-		// filtering irrelevant messages
-		// TODO: handle other types of roles
-		if identifier != string(msg.Message.Lambda) {
-			continue
-		}
+	return nil
+}
 
-		fields := messageFields(msg)
+func (i *incomingMsgsReader) onMessage(msg *proto.SignedMessage) {
+	fields := messageFields(msg)
 
-		switch msg.Message.Type {
-		case proto.RoundState_PrePrepare:
-			i.logger.Info("pre-prepare msg", fields...)
-		case proto.RoundState_Prepare:
-			i.logger.Info("prepare msg", fields...)
-		case proto.RoundState_Commit:
-			i.logger.Info("commit msg", fields...)
-		case proto.RoundState_ChangeRound:
-			i.logger.Info("change round msg", fields...)
-		default:
-			i.logger.Warn("undefined message type", zap.Any("msg", msg))
-		}
+	switch msg.Message.Type {
+	case proto.RoundState_PrePrepare:
+		i.logger.Info("pre-prepare msg", fields...)
+	case proto.RoundState_Prepare:
+		i.logger.Info("prepare msg", fields...)
+	case proto.RoundState_Commit:
+		i.logger.Info("commit msg", fields...)
+	case proto.RoundState_ChangeRound:
+		i.logger.Info("change round msg", fields...)
+	default:
+		i.logger.Warn("undefined message type", zap.Any("msg", msg))
 	}
 }
 
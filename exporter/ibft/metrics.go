@@ -0,0 +1,35 @@
+package ibft
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsDispatcherPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:exporter:dispatcher_pending",
+		Help: "Count of decided messages that are waiting for a worker",
+	})
+	metricsDecidedLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv:exporter:decided_latency_seconds",
+		Help:    "Time between the start of the duty slot and the receive time of the decided message",
+		Buckets: []float64{0.5, 1, 2, 3, 4, 6, 8, 12, 24},
+	}, []string{"pubKey"})
+	metricsDecidedRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:exporter:decided_rejected",
+		Help: "Count decided messages that were rejected by error class",
+	}, []string{"class"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsDispatcherPending); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsDecidedLatency); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsDecidedRejected); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
@@ -0,0 +1,189 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	// LeasePrefix is the db prefix of the lease, which is updated with compare and swap
+	LeasePrefix = []byte("exporter-leader")
+	leaseKey    = []byte("sync")
+)
+
+// casDb is a db that supports compare and swap
+type casDb interface {
+	basedb.IDb
+	basedb.CompareAndSwapper
+}
+
+// lease is the record of the current leader, stored in the shared db
+type lease struct {
+	Holder  string `json:"holder"`
+	Expires int64  `json:"expires"`
+}
+
+// Options defines the required parameters to create an instance
+type Options struct {
+	Ctx    context.Context
+	Logger *zap.Logger
+	// DB is the shared db, it must support compare and swap (basedb.CompareAndSwapper)
+	DB basedb.IDb
+	// ID is the unique id of this replica
+	ID string
+	// LeaseDuration is the time that a leader holds the lease w/o renewing it
+	LeaseDuration time.Duration
+}
+
+// Election elects a single leader among replicas that share a db, using a lease that is renewed by the leader
+type Election struct {
+	ctx           context.Context
+	logger        *zap.Logger
+	db            casDb
+	id            string
+	leaseDuration time.Duration
+
+	now func() time.Time
+
+	elected   chan struct{}
+	lost      chan struct{}
+	startOnce sync.Once
+}
+
+// New creates a new instance
+func New(opts Options) (*Election, error) {
+	db, ok := opts.DB.(casDb)
+	if !ok {
+		return nil, errors.New("leader election requires a db that supports compare and swap")
+	}
+	return &Election{
+		ctx:           opts.Ctx,
+		logger:        opts.Logger.With(zap.String("who", "leaderElection"), zap.String("replica", opts.ID)),
+		db:            db,
+		id:            opts.ID,
+		leaseDuration: opts.LeaseDuration,
+		now:           time.Now,
+		elected:       make(chan struct{}),
+		lost:          make(chan struct{}),
+	}, nil
+}
+
+// Start campaigns for leadership and renews the lease once elected, blocking until the leadership was lost
+func (e *Election) Start() {
+	e.startOnce.Do(func() {
+		interval := e.leaseDuration / 3
+		e.logger.Debug("campaigning for leadership")
+		for !e.tryAcquire() {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+		e.logger.Info("elected as leader")
+		reportLeader(true)
+		close(e.elected)
+
+		for {
+			select {
+			case <-e.ctx.Done():
+				if err := e.resign(); err != nil {
+					e.logger.Warn("could not resign", zap.Error(err))
+				}
+				return
+			case <-time.After(interval):
+			}
+			if !e.tryAcquire() {
+				e.logger.Warn("lost leadership")
+				reportLeader(false)
+				close(e.lost)
+				return
+			}
+		}
+	})
+}
+
+// Elected returns a channel that is closed once this replica becomes the leader
+func (e *Election) Elected() <-chan struct{} {
+	return e.elected
+}
+
+// Lost returns a channel that is closed if this replica lost the leadership
+func (e *Election) Lost() <-chan struct{} {
+	return e.lost
+}
+
+// IsLeader returns true if this replica is currently the leader
+func (e *Election) IsLeader() bool {
+	select {
+	case <-e.lost:
+		return false
+	default:
+	}
+	select {
+	case <-e.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// tryAcquire acquires or renews the lease, returns true if this replica holds the lease.
+// the lease is replaced with a compare and swap, so only one of concurrent replicas can acquire it
+func (e *Election) tryAcquire() bool {
+	current, raw, err := e.getLease()
+	if err != nil {
+		e.logger.Warn("could not read lease", zap.Error(err))
+		return false
+	}
+	now := e.now()
+	if current != nil && current.Holder != e.id && current.Expires > now.UnixNano() {
+		return false
+	}
+	next, err := json.Marshal(&lease{Holder: e.id, Expires: now.Add(e.leaseDuration).UnixNano()})
+	if err != nil {
+		e.logger.Warn("could not marshal lease", zap.Error(err))
+		return false
+	}
+	swapped, err := e.db.CompareAndSwap(LeasePrefix, leaseKey, raw, next)
+	if err != nil {
+		e.logger.Warn("could not save lease", zap.Error(err))
+		return false
+	}
+	return swapped
+}
+
+// resign releases the lease so other replicas won't wait for it to expire
+func (e *Election) resign() error {
+	current, raw, err := e.getLease()
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Holder != e.id {
+		return nil
+	}
+	_, err = e.db.CompareAndSwap(LeasePrefix, leaseKey, raw, nil)
+	return err
+}
+
+// getLease returns the current lease and its raw value, which is used to replace it
+func (e *Election) getLease() (*lease, []byte, error) {
+	obj, found, err := e.db.Get(LeasePrefix, leaseKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not get lease")
+	}
+	if !found {
+		return nil, nil, nil
+	}
+	var l lease
+	if err := json.Unmarshal(obj.Value, &l); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal lease")
+	}
+	return &l, obj.Value, nil
+}
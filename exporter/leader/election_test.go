@@ -0,0 +1,143 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	ssvstorage "github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newElection(t *testing.T, ctx context.Context, db basedb.IDb, id string) *Election {
+	e, err := New(Options{
+		Ctx:           ctx,
+		Logger:        zap.L(),
+		DB:            db,
+		ID:            id,
+		LeaseDuration: 90 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return e
+}
+
+// start starts the election and returns a channel that is closed once it stopped
+func start(e *Election) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Start()
+	}()
+	return done
+}
+
+func TestElection(t *testing.T) {
+	db, err := ssvstorage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	a := newElection(t, ctxA, db, "a")
+	doneA := start(a)
+	<-a.Elected()
+	require.True(t, a.IsLeader())
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	b := newElection(t, ctxB, db, "b")
+	doneB := start(b)
+	defer func() {
+		cancelB()
+		<-doneB
+	}()
+	// b is a follower as long as a renews the lease
+	select {
+	case <-b.Elected():
+		t.Fatal("two leaders were elected")
+	case <-time.After(200 * time.Millisecond):
+	}
+	require.False(t, b.IsLeader())
+
+	// a resigns, b should take over
+	cancelA()
+	<-doneA
+	select {
+	case <-b.Elected():
+	case <-time.After(time.Second):
+		t.Fatal("b wasn't elected")
+	}
+	require.True(t, b.IsLeader())
+}
+
+func TestElection_Lost(t *testing.T) {
+	db, err := ssvstorage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := newElection(t, ctx, db, "a")
+	done := start(a)
+	<-a.Elected()
+
+	// another replica took the lease (e.g. after a long pause of a)
+	raw, err := json.Marshal(&lease{Holder: "b", Expires: time.Now().Add(time.Minute).UnixNano()})
+	require.NoError(t, err)
+	require.NoError(t, db.Set(LeasePrefix, leaseKey, raw))
+	select {
+	case <-a.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("a didn't lose leadership")
+	}
+	<-done
+	require.False(t, a.IsLeader())
+}
+
+func TestElection_Concurrent(t *testing.T) {
+	db, err := ssvstorage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var elections []*Election
+	var dones []chan struct{}
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		e := newElection(t, ctx, db, id)
+		elections = append(elections, e)
+		dones = append(dones, start(e))
+	}
+	defer func() {
+		cancel()
+		for _, done := range dones {
+			<-done
+		}
+	}()
+	// replicas campaign concurrently, only one should be elected
+	time.Sleep(300 * time.Millisecond)
+	leaders := 0
+	for _, e := range elections {
+		if e.IsLeader() {
+			leaders++
+		}
+	}
+	require.Equal(t, 1, leaders)
+}
+
+func TestNew_NoCompareAndSwap(t *testing.T) {
+	_, err := New(Options{Ctx: context.Background(), Logger: zap.L(), DB: nil, ID: "a"})
+	require.EqualError(t, err, "leader election requires a db that supports compare and swap")
+}
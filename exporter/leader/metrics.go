@@ -0,0 +1,28 @@
+package leader
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:exporter:leader",
+		Help: "Indicates whether the exporter replica is the leader (1) or a follower (0)",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsLeader); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+func reportLeader(leader bool) {
+	if leader {
+		metricsLeader.Set(1)
+	} else {
+		metricsLeader.Set(0)
+	}
+}
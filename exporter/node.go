@@ -2,24 +2,34 @@ package exporter
 
 import (
 	"context"
+	"crypto/rsa"
 	"fmt"
 	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/eth1"
 	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/control"
+	"github.com/bloxapp/ssv/exporter/geo"
 	"github.com/bloxapp/ssv/exporter/ibft"
+	"github.com/bloxapp/ssv/exporter/leader"
+	"github.com/bloxapp/ssv/exporter/sink"
 	"github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/exporter/webhooks"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/ibft/sync/history"
 	"github.com/bloxapp/ssv/monitoring/metrics"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/collections"
+	"github.com/bloxapp/ssv/utils/batchverifier"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
 	"github.com/bloxapp/ssv/utils/tasks"
 	"github.com/bloxapp/ssv/validator"
 	validatorstorage "github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"sync"
 	"time"
 )
 
@@ -59,6 +69,34 @@ type Options struct {
 	IbftSyncEnabled                 bool
 	CleanRegistryData               bool
 	ValidatorMetaDataUpdateInterval time.Duration
+	// ValidatorMetaDataRequestsPerMinute is the beacon request budget of periodic metadata updates, zero is unlimited
+	ValidatorMetaDataRequestsPerMinute int
+	BatchVerifierOptions               batchverifier.Options
+	DecidedCheckpoints                 []ibft.DecidedCheckpoint
+	// ValidatorMetaDataTTL is the age of validators metadata that triggers a refresh when read by queries
+	ValidatorMetaDataTTL time.Duration
+	// ReadersWorkers is the number of workers that handle incoming messages of all validators
+	ReadersWorkers int
+	// ReplicaID is the unique id of this replica, used for leader election
+	ReplicaID string
+	// LeaderLeaseDuration enables leader election (for the sync role) among replicas that share the db
+	LeaderLeaseDuration time.Duration
+	// WebhooksOptions configures webhooks that outbound events are posted to, disabled if no url was provided
+	WebhooksOptions webhooks.Options
+	// OperatorsMetadataURL is an endpoint that serves display metadata of operators, disabled if empty
+	OperatorsMetadataURL string
+	// OperatorsMetadataInterval is the interval of operators metadata updates
+	OperatorsMetadataInterval time.Duration
+	// PeersGeoDBPath is the path of an ip2asn (tsv) database that peers countries and ASNs are resolved from, disabled if empty
+	PeersGeoDBPath string
+	// SinkOptions configures a message bus that outbound events are published to, disabled if no type was provided
+	SinkOptions sink.Options
+	// ReadOnly makes the exporter serve queries from the existing db w/o eth1 sync, p2p or beacon,
+	// Network, Eth1Client and Beacon are not required in this mode
+	ReadOnly bool
+	// AdminPublicKey is the key (base64 encoded pem) that control messages are verified against,
+	// control messages are ignored if not provided
+	AdminPublicKey string
 }
 
 // exporter is the internal implementation of Exporter interface
@@ -71,18 +109,49 @@ type exporter struct {
 	network          network.Network
 	eth1Client       eth1.Client
 	beacon           beacon.Beacon
+	ethNetwork       *core.Network
 
-	ws           api.WebSocketServer
-	commitReader ibft.Reader
+	ws            api.WebSocketServer
+	commitReader  ibft.Reader
+	batchVerifier batchverifier.Verifier
+	dispatcher    ibft.Dispatcher
 
 	wsAPIPort                       int
 	ibftSyncEnabled                 bool
 	validatorMetaDataUpdateInterval time.Duration
+	// validatorMetaDataRequestsPerMinute is the beacon request budget of periodic metadata updates
+	validatorMetaDataRequestsPerMinute int
+	validatorMetaDataTTL               time.Duration
+	checkpoints                        map[string]*history.Checkpoint
 
 	mainQueue            tasks.Queue
 	decidedReadersQueue  tasks.Queue
 	networkReadersQueue  tasks.Queue
 	metaDataReadersQueue tasks.Queue
+
+	// metaDataRefreshes holds the validators that were recently refreshed due to stale metadata
+	metaDataRefreshes     map[string]time.Time
+	metaDataRefreshesLock sync.Mutex
+
+	// election is nil if leader election is disabled (single replica)
+	election          *leader.Election
+	cleanRegistryData bool
+	syncOffset        *eth1.SyncOffset
+	// webhooks is nil if no webhook url was configured
+	webhooks *webhooks.Webhooks
+	// sink is nil if no message bus was configured
+	sink *sink.Publisher
+	// operatorsMetadata is nil if no operators metadata endpoint was configured
+	operatorsMetadata         OperatorsMetadataProvider
+	operatorsMetadataInterval time.Duration
+	// geo is nil if no geo db was configured
+	geo *geo.Resolver
+	// readOnly is true if the exporter only serves queries from the existing db
+	readOnly bool
+	// syncFilter decides which validators are synced, updated by control messages
+	syncFilter *control.SyncFilter
+	// adminPubKey is nil if control messages are disabled
+	adminPubKey *rsa.PublicKey
 }
 
 // New creates a new Exporter instance
@@ -94,6 +163,15 @@ func New(opts Options) Exporter {
 			Logger: opts.Logger,
 		},
 	)
+	batchVerifierOpts := opts.BatchVerifierOptions
+	batchVerifierOpts.Ctx = opts.Ctx
+	batchVerifierOpts.Logger = opts.Logger
+	batchVerifier := batchverifier.New(batchVerifierOpts)
+	dispatcher := ibft.NewDispatcher(ibft.DispatcherOptions{
+		Logger:  opts.Logger,
+		Network: opts.Network,
+		Workers: opts.ReadersWorkers,
+	})
 	e := exporter{
 		ctx:                  opts.Ctx,
 		storage:              storage.NewExporterStorage(opts.DB, opts.Logger),
@@ -103,6 +181,7 @@ func New(opts Options) Exporter {
 		network:              opts.Network,
 		eth1Client:           opts.Eth1Client,
 		beacon:               opts.Beacon,
+		ethNetwork:           opts.ETHNetwork,
 		mainQueue:            tasks.NewExecutionQueue(mainQueueInterval),
 		decidedReadersQueue:  tasks.NewExecutionQueue(readerQueuesInterval),
 		networkReadersQueue:  tasks.NewExecutionQueue(readerQueuesInterval),
@@ -114,10 +193,85 @@ func New(opts Options) Exporter {
 			ValidatorStorage: validatorStorage,
 			IbftStorage:      &ibftStorage,
 			Out:              opts.WS.OutboundFeed(),
+			Verify:           batchVerifier.Verify,
 		}),
-		wsAPIPort:                       opts.WsAPIPort,
-		ibftSyncEnabled:                 opts.IbftSyncEnabled,
-		validatorMetaDataUpdateInterval: opts.ValidatorMetaDataUpdateInterval,
+		batchVerifier:                      batchVerifier,
+		dispatcher:                         dispatcher,
+		wsAPIPort:                          opts.WsAPIPort,
+		ibftSyncEnabled:                    opts.IbftSyncEnabled,
+		validatorMetaDataUpdateInterval:    opts.ValidatorMetaDataUpdateInterval,
+		validatorMetaDataRequestsPerMinute: opts.ValidatorMetaDataRequestsPerMinute,
+		validatorMetaDataTTL:               opts.ValidatorMetaDataTTL,
+		metaDataRefreshes:                  map[string]time.Time{},
+		readOnly:                           opts.ReadOnly,
+		syncFilter:                         control.NewSyncFilter(syncWhitelist),
+	}
+
+	if e.validatorMetaDataTTL <= 0 {
+		e.validatorMetaDataTTL = defaultValidatorMetaDataTTL
+	}
+
+	if len(opts.PeersGeoDBPath) > 0 {
+		resolver, err := geo.Load(opts.PeersGeoDBPath)
+		if err != nil {
+			e.logger.Panic("failed to load geo db", zap.Error(err))
+		}
+		e.geo = resolver
+	}
+
+	if opts.ReadOnly {
+		// no data is written in read only mode, therefore sync related components are not created
+		return &e
+	}
+
+	if opts.LeaderLeaseDuration > 0 {
+		election, err := leader.New(leader.Options{
+			Ctx:           opts.Ctx,
+			Logger:        opts.Logger,
+			DB:            opts.DB,
+			ID:            opts.ReplicaID,
+			LeaseDuration: opts.LeaderLeaseDuration,
+		})
+		if err != nil {
+			e.logger.Panic("failed to create leader election", zap.Error(err))
+		}
+		e.election = election
+	}
+
+	if len(opts.WebhooksOptions.URLs) > 0 && opts.WS != nil {
+		webhooksOpts := opts.WebhooksOptions
+		webhooksOpts.Ctx = opts.Ctx
+		webhooksOpts.Logger = opts.Logger
+		webhooksOpts.Feed = opts.WS.OutboundFeed()
+		e.webhooks = webhooks.New(webhooksOpts)
+	}
+
+	if len(opts.AdminPublicKey) > 0 {
+		pk, err := rsaencryption.ConvertEncodedPemToPublicKey(opts.AdminPublicKey)
+		if err != nil {
+			e.logger.Panic("failed to decode admin public key", zap.Error(err))
+		}
+		e.adminPubKey = pk
+	}
+
+	if len(opts.OperatorsMetadataURL) > 0 {
+		e.operatorsMetadata = NewHTTPOperatorsMetadataProvider(opts.OperatorsMetadataURL)
+		e.operatorsMetadataInterval = opts.OperatorsMetadataInterval
+		if e.operatorsMetadataInterval <= 0 {
+			e.operatorsMetadataInterval = 10 * time.Minute
+		}
+	}
+
+	if len(opts.SinkOptions.Type) > 0 && opts.WS != nil {
+		sinkOpts := opts.SinkOptions
+		sinkOpts.Ctx = opts.Ctx
+		sinkOpts.Logger = opts.Logger
+		sinkOpts.Feed = opts.WS.OutboundFeed()
+		publisher, err := sink.New(sinkOpts)
+		if err != nil {
+			e.logger.Panic("failed to create sink", zap.Error(err))
+		}
+		e.sink = publisher
 	}
 
 	if err := e.init(opts); err != nil {
@@ -128,51 +282,146 @@ func New(opts Options) Exporter {
 }
 
 func (exp *exporter) init(opts Options) error {
+	checkpoints, err := ibft.ParseCheckpoints(opts.DecidedCheckpoints)
+	if err != nil {
+		return errors.Wrap(err, "could not parse decided checkpoints")
+	}
+	exp.checkpoints = checkpoints
 	if opts.CleanRegistryData {
-		if err := exp.validatorStorage.CleanAllShares(); err != nil {
-			return errors.Wrap(err, "could not clean existing shares")
-		}
-		if err := exp.storage.Clean(); err != nil {
-			return errors.Wrap(err, "could not clean existing data")
+		if exp.election != nil {
+			// shared data is cleaned only by the leader
+			exp.cleanRegistryData = true
+			return nil
 		}
-		exp.logger.Debug("manage to cleanup registry data")
+		return exp.cleanRegistry()
 	}
 	return nil
 }
 
+func (exp *exporter) cleanRegistry() error {
+	if err := exp.validatorStorage.CleanAllShares(); err != nil {
+		return errors.Wrap(err, "could not clean existing shares")
+	}
+	if err := exp.storage.Clean(); err != nil {
+		return errors.Wrap(err, "could not clean existing data")
+	}
+	exp.logger.Debug("manage to cleanup registry data")
+	return nil
+}
+
 // Start starts the Controller dispatcher for syncing data nd listen to messages
 func (exp *exporter) Start() error {
 	exp.logger.Info("starting node")
 
+	if exp.readOnly {
+		return exp.startReadOnly()
+	}
+
+	if exp.webhooks != nil {
+		exp.webhooks.Start()
+	}
+	if exp.sink != nil {
+		exp.sink.Start()
+	}
+
+	if exp.election == nil {
+		exp.startSync()
+	} else {
+		go exp.startElection()
+	}
+
+	if exp.ws == nil {
+		return nil
+	}
+
+	exp.ws.UseQueryHandler(exp.handleQueryRequests)
+
+	go exp.reportOperators()
+
+	return exp.ws.Start(fmt.Sprintf(":%d", exp.wsAPIPort))
+}
+
+// startReadOnly starts to serve queries from the existing db, nothing is synced
+func (exp *exporter) startReadOnly() error {
+	if exp.ws == nil {
+		return errors.New("ws api is required in read only mode")
+	}
+	exp.logger.Info("running in read only mode")
+	exp.ws.UseQueryHandler(exp.handleQueryRequests)
+
+	go exp.reportOperators()
+
+	return exp.ws.Start(fmt.Sprintf(":%d", exp.wsAPIPort))
+}
+
+// startElection campaigns for leadership, once elected this replica starts the sync role.
+// other replicas (followers) only serve queries from the shared db
+func (exp *exporter) startElection() {
+	go exp.election.Start()
+
+	select {
+	case <-exp.election.Elected():
+	case <-exp.ctx.Done():
+		return
+	}
+	if exp.cleanRegistryData {
+		if err := exp.cleanRegistry(); err != nil {
+			exp.logger.Error("failed to clean registry data", zap.Error(err))
+		}
+	}
+	if err := exp.startEth1(exp.syncOffset); err != nil {
+		exp.logger.Fatal("failed to start eth1", zap.Error(err))
+	}
+	exp.startSync()
+
+	select {
+	case <-exp.election.Lost():
+		// readers can't be stopped, therefore the replica exits in order to be restarted as a follower
+		exp.logger.Fatal("lost leadership")
+	case <-exp.ctx.Done():
+	}
+}
+
+// startSync starts the sync role: reading messages from the network, validators metadata updates, etc.
+func (exp *exporter) startSync() {
+	if _, err := exp.validatorStorage.MigrateShares(); err != nil {
+		exp.logger.Error("failed to migrate shares", zap.Error(err))
+	}
+	if err := exp.storage.ReindexValidatorsStatus(); err != nil {
+		exp.logger.Error("failed to reindex validators status", zap.Error(err))
+	}
 	go exp.metaDataReadersQueue.Start()
 	if err := exp.warmupValidatorsMetaData(); err != nil {
 		exp.logger.Error("failed to warmup validators metadata", zap.Error(err))
 	}
 	go exp.continuouslyUpdateValidatorMetaData()
+	if exp.operatorsMetadata != nil {
+		go exp.continuouslyUpdateOperatorsMetadata()
+	}
 
 	go exp.mainQueue.Start()
+	go exp.dispatcher.Start()
 	go exp.decidedReadersQueue.Start()
 	go exp.networkReadersQueue.Start()
 
 	if exp.ws == nil {
-		return nil
+		return
 	}
 
-	exp.ws.UseQueryHandler(exp.handleQueryRequests)
-
+	exp.loadControlMessage()
 	go exp.triggerAllValidators()
 
+	go exp.batchVerifier.Start()
+
 	go func() {
 		if err := exp.commitReader.Start(); err != nil {
 			exp.logger.Error("could not start commit reader", zap.Error(err))
 		}
 	}()
 
+	go exp.listenToOperatorHeartbeats()
+	go exp.listenToControlMessages()
 	go exp.startMainTopic()
-
-	go exp.reportOperators()
-
-	return exp.ws.Start(fmt.Sprintf(":%d", exp.wsAPIPort))
 }
 
 // HealthCheck returns a list of issues regards the state of the exporter node
@@ -191,11 +440,14 @@ func (exp *exporter) healthAgents() []metrics.HealthCheckAgent {
 	return agents
 }
 
-// startMainTopic starts to listen to main topic
+// startMainTopic starts to listen to main topic, and to the decided topics of all validators
 func (exp *exporter) startMainTopic() {
 	if err := tasks.Retry(exp.network.SubscribeToMainTopic, 3); err != nil {
 		exp.logger.Error("failed to subscribe to main topic", zap.Error(err))
 	}
+	if err := tasks.Retry(exp.network.SubscribeToDecidedTopics, 3); err != nil {
+		exp.logger.Error("failed to subscribe to decided topics", zap.Error(err))
+	}
 }
 
 // handleQueryRequests waits for incoming messages and
@@ -209,9 +461,31 @@ func (exp *exporter) handleQueryRequests(nm *api.NetworkMessage) {
 	case api.TypeOperator:
 		handleOperatorsQuery(exp.logger, exp.storage, nm)
 	case api.TypeValidator:
-		handleValidatorsQuery(exp.logger, exp.storage, nm)
+		handleValidatorsQuery(exp.logger, exp.storage, nm, exp.refreshStaleMetadata)
+	case api.TypeValidatorsCount:
+		handleValidatorsCountQuery(exp.logger, exp.storage, nm)
+	case api.TypeCommittee:
+		handleCommitteeQuery(exp.logger, exp.storage, exp.ibftStorage, nm)
 	case api.TypeDecided:
 		handleDecidedQuery(exp.logger, exp.storage, exp.ibftStorage, nm)
+	case api.TypeEvent:
+		handleEventsQuery(exp.logger, exp.storage, nm)
+	case api.TypeDecidedProof:
+		handleDecidedProofQuery(exp.logger, exp.storage, exp.validatorStorage, exp.ibftStorage, nm)
+	case api.TypeDecidedHistoryRoot:
+		handleDecidedHistoryRootQuery(exp.logger, exp.storage, exp.ibftStorage, nm)
+	case api.TypeBalanceHistory:
+		handleBalanceHistoryQuery(exp.logger, exp.storage, nm)
+	case api.TypeGossipStats:
+		handleGossipStatsQuery(exp.logger, exp.network, nm)
+	case api.TypeVersions:
+		handleVersionsQuery(exp.logger, exp.storage, exp.network, nm)
+	case api.TypePeersSummary:
+		handlePeersSummaryQuery(exp.logger, exp.network, exp.geo, nm)
+	case api.TypeDecidedLatency:
+		handleDecidedLatencyQuery(exp.logger, exp.storage, nm)
+	case api.TypeControl:
+		exp.handleControlQuery(nm)
 	case api.TypeError:
 		handleErrorQuery(exp.logger, nm)
 	default:
@@ -219,8 +493,20 @@ func (exp *exporter) handleQueryRequests(nm *api.NetworkMessage) {
 	}
 }
 
-// StartEth1 starts the eth1 events sync and streaming
+// StartEth1 starts the eth1 events sync and streaming,
+// if leader election is enabled it will start once this replica is elected. eth1 is not used in read only mode
 func (exp *exporter) StartEth1(syncOffset *eth1.SyncOffset) error {
+	if exp.readOnly {
+		return nil
+	}
+	if exp.election != nil {
+		exp.syncOffset = syncOffset
+		return nil
+	}
+	return exp.startEth1(syncOffset)
+}
+
+func (exp *exporter) startEth1(syncOffset *eth1.SyncOffset) error {
 	exp.logger.Info("starting node -> eth1")
 
 	// sync events
@@ -261,12 +547,7 @@ func (exp *exporter) triggerAllValidators() {
 }
 
 func (exp *exporter) shouldProcessValidator(pubkey string) bool {
-	for _, pk := range syncWhitelist {
-		if pubkey == pk {
-			return true
-		}
-	}
-	return exp.ibftSyncEnabled
+	return exp.syncFilter.ShouldSync(pubkey, exp.ibftSyncEnabled)
 }
 
 func (exp *exporter) triggerValidator(validatorPubKey *bls.PublicKey) error {
@@ -309,21 +590,27 @@ func (exp *exporter) setup(validatorShare *validatorstorage.Share) error {
 
 func (exp *exporter) getDecidedReader(validatorShare *validatorstorage.Share) ibft.Reader {
 	return ibft.NewDecidedReader(ibft.DecidedReaderOptions{
+		Ctx:            exp.ctx,
 		Logger:         exp.logger,
 		Storage:        exp.ibftStorage,
 		Network:        exp.network,
 		Config:         proto.DefaultConsensusParams(),
 		ValidatorShare: validatorShare,
+		Checkpoint:     exp.checkpoints[validatorShare.PublicKey.SerializeToHexStr()],
+		Dispatcher:     exp.dispatcher,
+		Timings:        exp.storage,
+		ETHNetwork:     exp.ethNetwork,
 		Out:            exp.ws.OutboundFeed(),
 	})
 }
 
 func (exp *exporter) getNetworkReader(validatorPubKey *bls.PublicKey) ibft.Reader {
 	return ibft.NewNetworkReader(ibft.IncomingMsgsReaderOptions{
-		Logger:  exp.logger,
-		Network: exp.network,
-		Config:  proto.DefaultConsensusParams(),
-		PK:      validatorPubKey,
+		Logger:     exp.logger,
+		Network:    exp.network,
+		Config:     proto.DefaultConsensusParams(),
+		PK:         validatorPubKey,
+		Dispatcher: exp.dispatcher,
 	})
 }
 
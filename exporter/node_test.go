@@ -4,19 +4,35 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/eth1"
 	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/control"
+	"github.com/bloxapp/ssv/exporter/leader"
+	exporterstorage "github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/operator/heartbeat"
 	"github.com/bloxapp/ssv/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/bloxapp/ssv/utils/tasks"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/async/event"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 var once sync.Once
@@ -112,6 +128,219 @@ func TestExporter_ListenToEth1Events(t *testing.T) {
 	require.Equal(t, len(operators), 1)
 }
 
+func TestExporter_StartEth1WithElection(t *testing.T) {
+	exp, err := newMockExporter()
+	require.NoError(t, err)
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	exp.election, err = leader.New(leader.Options{
+		Ctx:           context.Background(),
+		Logger:        zap.L(),
+		DB:            db,
+		ID:            "replica",
+		LeaseDuration: time.Second,
+	})
+	require.NoError(t, err)
+	offset := eth1.HexStringToSyncOffset("49e08f")
+	// eth1 sync is deferred until the replica is elected
+	require.NoError(t, exp.StartEth1(offset))
+	require.Equal(t, offset, exp.syncOffset)
+	require.False(t, exp.election.IsLeader())
+}
+
+func TestExporter_UpgradeSyncOffset(t *testing.T) {
+	exp, err := newMockExporter()
+	require.NoError(t, err)
+
+	require.NoError(t, exp.upgradeSyncOffset(10))
+	offset, found, err := exp.storage.GetSyncOffset()
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 10, offset.Uint64())
+	// older blocks don't downgrade the offset
+	require.NoError(t, exp.upgradeSyncOffset(5))
+	offset, _, err = exp.storage.GetSyncOffset()
+	require.NoError(t, err)
+	require.EqualValues(t, 10, offset.Uint64())
+}
+
+func TestExporter_UpdateOperatorsMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"publicKey":"01010101","name":"Operator One","logoUrl":"https://example.com/1.png"},{"name":"no key"}]`))
+	}))
+	defer srv.Close()
+
+	exp, err := newMockExporter()
+	require.NoError(t, err)
+	exp.operatorsMetadata = NewHTTPOperatorsMetadataProvider(srv.URL)
+	require.NoError(t, exp.storage.SaveOperatorInformation(&exporterstorage.OperatorInformation{PublicKey: "01010101", Name: "operator1"}))
+
+	require.NoError(t, exp.updateOperatorsMetadata())
+
+	oi, found, err := exp.storage.GetOperatorInformation("01010101")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "Operator One", oi.Metadata.Name)
+	require.Equal(t, "https://example.com/1.png", oi.Metadata.LogoURL)
+
+	srv.Close()
+	require.Error(t, exp.updateOperatorsMetadata())
+}
+
+func TestExporter_RefreshStaleMetadata(t *testing.T) {
+	logex.Build("test", zap.InfoLevel, nil)
+	exp, err := newMockExporter()
+	require.NoError(t, err)
+	exp.metaDataReadersQueue = tasks.NewExecutionQueue(10 * time.Millisecond)
+	go exp.metaDataReadersQueue.Start()
+	defer exp.metaDataReadersQueue.Stop()
+
+	stalePk, freshPk := spec.BLSPubKey{1, 1, 1}, spec.BLSPubKey{2, 2, 2}
+	exp.beacon = beacon.NewMockBeacon(nil, map[spec.BLSPubKey]*v1.Validator{
+		stalePk: {Index: 1, Balance: 32000000000, Status: v1.ValidatorStateActiveOngoing, Validator: &spec.Validator{PublicKey: stalePk}},
+		freshPk: {Index: 2, Balance: 32000000000, Status: v1.ValidatorStateActiveOngoing, Validator: &spec.Validator{PublicKey: freshPk}},
+	})
+	staleHex, freshHex := hex.EncodeToString(stalePk[:]), hex.EncodeToString(freshPk[:])
+	require.NoError(t, exp.storage.SaveValidatorInformation(&exporterstorage.ValidatorInformation{PublicKey: staleHex}))
+	require.NoError(t, exp.storage.SaveValidatorInformation(&exporterstorage.ValidatorInformation{PublicKey: freshHex}))
+	require.NoError(t, exp.storage.UpdateValidatorMetadata(freshHex, &beacon.ValidatorMetadata{Index: 2}))
+
+	nm := &api.NetworkMessage{Msg: api.Message{Type: api.TypeValidator, Filter: api.MessageFilter{From: 0, To: 1}}}
+	exp.handleQueryRequests(nm)
+	results, ok := nm.Msg.Data.([]exporterstorage.ValidatorInformation)
+	require.True(t, ok)
+	require.Len(t, results, 2)
+	// stale metadata is served while the refresh is in progress
+	require.Nil(t, results[0].Metadata)
+	require.EqualValues(t, 0, results[0].MetadataUpdatedAt)
+	require.Greater(t, results[1].MetadataUpdatedAt, int64(0))
+
+	exp.metaDataRefreshesLock.Lock()
+	require.Len(t, exp.metaDataRefreshes, 1)
+	require.Contains(t, exp.metaDataRefreshes, staleHex)
+	exp.metaDataRefreshesLock.Unlock()
+
+	require.Eventually(t, func() bool {
+		vi, found, err := exp.storage.GetValidatorInformation(staleHex)
+		return err == nil && found && vi.Metadata != nil && vi.MetadataUpdatedAt > 0
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// read only replicas don't refresh metadata
+	exp.metaDataRefreshes = map[string]time.Time{}
+	exp.readOnly = true
+	exp.refreshStaleMetadata([]exporterstorage.ValidatorInformation{{PublicKey: staleHex}})
+	require.Len(t, exp.metaDataRefreshes, 0)
+}
+
+func TestExporter_HandleOperatorHeartbeat(t *testing.T) {
+	_, skPem, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+	require.NoError(t, err)
+	pk, err := rsaencryption.ExtractPublicKey(sk)
+	require.NoError(t, err)
+
+	exp, err := newMockExporter()
+	require.NoError(t, err)
+	now := time.Now()
+
+	hb := &network.OperatorHeartbeat{
+		OperatorPubKey: pk,
+		Version:        "SSV-Node:v0.1.0",
+		Commit:         "abc1234",
+		ForkID:         "v0",
+		Timestamp:      now.UnixNano() / int64(time.Millisecond),
+		Resources:      &network.ResourceUsage{CPUPercent: 25, HeapBytes: 1 << 20, Goroutines: 200, DBSizeBytes: 1 << 30},
+	}
+	require.NoError(t, heartbeat.Sign(sk, hb))
+	require.EqualError(t, exp.handleOperatorHeartbeat(hb, now), "unknown operator")
+
+	require.NoError(t, exp.storage.SaveOperatorInformation(&exporterstorage.OperatorInformation{PublicKey: pk, Name: "operator1"}))
+	require.NoError(t, exp.handleOperatorHeartbeat(hb, now))
+	oi, found, err := exp.storage.GetOperatorInformation(pk)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotNil(t, oi.Liveness)
+	require.Equal(t, hb.Timestamp, oi.Liveness.LastSeen)
+	require.Equal(t, "SSV-Node:v0.1.0", oi.Liveness.Version)
+	require.Equal(t, "abc1234", oi.Liveness.Commit)
+	require.Equal(t, "v0", oi.Liveness.ForkID)
+	require.Equal(t, hb.Resources, oi.Liveness.Resources)
+
+	t.Run("stale heartbeat", func(t *testing.T) {
+		err := exp.handleOperatorHeartbeat(hb, now.Add(maxHeartbeatAge+time.Second))
+		require.True(t, errors.Is(err, errs.ErrStaleMessage))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		tampered := *hb
+		tampered.Version = "SSV-Node:v0.2.0"
+		err := exp.handleOperatorHeartbeat(&tampered, now)
+		require.True(t, errors.Is(err, errs.ErrInvalidSignature))
+	})
+}
+
+func TestExporter_HandleControlMessage(t *testing.T) {
+	_, skPem, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+	require.NoError(t, err)
+
+	exp, err := newMockExporter()
+	require.NoError(t, err)
+	now := time.Now()
+	pkHex := "a9cf360aa15fb1d1d30ee2b578dc5884823c19661886ae8b892775ccb3bd96b7d7345569a2aa0b14e4d015c54a6a0c54"
+
+	msg := &network.ControlMessage{
+		Nonce:         1,
+		Timestamp:     now.UnixNano() / int64(time.Millisecond),
+		SyncWhitelist: []string{pkHex},
+	}
+	require.NoError(t, control.Sign(sk, msg))
+	require.EqualError(t, exp.handleControlMessage(msg, now), "control messages are disabled")
+
+	exp.adminPubKey = &sk.PublicKey
+	require.False(t, exp.shouldProcessValidator(pkHex))
+	require.NoError(t, exp.handleControlMessage(msg, now))
+	require.True(t, exp.shouldProcessValidator(pkHex))
+	saved, found, err := exp.storage.GetControlMessage()
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, msg, saved)
+
+	t.Run("replayed message", func(t *testing.T) {
+		err := exp.handleControlMessage(msg, now)
+		require.True(t, errors.Is(err, errs.ErrStaleMessage))
+	})
+
+	t.Run("stale message", func(t *testing.T) {
+		stale := *msg
+		stale.Nonce = 2
+		require.NoError(t, control.Sign(sk, &stale))
+		err := exp.handleControlMessage(&stale, now.Add(maxControlMsgAge+time.Second))
+		require.True(t, errors.Is(err, errs.ErrStaleMessage))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		tampered := *msg
+		tampered.Nonce = 3
+		err := exp.handleControlMessage(&tampered, now)
+		require.True(t, errors.Is(err, errs.ErrInvalidSignature))
+	})
+
+	t.Run("saved message is applied on restart", func(t *testing.T) {
+		restarted := &exporter{storage: exp.storage, logger: exp.logger, syncFilter: control.NewSyncFilter(nil)}
+		restarted.loadControlMessage()
+		require.True(t, restarted.shouldProcessValidator(pkHex))
+		require.EqualValues(t, 1, restarted.syncFilter.Nonce())
+	})
+}
+
 func newMockExporter() (*exporter, error) {
 	logger := zap.L()
 	db, err := storage.GetStorageFactory(basedb.Options{
@@ -142,6 +371,39 @@ func newMockExporter() (*exporter, error) {
 	return e.(*exporter), nil
 }
 
+func TestExporter_ReadOnly(t *testing.T) {
+	logger := zap.L()
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: logger,
+		Path:   "",
+	})
+	require.NoError(t, err)
+	ws := api.NewWsServer(logger, api.NewAdapterMock(logger), nil, nil)
+	e := New(Options{
+		Ctx:      context.Background(),
+		Logger:   logger,
+		DB:       db,
+		WS:       ws,
+		ReadOnly: true,
+	}).(*exporter)
+
+	// eth1 is not used, therefore no client is required
+	require.NoError(t, e.StartEth1(nil))
+	require.Nil(t, e.election)
+	require.Nil(t, e.webhooks)
+	require.Empty(t, e.HealthCheck())
+
+	netMsg := api.NetworkMessage{
+		Msg: api.Message{
+			Type:   api.TypeValidator,
+			Filter: api.MessageFilter{From: 0},
+		},
+	}
+	e.handleQueryRequests(&netMsg)
+	require.Equal(t, api.TypeValidator, netMsg.Msg.Type)
+}
+
 func TestToValidatorInformation(t *testing.T) {
 	initBls()
 	e := validatorAddedMockEvent(t)
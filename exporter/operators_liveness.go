@@ -0,0 +1,82 @@
+package exporter
+
+import (
+	"github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/operator/heartbeat"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+	"time"
+)
+
+const (
+	// maxHeartbeatAge is the max age of an operator heartbeat, older heartbeats are dropped
+	maxHeartbeatAge = 5 * time.Minute
+	// maxHeartbeatSkew is the max clock skew of heartbeats that were sent in the future
+	maxHeartbeatSkew = 30 * time.Second
+	// livenessWindow is the max age of the last heartbeat of operators that are considered live
+	livenessWindow = time.Hour
+)
+
+var (
+	metricsOperatorHeartbeats = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:exporter:operator_heartbeats",
+		Help: "Count operator heartbeats by result (saved or the class of rejection)",
+	}, []string{"result"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsOperatorHeartbeats); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// listenToOperatorHeartbeats aggregates operator heartbeats into the liveness view of the operators
+func (exp *exporter) listenToOperatorHeartbeats() {
+	heartbeatNet, ok := exp.network.(network.OperatorHeartbeats)
+	if !ok {
+		exp.logger.Warn("network does not support operator heartbeats")
+		return
+	}
+	for hb := range heartbeatNet.ReceivedOperatorHeartbeatChan() {
+		if err := exp.handleOperatorHeartbeat(hb, time.Now()); err != nil {
+			metricsOperatorHeartbeats.WithLabelValues(errs.Label(err)).Inc()
+			exp.logger.Debug("dropping operator heartbeat", zap.Error(err))
+			continue
+		}
+		metricsOperatorHeartbeats.WithLabelValues("saved").Inc()
+	}
+}
+
+// handleOperatorHeartbeat verifies the given heartbeat against the registered operator key and saves the liveness
+func (exp *exporter) handleOperatorHeartbeat(hb *network.OperatorHeartbeat, now time.Time) error {
+	sent := time.Unix(0, hb.Timestamp*int64(time.Millisecond))
+	if now.Sub(sent) > maxHeartbeatAge {
+		return errs.Mark(errors.New("stale heartbeat"), errs.ErrStaleMessage)
+	}
+	if sent.Sub(now) > maxHeartbeatSkew {
+		return errs.Mark(errors.New("heartbeat from the future"), errs.ErrStaleMessage)
+	}
+	_, found, err := exp.storage.GetOperatorInformation(hb.OperatorPubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not read operator information")
+	}
+	if !found {
+		return errors.New("unknown operator")
+	}
+	if err := heartbeat.Verify(hb); err != nil {
+		return errs.Mark(err, errs.ErrInvalidSignature)
+	}
+	return exp.storage.SaveOperatorLiveness(&storage.OperatorLiveness{
+		PublicKey: hb.OperatorPubKey,
+		LastSeen:  hb.Timestamp,
+		Version:   hb.Version,
+		Commit:    hb.Commit,
+		ForkID:    hb.ForkID,
+		Resources: hb.Resources,
+	})
+}
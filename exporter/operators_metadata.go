@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bloxapp/ssv/exporter/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	operatorsMetadataTimeout = 30 * time.Second
+	// operatorsMetadataMaxSize is the max size of a response from the operators metadata endpoint
+	operatorsMetadataMaxSize = 10 << 20
+)
+
+// OperatorsMetadataProvider fetches display metadata of operators from an external registry
+type OperatorsMetadataProvider interface {
+	FetchOperatorsMetadata(ctx context.Context) ([]*storage.OperatorMetadata, error)
+}
+
+// httpOperatorsMetadataProvider fetches a JSON array of operators metadata from an HTTPS endpoint
+type httpOperatorsMetadataProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPOperatorsMetadataProvider creates a provider that fetches metadata from the given url
+func NewHTTPOperatorsMetadataProvider(url string) OperatorsMetadataProvider {
+	return &httpOperatorsMetadataProvider{
+		url:    url,
+		client: &http.Client{Timeout: operatorsMetadataTimeout},
+	}
+}
+
+// FetchOperatorsMetadata implements OperatorsMetadataProvider
+func (p *httpOperatorsMetadataProvider) FetchOperatorsMetadata(ctx context.Context) ([]*storage.OperatorMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch operators metadata")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d", res.StatusCode)
+	}
+	var metadata []*storage.OperatorMetadata
+	if err := json.NewDecoder(io.LimitReader(res.Body, operatorsMetadataMaxSize)).Decode(&metadata); err != nil {
+		return nil, errors.Wrap(err, "could not decode operators metadata")
+	}
+	return metadata, nil
+}
+
+func (exp *exporter) continuouslyUpdateOperatorsMetadata() {
+	for {
+		if err := exp.updateOperatorsMetadata(); err != nil {
+			exp.logger.Warn("could not update operators metadata", zap.Error(err))
+		}
+		select {
+		case <-exp.ctx.Done():
+			return
+		case <-time.After(exp.operatorsMetadataInterval):
+		}
+	}
+}
+
+// updateOperatorsMetadata fetches and saves the metadata of operators
+func (exp *exporter) updateOperatorsMetadata() error {
+	metadata, err := exp.operatorsMetadata.FetchOperatorsMetadata(exp.ctx)
+	if err != nil {
+		return err
+	}
+	valid := make([]*storage.OperatorMetadata, 0, len(metadata))
+	for _, m := range metadata {
+		if m == nil || len(m.PublicKey) == 0 {
+			continue
+		}
+		valid = append(valid, m)
+	}
+	if err := exp.storage.SaveOperatorsMetadata(valid); err != nil {
+		return errors.Wrap(err, "could not save operators metadata")
+	}
+	exp.logger.Debug("operators metadata was updated", zap.Int("count", len(valid)))
+	return nil
+}
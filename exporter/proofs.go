@@ -0,0 +1,66 @@
+package exporter
+
+import (
+	"encoding/hex"
+	"sort"
+
+	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/ibft/proto"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+)
+
+// buildDecidedProof creates an inclusion proof of the given decided message,
+// the proof is verified so invalid messages won't be served
+func buildDecidedProof(msg *proto.SignedMessage, share *validatorstorage.Share,
+	operators []storage.OperatorNodeLink) (*api.DecidedProof, error) {
+	root, err := msg.Message.SigningRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	operatorsPks := make(map[uint64]string, len(operators))
+	for _, op := range operators {
+		operatorsPks[op.ID] = op.PublicKey
+	}
+	committee := make([]api.ProofSigner, 0, len(share.Committee))
+	for id, node := range share.Committee {
+		committee = append(committee, api.ProofSigner{
+			ID:                id,
+			PublicKey:         hex.EncodeToString(node.Pk),
+			OperatorPublicKey: operatorsPks[id],
+		})
+	}
+	sort.Slice(committee, func(i, j int) bool {
+		return committee[i].ID < committee[j].ID
+	})
+
+	pks, err := share.PubKeysByID(msg.SignerIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get signers public keys")
+	}
+	aggPK := bls.PublicKey{}
+	signers := make([]api.ProofSigner, len(msg.SignerIds))
+	for i, id := range msg.SignerIds {
+		aggPK.Add(pks[i])
+		signers[i] = api.ProofSigner{
+			ID:                id,
+			PublicKey:         pks[i].SerializeToHexStr(),
+			OperatorPublicKey: operatorsPks[id],
+		}
+	}
+
+	proof := &api.DecidedProof{
+		Message:             msg,
+		SigningRoot:         hex.EncodeToString(root),
+		Signers:             signers,
+		AggregatedPublicKey: aggPK.SerializeToHexStr(),
+		Committee:           committee,
+		Threshold:           share.ThresholdSize(),
+	}
+	if err := proof.Verify(); err != nil {
+		return nil, errors.Wrap(err, "invalid proof")
+	}
+	return proof, nil
+}
@@ -1,23 +1,32 @@
 package exporter
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/geo"
 	"github.com/bloxapp/ssv/exporter/storage"
 	"github.com/bloxapp/ssv/ibft/sync/incoming"
+	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/storage/collections"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
 	"go.uber.org/zap"
+	"strings"
+	"time"
 )
 
 const (
 	unknownError = "unknown error"
+	// peersSummaryWindow is the max age of the last connection of peers that are included in the peers summary
+	peersSummaryWindow = 24 * time.Hour
 )
 
 func handleOperatorsQuery(logger *zap.Logger, storage storage.OperatorsCollection, nm *api.NetworkMessage) {
 	logger.Debug("handles operators request",
 		zap.Int64("from", nm.Msg.Filter.From),
 		zap.Int64("to", nm.Msg.Filter.To),
-		zap.String("pk", nm.Msg.Filter.PublicKey))
+		zap.String("pk", nm.Msg.Filter.PublicKey),
+		zap.String("owner", nm.Msg.Filter.OwnerAddress))
 	operators, err := getOperators(storage, nm.Msg.Filter)
 	res := api.Message{
 		Type:   nm.Msg.Type,
@@ -32,11 +41,16 @@ func handleOperatorsQuery(logger *zap.Logger, storage storage.OperatorsCollectio
 	nm.Msg = res
 }
 
-func handleValidatorsQuery(logger *zap.Logger, s storage.ValidatorsCollection, nm *api.NetworkMessage) {
+// handleValidatorsQuery serves validators from storage, onRead (optional) is called with the results
+// w/o blocking the response, e.g. to refresh stale metadata
+func handleValidatorsQuery(logger *zap.Logger, s storage.ValidatorsCollection, nm *api.NetworkMessage,
+	onRead func(validators []storage.ValidatorInformation)) {
 	logger.Debug("handles validators request",
 		zap.Int64("from", nm.Msg.Filter.From),
 		zap.Int64("to", nm.Msg.Filter.To),
-		zap.String("pk", nm.Msg.Filter.PublicKey))
+		zap.String("pk", nm.Msg.Filter.PublicKey),
+		zap.String("operatorPk", nm.Msg.Filter.OperatorPublicKey),
+		zap.String("status", nm.Msg.Filter.Status))
 	res := api.Message{
 		Type:   nm.Msg.Type,
 		Filter: nm.Msg.Filter,
@@ -47,6 +61,114 @@ func handleValidatorsQuery(logger *zap.Logger, s storage.ValidatorsCollection, n
 		res.Data = []string{"internal error - could not get validators"}
 	} else {
 		res.Data = validators
+		if onRead != nil {
+			onRead(validators)
+		}
+	}
+	nm.Msg = res
+}
+
+func handleValidatorsCountQuery(logger *zap.Logger, s storage.ValidatorsCollection, nm *api.NetworkMessage) {
+	logger.Debug("handles validators count request",
+		zap.String("operatorPk", nm.Msg.Filter.OperatorPublicKey))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	count, err := countValidators(s, nm.Msg.Filter)
+	if err != nil {
+		logger.Warn("failed to count validators", zap.Error(err))
+		res.Data = []string{"internal error - could not count validators"}
+	} else {
+		res.Data = count
+	}
+	nm.Msg = res
+}
+
+func handleCommitteeQuery(logger *zap.Logger, s storage.Storage, ibftStorage collections.Iibft, nm *api.NetworkMessage) {
+	logger.Debug("handles committee request",
+		zap.String("pk", nm.Msg.Filter.PublicKey),
+		zap.String("role", string(nm.Msg.Filter.Role)))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	// the last decided sequence is of attestations unless another role was requested
+	role := nm.Msg.Filter.Role
+	if len(role) == 0 {
+		role = api.RoleAttester
+	}
+	committee, found, err := getCommittee(s, ibftStorage, nm.Msg.Filter.PublicKey, role)
+	if err != nil {
+		logger.Warn("failed to get committee", zap.Error(err))
+		res.Data = []string{"internal error - could not get committee"}
+	} else if !found {
+		res.Data = []string{"internal error - could not find validator"}
+	} else {
+		res.Data = committee
+	}
+	nm.Msg = res
+}
+
+func handleEventsQuery(logger *zap.Logger, s storage.EventsCollection, nm *api.NetworkMessage) {
+	logger.Debug("handles events request",
+		zap.Int64("from", nm.Msg.Filter.From),
+		zap.Int64("to", nm.Msg.Filter.To))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	events, err := s.ListEvents(nm.Msg.Filter.From, nm.Msg.Filter.To)
+	if err != nil {
+		logger.Warn("failed to get events", zap.Error(err))
+		res.Data = []string{"internal error - could not get events"}
+	} else {
+		res.Data = events
+	}
+	nm.Msg = res
+}
+
+func handleBalanceHistoryQuery(logger *zap.Logger, s storage.BalancesCollection, nm *api.NetworkMessage) {
+	logger.Debug("handles balance history request",
+		zap.Int64("from", nm.Msg.Filter.From),
+		zap.Int64("to", nm.Msg.Filter.To),
+		zap.String("pk", nm.Msg.Filter.PublicKey))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	pk := strings.TrimPrefix(nm.Msg.Filter.PublicKey, "0x")
+	if len(pk) == 0 {
+		res.Data = []string{"bad request - missing public key"}
+	} else if history, err := s.GetBalanceHistory(pk, nm.Msg.Filter.From, nm.Msg.Filter.To); err != nil {
+		logger.Warn("failed to get balance history", zap.Error(err))
+		res.Data = []string{"internal error - could not get balance history"}
+	} else {
+		res.Data = history
+	}
+	nm.Msg = res
+}
+
+func handleDecidedLatencyQuery(logger *zap.Logger, s storage.DecidedTimingsCollection, nm *api.NetworkMessage) {
+	logger.Debug("handles decided latency request",
+		zap.Int64("from", nm.Msg.Filter.From),
+		zap.Int64("to", nm.Msg.Filter.To),
+		zap.String("pk", nm.Msg.Filter.PublicKey))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	pk := strings.TrimPrefix(nm.Msg.Filter.PublicKey, "0x")
+	if len(pk) == 0 {
+		res.Data = []string{"bad request - missing public key"}
+	} else if timings, err := s.GetDecidedTimings(pk, nm.Msg.Filter.From, nm.Msg.Filter.To); err != nil {
+		logger.Warn("failed to get decided timings", zap.Error(err))
+		res.Data = []string{"internal error - could not get decided timings"}
+	} else {
+		res.Data = api.DecidedLatency{
+			Timings: timings,
+			Stats:   storage.NewLatencyStats(timings),
+		}
 	}
 	nm.Msg = res
 }
@@ -82,6 +204,146 @@ func handleDecidedQuery(logger *zap.Logger, validatorStorage storage.ValidatorsC
 	nm.Msg = res
 }
 
+func handleDecidedProofQuery(logger *zap.Logger, validatorStorage storage.ValidatorsCollection,
+	shareStorage validatorstorage.ICollection, ibftStorage collections.Iibft, nm *api.NetworkMessage) {
+	logger.Debug("handles decided proof request",
+		zap.Int64("seq", nm.Msg.Filter.From),
+		zap.String("pk", nm.Msg.Filter.PublicKey),
+		zap.String("role", string(nm.Msg.Filter.Role)))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	nm.Msg = res
+	v, found, err := validatorStorage.GetValidatorInformation(nm.Msg.Filter.PublicKey)
+	if err != nil {
+		logger.Warn("failed to get validators", zap.Error(err))
+		nm.Msg.Data = []string{"internal error - could not get validator"}
+		return
+	} else if !found {
+		logger.Warn("validator not found")
+		nm.Msg.Data = []string{"internal error - could not find validator"}
+		return
+	}
+	pkBytes, err := hex.DecodeString(v.PublicKey)
+	if err != nil {
+		nm.Msg.Data = []string{"bad request - invalid public key"}
+		return
+	}
+	share, found, err := shareStorage.GetValidatorShare(pkBytes)
+	if err != nil || !found {
+		logger.Warn("failed to get validator share", zap.Error(err))
+		nm.Msg.Data = []string{"internal error - could not find validator share"}
+		return
+	}
+	identifier := fmt.Sprintf("%s_%s", v.PublicKey, string(nm.Msg.Filter.Role))
+	msg, found, err := ibftStorage.GetDecided([]byte(identifier), uint64(nm.Msg.Filter.From))
+	if err != nil {
+		logger.Warn("failed to get decided message", zap.Error(err))
+		nm.Msg.Data = []string{"internal error - could not get decided message"}
+		return
+	} else if !found {
+		nm.Msg.Data = []string{"internal error - could not find decided message"}
+		return
+	}
+	proof, err := buildDecidedProof(msg, share, v.Operators)
+	if err != nil {
+		logger.Warn("failed to build decided proof", zap.Error(err))
+		nm.Msg.Data = []string{"internal error - could not build decided proof"}
+		return
+	}
+	nm.Msg.Data = proof
+}
+
+func handleDecidedHistoryRootQuery(logger *zap.Logger, validatorStorage storage.ValidatorsCollection,
+	ibftStorage collections.Iibft, nm *api.NetworkMessage) {
+	logger.Debug("handles decided history root request",
+		zap.Int64("to", nm.Msg.Filter.To),
+		zap.String("pk", nm.Msg.Filter.PublicKey),
+		zap.String("role", string(nm.Msg.Filter.Role)))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	nm.Msg = res
+	if nm.Msg.Filter.To < 0 {
+		nm.Msg.Data = []string{"bad request - invalid sequence"}
+		return
+	}
+	v, found, err := validatorStorage.GetValidatorInformation(nm.Msg.Filter.PublicKey)
+	if err != nil {
+		logger.Warn("failed to get validators", zap.Error(err))
+		nm.Msg.Data = []string{"internal error - could not get validator"}
+		return
+	} else if !found {
+		logger.Warn("validator not found")
+		nm.Msg.Data = []string{"internal error - could not find validator"}
+		return
+	}
+	// a zero count stands for the whole history
+	var count uint64
+	if nm.Msg.Filter.To > 0 {
+		count = uint64(nm.Msg.Filter.To) + 1
+	}
+	identifier := fmt.Sprintf("%s_%s", v.PublicKey, string(nm.Msg.Filter.Role))
+	root, found, err := ibftStorage.GetDecidedHistoryRoot([]byte(identifier), count)
+	if err != nil {
+		logger.Warn("failed to get decided history root", zap.Error(err))
+		nm.Msg.Data = []string{"internal error - could not get decided history root"}
+		return
+	} else if !found {
+		nm.Msg.Data = []string{"internal error - could not find decided history"}
+		return
+	}
+	nm.Msg.Data = api.DecidedHistoryRoot{Count: root.Count, Root: hex.EncodeToString(root.Root)}
+}
+
+func handleGossipStatsQuery(logger *zap.Logger, net network.Network, nm *api.NetworkMessage) {
+	logger.Debug("handles gossip stats request",
+		zap.String("pk", nm.Msg.Filter.PublicKey))
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	if stats, ok := getGossipStats(net, nm.Msg.Filter); !ok {
+		res.Data = []string{"bad request - gossip inspection is disabled"}
+	} else {
+		res.Data = stats
+	}
+	nm.Msg = res
+}
+
+func handleVersionsQuery(logger *zap.Logger, s storage.OperatorsCollection, net network.Network, nm *api.NetworkMessage) {
+	logger.Debug("handles versions request")
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	summary, err := getVersionsSummary(s, net, time.Now())
+	if err != nil {
+		logger.Warn("failed to get versions summary", zap.Error(err))
+		res.Data = []string{"internal error - could not get versions summary"}
+	} else {
+		res.Data = summary
+	}
+	nm.Msg = res
+}
+
+func handlePeersSummaryQuery(logger *zap.Logger, net network.Network, resolver *geo.Resolver, nm *api.NetworkMessage) {
+	logger.Debug("handles peers summary request")
+	res := api.Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+	summary, ok := getPeersSummary(net, resolver, time.Now())
+	if !ok {
+		res.Data = []string{"bad request - peers records are not supported by the network"}
+	} else {
+		res.Data = summary
+	}
+	nm.Msg = res
+}
+
 func handleErrorQuery(logger *zap.Logger, nm *api.NetworkMessage) {
 	logger.Warn("handles error message")
 	if _, ok := nm.Msg.Data.([]string); !ok {
@@ -3,15 +3,19 @@ package exporter
 import (
 	"encoding/hex"
 	"fmt"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/geo"
 	"github.com/bloxapp/ssv/exporter/storage"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/ibft/sync"
+	"github.com/bloxapp/ssv/network"
 	ssvstorage "github.com/bloxapp/ssv/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/collections"
 	"github.com/bloxapp/ssv/utils/format"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
@@ -19,6 +23,7 @@ import (
 	"go.uber.org/zap"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestHandleUnknownQuery(t *testing.T) {
@@ -156,6 +161,45 @@ func TestHandleOperatorsQuery(t *testing.T) {
 		require.Equal(t, "03030303", results[0].PublicKey)
 		require.Equal(t, int64(2), results[0].Index)
 	})
+
+	t.Run("query by owner address", func(t *testing.T) {
+		owner := common.HexToAddress("0x3dB7c0D5b5D8ad5BeB6E9a1D3b2c5b4D1B8C6f2e")
+		oi := storage.OperatorInformation{
+			PublicKey:    "04040404",
+			Name:         "my_operator4",
+			OwnerAddress: owner,
+		}
+		require.NoError(t, s.SaveOperatorInformation(&oi))
+
+		nm := api.NetworkMessage{
+			Msg: api.Message{
+				Type:   api.TypeOperator,
+				Filter: api.MessageFilter{OwnerAddress: owner.Hex()},
+			},
+			Err:  nil,
+			Conn: nil,
+		}
+		handleOperatorsQuery(l, s, &nm)
+		require.Equal(t, api.TypeOperator, nm.Msg.Type)
+		results, ok := nm.Msg.Data.([]storage.OperatorInformation)
+		require.True(t, ok)
+		require.Equal(t, 1, len(results))
+		require.Equal(t, "my_operator4", results[0].Name)
+		require.Equal(t, owner, results[0].OwnerAddress)
+	})
+
+	t.Run("query by invalid owner address", func(t *testing.T) {
+		nm := api.NetworkMessage{
+			Msg: api.Message{
+				Type:   api.TypeOperator,
+				Filter: api.MessageFilter{OwnerAddress: "0xinvalid"},
+			},
+			Err:  nil,
+			Conn: nil,
+		}
+		handleOperatorsQuery(l, s, &nm)
+		require.Equal(t, []string{"internal error - could not get operators"}, nm.Msg.Data)
+	})
 }
 
 func TestHandleValidatorsQuery(t *testing.T) {
@@ -189,7 +233,7 @@ func TestHandleValidatorsQuery(t *testing.T) {
 			Err:  nil,
 			Conn: nil,
 		}
-		handleValidatorsQuery(l, s, &nm)
+		handleValidatorsQuery(l, s, &nm, nil)
 		require.Equal(t, api.TypeValidator, nm.Msg.Type)
 		results, ok := nm.Msg.Data.([]storage.ValidatorInformation)
 		require.True(t, ok)
@@ -211,7 +255,7 @@ func TestHandleValidatorsQuery(t *testing.T) {
 			Err:  nil,
 			Conn: nil,
 		}
-		handleValidatorsQuery(l, s, &nm)
+		handleValidatorsQuery(l, s, &nm, nil)
 		require.Equal(t, api.TypeValidator, nm.Msg.Type)
 		results, ok := nm.Msg.Data.([]storage.ValidatorInformation)
 		require.True(t, ok)
@@ -227,7 +271,7 @@ func TestHandleValidatorsQuery(t *testing.T) {
 			Err:  nil,
 			Conn: nil,
 		}
-		handleValidatorsQuery(l, s, &nm)
+		handleValidatorsQuery(l, s, &nm, nil)
 		require.Equal(t, api.TypeValidator, nm.Msg.Type)
 		results, ok := nm.Msg.Data.([]storage.ValidatorInformation)
 		require.True(t, ok)
@@ -235,6 +279,371 @@ func TestHandleValidatorsQuery(t *testing.T) {
 		require.Equal(t, int64(2), results[0].Index)
 		require.Equal(t, "03030303", results[0].PublicKey)
 	})
+
+	t.Run("query by operator", func(t *testing.T) {
+		vi := storage.ValidatorInformation{
+			PublicKey: "04040404",
+			Operators: []storage.OperatorNodeLink{{ID: 1, PublicKey: "05050505"}},
+		}
+		require.NoError(t, s.SaveValidatorInformation(&vi))
+
+		nm := api.NetworkMessage{
+			Msg: api.Message{
+				Type:   api.TypeValidator,
+				Filter: api.MessageFilter{OperatorPublicKey: hex.EncodeToString([]byte{3, 3, 3, 3}), From: 1, To: 2},
+			},
+			Err:  nil,
+			Conn: nil,
+		}
+		handleValidatorsQuery(l, s, &nm, nil)
+		require.Equal(t, api.TypeValidator, nm.Msg.Type)
+		results, ok := nm.Msg.Data.([]storage.ValidatorInformation)
+		require.True(t, ok)
+		require.Equal(t, 2, len(results))
+		require.Equal(t, "02020202", results[0].PublicKey)
+		require.Equal(t, "03030303", results[1].PublicKey)
+
+		nm.Msg = api.Message{
+			Type:   api.TypeValidator,
+			Filter: api.MessageFilter{OperatorPublicKey: "05050505"},
+		}
+		handleValidatorsQuery(l, s, &nm, nil)
+		results, ok = nm.Msg.Data.([]storage.ValidatorInformation)
+		require.True(t, ok)
+		require.Equal(t, 1, len(results))
+		require.Equal(t, "04040404", results[0].PublicKey)
+	})
+}
+
+func TestHandleValidatorsQuery_Status(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	s, _ := newStorageForTest(db, l)
+
+	vis := []storage.ValidatorInformation{
+		{
+			PublicKey: "01010101",
+			Operators: getMockOperatorLinks(),
+			Metadata:  &beacon.ValidatorMetadata{Status: v1.ValidatorStateActiveOngoing, Index: 1},
+		}, {
+			PublicKey: "02020202",
+			Operators: []storage.OperatorNodeLink{{ID: 1, PublicKey: "05050505"}},
+			Metadata:  &beacon.ValidatorMetadata{Status: v1.ValidatorStateActiveOngoing, Index: 2},
+		}, {
+			PublicKey: "03030303",
+			Operators: getMockOperatorLinks(),
+			Metadata:  &beacon.ValidatorMetadata{Status: v1.ValidatorStateExitedSlashed, Index: 3},
+		}, {
+			PublicKey: "04040404",
+			Operators: getMockOperatorLinks(),
+		},
+	}
+	for _, vi := range vis {
+		vi := vi
+		require.NoError(t, s.SaveValidatorInformation(&vi))
+	}
+
+	query := func(filter api.MessageFilter) []storage.ValidatorInformation {
+		nm := api.NetworkMessage{Msg: api.Message{Type: api.TypeValidator, Filter: filter}}
+		handleValidatorsQuery(l, s, &nm, nil)
+		results, ok := nm.Msg.Data.([]storage.ValidatorInformation)
+		require.True(t, ok)
+		return results
+	}
+
+	t.Run("query by status", func(t *testing.T) {
+		results := query(api.MessageFilter{Status: "active"})
+		require.Len(t, results, 2)
+		require.Equal(t, "01010101", results[0].PublicKey)
+		require.Equal(t, "02020202", results[1].PublicKey)
+
+		results = query(api.MessageFilter{Status: "slashed"})
+		require.Len(t, results, 1)
+		require.Equal(t, "03030303", results[0].PublicKey)
+	})
+
+	t.Run("query by status and operator", func(t *testing.T) {
+		results := query(api.MessageFilter{Status: "active", OperatorPublicKey: "05050505"})
+		require.Len(t, results, 1)
+		require.Equal(t, "02020202", results[0].PublicKey)
+	})
+
+	t.Run("query by index presence", func(t *testing.T) {
+		hasIndex := false
+		results := query(api.MessageFilter{HasIndex: &hasIndex})
+		require.Len(t, results, 1)
+		require.Equal(t, "04040404", results[0].PublicKey)
+
+		hasIndex = true
+		results = query(api.MessageFilter{HasIndex: &hasIndex})
+		require.Len(t, results, 3)
+	})
+
+	t.Run("query unknown status", func(t *testing.T) {
+		nm := api.NetworkMessage{Msg: api.Message{Type: api.TypeValidator, Filter: api.MessageFilter{Status: "xxx"}}}
+		handleValidatorsQuery(l, s, &nm, nil)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "internal error - could not get validators", errs[0])
+	})
+
+	t.Run("count", func(t *testing.T) {
+		nm := api.NetworkMessage{Msg: api.Message{Type: api.TypeValidatorsCount}}
+		handleValidatorsCountQuery(l, s, &nm)
+		require.Equal(t, api.TypeValidatorsCount, nm.Msg.Type)
+		count, ok := nm.Msg.Data.(*storage.ValidatorsCount)
+		require.True(t, ok)
+		require.Equal(t, storage.ValidatorsCount{Total: 4, Unknown: 1, Active: 2, Slashed: 1}, *count)
+
+		nm = api.NetworkMessage{Msg: api.Message{Type: api.TypeValidatorsCount,
+			Filter: api.MessageFilter{OperatorPublicKey: "05050505"}}}
+		handleValidatorsCountQuery(l, s, &nm)
+		count, ok = nm.Msg.Data.(*storage.ValidatorsCount)
+		require.True(t, ok)
+		require.Equal(t, storage.ValidatorsCount{Total: 1, Active: 1}, *count)
+	})
+}
+
+func TestHandleEventsQuery(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	s, _ := newStorageForTest(db, l)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.SaveEventInformation(&storage.EventInformation{
+			Type:        "OperatorAdded",
+			BlockNumber: uint64(i),
+			TxHash:      fmt.Sprintf("0x0%d", i),
+			Data:        []byte(`{}`),
+		})
+		require.NoError(t, err)
+	}
+
+	nm := api.NetworkMessage{
+		Msg: api.Message{
+			Type:   api.TypeEvent,
+			Filter: api.MessageFilter{From: 1, To: 2},
+		},
+	}
+	handleEventsQuery(l, s, &nm)
+	require.Equal(t, api.TypeEvent, nm.Msg.Type)
+	results, ok := nm.Msg.Data.([]storage.EventInformation)
+	require.True(t, ok)
+	require.Equal(t, 2, len(results))
+	for _, e := range results {
+		require.GreaterOrEqual(t, e.Index, int64(1))
+		require.Equal(t, "OperatorAdded", e.Type)
+	}
+}
+
+func TestHandleBalanceHistoryQuery(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	s, _ := newStorageForTest(db, l)
+
+	pk := "82e9b36feb8147d3f82c1a03ba246d4a63ac1ce0b1dabbb6991940a06401ab46fb4afbf971a3c145fdad2d4bddd30e12"
+	for epoch := uint64(1); epoch <= 3; epoch++ {
+		require.NoError(t, s.SaveBalanceSnapshot(pk, epoch, 32000000000+epoch))
+	}
+
+	nm := api.NetworkMessage{
+		Msg: api.Message{
+			Type:   api.TypeBalanceHistory,
+			Filter: api.MessageFilter{From: 2, To: 3, PublicKey: "0x" + pk},
+		},
+	}
+	handleBalanceHistoryQuery(l, s, &nm)
+	require.Equal(t, api.TypeBalanceHistory, nm.Msg.Type)
+	results, ok := nm.Msg.Data.([]storage.BalanceSnapshot)
+	require.True(t, ok)
+	require.Equal(t, []storage.BalanceSnapshot{{Epoch: 2, Balance: 32000000002}, {Epoch: 3, Balance: 32000000003}}, results)
+
+	nm = api.NetworkMessage{
+		Msg: api.Message{
+			Type: api.TypeBalanceHistory,
+		},
+	}
+	handleBalanceHistoryQuery(l, s, &nm)
+	errs, ok := nm.Msg.Data.([]string)
+	require.True(t, ok)
+	require.Equal(t, "bad request - missing public key", errs[0])
+}
+
+func TestHandleDecidedLatencyQuery(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	s, _ := newStorageForTest(db, l)
+
+	pk := "82e9b36feb8147d3f82c1a03ba246d4a63ac1ce0b1dabbb6991940a06401ab46fb4afbf971a3c145fdad2d4bddd30e12"
+	for seq := uint64(1); seq <= 4; seq++ {
+		latency := int64(seq * 1000)
+		require.NoError(t, s.SaveDecidedTiming(pk, &storage.DecidedTiming{SeqNumber: seq, Latency: &latency}))
+	}
+
+	nm := api.NetworkMessage{Msg: api.Message{
+		Type:   api.TypeDecidedLatency,
+		Filter: api.MessageFilter{PublicKey: "0x" + pk, From: 2},
+	}}
+	handleDecidedLatencyQuery(l, s, &nm)
+	require.Equal(t, api.TypeDecidedLatency, nm.Msg.Type)
+	res, ok := nm.Msg.Data.(api.DecidedLatency)
+	require.True(t, ok)
+	require.Len(t, res.Timings, 3)
+	require.Equal(t, storage.LatencyStats{Count: 3, P50: 3000, P90: 4000, P99: 4000}, res.Stats)
+
+	nm = api.NetworkMessage{Msg: api.Message{Type: api.TypeDecidedLatency}}
+	handleDecidedLatencyQuery(l, s, &nm)
+	errs, ok := nm.Msg.Data.([]string)
+	require.True(t, ok)
+	require.Equal(t, "bad request - missing public key", errs[0])
+}
+
+type gossipInspectorNetwork struct {
+	network.Network
+	stats []network.PeerGossipStats
+}
+
+func (n *gossipInspectorNetwork) GossipStats() ([]network.PeerGossipStats, bool) {
+	return n.stats, n.stats != nil
+}
+
+func TestHandleGossipStatsQuery(t *testing.T) {
+	_, l, done := newDBAndLoggerForTest()
+	defer done()
+
+	net := &gossipInspectorNetwork{}
+	nm := api.NetworkMessage{Msg: api.Message{Type: api.TypeGossipStats}}
+	handleGossipStatsQuery(l, net, &nm)
+	errs, ok := nm.Msg.Data.([]string)
+	require.True(t, ok)
+	require.Equal(t, "bad request - gossip inspection is disabled", errs[0])
+
+	net.stats = []network.PeerGossipStats{
+		{PeerID: "a", Score: 2, Topics: []network.TopicGossipStats{{Topic: "bloxstaking.ssv.0101"}, {Topic: "bloxstaking.ssv.0202"}}},
+		{PeerID: "b", Score: 1, Topics: []network.TopicGossipStats{{Topic: "bloxstaking.ssv.0202"}}},
+	}
+	nm = api.NetworkMessage{Msg: api.Message{Type: api.TypeGossipStats}}
+	handleGossipStatsQuery(l, net, &nm)
+	require.Equal(t, api.TypeGossipStats, nm.Msg.Type)
+	stats, ok := nm.Msg.Data.([]network.PeerGossipStats)
+	require.True(t, ok)
+	require.Len(t, stats, 2)
+
+	nm = api.NetworkMessage{Msg: api.Message{Type: api.TypeGossipStats, Filter: api.MessageFilter{PublicKey: "0x0101"}}}
+	handleGossipStatsQuery(l, net, &nm)
+	stats, ok = nm.Msg.Data.([]network.PeerGossipStats)
+	require.True(t, ok)
+	require.Len(t, stats, 1)
+	require.Equal(t, "a", stats[0].PeerID)
+	require.Len(t, stats[0].Topics, 1)
+	require.Equal(t, "bloxstaking.ssv.0101", stats[0].Topics[0].Topic)
+}
+
+type peersVersionsNetwork struct {
+	network.Network
+	versions []network.PeerVersion
+}
+
+func (n *peersVersionsNetwork) PeersVersions() []network.PeerVersion {
+	return n.versions
+}
+
+func TestHandleVersionsQuery(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	s, _ := newStorageForTest(db, l)
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	for i, lastSeen := range []int64{now, now, now, now - 2*time.Hour.Milliseconds()} {
+		pk := fmt.Sprintf("0%d0%d", i, i)
+		version := "SSV-Node:v0.1.1"
+		if i == 1 {
+			version = "SSV-Node:v0.1.0"
+		}
+		require.NoError(t, s.SaveOperatorInformation(&storage.OperatorInformation{PublicKey: pk}))
+		require.NoError(t, s.SaveOperatorLiveness(&storage.OperatorLiveness{PublicKey: pk, LastSeen: lastSeen, Version: version}))
+	}
+	// an operator w/o heartbeats
+	require.NoError(t, s.SaveOperatorInformation(&storage.OperatorInformation{PublicKey: "0909"}))
+
+	net := &peersVersionsNetwork{versions: []network.PeerVersion{
+		{PeerID: "a", Version: "SSV-Node:v0.1.1", Commit: "abc", ForkID: "v0"},
+		{PeerID: "b", Version: "SSV-Node:v0.1.1", Commit: "abc", ForkID: "v0"},
+		{PeerID: "c", Version: "SSV-Node:v0.0.9"},
+	}}
+	nm := api.NetworkMessage{Msg: api.Message{Type: api.TypeVersions}}
+	handleVersionsQuery(l, s, net, &nm)
+	require.Equal(t, api.TypeVersions, nm.Msg.Type)
+	summary, ok := nm.Msg.Data.(*api.VersionsSummary)
+	require.True(t, ok)
+	// the operator that wasn't seen recently is not included
+	require.Equal(t, []api.VersionCount{
+		{Version: "SSV-Node:v0.1.1", Count: 2},
+		{Version: "SSV-Node:v0.1.0", Count: 1},
+	}, summary.Operators)
+	require.Equal(t, []api.VersionCount{
+		{Version: "SSV-Node:v0.1.1", Commit: "abc", ForkID: "v0", Count: 2},
+		{Version: "SSV-Node:v0.0.9", Count: 1},
+	}, summary.Peers)
+}
+
+type peersRecordsNetwork struct {
+	network.Network
+	records []network.PeerRecord
+}
+
+func (n *peersRecordsNetwork) PeersRecords(since time.Time) []network.PeerRecord {
+	return n.records
+}
+
+func TestHandlePeersSummaryQuery(t *testing.T) {
+	l := zap.L()
+	net := &peersRecordsNetwork{records: []network.PeerRecord{
+		{PeerID: "a", Version: "SSV-Node:v0.1.1", Commit: "abc", IP: "1.0.0.1", Connected: true},
+		{PeerID: "b", Version: "SSV-Node:v0.1.1", Commit: "abc", IP: "1.0.4.1", Connected: true},
+		{PeerID: "c", Version: "SSV-Node:v0.1.0", IP: "1.0.0.2"},
+		{PeerID: "d", Version: "SSV-Node:v0.1.0"},
+	}}
+	resolver, err := geo.Parse(strings.NewReader("1.0.0.0\t1.0.0.255\t13335\tUS\tCLOUDFLARENET\n1.0.4.0\t1.0.7.255\t38803\tAU\tWPL-AS-AP\n"))
+	require.NoError(t, err)
+
+	nm := api.NetworkMessage{Msg: api.Message{Type: api.TypePeersSummary}}
+	handlePeersSummaryQuery(l, net, resolver, &nm)
+	require.Equal(t, api.TypePeersSummary, nm.Msg.Type)
+	summary, ok := nm.Msg.Data.(*api.PeersSummary)
+	require.True(t, ok)
+	require.Equal(t, 4, summary.Peers)
+	require.Equal(t, 2, summary.Connected)
+	require.Equal(t, []api.VersionCount{
+		{Version: "SSV-Node:v0.1.0", Count: 2},
+		{Version: "SSV-Node:v0.1.1", Commit: "abc", Count: 2},
+	}, summary.Versions)
+	require.Equal(t, []api.CountryCount{
+		{Country: "US", Count: 2},
+		{Country: "AU", Count: 1},
+		{Country: "unknown", Count: 1},
+	}, summary.Countries)
+	require.Equal(t, []api.ASNCount{
+		{ASN: 13335, Description: "CLOUDFLARENET", Count: 2},
+		{Count: 1},
+		{ASN: 38803, Description: "WPL-AS-AP", Count: 1},
+	}, summary.ASNs)
+
+	t.Run("w/o geo db", func(t *testing.T) {
+		nm := api.NetworkMessage{Msg: api.Message{Type: api.TypePeersSummary}}
+		handlePeersSummaryQuery(l, net, nil, &nm)
+		summary, ok := nm.Msg.Data.(*api.PeersSummary)
+		require.True(t, ok)
+		require.Len(t, summary.Versions, 2)
+		require.Nil(t, summary.Countries)
+		require.Nil(t, summary.ASNs)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		nm := api.NetworkMessage{Msg: api.Message{Type: api.TypePeersSummary}}
+		handlePeersSummaryQuery(l, &peersVersionsNetwork{}, resolver, &nm)
+		require.Equal(t, []string{"bad request - peers records are not supported by the network"}, nm.Msg.Data)
+	})
 }
 
 func TestHandleDecidedQuery(t *testing.T) {
@@ -284,6 +693,214 @@ func TestHandleDecidedQuery(t *testing.T) {
 	})
 }
 
+func TestHandleDecidedProofQuery(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	exporterStorage, ibftStorage := newStorageForTest(db, l)
+	shareStorage := validatorstorage.NewCollection(validatorstorage.CollectionOptions{DB: db, Logger: l})
+	_ = bls.Init(bls.BLS12_381)
+
+	sks, nodes := sync.GenerateNodes(4)
+	pk := sks[1].GetPublicKey()
+	identifier := format.IdentifierFormat(pk.Serialize(), beacon.RoleTypeAttester.String())
+	for _, d := range sync.DecidedArr(t, 3, sks, []byte(identifier)) {
+		require.NoError(t, ibftStorage.SaveDecided(d))
+	}
+	require.NoError(t, exporterStorage.SaveValidatorInformation(&storage.ValidatorInformation{
+		PublicKey: pk.SerializeToHexStr(),
+		Operators: getMockOperatorLinks(),
+	}))
+
+	newProofMsg := func(pk string, seq int64) *api.NetworkMessage {
+		nm := newDecidedAPIMsg(pk, seq, seq)
+		nm.Msg.Type = api.TypeDecidedProof
+		return nm
+	}
+
+	t.Run("missing share", func(t *testing.T) {
+		nm := newProofMsg(pk.SerializeToHexStr(), 2)
+		handleDecidedProofQuery(l, exporterStorage, shareStorage, ibftStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "internal error - could not find validator share", errs[0])
+	})
+
+	require.NoError(t, shareStorage.SaveValidatorShare(&validatorstorage.Share{
+		NodeID:    1,
+		PublicKey: pk,
+		Committee: nodes,
+	}))
+
+	t.Run("valid proof", func(t *testing.T) {
+		nm := newProofMsg(pk.SerializeToHexStr(), 2)
+		handleDecidedProofQuery(l, exporterStorage, shareStorage, ibftStorage, nm)
+		proof, ok := nm.Msg.Data.(*api.DecidedProof)
+		require.True(t, ok)
+		require.NoError(t, proof.Verify())
+		require.EqualValues(t, 2, proof.Message.Message.SeqNumber)
+		require.Len(t, proof.Signers, 3)
+		require.Len(t, proof.Committee, 4)
+		require.Equal(t, 3, proof.Threshold)
+		require.Equal(t, hex.EncodeToString([]byte{3, 3, 3, 3}), proof.Signers[2].OperatorPublicKey)
+
+		// tampered proofs should fail
+		proof.Message.Message.Value = []byte{1}
+		require.EqualError(t, proof.Verify(), "signing root mismatch")
+		proof.Message.Message.Type = proto.RoundState_Decided
+		require.EqualError(t, proof.Verify(), "message is not a decided message")
+	})
+
+	t.Run("insufficient signers", func(t *testing.T) {
+		nm := newProofMsg(pk.SerializeToHexStr(), 3)
+		handleDecidedProofQuery(l, exporterStorage, shareStorage, ibftStorage, nm)
+		proof, ok := nm.Msg.Data.(*api.DecidedProof)
+		require.True(t, ok)
+		proof.Signers = proof.Signers[:2]
+		require.EqualError(t, proof.Verify(), "not enough signers (2/3)")
+	})
+
+	t.Run("non-exist seq", func(t *testing.T) {
+		nm := newProofMsg(pk.SerializeToHexStr(), 10)
+		handleDecidedProofQuery(l, exporterStorage, shareStorage, ibftStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "internal error - could not find decided message", errs[0])
+	})
+
+	t.Run("non-exist validator", func(t *testing.T) {
+		nm := newProofMsg("xxx", 1)
+		handleDecidedProofQuery(l, exporterStorage, shareStorage, ibftStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "internal error - could not find validator", errs[0])
+	})
+}
+
+func TestHandleDecidedHistoryRootQuery(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	exporterStorage, ibftStorage := newStorageForTest(db, l)
+	_ = bls.Init(bls.BLS12_381)
+
+	sks, _ := sync.GenerateNodes(4)
+	pk := sks[1].GetPublicKey()
+	identifier := format.IdentifierFormat(pk.Serialize(), beacon.RoleTypeAttester.String())
+	for _, d := range sync.DecidedArr(t, 20, sks, []byte(identifier)) {
+		require.NoError(t, ibftStorage.SaveDecided(d))
+	}
+	require.NoError(t, exporterStorage.SaveValidatorInformation(&storage.ValidatorInformation{
+		PublicKey: pk.SerializeToHexStr(),
+	}))
+
+	newHistoryRootMsg := func(pk string, to int64) *api.NetworkMessage {
+		nm := newDecidedAPIMsg(pk, 0, to)
+		nm.Msg.Type = api.TypeDecidedHistoryRoot
+		return nm
+	}
+
+	t.Run("whole history", func(t *testing.T) {
+		nm := newHistoryRootMsg(pk.SerializeToHexStr(), 0)
+		handleDecidedHistoryRootQuery(l, exporterStorage, ibftStorage, nm)
+		res, ok := nm.Msg.Data.(api.DecidedHistoryRoot)
+		require.True(t, ok)
+		require.Equal(t, uint64(21), res.Count)
+		expected, found, err := ibftStorage.GetDecidedHistoryRoot([]byte(identifier), 0)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, hex.EncodeToString(expected.Root), res.Root)
+	})
+
+	t.Run("partial history", func(t *testing.T) {
+		nm := newHistoryRootMsg(pk.SerializeToHexStr(), 9)
+		handleDecidedHistoryRootQuery(l, exporterStorage, ibftStorage, nm)
+		res, ok := nm.Msg.Data.(api.DecidedHistoryRoot)
+		require.True(t, ok)
+		require.Equal(t, uint64(10), res.Count)
+		expected, found, err := ibftStorage.GetDecidedHistoryRoot([]byte(identifier), 10)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, hex.EncodeToString(expected.Root), res.Root)
+	})
+
+	t.Run("exceeding history", func(t *testing.T) {
+		nm := newHistoryRootMsg(pk.SerializeToHexStr(), 40)
+		handleDecidedHistoryRootQuery(l, exporterStorage, ibftStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "internal error - could not find decided history", errs[0])
+	})
+
+	t.Run("non-exist validator", func(t *testing.T) {
+		nm := newHistoryRootMsg("xxx", 0)
+		handleDecidedHistoryRootQuery(l, exporterStorage, ibftStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "internal error - could not find validator", errs[0])
+	})
+}
+
+func TestHandleCommitteeQuery(t *testing.T) {
+	db, l, done := newDBAndLoggerForTest()
+	defer done()
+	exporterStorage, ibftStorage := newStorageForTest(db, l)
+	_ = bls.Init(bls.BLS12_381)
+
+	sks, _ := sync.GenerateNodes(4)
+	pk := sks[1].GetPublicKey()
+	identifier := format.IdentifierFormat(pk.Serialize(), beacon.RoleTypeAttester.String())
+	for _, d := range sync.DecidedArr(t, 5, sks, []byte(identifier)) {
+		require.NoError(t, ibftStorage.SaveDecidedAndHighest(d))
+	}
+	require.NoError(t, exporterStorage.SaveOperatorInformation(&storage.OperatorInformation{
+		PublicKey: "op1",
+		Name:      "operator-1",
+		Metadata:  &storage.OperatorMetadata{PublicKey: "op1", Name: "Operator One"},
+	}))
+	require.NoError(t, exporterStorage.SaveValidatorInformation(&storage.ValidatorInformation{
+		PublicKey: pk.SerializeToHexStr(),
+		Metadata:  &beacon.ValidatorMetadata{Status: v1.ValidatorStateActiveOngoing},
+		Operators: []storage.OperatorNodeLink{{ID: 1, PublicKey: "op1"}, {ID: 2, PublicKey: "op2"}},
+	}))
+
+	newCommitteeMsg := func(pk string, role api.DutyRole) *api.NetworkMessage {
+		return &api.NetworkMessage{Msg: api.Message{
+			Type:   api.TypeCommittee,
+			Filter: api.MessageFilter{PublicKey: pk, Role: role},
+		}}
+	}
+
+	t.Run("committee", func(t *testing.T) {
+		nm := newCommitteeMsg(pk.SerializeToHexStr(), "")
+		handleCommitteeQuery(l, exporterStorage, ibftStorage, nm)
+		require.Equal(t, api.TypeCommittee, nm.Msg.Type)
+		committee, ok := nm.Msg.Data.(*api.Committee)
+		require.True(t, ok)
+		require.Equal(t, pk.SerializeToHexStr(), committee.PublicKey)
+		require.Equal(t, storage.ValidatorStatusActive, committee.Status)
+		require.Equal(t, []api.CommitteeOperator{
+			{ID: 1, PublicKey: "op1", Name: "operator-1", Metadata: &storage.OperatorMetadata{PublicKey: "op1", Name: "Operator One"}},
+			// an unknown operator
+			{ID: 2, PublicKey: "op2"},
+		}, committee.Operators)
+		require.NotNil(t, committee.LastDecidedSeq)
+		require.Equal(t, uint64(5), *committee.LastDecidedSeq)
+	})
+
+	t.Run("nothing decided", func(t *testing.T) {
+		nm := newCommitteeMsg(pk.SerializeToHexStr(), api.RoleProposer)
+		handleCommitteeQuery(l, exporterStorage, ibftStorage, nm)
+		committee, ok := nm.Msg.Data.(*api.Committee)
+		require.True(t, ok)
+		require.Nil(t, committee.LastDecidedSeq)
+	})
+
+	t.Run("unknown validator", func(t *testing.T) {
+		nm := newCommitteeMsg("xxx", "")
+		handleCommitteeQuery(l, exporterStorage, ibftStorage, nm)
+		require.Equal(t, []string{"internal error - could not find validator"}, nm.Msg.Data)
+	})
+}
+
 func newDecidedAPIMsg(pk string, from, to int64) *api.NetworkMessage {
 	return &api.NetworkMessage{
 		Msg: api.Message{
@@ -3,9 +3,15 @@ package exporter
 import (
 	"fmt"
 	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/exporter/geo"
 	"github.com/bloxapp/ssv/exporter/storage"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/storage/collections"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"sort"
+	"strings"
+	"time"
 )
 
 // operatorIndexSorter sorts operators by Index
@@ -27,6 +33,15 @@ func getOperators(s storage.OperatorsCollection, filter api.MessageFilter) ([]st
 			return nil, errors.Wrap(err, "could not read operator")
 		}
 		operators = append(operators, *operator)
+	} else if len(filter.OwnerAddress) > 0 {
+		if !common.IsHexAddress(filter.OwnerAddress) {
+			return nil, errors.Errorf("invalid owner address %s", filter.OwnerAddress)
+		}
+		var err error
+		operators, err = s.ListOperatorsByOwner(common.HexToAddress(filter.OwnerAddress))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read operators of owner")
+		}
 	} else {
 		var err error
 		operators, err = s.ListOperators(filter.From, filter.To)
@@ -57,6 +72,21 @@ func getValidators(s storage.ValidatorsCollection, filter api.MessageFilter) ([]
 			return nil, errors.Wrap(err, "could not read validator")
 		}
 		validators = append(validators, *validator)
+	} else if len(filter.OperatorPublicKey) > 0 {
+		var err error
+		validators, err = s.ListValidatorsByOperator(filter.OperatorPublicKey, filter.From, filter.To)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read validators of operator")
+		}
+	} else if len(filter.Status) > 0 {
+		status, err := storage.ParseValidatorStatus(filter.Status)
+		if err != nil {
+			return nil, err
+		}
+		validators, err = s.ListValidatorsByStatus(status, filter.From, filter.To)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read validators by status")
+		}
 	} else {
 		var err error
 		validators, err = s.ListValidators(filter.From, filter.To)
@@ -64,6 +94,246 @@ func getValidators(s storage.ValidatorsCollection, filter api.MessageFilter) ([]
 			return nil, errors.Wrap(err, "could not read validators")
 		}
 	}
+	validators, err := filterValidators(validators, filter)
+	if err != nil {
+		return nil, err
+	}
 	sort.Sort(validatorIndexSorter(validators))
 	return validators, nil
 }
+
+// filterValidators applies the status and index filters on the given validators
+func filterValidators(validators []storage.ValidatorInformation, filter api.MessageFilter) ([]storage.ValidatorInformation, error) {
+	if len(filter.Status) == 0 && filter.HasIndex == nil {
+		return validators, nil
+	}
+	var status storage.ValidatorStatus
+	if len(filter.Status) > 0 {
+		var err error
+		if status, err = storage.ParseValidatorStatus(filter.Status); err != nil {
+			return nil, err
+		}
+	}
+	var res []storage.ValidatorInformation
+	for _, vi := range validators {
+		if len(status) > 0 && vi.Status() != status {
+			continue
+		}
+		if filter.HasIndex != nil && (vi.Metadata != nil) != *filter.HasIndex {
+			continue
+		}
+		res = append(res, vi)
+	}
+	return res, nil
+}
+
+// countValidators returns the number of validators by status, optionally of a specific operator
+func countValidators(s storage.ValidatorsCollection, filter api.MessageFilter) (*storage.ValidatorsCount, error) {
+	if len(filter.OperatorPublicKey) == 0 {
+		count, err := s.CountValidators()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not count validators")
+		}
+		return count, nil
+	}
+	validators, err := s.ListValidatorsByOperator(filter.OperatorPublicKey, 0, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read validators of operator")
+	}
+	count := storage.ValidatorsCount{}
+	for _, vi := range validators {
+		count.Add(vi.Status(), 1)
+	}
+	return &count, nil
+}
+
+// getGossipStats returns the gossip stats of the network peers, false if not supported or disabled.
+// if a validator public key was provided, only the topic of that validator is included
+func getGossipStats(net network.Network, filter api.MessageFilter) ([]network.PeerGossipStats, bool) {
+	gi, ok := net.(network.GossipInspector)
+	if !ok {
+		return nil, false
+	}
+	stats, ok := gi.GossipStats()
+	if !ok {
+		return nil, false
+	}
+	pk := strings.TrimPrefix(filter.PublicKey, "0x")
+	if len(pk) == 0 {
+		return stats, true
+	}
+	res := make([]network.PeerGossipStats, 0)
+	for _, ps := range stats {
+		var topics []network.TopicGossipStats
+		for _, ts := range ps.Topics {
+			if strings.HasSuffix(ts.Topic, pk) {
+				topics = append(topics, ts)
+			}
+		}
+		if len(topics) > 0 {
+			ps.Topics = topics
+			res = append(res, ps)
+		}
+	}
+	return res, true
+}
+
+// getCommittee returns the committee of the given validator, joined with the operators information
+// and the last decided sequence of the given role. returns false if the validator was not found
+func getCommittee(s storage.Storage, ibftStorage collections.Iibft, pubKey string, role api.DutyRole) (*api.Committee, bool, error) {
+	v, found, err := s.GetValidatorInformation(pubKey)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not get validator")
+	} else if !found {
+		return nil, false, nil
+	}
+	committee := api.Committee{
+		PublicKey:         v.PublicKey,
+		Index:             v.Index,
+		Status:            v.Status(),
+		MetadataUpdatedAt: v.MetadataUpdatedAt,
+		Operators:         make([]api.CommitteeOperator, 0, len(v.Operators)),
+	}
+	for _, link := range v.Operators {
+		co := api.CommitteeOperator{ID: link.ID, PublicKey: link.PublicKey}
+		oi, found, err := s.GetOperatorInformation(link.PublicKey)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "could not get operator")
+		}
+		if found {
+			co.Name = oi.Name
+			co.Metadata = oi.Metadata
+		}
+		committee.Operators = append(committee.Operators, co)
+	}
+	identifier := fmt.Sprintf("%s_%s", v.PublicKey, string(role))
+	highest, found, err := ibftStorage.GetHighestDecidedInstance([]byte(identifier))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not get highest decided")
+	}
+	if found && highest.Message != nil {
+		seq := highest.Message.SeqNumber
+		committee.LastDecidedSeq = &seq
+	}
+	return &committee, true, nil
+}
+
+// getVersionsSummary returns the versions distribution of the operators that were seen in the liveness window,
+// and of the connected peers if supported by the network
+func getVersionsSummary(s storage.OperatorsCollection, net network.Network, now time.Time) (*api.VersionsSummary, error) {
+	operators, err := s.ListOperators(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	since := now.Add(-livenessWindow).UnixNano() / int64(time.Millisecond)
+	var operatorVersions []api.VersionCount
+	for _, oi := range operators {
+		if oi.Liveness == nil || oi.Liveness.LastSeen < since {
+			continue
+		}
+		operatorVersions = append(operatorVersions, api.VersionCount{
+			Version: oi.Liveness.Version,
+			Commit:  oi.Liveness.Commit,
+			ForkID:  oi.Liveness.ForkID,
+		})
+	}
+	var peerVersions []api.VersionCount
+	if pv, ok := net.(network.PeersVersions); ok {
+		for _, p := range pv.PeersVersions() {
+			peerVersions = append(peerVersions, api.VersionCount{
+				Version: p.Version,
+				Commit:  p.Commit,
+				ForkID:  p.ForkID,
+			})
+		}
+	}
+	return &api.VersionsSummary{
+		Operators: countVersions(operatorVersions),
+		Peers:     countVersions(peerVersions),
+	}, nil
+}
+
+// getPeersSummary returns the versions, countries and ASNs distribution of the peers that were seen in the summary window,
+// countries and ASNs are resolved only if a geo resolver was provided. returns false if peers records are not supported by the network
+func getPeersSummary(net network.Network, resolver *geo.Resolver, now time.Time) (*api.PeersSummary, bool) {
+	pr, ok := net.(network.PeersRecords)
+	if !ok {
+		return nil, false
+	}
+	records := pr.PeersRecords(now.Add(-peersSummaryWindow))
+	summary := api.PeersSummary{Peers: len(records)}
+	versions := make([]api.VersionCount, 0, len(records))
+	countries := make(map[string]int)
+	asns := make(map[api.ASNCount]int)
+	for _, r := range records {
+		if r.Connected {
+			summary.Connected++
+		}
+		versions = append(versions, api.VersionCount{
+			Version: r.Version,
+			Commit:  r.Commit,
+			ForkID:  r.ForkID,
+		})
+		if resolver == nil {
+			continue
+		}
+		info, ok := resolver.Lookup(r.IP)
+		if !ok {
+			countries["unknown"]++
+			asns[api.ASNCount{}]++
+			continue
+		}
+		countries[info.Country]++
+		asns[api.ASNCount{ASN: info.ASN, Description: info.Description}]++
+	}
+	summary.Versions = countVersions(versions)
+	if resolver == nil {
+		return &summary, true
+	}
+	for country, count := range countries {
+		summary.Countries = append(summary.Countries, api.CountryCount{Country: country, Count: count})
+	}
+	sort.Slice(summary.Countries, func(i, j int) bool {
+		if summary.Countries[i].Count != summary.Countries[j].Count {
+			return summary.Countries[i].Count > summary.Countries[j].Count
+		}
+		return summary.Countries[i].Country < summary.Countries[j].Country
+	})
+	for asn, count := range asns {
+		asn.Count = count
+		summary.ASNs = append(summary.ASNs, asn)
+	}
+	sort.Slice(summary.ASNs, func(i, j int) bool {
+		if summary.ASNs[i].Count != summary.ASNs[j].Count {
+			return summary.ASNs[i].Count > summary.ASNs[j].Count
+		}
+		return summary.ASNs[i].ASN < summary.ASNs[j].ASN
+	})
+	return &summary, true
+}
+
+// countVersions groups the given versions, sorted by count (descending) and version
+func countVersions(versions []api.VersionCount) []api.VersionCount {
+	counts := make(map[api.VersionCount]int)
+	for _, v := range versions {
+		counts[v]++
+	}
+	res := make([]api.VersionCount, 0, len(counts))
+	for v, count := range counts {
+		v.Count = count
+		res = append(res, v)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		if res[i].Version != res[j].Version {
+			return res[i].Version < res[j].Version
+		}
+		if res[i].Commit != res[j].Commit {
+			return res[i].Commit < res[j].Commit
+		}
+		return res[i].ForkID < res[j].ForkID
+	})
+	return res
+}
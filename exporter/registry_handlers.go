@@ -2,10 +2,12 @@ package exporter
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"github.com/bloxapp/ssv/eth1"
 	"github.com/bloxapp/ssv/exporter/api"
 	"github.com/bloxapp/ssv/exporter/storage"
 	"github.com/bloxapp/ssv/validator"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/async/event"
@@ -24,6 +26,8 @@ func (exp *exporter) listenToEth1Events(eventsFeed *event.Feed) <-chan error {
 			case event := <-cn:
 				if err := exp.handleEth1Event(*event); err != nil {
 					cnErr <- err
+				} else if err := exp.upgradeSyncOffset(event.Log.BlockNumber); err != nil {
+					cnErr <- err
 				}
 			case err := <-sub.Err():
 				cnErr <- err
@@ -33,30 +37,106 @@ func (exp *exporter) listenToEth1Events(eventsFeed *event.Feed) <-chan error {
 	return cnErr
 }
 
+// upgradeSyncOffset saves the block of a handled (live) event as the sync offset,
+// so a new leader or a restarted replica continues from the last handled event rather than from the last sync
+func (exp *exporter) upgradeSyncOffset(blockNumber uint64) error {
+	offset, found, err := exp.storage.GetSyncOffset()
+	if err != nil {
+		return errors.Wrap(err, "could not get sync offset")
+	}
+	if found && offset != nil && offset.Uint64() >= blockNumber {
+		return nil
+	}
+	if err := exp.storage.SaveSyncOffset(new(eth1.SyncOffset).SetUint64(blockNumber)); err != nil {
+		return errors.Wrap(err, "could not upgrade sync offset")
+	}
+	return nil
+}
+
 // ListenToEth1Events register for eth1 events
 func (exp *exporter) handleEth1Event(e eth1.Event) error {
+	if err := exp.archiveEth1Event(e); err != nil {
+		exp.logger.Warn("could not archive eth1 event", zap.Error(err))
+	}
 	var err error = nil
 	if validatorAddedEvent, ok := e.Data.(eth1.ValidatorAddedEvent); ok {
-		err = exp.handleValidatorAddedEvent(validatorAddedEvent)
+		err = exp.handleValidatorAddedEvent(validatorAddedEvent, e.Log.BlockNumber)
+	} else if removedEvent, ok := e.Data.(eth1.ValidatorRemovedEvent); ok {
+		err = exp.handleValidatorRemovedEvent(removedEvent, e.Log.BlockNumber)
 	} else if opertaorAddedEvent, ok := e.Data.(eth1.OperatorAddedEvent); ok {
 		err = exp.handleOperatorAddedEvent(opertaorAddedEvent)
 	}
 	return err
 }
 
-// handleValidatorAddedEvent parses the given event and sync the ibft-data of the validator
-func (exp *exporter) handleValidatorAddedEvent(event eth1.ValidatorAddedEvent) error {
+// archiveEth1Event saves the raw event and notifies the stream, events that were already archived are ignored
+func (exp *exporter) archiveEth1Event(e eth1.Event) error {
+	if len(e.Name) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal event data")
+	}
+	ei := storage.EventInformation{
+		Type:        e.Name,
+		BlockNumber: e.Log.BlockNumber,
+		TxHash:      e.Log.TxHash.Hex(),
+		LogIndex:    e.Log.Index,
+		Data:        data,
+	}
+	saved, err := exp.storage.SaveEventInformation(&ei)
+	if err != nil {
+		return errors.Wrap(err, "failed to save event information")
+	}
+	if !saved {
+		return nil
+	}
+	go func() {
+		n := exp.ws.OutboundFeed().Send(&api.NetworkMessage{Msg: api.Message{
+			Type:   api.TypeEvent,
+			Filter: api.MessageFilter{From: ei.Index, To: ei.Index},
+			Data:   []storage.EventInformation{ei},
+		}, Conn: nil})
+		exp.logger.Debug("msg was sent on outbound feed", zap.String("eventType", ei.Type),
+			zap.Int("num of subscribers", n))
+	}()
+	return nil
+}
+
+// handleValidatorAddedEvent parses the given event and sync the ibft-data of the validator,
+// events that precede the removal of the validator are ignored
+func (exp *exporter) handleValidatorAddedEvent(event eth1.ValidatorAddedEvent, blockNumber uint64) error {
 	pubKeyHex := hex.EncodeToString(event.PublicKey)
 	logger := exp.logger.With(zap.String("eventType", "ValidatorAdded"), zap.String("pubKey", pubKeyHex))
 	logger.Info("validator added event")
+	tombstone, found, err := exp.validatorStorage.GetTombstone(event.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get validator tombstone")
+	}
+	if found {
+		if tombstone.Covers(blockNumber) {
+			logger.Debug("validator was removed, ignoring stale event", zap.Uint64("block", blockNumber))
+			return nil
+		}
+		if err := exp.validatorStorage.DeleteTombstone(event.PublicKey); err != nil {
+			return errors.Wrap(err, "could not delete validator tombstone")
+		}
+	}
 	// save the share to be able to reuse IBFT functionality
 	validatorShare, _, err := validator.ShareFromValidatorAddedEvent(event, "")
 	if err != nil {
 		return errors.Wrap(err, "could not create a share from ValidatorAddedEvent")
 	}
-	if err := exp.validatorStorage.SaveValidatorShare(validatorShare); err != nil {
+	validatorShare.BlockNumber = blockNumber
+	saved, err := exp.validatorStorage.MergeValidatorShare(validatorShare)
+	if err != nil {
 		return errors.Wrap(err, "failed to save validator share")
 	}
+	if !saved {
+		logger.Debug("validator share of a newer event exists, ignoring stale event", zap.Uint64("block", blockNumber))
+		return nil
+	}
 	logger.Debug("validator share was saved")
 	// save information for exporting validators
 	vi, err := toValidatorInformation(event)
@@ -86,6 +166,31 @@ func (exp *exporter) handleValidatorAddedEvent(event eth1.ValidatorAddedEvent) e
 	return nil
 }
 
+// handleValidatorRemovedEvent replaces the share of the given validator with a tombstone,
+// the validator information is kept
+func (exp *exporter) handleValidatorRemovedEvent(event eth1.ValidatorRemovedEvent, blockNumber uint64) error {
+	logger := exp.logger.With(zap.String("eventType", "ValidatorRemoved"),
+		zap.String("pubKey", hex.EncodeToString(event.PublicKey)))
+	logger.Info("validator removed event")
+	_, found, err := exp.validatorStorage.GetValidatorShare(event.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get validator share")
+	}
+	if !found {
+		logger.Debug("validator share was not found")
+		return nil
+	}
+	err = exp.validatorStorage.RemoveValidatorShare(event.PublicKey, &validatorstorage.Tombstone{
+		Reason:      validatorstorage.TombstoneReasonRemovedEvent,
+		BlockNumber: blockNumber,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to remove validator share")
+	}
+	logger.Debug("validator share was removed")
+	return nil
+}
+
 // handleOperatorAddedEvent parses the given event and saves operator information
 func (exp *exporter) handleOperatorAddedEvent(event eth1.OperatorAddedEvent) error {
 	logger := exp.logger.With(zap.String("eventType", "OperatorAdded"),
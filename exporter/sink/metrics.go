@@ -0,0 +1,27 @@
+package sink
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsSinkEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:exporter:sink_events",
+		Help: "Count message bus publish attempts by event type and status (published, failed, dropped)",
+	}, []string{"type", "status"})
+	metricsSinkQueue = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:exporter:sink_queue",
+		Help: "Number of events that are pending to be published",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsSinkEvents); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsSinkQueue); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
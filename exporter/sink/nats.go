@@ -0,0 +1,67 @@
+package sink
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// natsSink publishes to NATS JetStream, a message is considered as delivered once JetStream acknowledged it,
+// therefore subjects must be bound to a JetStream stream
+type natsSink struct {
+	conn       *nats.Conn
+	js         nats.JetStreamContext
+	ackTimeout time.Duration
+}
+
+// newNATS connects to the nats server, the connection is retried (in the background) until it succeeds
+func newNATS(logger *zap.Logger, opts Options, tlsConfig *tls.Config) (*natsSink, error) {
+	natsOpts := []nats.Option{
+		nats.Name("ssv-exporter"),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			logger.Warn("disconnected from nats", zap.Error(err))
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info("reconnected to nats", zap.String("url", conn.ConnectedUrl()))
+		}),
+	}
+	if tlsConfig != nil {
+		natsOpts = append(natsOpts, nats.Secure(tlsConfig))
+	}
+	if len(opts.CredentialsFile) > 0 {
+		natsOpts = append(natsOpts, nats.UserCredentials(opts.CredentialsFile))
+	}
+	conn, err := nats.Connect(opts.URL, natsOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to nats")
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not create jetstream context")
+	}
+	return &natsSink{
+		conn:       conn,
+		js:         js,
+		ackTimeout: opts.AckTimeout,
+	}, nil
+}
+
+// Publish publishes the payload and waits for its acknowledgement
+func (n *natsSink) Publish(subject string, payload []byte) error {
+	if _, err := n.js.Publish(subject, payload, nats.AckWait(n.ackTimeout)); err != nil {
+		return errors.Wrap(err, "publish failed")
+	}
+	return nil
+}
+
+// Close closes the connection
+func (n *natsSink) Close() error {
+	n.conn.Close()
+	return nil
+}
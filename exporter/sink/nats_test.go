@@ -0,0 +1,157 @@
+package sink
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNATS is a minimal NATS server that acknowledges published messages as JetStream does
+type fakeNATS struct {
+	listener net.Listener
+	lock     sync.Mutex
+	received map[string][]string
+	// subs maps subscribed subjects (the reply inboxes) to their sid
+	subs map[string]string
+	// failNext is the number of publishes to reject
+	failNext int
+	seq      int
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeNATS{listener: l, received: map[string][]string{}, subs: map[string]string{}}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATS) url() string {
+	return fmt.Sprintf("nats://user:pass@%s", s.listener.Addr().String())
+}
+
+func (s *fakeNATS) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	_, _ = conn.Write([]byte("INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n"))
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "PING":
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		case "SUB":
+			s.lock.Lock()
+			s.subs[fields[1]] = fields[len(fields)-1]
+			s.lock.Unlock()
+		case "PUB":
+			size, _ := strconv.Atoi(fields[3])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			ack := s.publish(fields[1], string(payload[:size]))
+			_, _ = conn.Write([]byte(fmt.Sprintf("MSG %s %s %d\r\n%s\r\n", fields[2], s.sid(fields[2]), len(ack), ack)))
+		}
+	}
+}
+
+// sid returns the sid of the subscription that matches the given reply subject (e.g. _INBOX.abc.*)
+func (s *fakeNATS) sid(reply string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for subject, sid := range s.subs {
+		if subject == reply || (strings.HasSuffix(subject, ".*") && strings.HasPrefix(reply, strings.TrimSuffix(subject, "*"))) {
+			return sid
+		}
+	}
+	return "1"
+}
+
+func (s *fakeNATS) publish(subject, payload string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.failNext > 0 {
+		s.failNext--
+		return `{"error":{"code":503,"description":"no stream"}}`
+	}
+	s.seq++
+	s.received[subject] = append(s.received[subject], payload)
+	return fmt.Sprintf(`{"stream":"ssv","seq":%d}`, s.seq)
+}
+
+func TestNATSSink(t *testing.T) {
+	server := newFakeNATS(t)
+	defer server.listener.Close()
+
+	n, err := newNATS(zap.L(), Options{URL: server.url(), AckTimeout: time.Second}, nil)
+	require.NoError(t, err)
+	defer n.Close()
+
+	require.NoError(t, n.Publish("ssv.exporter.decided", []byte(`{"a":1}`)))
+	require.NoError(t, n.Publish("ssv.exporter.decided", []byte(`{"a":2}`)))
+
+	server.lock.Lock()
+	server.failNext = 1
+	server.lock.Unlock()
+	require.EqualError(t, n.Publish("ssv.exporter.validator", []byte(`{}`)), "publish failed: nats: no stream")
+	require.NoError(t, n.Publish("ssv.exporter.validator", []byte(`{}`)))
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	require.Equal(t, []string{`{"a":1}`, `{"a":2}`}, server.received["ssv.exporter.decided"])
+	require.Len(t, server.received["ssv.exporter.validator"], 1)
+}
+
+func TestNATSSink_AckTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	// a server that never acks
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte("INFO {\"max_payload\":1048576}\r\n"))
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.HasPrefix(line, "PING") {
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	n, err := newNATS(zap.L(), Options{URL: "nats://" + l.Addr().String(), AckTimeout: 100 * time.Millisecond}, nil)
+	require.NoError(t, err)
+	defer n.Close()
+	require.EqualError(t, n.Publish("test", []byte(`{}`)), "publish failed: nats: timeout")
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"sort"
+)
+
+func balancesPrefix() []byte {
+	return []byte("balances")
+}
+
+// BalanceSnapshot is the balance of a validator at a given epoch
+type BalanceSnapshot struct {
+	Epoch   uint64 `json:"epoch"`
+	Balance uint64 `json:"balance"`
+}
+
+// BalancesCollection is the interface for managing the balance history of validators
+type BalancesCollection interface {
+	SaveBalanceSnapshot(pubKey string, epoch uint64, balance uint64) error
+	GetBalanceHistory(pubKey string, from int64, to int64) ([]BalanceSnapshot, error)
+}
+
+// SaveBalanceSnapshot saves the balance of the given validator at the given epoch,
+// a snapshot per epoch is kept so repeated updates within the same epoch override each other
+func (es *exporterStorage) SaveBalanceSnapshot(pubKey string, epoch uint64, balance uint64) error {
+	es.balancesLock.Lock()
+	defer es.balancesLock.Unlock()
+
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, balance)
+	return es.db.Set(storagePrefix(), balanceKey(pubKey, epoch), value)
+}
+
+// GetBalanceHistory returns the balance snapshots of the given validator in the given epochs range, sorted by epoch.
+// when 'to' equals zero, all snapshots since 'from' will be returned
+func (es *exporterStorage) GetBalanceHistory(pubKey string, from int64, to int64) ([]BalanceSnapshot, error) {
+	es.balancesLock.RLock()
+	defer es.balancesLock.RUnlock()
+
+	objs, err := es.db.GetAllByCollection(append(storagePrefix(), validatorBalancesPrefix(pubKey)...))
+	if err != nil {
+		return nil, err
+	}
+	to = normalTo(to)
+	snapshots := make([]BalanceSnapshot, 0, len(objs))
+	for _, obj := range objs {
+		if len(obj.Key) < 8 || len(obj.Value) != 8 {
+			return nil, errors.New("could not parse balance snapshot")
+		}
+		epoch := binary.BigEndian.Uint64(obj.Key[len(obj.Key)-8:])
+		if int64(epoch) < from || int64(epoch) > to {
+			continue
+		}
+		snapshots = append(snapshots, BalanceSnapshot{
+			Epoch:   epoch,
+			Balance: binary.BigEndian.Uint64(obj.Value),
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Epoch < snapshots[j].Epoch
+	})
+	return snapshots, nil
+}
+
+// validatorBalancesPrefix returns the prefix of the given validator snapshots, the key ends with a separator
+// so validators that share a prefix of the public key are not mixed
+func validatorBalancesPrefix(pubKey string) []byte {
+	return bytes.Join([][]byte{
+		balancesPrefix(),
+		[]byte(pubKey),
+		{},
+	}, []byte("/"))
+}
+
+// balanceKey is the key of a single snapshot, the epoch is big endian encoded to keep keys sorted
+func balanceKey(pubKey string, epoch uint64) []byte {
+	e := make([]byte, 8)
+	binary.BigEndian.PutUint64(e, epoch)
+	return append(validatorBalancesPrefix(pubKey), e...)
+}
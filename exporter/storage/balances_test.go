@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStorage_BalanceHistory(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	pkA := "82e9b36feb8147d3f82c1a03ba246d4a63ac1ce0b1dabbb6991940a06401ab46fb4afbf971a3c145fdad2d4bddd30e12"
+	pkB := pkA + "ff"
+	for epoch := uint64(300); epoch > 0; epoch -= 100 {
+		require.NoError(t, storage.SaveBalanceSnapshot(pkA, epoch, 32000000000+epoch))
+	}
+	// override within the same epoch
+	require.NoError(t, storage.SaveBalanceSnapshot(pkA, 300, 32000000301))
+	require.NoError(t, storage.SaveBalanceSnapshot(pkB, 100, 31000000000))
+
+	history, err := storage.GetBalanceHistory(pkA, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, []BalanceSnapshot{
+		{Epoch: 100, Balance: 32000000100},
+		{Epoch: 200, Balance: 32000000200},
+		{Epoch: 300, Balance: 32000000301},
+	}, history)
+
+	history, err = storage.GetBalanceHistory(pkA, 150, 250)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, uint64(200), history[0].Epoch)
+
+	history, err = storage.GetBalanceHistory(pkB, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	history, err = storage.GetBalanceHistory("unknown", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 0)
+}
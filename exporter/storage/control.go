@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"encoding/json"
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+)
+
+var (
+	controlMessageKey = []byte("control_message")
+)
+
+// ControlCollection is the interface for persisting the last applied control message
+type ControlCollection interface {
+	SaveControlMessage(msg *network.ControlMessage) error
+	GetControlMessage() (*network.ControlMessage, bool, error)
+}
+
+// SaveControlMessage saves the given control message, replacing the previous one
+func (es *exporterStorage) SaveControlMessage(msg *network.ControlMessage) error {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal control message")
+	}
+	return es.db.Set(storagePrefix(), controlMessageKey, raw)
+}
+
+// GetControlMessage returns the last applied control message
+func (es *exporterStorage) GetControlMessage() (*network.ControlMessage, bool, error) {
+	obj, found, err := es.db.Get(storagePrefix(), controlMessageKey)
+	if !found {
+		return nil, found, nil
+	}
+	if err != nil {
+		return nil, found, err
+	}
+	msg := new(network.ControlMessage)
+	if err := json.Unmarshal(obj.Value, msg); err != nil {
+		return nil, found, errors.Wrap(err, "could not unmarshal control message")
+	}
+	return msg, found, nil
+}
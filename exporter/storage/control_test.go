@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExporterStorage_SaveAndGetControlMessage(t *testing.T) {
+	s, done := newStorageForTest()
+	require.NotNil(t, s)
+	defer done()
+
+	_, found, err := s.GetControlMessage()
+	require.NoError(t, err)
+	require.False(t, found)
+
+	msg := &network.ControlMessage{
+		Nonce:         2,
+		Timestamp:     1000,
+		SyncWhitelist: []string{"aa"},
+		SyncBlacklist: []string{"bb"},
+		Signature:     []byte{1, 2},
+	}
+	require.NoError(t, s.SaveControlMessage(msg))
+	saved, found, err := s.GetControlMessage()
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, msg, saved)
+}
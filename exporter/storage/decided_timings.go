@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"math"
+	"sort"
+)
+
+func decidedTimingsPrefix() []byte {
+	return []byte("decided_timings")
+}
+
+// DecidedTiming holds the local receive time of a decided message,
+// and the latency since the start of the originating duty slot (when available)
+type DecidedTiming struct {
+	SeqNumber uint64 `json:"seq"`
+	// ReceivedAt is the local receive time in unix milliseconds
+	ReceivedAt int64 `json:"receivedAt"`
+	// Slot is the duty slot, nil if unknown (e.g. the decided value is not an attestation)
+	Slot *uint64 `json:"slot,omitempty"`
+	// Latency is the time (in milliseconds) between the start of the slot and the receive time
+	Latency *int64 `json:"latency,omitempty"`
+}
+
+// LatencyStats holds the percentiles (in milliseconds) of decided messages latency
+type LatencyStats struct {
+	Count int   `json:"count"`
+	P50   int64 `json:"p50"`
+	P90   int64 `json:"p90"`
+	P99   int64 `json:"p99"`
+}
+
+// DecidedTimingsCollection is the interface for managing the timings of decided messages
+type DecidedTimingsCollection interface {
+	SaveDecidedTiming(pubKey string, timing *DecidedTiming) error
+	GetDecidedTimings(pubKey string, from int64, to int64) ([]DecidedTiming, error)
+}
+
+// SaveDecidedTiming saves the timing of a decided message of the given validator
+func (es *exporterStorage) SaveDecidedTiming(pubKey string, timing *DecidedTiming) error {
+	es.decidedTimingsLock.Lock()
+	defer es.decidedTimingsLock.Unlock()
+
+	raw, err := json.Marshal(timing)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal decided timing")
+	}
+	return es.db.Set(storagePrefix(), decidedTimingKey(pubKey, timing.SeqNumber), raw)
+}
+
+// GetDecidedTimings returns the timings of the given validator in the given sequence range, sorted by sequence.
+// when 'to' equals zero, all timings since 'from' will be returned
+func (es *exporterStorage) GetDecidedTimings(pubKey string, from int64, to int64) ([]DecidedTiming, error) {
+	es.decidedTimingsLock.RLock()
+	defer es.decidedTimingsLock.RUnlock()
+
+	objs, err := es.db.GetAllByCollection(append(storagePrefix(), validatorDecidedTimingsPrefix(pubKey)...))
+	if err != nil {
+		return nil, err
+	}
+	to = normalTo(to)
+	timings := make([]DecidedTiming, 0, len(objs))
+	for _, obj := range objs {
+		var timing DecidedTiming
+		if err := json.Unmarshal(obj.Value, &timing); err != nil {
+			return nil, errors.Wrap(err, "could not parse decided timing")
+		}
+		if int64(timing.SeqNumber) < from || int64(timing.SeqNumber) > to {
+			continue
+		}
+		timings = append(timings, timing)
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		return timings[i].SeqNumber < timings[j].SeqNumber
+	})
+	return timings, nil
+}
+
+// NewLatencyStats calculates the latency percentiles of the given timings, timings w/o latency are ignored
+func NewLatencyStats(timings []DecidedTiming) LatencyStats {
+	var latencies []int64
+	for _, timing := range timings {
+		if timing.Latency != nil {
+			latencies = append(latencies, *timing.Latency)
+		}
+	}
+	stats := LatencyStats{Count: len(latencies)}
+	if len(latencies) == 0 {
+		return stats
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	stats.P50 = percentile(latencies, 50)
+	stats.P90 = percentile(latencies, 90)
+	stats.P99 = percentile(latencies, 99)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of the given sorted values
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// validatorDecidedTimingsPrefix returns the prefix of the given validator timings,
+// the key ends with a separator so validators that share a prefix of the public key are not mixed
+func validatorDecidedTimingsPrefix(pubKey string) []byte {
+	return bytes.Join([][]byte{
+		decidedTimingsPrefix(),
+		[]byte(pubKey),
+		{},
+	}, []byte("/"))
+}
+
+// decidedTimingKey is the key of a single timing, the sequence is big endian encoded to keep keys sorted
+func decidedTimingKey(pubKey string, seq uint64) []byte {
+	s := make([]byte, 8)
+	binary.BigEndian.PutUint64(s, seq)
+	return append(validatorDecidedTimingsPrefix(pubKey), s...)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStorage_DecidedTimings(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	pkA := "82e9b36feb8147d3f82c1a03ba246d4a63ac1ce0b1dabbb6991940a06401ab46fb4afbf971a3c145fdad2d4bddd30e12"
+	pkB := pkA + "ff"
+	for seq := uint64(3); seq > 0; seq-- {
+		slot := seq * 10
+		latency := int64(seq * 1000)
+		require.NoError(t, storage.SaveDecidedTiming(pkA, &DecidedTiming{
+			SeqNumber:  seq,
+			ReceivedAt: 1000 + int64(seq),
+			Slot:       &slot,
+			Latency:    &latency,
+		}))
+	}
+	require.NoError(t, storage.SaveDecidedTiming(pkB, &DecidedTiming{SeqNumber: 1, ReceivedAt: 1001}))
+
+	timings, err := storage.GetDecidedTimings(pkA, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, timings, 3)
+	for i, timing := range timings {
+		require.Equal(t, uint64(i+1), timing.SeqNumber)
+		require.Equal(t, uint64(i+1)*10, *timing.Slot)
+	}
+
+	timings, err = storage.GetDecidedTimings(pkA, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, timings, 1)
+	require.Equal(t, int64(2000), *timings[0].Latency)
+
+	timings, err = storage.GetDecidedTimings(pkB, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, timings, 1)
+	require.Nil(t, timings[0].Slot)
+	require.Nil(t, timings[0].Latency)
+}
+
+func TestNewLatencyStats(t *testing.T) {
+	var timings []DecidedTiming
+	for i := int64(100); i > 0; i-- {
+		latency := i * 10
+		timings = append(timings, DecidedTiming{SeqNumber: uint64(i), Latency: &latency})
+	}
+	// timings w/o latency are ignored
+	timings = append(timings, DecidedTiming{SeqNumber: 101})
+
+	require.Equal(t, LatencyStats{Count: 100, P50: 500, P90: 900, P99: 990}, NewLatencyStats(timings))
+	require.Equal(t, LatencyStats{}, NewLatencyStats(nil))
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	eventsPrefix = []byte("events")
+)
+
+// EventInformation represents a raw contract event log
+type EventInformation struct {
+	Index       int64           `json:"index"`
+	Type        string          `json:"type"`
+	BlockNumber uint64          `json:"blockNumber"`
+	TxHash      string          `json:"txHash"`
+	LogIndex    uint            `json:"logIndex"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// EventsCollection is the interface for managing the archive of contract events
+type EventsCollection interface {
+	SaveEventInformation(eventInformation *EventInformation) (bool, error)
+	ListEvents(from int64, to int64) ([]EventInformation, error)
+}
+
+// ListEvents returns all the archived events
+// when 'to' equals zero, all events will be returned
+func (es *exporterStorage) ListEvents(from int64, to int64) ([]EventInformation, error) {
+	es.eventsLock.RLock()
+	defer es.eventsLock.RUnlock()
+
+	objs, err := es.db.GetAllByCollection(append(storagePrefix(), eventsPrefix...))
+	if err != nil {
+		return nil, err
+	}
+	to = normalTo(to)
+	var events []EventInformation
+	for _, obj := range objs {
+		var ei EventInformation
+		if err := json.Unmarshal(obj.Value, &ei); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal event information")
+		}
+		if ei.Index >= from && ei.Index <= to {
+			events = append(events, ei)
+		}
+	}
+	return events, nil
+}
+
+// SaveEventInformation saves the given event, returns false if the event already exist (e.g. on re-sync)
+func (es *exporterStorage) SaveEventInformation(eventInformation *EventInformation) (bool, error) {
+	es.eventsLock.Lock()
+	defer es.eventsLock.Unlock()
+
+	key := eventKey(eventInformation.TxHash, eventInformation.LogIndex)
+	_, found, err := es.db.Get(storagePrefix(), key)
+	if found {
+		if err != nil {
+			return false, errors.Wrap(err, "could not read event from DB")
+		}
+		es.logger.Debug("event already exist", zap.String("txHash", eventInformation.TxHash),
+			zap.Uint("logIndex", eventInformation.LogIndex))
+		return false, nil
+	}
+	eventInformation.Index, err = es.nextIndex(eventsPrefix)
+	if err != nil {
+		return false, errors.Wrap(err, "could not calculate next event index")
+	}
+	raw, err := json.Marshal(eventInformation)
+	if err != nil {
+		return false, errors.Wrap(err, "could not marshal event information")
+	}
+	if err := es.db.Set(storagePrefix(), key, raw); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func eventKey(txHash string, logIndex uint) []byte {
+	return bytes.Join([][]byte{
+		eventsPrefix,
+		[]byte(fmt.Sprintf("%s_%d", txHash, logIndex)),
+	}, []byte("/"))
+}
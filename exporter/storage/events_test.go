@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStorage_SaveAndListEvents(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	events := []EventInformation{
+		{Type: "OperatorAdded", BlockNumber: 10, TxHash: "0x01", LogIndex: 0, Data: json.RawMessage(`{"name":"a"}`)},
+		{Type: "ValidatorAdded", BlockNumber: 10, TxHash: "0x01", LogIndex: 1, Data: json.RawMessage(`{"publicKey":"b"}`)},
+		{Type: "ValidatorAdded", BlockNumber: 11, TxHash: "0x02", LogIndex: 0, Data: json.RawMessage(`{"publicKey":"c"}`)},
+	}
+
+	t.Run("save events", func(t *testing.T) {
+		for i := range events {
+			saved, err := storage.SaveEventInformation(&events[i])
+			require.NoError(t, err)
+			require.True(t, saved)
+			require.Equal(t, int64(i), events[i].Index)
+		}
+	})
+
+	t.Run("save existing event", func(t *testing.T) {
+		ei := events[1]
+		saved, err := storage.SaveEventInformation(&ei)
+		require.NoError(t, err)
+		require.False(t, saved)
+	})
+
+	t.Run("list events", func(t *testing.T) {
+		all, err := storage.ListEvents(0, 0)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+
+		some, err := storage.ListEvents(1, 1)
+		require.NoError(t, err)
+		require.Len(t, some, 1)
+		require.Equal(t, "0x01", some[0].TxHash)
+		require.Equal(t, uint(1), some[0].LogIndex)
+		require.JSONEq(t, `{"publicKey":"b"}`, string(some[0].Data))
+	})
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/bloxapp/ssv/network"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -10,6 +11,13 @@ import (
 
 var (
 	operatorsPrefix = []byte("operators")
+	// ownerOperatorsPrefix is the prefix of the secondary index of operators by owner address
+	ownerOperatorsPrefix = []byte("owner_operators")
+	// operatorsMetadataPrefix is the prefix of operators metadata that is fetched from an external registry,
+	// must not start with operatorsPrefix as operators are listed by prefix
+	operatorsMetadataPrefix = []byte("operator_metadata")
+	// operatorsLivenessPrefix is the prefix of operators liveness that is based on heartbeats
+	operatorsLivenessPrefix = []byte("operator_liveness")
 )
 
 // OperatorInformation the public data of an operator
@@ -18,6 +26,31 @@ type OperatorInformation struct {
 	Name         string         `json:"name"`
 	OwnerAddress common.Address `json:"ownerAddress"`
 	Index        int64          `json:"index"`
+	// Metadata is the display information of the operator, fetched from an external registry
+	Metadata *OperatorMetadata `json:"metadata,omitempty"`
+	// Liveness is based on the heartbeats of the operator, nil if no heartbeat was received
+	Liveness *OperatorLiveness `json:"liveness,omitempty"`
+}
+
+// OperatorMetadata is the display information of an operator
+type OperatorMetadata struct {
+	PublicKey   string `json:"publicKey"`
+	Name        string `json:"name,omitempty"`
+	LogoURL     string `json:"logoUrl,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// OperatorLiveness is the liveness of an operator, based on its signed heartbeats
+type OperatorLiveness struct {
+	PublicKey string `json:"publicKey"`
+	// LastSeen is the unix time (milliseconds) of the last heartbeat
+	LastSeen int64 `json:"lastSeen"`
+	// Version is the build version of the operator, as announced in the last heartbeat
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	ForkID  string `json:"forkId,omitempty"`
+	// Resources is the resource usage of the operator, as reported in the last heartbeat
+	Resources *network.ResourceUsage `json:"resources,omitempty"`
 }
 
 // OperatorsCollection is the interface for managing operators information
@@ -25,6 +58,9 @@ type OperatorsCollection interface {
 	GetOperatorInformation(operatorPubKey string) (*OperatorInformation, bool, error)
 	SaveOperatorInformation(operatorInformation *OperatorInformation) error
 	ListOperators(from int64, to int64) ([]OperatorInformation, error)
+	ListOperatorsByOwner(ownerAddress common.Address) ([]OperatorInformation, error)
+	SaveOperatorsMetadata(metadata []*OperatorMetadata) error
+	SaveOperatorLiveness(liveness *OperatorLiveness) error
 }
 
 // ListOperators returns information of all the known operators
@@ -46,7 +82,40 @@ func (es *exporterStorage) ListOperators(from int64, to int64) ([]OperatorInform
 			operators = append(operators, oi)
 		}
 	}
-	return operators, err
+	if err != nil {
+		return operators, err
+	}
+	for i := range operators {
+		if err := es.addOperatorMetadata(&operators[i]); err != nil {
+			return nil, err
+		}
+		if err := es.addOperatorLiveness(&operators[i]); err != nil {
+			return nil, err
+		}
+	}
+	return operators, nil
+}
+
+// ListOperatorsByOwner returns information of the operators of the given owner address
+func (es *exporterStorage) ListOperatorsByOwner(ownerAddress common.Address) ([]OperatorInformation, error) {
+	es.operatorsLock.RLock()
+	defer es.operatorsLock.RUnlock()
+
+	objs, err := es.db.GetAllByCollection(append(storagePrefix(), ownerOperatorsKeyPrefix(ownerAddress)...))
+	if err != nil {
+		return nil, err
+	}
+	var operators []OperatorInformation
+	for _, obj := range objs {
+		oi, found, err := es.getOperatorInformation(string(obj.Value))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read operator information")
+		}
+		if found {
+			operators = append(operators, *oi)
+		}
+	}
+	return operators, nil
 }
 
 // GetOperatorInformation returns information of the given operator by public key
@@ -67,8 +136,96 @@ func (es *exporterStorage) getOperatorInformation(operatorPubKey string) (*Opera
 		return nil, found, err
 	}
 	var operatorInformation OperatorInformation
-	err = json.Unmarshal(obj.Value, &operatorInformation)
-	return &operatorInformation, found, err
+	if err := json.Unmarshal(obj.Value, &operatorInformation); err != nil {
+		return &operatorInformation, found, err
+	}
+	if err := es.addOperatorMetadata(&operatorInformation); err != nil {
+		return &operatorInformation, found, err
+	}
+	return &operatorInformation, found, es.addOperatorLiveness(&operatorInformation)
+}
+
+// SaveOperatorsMetadata saves the given metadata of operators, the on-chain information is kept as is
+func (es *exporterStorage) SaveOperatorsMetadata(metadata []*OperatorMetadata) error {
+	es.operatorsLock.Lock()
+	defer es.operatorsLock.Unlock()
+
+	for _, m := range metadata {
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal operator metadata")
+		}
+		if err := es.db.Set(storagePrefix(), operatorMetadataKey(m.PublicKey), raw); err != nil {
+			return errors.Wrap(err, "could not save operator metadata")
+		}
+	}
+	return nil
+}
+
+// addOperatorMetadata sets the metadata of the given operator if exist
+func (es *exporterStorage) addOperatorMetadata(oi *OperatorInformation) error {
+	obj, found, err := es.db.Get(storagePrefix(), operatorMetadataKey(oi.PublicKey))
+	if err != nil {
+		return errors.Wrap(err, "could not read operator metadata")
+	}
+	if !found {
+		return nil
+	}
+	var metadata OperatorMetadata
+	if err := json.Unmarshal(obj.Value, &metadata); err != nil {
+		return errors.Wrap(err, "could not unmarshal operator metadata")
+	}
+	oi.Metadata = &metadata
+	return nil
+}
+
+// SaveOperatorLiveness saves the liveness of an operator, older heartbeats are ignored
+func (es *exporterStorage) SaveOperatorLiveness(liveness *OperatorLiveness) error {
+	es.operatorsLock.Lock()
+	defer es.operatorsLock.Unlock()
+
+	existing, found, err := es.getOperatorLiveness(liveness.PublicKey)
+	if err != nil {
+		return err
+	}
+	if found && existing.LastSeen >= liveness.LastSeen {
+		return nil
+	}
+	raw, err := json.Marshal(liveness)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal operator liveness")
+	}
+	if err := es.db.Set(storagePrefix(), operatorLivenessKey(liveness.PublicKey), raw); err != nil {
+		return errors.Wrap(err, "could not save operator liveness")
+	}
+	return nil
+}
+
+// addOperatorLiveness sets the liveness of the given operator if exist
+func (es *exporterStorage) addOperatorLiveness(oi *OperatorInformation) error {
+	liveness, found, err := es.getOperatorLiveness(oi.PublicKey)
+	if err != nil {
+		return err
+	}
+	if found {
+		oi.Liveness = liveness
+	}
+	return nil
+}
+
+func (es *exporterStorage) getOperatorLiveness(pubKey string) (*OperatorLiveness, bool, error) {
+	obj, found, err := es.db.Get(storagePrefix(), operatorLivenessKey(pubKey))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not read operator liveness")
+	}
+	if !found {
+		return nil, false, nil
+	}
+	var liveness OperatorLiveness
+	if err := json.Unmarshal(obj.Value, &liveness); err != nil {
+		return nil, false, errors.Wrap(err, "could not unmarshal operator liveness")
+	}
+	return &liveness, true, nil
 }
 
 // SaveOperatorInformation saves operator information by its public key
@@ -85,7 +242,8 @@ func (es *exporterStorage) SaveOperatorInformation(operatorInformation *Operator
 			zap.String("pubKey", operatorInformation.PublicKey))
 		operatorInformation.Index = info.Index
 		// TODO: update operator information (i.e. change name)
-		return nil
+		// makes sure that operators which were saved before the owner index was introduced are indexed
+		return es.db.Set(storagePrefix(), ownerOperatorKey(info.OwnerAddress, info.PublicKey), []byte(info.PublicKey))
 	}
 
 	operatorInformation.Index, err = es.nextIndex(operatorsPrefix)
@@ -96,7 +254,11 @@ func (es *exporterStorage) SaveOperatorInformation(operatorInformation *Operator
 	if err != nil {
 		return errors.Wrap(err, "could not marshal operator information")
 	}
-	return es.db.Set(storagePrefix(), operatorKey(operatorInformation.PublicKey), raw)
+	if err := es.db.Set(storagePrefix(), operatorKey(operatorInformation.PublicKey), raw); err != nil {
+		return errors.Wrap(err, "could not save operator information")
+	}
+	return es.db.Set(storagePrefix(), ownerOperatorKey(operatorInformation.OwnerAddress, operatorInformation.PublicKey),
+		[]byte(operatorInformation.PublicKey))
 }
 
 func operatorKey(pubKey string) []byte {
@@ -105,3 +267,29 @@ func operatorKey(pubKey string) []byte {
 		[]byte(pubKey),
 	}, []byte("/"))
 }
+
+func operatorMetadataKey(pubKey string) []byte {
+	return bytes.Join([][]byte{
+		operatorsMetadataPrefix[:],
+		[]byte(pubKey),
+	}, []byte("/"))
+}
+
+func operatorLivenessKey(pubKey string) []byte {
+	return bytes.Join([][]byte{
+		operatorsLivenessPrefix[:],
+		[]byte(pubKey),
+	}, []byte("/"))
+}
+
+func ownerOperatorsKeyPrefix(ownerAddress common.Address) []byte {
+	return bytes.Join([][]byte{
+		ownerOperatorsPrefix[:],
+		ownerAddress.Bytes(),
+		{},
+	}, []byte("/"))
+}
+
+func ownerOperatorKey(ownerAddress common.Address, pubKey string) []byte {
+	return append(ownerOperatorsKeyPrefix(ownerAddress), []byte(pubKey)...)
+}
@@ -114,3 +114,126 @@ func TestStorage_ListOperators(t *testing.T) {
 		require.True(t, strings.Contains(operator.Name, "operator-"))
 	}
 }
+
+func TestStorage_ListOperatorsByOwner(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	owner := common.HexToAddress("0x3dB7c0D5b5D8ad5BeB6E9a1D3b2c5b4D1B8C6f2e")
+	other := common.HexToAddress("0x1aB3c0D5b5D8ad5BeB6E9a1D3b2c5b4D1B8C6f2e")
+	ois := []OperatorInformation{
+		{PublicKey: "01010101", Name: "my_operator1", OwnerAddress: owner},
+		{PublicKey: "02020202", Name: "other_operator", OwnerAddress: other},
+		{PublicKey: "03030303", Name: "my_operator2", OwnerAddress: owner},
+	}
+	for _, oi := range ois {
+		require.NoError(t, storage.SaveOperatorInformation(&oi))
+	}
+
+	operators, err := storage.ListOperatorsByOwner(owner)
+	require.NoError(t, err)
+	require.Len(t, operators, 2)
+	for _, operator := range operators {
+		require.Equal(t, owner, operator.OwnerAddress)
+		require.True(t, strings.HasPrefix(operator.Name, "my_operator"))
+	}
+
+	operators, err = storage.ListOperatorsByOwner(common.Address{})
+	require.NoError(t, err)
+	require.Len(t, operators, 0)
+
+	// the secondary index doesn't affect operators indexing
+	all, err := storage.ListOperators(0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	i, err := storage.(*exporterStorage).nextIndex(operatorsPrefix)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), i)
+}
+
+func TestStorage_SaveOperatorsMetadata(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	ois := []OperatorInformation{
+		{PublicKey: "01010101", Name: "operator1"},
+		{PublicKey: "02020202", Name: "operator2"},
+	}
+	for _, oi := range ois {
+		require.NoError(t, storage.SaveOperatorInformation(&oi))
+	}
+	require.NoError(t, storage.SaveOperatorsMetadata([]*OperatorMetadata{
+		{PublicKey: "01010101", Name: "Operator One", LogoURL: "https://example.com/1.png", Description: "first"},
+		// an unknown operator
+		{PublicKey: "03030303", Name: "Operator Three"},
+	}))
+
+	oi, found, err := storage.GetOperatorInformation("01010101")
+	require.NoError(t, err)
+	require.True(t, found)
+	// on-chain information is kept
+	require.Equal(t, "operator1", oi.Name)
+	require.NotNil(t, oi.Metadata)
+	require.Equal(t, "Operator One", oi.Metadata.Name)
+	require.Equal(t, "https://example.com/1.png", oi.Metadata.LogoURL)
+
+	all, err := storage.ListOperators(0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	for _, operator := range all {
+		if operator.PublicKey == "01010101" {
+			require.Equal(t, "first", operator.Metadata.Description)
+		} else {
+			require.Nil(t, operator.Metadata)
+		}
+	}
+	// metadata doesn't affect operators indexing
+	i, err := storage.(*exporterStorage).nextIndex(operatorsPrefix)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), i)
+}
+
+func TestStorage_SaveOperatorLiveness(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	ois := []OperatorInformation{
+		{PublicKey: "01010101", Name: "operator1"},
+		{PublicKey: "02020202", Name: "operator2"},
+	}
+	for _, oi := range ois {
+		require.NoError(t, storage.SaveOperatorInformation(&oi))
+	}
+	require.NoError(t, storage.SaveOperatorLiveness(&OperatorLiveness{
+		PublicKey: "01010101", LastSeen: 2000, Version: "v0.1.1",
+	}))
+	// older heartbeats are ignored
+	require.NoError(t, storage.SaveOperatorLiveness(&OperatorLiveness{
+		PublicKey: "01010101", LastSeen: 1000, Version: "v0.1.0",
+	}))
+
+	oi, found, err := storage.GetOperatorInformation("01010101")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotNil(t, oi.Liveness)
+	require.Equal(t, int64(2000), oi.Liveness.LastSeen)
+	require.Equal(t, "v0.1.1", oi.Liveness.Version)
+
+	all, err := storage.ListOperators(0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	for _, operator := range all {
+		if operator.PublicKey == "01010101" {
+			require.NotNil(t, operator.Liveness)
+		} else {
+			require.Nil(t, operator.Liveness)
+		}
+	}
+	// liveness doesn't affect operators indexing
+	i, err := storage.(*exporterStorage).nextIndex(operatorsPrefix)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), i)
+}
@@ -15,27 +15,44 @@ func storagePrefix() []byte {
 // Storage represents the interface of exporter storage
 type Storage interface {
 	eth1.SyncOffsetStorage
+	eth1.QuarantineStorage
 	OperatorsCollection
 	ValidatorsCollection
+	EventsCollection
+	BalancesCollection
+	DecidedTimingsCollection
+	ControlCollection
 
 	Clean() error
 }
 
 type exporterStorage struct {
+	eth1.QuarantineStorage
+
 	db     basedb.IDb
 	logger *zap.Logger
 
 	validatorsLock sync.RWMutex
 	operatorsLock  sync.RWMutex
+	eventsLock     sync.RWMutex
+	balancesLock   sync.RWMutex
+
+	decidedTimingsLock sync.RWMutex
 }
 
 // NewExporterStorage creates a new instance of Storage
 func NewExporterStorage(db basedb.IDb, logger *zap.Logger) Storage {
 	es := exporterStorage{
+		QuarantineStorage: eth1.NewQuarantineStorage(db, append(storagePrefix(), []byte("eth1-quarantine-")...)),
+
 		db:             db,
 		logger:         logger.With(zap.String("component", "exporter/storage")),
 		validatorsLock: sync.RWMutex{},
 		operatorsLock:  sync.RWMutex{},
+		eventsLock:     sync.RWMutex{},
+		balancesLock:   sync.RWMutex{},
+
+		decidedTimingsLock: sync.RWMutex{},
 	}
 	return &es
 }
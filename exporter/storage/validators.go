@@ -2,22 +2,39 @@ package storage
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"time"
 )
 
 func validatorsPrefix() []byte {
 	return []byte("validators")
 }
 
+// operatorValidatorsPrefix is the prefix of the secondary index of validators by operator
+func operatorValidatorsPrefix() []byte {
+	return []byte("operator_validators")
+}
+
 // ValidatorInformation represents a validator
 type ValidatorInformation struct {
 	Index     int64                     `json:"index"`
 	PublicKey string                    `json:"publicKey"`
 	Metadata  *beacon.ValidatorMetadata `json:"metadata"`
 	Operators []OperatorNodeLink        `json:"operators"`
+	// MetadataUpdatedAt is the time (unix milliseconds) of the last metadata update, 0 if was never updated
+	MetadataUpdatedAt int64 `json:"metadataUpdatedAt,omitempty"`
+}
+
+// IsMetadataStale returns true if the metadata was not updated within the given ttl
+func (vi *ValidatorInformation) IsMetadataStale(ttl time.Duration, now time.Time) bool {
+	if vi.MetadataUpdatedAt == 0 {
+		return true
+	}
+	return now.Sub(time.Unix(0, vi.MetadataUpdatedAt*int64(time.Millisecond))) > ttl
 }
 
 // ValidatorsCollection is the interface for managing validators information
@@ -27,6 +44,10 @@ type ValidatorsCollection interface {
 	GetValidatorInformation(validatorPubKey string) (*ValidatorInformation, bool, error)
 	SaveValidatorInformation(validatorInformation *ValidatorInformation) error
 	ListValidators(from int64, to int64) ([]ValidatorInformation, error)
+	ListValidatorsByOperator(operatorPubKey string, from int64, to int64) ([]ValidatorInformation, error)
+	ListValidatorsByStatus(status ValidatorStatus, from int64, to int64) ([]ValidatorInformation, error)
+	CountValidators() (*ValidatorsCount, error)
+	ReindexValidatorsStatus() error
 }
 
 // OperatorNodeLink links a validator to an operator
@@ -57,6 +78,30 @@ func (es *exporterStorage) ListValidators(from int64, to int64) ([]ValidatorInfo
 	return validators, err
 }
 
+// ListValidatorsByOperator returns information of the validators whose committee includes the given operator
+// when 'to' equals zero, all validators of the operator will be returned
+func (es *exporterStorage) ListValidatorsByOperator(operatorPubKey string, from int64, to int64) ([]ValidatorInformation, error) {
+	es.validatorsLock.RLock()
+	defer es.validatorsLock.RUnlock()
+
+	objs, err := es.db.GetAllByCollection(append(storagePrefix(), operatorValidatorsKeyPrefix(operatorPubKey)...))
+	if err != nil {
+		return nil, err
+	}
+	to = normalTo(to)
+	var validators []ValidatorInformation
+	for _, obj := range objs {
+		vi, found, err := es.getValidatorInformationNotSafe(string(obj.Value))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read validator information")
+		}
+		if found && vi.Index >= from && vi.Index <= to {
+			validators = append(validators, *vi)
+		}
+	}
+	return validators, nil
+}
+
 // GetValidatorInformation returns information of the given validator by public key
 func (es *exporterStorage) GetValidatorInformation(validatorPubKey string) (*ValidatorInformation, bool, error) {
 	es.validatorsLock.RLock()
@@ -94,13 +139,31 @@ func (es *exporterStorage) SaveValidatorInformation(validatorInformation *Valida
 			zap.String("pubKey", validatorInformation.PublicKey))
 		validatorInformation.Index = info.Index
 		// TODO: update validator information (i.e. change operator)
-		return nil
+		// makes sure that validators which were saved before the operator index was introduced are indexed
+		return es.indexValidatorOperatorsNotSafe(info)
 	}
 	validatorInformation.Index, err = es.nextIndex(validatorsPrefix())
 	if err != nil {
 		return errors.Wrap(err, "could not calculate next validator index")
 	}
-	return es.saveValidatorNotSafe(validatorInformation)
+	if err := es.saveValidatorNotSafe(validatorInformation); err != nil {
+		return err
+	}
+	if err := es.indexValidatorStatusNotSafe(validatorInformation.PublicKey, "", validatorInformation.Status()); err != nil {
+		return err
+	}
+	return es.indexValidatorOperatorsNotSafe(validatorInformation)
+}
+
+// indexValidatorOperatorsNotSafe adds the validator to the index of each of its operators
+func (es *exporterStorage) indexValidatorOperatorsNotSafe(val *ValidatorInformation) error {
+	for _, operator := range val.Operators {
+		key := operatorValidatorKey(operator.PublicKey, val.PublicKey)
+		if err := es.db.Set(storagePrefix(), key, []byte(val.PublicKey)); err != nil {
+			return errors.Wrap(err, "could not index validator operator")
+		}
+	}
+	return nil
 }
 
 func (es *exporterStorage) UpdateValidatorMetadata(pk string, metadata *beacon.ValidatorMetadata) error {
@@ -117,9 +180,14 @@ func (es *exporterStorage) UpdateValidatorMetadata(pk string, metadata *beacon.V
 	es.validatorsLock.Lock()
 	defer es.validatorsLock.Unlock()
 
+	prevStatus := info.Status()
 	info.Metadata = metadata
+	info.MetadataUpdatedAt = time.Now().UnixNano() / int64(time.Millisecond)
 	// save
-	return es.saveValidatorNotSafe(info)
+	if err := es.saveValidatorNotSafe(info); err != nil {
+		return err
+	}
+	return es.indexValidatorStatusNotSafe(info.PublicKey, prevStatus, info.Status())
 }
 
 func (es *exporterStorage) saveValidatorNotSafe(val *ValidatorInformation) error {
@@ -136,3 +204,17 @@ func validatorKey(pubKey string) []byte {
 		[]byte(pubKey),
 	}, []byte("/"))
 }
+
+// operatorValidatorsKeyPrefix returns the index prefix of the given operator,
+// the public key is hex encoded as it might contain the separator
+func operatorValidatorsKeyPrefix(operatorPubKey string) []byte {
+	return bytes.Join([][]byte{
+		operatorValidatorsPrefix(),
+		[]byte(hex.EncodeToString([]byte(operatorPubKey))),
+		{},
+	}, []byte("/"))
+}
+
+func operatorValidatorKey(operatorPubKey, validatorPubKey string) []byte {
+	return append(operatorValidatorsKeyPrefix(operatorPubKey), []byte(validatorPubKey)...)
+}
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/pkg/errors"
+)
+
+// ValidatorStatus is the (simplified) status of a validator, used for indexing and queries
+type ValidatorStatus string
+
+const (
+	// ValidatorStatusUnknown is the status of validators w/o metadata (unknown to the beacon chain)
+	ValidatorStatusUnknown ValidatorStatus = "unknown"
+	// ValidatorStatusPending is the status of validators that are pending activation
+	ValidatorStatusPending ValidatorStatus = "pending"
+	// ValidatorStatusActive is the status of active validators
+	ValidatorStatusActive ValidatorStatus = "active"
+	// ValidatorStatusExited is the status of exited (or exiting) validators
+	ValidatorStatusExited ValidatorStatus = "exited"
+	// ValidatorStatusSlashed is the status of slashed validators
+	ValidatorStatusSlashed ValidatorStatus = "slashed"
+)
+
+// validatorStatuses are all the statuses, ordered
+var validatorStatuses = []ValidatorStatus{
+	ValidatorStatusUnknown,
+	ValidatorStatusPending,
+	ValidatorStatusActive,
+	ValidatorStatusExited,
+	ValidatorStatusSlashed,
+}
+
+// ValidatorsCount holds the number of validators by status
+type ValidatorsCount struct {
+	Total   int64 `json:"total"`
+	Unknown int64 `json:"unknown"`
+	Pending int64 `json:"pending"`
+	Active  int64 `json:"active"`
+	Exited  int64 `json:"exited"`
+	Slashed int64 `json:"slashed"`
+}
+
+// Add counts a validator with the given status
+func (c *ValidatorsCount) Add(status ValidatorStatus, n int64) {
+	c.Total += n
+	switch status {
+	case ValidatorStatusPending:
+		c.Pending += n
+	case ValidatorStatusActive:
+		c.Active += n
+	case ValidatorStatusExited:
+		c.Exited += n
+	case ValidatorStatusSlashed:
+		c.Slashed += n
+	default:
+		c.Unknown += n
+	}
+}
+
+// ParseValidatorStatus returns the status of the given string
+func ParseValidatorStatus(s string) (ValidatorStatus, error) {
+	for _, status := range validatorStatuses {
+		if string(status) == s {
+			return status, nil
+		}
+	}
+	return "", errors.Errorf("unknown validator status %s", s)
+}
+
+// Status returns the status of the validator according to its metadata
+func (vi *ValidatorInformation) Status() ValidatorStatus {
+	return validatorStatus(vi.Metadata)
+}
+
+func validatorStatus(meta *beacon.ValidatorMetadata) ValidatorStatus {
+	switch {
+	case meta == nil:
+		return ValidatorStatusUnknown
+	case meta.Slashed():
+		return ValidatorStatusSlashed
+	case meta.Exiting():
+		return ValidatorStatusExited
+	case meta.Status.IsActive():
+		return ValidatorStatusActive
+	case meta.Pending():
+		return ValidatorStatusPending
+	default:
+		return ValidatorStatusUnknown
+	}
+}
+
+// ListValidatorsByStatus returns information of the validators with the given status,
+// when 'to' equals zero, all validators with the status will be returned
+func (es *exporterStorage) ListValidatorsByStatus(status ValidatorStatus, from int64, to int64) ([]ValidatorInformation, error) {
+	es.validatorsLock.RLock()
+	defer es.validatorsLock.RUnlock()
+
+	objs, err := es.db.GetAllByCollection(append(storagePrefix(), validatorStatusKeyPrefix(status)...))
+	if err != nil {
+		return nil, err
+	}
+	to = normalTo(to)
+	var validators []ValidatorInformation
+	for _, obj := range objs {
+		vi, found, err := es.getValidatorInformationNotSafe(string(obj.Value))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read validator information")
+		}
+		if found && vi.Index >= from && vi.Index <= to {
+			validators = append(validators, *vi)
+		}
+	}
+	return validators, nil
+}
+
+// CountValidators returns the number of validators by status
+func (es *exporterStorage) CountValidators() (*ValidatorsCount, error) {
+	es.validatorsLock.RLock()
+	defer es.validatorsLock.RUnlock()
+
+	count := ValidatorsCount{}
+	for _, status := range validatorStatuses {
+		n, err := es.db.CountByCollection(append(storagePrefix(), validatorStatusKeyPrefix(status)...))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not count validators")
+		}
+		count.Add(status, n)
+	}
+	return &count, nil
+}
+
+// ReindexValidatorsStatus rebuilds the status index, used for validators that were saved before the index was introduced
+func (es *exporterStorage) ReindexValidatorsStatus() error {
+	es.validatorsLock.Lock()
+	defer es.validatorsLock.Unlock()
+
+	if err := es.db.RemoveAllByCollection(append(storagePrefix(), validatorStatusPrefix()...)); err != nil {
+		return errors.Wrap(err, "could not clean status index")
+	}
+	objs, err := es.db.GetAllByCollection(append(storagePrefix(), validatorsPrefix()...))
+	if err != nil {
+		return errors.Wrap(err, "could not read validators")
+	}
+	for _, obj := range objs {
+		var vi ValidatorInformation
+		if err := json.Unmarshal(obj.Value, &vi); err != nil {
+			return errors.Wrap(err, "could not read validator information")
+		}
+		if err := es.indexValidatorStatusNotSafe(vi.PublicKey, "", vi.Status()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexValidatorStatusNotSafe moves the validator from the index of the previous status (if not empty) to the given status
+func (es *exporterStorage) indexValidatorStatusNotSafe(pubKey string, prev, status ValidatorStatus) error {
+	if len(prev) > 0 && prev != status {
+		if err := es.db.Delete(storagePrefix(), validatorStatusKey(prev, pubKey)); err != nil {
+			return errors.Wrap(err, "could not remove validator status index")
+		}
+	}
+	if err := es.db.Set(storagePrefix(), validatorStatusKey(status, pubKey), []byte(pubKey)); err != nil {
+		return errors.Wrap(err, "could not index validator status")
+	}
+	return nil
+}
+
+// validatorStatusPrefix is the prefix of the secondary index of validators by status
+func validatorStatusPrefix() []byte {
+	return []byte("validator_status")
+}
+
+func validatorStatusKeyPrefix(status ValidatorStatus) []byte {
+	return bytes.Join([][]byte{
+		validatorStatusPrefix(),
+		[]byte(status),
+		{},
+	}, []byte("/"))
+}
+
+func validatorStatusKey(status ValidatorStatus, pubKey string) []byte {
+	return append(validatorStatusKeyPrefix(status), []byte(pubKey)...)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/bloxapp/ssv/utils/rsaencryption"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestStorage_SaveAndGetValidatorInformation(t *testing.T) {
@@ -123,6 +124,51 @@ func TestStorage_ListValidators(t *testing.T) {
 	require.Equal(t, 5, len(validators))
 }
 
+func TestStorage_ListValidatorsByOperator(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	// operator public keys might contain the key separator
+	operatorA := "LS0tLS1CRUdJTi/BSQSBLRVktLS0tLQo="
+	operatorB := "LS0tLS1CRUdJTi"
+	for i := 0; i < 4; i++ {
+		operators := []OperatorNodeLink{{ID: 1, PublicKey: operatorA}}
+		if i%2 == 1 {
+			operators = append(operators, OperatorNodeLink{ID: 2, PublicKey: operatorB})
+		}
+		require.NoError(t, storage.SaveValidatorInformation(&ValidatorInformation{
+			PublicKey: hex.EncodeToString([]byte{byte(i)}),
+			Operators: operators,
+		}))
+	}
+
+	validators, err := storage.ListValidatorsByOperator(operatorA, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, validators, 4)
+
+	validators, err = storage.ListValidatorsByOperator(operatorB, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+
+	// pagination by index
+	validators, err = storage.ListValidatorsByOperator(operatorA, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+	for _, vi := range validators {
+		require.True(t, vi.Index >= 1 && vi.Index <= 2)
+	}
+
+	validators, err = storage.ListValidatorsByOperator("unknown", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, validators, 0)
+
+	// the secondary index doesn't affect validators indexing
+	all, err := storage.ListValidators(0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+}
+
 func TestStorage_UpdateValidator(t *testing.T) {
 	storage, done := newStorageForTest()
 	require.NotNil(t, storage)
@@ -156,4 +202,59 @@ func TestStorage_UpdateValidator(t *testing.T) {
 	require.EqualValues(t, 7, gotVal.Metadata.Status)
 	require.EqualValues(t, 1000001, gotVal.Metadata.Balance)
 	require.EqualValues(t, 1, gotVal.Metadata.Index)
+	require.Greater(t, gotVal.MetadataUpdatedAt, int64(0))
+	require.False(t, gotVal.IsMetadataStale(time.Minute, time.Now()))
+	require.True(t, gotVal.IsMetadataStale(time.Minute, time.Now().Add(2*time.Minute)))
+	require.True(t, (&ValidatorInformation{}).IsMetadataStale(time.Minute, time.Now()))
+}
+
+func TestStorage_ValidatorsStatus(t *testing.T) {
+	storage, done := newStorageForTest()
+	require.NotNil(t, storage)
+	defer done()
+
+	states := []v1.ValidatorState{
+		v1.ValidatorStateActiveOngoing,
+		v1.ValidatorStateActiveOngoing,
+		v1.ValidatorStatePendingQueued,
+		v1.ValidatorStateExitedUnslashed,
+	}
+	for i, state := range states {
+		require.NoError(t, storage.SaveValidatorInformation(&ValidatorInformation{
+			PublicKey: hex.EncodeToString([]byte{byte(i)}),
+			Metadata:  &beacon.ValidatorMetadata{Status: state, Index: spec.ValidatorIndex(i)},
+		}))
+	}
+	// validator w/o metadata
+	require.NoError(t, storage.SaveValidatorInformation(&ValidatorInformation{PublicKey: "05050505"}))
+
+	count, err := storage.CountValidators()
+	require.NoError(t, err)
+	require.Equal(t, ValidatorsCount{Total: 5, Unknown: 1, Pending: 1, Active: 2, Exited: 1}, *count)
+
+	validators, err := storage.ListValidatorsByStatus(ValidatorStatusActive, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+
+	// status changes moves the validator in the index
+	require.NoError(t, storage.UpdateValidatorMetadata(hex.EncodeToString([]byte{0}),
+		&beacon.ValidatorMetadata{Status: v1.ValidatorStateActiveSlashed}))
+	validators, err = storage.ListValidatorsByStatus(ValidatorStatusActive, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, validators, 1)
+	validators, err = storage.ListValidatorsByStatus(ValidatorStatusSlashed, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, validators, 1)
+	require.Equal(t, hex.EncodeToString([]byte{0}), validators[0].PublicKey)
+
+	// the status index doesn't affect validators indexing
+	all, err := storage.ListValidators(0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+
+	// reindex rebuilds the same counts
+	require.NoError(t, storage.ReindexValidatorsStatus())
+	count, err = storage.CountValidators()
+	require.NoError(t, err)
+	require.Equal(t, ValidatorsCount{Total: 5, Unknown: 1, Pending: 1, Active: 1, Exited: 1, Slashed: 1}, *count)
 }
@@ -1,25 +1,59 @@
 package exporter
 
 import (
+	"encoding/hex"
 	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/exporter/storage"
 	"github.com/bloxapp/ssv/validator"
 	validatorstorage "github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
+	"log"
 	"time"
 )
 
+const (
+	// defaultValidatorMetaDataTTL is used if no ttl was configured, slightly longer than the default update interval
+	defaultValidatorMetaDataTTL = 15 * time.Minute
+	// metaDataRefreshBackoff is the minimum time between refreshes of the same validator that are triggered by reads
+	metaDataRefreshBackoff = time.Minute
+)
+
+var (
+	metricsStaleMetadataRefreshes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv:exporter:stale_metadata_refreshes",
+		Help: "Count validators with stale metadata that were refreshed on read",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsStaleMetadataRefreshes); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// continuouslyUpdateValidatorMetaData updates the metadata of all validators in rounds, the interval of a round
+// is computed from the number of validators and the beacon request budget (see beacon.NewMetadataSchedule)
 func (exp *exporter) continuouslyUpdateValidatorMetaData() {
 	for {
-		time.Sleep(exp.validatorMetaDataUpdateInterval)
-
 		shares, err := exp.validatorStorage.GetAllValidatorsShare()
 		if err != nil {
 			exp.logger.Error("could not get validators shares for metadata update", zap.Error(err))
+			time.Sleep(exp.validatorMetaDataUpdateInterval)
 			continue
 		}
-
-		exp.updateValidatorsMetadata(shares, metaDataBatchSize)
+		var pks [][]byte
+		for _, share := range shares {
+			pks = append(pks, share.PublicKey.Serialize())
+		}
+		schedule := beacon.NewMetadataSchedule(len(pks), metaDataBatchSize, exp.validatorMetaDataUpdateInterval,
+			exp.validatorMetaDataRequestsPerMinute)
+		exp.logger.Debug("updating validators metadata", zap.Int("count", len(pks)),
+			zap.Duration("interval", schedule.Interval), zap.Duration("batch interval", schedule.BatchInterval))
+		beacon.UpdateValidatorsMetadataRound(pks, exp.metaDataReadersQueue, exp.storage, exp.beacon,
+			exp.onMetadataUpdated, metaDataBatchSize, schedule)
 	}
 }
 
@@ -42,26 +76,86 @@ func (exp *exporter) updateValidatorsMetadata(shares []*validatorstorage.Share,
 	for _, share := range shares {
 		pks = append(pks, share.PublicKey.Serialize())
 	}
-	onUpdated := func(pk string, meta *beacon.ValidatorMetadata) {
-		logger := exp.logger.With(zap.String("pk", pk))
-		validator.ReportValidatorStatus(pk, meta, exp.logger)
-		pubKey := bls.PublicKey{}
-		if err := pubKey.DeserializeHexStr(pk); err != nil {
-			logger.Error("could not desrialize public key", zap.Error(err))
-			return
+	exp.updateMetadata(pks, batchSize)
+}
+
+// refreshStaleMetadata queues an update of the given validators that have metadata older than the ttl,
+// the stale metadata is still served until the update is done
+func (exp *exporter) refreshStaleMetadata(validators []storage.ValidatorInformation) {
+	if exp.readOnly || exp.beacon == nil || (exp.election != nil && !exp.election.IsLeader()) {
+		return
+	}
+	now := time.Now()
+	var pks [][]byte
+
+	exp.metaDataRefreshesLock.Lock()
+	for pk, requested := range exp.metaDataRefreshes {
+		if now.Sub(requested) > metaDataRefreshBackoff {
+			delete(exp.metaDataRefreshes, pk)
 		}
-		share, found, err := exp.validatorStorage.GetValidatorShare(pubKey.Serialize())
-		if err != nil {
-			logger.Error("could not get validator share", zap.Error(err))
-			return
+	}
+	for i := range validators {
+		vi := &validators[i]
+		if !vi.IsMetadataStale(exp.validatorMetaDataTTL, now) {
+			continue
 		}
-		if !found {
-			logger.Error("could not find validator share")
-			return
+		if _, requested := exp.metaDataRefreshes[vi.PublicKey]; requested {
+			continue
 		}
-		if err := exp.setup(share); err != nil {
-			logger.Error("could not setup validator share")
+		pk, err := hex.DecodeString(vi.PublicKey)
+		if err != nil {
+			exp.logger.Debug("could not decode validator public key", zap.String("pk", vi.PublicKey), zap.Error(err))
+			continue
 		}
+		exp.metaDataRefreshes[vi.PublicKey] = now
+		pks = append(pks, pk)
+	}
+	exp.metaDataRefreshesLock.Unlock()
+
+	if len(pks) == 0 {
+		return
+	}
+	exp.logger.Debug("refreshing stale validators metadata", zap.Int("count", len(pks)))
+	metricsStaleMetadataRefreshes.Add(float64(len(pks)))
+	exp.updateMetadata(pks, metaDataBatchSize)
+}
+
+// updateMetadata queues metadata updates of the given public keys in batches
+func (exp *exporter) updateMetadata(pks [][]byte, batchSize int) {
+	beacon.UpdateValidatorsMetadataBatch(pks, exp.metaDataReadersQueue, exp.storage, exp.beacon, exp.onMetadataUpdated, batchSize)
+}
+
+// onMetadataUpdated is called once the metadata of a validator was updated
+func (exp *exporter) onMetadataUpdated(pk string, meta *beacon.ValidatorMetadata) {
+	logger := exp.logger.With(zap.String("pk", pk))
+	validator.ReportValidatorStatus(pk, meta, exp.logger)
+	exp.saveBalanceSnapshot(pk, meta)
+	pubKey := bls.PublicKey{}
+	if err := pubKey.DeserializeHexStr(pk); err != nil {
+		logger.Error("could not desrialize public key", zap.Error(err))
+		return
+	}
+	share, found, err := exp.validatorStorage.GetValidatorShare(pubKey.Serialize())
+	if err != nil {
+		logger.Error("could not get validator share", zap.Error(err))
+		return
+	}
+	if !found {
+		logger.Error("could not find validator share")
+		return
+	}
+	if err := exp.setup(share); err != nil {
+		logger.Error("could not setup validator share")
+	}
+}
+
+// saveBalanceSnapshot adds the balance of the given validator to its history, at the current epoch
+func (exp *exporter) saveBalanceSnapshot(pk string, meta *beacon.ValidatorMetadata) {
+	if exp.ethNetwork == nil || meta == nil || meta.Index == 0 {
+		return
+	}
+	epoch := uint64(exp.ethNetwork.EstimatedCurrentEpoch())
+	if err := exp.storage.SaveBalanceSnapshot(pk, epoch, uint64(meta.Balance)); err != nil {
+		exp.logger.Warn("could not save balance snapshot", zap.String("pk", pk), zap.Error(err))
 	}
-	beacon.UpdateValidatorsMetadataBatch(pks, exp.metaDataReadersQueue, exp.storage, exp.beacon, onUpdated, batchSize)
 }
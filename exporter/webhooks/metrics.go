@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsWebhooks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:exporter:webhooks",
+		Help: "Count webhook events by type and status (sent, failed, dropped)",
+	}, []string{"type", "status"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsWebhooks); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
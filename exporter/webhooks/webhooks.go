@@ -0,0 +1,205 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/async/event"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderEvent is the header that holds the event type
+	HeaderEvent = "X-SSV-Event"
+	// HeaderSignature is the header that holds the HMAC-SHA256 signature of the body (hex), if a secret was configured
+	HeaderSignature = "X-SSV-Signature"
+)
+
+// Options defines the parameters of webhooks
+type Options struct {
+	Ctx    context.Context
+	Logger *zap.Logger
+	// Feed is the outbound feed of the exporter
+	Feed *event.Feed
+
+	URLs       []string      `yaml:"URLs" env:"WEBHOOK_URLS" env-description:"comma separated urls that events are posted to"`
+	Secret     string        `yaml:"Secret" env:"WEBHOOK_SECRET" env-description:"secret for HMAC-SHA256 signatures of webhook payloads"`
+	Events     []string      `yaml:"Events" env:"WEBHOOK_EVENTS" env-default:"decided,validator,operator" env-description:"comma separated types of events to post"`
+	MaxRetries int           `yaml:"MaxRetries" env:"WEBHOOK_MAX_RETRIES" env-default:"3" env-description:"number of retries of a failed post"`
+	Timeout    time.Duration `yaml:"Timeout" env:"WEBHOOK_TIMEOUT" env-default:"5s" env-description:"timeout of a single post"`
+	QueueSize  int           `yaml:"QueueSize" env:"WEBHOOK_QUEUE_SIZE" env-default:"1024" env-description:"max number of pending events per url, new events are dropped once full"`
+}
+
+// Event is the payload that is posted to webhook urls
+type Event struct {
+	Type      api.MessageType   `json:"type"`
+	Timestamp int64             `json:"timestamp"`
+	Filter    api.MessageFilter `json:"filter"`
+	Data      interface{}       `json:"data"`
+}
+
+// payload is a marshaled event
+type payload struct {
+	eventType api.MessageType
+	body      []byte
+}
+
+// target is a webhook url with its own queue, so a slow target won't delay the others
+type target struct {
+	url   string
+	queue chan *payload
+}
+
+// Webhooks posts outbound (broadcasted) exporter messages to the configured urls
+type Webhooks struct {
+	ctx    context.Context
+	logger *zap.Logger
+	feed   *event.Feed
+	client *http.Client
+	secret []byte
+	events map[api.MessageType]bool
+	policy tasks.RetryPolicy
+
+	targets []*target
+}
+
+// New creates a new instance
+func New(opts Options) *Webhooks {
+	events := make(map[api.MessageType]bool, len(opts.Events))
+	for _, e := range opts.Events {
+		events[api.MessageType(e)] = true
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = 1024
+	}
+	targets := make([]*target, len(opts.URLs))
+	for i, url := range opts.URLs {
+		targets[i] = &target{url: url, queue: make(chan *payload, queueSize)}
+	}
+	return &Webhooks{
+		ctx:    opts.Ctx,
+		logger: opts.Logger.With(zap.String("component", "exporter/webhooks")),
+		feed:   opts.Feed,
+		client: &http.Client{Timeout: opts.Timeout},
+		secret: []byte(opts.Secret),
+		events: events,
+		policy: tasks.RetryPolicy{
+			MaxAttempts: opts.MaxRetries + 1,
+			Backoff:     time.Second,
+			MaxBackoff:  30 * time.Second,
+		},
+		targets: targets,
+	}
+}
+
+// Start subscribes to the feed and starts to post events, non-blocking
+func (w *Webhooks) Start() {
+	cn := make(chan *api.NetworkMessage, 64)
+	sub := w.feed.Subscribe(cn)
+	for _, t := range w.targets {
+		go w.post(t)
+	}
+	w.logger.Info("posting events to webhooks", zap.Int("urls", len(w.targets)))
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case err := <-sub.Err():
+				w.logger.Warn("feed subscription error", zap.Error(err))
+				return
+			case nm := <-cn:
+				w.handle(nm)
+			}
+		}
+	}()
+}
+
+// handle enqueues the given message if it was broadcasted and its type was configured
+func (w *Webhooks) handle(nm *api.NetworkMessage) {
+	// messages with a connection are responses to queries
+	if nm == nil || nm.Conn != nil || !w.events[nm.Msg.Type] {
+		return
+	}
+	body, err := json.Marshal(&Event{
+		Type:      nm.Msg.Type,
+		Timestamp: time.Now().Unix(),
+		Filter:    nm.Msg.Filter,
+		Data:      nm.Msg.Data,
+	})
+	if err != nil {
+		w.logger.Warn("could not marshal event", zap.Error(err))
+		return
+	}
+	p := &payload{eventType: nm.Msg.Type, body: body}
+	for _, t := range w.targets {
+		select {
+		case t.queue <- p:
+		default:
+			metricsWebhooks.WithLabelValues(string(p.eventType), "dropped").Inc()
+			w.logger.Warn("webhook queue is full, dropping event", zap.String("url", t.url))
+		}
+	}
+}
+
+// post sends the queued events of the given target
+func (w *Webhooks) post(t *target) {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case p := <-t.queue:
+			attempts, err := tasks.RetryWithPolicy(w.ctx, func() error {
+				return w.send(t.url, p)
+			}, w.policy)
+			if err != nil {
+				metricsWebhooks.WithLabelValues(string(p.eventType), "failed").Inc()
+				w.logger.Warn("could not post event", zap.String("url", t.url),
+					zap.String("type", string(p.eventType)), zap.Int("attempts", attempts), zap.Error(err))
+				continue
+			}
+			metricsWebhooks.WithLabelValues(string(p.eventType), "sent").Inc()
+		}
+	}
+}
+
+func (w *Webhooks) send(url string, p *payload) error {
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPost, url, bytes.NewReader(p.body))
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(p.eventType))
+	if len(w.secret) > 0 {
+		req.Header.Set(HeaderSignature, Sign(w.secret, p.body))
+	}
+	res, err := w.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not post event")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the given body, used by receivers to verify events
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
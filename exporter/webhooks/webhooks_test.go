@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloxapp/ssv/exporter/api"
+	"github.com/prysmaticlabs/prysm/async/event"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type connectionMock struct{}
+
+func (cm *connectionMock) Close() error {
+	return nil
+}
+
+func (cm *connectionMock) LocalAddr() net.Addr {
+	return nil
+}
+
+func TestWebhooks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lock sync.Mutex
+	var received []*Event
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, Sign([]byte("secret"), body), r.Header.Get(HeaderSignature))
+		var e Event
+		require.NoError(t, json.Unmarshal(body, &e))
+		require.Equal(t, string(e.Type), r.Header.Get(HeaderEvent))
+		received = append(received, &e)
+	}))
+	defer srv.Close()
+
+	feed := new(event.Feed)
+	w := New(Options{
+		Ctx:        ctx,
+		Logger:     zap.L(),
+		Feed:       feed,
+		URLs:       []string{srv.URL},
+		Secret:     "secret",
+		Events:     []string{"validator", "decided"},
+		MaxRetries: 2,
+		Timeout:    time.Second,
+	})
+	w.policy.Backoff = 10 * time.Millisecond
+	w.Start()
+
+	feed.Send(&api.NetworkMessage{Msg: api.Message{
+		Type:   api.TypeValidator,
+		Filter: api.MessageFilter{From: 1, To: 1},
+	}})
+	// not configured
+	feed.Send(&api.NetworkMessage{Msg: api.Message{Type: api.TypeOperator}})
+	// a response to a query
+	feed.Send(&api.NetworkMessage{Msg: api.Message{Type: api.TypeDecided}, Conn: &connectionMock{}})
+	feed.Send(&api.NetworkMessage{Msg: api.Message{
+		Type:   api.TypeDecided,
+		Filter: api.MessageFilter{PublicKey: "aaa", From: 2, To: 2},
+	}})
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(received) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, 3, attempts)
+	require.Equal(t, api.TypeValidator, received[0].Type)
+	require.EqualValues(t, 1, received[0].Filter.From)
+	require.Equal(t, api.TypeDecided, received[1].Type)
+	require.Equal(t, "aaa", received[1].Filter.PublicKey)
+}
@@ -1,18 +1,23 @@
 package controller
 
 import (
+	"context"
 	"github.com/bloxapp/ssv/ibft"
 	contollerforks "github.com/bloxapp/ssv/ibft/controller/forks"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"golang.org/x/sync/semaphore"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/ibft/sync/incoming"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/bloxapp/ssv/storage/collections"
+	"github.com/bloxapp/ssv/utils/format"
 	"github.com/bloxapp/ssv/validator/storage"
 )
 
@@ -28,9 +33,18 @@ type Controller struct {
 	Identifier      []byte
 	fork            contollerforks.Fork
 	signer          beacon.Signer
+	// decidedCache keeps decided ranges that were recently served to syncing peers
+	decidedCache *incoming.DecidedCache
+	// seqWindow is the max distance of a message seq from the highest decided, 0 disables the check
+	seqWindow uint64
 
 	// flags
 	initFinished bool
+	// closed is set (1) once the controller was closed
+	closed uint32
+
+	// lastHighestDecided is the time of the last processed highest decided announcement
+	lastHighestDecided time.Time
 
 	// locks
 	currentInstanceLock sync.Locker
@@ -49,6 +63,7 @@ func New(
 	ValidatorShare *storage.Share,
 	fork contollerforks.Fork,
 	signer beacon.Signer,
+	seqWindow uint64,
 ) ibft.Controller {
 	logger = logger.With(zap.String("role", role.String()))
 	ret := &Controller{
@@ -60,6 +75,8 @@ func New(
 		ValidatorShare: ValidatorShare,
 		Identifier:     identifier,
 		signer:         signer,
+		seqWindow:      seqWindow,
+		decidedCache:   incoming.NewDecidedCache(incoming.DefaultDecidedCacheSize),
 
 		// flags
 		initFinished: false,
@@ -75,24 +92,79 @@ func New(
 }
 
 // Init sets all major processes of iBFT while blocking until completed.
+// In case a highest decided was persisted, the controller is warm started and the history sync continues in the background.
 func (i *Controller) Init() error {
+	start := time.Now()
 	i.logger.Info("iBFT implementation init started")
 	i.processDecidedQueueMessages()
 	i.processSyncQueueMessages()
 	i.listenToSyncMessages()
 	i.listenToNetworkMessages()
 	i.listenToNetworkDecidedMessages()
+	i.startHighestDecidedAnnouncements()
+	if i.warmStart() {
+		i.initFinished = true
+		i.reportInitDuration("warm", start)
+		return nil
+	}
 	i.waitForMinPeerOnInit(1) // minimum of 2 validators (me + 1)
 	if err := i.SyncIBFT(); err != nil {
 		return errors.Wrap(err, "could not sync history, stopping Controller init")
 	}
 	i.initFinished = true
-	i.logger.Info("iBFT implementation init finished")
+	i.reportInitDuration("cold", start)
 	return nil
 }
 
+// warmStart returns true if a highest decided was persisted and peers are not ahead of it,
+// in that case the controller resumes from it and the history sync is done in the background.
+// if peers can't be reached (e.g. no peers yet), the persisted highest decided is trusted.
+// once the background sync is done, a running instance that was decided meanwhile is stopped
+func (i *Controller) warmStart() bool {
+	highest, err := i.highestKnownDecided()
+	if err != nil {
+		i.logger.Warn("could not get persisted highest decided, falling back to cold start", zap.Error(err))
+		return false
+	}
+	if highest == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), warmStartCheckTimeout)
+	defer cancel()
+	remote, err := i.historySync(ctx).FindHighest()
+	if err != nil {
+		i.logger.Debug("could not check highest decided of peers on warm start", zap.Error(err))
+	} else if remote != nil && remote.Message.SeqNumber > highest.Message.SeqNumber {
+		i.logger.Info("peers are ahead of persisted highest decided, falling back to cold start",
+			zap.Uint64("seq number", highest.Message.SeqNumber), zap.Uint64("remote seq number", remote.Message.SeqNumber))
+		return false
+	}
+	i.logger.Info("warm start from persisted highest decided", zap.Uint64("seq number", highest.Message.SeqNumber))
+	go func() {
+		i.waitForMinPeerOnInit(1)
+		if err := i.syncIBFT(false); err != nil {
+			i.logger.Warn("could not sync history after warm start", zap.Error(err))
+			return
+		}
+		i.stopStaleInstance()
+	}()
+	return true
+}
+
+// reportInitDuration reports the time it took for the controller to be ready for duties
+func (i *Controller) reportInitDuration(startType string, start time.Time) {
+	duration := time.Since(start)
+	pk, role := format.IdentifierUnformat(string(i.Identifier))
+	metricsInitDuration.WithLabelValues(role, pk, startType).Set(duration.Seconds())
+	i.logger.Info("iBFT implementation init finished", zap.String("start", startType),
+		zap.Duration("duration", duration))
+}
+
 // StartInstance - starts an ibft instance or returns error
 func (i *Controller) StartInstance(opts ibft.ControllerStartInstanceOptions) (*ibft.InstanceResult, error) {
+	if i.isClosed() {
+		return nil, errors.New("controller is closed")
+	}
 	instanceOpts, err := i.instanceOptionsFromStartOptions(opts)
 	if err != nil {
 		return nil, errors.WithMessage(err, "can't generate instance options")
@@ -102,7 +174,24 @@ func (i *Controller) StartInstance(opts ibft.ControllerStartInstanceOptions) (*i
 		return nil, errors.WithMessage(err, "can't start new iBFT instance")
 	}
 
-	return i.startInstanceWithOptions(instanceOpts, opts.Value)
+	return i.startInstanceWithOptions(instanceOpts, opts.Value, opts.Deadline)
+}
+
+// Close stops the running instance and the processing of messages.
+// the channels of the network can't be unregistered, therefore they are still drained but messages are dropped
+func (i *Controller) Close() {
+	if !atomic.CompareAndSwapUint32(&i.closed, 0, 1) {
+		return
+	}
+	if current := i.currentInstance; current != nil {
+		current.Stop()
+	}
+	i.logger.Debug("iBFT controller closed")
+}
+
+// isClosed returns true if the controller was closed
+func (i *Controller) isClosed() bool {
+	return atomic.LoadUint32(&i.closed) == 1
 }
 
 // GetIBFTCommittee returns a map of the iBFT committee where the key is the member's id.
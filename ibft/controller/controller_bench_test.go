@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/bloxapp/ssv/storage/collections"
+	"github.com/bloxapp/ssv/utils/format"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/bloxapp/ssv/validator/storage"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"go.uber.org/zap"
+)
+
+// benchSigner signs iBFT messages with the share key of the node
+type benchSigner struct {
+	testSigner
+	sk *bls.SecretKey
+}
+
+func (s *benchSigner) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte, error) {
+	sig, err := message.Sign(s.sk)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize(), nil
+}
+
+type alwaysTrueValueCheck struct{}
+
+func (c *alwaysTrueValueCheck) Check(value []byte) error {
+	return nil
+}
+
+// newBenchCommittee creates and inits the controllers of a committee of 4 nodes of a new validator,
+// the nodes are connected by a local network of the committee
+func newBenchCommittee(b *testing.B, logger *zap.Logger) []*Controller {
+	sks, nodes := GenerateNodes(4)
+	net := local.NewLocalNetwork()
+	// the key of the first node is used as the validator key, therefore the identifier is unique per committee
+	pk := validatorPK(sks)
+	identifier := []byte(format.IdentifierFormat(pk.Serialize(), beacon.RoleTypeAttester.String()))
+
+	committee := make([]*Controller, 0, len(sks))
+	for id := uint64(1); id <= uint64(len(sks)); id++ {
+		ibftStorage := collections.NewIbft(newInMemDb(), logger, "attestation")
+		ctrl := New(
+			beacon.RoleTypeAttester,
+			identifier,
+			logger,
+			&ibftStorage,
+			net,
+			msgqueue.New(),
+			proto.DefaultConsensusParams(),
+			&storage.Share{
+				NodeID:    id,
+				PublicKey: pk,
+				Committee: nodes,
+			},
+			nil,
+			&benchSigner{sk: sks[id]},
+			0,
+		).(*Controller)
+		ctrl.setFork(testFork(ctrl))
+		committee = append(committee, ctrl)
+	}
+
+	var wg sync.WaitGroup
+	for _, ctrl := range committee {
+		wg.Add(1)
+		go func(ctrl *Controller) {
+			defer wg.Done()
+			if err := ctrl.Init(); err != nil {
+				b.Error(err)
+			}
+		}(ctrl)
+	}
+	wg.Wait()
+	return committee
+}
+
+// decide runs the given sequence on all the nodes of the given committees and waits for all of them to decide
+func decide(b *testing.B, logger *zap.Logger, committees [][]*Controller, seq uint64) {
+	var wg sync.WaitGroup
+	for _, committee := range committees {
+		for _, ctrl := range committee {
+			wg.Add(1)
+			go func(ctrl *Controller) {
+				defer wg.Done()
+				res, err := ctrl.StartInstance(ibft.ControllerStartInstanceOptions{
+					Logger:         logger,
+					ValueCheck:     &alwaysTrueValueCheck{},
+					SeqNumber:      seq,
+					Value:          []byte(fmt.Sprintf("value %d", seq)),
+					ValidatorShare: ctrl.ValidatorShare,
+				})
+				if err == nil && res.Decided {
+					return
+				}
+				// a slow node might receive the decided message of the sequence before it started the instance
+				if highest, _ := ctrl.highestKnownDecided(); highest != nil && highest.Message.SeqNumber >= seq {
+					return
+				}
+				b.Errorf("node %d didn't decide seq %d: %v", ctrl.ValidatorShare.NodeID, seq, err)
+			}(ctrl)
+		}
+	}
+	wg.Wait()
+}
+
+// benchmarkDecide drives the given number of concurrent committees through full decide cycles (pre-prepare,
+// prepare, commit and decided broadcast) and reports the decides per second
+func benchmarkDecide(b *testing.B, concurrency int) {
+	// logs are reduced to errors, to avoid measuring the logger
+	logger := logex.Build("", zap.ErrorLevel, nil)
+	committees := make([][]*Controller, 0, concurrency)
+	for i := 0; i < concurrency; i++ {
+		committees = append(committees, newBenchCommittee(b, logger))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		decide(b, logger, committees, uint64(i))
+	}
+	elapsed := time.Since(start)
+	b.StopTimer()
+
+	b.ReportMetric(float64(b.N*concurrency)/elapsed.Seconds(), "decides/s")
+}
+
+func BenchmarkController_Decide(b *testing.B) {
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("instances=%d", concurrency), func(b *testing.B) {
+			benchmarkDecide(b, concurrency)
+		})
+	}
+}
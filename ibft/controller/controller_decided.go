@@ -5,6 +5,7 @@ import (
 	"github.com/bloxapp/ssv/ibft/pipeline"
 	"github.com/bloxapp/ssv/ibft/pipeline/auth"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -14,7 +15,7 @@ import (
 // processDecidedQueueMessages is listen for all the ibft decided msg's and process them
 func (i *Controller) processDecidedQueueMessages() {
 	go func() {
-		for {
+		for !i.isClosed() {
 			if decidedMsg := i.msgQueue.PopMessage(msgqueue.DecidedIndexKey(i.GetIdentifier())); decidedMsg != nil {
 				i.ProcessDecidedMessage(decidedMsg.SignedMessage)
 			}
@@ -51,10 +52,17 @@ by calling Decide(λi,− , Qcommit) do
 */
 func (i *Controller) ProcessDecidedMessage(msg *proto.SignedMessage) {
 	if err := i.ValidateDecidedMsg(msg); err != nil {
+		network.ReportRejectedMessage(err)
 		i.logger.Error("received invalid decided message", zap.Error(err), zap.Uint64s("signer ids", msg.SignerIds))
 		return
 	}
 
+	pk := i.ValidatorShare.PublicKey.SerializeToHexStr()
+	if err := pipeline.Middlewares.Pipeline(pk, pipeline.HookDecided).Run(msg); err != nil {
+		i.logger.Warn("decided message was rejected by middleware", zap.Error(err), zap.Uint64s("signer ids", msg.SignerIds))
+		return
+	}
+
 	i.logger.Debug("received valid decided msg", zap.Uint64("seq number", msg.Message.SeqNumber), zap.Uint64s("signer ids", msg.SignerIds))
 
 	// if we already have this in storage, pass
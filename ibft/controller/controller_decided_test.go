@@ -48,6 +48,36 @@ func (s *testStorage) SaveHighestDecidedInstance(_ *proto.SignedMessage) error {
 	return nil
 }
 
+// SaveDecidedAndHighest implementation
+func (s *testStorage) SaveDecidedAndHighest(_ *proto.SignedMessage) error {
+	return nil
+}
+
+// SaveDecidedProvenance implementation
+func (s *testStorage) SaveDecidedProvenance(identifier []byte, seqNumber uint64, p *collections.DecidedProvenance) error {
+	return nil
+}
+
+// GetDecidedProvenance implementation
+func (s *testStorage) GetDecidedProvenance(identifier []byte, seqNumber uint64) (*collections.DecidedProvenance, bool, error) {
+	return nil, false, nil
+}
+
+// GetDecidedHistoryRoot implementation
+func (s *testStorage) GetDecidedHistoryRoot(identifier []byte, count uint64) (*collections.DecidedHistoryRoot, bool, error) {
+	return nil, false, nil
+}
+
+// CleanAll implementation
+func (s *testStorage) CleanAll(identifier []byte) error {
+	return nil
+}
+
+// MigrateLegacyKeys implementation
+func (s *testStorage) MigrateLegacyKeys(identifier []byte) (int, error) {
+	return 0, nil
+}
+
 // GetHighestDecidedInstance implementation
 func (s *testStorage) GetHighestDecidedInstance(identifier []byte) (*proto.SignedMessage, bool, error) {
 	return s.highestDecided, true, nil
@@ -290,6 +320,41 @@ func TestForceDecided(t *testing.T) {
 	require.EqualValues(t, 4, highest.Message.SeqNumber)
 }
 
+func TestStartInstance_Deadline(t *testing.T) {
+	sks, nodes := GenerateNodes(4)
+	network := local.NewLocalNetwork()
+
+	identifier := []byte("lambda_11")
+	s1 := populatedStorage(t, sks, 3)
+	i1 := populatedIbft(1, identifier, network, s1, sks, nodes, newTestSigner())
+
+	time.Sleep(time.Second * 1) // wait for sync to complete
+
+	share := &storage.Share{
+		NodeID:    1,
+		PublicKey: validatorPK(sks),
+		Committee: nodes,
+	}
+	// other nodes are not running, therefore the instance can't decide
+	start := time.Now()
+	res, err := i1.StartInstance(ibft.ControllerStartInstanceOptions{
+		Logger:         logex.GetLogger(),
+		ValueCheck:     &valcheck.AttestationValueCheck{},
+		SeqNumber:      4,
+		Value:          []byte("value"),
+		ValidatorShare: share,
+		Deadline:       start.Add(500 * time.Millisecond),
+	})
+	require.Equal(t, ibft.ErrDeadlineExceeded, errors.Cause(err))
+	require.Nil(t, res)
+	require.Less(t, int64(time.Since(start)), int64(2*time.Second))
+
+	highest, found, err := i1.(*Controller).ibftStorage.GetHighestDecidedInstance(identifier)
+	require.True(t, found)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, highest.Message.SeqNumber)
+}
+
 func TestSyncAfterDecided(t *testing.T) {
 	sks, nodes := GenerateNodes(4)
 	network := local.NewLocalNetwork()
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"go.uber.org/zap"
+	"math/rand"
+	"time"
+)
+
+var (
+	// highestDecidedAnnounceInterval is the interval in which the highest decided is announced on the decided topic
+	highestDecidedAnnounceInterval = 5 * time.Minute
+	// highestDecidedMinInterval is the minimum time between processed announcements of some validator
+	highestDecidedMinInterval = 12 * time.Second
+)
+
+// startHighestDecidedAnnouncements announces the highest decided periodically and listens to announcements of peers,
+// does nothing if the network doesn't support announcements
+func (i *Controller) startHighestDecidedAnnouncements() {
+	net, ok := i.network.(network.HighestDecided)
+	if !ok {
+		return
+	}
+	i.listenToHighestDecided(net)
+	go i.announceHighestDecided(net)
+}
+
+// announceHighestDecided broadcasts the highest known decided on the decided topic in an interval
+func (i *Controller) announceHighestDecided(net network.HighestDecided) {
+	// a random delay spreads the announcements of all validators
+	time.Sleep(time.Duration(rand.Int63n(int64(highestDecidedAnnounceInterval))))
+	ticker := time.NewTicker(highestDecidedAnnounceInterval)
+	defer ticker.Stop()
+	for !i.isClosed() {
+		highest, err := i.highestKnownDecided()
+		if err != nil {
+			i.logger.Warn("could not get highest decided for announcement", zap.Error(err))
+		} else if highest != nil {
+			if err := net.BroadcastHighestDecided(highest); err != nil {
+				i.logger.Warn("could not announce highest decided", zap.Error(err))
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// listenToHighestDecided pushes announcements that are ahead of this node into the decided queue
+func (i *Controller) listenToHighestDecided(net network.HighestDecided) {
+	cn := net.ReceivedHighestDecidedChan()
+	go func() {
+		for msg := range cn {
+			if !i.isClosed() && msg.Message != nil && i.equalIdentifier(msg.Message.Lambda) {
+				i.handleHighestDecided(msg)
+			}
+		}
+	}()
+}
+
+// handleHighestDecided returns true if the given announcement was added to the decided queue.
+// announcements are processed only if they require sync, at most once in highestDecidedMinInterval
+// and only if the message is authenticated by the committee
+func (i *Controller) handleHighestDecided(msg *proto.SignedMessage) bool {
+	shouldSync, err := i.decidedRequiresSync(msg)
+	if err != nil {
+		i.logger.Warn("can't check highest decided announcement", zap.Error(err))
+		return false
+	}
+	if !shouldSync {
+		return false
+	}
+	now := time.Now()
+	if now.Sub(i.lastHighestDecided) < highestDecidedMinInterval {
+		metricsHighestDecidedAnnouncements.WithLabelValues("dropped").Inc()
+		return false
+	}
+	i.lastHighestDecided = now
+	if err := i.ValidateDecidedMsg(msg); err != nil {
+		metricsHighestDecidedAnnouncements.WithLabelValues("invalid").Inc()
+		i.logger.Debug("received invalid highest decided announcement", zap.Error(err),
+			zap.Uint64s("signer ids", msg.SignerIds))
+		return false
+	}
+	metricsHighestDecidedAnnouncements.WithLabelValues("accepted").Inc()
+	i.logger.Debug("highest decided announcement is ahead of this node",
+		zap.Uint64("seq number", msg.Message.SeqNumber))
+	i.msgQueue.AddMessage(&network.Message{
+		SignedMessage: msg,
+		Type:          network.NetworkMsg_DecidedType,
+	})
+	return true
+}
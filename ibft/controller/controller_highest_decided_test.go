@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestHandleHighestDecided(t *testing.T) {
+	sks, nodes := GenerateNodes(4)
+	identifier := []byte("lambda_11")
+	i1 := populatedIbft(1, identifier, local.NewLocalNetwork(), populatedStorage(t, sks, 4), sks, nodes, newTestSigner()).(*Controller)
+	// a separate queue that is not consumed
+	i1.msgQueue = msgqueue.New()
+	queued := func() int {
+		return i1.msgQueue.MsgCount(msgqueue.DecidedIndexKey(identifier))
+	}
+	newMsg := func(seq uint64) *proto.SignedMessage {
+		return aggregateSign(t, sks, &proto.Message{
+			Type:      proto.RoundState_Commit,
+			Round:     1,
+			SeqNumber: seq,
+			Lambda:    identifier,
+			Value:     []byte("value"),
+		})
+	}
+
+	t.Run("ignore announcement that is not ahead", func(t *testing.T) {
+		require.False(t, i1.handleHighestDecided(newMsg(3)))
+		require.False(t, i1.handleHighestDecided(newMsg(4)))
+		require.Equal(t, 0, queued())
+	})
+
+	t.Run("ignore invalid announcement", func(t *testing.T) {
+		require.False(t, i1.handleHighestDecided(aggregateInvalidSign(t, sks, &proto.Message{
+			Type:      proto.RoundState_Commit,
+			Round:     1,
+			SeqNumber: 10,
+			Lambda:    identifier,
+			Value:     []byte("value"),
+		})))
+		require.Equal(t, 0, queued())
+	})
+
+	t.Run("rate limit announcements", func(t *testing.T) {
+		require.False(t, i1.handleHighestDecided(newMsg(10)))
+		i1.lastHighestDecided = time.Now().Add(-highestDecidedMinInterval)
+		require.True(t, i1.handleHighestDecided(newMsg(10)))
+		require.Equal(t, 1, queued())
+		require.False(t, i1.handleHighestDecided(newMsg(11)))
+		require.Equal(t, 1, queued())
+	})
+}
@@ -35,12 +35,23 @@ func (i *Controller) waitForMinPeers(minPeerCount int, stopAtLimit bool) error {
 		waitMinPeersIntervalStart, waitMinPeersIntervalEnd, stopAtLimit)
 }
 
+// messageVersion returns the message version of the fork, the latest version is returned if no fork was set
+func (i *Controller) messageVersion() network.MessageVersion {
+	if i.fork == nil {
+		return network.CurrentMessageVersion
+	}
+	return i.fork.MessageVersion()
+}
+
 func (i *Controller) listenToNetworkMessages() {
 	msgChan := i.network.ReceivedMsgChan()
 	go func() {
 		for msg := range msgChan {
-			if msg.Message != nil && i.equalIdentifier(msg.Message.Lambda) {
+			if !i.isClosed() && msg.Message != nil && i.equalIdentifier(msg.Message.Lambda) &&
+				i.acceptMsgSeq(msg, network.NetworkMsg_IBFTType) {
+				// messages with unknown versions are rejected by the network
 				i.msgQueue.AddMessage(&network.Message{
+					Version:       i.messageVersion(),
 					SignedMessage: msg,
 					Type:          network.NetworkMsg_IBFTType,
 				})
@@ -53,8 +64,10 @@ func (i *Controller) listenToNetworkDecidedMessages() {
 	decidedChan := i.network.ReceivedDecidedChan()
 	go func() {
 		for msg := range decidedChan {
-			if msg.Message != nil && i.equalIdentifier(msg.Message.Lambda) {
+			// decided messages are not limited by the seq window as future decided messages trigger a sync
+			if !i.isClosed() && msg.Message != nil && i.equalIdentifier(msg.Message.Lambda) {
 				i.msgQueue.AddMessage(&network.Message{
+					Version:       i.messageVersion(),
 					SignedMessage: msg,
 					Type:          network.NetworkMsg_DecidedType,
 				})
@@ -67,8 +80,9 @@ func (i *Controller) listenToSyncMessages() {
 	syncChan := i.network.ReceivedSyncMsgChan()
 	go func() {
 		for msg := range syncChan {
-			if msg.Msg != nil && i.equalIdentifier(msg.Msg.Lambda) {
+			if !i.isClosed() && msg.Msg != nil && i.equalIdentifier(msg.Msg.Lambda) {
 				i.msgQueue.AddMessage(&network.Message{
+					Version:     msg.Version,
 					SyncMessage: msg.Msg,
 					Stream:      msg.Stream,
 					Type:        network.NetworkMsg_SyncType,
@@ -17,7 +17,9 @@ import (
 
 // startInstanceWithOptions will start an iBFT instance with the provided options.
 // Does not pre-check instance validity and start validity!
-func (i *Controller) startInstanceWithOptions(instanceOpts *instance.InstanceOptions, value []byte) (*ibft.InstanceResult, error) {
+// the instance is aborted if not decided until the given deadline (unless zero)
+func (i *Controller) startInstanceWithOptions(instanceOpts *instance.InstanceOptions, value []byte, deadline time.Time) (*ibft.InstanceResult, error) {
+	instanceOpts.Deadline = deadline
 	i.currentInstance = instance.NewInstance(instanceOpts)
 	i.currentInstance.Init()
 	stageChan := i.currentInstance.GetStageChan()
@@ -33,12 +35,38 @@ func (i *Controller) startInstanceWithOptions(instanceOpts *instance.InstanceOpt
 	// catch up if we can
 	go i.fastChangeRoundCatchup(i.currentInstance)
 
+	stalled := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go i.watchInstance(i.currentInstance, stalled, done)
+
+	var deadlineC <-chan time.Time
+	if !deadline.IsZero() {
+		deadlineTimer := time.NewTimer(time.Until(deadline))
+		defer deadlineTimer.Stop()
+		deadlineC = deadlineTimer.C
+	}
+
 	// main instance callback loop
 	var retRes *ibft.InstanceResult
 	var err error
 instanceLoop:
 	for {
-		stage := <-stageChan
+		var stage proto.RoundState
+		select {
+		case stage = <-stageChan:
+		case <-stalled:
+			err = errors.New("iBFT instance was aborted by watchdog")
+			i.abortInstance(stageChan)
+			break instanceLoop
+		case <-deadlineC:
+			err = ibft.ErrDeadlineExceeded
+			i.logger.Warn("iBFT instance deadline exceeded, aborting",
+				zap.Uint64("seqNum", instanceOpts.SeqNumber),
+				zap.Uint64("round", i.currentInstance.State().Round.Get()))
+			i.abortInstance(stageChan)
+			break instanceLoop
+		}
 		if i.currentInstance == nil {
 			i.logger.Debug("stage channel was invoked but instance is already empty", zap.Any("stage", stage))
 			break instanceLoop
@@ -76,6 +104,16 @@ instanceLoop:
 	return retRes, err
 }
 
+// abortInstance stops the current instance
+func (i *Controller) abortInstance(stageChan chan proto.RoundState) {
+	// stage changes are drained until the instance closes the channel upon stop
+	go func() {
+		for range stageChan {
+		}
+	}()
+	i.currentInstance.Stop()
+}
+
 // instanceStageChange processes a stage change for the current instance, returns true if requires stopping the instance after stage process.
 func (i *Controller) instanceStageChange(stage proto.RoundState) (bool, error) {
 	switch stage {
@@ -88,12 +126,9 @@ func (i *Controller) instanceStageChange(stage proto.RoundState) (bool, error) {
 		if err != nil {
 			return true, errors.Wrap(err, "could not get aggregated commit msg and save to storage")
 		}
-		if err := i.ibftStorage.SaveDecided(agg); err != nil {
+		if err := i.ibftStorage.SaveDecidedAndHighest(agg); err != nil {
 			return true, errors.Wrap(err, "could not save aggregated commit msg to storage")
 		}
-		if err := i.ibftStorage.SaveHighestDecidedInstance(agg); err != nil {
-			return true, errors.Wrap(err, "could not save highest decided message to storage")
-		}
 		if err := i.network.BroadcastDecided(i.ValidatorShare.PublicKey.Serialize(), agg); err != nil {
 			return true, errors.Wrap(err, "could not broadcast decided message")
 		}
@@ -157,7 +192,7 @@ func (i *Controller) fastChangeRoundCatchup(instance ibft.Instance) {
 		instance.State().SeqNumber.Get(),
 		i.network,
 		instance.ChangeRoundMsgValidationPipeline(),
-	)
+	).WithCommittee(i.ValidatorShare.CommitteeOperators())
 	msgs, err := sync.Start()
 	if err != nil {
 		i.logger.Error("failed fast change round catchup", zap.Error(err))
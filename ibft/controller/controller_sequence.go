@@ -4,7 +4,11 @@ import (
 	"github.com/bloxapp/ssv/ibft"
 	instance "github.com/bloxapp/ssv/ibft/instance"
 	"github.com/bloxapp/ssv/ibft/leader/deterministic"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 	"strconv"
 )
 
@@ -83,3 +87,42 @@ func (i *Controller) instanceOptionsFromStartOptions(opts ibft.ControllerStartIn
 		Signer:          i.signer,
 	}, nil
 }
+
+// outOfSeqWindow returns "stale" or "future" if the given seq is further than seqWindow from the highest decided,
+// an empty string is returned if the seq is within the window, the check is disabled or no decided is known yet
+func (i *Controller) outOfSeqWindow(seq uint64) string {
+	if i.seqWindow == 0 {
+		return ""
+	}
+	highestKnown, err := i.highestKnownDecided()
+	if err != nil {
+		i.logger.Debug("could not get highest decided for seq window check", zap.Error(err))
+		return ""
+	}
+	if highestKnown == nil {
+		return ""
+	}
+	highestSeq := highestKnown.Message.SeqNumber
+	if seq+i.seqWindow < highestSeq {
+		return "stale"
+	}
+	if seq > highestSeq+i.seqWindow {
+		return "future"
+	}
+	return ""
+}
+
+// acceptMsgSeq returns false (and counts the rejection) if the given message is outside of the seq window.
+// it applies to consensus messages only, decided messages must reach the decided flow which syncs when the node is behind
+func (i *Controller) acceptMsgSeq(msg *proto.SignedMessage, msgType network.NetworkMsg) bool {
+	reason := i.outOfSeqWindow(msg.Message.SeqNumber)
+	if len(reason) == 0 {
+		return true
+	}
+	metricsOutOfSeqWindow.WithLabelValues(msgType.String(), reason).Inc()
+	network.ReportRejectedMessage(errs.Mark(errors.Errorf("seq %d is out of window (%s)", msg.Message.SeqNumber, reason),
+		errs.ErrStaleMessage))
+	i.logger.Debug("rejected message outside of seq window", zap.String("reason", reason),
+		zap.String("type", msgType.String()), zap.Uint64("seq number", msg.Message.SeqNumber))
+	return false
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/bloxapp/ssv/ibft"
 	instance "github.com/bloxapp/ssv/ibft/instance"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/bloxapp/ssv/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/collections"
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"testing"
+	"time"
 )
 
 func testIBFTInstance(t *testing.T) *Controller {
@@ -167,3 +170,59 @@ func TestCanStartNewInstance(t *testing.T) {
 		})
 	}
 }
+
+func TestOutOfSeqWindow(t *testing.T) {
+	sks, _ := GenerateNodes(4)
+	i := testIBFTInstance(t)
+	i.logger = zap.L()
+	i.seqWindow = 5
+
+	t.Run("accept any seq if no decided is known", func(t *testing.T) {
+		i.ibftStorage = populatedStorage(t, sks, -1)
+		require.Equal(t, "", i.outOfSeqWindow(0))
+		require.Equal(t, "", i.outOfSeqWindow(100))
+	})
+
+	i.ibftStorage = populatedStorage(t, sks, 10)
+
+	t.Run("accept seq within the window", func(t *testing.T) {
+		require.Equal(t, "", i.outOfSeqWindow(5))
+		require.Equal(t, "", i.outOfSeqWindow(10))
+		require.Equal(t, "", i.outOfSeqWindow(15))
+	})
+
+	t.Run("reject stale and future seq", func(t *testing.T) {
+		require.Equal(t, "stale", i.outOfSeqWindow(4))
+		require.Equal(t, "future", i.outOfSeqWindow(16))
+	})
+
+	t.Run("disabled window", func(t *testing.T) {
+		i.seqWindow = 0
+		require.Equal(t, "", i.outOfSeqWindow(0))
+		require.Equal(t, "", i.outOfSeqWindow(100))
+	})
+}
+
+func TestSeqWindowDecidedMessages(t *testing.T) {
+	sks, _ := GenerateNodes(4)
+	net := local.NewLocalNetwork()
+	queue := msgqueue.New()
+	i := testIBFTInstance(t)
+	i.logger = zap.L()
+	i.network = net
+	i.msgQueue = queue
+	i.seqWindow = 5
+	i.ibftStorage = populatedStorage(t, sks, 10)
+	i.listenToNetworkMessages()
+	i.listenToNetworkDecidedMessages()
+
+	msg := &proto.SignedMessage{Message: &proto.Message{Type: proto.RoundState_Commit, Lambda: i.Identifier, SeqNumber: 100}}
+	require.NoError(t, net.Broadcast(i.Identifier, msg))
+	require.NoError(t, net.BroadcastDecided(i.Identifier, msg))
+
+	// future decided messages are accepted, so they can trigger a sync
+	require.Eventually(t, func() bool {
+		return len(queue.MessagesForIndex(msgqueue.DecidedIndexKey(i.Identifier))) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, queue.MessagesForIndex(msgqueue.IBFTMessageIndexKey(i.Identifier, 100)), 0)
+}
@@ -1,23 +1,33 @@
 package controller
 
 import (
+	"context"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/ibft/sync/history"
 	"github.com/bloxapp/ssv/ibft/sync/incoming"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 	"time"
 )
 
+const (
+	// historySyncTimeout is the max duration of a history sync, the sync is canceled (and retried by the caller) afterwards
+	historySyncTimeout = 5 * time.Minute
+	// warmStartCheckTimeout is the max duration of checking the highest decided of peers on warm start
+	warmStartCheckTimeout = 10 * time.Second
+)
+
 // processSyncQueueMessages is listen for all the ibft sync msg's and process them
 func (i *Controller) processSyncQueueMessages() {
 	go func() {
-		for {
+		for !i.isClosed() {
 			if syncMsg := i.msgQueue.PopMessage(msgqueue.SyncIndexKey(i.Identifier)); syncMsg != nil {
 				i.ProcessSyncMessage(&network.SyncChanObj{
-					Msg:    syncMsg.SyncMessage,
-					Stream: syncMsg.Stream,
+					Msg:     syncMsg.SyncMessage,
+					Stream:  syncMsg.Stream,
+					Version: syncMsg.Version,
 				})
 			}
 			time.Sleep(time.Millisecond * 100)
@@ -34,12 +44,17 @@ func (i *Controller) ProcessSyncMessage(msg *network.SyncChanObj) {
 		lastChangeRoundMsg = i.currentInstance.GetLastChangeRoundMsg()
 		currentInstaceSeqNumber = int64(i.currentInstance.State().SeqNumber.Get())
 	}
-	s := incoming.New(i.logger, i.Identifier, currentInstaceSeqNumber, i.network, i.ibftStorage, lastChangeRoundMsg)
+	s := incoming.New(i.logger, i.Identifier, currentInstaceSeqNumber, i.network, i.ibftStorage, lastChangeRoundMsg, i.decidedCache)
 	go s.Process(msg)
 }
 
 // SyncIBFT will fetch best known decided message (highest sequence) from the network and sync to it.
 func (i *Controller) SyncIBFT() error {
+	return i.syncIBFT(true)
+}
+
+// syncIBFT syncs to the best known decided message, the current instance is stopped only if stopInstance is set
+func (i *Controller) syncIBFT(stopInstance bool) error {
 	if !i.syncingLock.TryAcquire(1) {
 		return errors.New("failed to start iBFT sync, already running")
 	}
@@ -48,15 +63,40 @@ func (i *Controller) SyncIBFT() error {
 	i.logger.Info("syncing iBFT..")
 
 	// stop current instance and return any waiting chan.
-	if i.currentInstance != nil {
+	if stopInstance && i.currentInstance != nil {
 		i.currentInstance.Stop()
 	}
 
 	// sync
-	s := history.New(i.logger, i.ValidatorShare.PublicKey.Serialize(), i.GetIdentifier(), i.network, i.ibftStorage, i.ValidateDecidedMsg)
-	err := s.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), historySyncTimeout)
+	defer cancel()
+	err := i.historySync(ctx).Start()
 	if err != nil {
 		return errors.Wrap(err, "history sync failed")
 	}
 	return nil
 }
+
+// historySync returns a history sync of the controller
+func (i *Controller) historySync(ctx context.Context) *history.Sync {
+	return history.New(i.logger, i.ValidatorShare.PublicKey.Serialize(), i.GetIdentifier(), i.network, i.ibftStorage, i.ValidateDecidedMsg).
+		WithCommittee(i.ValidatorShare.CommitteeOperators()).
+		WithContext(ctx)
+}
+
+// stopStaleInstance stops the running instance if its sequence was decided already, e.g. by a background sync
+func (i *Controller) stopStaleInstance() {
+	current := i.currentInstance
+	if current == nil {
+		return
+	}
+	highest, err := i.highestKnownDecided()
+	if err != nil || highest == nil {
+		return
+	}
+	if seq := current.State().SeqNumber.Get(); seq <= highest.Message.SeqNumber {
+		i.logger.Info("stopping stale instance, its sequence was decided already", zap.Uint64("seq number", seq),
+			zap.Uint64("highest decided", highest.Message.SeqNumber))
+		current.Stop()
+	}
+}
@@ -9,12 +9,14 @@ import (
 	v0forks "github.com/bloxapp/ssv/ibft/instance/forks/v0"
 	"github.com/bloxapp/ssv/ibft/pipeline"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/local"
 	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/collections"
 	"github.com/bloxapp/ssv/storage/kv"
 	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/bloxapp/ssv/utils/threadsafe"
 	"github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -44,6 +46,10 @@ func (s *testSigner) SignAttestation(data *spec.AttestationData, duty *beacon.Du
 	return nil, nil, nil
 }
 
+func (s *testSigner) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return nil, nil, nil
+}
+
 type testingFork struct {
 	controller *Controller
 }
@@ -55,6 +61,10 @@ func testFork(controller *Controller) *testingFork {
 func (v0 *testingFork) Apply(controller ibft.Controller) {
 }
 
+func (v0 *testingFork) MessageVersion() network.MessageVersion {
+	return network.MessageVersionV0
+}
+
 func (v0 *testingFork) InstanceFork() forks.Fork {
 	return v0forks.New()
 }
@@ -163,7 +173,8 @@ func populatedIbft(
 		proto.DefaultConsensusParams(),
 		share,
 		nil,
-		signer)
+		signer,
+		0)
 	ret.(*Controller).setFork(testFork(ret.(*Controller)))
 	ret.(*Controller).initFinished = true // as if they are already synced
 	ret.(*Controller).listenToNetworkMessages()
@@ -336,3 +347,121 @@ func newInMemDb() basedb.IDb {
 	})
 	return db
 }
+
+func TestInitWarmStart(t *testing.T) {
+	sks, nodes := GenerateNodes(4)
+	ibftStorage := populatedStorage(t, sks, 10)
+	i := New(
+		beacon.RoleTypeAttester,
+		[]byte("lambda_11"),
+		logex.Build("", zap.DebugLevel, nil),
+		ibftStorage,
+		local.NewLocalNetwork(),
+		msgqueue.New(),
+		proto.DefaultConsensusParams(),
+		&storage.Share{
+			NodeID:    1,
+			PublicKey: validatorPK(sks),
+			Committee: nodes,
+		},
+		nil,
+		newTestSigner(),
+		0).(*Controller)
+	i.setFork(testFork(i))
+
+	// no peers are available, init must not wait for the network
+	done := make(chan error)
+	go func() {
+		done <- i.Init()
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("warm start did not finish in time")
+	}
+	require.True(t, i.initFinished)
+	next, err := i.NextSeqNumber()
+	require.NoError(t, err)
+	require.EqualValues(t, 11, next)
+}
+
+func TestWarmStart_PeersAhead(t *testing.T) {
+	sks, nodes := GenerateNodes(4)
+	signer := newTestSigner()
+	network := local.NewLocalNetwork()
+
+	identifier := []byte("lambda_11")
+	i1 := populatedIbft(1, identifier, network, populatedStorage(t, sks, 10), sks, nodes, signer).(*Controller)
+	i2 := populatedIbft(2, identifier, network, populatedStorage(t, sks, 20), sks, nodes, signer).(*Controller)
+
+	// the persisted highest decided is behind peers
+	require.False(t, i1.warmStart())
+	// the persisted highest decided is up to date
+	require.True(t, i2.warmStart())
+}
+
+// stoppableInstance records whether it was stopped
+type stoppableInstance struct {
+	ibft.Instance
+	state   *proto.State
+	stopped bool
+}
+
+func (s *stoppableInstance) State() *proto.State {
+	return s.state
+}
+
+func (s *stoppableInstance) Stop() {
+	s.stopped = true
+}
+
+func TestStopStaleInstance(t *testing.T) {
+	sks, _ := GenerateNodes(4)
+	identifier := []byte("lambda_11")
+	i := &Controller{
+		logger:      zap.L(),
+		ibftStorage: populatedStorage(t, sks, 10),
+		Identifier:  identifier,
+	}
+
+	running := &stoppableInstance{state: &proto.State{SeqNumber: threadsafe.Uint64(11)}}
+	i.currentInstance = running
+	i.stopStaleInstance()
+	require.False(t, running.stopped)
+
+	stale := &stoppableInstance{state: &proto.State{SeqNumber: threadsafe.Uint64(10)}}
+	i.currentInstance = stale
+	i.stopStaleInstance()
+	require.True(t, stale.stopped)
+}
+
+func TestClose(t *testing.T) {
+	i := &Controller{logger: zap.L()}
+	running := &stoppableInstance{state: &proto.State{SeqNumber: threadsafe.Uint64(11)}}
+	i.currentInstance = running
+	i.Close()
+	require.True(t, running.stopped)
+	require.True(t, i.isClosed())
+
+	_, err := i.StartInstance(ibft.ControllerStartInstanceOptions{})
+	require.EqualError(t, err, "controller is closed")
+}
+
+// versionedFork is a testing fork with the given message version
+type versionedFork struct {
+	*testingFork
+	version network.MessageVersion
+}
+
+func (f *versionedFork) MessageVersion() network.MessageVersion {
+	return f.version
+}
+
+func TestMessageVersion(t *testing.T) {
+	i := &Controller{}
+	require.Equal(t, network.CurrentMessageVersion, i.messageVersion())
+
+	i.fork = &versionedFork{testingFork: testFork(i), version: 1}
+	require.EqualValues(t, 1, i.messageVersion())
+}
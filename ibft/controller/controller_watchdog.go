@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"github.com/bloxapp/ssv/ibft"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/bloxapp/ssv/utils/format"
+	"go.uber.org/zap"
+	"math"
+	"time"
+)
+
+var (
+	// instanceWatchdogInterval is the interval in which the running instance is checked
+	instanceWatchdogInterval = 10 * time.Second
+	// instanceStallRounds is the amount of round timeouts w/o progress after which an instance is considered as stalled
+	instanceStallRounds = 3.0
+	// instanceStallMinTimeout is the minimum time w/o progress after which an instance is considered as stalled
+	instanceStallMinTimeout = time.Minute
+)
+
+// watchInstance closes the given stalled channel if the instance stopped making progress, returns once done is closed
+func (i *Controller) watchInstance(instance ibft.Instance, stalled chan<- struct{}, done <-chan struct{}) {
+	ticker := time.NewTicker(instanceWatchdogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		if i.instanceStalled(instance, time.Now()) {
+			i.dumpStalledInstance(instance)
+			pk, role := format.IdentifierUnformat(string(i.Identifier))
+			metricsInstanceStalled.WithLabelValues(role, pk).Inc()
+			close(stalled)
+			return
+		}
+	}
+}
+
+// instanceStalled returns true if the instance processed no events (messages, timeouts) for instanceStallRounds round timeouts
+func (i *Controller) instanceStalled(instance ibft.Instance, now time.Time) bool {
+	last := instance.LastProgress()
+	if last.IsZero() {
+		return false
+	}
+	return now.Sub(last) > i.stallTimeout(instance.State().Round.Get())
+}
+
+// stallTimeout returns the time w/o progress after which an instance in the given round is considered as stalled
+func (i *Controller) stallTimeout(round uint64) time.Duration {
+	roundTimeout := math.Pow(float64(i.instanceConfig.RoundChangeDurationSeconds), float64(round))
+	timeout := time.Duration(float64(time.Second) * roundTimeout * instanceStallRounds)
+	if timeout < instanceStallMinTimeout {
+		return instanceStallMinTimeout
+	}
+	return timeout
+}
+
+// dumpStalledInstance logs the state of a stalled instance
+func (i *Controller) dumpStalledInstance(instance ibft.Instance) {
+	state := instance.State()
+	i.logger.Error("iBFT instance is stalled, aborting",
+		zap.Uint64("seqNum", state.SeqNumber.Get()),
+		zap.Uint64("round", state.Round.Get()),
+		zap.String("stage", proto.RoundState(state.Stage.Get()).String()),
+		zap.Uint64("preparedRound", state.PreparedRound.Get()),
+		zap.Bool("hasPreparedValue", len(state.PreparedValue.Get()) > 0),
+		zap.Time("lastProgress", instance.LastProgress()),
+		zap.Int("queuedMsgs", i.msgQueue.MsgCount(msgqueue.IBFTMessageIndexKey(state.Lambda.Get(), state.SeqNumber.Get()))))
+}
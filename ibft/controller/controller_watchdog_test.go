@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"github.com/bloxapp/ssv/ibft"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/bloxapp/ssv/utils/threadsafe"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type stalledInstance struct {
+	ibft.Instance
+	state        *proto.State
+	lastProgress time.Time
+}
+
+func (s *stalledInstance) State() *proto.State {
+	return s.state
+}
+
+func (s *stalledInstance) LastProgress() time.Time {
+	return s.lastProgress
+}
+
+func newStalledInstance(round uint64, lastProgress time.Time) *stalledInstance {
+	return &stalledInstance{
+		state: &proto.State{
+			Stage:         threadsafe.Int32(int32(proto.RoundState_Prepare)),
+			Lambda:        threadsafe.Bytes([]byte("lambda_11")),
+			SeqNumber:     threadsafe.Uint64(2),
+			Round:         threadsafe.Uint64(round),
+			PreparedRound: threadsafe.Uint64(0),
+			PreparedValue: threadsafe.Bytes(nil),
+		},
+		lastProgress: lastProgress,
+	}
+}
+
+func TestInstanceStalled(t *testing.T) {
+	i := &Controller{instanceConfig: proto.DefaultConsensusParams()}
+	now := time.Now()
+
+	require.False(t, i.instanceStalled(newStalledInstance(1, time.Time{}), now))
+	require.False(t, i.instanceStalled(newStalledInstance(1, now.Add(-30*time.Second)), now))
+	require.True(t, i.instanceStalled(newStalledInstance(1, now.Add(-2*time.Minute)), now))
+	// higher rounds have longer timeouts (3^4 * 3 seconds)
+	require.False(t, i.instanceStalled(newStalledInstance(4, now.Add(-2*time.Minute)), now))
+	require.True(t, i.instanceStalled(newStalledInstance(4, now.Add(-5*time.Minute)), now))
+}
+
+func TestWatchInstance(t *testing.T) {
+	interval := instanceWatchdogInterval
+	instanceWatchdogInterval = 10 * time.Millisecond
+	defer func() {
+		instanceWatchdogInterval = interval
+	}()
+	i := &Controller{
+		instanceConfig: proto.DefaultConsensusParams(),
+		logger:         zap.L(),
+		msgQueue:       msgqueue.New(),
+		Identifier:     []byte("lambda_11"),
+	}
+
+	t.Run("abort stalled instance", func(t *testing.T) {
+		stalled := make(chan struct{})
+		done := make(chan struct{})
+		defer close(done)
+		go i.watchInstance(newStalledInstance(1, time.Now().Add(-2*time.Minute)), stalled, done)
+		select {
+		case <-stalled:
+		case <-time.After(time.Second):
+			require.Fail(t, "stalled instance was not detected")
+		}
+	})
+
+	t.Run("instance in progress", func(t *testing.T) {
+		stalled := make(chan struct{})
+		done := make(chan struct{})
+		go i.watchInstance(newStalledInstance(1, time.Now()), stalled, done)
+		select {
+		case <-stalled:
+			require.Fail(t, "instance in progress was aborted")
+		case <-time.After(100 * time.Millisecond):
+		}
+		close(done)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/bloxapp/ssv/ibft"
 	"github.com/bloxapp/ssv/ibft/instance/forks"
 	"github.com/bloxapp/ssv/ibft/pipeline"
+	"github.com/bloxapp/ssv/network"
 )
 
 // Fork holds all fork related implementations for the controller
@@ -11,4 +12,6 @@ type Fork interface {
 	Apply(controller ibft.Controller)
 	InstanceFork() forks.Fork
 	ValidateDecidedMsg() pipeline.Pipeline
+	// MessageVersion returns the version of the messages of the fork, incoming messages are queued with it
+	MessageVersion() network.MessageVersion
 }
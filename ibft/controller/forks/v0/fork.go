@@ -6,6 +6,7 @@ import (
 	"github.com/bloxapp/ssv/ibft/instance/forks"
 	v02 "github.com/bloxapp/ssv/ibft/instance/forks/v0"
 	"github.com/bloxapp/ssv/ibft/pipeline"
+	"github.com/bloxapp/ssv/network"
 )
 
 // ForkV0 is the genesis fork for controller
@@ -32,3 +33,8 @@ func (v0 *ForkV0) InstanceFork() forks.Fork {
 func (v0 *ForkV0) ValidateDecidedMsg() pipeline.Pipeline {
 	return v0.ctrl.ValidateDecidedMsgV0()
 }
+
+// MessageVersion returns the version of the messages of the fork
+func (v0 *ForkV0) MessageVersion() network.MessageVersion {
+	return network.MessageVersionV0
+}
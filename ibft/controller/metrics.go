@@ -12,10 +12,42 @@ var (
 		Name: "ssv:validator:ibft_current_sequence",
 		Help: "The highest decided sequence number",
 	}, []string{"lambda", "pubKey"})
+	// metricsHighestDecidedAnnouncements counts announcements of peers that are ahead, by status
+	metricsHighestDecidedAnnouncements = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:ibft_highest_decided_announcements",
+		Help: "Count of highest decided announcements that are ahead of this node",
+	}, []string{"status"})
+	// metricsInstanceStalled counts instances that were aborted by the watchdog
+	metricsInstanceStalled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:ibft_instance_stalled",
+		Help: "Count of iBFT instances that were aborted as they stopped making progress",
+	}, []string{"lambda", "pubKey"})
+	// metricsOutOfSeqWindow counts network messages that were rejected as they are outside of the seq window
+	metricsOutOfSeqWindow = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:ibft_out_of_seq_window",
+		Help: "Count of network messages that were rejected as their seq is too far from the highest decided",
+	}, []string{"type", "reason"})
+	// metricsInitDuration is the time it took for the controller to be ready for duties after (re)start
+	metricsInitDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:validator:ibft_init_duration_seconds",
+		Help: "Time from the start of iBFT controller init until it accepts duties",
+	}, []string{"lambda", "pubKey", "start"})
 )
 
 func init() {
 	if err := prometheus.Register(metricsCurrentSequence); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricsHighestDecidedAnnouncements); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsInstanceStalled); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsOutOfSeqWindow); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsInitDuration); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
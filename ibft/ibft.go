@@ -5,9 +5,14 @@ import (
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/ibft/valcheck"
 	"github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"time"
 )
 
+// ErrDeadlineExceeded is returned when an instance didn't decide before its deadline
+var ErrDeadlineExceeded = errors.New("iBFT instance deadline exceeded")
+
 // ControllerStartInstanceOptions defines type for Controller instance options
 type ControllerStartInstanceOptions struct {
 	Logger         *zap.Logger
@@ -18,6 +23,8 @@ type ControllerStartInstanceOptions struct {
 	// RequireMinPeers flag to require minimum peers before starting an instance
 	// useful for tests where we want (sometimes) to avoid networking
 	RequireMinPeers bool
+	// Deadline is the time after which the instance is aborted if not decided, zero means no deadline
+	Deadline time.Time
 }
 
 // InstanceResult is a struct holding the result of a single iBFT instance
@@ -43,6 +50,9 @@ type Controller interface {
 
 	// GetIdentifier returns ibft identifier made of public key and role (type)
 	GetIdentifier() []byte
+
+	// Close stops the running instance and the processing of messages, the controller can't be used afterwards
+	Close()
 }
 
 // Instance represents an iBFT instance (a single sequence number)
@@ -56,6 +66,8 @@ type Instance interface {
 	GetStageChan() chan proto.RoundState
 	GetLastChangeRoundMsg() *proto.SignedMessage
 	CommittedAggregatedMsg() (*proto.SignedMessage, error)
+	// LastProgress returns the time of the last event (message, timeout or stage change) processed by the instance
+	LastProgress() time.Time
 }
 
 // Pipelines holds all major instance pipeline implementations
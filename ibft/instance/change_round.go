@@ -120,7 +120,7 @@ func (i *Instance) uponChangeRoundFullQuorum() pipeline.Pipeline {
 			// send pre-prepare msg
 			broadcastMsg := i.generatePrePrepareMessage(value)
 			if e := i.SignAndBroadcast(broadcastMsg); e != nil {
-				logger.Error("could not broadcast pre-prepare message after round change", zap.Error(err))
+				logger.Error("could not broadcast pre-prepare message after round change", zap.Error(e))
 				err = e
 			}
 		})
@@ -201,6 +201,11 @@ func (i *Instance) uponChangeRoundTrigger() {
 	}
 }
 
+// TriggerRoundTimeout acts as if the round timer expired, used to replay recorded instances w/o timers
+func (i *Instance) TriggerRoundTimeout() {
+	i.uponChangeRoundTrigger()
+}
+
 func (i *Instance) broadcastChangeRound() error {
 	broadcastMsg, err := i.generateChangeRoundMessage()
 	if err != nil {
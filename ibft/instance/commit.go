@@ -47,6 +47,7 @@ func (i *Instance) CommitMsgPipeline() pipeline.Pipeline {
 func (i *Instance) CommitMsgPipelineV0() pipeline.Pipeline {
 	return pipeline.Combine(
 		i.CommitMsgValidationPipeline(),
+		pipeline.RunMiddlewares(pipeline.HookCommit, i.pubKeyHex),
 		pipeline.WrapFunc("add commit msg", func(signedMessage *proto.SignedMessage) error {
 			i.Logger.Info("received valid commit message for round",
 				zap.String("sender_ibft_id", signedMessage.SignersIDString()),
@@ -1,11 +1,13 @@
 package ibft
 
 import (
+	"github.com/bloxapp/ssv/ibft/pipeline"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/collections"
 	"github.com/bloxapp/ssv/storage/kv"
 	"github.com/bloxapp/ssv/utils/threadsafe"
 	"github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"strings"
 	"testing"
@@ -190,7 +192,41 @@ func TestCommitPipeline(t *testing.T) {
 	}
 	instance.setFork(testingFork(instance))
 	pipeline := instance.CommitMsgPipeline()
-	require.EqualValues(t, "combination of: combination of: basic msg validation, type check, lambda, sequence, authorize, , add commit msg, upon commit msg, ", pipeline.Name())
+	require.EqualValues(t, "combination of: combination of: basic msg validation, type check, lambda, sequence, authorize, , middlewares (commit), add commit msg, upon commit msg, ", pipeline.Name())
+}
+
+func TestCommitPipeline_Middleware(t *testing.T) {
+	sks, nodes := GenerateNodes(4)
+	instance := &Instance{
+		CommitMessages: msgcontinmem.New(3, 2),
+		ValidatorShare: &storage.Share{Committee: nodes, PublicKey: sks[1].GetPublicKey()},
+		state: &proto.State{
+			Round:     threadsafe.Uint64(1),
+			Lambda:    threadsafe.Bytes([]byte("Lambda")),
+			SeqNumber: threadsafe.Uint64(0),
+		},
+		Logger: zap.L(),
+	}
+	instance.setFork(testingFork(instance))
+	pipeline.Middlewares.Register(sks[1].GetPublicKey().SerializeToHexStr(), pipeline.HookCommit, 0,
+		pipeline.WrapFunc("reject", func(signedMessage *proto.SignedMessage) error {
+			if signedMessage.SignerIds[0] == 2 {
+				return errors.New("rejected by policy")
+			}
+			return nil
+		}))
+
+	msg := func(id uint64) *proto.SignedMessage {
+		return SignMsg(t, id, sks[id], &proto.Message{
+			Type:   proto.RoundState_Commit,
+			Round:  1,
+			Lambda: []byte("Lambda"),
+			Value:  []byte("value"),
+		})
+	}
+	require.NoError(t, instance.CommitMsgPipeline().Run(msg(1)))
+	require.EqualError(t, instance.CommitMsgPipeline().Run(msg(2)), "rejected by policy")
+	require.Len(t, instance.CommitMessages.ReadOnlyMessagesByRound(1), 1)
 }
 
 func TestProcessLateCommitMsg(t *testing.T) {
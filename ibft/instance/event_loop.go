@@ -1,6 +1,7 @@
 package ibft
 
 import (
+	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/msgqueue"
 	"go.uber.org/zap"
 	"sync"
@@ -35,6 +36,7 @@ loop:
 
 		if f := i.eventQueue.Pop(); f != nil {
 			f()
+			i.markProgress()
 		} else {
 			time.Sleep(time.Millisecond * 100)
 		}
@@ -60,6 +62,7 @@ loop:
 			if added := i.eventQueue.Add(func() {
 				_, err := i.ProcessMessage()
 				if err != nil {
+					network.ReportRejectedMessage(err)
 					logger.Error("msg pipeline error", zap.Error(err))
 				}
 				wg.Done()
@@ -37,6 +37,10 @@ func (s *testSigner) SignAttestation(data *spec.AttestationData, duty *beacon.Du
 	return nil, nil, nil
 }
 
+func (s *testSigner) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return nil, nil, nil
+}
+
 func TestChangeRoundTimer(t *testing.T) {
 	secretKeys, nodes := GenerateNodes(4)
 	instance := &Instance{
@@ -13,6 +13,7 @@ import (
 	"github.com/bloxapp/ssv/validator/storage"
 	"github.com/pkg/errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bloxapp/ssv/ibft/leader"
@@ -44,6 +45,8 @@ type InstanceOptions struct {
 	// Fork sets the current fork to apply on instance
 	Fork   forks.Fork
 	Signer beacon.Signer
+	// Deadline bounds the broadcast retries of the instance messages, zero means no deadline
+	Deadline time.Time
 }
 
 // Instance defines the instance attributes
@@ -58,6 +61,7 @@ type Instance struct {
 	Logger         *zap.Logger
 	fork           forks.Fork
 	signer         beacon.Signer
+	deadline       time.Time
 
 	// messages
 	MsgQueue            *msgqueue.MessageQueue
@@ -78,6 +82,9 @@ type Instance struct {
 	stopped     bool
 	initialized bool
 
+	// lastProgress is the time (unix nano) of the last processed event, used to detect stalled instances
+	lastProgress int64
+
 	// locks
 	runInitOnce                  sync.Once
 	runStopOnce                  sync.Once
@@ -117,7 +124,8 @@ func NewInstance(opts *InstanceOptions) ibft.Instance {
 		Logger: opts.Logger.With(zap.Uint64("node_id", opts.ValidatorShare.NodeID),
 			zap.Uint64("seq_num", opts.SeqNumber),
 			zap.String("pubKey", opts.ValidatorShare.PublicKey.SerializeToHexStr())),
-		signer: opts.Signer,
+		signer:   opts.Signer,
+		deadline: opts.Deadline,
 
 		MsgQueue:            opts.Queue,
 		PrePrepareMessages:  msgcontinmem.New(uint64(opts.ValidatorShare.ThresholdSize()), uint64(opts.ValidatorShare.PartialThresholdSize())),
@@ -151,6 +159,7 @@ func (i *Instance) Init() {
 		go i.startRoundTimerLoop()
 		go i.StartMainEventLoop()
 		i.initialized = true
+		i.markProgress()
 		i.Logger.Debug("iBFT instance init finished")
 	})
 }
@@ -198,8 +207,9 @@ func (i *Instance) Start(inputValue []byte) error {
 
 			msg := i.generatePrePrepareMessage(i.State().InputValue.Get())
 			//
+			// a failed broadcast (after retries) is recovered by the round timer, which triggers a round change
 			if err := i.SignAndBroadcast(msg); err != nil {
-				i.Logger.Fatal("could not broadcast pre-prepare", zap.Error(err))
+				i.Logger.Error("could not broadcast pre-prepare", zap.Error(err))
 			}
 		}()
 	}
@@ -207,6 +217,11 @@ func (i *Instance) Start(inputValue []byte) error {
 	return nil
 }
 
+// pubKeyHex returns the public key of the validator, used to resolve its middlewares
+func (i *Instance) pubKeyHex() string {
+	return i.ValidatorShare.PublicKey.SerializeToHexStr()
+}
+
 // ForceDecide will attempt to decide the instance with provided decided signed msg.
 func (i *Instance) ForceDecide(msg *proto.SignedMessage) {
 	i.eventQueue.Add(func() {
@@ -280,6 +295,7 @@ func (i *Instance) ProcessStageChange(stage proto.RoundState) {
 	metricsIBFTStage.WithLabelValues(role, pk).Set(float64(stage))
 
 	i.State().Stage.Set(int32(stage))
+	i.markProgress()
 
 	// Delete all queue messages when decided, we do not need them anymore.
 	if stage == proto.RoundState_Decided || stage == proto.RoundState_Stopped {
@@ -326,6 +342,9 @@ func (i *Instance) SignAndBroadcast(msg *proto.Message) error {
 	}
 
 	if i.network != nil {
+		if db, ok := i.network.(network.DeadlineBroadcaster); ok && !i.deadline.IsZero() {
+			return db.BroadcastWithDeadline(i.ValidatorShare.PublicKey.Serialize(), signedMessage, i.deadline)
+		}
 		return i.network.Broadcast(i.ValidatorShare.PublicKey.Serialize(), signedMessage)
 	}
 	return errors.New("no networking, could not broadcast msg")
@@ -355,6 +374,19 @@ func (i *Instance) CommittedAggregatedMsg() (*proto.SignedMessage, error) {
 	return nil, errors.New("missing decided message")
 }
 
+// LastProgress returns the time of the last event (message, timeout or stage change) processed by the instance
+func (i *Instance) LastProgress() time.Time {
+	last := atomic.LoadInt64(&i.lastProgress)
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+func (i *Instance) markProgress() {
+	atomic.StoreInt64(&i.lastProgress, time.Now().UnixNano())
+}
+
 func (i *Instance) setFork(fork forks.Fork) {
 	if fork == nil {
 		return
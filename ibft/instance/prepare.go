@@ -25,6 +25,7 @@ func (i *Instance) PrepareMsgPipelineV0() pipeline.Pipeline {
 		auth.ValidateLambdas(i.State().Lambda.Get()),
 		auth.ValidateSequenceNumber(i.State().SeqNumber.Get()),
 		auth.AuthorizeMsg(i.ValidatorShare),
+		pipeline.RunMiddlewares(pipeline.HookPrepare, i.pubKeyHex),
 		pipeline.WrapFunc("add prepare msg", func(signedMessage *proto.SignedMessage) error {
 			i.Logger.Info("received valid prepare message from round",
 				zap.String("sender_ibft_id", signedMessage.SignersIDString()),
@@ -94,7 +95,7 @@ func (i *Instance) uponPrepareMsg() pipeline.Pipeline {
 				// send commit msg
 				broadcastMsg := i.generateCommitMessage(i.State().PreparedValue.Get())
 				if e := i.SignAndBroadcast(broadcastMsg); e != nil {
-					i.Logger.Info("could not broadcast commit message", zap.Error(err))
+					i.Logger.Info("could not broadcast commit message", zap.Error(e))
 					err = e
 				}
 			})
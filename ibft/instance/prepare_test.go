@@ -93,5 +93,5 @@ func TestPreparePipeline(t *testing.T) {
 	}
 	instance.fork = testingFork(instance)
 	pipeline := instance.PrepareMsgPipeline()
-	require.EqualValues(t, "combination of: basic msg validation, type check, lambda, sequence, authorize, add prepare msg, if first pipeline non error, continue to second, ", pipeline.Name())
+	require.EqualValues(t, "combination of: basic msg validation, type check, lambda, sequence, authorize, middlewares (prepare), add prepare msg, if first pipeline non error, continue to second, ", pipeline.Name())
 }
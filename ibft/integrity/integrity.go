@@ -0,0 +1,118 @@
+package integrity
+
+import (
+	"github.com/bloxapp/ssv/ibft/pipeline"
+	"github.com/bloxapp/ssv/ibft/pipeline/auth"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Storage is the storage of the decided messages that are verified
+type Storage interface {
+	// GetDecided returns the decided message of the given sequence number
+	GetDecided(identifier []byte, seqNumber uint64) (*proto.SignedMessage, bool, error)
+	// GetHighestDecidedInstance returns the highest decided message
+	GetHighestDecidedInstance(identifier []byte) (*proto.SignedMessage, bool, error)
+	// DeleteDecided removes the decided message of the given sequence number, the highest decided is moved below it
+	DeleteDecided(identifier []byte, seqNumber uint64) error
+}
+
+// Options defines the decided history to verify
+type Options struct {
+	Logger  *zap.Logger
+	Storage Storage
+	// Share holds the committee (from the registry data) that the aggregated signatures are verified against
+	Share *storage.Share
+	// Identifier is the lambda of the validator, i.e. format.IdentifierFormat(pk, role)
+	Identifier []byte
+	// Purge removes invalid decided messages from the storage and moves the highest decided below the lowest of them,
+	// so the history sync fetches them (and the following messages) once again
+	Purge bool
+}
+
+// InvalidDecided is a stored decided message that failed verification
+type InvalidDecided struct {
+	SeqNumber uint64 `json:"seq"`
+	Error     string `json:"error"`
+	// Highest is true if the stored highest decided (rather than the decided message) is invalid
+	Highest bool `json:"highest,omitempty"`
+	Purged  bool `json:"purged"`
+}
+
+// Report is the result of the verification of the decided history of a validator
+type Report struct {
+	Identifier string `json:"identifier"`
+	// Highest is the sequence number of the highest decided, before purging
+	Highest  uint64           `json:"highest"`
+	Verified uint64           `json:"verified"`
+	Missing  uint64           `json:"missing"`
+	Invalid  []InvalidDecided `json:"invalid,omitempty"`
+}
+
+// VerifyDecided re-verifies the stored decided messages of the given identifier, up to the highest decided,
+// against the committee of the given share. invalid messages are purged if requested,
+// an invalid highest decided is purged along with its decided message
+func VerifyDecided(opts Options) (*Report, error) {
+	if opts.Share == nil || len(opts.Share.Committee) == 0 {
+		return nil, errors.New("missing committee")
+	}
+	res := &Report{Identifier: string(opts.Identifier)}
+	highest, found, err := opts.Storage.GetHighestDecidedInstance(opts.Identifier)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get highest decided")
+	}
+	if !found {
+		return res, nil
+	}
+	res.Highest = highest.Message.SeqNumber
+
+	for seq := uint64(0); seq <= res.Highest; seq++ {
+		msg, found, err := opts.Storage.GetDecided(opts.Identifier, seq)
+		if err == nil && !found {
+			res.Missing++
+			continue
+		}
+		if err == nil {
+			err = validateDecided(msg, opts.Share, opts.Identifier, seq)
+		}
+		if err == nil {
+			res.Verified++
+			continue
+		}
+		res.Invalid = append(res.Invalid, InvalidDecided{SeqNumber: seq, Error: err.Error()})
+	}
+	// the highest decided is stored separately, it is reported only if the decided message itself is valid
+	highestReported := len(res.Invalid) > 0 && res.Invalid[len(res.Invalid)-1].SeqNumber == res.Highest
+	if err := validateDecided(highest, opts.Share, opts.Identifier, res.Highest); err != nil && !highestReported {
+		res.Invalid = append(res.Invalid, InvalidDecided{SeqNumber: res.Highest, Error: err.Error(), Highest: true})
+	}
+
+	if opts.Purge {
+		for i := range res.Invalid {
+			if err := opts.Storage.DeleteDecided(opts.Identifier, res.Invalid[i].SeqNumber); err != nil {
+				return res, errors.Wrapf(err, "could not purge decided %d", res.Invalid[i].SeqNumber)
+			}
+			res.Invalid[i].Purged = true
+		}
+	}
+	if len(res.Invalid) > 0 && opts.Logger != nil {
+		opts.Logger.Warn("found invalid decided messages", zap.String("identifier", res.Identifier),
+			zap.Int("count", len(res.Invalid)), zap.Bool("purged", opts.Purge))
+	}
+	return res, nil
+}
+
+// validateDecided validates the given decided message of the given sequence number,
+// including the aggregated signature of the committee
+func validateDecided(msg *proto.SignedMessage, share *storage.Share, identifier []byte, seq uint64) error {
+	return pipeline.Combine(
+		auth.BasicMsgValidation(),
+		auth.MsgTypeCheck(proto.RoundState_Commit),
+		auth.ValidateLambdas(identifier),
+		auth.ValidateSequenceNumber(seq),
+		auth.AuthorizeMsg(share),
+		auth.ValidateQuorum(share.ThresholdSize()),
+	).Run(msg)
+}
@@ -0,0 +1,84 @@
+package integrity
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/ibft/sync"
+	"github.com/bloxapp/ssv/validator/storage"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestVerifyDecided(t *testing.T) {
+	sks, nodes := sync.GenerateNodes(4)
+	ibftStorage := sync.TestingIbftStorage(t)
+	identifier := []byte("6139636633363061_ATTESTER")
+	share := &storage.Share{NodeID: 1, PublicKey: sks[1].GetPublicKey(), Committee: nodes}
+	decided := func(seq uint64, signers ...uint64) *proto.SignedMessage {
+		return sync.MultiSignMsg(t, signers, sks, &proto.Message{
+			Type:      proto.RoundState_Commit,
+			Round:     1,
+			Lambda:    identifier,
+			SeqNumber: seq,
+			Value:     []byte("value"),
+		})
+	}
+
+	require.NoError(t, ibftStorage.SaveDecided(decided(0, 1, 2, 3)))
+	// no quorum
+	require.NoError(t, ibftStorage.SaveDecided(decided(2, 1, 2)))
+	// the signature doesn't match the signers
+	invalidSig := decided(3, 1, 2, 3)
+	invalidSig.SignerIds = []uint64{2, 3, 4}
+	require.NoError(t, ibftStorage.SaveDecided(invalidSig))
+	require.NoError(t, ibftStorage.SaveDecidedAndHighest(decided(4, 2, 3, 4)))
+
+	opts := Options{Storage: &ibftStorage, Share: share, Identifier: identifier}
+	res, err := VerifyDecided(opts)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, res.Highest)
+	require.EqualValues(t, 2, res.Verified)
+	require.EqualValues(t, 1, res.Missing)
+	require.Len(t, res.Invalid, 2)
+	require.EqualValues(t, 2, res.Invalid[0].SeqNumber)
+	require.EqualValues(t, 3, res.Invalid[1].SeqNumber)
+	require.False(t, res.Invalid[0].Purged)
+	_, found, err := ibftStorage.GetDecided(identifier, 2)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	opts.Purge = true
+	res, err = VerifyDecided(opts)
+	require.NoError(t, err)
+	require.Len(t, res.Invalid, 2)
+	require.True(t, res.Invalid[0].Purged)
+	for _, seq := range []uint64{2, 3} {
+		_, found, err := ibftStorage.GetDecided(identifier, seq)
+		require.NoError(t, err)
+		require.False(t, found)
+	}
+	// the highest decided is moved below the purged messages, so they will be synced once again
+	highest, found, err := ibftStorage.GetHighestDecidedInstance(identifier)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 0, highest.Message.SeqNumber)
+
+	// an invalid highest decided is purged along with its decided message
+	require.NoError(t, ibftStorage.SaveHighestDecidedInstance(decided(4, 1, 2)))
+	res, err = VerifyDecided(opts)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, res.Verified)
+	require.Len(t, res.Invalid, 1)
+	require.True(t, res.Invalid[0].Highest)
+	highest, found, err = ibftStorage.GetHighestDecidedInstance(identifier)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 0, highest.Message.SeqNumber)
+
+	res, err = VerifyDecided(opts)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Verified)
+	require.Empty(t, res.Invalid)
+
+	_, err = VerifyDecided(Options{Storage: &ibftStorage, Share: &storage.Share{}, Identifier: identifier})
+	require.EqualError(t, err, "missing committee")
+}
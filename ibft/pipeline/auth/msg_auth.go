@@ -12,3 +12,10 @@ func AuthorizeMsg(share *storage.Share) pipeline.Pipeline {
 		return share.VerifySignedMessage(signedMessage)
 	})
 }
+
+// AuthorizeMsgWith is the pipeline to authorize message, signatures are verified by the given verifier
+func AuthorizeMsgWith(share *storage.Share, verify proto.SigVerifier) pipeline.Pipeline {
+	return pipeline.WrapFunc("authorize", func(signedMessage *proto.SignedMessage) error {
+		return share.VerifySignedMessageWith(signedMessage, verify)
+	})
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"bytes"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/pkg/errors"
 
 	"github.com/bloxapp/ssv/ibft/pipeline"
@@ -12,7 +13,8 @@ import (
 func ValidateLambdas(lambda []byte) pipeline.Pipeline {
 	return pipeline.WrapFunc("lambda", func(signedMessage *proto.SignedMessage) error {
 		if !bytes.Equal(signedMessage.Message.Lambda, lambda) {
-			return errors.Errorf("message Lambda (%s) does not equal expected Lambda (%s)", string(signedMessage.Message.Lambda), string(lambda))
+			return errs.Mark(errors.Errorf("message Lambda (%s) does not equal expected Lambda (%s)",
+				string(signedMessage.Message.Lambda), string(lambda)), errs.ErrUnknownValidator)
 		}
 		return nil
 	})
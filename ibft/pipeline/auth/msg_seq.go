@@ -3,6 +3,7 @@ package auth
 import (
 	"github.com/bloxapp/ssv/ibft/pipeline"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/pkg/errors"
 )
 
@@ -12,7 +13,7 @@ func ValidateSequenceNumber(seq uint64) pipeline.Pipeline {
 		if signedMessage.Message.SeqNumber != seq {
 			err := errors.Errorf("expected: %d, actual: %d",
 				seq, signedMessage.Message.SeqNumber)
-			return errors.Wrap(err, "invalid message sequence number")
+			return errs.Mark(errors.Wrap(err, "invalid message sequence number"), errs.ErrStaleMessage)
 		}
 		return nil
 	})
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -40,6 +42,7 @@ func TestMsgSeq(t *testing.T) {
 				require.NoError(t, err)
 			} else {
 				require.EqualError(t, err, test.expectedError)
+				require.True(t, errors.Is(err, errs.ErrStaleMessage))
 			}
 		})
 	}
@@ -3,6 +3,7 @@ package changeround
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/bloxapp/ssv/validator/storage"
 	"github.com/pkg/errors"
 
@@ -72,7 +73,7 @@ func (p *validateJustification) Run(signedMessage *proto.SignedMessage) error {
 
 	}
 	if !res {
-		return errors.New("change round justification signature doesn't verify")
+		return errs.Mark(errors.New("change round justification signature doesn't verify"), errs.ErrInvalidSignature)
 	}
 
 	return nil
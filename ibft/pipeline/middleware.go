@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/bloxapp/ssv/ibft/proto"
+)
+
+// Hook is the processing stage that a middleware is attached to
+type Hook string
+
+const (
+	// HookPrepare runs on valid prepare messages, before they are processed by the instance
+	HookPrepare Hook = "prepare"
+	// HookCommit runs on valid commit messages, before they are processed by the instance
+	HookCommit Hook = "commit"
+	// HookDecided runs on valid decided messages, before they are processed by the controller
+	HookDecided Hook = "decided"
+)
+
+// AllValidators is used to register a middleware for the messages of all validators
+const AllValidators = ""
+
+// middleware is a registered pipeline
+type middleware struct {
+	pipeline Pipeline
+	priority int
+	// seq is the registration order, used to keep the order of middlewares with the same priority
+	seq uint64
+}
+
+// MiddlewareRegistry holds custom pipelines (e.g. audit logging, policy checks) that run as part of messages processing,
+// a middleware that returns an error stops the processing of the message
+type MiddlewareRegistry struct {
+	lock        sync.RWMutex
+	seq         uint64
+	middlewares map[string]map[Hook][]middleware
+}
+
+// NewMiddlewareRegistry creates a new instance
+func NewMiddlewareRegistry() *MiddlewareRegistry {
+	return &MiddlewareRegistry{
+		middlewares: map[string]map[Hook][]middleware{},
+	}
+}
+
+// Register adds a middleware to the given hook of the given validator (hex public key) or AllValidators.
+// middlewares run by ascending priority, and by registration order for the same priority
+func (r *MiddlewareRegistry) Register(pubKey string, hook Hook, priority int, p Pipeline) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	hooks, ok := r.middlewares[pubKey]
+	if !ok {
+		hooks = map[Hook][]middleware{}
+		r.middlewares[pubKey] = hooks
+	}
+	r.seq++
+	hooks[hook] = append(hooks[hook], middleware{pipeline: p, priority: priority, seq: r.seq})
+}
+
+// Pipeline returns the combined middlewares of the given hook and validator, including the ones of all validators
+func (r *MiddlewareRegistry) Pipeline(pubKey string, hook Hook) Pipeline {
+	r.lock.RLock()
+	mws := make([]middleware, 0)
+	mws = append(mws, r.middlewares[AllValidators][hook]...)
+	if pubKey != AllValidators {
+		mws = append(mws, r.middlewares[pubKey][hook]...)
+	}
+	r.lock.RUnlock()
+
+	sort.Slice(mws, func(i, j int) bool {
+		if mws[i].priority != mws[j].priority {
+			return mws[i].priority < mws[j].priority
+		}
+		return mws[i].seq < mws[j].seq
+	})
+	pipelines := make([]Pipeline, len(mws))
+	for i, mw := range mws {
+		pipelines[i] = mw.pipeline
+	}
+	return Combine(pipelines...)
+}
+
+// Middlewares is the default registry, used by ibft instances and controllers
+var Middlewares = NewMiddlewareRegistry()
+
+// RunMiddlewares returns a pipeline that runs the middlewares of the default registry,
+// the public key is resolved once the pipeline runs so middlewares can be registered at any time
+func RunMiddlewares(hook Hook, pubKey func() string) Pipeline {
+	return WrapFunc("middlewares ("+string(hook)+")", func(signedMessage *proto.SignedMessage) error {
+		return Middlewares.Pipeline(pubKey(), hook).Run(signedMessage)
+	})
+}
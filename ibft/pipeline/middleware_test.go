@@ -0,0 +1,40 @@
+package pipeline
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMiddlewareRegistry(t *testing.T) {
+	r := NewMiddlewareRegistry()
+	var called []string
+	mw := func(name string, err error) Pipeline {
+		return WrapFunc(name, func(signedMessage *proto.SignedMessage) error {
+			called = append(called, name)
+			return err
+		})
+	}
+	r.Register("pk1", HookPrepare, 0, mw("pk1-a", nil))
+	r.Register(AllValidators, HookPrepare, 0, mw("all-a", nil))
+	r.Register("pk1", HookPrepare, -1, mw("pk1-first", nil))
+	r.Register("pk1", HookPrepare, 0, mw("pk1-b", nil))
+	r.Register("pk1", HookCommit, 0, mw("pk1-commit", errors.New("rejected")))
+	r.Register("pk2", HookPrepare, 0, mw("pk2", nil))
+
+	require.NoError(t, r.Pipeline("pk1", HookPrepare).Run(&proto.SignedMessage{}))
+	require.Equal(t, []string{"pk1-first", "pk1-a", "all-a", "pk1-b"}, called)
+
+	called = nil
+	require.NoError(t, r.Pipeline("pk3", HookPrepare).Run(&proto.SignedMessage{}))
+	require.Equal(t, []string{"all-a"}, called)
+
+	called = nil
+	require.EqualError(t, r.Pipeline("pk1", HookCommit).Run(&proto.SignedMessage{}), "rejected")
+	require.Equal(t, []string{"pk1-commit"}, called)
+
+	called = nil
+	require.NoError(t, r.Pipeline("pk1", HookDecided).Run(&proto.SignedMessage{}))
+	require.Empty(t, called)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
 )
@@ -57,10 +58,19 @@ func (msg *SignedMessage) VerifySig(pk *bls.PublicKey) (bool, error) {
 	return msg.VerifyAggregatedSig([]*bls.PublicKey{pk})
 }
 
+// SigVerifier verifies a signature of the given root against the public key
+type SigVerifier func(sig *bls.Sign, pk *bls.PublicKey, root []byte) bool
+
 // VerifyAggregatedSig returns true if the  signed msg verifies against the public keys, false if otherwise
 func (msg *SignedMessage) VerifyAggregatedSig(pks []*bls.PublicKey) (bool, error) {
+	return msg.VerifyAggregatedSigWith(pks, nil)
+}
+
+// VerifyAggregatedSigWith is the same as VerifyAggregatedSig but delegates the actual signature verification
+// to the given verifier, if nil the signature is verified directly
+func (msg *SignedMessage) VerifyAggregatedSigWith(pks []*bls.PublicKey, verify SigVerifier) (bool, error) {
 	if msg.Signature == nil || len(msg.Signature) == 0 {
-		return false, errors.New("message signature is invalid")
+		return false, errs.Mark(errors.New("message signature is invalid"), errs.ErrInvalidSignature)
 	}
 
 	if len(pks) == 0 {
@@ -92,6 +102,9 @@ func (msg *SignedMessage) VerifyAggregatedSig(pks []*bls.PublicKey) (bool, error
 	if err := sig.Deserialize(msg.Signature); err != nil {
 		return false, err
 	}
+	if verify != nil {
+		return verify(sig, aggPK, root), nil
+	}
 	return sig.VerifyByte(aggPK, root), nil
 }
 
@@ -0,0 +1,54 @@
+//go:build go1.18
+// +build go1.18
+
+package proto
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func FuzzSignedMessage_Decode(f *testing.F) {
+	sks, _ := generateNodes(1)
+	signed, _ := signMsg(0, sks[0], &Message{
+		Type:      RoundState_Commit,
+		Round:     1,
+		Lambda:    []byte("lambda_ATTESTER"),
+		SeqNumber: 12,
+		Value:     []byte("value"),
+	})
+	seed, err := json.Marshal(signed)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(seed)
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"message":null,"signature":"AA==","signer_ids":[1,1]}`))
+	f.Add([]byte(`{"message":{"value":"e30="},"signature":""}`))
+	pk := sks[0].GetPublicKey()
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg := &SignedMessage{}
+		if err := json.Unmarshal(data, msg); err != nil {
+			return
+		}
+		_ = msg.SignersIDString()
+		if msg.Message == nil {
+			return
+		}
+		cp, err := msg.DeepCopy()
+		if err != nil {
+			t.Fatalf("could not copy a decoded message: %v", err)
+		}
+		if !msg.Message.Compare(cp.Message) {
+			t.Fatal("copied message is not equal to the original")
+		}
+		_, _ = msg.VerifySig(pk)
+
+		changeRoundData := &ChangeRoundData{}
+		if err := json.Unmarshal(msg.Message.Value, changeRoundData); err == nil {
+			_, _ = changeRoundData.VerifySig(*pk)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package replay
+
+import (
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// recordingNetwork keeps the messages that the replayed instance broadcasts instead of sending them
+type recordingNetwork struct {
+	*local.Local
+
+	lock        sync.Mutex
+	broadcasted []string
+}
+
+func newRecordingNetwork() *recordingNetwork {
+	return &recordingNetwork{Local: local.NewLocalNetwork()}
+}
+
+// Broadcast records the given message
+func (n *recordingNetwork) Broadcast(topicName []byte, msg *proto.SignedMessage) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.broadcasted = append(n.broadcasted, describe(msg))
+	return nil
+}
+
+// flush returns and clears the recorded messages
+func (n *recordingNetwork) flush() []string {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	res := n.broadcasted
+	n.broadcasted = nil
+	return res
+}
+
+// nopSigner returns empty signatures, as the share key of the operator is not available when replaying.
+// messages of the operator itself are expected to be part of the recorded stream
+type nopSigner struct{}
+
+// SignIBFTMessage returns an empty signature
+func (s *nopSigner) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte, error) {
+	return []byte{}, nil
+}
+
+// SignAttestation is not supported
+func (s *nopSigner) SignAttestation(data *spec.AttestationData, duty *beacon.Duty, pk []byte) (*spec.Attestation, []byte, error) {
+	return nil, nil, errors.New("not supported")
+}
+
+// SignVoluntaryExit is not supported
+func (s *nopSigner) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return nil, nil, errors.New("not supported")
+}
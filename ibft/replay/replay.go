@@ -0,0 +1,200 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	instance "github.com/bloxapp/ssv/ibft/instance"
+	v0 "github.com/bloxapp/ssv/ibft/instance/forks/v0"
+	"github.com/bloxapp/ssv/ibft/leader/deterministic"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/ibft/valcheck"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// Options defines the instance to replay
+type Options struct {
+	Logger *zap.Logger
+	// Share is the share of the operator that recorded the messages
+	Share *storage.Share
+	// Identifier is the lambda of the instance, i.e. format.IdentifierFormat(pk, role)
+	Identifier []byte
+	SeqNumber  uint64
+	// InputValue is the value the operator started the instance with, required only if it was a leader
+	InputValue []byte
+	// ValueCheck is applied on proposed values, all values are accepted if nil as the duty is not recorded
+	ValueCheck valcheck.ValueCheck
+	// Config defaults to proto.DefaultConsensusParams()
+	Config *proto.InstanceConfig
+	// InferTimeouts triggers round timeouts before a change round message of this operator to a higher round,
+	// as local round timeouts are not recorded
+	InferTimeouts bool
+}
+
+// Step is the state of the instance after a single event was replayed
+type Step struct {
+	// Record is the index of the record in the stream
+	Record        int    `json:"record"`
+	Time          int64  `json:"time,omitempty"`
+	Event         string `json:"event"`
+	Error         string `json:"error,omitempty"`
+	Round         uint64 `json:"round"`
+	Stage         string `json:"stage"`
+	PreparedRound uint64 `json:"preparedRound"`
+	PreparedValue []byte `json:"preparedValue,omitempty"`
+	// Broadcasted are the messages that the instance broadcasted as a result of the event
+	Broadcasted []string `json:"broadcasted,omitempty"`
+}
+
+// Result holds the state transitions of a replayed instance
+type Result struct {
+	Steps []Step `json:"steps"`
+	// Skipped is the number of records that belong to other instances
+	Skipped int                  `json:"skipped"`
+	Decided *proto.SignedMessage `json:"decided,omitempty"`
+}
+
+// Replay processes the given records in order by a fresh instance, events are processed sequentially
+// w/o timers or networking so the state transitions are deterministic
+func Replay(opts Options, records []*msgqueue.DumpRecord) (*Result, error) {
+	if opts.Share == nil {
+		return nil, errors.New("missing share")
+	}
+	if len(opts.Identifier) == 0 {
+		return nil, errors.New("missing identifier")
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+	if opts.ValueCheck == nil {
+		opts.ValueCheck = acceptAll{}
+	}
+	if opts.Config == nil {
+		opts.Config = proto.DefaultConsensusParams()
+	}
+	leaderSelectionSeed := append(append([]byte{}, opts.Identifier...), []byte(strconv.FormatUint(opts.SeqNumber, 10))...)
+	leaderSelector, err := deterministic.New(leaderSelectionSeed, uint64(opts.Share.CommitteeSize()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create leader selector")
+	}
+	net := newRecordingNetwork()
+	inst := instance.NewInstance(&instance.InstanceOptions{
+		Logger:         opts.Logger,
+		ValidatorShare: opts.Share,
+		Network:        net,
+		Queue:          msgqueue.New(),
+		ValueCheck:     opts.ValueCheck,
+		LeaderSelector: leaderSelector,
+		Config:         opts.Config,
+		Lambda:         opts.Identifier,
+		SeqNumber:      opts.SeqNumber,
+		Fork:           v0.New(),
+		Signer:         &nopSigner{},
+	}).(*instance.Instance)
+	if len(opts.InputValue) > 0 {
+		inst.State().InputValue.Set(opts.InputValue)
+	}
+
+	r := &replayer{opts: opts, instance: inst, net: net, res: &Result{}}
+	for i, record := range records {
+		r.replay(i, record)
+	}
+	if decided, err := inst.CommittedAggregatedMsg(); err == nil {
+		r.res.Decided = decided
+	}
+	return r.res, nil
+}
+
+type replayer struct {
+	opts     Options
+	instance *instance.Instance
+	net      *recordingNetwork
+	res      *Result
+}
+
+func (r *replayer) replay(index int, record *msgqueue.DumpRecord) {
+	msg := record.Message.SignedMessage
+	if !r.belongs(record.Message) {
+		r.res.Skipped++
+		return
+	}
+	if record.Message.Type == network.NetworkMsg_DecidedType {
+		err := r.instance.DecidedMsgPipeline().Run(msg)
+		r.step(index, record.Time, "decided "+describe(msg), err)
+		return
+	}
+	if r.opts.InferTimeouts && msg.Message.Type == proto.RoundState_ChangeRound && r.isOwn(msg) {
+		for r.instance.State().Round.Get() < msg.Message.Round {
+			r.instance.TriggerRoundTimeout()
+			r.step(index, record.Time, "inferred round timeout", nil)
+		}
+	}
+	r.instance.MsgQueue.AddMessage(&network.Message{
+		SignedMessage: msg,
+		Type:          network.NetworkMsg_IBFTType,
+	})
+	for {
+		processed, err := r.instance.ProcessMessage()
+		if !processed {
+			break
+		}
+		r.step(index, record.Time, describe(msg), err)
+	}
+}
+
+// belongs returns true if the given message is a consensus message of the replayed instance
+func (r *replayer) belongs(msg *network.Message) bool {
+	if msg.Type != network.NetworkMsg_IBFTType && msg.Type != network.NetworkMsg_DecidedType {
+		return false
+	}
+	signed := msg.SignedMessage
+	if signed == nil || signed.Message == nil {
+		return false
+	}
+	return signed.Message.SeqNumber == r.opts.SeqNumber && bytes.Equal(signed.Message.Lambda, r.opts.Identifier)
+}
+
+func (r *replayer) isOwn(msg *proto.SignedMessage) bool {
+	for _, id := range msg.SignerIds {
+		if id == r.opts.Share.NodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// step adds the current state of the instance to the result
+func (r *replayer) step(index int, ts int64, event string, err error) {
+	state := r.instance.State()
+	s := Step{
+		Record:        index,
+		Time:          ts,
+		Event:         event,
+		Round:         state.Round.Get(),
+		Stage:         proto.RoundState(state.Stage.Get()).String(),
+		PreparedRound: state.PreparedRound.Get(),
+		PreparedValue: state.PreparedValue.Get(),
+		Broadcasted:   r.net.flush(),
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	r.res.Steps = append(r.res.Steps, s)
+}
+
+// describe returns a short description of the given message
+func describe(msg *proto.SignedMessage) string {
+	return fmt.Sprintf("%s round %d from %v", msg.Message.Type.String(), msg.Message.Round, msg.SignerIds)
+}
+
+// acceptAll is a value check that accepts all values
+type acceptAll struct{}
+
+// Check implements valcheck.ValueCheck
+func (acceptAll) Check(value []byte) error {
+	return nil
+}
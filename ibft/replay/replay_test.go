@@ -0,0 +1,89 @@
+package replay
+
+import (
+	"github.com/bloxapp/ssv/ibft/instance/spectesting"
+	"github.com/bloxapp/ssv/ibft/leader/deterministic"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func toRecords(msgs ...*proto.SignedMessage) []*msgqueue.DumpRecord {
+	records := make([]*msgqueue.DumpRecord, 0, len(msgs))
+	for i, msg := range msgs {
+		records = append(records, &msgqueue.DumpRecord{
+			Time:    int64(i),
+			Message: &network.Message{SignedMessage: msg, Type: network.NetworkMsg_IBFTType},
+		})
+	}
+	return records
+}
+
+func TestReplay(t *testing.T) {
+	shares, _ := spectesting.TestSharesAndSigner()
+	sks := spectesting.TestSKs()
+	identifier := []byte("01020304_ATTESTER")
+	seq := uint64(3)
+	value := spectesting.TestInputValue()
+
+	leaderSelector, err := deterministic.New(append(identifier, []byte(strconv.FormatUint(seq, 10))...), 4)
+	require.NoError(t, err)
+	leader := leaderSelector.Calculate(1) + 1
+
+	withSeq := func(msg *proto.SignedMessage, seq uint64) *proto.SignedMessage {
+		msg.Message.SeqNumber = seq
+		return spectesting.SignMsg(t, msg.SignerIds[0], sks[msg.SignerIds[0]-1], msg.Message)
+	}
+
+	t.Run("decided", func(t *testing.T) {
+		var msgs []*proto.SignedMessage
+		msgs = append(msgs, withSeq(spectesting.PrePrepareMsg(t, sks[leader-1], identifier, value, 1, leader), seq))
+		// a message of another instance
+		msgs = append(msgs, withSeq(spectesting.PrepareMsg(t, sks[0], identifier, value, 1, 1), seq+1))
+		for id := uint64(1); id <= 3; id++ {
+			msgs = append(msgs, withSeq(spectesting.PrepareMsg(t, sks[id-1], identifier, value, 1, id), seq))
+		}
+		for id := uint64(1); id <= 3; id++ {
+			msgs = append(msgs, withSeq(spectesting.CommitMsg(t, sks[id-1], identifier, value, 1, id), seq))
+		}
+
+		res, err := Replay(Options{Share: shares[1], Identifier: identifier, SeqNumber: seq}, toRecords(msgs...))
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Skipped)
+		require.Len(t, res.Steps, 7)
+		for _, step := range res.Steps {
+			require.Empty(t, step.Error)
+		}
+		require.Equal(t, proto.RoundState_PrePrepare.String(), res.Steps[0].Stage)
+		require.Equal(t, proto.RoundState_Prepare.String(), res.Steps[3].Stage)
+		require.Equal(t, proto.RoundState_Decided.String(), res.Steps[6].Stage)
+		require.NotNil(t, res.Decided)
+		require.Equal(t, value, res.Decided.Message.Value)
+
+		// the replay is deterministic
+		again, err := Replay(Options{Share: shares[1], Identifier: identifier, SeqNumber: seq}, toRecords(msgs...))
+		require.NoError(t, err)
+		require.Equal(t, res.Steps, again.Steps)
+	})
+
+	t.Run("inferred timeouts", func(t *testing.T) {
+		own := withSeq(spectesting.ChangeRoundMsg(t, sks[0], identifier, 2, 1), seq)
+		opts := Options{Share: shares[1], Identifier: identifier, SeqNumber: seq, InferTimeouts: true}
+		res, err := Replay(opts, toRecords(own))
+		require.NoError(t, err)
+		require.Len(t, res.Steps, 2)
+		require.Equal(t, "inferred round timeout", res.Steps[0].Event)
+		require.EqualValues(t, 2, res.Steps[0].Round)
+		require.Len(t, res.Steps[0].Broadcasted, 1)
+		require.Equal(t, proto.RoundState_ChangeRound.String(), res.Steps[1].Stage)
+
+		opts.InferTimeouts = false
+		res, err = Replay(opts, toRecords(own))
+		require.NoError(t, err)
+		require.Len(t, res.Steps, 1)
+		require.EqualValues(t, 1, res.Steps[0].Round)
+	})
+}
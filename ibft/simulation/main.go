@@ -79,6 +79,10 @@ func (s *testSigner) SignAttestation(data *spec.AttestationData, duty *beacon.Du
 	return nil, nil, nil
 }
 
+func (s *testSigner) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	return nil, nil, nil
+}
+
 func db() collections.Iibft {
 	db, err := storage.GetStorageFactory(basedb.Options{
 		Type:   "badger-memory",
@@ -164,6 +168,7 @@ func main() {
 			shares[i],
 			v0.New(),
 			newTestSigner(),
+			0,
 		)
 		nodes = append(nodes, node)
 	}
@@ -96,10 +96,7 @@ func (r *farFutureSync) startNode(node ibft.Controller, index uint64, seqNumber
 		r.logger.Info("decided with value", zap.String("decided value", string(res.Msg.Message.Value)))
 	}
 
-	if err := r.dbs[index-1].SaveDecided(res.Msg); err != nil {
-		r.logger.Error("could not save decided msg", zap.Uint64("node_id", index), zap.Error(err))
-	}
-	if err := r.dbs[index-1].SaveHighestDecidedInstance(res.Msg); err != nil {
+	if err := r.dbs[index-1].SaveDecidedAndHighest(res.Msg); err != nil {
 		r.logger.Error("could not save decided msg", zap.Uint64("node_id", index), zap.Error(err))
 	}
 }
@@ -0,0 +1,80 @@
+package history
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Checkpoint is a trusted decided message, identified by its sequence number and signing root.
+// a synced history must include the checkpoint, so peers can't feed a node with a fake decided chain
+type Checkpoint struct {
+	SeqNumber uint64
+	Root      []byte
+}
+
+// verify returns an error if the given message has the sequence number of the checkpoint, but a different root
+func (cp *Checkpoint) verify(msg *proto.SignedMessage) error {
+	if msg.Message.SeqNumber != cp.SeqNumber {
+		return nil
+	}
+	root, err := msg.Message.SigningRoot()
+	if err != nil {
+		return errors.Wrap(err, "could not get signing root")
+	}
+	if !bytes.Equal(root, cp.Root) {
+		return errors.Errorf("decided message does not match trusted checkpoint: seq=%d, root=%s, expected=%s",
+			cp.SeqNumber, hex.EncodeToString(root), hex.EncodeToString(cp.Root))
+	}
+	return nil
+}
+
+// WithCheckpoint sets a trusted checkpoint to verify the history against
+func (s *Sync) WithCheckpoint(cp *Checkpoint) *Sync {
+	s.checkpoint = cp
+	return s
+}
+
+// verifyCheckpoint verifies the local and remote (of the given peer) history against the checkpoint,
+// the checkpoint message is saved so the rest of the history is synced backwards and forwards from it
+func (s *Sync) verifyCheckpoint(fromPeer string, remoteHighest *proto.SignedMessage) error {
+	cp := s.checkpoint
+	if remoteHighest.Message.SeqNumber < cp.SeqNumber {
+		return errors.Errorf("remote highest decided (%d) is lower than trusted checkpoint (%d)",
+			remoteHighest.Message.SeqNumber, cp.SeqNumber)
+	}
+	local, found, err := s.ibftStorage.GetDecided(s.identifier, cp.SeqNumber)
+	if err != nil {
+		return errors.Wrap(err, "could not get local checkpoint decided")
+	}
+	if found {
+		return errors.Wrap(cp.verify(local), "local history")
+	}
+	res, err := s.network.GetDecidedByRange(fromPeer, &network.SyncMessage{
+		Lambda: s.identifier,
+		Params: []uint64{cp.SeqNumber, cp.SeqNumber},
+		Type:   network.Sync_GetInstanceRange,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not fetch checkpoint decided")
+	}
+	if len(res.SignedMessages) == 0 || res.SignedMessages[0].Message.SeqNumber != cp.SeqNumber {
+		return errors.New("peer did not return checkpoint decided")
+	}
+	msg := res.SignedMessages[0]
+	if err := s.validateDecidedMsgF(msg); err != nil {
+		return errors.Wrap(err, "invalid checkpoint decided")
+	}
+	if err := cp.verify(msg); err != nil {
+		s.logger.Warn("peer history conflicts with trusted checkpoint", zap.String("peer", fromPeer), zap.Error(err))
+		return err
+	}
+	if err := s.ibftStorage.SaveDecided(msg); err != nil {
+		return errors.Wrap(err, "could not save checkpoint decided")
+	}
+	s.logger.Info("verified trusted checkpoint", zap.Uint64("seq", cp.SeqNumber), zap.String("peer", fromPeer))
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/storage/collections"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"time"
 )
 
 // FetchValidateAndSaveInstances fetches, validates and saves decided messages from the P2P network.
@@ -16,6 +18,9 @@ func (s *Sync) fetchValidateAndSaveInstances(fromPeer string, startSeq uint64, e
 	done := false
 	var latestError error
 	for {
+		if err := s.ctx.Err(); err != nil {
+			return highestSaved, errors.Wrap(err, "sync was canceled")
+		}
 		if failCount == 5 {
 			return highestSaved, latestError
 		}
@@ -30,15 +35,28 @@ func (s *Sync) fetchValidateAndSaveInstances(fromPeer string, startSeq uint64, e
 		}
 
 		res, err := s.network.GetDecidedByRange(fromPeer, &network.SyncMessage{
-			Lambda: s.identifier,
-			Params: []uint64{start, batchMaxSeq},
-			Type:   network.Sync_GetInstanceRange,
+			Lambda:   s.identifier,
+			Params:   []uint64{start, batchMaxSeq},
+			MaxBatch: s.paginationMaxSize,
+			Type:     network.Sync_GetInstanceRange,
 		})
 		if err != nil {
 			failCount++
 			latestError = err
 			continue
 		}
+		// the peer might have a smaller max batch, in that case only a page of the batch is returned
+		// and the rest is fetched in the next iterations
+		if res.MaxBatch > 0 && res.MaxBatch < s.paginationMaxSize {
+			s.paginationMaxSize = res.MaxBatch
+		}
+		batchMaxSeq = pageEnd(res, start, batchMaxSeq)
+		provenance, err := newProvenance(fromPeer, res)
+		if err != nil {
+			failCount++
+			latestError = err
+			continue
+		}
 
 		// organize signed msgs into a map where the key is the sequence number
 		// This is for verifying all expected sequence numbers where returned from peer
@@ -63,15 +81,25 @@ func (s *Sync) fetchValidateAndSaveInstances(fromPeer string, startSeq uint64, e
 			// counting all the messages that were visited
 			msgCount--
 			// if msg is invalid, break and try again with an updated start seq
-			if s.validateDecidedMsgF(msg) != nil {
+			if err := s.validateDecidedMsgF(msg); err != nil {
+				network.ReportRejectedMessage(err)
+				s.reportBadPeer(fromPeer, "invalid_decided")
 				start = msg.Message.SeqNumber
 				continue
 			}
+			if s.checkpoint != nil {
+				if err := s.checkpoint.verify(msg); err != nil {
+					return highestSaved, err
+				}
+			}
 
 			// save
 			if err := s.ibftStorage.SaveDecided(msg); err != nil {
 				return highestSaved, err
 			}
+			if err := s.ibftStorage.SaveDecidedProvenance(msg.Message.Lambda, msg.Message.SeqNumber, provenance); err != nil {
+				s.logger.Warn("could not save decided provenance", zap.Error(err))
+			}
 
 			// set highest
 			if highestSaved == nil || highestSaved.Message.SeqNumber < msg.Message.SeqNumber {
@@ -90,3 +118,40 @@ func (s *Sync) fetchValidateAndSaveInstances(fromPeer string, startSeq uint64, e
 		}
 	}
 }
+
+// newProvenance creates the provenance of the decided messages in the given response,
+// the signing root and signature of the response allow to trace corrupted messages to the peer that served them.
+// signatures of responses are verified by the network, therefore only signed responses are recorded as verified
+func newProvenance(fromPeer string, res *network.SyncMessage) (*collections.DecidedProvenance, error) {
+	root, err := res.SigningRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get response root")
+	}
+	return &collections.DecidedProvenance{
+		PeerID:    fromPeer,
+		Root:      root,
+		Signature: res.Signature,
+		Verified:  len(res.Signature) > 0,
+		SyncedAt:  time.Now().Unix(),
+	}, nil
+}
+
+// reportBadPeer penalizes the given peer if the network supports it
+func (s *Sync) reportBadPeer(peerStr string, reason string) {
+	if reporter, ok := s.network.(network.PeersReporter); ok {
+		reporter.ReportBadPeer(peerStr, reason)
+	}
+}
+
+// pageEnd returns the last sequence of the returned page,
+// responses w/o a page token (or of peers w/o paging support) are expected to cover the whole batch
+func pageEnd(res *network.SyncMessage, start, batchMaxSeq uint64) uint64 {
+	if len(res.PageToken) == 0 {
+		return batchMaxSeq
+	}
+	next, err := network.DecodePageToken(res.PageToken)
+	if err != nil || next <= start || next > batchMaxSeq {
+		return batchMaxSeq
+	}
+	return next - 1
+}
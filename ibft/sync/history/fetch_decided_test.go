@@ -3,6 +3,7 @@ package history
 import (
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/ibft/sync"
+	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/collections"
 	"github.com/bloxapp/ssv/storage/kv"
@@ -132,3 +133,91 @@ func TestFetchDecided(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchDecided_Paging(t *testing.T) {
+	sks, _ := sync.GenerateNodes(4)
+	logger := zap.L()
+	db, err := kv.New(basedb.Options{
+		Type:   "badger-memory",
+		Path:   "",
+		Logger: logger,
+	})
+	require.NoError(t, err)
+	storage := collections.NewIbft(db, logger, "attestation")
+	decidedArr := map[string][]*proto.SignedMessage{
+		"2": sync.DecidedArr(t, 20, sks, []byte("lambda")),
+	}
+	// the peer returns pages of up to 3 decided while the local max batch is 10
+	network := sync.NewTestNetwork(t, []string{"2"}, 10, nil, nil, decidedArr, nil, nil).WithPeerMaxBatch(2)
+	s := New(logger, []byte{1, 2, 3, 4}, []byte("lambda"), network, &storage, func(msg *proto.SignedMessage) error {
+		return nil
+	})
+
+	res, err := s.fetchValidateAndSaveInstances("2", 1, 20)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, res.Message.SeqNumber)
+	require.EqualValues(t, 2, s.paginationMaxSize)
+	for seq := uint64(1); seq <= 20; seq++ {
+		_, found, err := storage.GetDecided([]byte("lambda"), seq)
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+}
+
+func TestFetchDecided_Provenance(t *testing.T) {
+	sks, _ := sync.GenerateNodes(4)
+	logger := zap.L()
+	db, err := kv.New(basedb.Options{
+		Type:   "badger-memory",
+		Path:   "",
+		Logger: logger,
+	})
+	require.NoError(t, err)
+	storage := collections.NewIbft(db, logger, "attestation")
+	decidedArr := map[string][]*proto.SignedMessage{
+		"2": sync.DecidedArr(t, 5, sks, []byte("lambda")),
+	}
+	network := sync.NewTestNetwork(t, []string{"2"}, 10, nil, nil, decidedArr, nil, nil)
+	// seq 3 is invalid once
+	failed := false
+	s := New(logger, []byte{1, 2, 3, 4}, []byte("lambda"), network, &storage, func(msg *proto.SignedMessage) error {
+		if msg.Message.SeqNumber == 3 && !failed {
+			failed = true
+			return errors.New("invalid decided")
+		}
+		return nil
+	})
+
+	res, err := s.fetchValidateAndSaveInstances("2", 1, 5)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, res.Message.SeqNumber)
+	require.EqualValues(t, 1, network.BadPeerReports("2"))
+	for seq := uint64(1); seq <= 5; seq++ {
+		_, saved, err := storage.GetDecided([]byte("lambda"), seq)
+		require.NoError(t, err)
+		p, found, err := storage.GetDecidedProvenance([]byte("lambda"), seq)
+		require.NoError(t, err)
+		require.Equal(t, saved, found)
+		if !found {
+			continue
+		}
+		require.EqualValues(t, "2", p.PeerID)
+		require.Len(t, p.Root, 32)
+		// the test network doesn't sign responses
+		require.False(t, p.Verified)
+	}
+}
+
+func TestNewProvenance(t *testing.T) {
+	res := &network.SyncMessage{FromPeerID: "2", Lambda: []byte("lambda"), Type: network.Sync_GetInstanceRange}
+	p, err := newProvenance("2", res)
+	require.NoError(t, err)
+	require.False(t, p.Verified)
+	require.Empty(t, p.Signature)
+
+	res.Signature = []byte{1, 2, 3}
+	p, err = newProvenance("2", res)
+	require.NoError(t, err)
+	require.True(t, p.Verified)
+	require.Equal(t, []byte{1, 2, 3}, p.Signature)
+}
@@ -6,6 +6,7 @@ import (
 	sync2 "github.com/bloxapp/ssv/ibft/sync"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/storage/kv"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"sync"
@@ -15,7 +16,7 @@ import (
 func (s *Sync) findHighestInstance() (*proto.SignedMessage, string, error) {
 	// pick up to 4 peers
 	// TODO - why 4? should be set as param?
-	usedPeers, err := sync2.GetPeers(s.network, s.publicKey, 4)
+	usedPeers, err := sync2.GetPeers(s.network, s.publicKey, 4, s.committee...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -26,7 +27,7 @@ func (s *Sync) findHighestInstance() (*proto.SignedMessage, string, error) {
 	if len(results) == 0 {
 		s.logger.Debug("could not fetch highest decided from peers",
 			zap.String("identifier", hex.EncodeToString(s.identifier)))
-		return nil, "", errors.New("could not fetch highest decided from peers")
+		return nil, "", errs.Mark(errors.New("could not fetch highest decided from peers"), errs.ErrNoPeers)
 	}
 
 	// find the highest decided within the incoming messages
@@ -44,6 +45,10 @@ func (s *Sync) findHighestInstance() (*proto.SignedMessage, string, error) {
 		if ret.Message.SeqNumber < res.SignedMessages[0].Message.SeqNumber {
 			ret = res.SignedMessages[0]
 			fromPeer = res.FromPeerID
+		} else if ret.Message.SeqNumber == res.SignedMessages[0].Message.SeqNumber &&
+			sync2.PeerLatency(s.network, res.FromPeerID) < sync2.PeerLatency(s.network, fromPeer) {
+			// prefer a faster peer for fetching the history
+			fromPeer = res.FromPeerID
 		}
 	}
 
@@ -1,6 +1,7 @@
 package history
 
 import (
+	"context"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/storage/collections"
@@ -12,6 +13,8 @@ import (
 // Sync is responsible for syncing and iBFT instance when needed by
 // fetching decided messages from the network
 type Sync struct {
+	// ctx cancels the sync between network calls, once the sync is no longer needed or its deadline has passed
+	ctx                 context.Context
 	logger              *zap.Logger
 	publicKey           []byte
 	network             network.Network
@@ -20,11 +23,16 @@ type Sync struct {
 	identifier          []byte
 	// paginationMaxSize is the max number of returned elements in a single response
 	paginationMaxSize uint64
+	// checkpoint is optional, used to verify the history against a trusted decided message
+	checkpoint *Checkpoint
+	// committee is optional, the public keys of the committee operators whose peers are preferred
+	committee []string
 }
 
 // New returns a new instance of Sync
 func New(logger *zap.Logger, publicKey []byte, identifier []byte, network network.Network, ibftStorage collections.Iibft, validateDecidedMsgF func(msg *proto.SignedMessage) error) *Sync {
 	return &Sync{
+		ctx:                 context.Background(),
 		logger:              logger.With(zap.String("sync", "history")),
 		publicKey:           publicKey,
 		identifier:          identifier,
@@ -35,6 +43,25 @@ func New(logger *zap.Logger, publicKey []byte, identifier []byte, network networ
 	}
 }
 
+// WithContext sets the context of the sync
+func (s *Sync) WithContext(ctx context.Context) *Sync {
+	s.ctx = ctx
+	return s
+}
+
+// WithCommittee sets the public keys of the committee operators of the validator,
+// their peers are preferred as they are likely to have the complete history
+func (s *Sync) WithCommittee(operators []string) *Sync {
+	s.committee = operators
+	return s
+}
+
+// FindHighest returns the highest decided message of the peers, nil if peers have no history
+func (s *Sync) FindHighest() (*proto.SignedMessage, error) {
+	highest, _, err := s.findHighestInstance()
+	return highest, err
+}
+
 // Start the sync
 func (s *Sync) Start() error {
 	start := time.Now()
@@ -48,6 +75,10 @@ func (s *Sync) Start() error {
 		return nil
 	}
 
+	if err := s.ctx.Err(); err != nil {
+		return errors.Wrap(err, "sync was canceled")
+	}
+
 	// fetch local highest
 	localHighest, found, err := s.ibftStorage.GetHighestDecidedInstance(s.identifier)
 	if err != nil { // if not found, don't continue with sync
@@ -59,6 +90,12 @@ func (s *Sync) Start() error {
 		syncStartSeqNumber = localHighest.Message.SeqNumber
 	}
 
+	if s.checkpoint != nil && syncStartSeqNumber <= s.checkpoint.SeqNumber {
+		if err := s.verifyCheckpoint(fromPeer, remoteHighest); err != nil {
+			return errors.Wrap(err, "could not verify trusted checkpoint")
+		}
+	}
+
 	specialStartupCase := !found && remoteHighest.Message.SeqNumber == 0 // in case when remote return seqNum of 0 and local is empty (notFound) we need to save and not assumed to be synced
 	if !specialStartupCase {
 		// check we are behind and need to sync
@@ -1,8 +1,10 @@
 package history
 
 import (
+	"context"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/ibft/sync"
+	"github.com/bloxapp/ssv/storage/collections"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"testing"
@@ -211,3 +213,68 @@ func TestSync(t *testing.T) {
 		})
 	}
 }
+
+func TestSync_Checkpoint(t *testing.T) {
+	sks, _ := sync.GenerateNodes(4)
+	decided := sync.DecidedArr(t, 10, sks, []byte("lambda"))
+	root, err := decided[5].Message.SigningRoot()
+	require.NoError(t, err)
+	fake := sync.MultiSignMsg(t, []uint64{1, 2, 3}, sks, &proto.Message{
+		Type:      proto.RoundState_Decided,
+		Round:     2,
+		Lambda:    []byte("lambda"),
+		SeqNumber: 5,
+	})
+	fakeHistory := append(append(append([]*proto.SignedMessage{}, decided[:5]...), fake), decided[6:]...)
+
+	newSync := func(peerHistory []*proto.SignedMessage, cp *Checkpoint) (*Sync, *collections.IbftStorage) {
+		storage := sync.TestingIbftStorage(t)
+		network := sync.NewTestNetwork(t, []string{"2"}, 100,
+			map[string]*proto.SignedMessage{"2": peerHistory[len(peerHistory)-1]}, nil,
+			map[string][]*proto.SignedMessage{"2": peerHistory}, nil, nil)
+		s := New(zap.L(), []byte{1, 2, 3, 4}, []byte("lambda"), network, &storage, func(msg *proto.SignedMessage) error {
+			return nil
+		}).WithCheckpoint(cp)
+		return s, &storage
+	}
+
+	t.Run("history matches checkpoint", func(t *testing.T) {
+		s, storage := newSync(decided, &Checkpoint{SeqNumber: 5, Root: root})
+		require.NoError(t, s.Start())
+		highest, found, err := storage.GetHighestDecidedInstance([]byte("lambda"))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.EqualValues(t, 10, highest.Message.SeqNumber)
+	})
+
+	t.Run("fake history", func(t *testing.T) {
+		s, storage := newSync(fakeHistory, &Checkpoint{SeqNumber: 5, Root: root})
+		require.Error(t, s.Start())
+		_, found, err := storage.GetDecided([]byte("lambda"), 0)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("remote highest lower than checkpoint", func(t *testing.T) {
+		s, _ := newSync(decided[:3], &Checkpoint{SeqNumber: 5, Root: root})
+		require.EqualError(t, s.Start(), "could not verify trusted checkpoint: remote highest decided (2) is lower than trusted checkpoint (5)")
+	})
+}
+
+func TestSync_Canceled(t *testing.T) {
+	sks, _ := sync.GenerateNodes(4)
+	decided := sync.DecidedArr(t, 10, sks, []byte("lambda"))
+	storage := sync.TestingIbftStorage(t)
+	network := sync.NewTestNetwork(t, []string{"2"}, 100,
+		map[string]*proto.SignedMessage{"2": decided[len(decided)-1]}, nil,
+		map[string][]*proto.SignedMessage{"2": decided}, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := New(zap.L(), []byte{1, 2, 3, 4}, []byte("lambda"), network, &storage, func(msg *proto.SignedMessage) error {
+		return nil
+	}).WithContext(ctx)
+	require.EqualError(t, s.Start(), "sync was canceled: context canceled")
+	_, found, err := storage.GetDecided([]byte("lambda"), 0)
+	require.NoError(t, err)
+	require.False(t, found)
+}
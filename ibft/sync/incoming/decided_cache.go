@@ -0,0 +1,51 @@
+package incoming
+
+import (
+	"fmt"
+	"github.com/bloxapp/ssv/ibft/proto"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// DefaultDecidedCacheSize is the default max number of decided ranges in the cache
+const DefaultDecidedCacheSize = 32
+
+// DecidedCache is a bounded LRU cache of recently served decided ranges, keyed by (identifier, from, to).
+// sync requests for the same ranges arrive from many lagging peers, therefore popular ranges are served from memory
+type DecidedCache struct {
+	cache *lru.Cache
+}
+
+// NewDecidedCache creates a new cache with the given size
+func NewDecidedCache(size int) *DecidedCache {
+	if size <= 0 {
+		size = DefaultDecidedCacheSize
+	}
+	// an error is returned only for non-positive sizes
+	cache, _ := lru.New(size)
+	return &DecidedCache{cache: cache}
+}
+
+// get returns the cached decided messages of the given range
+func (c *DecidedCache) get(identifier []byte, from, to uint64) ([]*proto.SignedMessage, bool) {
+	if c == nil {
+		return nil, false
+	}
+	cached, ok := c.cache.Get(decidedCacheKey(identifier, from, to))
+	if !ok {
+		return nil, false
+	}
+	return cached.([]*proto.SignedMessage), true
+}
+
+// add caches the decided messages of the given range,
+// only complete ranges are cached as missing sequences might be saved later
+func (c *DecidedCache) add(identifier []byte, from, to uint64, msgs []*proto.SignedMessage) {
+	if c == nil || uint64(len(msgs)) != to-from+1 {
+		return
+	}
+	c.cache.Add(decidedCacheKey(identifier, from, to), msgs)
+}
+
+func decidedCacheKey(identifier []byte, from, to uint64) string {
+	return fmt.Sprintf("%x/%d/%d", identifier, from, to)
+}
@@ -17,18 +17,22 @@ func (s *ReqHandler) handleGetDecidedReq(msg *network.SyncChanObj) {
 	if err := s.validateGetDecidedReq(msg); err != nil {
 		retMsg.Error = errors.Wrap(err, "invalid get decided request").Error()
 	} else {
-		// enforce max page size
 		startSeq := msg.Msg.Params[0]
 		endSeq := msg.Msg.Params[1]
-		if endSeq-startSeq > s.paginationMaxSize {
-			endSeq = startSeq + s.paginationMaxSize
+		if len(msg.Msg.PageToken) > 0 {
+			// the token was validated in validateGetDecidedReq
+			startSeq, _ = network.DecodePageToken(msg.Msg.PageToken)
 		}
-
-		ret, err := GetDecidedInRange(s.identifier, startSeq, endSeq, s.logger, s.storage)
-		if err != nil {
-			ret = make([]*proto.SignedMessage, 0)
+		// enforce max page size, the rest of the range can be fetched with the returned page token
+		batch := s.negotiateMaxBatch(msg.Msg.MaxBatch)
+		if endSeq-startSeq > batch {
+			endSeq = startSeq + batch
+			retMsg.PageToken = network.EncodePageToken(endSeq + 1)
 		}
-		retMsg.SignedMessages = ret
+		retMsg.Params = []uint64{startSeq, endSeq}
+		retMsg.MaxBatch = batch
+
+		retMsg.SignedMessages = s.getDecidedInRange(startSeq, endSeq)
 	}
 
 	if err := s.network.RespondToGetDecidedByRange(msg.Stream, retMsg); err != nil {
@@ -46,9 +50,39 @@ func (s *ReqHandler) validateGetDecidedReq(msg *network.SyncChanObj) error {
 	if msg.Msg.Params[0] > msg.Msg.Params[1] {
 		return errors.New("sync msg invalid: param[0] should be <= param[1]")
 	}
+	if len(msg.Msg.PageToken) > 0 {
+		next, err := network.DecodePageToken(msg.Msg.PageToken)
+		if err != nil {
+			return errors.Wrap(err, "sync msg invalid")
+		}
+		if next < msg.Msg.Params[0] || next > msg.Msg.Params[1] {
+			return errors.New("sync msg invalid: page token is out of range")
+		}
+	}
 	return nil
 }
 
+// negotiateMaxBatch returns the max batch size of a response, the smaller of the local and requested limits
+func (s *ReqHandler) negotiateMaxBatch(requested uint64) uint64 {
+	if requested > 0 && requested < s.paginationMaxSize {
+		return requested
+	}
+	return s.paginationMaxSize
+}
+
+// getDecidedInRange returns decided messages of the given range, served from the cache if possible
+func (s *ReqHandler) getDecidedInRange(startSeq, endSeq uint64) []*proto.SignedMessage {
+	if cached, ok := s.decidedCache.get(s.identifier, startSeq, endSeq); ok {
+		return cached
+	}
+	ret, err := GetDecidedInRange(s.identifier, startSeq, endSeq, s.logger, s.storage)
+	if err != nil {
+		return make([]*proto.SignedMessage, 0)
+	}
+	s.decidedCache.add(s.identifier, startSeq, endSeq, ret)
+	return ret
+}
+
 // GetDecidedInRange returns decided messages of the validator (and role) for the given range
 func GetDecidedInRange(identifier []byte, start, end uint64, logger *zap.Logger, storage collections.Iibft) ([]*proto.SignedMessage, error) {
 	ret := make([]*proto.SignedMessage, 0)
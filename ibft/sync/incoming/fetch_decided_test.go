@@ -175,3 +175,104 @@ func TestTestNetwork_GetDecidedByRange(t *testing.T) {
 		})
 	}
 }
+
+func TestReqHandler_DecidedCache(t *testing.T) {
+	sks, _ := sync.GenerateNodes(4)
+	ibftStorage := sync.TestingIbftStorage(t)
+	for _, d := range sync.DecidedArr(t, 10, sks, []byte("lambda")) {
+		require.NoError(t, ibftStorage.SaveDecided(d))
+	}
+	handler := ReqHandler{
+		identifier:   []byte("lambda"),
+		storage:      &ibftStorage,
+		logger:       zap.L(),
+		decidedCache: NewDecidedCache(10),
+	}
+
+	// incomplete ranges are not cached
+	require.Len(t, handler.getDecidedInRange(5, 15), 6)
+	_, found := handler.decidedCache.get([]byte("lambda"), 5, 15)
+	require.False(t, found)
+
+	require.Len(t, handler.getDecidedInRange(0, 10), 11)
+	cached, found := handler.decidedCache.get([]byte("lambda"), 0, 10)
+	require.True(t, found)
+	require.Len(t, cached, 11)
+
+	// cached ranges are served w/o storage access
+	handler.storage = nil
+	res := handler.getDecidedInRange(0, 10)
+	require.Len(t, res, 11)
+	require.EqualValues(t, 10, res[10].Message.SeqNumber)
+}
+
+func TestReqHandler_GetDecidedPages(t *testing.T) {
+	sks, _ := sync.GenerateNodes(4)
+	ibftStorage := sync.TestingIbftStorage(t)
+	for _, d := range sync.DecidedArr(t, 250, sks, []byte("lambda")) {
+		require.NoError(t, ibftStorage.SaveDecided(d))
+	}
+	handler := ReqHandler{
+		paginationMaxSize: 100,
+		identifier:        []byte("lambda"),
+		network:           sync.NewTestNetwork(t, nil, 100, nil, nil, nil, nil, nil),
+		storage:           &ibftStorage,
+		logger:            zap.L(),
+	}
+	request := func(maxBatch uint64, token []byte) *network.SyncMessage {
+		s := sync.NewTestStream("")
+		go handler.handleGetDecidedReq(&network.SyncChanObj{
+			Msg: &network.SyncMessage{
+				Params:    []uint64{0, 250},
+				Lambda:    []byte("lambda"),
+				MaxBatch:  maxBatch,
+				PageToken: token,
+			},
+			Stream: s,
+		})
+		res := &network.Message{}
+		require.NoError(t, json.Unmarshal(<-s.C, res))
+		return res.SyncMessage
+	}
+
+	t.Run("negotiate smaller max batch", func(t *testing.T) {
+		res := request(50, nil)
+		require.EqualValues(t, 50, res.MaxBatch)
+		require.Equal(t, []uint64{0, 50}, res.Params)
+		require.Len(t, res.SignedMessages, 51)
+		require.Equal(t, network.EncodePageToken(51), res.PageToken)
+	})
+
+	t.Run("local max batch is not exceeded", func(t *testing.T) {
+		res := request(1000, nil)
+		require.EqualValues(t, 100, res.MaxBatch)
+		require.Equal(t, []uint64{0, 100}, res.Params)
+	})
+
+	t.Run("iterate with page tokens", func(t *testing.T) {
+		var token []byte
+		var seqs []uint64
+		for {
+			res := request(100, token)
+			require.Len(t, res.Error, 0)
+			for _, msg := range res.SignedMessages {
+				seqs = append(seqs, msg.Message.SeqNumber)
+			}
+			if len(res.PageToken) == 0 {
+				break
+			}
+			token = res.PageToken
+		}
+		require.Len(t, seqs, 251)
+		for i, seq := range seqs {
+			require.EqualValues(t, i, seq)
+		}
+	})
+
+	t.Run("invalid page token", func(t *testing.T) {
+		res := request(100, network.EncodePageToken(300))
+		require.Equal(t, "invalid get decided request: sync msg invalid: page token is out of range", res.Error)
+		res = request(100, []byte{1})
+		require.Equal(t, "invalid get decided request: sync msg invalid: invalid page token", res.Error)
+	})
+}
@@ -0,0 +1,45 @@
+package incoming
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/storage/kv"
+	"go.uber.org/zap"
+)
+
+// handleGetDecidedHistoryRootReq returns the merkle root over the decided history,
+// params[0] is the number of decided instances that the root covers, the whole history if missing or zero
+func (s *ReqHandler) handleGetDecidedHistoryRootReq(msg *network.SyncChanObj) {
+	historyNet, ok := s.network.(network.DecidedHistoryRoots)
+	if !ok {
+		s.logger.Warn("network does not support decided history roots")
+		return
+	}
+	res := s.getDecidedHistoryRoot(msg.Msg)
+	if err := historyNet.RespondToDecidedHistoryRoot(msg.Stream, res); err != nil {
+		s.logger.Error("failed to send decided history root response", zap.Error(err))
+	}
+}
+
+func (s *ReqHandler) getDecidedHistoryRoot(req *network.SyncMessage) *network.SyncMessage {
+	res := &network.SyncMessage{
+		Lambda: s.identifier,
+		Type:   network.Sync_GetDecidedHistoryRoot,
+	}
+	var count uint64
+	if len(req.Params) > 0 {
+		count = req.Params[0]
+	}
+	root, found, err := s.storage.GetDecidedHistoryRoot(s.identifier, count)
+	if err != nil {
+		s.logger.Error("failed to get decided history root", zap.String("fromPeer", req.FromPeerID), zap.Error(err))
+		res.Error = err.Error()
+		return res
+	}
+	if !found {
+		res.Error = kv.EntryNotFoundError
+		return res
+	}
+	res.Params = []uint64{root.Count}
+	res.HistoryRoot = root.Root
+	return res
+}
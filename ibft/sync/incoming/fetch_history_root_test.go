@@ -0,0 +1,59 @@
+package incoming
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/ibft/sync"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/storage/kv"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestReqHandler_getDecidedHistoryRoot(t *testing.T) {
+	ibftStorage := sync.TestingIbftStorage(t)
+	identifier := []byte{1, 2, 3, 4}
+	handler := ReqHandler{
+		identifier: identifier,
+		storage:    &ibftStorage,
+		logger:     zap.L(),
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		res := handler.getDecidedHistoryRoot(&network.SyncMessage{})
+		require.Equal(t, kv.EntryNotFoundError, res.Error)
+		require.Nil(t, res.HistoryRoot)
+	})
+
+	for seq := uint64(0); seq < 3; seq++ {
+		require.NoError(t, ibftStorage.SaveDecided(&proto.SignedMessage{
+			Message: &proto.Message{
+				Type:      proto.RoundState_Commit,
+				Round:     1,
+				Lambda:    identifier,
+				SeqNumber: seq,
+				Value:     []byte("value"),
+			},
+			Signature: []byte("sig"),
+			SignerIds: []uint64{1, 2, 3},
+		}))
+	}
+
+	t.Run("whole history", func(t *testing.T) {
+		res := handler.getDecidedHistoryRoot(&network.SyncMessage{})
+		require.Empty(t, res.Error)
+		require.Equal(t, []uint64{3}, res.Params)
+		require.Len(t, res.HistoryRoot, 32)
+	})
+
+	t.Run("by count", func(t *testing.T) {
+		res := handler.getDecidedHistoryRoot(&network.SyncMessage{Params: []uint64{2}})
+		require.Empty(t, res.Error)
+		require.Equal(t, []uint64{2}, res.Params)
+		whole := handler.getDecidedHistoryRoot(&network.SyncMessage{})
+		require.NotEqual(t, whole.HistoryRoot, res.HistoryRoot)
+
+		res = handler.getDecidedHistoryRoot(&network.SyncMessage{Params: []uint64{5}})
+		require.Equal(t, kv.EntryNotFoundError, res.Error)
+	})
+}
@@ -18,6 +18,8 @@ type ReqHandler struct {
 	storage            collections.Iibft
 	logger             *zap.Logger
 	lastChangeRoundMsg *proto.SignedMessage
+	// decidedCache keeps recently served decided ranges, nil disables caching
+	decidedCache *DecidedCache
 }
 
 // New returns a new instance of ReqHandler
@@ -28,6 +30,7 @@ func New(
 	network network.Network,
 	storage collections.Iibft,
 	lastChangeRoundMsg *proto.SignedMessage,
+	decidedCache *DecidedCache,
 ) *ReqHandler {
 	return &ReqHandler{
 		paginationMaxSize:  network.MaxBatch(),
@@ -37,6 +40,7 @@ func New(
 		network:            network,
 		storage:            storage,
 		lastChangeRoundMsg: lastChangeRoundMsg,
+		decidedCache:       decidedCache,
 	}
 }
 
@@ -50,6 +54,8 @@ func (s *ReqHandler) Process(msg *network.SyncChanObj) {
 		s.handleGetDecidedReq(msg)
 	case network.Sync_GetLatestChangeRound:
 		s.handleGetLatestChangeRoundReq(msg)
+	case network.Sync_GetDecidedHistoryRoot:
+		s.handleGetDecidedHistoryRootReq(msg)
 	default:
 		s.logger.Error("sync req handler received un-supported type", zap.Uint64("received type", uint64(msg.Msg.Type)))
 	}
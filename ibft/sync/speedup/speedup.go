@@ -20,6 +20,8 @@ type Speedup struct {
 	seqNumber             uint64
 	network               network.Network
 	msgValidationPipeline pipeline.Pipeline
+	// committee is optional, the public keys of the committee operators whose peers are preferred
+	committee []string
 }
 
 // New returns a new Speedup instance
@@ -41,9 +43,15 @@ func New(
 	}
 }
 
+// WithCommittee sets the public keys of the committee operators of the validator, their peers are preferred
+func (s *Speedup) WithCommittee(operators []string) *Speedup {
+	s.committee = operators
+	return s
+}
+
 // Start starts the speedup sync
 func (s *Speedup) Start() ([]*proto.SignedMessage, error) {
-	usedPeers, err := sync2.GetPeers(s.network, s.publicKey, 4)
+	usedPeers, err := sync2.GetPeers(s.network, s.publicKey, 4, s.committee...)
 	if err != nil {
 		return nil, err
 	}
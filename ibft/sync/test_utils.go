@@ -51,7 +51,7 @@ func DecidedArr(t *testing.T, maxSeq uint64, sks map[uint64]*bls.SecretKey, iden
 	ret := make([]*proto.SignedMessage, 0)
 	for i := uint64(0); i <= maxSeq; i++ {
 		ret = append(ret, MultiSignMsg(t, []uint64{1, 2, 3}, sks, &proto.Message{
-			Type:      proto.RoundState_Decided,
+			Type:      proto.RoundState_Commit,
 			Round:     1,
 			Lambda:    identifier[:],
 			SeqNumber: i,
@@ -90,8 +90,12 @@ type TestNetwork struct {
 	decidedArr             map[string][]*proto.SignedMessage
 	lastMsgs               map[string]*proto.SignedMessage
 	maxBatch               int
-	peers                  []string
-	retError               error
+	// peerMaxBatch is the max batch of peers that support paging, 0 means peers w/o paging support
+	peerMaxBatch uint64
+	peers        []string
+	retError     error
+	// badPeers counts the reports of bad peers by peer
+	badPeers map[string]int
 }
 
 // NewTestNetwork returns a new test network instance
@@ -116,6 +120,25 @@ func NewTestNetwork(
 	}
 }
 
+// WithPeerMaxBatch makes peers respond to range requests with pages of the given max batch
+func (n *TestNetwork) WithPeerMaxBatch(maxBatch uint64) *TestNetwork {
+	n.peerMaxBatch = maxBatch
+	return n
+}
+
+// ReportBadPeer impl
+func (n *TestNetwork) ReportBadPeer(peerStr string, reason string) {
+	if n.badPeers == nil {
+		n.badPeers = make(map[string]int)
+	}
+	n.badPeers[peerStr]++
+}
+
+// BadPeerReports returns the number of reports of the given peer
+func (n *TestNetwork) BadPeerReports(peerStr string) int {
+	return n.badPeers[peerStr]
+}
+
 // Broadcast impl
 func (n *TestNetwork) Broadcast(topicName []byte, msg *proto.SignedMessage) error {
 	return nil
@@ -195,6 +218,10 @@ func (n *TestNetwork) GetDecidedByRange(peerStr string, msg *network.SyncMessage
 			return nil, errors.New("could not find highest")
 		}
 
+		if n.peerMaxBatch > 0 {
+			return n.getDecidedPage(peerStr, arr, msg), nil
+		}
+
 		ret := make([]*proto.SignedMessage, 0)
 		for _, m := range arr {
 			if m.Message.SeqNumber >= msg.Params[0] && m.Message.SeqNumber <= msg.Params[1] {
@@ -215,6 +242,32 @@ func (n *TestNetwork) GetDecidedByRange(peerStr string, msg *network.SyncMessage
 	return nil, errors.New("could not find highest")
 }
 
+// getDecidedPage returns a page of the requested range as done by peers that support paging
+func (n *TestNetwork) getDecidedPage(peerStr string, arr []*proto.SignedMessage, msg *network.SyncMessage) *network.SyncMessage {
+	batch := n.peerMaxBatch
+	if msg.MaxBatch > 0 && msg.MaxBatch < batch {
+		batch = msg.MaxBatch
+	}
+	res := &network.SyncMessage{
+		FromPeerID: peerStr,
+		Lambda:     msg.Lambda,
+		Type:       network.Sync_GetInstanceRange,
+		MaxBatch:   batch,
+	}
+	startSeq, endSeq := msg.Params[0], msg.Params[1]
+	if endSeq-startSeq > batch {
+		endSeq = startSeq + batch
+		res.PageToken = network.EncodePageToken(endSeq + 1)
+	}
+	res.Params = []uint64{startSeq, endSeq}
+	for _, m := range arr {
+		if m.Message.SeqNumber >= startSeq && m.Message.SeqNumber <= endSeq {
+			res.SignedMessages = append(res.SignedMessages, m)
+		}
+	}
+	return res
+}
+
 // RespondToGetDecidedByRange responds to a GetDecidedByRange
 func (n *TestNetwork) RespondToGetDecidedByRange(stream network.SyncStream, msg *network.SyncMessage) error {
 	msgBytes, err := json.Marshal(network.Message{
@@ -299,6 +352,11 @@ func (n *TestNetwork) SubscribeToMainTopic() error {
 	return nil
 }
 
+// SubscribeToDecidedTopics implementation
+func (n *TestNetwork) SubscribeToDecidedTopics() error {
+	return nil
+}
+
 // TestStream struct
 type TestStream struct {
 	C    chan []byte
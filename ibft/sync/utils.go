@@ -1,17 +1,59 @@
 package sync
 
-import "github.com/bloxapp/ssv/network"
+import (
+	"github.com/bloxapp/ssv/network"
+	"sort"
+	"time"
+)
 
-// GetPeers returns an array of peers selected
-func GetPeers(net network.Network, pk []byte, maxPeerCount int) ([]string, error) {
-	// TODO select peers by quality/ score?
+// GetPeers returns an array of peers selected, peers of the given committee (operator public keys) are preferred
+// as they are likely to have the complete history of the validator, then peers with a lower latency
+func GetPeers(net network.Network, pk []byte, maxPeerCount int, committee ...string) ([]string, error) {
 	// TODO - should be changed to support multi duty
 	usedPeers, err := net.AllPeers(pk)
 	if err != nil {
 		return nil, err
 	}
+	SortPeersByLatency(net, usedPeers)
+	PreferCommitteePeers(net, usedPeers, committee)
 	if len(usedPeers) > maxPeerCount {
 		usedPeers = usedPeers[:maxPeerCount]
 	}
 	return usedPeers, nil
 }
+
+// SortPeersByLatency sorts the given peers by their latency,
+// peers w/o known latency come first so they will be measured
+func SortPeersByLatency(net network.Network, peers []string) {
+	sort.SliceStable(peers, func(i, j int) bool {
+		return PeerLatency(net, peers[i]) < PeerLatency(net, peers[j])
+	})
+}
+
+// PreferCommitteePeers moves the peers of the given committee operators to the beginning of the given peers,
+// the order is kept otherwise. does nothing if the network doesn't map operators to peers
+func PreferCommitteePeers(net network.Network, peers []string, committee []string) {
+	op, ok := net.(network.OperatorPeers)
+	if !ok || len(committee) == 0 {
+		return
+	}
+	committeePeers := make(map[string]bool, len(committee))
+	for _, operatorPubKey := range committee {
+		if p, found := op.OperatorPeer(operatorPubKey); found {
+			committeePeers[p] = true
+		}
+	}
+	sort.SliceStable(peers, func(i, j int) bool {
+		return committeePeers[peers[i]] && !committeePeers[peers[j]]
+	})
+}
+
+// PeerLatency returns the latency of the given peer, or zero if unknown
+func PeerLatency(net network.Network, peer string) time.Duration {
+	pl, ok := net.(network.PeersLatency)
+	if !ok {
+		return 0
+	}
+	latency, _ := pl.PeerLatency(peer)
+	return latency
+}
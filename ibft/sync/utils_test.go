@@ -0,0 +1,76 @@
+package sync
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type latencyNetwork struct {
+	network.Network
+	peers   []string
+	latency map[string]time.Duration
+}
+
+func (n *latencyNetwork) AllPeers(validatorPk []byte) ([]string, error) {
+	// a copy is returned as peers are sorted in place
+	return append([]string{}, n.peers...), nil
+}
+
+func (n *latencyNetwork) PeerLatency(peerStr string) (time.Duration, bool) {
+	l, found := n.latency[peerStr]
+	return l, found
+}
+
+type operatorsNetwork struct {
+	latencyNetwork
+	operators map[string]string
+}
+
+func (n *operatorsNetwork) OperatorPeer(operatorPubKey string) (string, bool) {
+	p, found := n.operators[operatorPubKey]
+	return p, found
+}
+
+func TestGetPeers_Committee(t *testing.T) {
+	net := &operatorsNetwork{
+		latencyNetwork: latencyNetwork{
+			peers: []string{"slow", "fast", "committee-slow", "medium", "committee-fast"},
+			latency: map[string]time.Duration{
+				"slow":           time.Second,
+				"committee-slow": time.Second,
+				"medium":         100 * time.Millisecond,
+				"fast":           10 * time.Millisecond,
+				"committee-fast": 10 * time.Millisecond,
+			},
+		},
+		operators: map[string]string{
+			"op1": "committee-slow",
+			"op2": "committee-fast",
+			"op3": "disconnected",
+		},
+	}
+	peers, err := GetPeers(net, []byte{1, 2, 3, 4}, 3, "op1", "op2", "op3", "op4")
+	require.NoError(t, err)
+	require.Equal(t, []string{"committee-fast", "committee-slow", "fast"}, peers)
+
+	// w/o committee, only latency is considered
+	peers, err = GetPeers(net, []byte{1, 2, 3, 4}, 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fast", "committee-fast", "medium"}, peers)
+}
+
+func TestGetPeers(t *testing.T) {
+	net := &latencyNetwork{
+		peers: []string{"slow", "fast", "unknown", "medium"},
+		latency: map[string]time.Duration{
+			"slow":   time.Second,
+			"medium": 100 * time.Millisecond,
+			"fast":   10 * time.Millisecond,
+		},
+	}
+	peers, err := GetPeers(net, []byte{1, 2, 3, 4}, 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"unknown", "fast", "medium"}, peers)
+}
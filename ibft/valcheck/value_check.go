@@ -5,3 +5,23 @@ package valcheck
 type ValueCheck interface {
 	Check(value []byte) error
 }
+
+// combined runs multiple value checks in order
+type combined struct {
+	checks []ValueCheck
+}
+
+// Combine returns a value check that fails on the first failing check
+func Combine(checks ...ValueCheck) ValueCheck {
+	return &combined{checks: checks}
+}
+
+// Check implements ValueCheck
+func (c *combined) Check(value []byte) error {
+	for _, check := range c.checks {
+		if err := check.Check(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
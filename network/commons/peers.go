@@ -3,6 +3,7 @@ package commons
 import (
 	"context"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"time"
@@ -32,11 +33,11 @@ func WaitForMinPeers(ctx WaitMinPeersCtx, validatorPk []byte, min int, start, li
 
 		select {
 		case <-ctx.Ctx.Done():
-			return errors.New("timed out")
+			return errs.Mark(errors.New("timed out"), errs.ErrNoPeers)
 		default:
 			interval *= 2
 			if stopAtLimit && interval == limit {
-				return errors.New("could not find peers")
+				return errs.Mark(errors.New("could not find peers"), errs.ErrNoPeers)
 			}
 			interval %= limit
 			if interval == 0 {
@@ -0,0 +1,26 @@
+package network
+
+// ControlMessage is signed by the maintainer of the exporters (admin key) and broadcasted on the main topic,
+// exporters apply it to update their runtime configuration, e.g. to coordinate staged rollouts across the fleet
+type ControlMessage struct {
+	// Nonce must increase with each message, messages with an older (or same) nonce are ignored
+	Nonce uint64 `json:"nonce"`
+	// Timestamp is the unix time (milliseconds) of the message
+	Timestamp int64 `json:"timestamp"`
+	// SyncWhitelist are validators (hex public keys) that are synced even if ibft sync is disabled,
+	// replaces the whitelist of the previous message
+	SyncWhitelist []string `json:"syncWhitelist,omitempty"`
+	// SyncBlacklist are validators (hex public keys) that are not synced, even if ibft sync is enabled or whitelisted,
+	// replaces the blacklist of the previous message
+	SyncBlacklist []string `json:"syncBlacklist,omitempty"`
+	// Signature is the signature of the admin key on the message
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// ControlMessages is the interface for broadcasting and receiving control messages on the main topic
+type ControlMessages interface {
+	// BroadcastControlMessage broadcasts the given control message on the main topic
+	BroadcastControlMessage(msg *ControlMessage) error
+	// ReceivedControlMessageChan returns the channel for control messages
+	ReceivedControlMessageChan() <-chan *ControlMessage
+}
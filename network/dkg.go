@@ -0,0 +1,51 @@
+package network
+
+// DKGMsgType is the type of a dkg message
+type DKGMsgType int32
+
+const (
+	// DKGInitType is sent by the initiator of a ceremony to invite the participating operators
+	DKGInitType DKGMsgType = iota
+	// DKGDealType holds the commitments and encrypted shares of a single dealer
+	DKGDealType
+	// DKGCompleteType is sent by a participant once it derived its share
+	DKGCompleteType
+)
+
+// String returns the name of the dkg message type
+func (t DKGMsgType) String() string {
+	switch t {
+	case DKGInitType:
+		return "INIT"
+	case DKGDealType:
+		return "DEAL"
+	case DKGCompleteType:
+		return "COMPLETE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// DKGMessage is a message that is exchanged during a distributed key generation ceremony
+type DKGMessage struct {
+	// CeremonyID identifies the ceremony
+	CeremonyID string `json:"ceremonyId"`
+	// Type is the type of message
+	Type DKGMsgType `json:"type"`
+	// Sender is the operator public key (base64) of the sender
+	Sender string `json:"sender"`
+	// Data is the type specific payload
+	Data []byte `json:"data"`
+	// Signature is the signature of the sender's operator key on the message
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// DKG is the interface for the network layer of distributed key generation
+type DKG interface {
+	// SubscribeToDKGTopic subscribes to the dkg topic
+	SubscribeToDKGTopic() error
+	// BroadcastDKG broadcasts the given message on the dkg topic
+	BroadcastDKG(msg *DKGMessage) error
+	// ReceivedDKGMsgChan returns the channel for dkg messages
+	ReceivedDKGMsgChan() <-chan *DKGMessage
+}
@@ -0,0 +1,26 @@
+package network
+
+// FailoverHeartbeat is broadcasted periodically by the active instance of an operator,
+// redundant (standby) instances that share the same operator key use it to detect whether the active instance is alive
+type FailoverHeartbeat struct {
+	// Sender is the operator public key (base64) of the sender
+	Sender string `json:"sender"`
+	// InstanceID identifies the sending instance
+	InstanceID string `json:"instanceId"`
+	// Primary is true if the sending instance is configured as primary
+	Primary bool `json:"primary"`
+	// Timestamp is the unix time (milliseconds) of the heartbeat
+	Timestamp int64 `json:"timestamp"`
+	// Signature is the signature of the sender's operator key on the heartbeat
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Failover is the interface for the network layer of active/standby coordination
+type Failover interface {
+	// SubscribeToFailoverTopic subscribes to the failover topic
+	SubscribeToFailoverTopic() error
+	// BroadcastHeartbeat broadcasts the given heartbeat on the failover topic
+	BroadcastHeartbeat(hb *FailoverHeartbeat) error
+	// ReceivedHeartbeatChan returns the channel for heartbeats
+	ReceivedHeartbeatChan() <-chan *FailoverHeartbeat
+}
@@ -4,15 +4,41 @@ import "github.com/bloxapp/ssv/network"
 
 // Fork is an interface for network specific fork implementations
 type Fork interface {
+	identity
 	encoding
 	pubSubMapping
+	validation
+	syncProtocols
+}
+
+type identity interface {
+	// ID returns the identifier of the fork (e.g. "v0"), announced by nodes so the adoption of forks can be tracked
+	ID() string
+	// MessageVersion returns the version of the message envelope of the fork, outgoing messages are stamped with it
+	MessageVersion() network.MessageVersion
 }
 
 type pubSubMapping interface {
 	ValidatorTopicID(pk []byte) string
+	// DecidedTopicID returns the topic of the decided messages (and highest decided announcements) of the given validator
+	DecidedTopicID(pk []byte) string
+	// DecidedTopicIDs returns all the topics of decided messages, used by nodes that follow all validators (e.g. exporter)
+	DecidedTopicIDs() []string
 }
 
 type encoding interface {
 	EncodeNetworkMsg(msg *network.Message) ([]byte, error)
 	DecodeNetworkMsg(data []byte) (*network.Message, error)
+	// DecodeNetworkMsgInto decodes the given data into an existing message, used to reuse messages
+	DecodeNetworkMsgInto(data []byte, msg *network.Message) error
+}
+
+type validation interface {
+	// ValidateNetworkMsg validates a decoded message according to the rules of the fork
+	ValidateNetworkMsg(msg *network.Message) error
+}
+
+type syncProtocols interface {
+	// SyncProtocolID returns the protocol id of the given sync stream (e.g. "highest_decided")
+	SyncProtocolID(stream string) string
 }
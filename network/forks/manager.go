@@ -0,0 +1,292 @@
+package forks
+
+import (
+	"context"
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	metricsActiveForkEpoch = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:network:active_fork_epoch",
+		Help: "The activation epoch of the active network fork",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsActiveForkEpoch); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// ScheduledFork is a fork that is activated at the given epoch
+type ScheduledFork struct {
+	Epoch uint64
+	Fork  Fork
+}
+
+// ActivationHandler is called once a scheduled fork was activated
+type ActivationHandler func(prev, next ScheduledFork)
+
+// ManagerOptions contains options to create a fork manager
+type ManagerOptions struct {
+	Logger *zap.Logger
+	// CurrentEpoch returns the current epoch, usually estimated from the eth2 network genesis time
+	CurrentEpoch func() uint64
+	// Schedule is the list of forks by activation epoch, a fork at epoch 0 (genesis) is required
+	Schedule []ScheduledFork
+}
+
+// Manager is a Fork that delegates to the fork that is active in the current epoch,
+// subsystems (topic naming, encoding, validation, sync protocols) consult it so network upgrades
+// can be shipped ahead of time with an activation epoch.
+// note that existing topic subscriptions are not switched, handlers that were registered with OnActivation
+// are responsible for re-subscribing if the topic naming was changed
+type Manager struct {
+	logger       *zap.Logger
+	currentEpoch func() uint64
+	// schedule is sorted by epoch
+	schedule []ScheduledFork
+
+	handlersLock sync.Mutex
+	handlers     []ActivationHandler
+}
+
+// NewManager creates a new fork manager
+func NewManager(opts ManagerOptions) (*Manager, error) {
+	if opts.CurrentEpoch == nil {
+		return nil, errors.New("current epoch function is required")
+	}
+	schedule := make([]ScheduledFork, len(opts.Schedule))
+	copy(schedule, opts.Schedule)
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].Epoch < schedule[j].Epoch
+	})
+	if len(schedule) == 0 || schedule[0].Epoch != 0 {
+		return nil, errors.New("genesis fork is required")
+	}
+	for i, sf := range schedule {
+		if sf.Fork == nil {
+			return nil, errors.Errorf("fork at epoch %d is nil", sf.Epoch)
+		}
+		if i > 0 && schedule[i-1].Epoch == sf.Epoch {
+			return nil, errors.Errorf("multiple forks at epoch %d", sf.Epoch)
+		}
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &Manager{
+		logger:       logger.With(zap.String("component", "forks/manager")),
+		currentEpoch: opts.CurrentEpoch,
+		schedule:     schedule,
+	}, nil
+}
+
+// Schedule returns the scheduled forks ordered by activation epoch
+func (m *Manager) Schedule() []ScheduledFork {
+	res := make([]ScheduledFork, len(m.schedule))
+	copy(res, m.schedule)
+	return res
+}
+
+// Current returns the fork that is active in the current epoch
+func (m *Manager) Current() Fork {
+	return m.scheduledAt(m.currentEpoch()).Fork
+}
+
+// ForkAt returns the fork that is active at the given epoch
+func (m *Manager) ForkAt(epoch uint64) Fork {
+	return m.scheduledAt(epoch).Fork
+}
+
+// NextFork returns the next scheduled fork, false if no fork is scheduled after the current one
+func (m *Manager) NextFork() (ScheduledFork, bool) {
+	epoch := m.currentEpoch()
+	for _, sf := range m.schedule {
+		if sf.Epoch > epoch {
+			return sf, true
+		}
+	}
+	return ScheduledFork{}, false
+}
+
+// OnActivation registers a handler that is called once a scheduled fork was activated
+func (m *Manager) OnActivation(handler ActivationHandler) {
+	m.handlersLock.Lock()
+	defer m.handlersLock.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+}
+
+// Start checks the active fork on every tick (e.g. slot), until the context is done
+func (m *Manager) Start(ctx context.Context, interval time.Duration) {
+	active := m.scheduledAt(m.currentEpoch())
+	metricsActiveForkEpoch.Set(float64(active.Epoch))
+	m.logger.Info("active network fork", zap.Uint64("epoch", active.Epoch))
+	if next, ok := m.NextFork(); ok {
+		m.logger.Info("next network fork is scheduled", zap.Uint64("epoch", next.Epoch))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			active = m.checkActivation(active)
+		}
+	}
+}
+
+// checkActivation notifies the handlers if the active fork was changed since the given one
+func (m *Manager) checkActivation(prev ScheduledFork) ScheduledFork {
+	next := m.scheduledAt(m.currentEpoch())
+	if next.Epoch == prev.Epoch {
+		return prev
+	}
+	m.logger.Info("network fork was activated", zap.Uint64("epoch", next.Epoch),
+		zap.Uint64("prevEpoch", prev.Epoch))
+	metricsActiveForkEpoch.Set(float64(next.Epoch))
+
+	m.handlersLock.Lock()
+	handlers := make([]ActivationHandler, len(m.handlers))
+	copy(handlers, m.handlers)
+	m.handlersLock.Unlock()
+
+	for _, h := range handlers {
+		h(prev, next)
+	}
+	return next
+}
+
+// scheduledAt returns the scheduled fork that is active at the given epoch
+func (m *Manager) scheduledAt(epoch uint64) ScheduledFork {
+	// the first fork with activation epoch greater than the given epoch
+	i := sort.Search(len(m.schedule), func(i int) bool {
+		return m.schedule[i].Epoch > epoch
+	})
+	return m.schedule[i-1]
+}
+
+// previous returns the fork that was active before the current one, nil if the current fork is genesis
+func (m *Manager) previous() Fork {
+	epoch := m.currentEpoch()
+	i := sort.Search(len(m.schedule), func(i int) bool {
+		return m.schedule[i].Epoch > epoch
+	})
+	if i < 2 {
+		return nil
+	}
+	return m.schedule[i-2].Fork
+}
+
+// EncodeNetworkMsg encodes the message with the current fork
+func (m *Manager) EncodeNetworkMsg(msg *network.Message) ([]byte, error) {
+	return m.Current().EncodeNetworkMsg(msg)
+}
+
+// DecodeNetworkMsg decodes the message with the current fork,
+// messages of peers that didn't switch yet are decoded with the previous fork
+func (m *Manager) DecodeNetworkMsg(data []byte) (*network.Message, error) {
+	msg, err := m.Current().DecodeNetworkMsg(data)
+	if err != nil {
+		if prev := m.previous(); prev != nil {
+			if prevMsg, prevErr := prev.DecodeNetworkMsg(data); prevErr == nil {
+				return prevMsg, nil
+			}
+		}
+	}
+	return msg, err
+}
+
+// DecodeNetworkMsgInto decodes the message into an existing message with the current fork,
+// messages of peers that didn't switch yet are decoded with the previous fork
+func (m *Manager) DecodeNetworkMsgInto(data []byte, msg *network.Message) error {
+	err := m.Current().DecodeNetworkMsgInto(data, msg)
+	if err != nil {
+		if prev := m.previous(); prev != nil {
+			if prevErr := prev.DecodeNetworkMsgInto(data, msg); prevErr == nil {
+				return nil
+			}
+		}
+	}
+	return err
+}
+
+// ID returns the identifier of the current fork
+func (m *Manager) ID() string {
+	return m.Current().ID()
+}
+
+// MessageVersion returns the message version of the current fork
+func (m *Manager) MessageVersion() network.MessageVersion {
+	return m.Current().MessageVersion()
+}
+
+// ValidatorTopicID returns the topic of the validator in the current fork
+func (m *Manager) ValidatorTopicID(pk []byte) string {
+	return m.Current().ValidatorTopicID(pk)
+}
+
+// DecidedTopicID returns the decided topic of the validator in the current fork
+func (m *Manager) DecidedTopicID(pk []byte) string {
+	return m.Current().DecidedTopicID(pk)
+}
+
+// DecidedTopicIDs returns the (unique) decided topics of all the scheduled forks,
+// so decided messages are received throughout the rollout of a fork that changes the topics
+func (m *Manager) DecidedTopicIDs() []string {
+	return m.uniqueIDs(func(f Fork) []string {
+		return f.DecidedTopicIDs()
+	})
+}
+
+// DecidedTopicIDsOf returns the (unique) decided topics of the given validator in all the scheduled forks
+func (m *Manager) DecidedTopicIDsOf(pk []byte) []string {
+	return m.uniqueIDs(func(f Fork) []string {
+		return []string{f.DecidedTopicID(pk)}
+	})
+}
+
+// ValidateNetworkMsg validates the message according to the rules of the current fork
+func (m *Manager) ValidateNetworkMsg(msg *network.Message) error {
+	return m.Current().ValidateNetworkMsg(msg)
+}
+
+// SyncProtocolID returns the protocol id of the given sync stream in the current fork
+func (m *Manager) SyncProtocolID(stream string) string {
+	return m.Current().SyncProtocolID(stream)
+}
+
+// SyncProtocolIDs returns the (unique) protocol ids of the given sync stream in all the scheduled forks,
+// so streams of peers that didn't switch yet (or switched already) can be handled
+func (m *Manager) SyncProtocolIDs(stream string) []string {
+	return m.uniqueIDs(func(f Fork) []string {
+		return []string{f.SyncProtocolID(stream)}
+	})
+}
+
+// uniqueIDs returns the unique ids of all the scheduled forks, ordered by activation epoch
+func (m *Manager) uniqueIDs(ids func(f Fork) []string) []string {
+	var res []string
+	visited := map[string]bool{}
+	for _, sf := range m.schedule {
+		for _, id := range ids(sf.Fork) {
+			if !visited[id] {
+				visited[id] = true
+				res = append(res, id)
+			}
+		}
+	}
+	return res
+}
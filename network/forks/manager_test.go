@@ -0,0 +1,180 @@
+package forks
+
+import (
+	"encoding/json"
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// testFork is a fork that uses a name for its topics and protocols,
+// decoding requires the name to be a prefix of the data
+type testFork struct {
+	name string
+}
+
+func (f *testFork) ID() string {
+	return f.name
+}
+
+func (f *testFork) EncodeNetworkMsg(msg *network.Message) ([]byte, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(f.name), data...), nil
+}
+
+func (f *testFork) DecodeNetworkMsg(data []byte) (*network.Message, error) {
+	msg := &network.Message{}
+	return msg, f.DecodeNetworkMsgInto(data, msg)
+}
+
+func (f *testFork) DecodeNetworkMsgInto(data []byte, msg *network.Message) error {
+	if len(data) < len(f.name) || string(data[:len(f.name)]) != f.name {
+		return errors.New("unknown encoding")
+	}
+	return json.Unmarshal(data[len(f.name):], msg)
+}
+
+func (f *testFork) ValidatorTopicID(pk []byte) string {
+	return f.name + "." + string(pk)
+}
+
+func (f *testFork) DecidedTopicID(pk []byte) string {
+	return f.name + ".decided." + string(pk[:1])
+}
+
+func (f *testFork) MessageVersion() network.MessageVersion {
+	return network.MessageVersionV0
+}
+
+func (f *testFork) DecidedTopicIDs() []string {
+	return []string{f.name + ".decided.a", f.name + ".decided.b"}
+}
+
+func (f *testFork) ValidateNetworkMsg(msg *network.Message) error {
+	return network.ValidateMessage(msg)
+}
+
+func (f *testFork) SyncProtocolID(stream string) string {
+	return "/" + f.name + "/" + stream
+}
+
+func TestNewManager(t *testing.T) {
+	epoch := func() uint64 { return 0 }
+
+	_, err := NewManager(ManagerOptions{CurrentEpoch: epoch})
+	require.EqualError(t, err, "genesis fork is required")
+
+	_, err = NewManager(ManagerOptions{CurrentEpoch: epoch, Schedule: []ScheduledFork{{Epoch: 10, Fork: &testFork{"a"}}}})
+	require.EqualError(t, err, "genesis fork is required")
+
+	_, err = NewManager(ManagerOptions{CurrentEpoch: epoch, Schedule: []ScheduledFork{
+		{Epoch: 0, Fork: &testFork{"a"}},
+		{Epoch: 0, Fork: &testFork{"b"}},
+	}})
+	require.EqualError(t, err, "multiple forks at epoch 0")
+
+	_, err = NewManager(ManagerOptions{Schedule: []ScheduledFork{{Epoch: 0, Fork: &testFork{"a"}}}})
+	require.EqualError(t, err, "current epoch function is required")
+}
+
+func TestManager_Schedule(t *testing.T) {
+	current := uint64(0)
+	genesis, upgrade := &testFork{"genesis"}, &testFork{"upgrade"}
+	m, err := NewManager(ManagerOptions{
+		CurrentEpoch: func() uint64 { return current },
+		// not ordered on purpose
+		Schedule: []ScheduledFork{{Epoch: 100, Fork: upgrade}, {Epoch: 0, Fork: genesis}},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, genesis, m.ForkAt(0))
+	require.Equal(t, genesis, m.ForkAt(99))
+	require.Equal(t, upgrade, m.ForkAt(100))
+	require.Equal(t, upgrade, m.ForkAt(1000))
+
+	require.Equal(t, genesis, m.Current())
+	next, ok := m.NextFork()
+	require.True(t, ok)
+	require.Equal(t, uint64(100), next.Epoch)
+	require.Equal(t, "genesis.pk", m.ValidatorTopicID([]byte("pk")))
+	require.Equal(t, "/genesis/highest_decided", m.SyncProtocolID("highest_decided"))
+	require.Equal(t, "genesis", m.ID())
+
+	current = 100
+	require.Equal(t, upgrade, m.Current())
+	_, ok = m.NextFork()
+	require.False(t, ok)
+	require.Equal(t, "upgrade.pk", m.ValidatorTopicID([]byte("pk")))
+	require.Equal(t, "/upgrade/highest_decided", m.SyncProtocolID("highest_decided"))
+	require.Equal(t, "upgrade", m.ID())
+
+	require.Equal(t, []string{"/genesis/highest_decided", "/upgrade/highest_decided"},
+		m.SyncProtocolIDs("highest_decided"))
+	require.Equal(t, "upgrade.decided.a", m.DecidedTopicID([]byte("abc")))
+	require.Equal(t, []string{"genesis.decided.a", "genesis.decided.b", "upgrade.decided.a", "upgrade.decided.b"},
+		m.DecidedTopicIDs())
+	require.Equal(t, []string{"genesis.decided.b", "upgrade.decided.b"}, m.DecidedTopicIDsOf([]byte("bcd")))
+}
+
+func TestManager_DecodeFallback(t *testing.T) {
+	current := uint64(5)
+	genesis, upgrade := &testFork{"genesis"}, &testFork{"upgrade"}
+	m, err := NewManager(ManagerOptions{
+		CurrentEpoch: func() uint64 { return current },
+		Schedule:     []ScheduledFork{{Epoch: 0, Fork: genesis}, {Epoch: 10, Fork: upgrade}},
+	})
+	require.NoError(t, err)
+
+	msg := &network.Message{Type: network.NetworkMsg_SyncType, SyncMessage: &network.SyncMessage{}}
+	genesisData, err := genesis.EncodeNetworkMsg(msg)
+	require.NoError(t, err)
+
+	// before the upgrade, encoding is done with genesis
+	data, err := m.EncodeNetworkMsg(msg)
+	require.NoError(t, err)
+	require.Equal(t, genesisData, data)
+
+	current = 10
+	data, err = m.EncodeNetworkMsg(msg)
+	require.NoError(t, err)
+	require.NotEqual(t, genesisData, data)
+
+	// messages of peers that didn't switch yet are decoded with the previous fork
+	decoded, err := m.DecodeNetworkMsg(genesisData)
+	require.NoError(t, err)
+	require.Equal(t, network.NetworkMsg_SyncType, decoded.Type)
+	decodedInto := &network.Message{}
+	require.NoError(t, m.DecodeNetworkMsgInto(genesisData, decodedInto))
+	require.Equal(t, network.NetworkMsg_SyncType, decodedInto.Type)
+
+	_, err = m.DecodeNetworkMsg([]byte("unknown"))
+	require.EqualError(t, err, "unknown encoding")
+}
+
+func TestManager_OnActivation(t *testing.T) {
+	current := uint64(0)
+	m, err := NewManager(ManagerOptions{
+		CurrentEpoch: func() uint64 { return current },
+		Schedule:     []ScheduledFork{{Epoch: 0, Fork: &testFork{"genesis"}}, {Epoch: 10, Fork: &testFork{"upgrade"}}},
+	})
+	require.NoError(t, err)
+
+	var activated []uint64
+	m.OnActivation(func(prev, next ScheduledFork) {
+		activated = append(activated, next.Epoch)
+	})
+
+	active := m.checkActivation(m.scheduledAt(current))
+	require.Len(t, activated, 0)
+	current = 11
+	active = m.checkActivation(active)
+	require.Equal(t, []uint64{10}, activated)
+	require.Equal(t, uint64(10), active.Epoch)
+	// not notified again
+	m.checkActivation(active)
+	require.Equal(t, []uint64{10}, activated)
+}
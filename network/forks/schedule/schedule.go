@@ -0,0 +1,43 @@
+package schedule
+
+import (
+	"math"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/network/forks"
+	v0 "github.com/bloxapp/ssv/network/forks/v0"
+	v1 "github.com/bloxapp/ssv/network/forks/v1"
+	"go.uber.org/zap"
+)
+
+// farFutureEpoch is the activation epoch of forks that were not scheduled yet
+const farFutureEpoch = math.MaxUint64
+
+// Forks returns the scheduled network forks, upcoming forks should be added with their activation epoch.
+// the given epochs (by fork id) override the default activation epochs, e.g. to activate a fork in a test network
+func Forks(epochs map[string]uint64) []forks.ScheduledFork {
+	scheduled := []forks.ScheduledFork{
+		{Epoch: 0, Fork: v0.New()},
+		// sharding of decided topics, not scheduled yet
+		{Epoch: farFutureEpoch, Fork: v1.New()},
+	}
+	for i, f := range scheduled {
+		// genesis fork is always active from epoch 0
+		if epoch, ok := epochs[f.Fork.ID()]; ok && i > 0 {
+			scheduled[i].Epoch = epoch
+		}
+	}
+	return scheduled
+}
+
+// NewManager creates a fork manager with the scheduled network forks, using the epoch of the given eth2 network.
+// epochs is optional, see Forks
+func NewManager(logger *zap.Logger, ethNetwork core.Network, epochs map[string]uint64) (*forks.Manager, error) {
+	return forks.NewManager(forks.ManagerOptions{
+		Logger: logger,
+		CurrentEpoch: func() uint64 {
+			return uint64(ethNetwork.EstimatedCurrentEpoch())
+		},
+		Schedule: Forks(epochs),
+	})
+}
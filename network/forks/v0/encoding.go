@@ -13,6 +13,30 @@ func (v0 *ForkV0) EncodeNetworkMsg(msg *network.Message) ([]byte, error) {
 // DecodeNetworkMsg - genesis version 0
 func (v0 *ForkV0) DecodeNetworkMsg(data []byte) (*network.Message, error) {
 	ret := &network.Message{}
-	err := json.Unmarshal(data, ret)
+	err := v0.DecodeNetworkMsgInto(data, ret)
 	return ret, err
 }
+
+// DecodeNetworkMsgInto - genesis version 0
+func (v0 *ForkV0) DecodeNetworkMsgInto(data []byte, msg *network.Message) error {
+	if err := json.Unmarshal(data, msg); err != nil {
+		// messages of newer versions might have a different format,
+		// returning the version error to avoid confusing unmarshal errors
+		if versionErr := network.ValidateVersion(decodeVersion(data)); versionErr != nil {
+			return versionErr
+		}
+		return err
+	}
+	return nil
+}
+
+// decodeVersion tries to decode only the version of the message, v0 is returned if it could not be decoded
+func decodeVersion(data []byte) network.MessageVersion {
+	v := struct {
+		Version network.MessageVersion
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return network.MessageVersionV0
+	}
+	return v.Version
+}
@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package v0
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"testing"
+)
+
+func FuzzForkV0_DecodeNetworkMsg(f *testing.F) {
+	fork := New()
+	seed, err := fork.EncodeNetworkMsg(newTestNetworkMsg())
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(seed)
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"Type":3}`))
+	f.Add([]byte(`{"Type":3,"SyncMessage":{"SignedMessages":[null]}}`))
+	f.Add([]byte(`{"Type":1,"SignedMessage":{"signature":"AA=="}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg := network.AcquireMessage()
+		defer network.ReleaseMessage(msg)
+		if err := fork.DecodeNetworkMsgInto(data, msg); err != nil {
+			return
+		}
+		if err := network.ValidateMessage(msg); err != nil {
+			return
+		}
+		_ = msg.Type.String()
+		if msg.SignedMessage != nil {
+			if _, err := msg.SignedMessage.Message.SigningRoot(); err != nil {
+				t.Fatalf("could not get signing root of a valid message: %v", err)
+			}
+		}
+		if msg.SyncMessage != nil {
+			for _, sm := range msg.SyncMessage.SignedMessages {
+				_ = sm.Message.SeqNumber
+			}
+		}
+		if _, err := fork.EncodeNetworkMsg(msg); err != nil {
+			t.Fatalf("could not encode a decoded message: %v", err)
+		}
+	})
+}
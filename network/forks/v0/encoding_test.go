@@ -0,0 +1,87 @@
+package v0
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newTestNetworkMsg() *network.Message {
+	return &network.Message{
+		SignedMessage: &proto.SignedMessage{
+			Message: &proto.Message{
+				Type:      proto.RoundState_Commit,
+				Round:     1,
+				Lambda:    []byte("lambda_ATTESTER"),
+				SeqNumber: 12,
+				Value:     []byte("value"),
+			},
+			Signature: make([]byte, 96),
+			SignerIds: []uint64{1, 2, 3},
+		},
+		Type: network.NetworkMsg_IBFTType,
+	}
+}
+
+func TestForkV0_DecodeNetworkMsgInto(t *testing.T) {
+	fork := New()
+	data, err := fork.EncodeNetworkMsg(newTestNetworkMsg())
+	require.NoError(t, err)
+
+	msg := network.AcquireMessage()
+	require.NoError(t, fork.DecodeNetworkMsgInto(data, msg))
+	require.Equal(t, newTestNetworkMsg(), msg)
+
+	network.ReleaseMessage(msg)
+	require.Nil(t, msg.SignedMessage)
+}
+
+func TestForkV0_DecodeNetworkMsgVersion(t *testing.T) {
+	fork := New()
+
+	// v0 messages are encoded without a version, as older peers do
+	data, err := fork.EncodeNetworkMsg(newTestNetworkMsg())
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "Version")
+
+	// unknown versions are decoded, the version is validated later on
+	msg, err := fork.DecodeNetworkMsg([]byte(`{"Version":3,"Type":1}`))
+	require.NoError(t, err)
+	require.Equal(t, network.MessageVersion(3), msg.Version)
+	require.True(t, errors.Is(network.ValidateMessage(msg), network.ErrUnknownMessageVersion))
+
+	// a format change of a newer version results in a version error rather than an unmarshal error
+	_, err = fork.DecodeNetworkMsg([]byte(`{"Version":3,"Type":"ibft"}`))
+	require.EqualError(t, err, "unknown message version 3")
+	_, err = fork.DecodeNetworkMsg([]byte(`{"Type":"ibft"}`))
+	require.Error(t, err)
+	require.False(t, errors.Is(err, network.ErrUnknownMessageVersion))
+}
+
+func BenchmarkForkV0_DecodeNetworkMsg(b *testing.B) {
+	fork := New()
+	data, err := fork.EncodeNetworkMsg(newTestNetworkMsg())
+	require.NoError(b, err)
+
+	b.Run("new message", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := fork.DecodeNetworkMsg(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pooled message", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			msg := network.AcquireMessage()
+			if err := fork.DecodeNetworkMsgInto(data, msg); err != nil {
+				b.Fatal(err)
+			}
+			network.ReleaseMessage(msg)
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package v0
 
+import "github.com/bloxapp/ssv/network"
+
 // ForkV0 is the genesis version 0 implementation
 type ForkV0 struct {
 }
@@ -8,3 +10,13 @@ type ForkV0 struct {
 func New() *ForkV0 {
 	return &ForkV0{}
 }
+
+// ID returns the identifier of the fork
+func (v0 *ForkV0) ID() string {
+	return "v0"
+}
+
+// MessageVersion returns the version of the message envelope of the fork
+func (v0 *ForkV0) MessageVersion() network.MessageVersion {
+	return network.MessageVersionV0
+}
@@ -4,7 +4,20 @@ import (
 	"encoding/hex"
 )
 
+// mainTopicID is the topic of decided messages of all validators
+const mainTopicID = "main"
+
 // ValidatorTopicID - genesis version 0
 func (v0 *ForkV0) ValidatorTopicID(pkByts []byte) string {
 	return hex.EncodeToString(pkByts)
 }
+
+// DecidedTopicID - genesis version 0, decided messages of all validators are sent on the main topic
+func (v0 *ForkV0) DecidedTopicID(pkByts []byte) string {
+	return mainTopicID
+}
+
+// DecidedTopicIDs - genesis version 0
+func (v0 *ForkV0) DecidedTopicIDs() []string {
+	return []string{mainTopicID}
+}
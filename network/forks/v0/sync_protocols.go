@@ -0,0 +1,9 @@
+package v0
+
+// syncProtocolPrefix is the prefix of sync protocol ids
+const syncProtocolPrefix = "/sync/"
+
+// SyncProtocolID - genesis version 0
+func (v0 *ForkV0) SyncProtocolID(stream string) string {
+	return syncProtocolPrefix + stream
+}
@@ -0,0 +1,13 @@
+package v0
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestForkV0_SyncProtocolID(t *testing.T) {
+	// protocol ids of genesis must not be changed, otherwise peers won't be able to sync
+	require.Equal(t, "/sync/highest_decided", New().SyncProtocolID("highest_decided"))
+	require.Equal(t, "/sync/decided_by_range", New().SyncProtocolID("decided_by_range"))
+	require.Equal(t, "/sync/last_change_round", New().SyncProtocolID("last_change_round"))
+}
@@ -0,0 +1,8 @@
+package v0
+
+import "github.com/bloxapp/ssv/network"
+
+// ValidateNetworkMsg - genesis version 0
+func (v0 *ForkV0) ValidateNetworkMsg(msg *network.Message) error {
+	return network.ValidateMessage(msg)
+}
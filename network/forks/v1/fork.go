@@ -0,0 +1,21 @@
+package v1
+
+import (
+	v0 "github.com/bloxapp/ssv/network/forks/v0"
+)
+
+// ForkV1 shards the decided traffic of the main topic into multiple topics,
+// the rest (encoding, validation, validator topics and sync protocols) is taken from ForkV0
+type ForkV1 struct {
+	*v0.ForkV0
+}
+
+// New returns an instance of ForkV1
+func New() *ForkV1 {
+	return &ForkV1{v0.New()}
+}
+
+// ID returns the identifier of the fork
+func (v1 *ForkV1) ID() string {
+	return "v1"
+}
@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// DecidedShards is the number of topics of decided messages
+const DecidedShards = 16
+
+// decidedTopicPrefix is the prefix of the decided topics, followed by the shard
+const decidedTopicPrefix = "decided."
+
+// DecidedTopicID - version 1, the topic is selected by the hash of the validator public key
+func (v1 *ForkV1) DecidedTopicID(pkByts []byte) string {
+	return decidedTopicID(DecidedShard(pkByts))
+}
+
+// DecidedTopicIDs - version 1, returns the topics of all the shards
+func (v1 *ForkV1) DecidedTopicIDs() []string {
+	ids := make([]string, 0, DecidedShards)
+	for shard := uint64(0); shard < DecidedShards; shard++ {
+		ids = append(ids, decidedTopicID(shard))
+	}
+	return ids
+}
+
+// DecidedShard returns the shard of the decided messages of the given validator
+func DecidedShard(pkByts []byte) uint64 {
+	h := sha256.Sum256(pkByts)
+	return binary.BigEndian.Uint64(h[:8]) % DecidedShards
+}
+
+func decidedTopicID(shard uint64) string {
+	return fmt.Sprintf("%s%d", decidedTopicPrefix, shard)
+}
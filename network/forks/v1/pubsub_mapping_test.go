@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForkV1_DecidedTopicID(t *testing.T) {
+	threshold.Init()
+	fork := New()
+
+	ids := fork.DecidedTopicIDs()
+	require.Len(t, ids, DecidedShards)
+	require.Equal(t, "decided.0", ids[0])
+	require.Equal(t, "decided.15", ids[DecidedShards-1])
+
+	shards := map[string]int{}
+	for i := 0; i < 200; i++ {
+		sk := bls.SecretKey{}
+		sk.SetByCSPRNG()
+		pk := sk.GetPublicKey().Serialize()
+		id := fork.DecidedTopicID(pk)
+		// the topic of a validator is deterministic
+		require.Equal(t, id, fork.DecidedTopicID(pk))
+		require.Contains(t, ids, id)
+		shards[id]++
+	}
+	// validators are spread among the shards
+	require.Greater(t, len(shards), DecidedShards/2)
+
+	require.Equal(t, "v1", fork.ID())
+	// validator topics are not changed
+	require.Equal(t, fork.ForkV0.ValidatorTopicID([]byte{1, 2}), fork.ValidatorTopicID([]byte{1, 2}))
+}
@@ -0,0 +1,13 @@
+package network
+
+import "github.com/bloxapp/ssv/ibft/proto"
+
+// HighestDecided is the interface for announcing highest decided instances on the decided topics,
+// it allows peers to detect that they are behind w/o opening sync streams.
+// announcements are decided messages, therefore authenticated by the aggregated signature of the committee
+type HighestDecided interface {
+	// BroadcastHighestDecided broadcasts the given highest decided message on the decided topic of its validator
+	BroadcastHighestDecided(msg *proto.SignedMessage) error
+	// ReceivedHighestDecidedChan returns the channel for highest decided announcements
+	ReceivedHighestDecidedChan() <-chan *proto.SignedMessage
+}
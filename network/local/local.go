@@ -7,10 +7,14 @@ import (
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"sync"
+	"time"
 
 	"github.com/bloxapp/ssv/ibft/proto"
 )
 
+// syncTimeout is the time to wait for a sync response, peers that are not interested in a request don't respond
+const syncTimeout = 5 * time.Second
+
 // Local implements network.Local interface
 type Local struct {
 	localPeerID        peer.ID
@@ -122,12 +126,21 @@ func (n *Local) GetHighestDecidedInstance(peerStr string, msg *network.SyncMessa
 			}
 		}()
 
-		ret := <-stream.(*Stream).ReceiveChan
-		return ret, nil
+		return readSyncResponse(stream)
 	}
 	return nil, errors.New("could not find peer")
 }
 
+// readSyncResponse waits for the response of a sync request
+func readSyncResponse(stream network.SyncStream) (*network.SyncMessage, error) {
+	select {
+	case ret := <-stream.(*Stream).ReceiveChan:
+		return ret, nil
+	case <-time.After(syncTimeout):
+		return nil, errors.New("sync request timed out")
+	}
+}
+
 // RespondToHighestDecidedInstance responds to a GetHighestDecidedInstance
 func (n *Local) RespondToHighestDecidedInstance(stream network.SyncStream, msg *network.SyncMessage) error {
 	msg.FromPeerID = string(n.localPeerID)
@@ -156,8 +169,7 @@ func (n *Local) GetDecidedByRange(peerStr string, msg *network.SyncMessage) (*ne
 			}
 		}()
 
-		ret := <-stream.(*Stream).ReceiveChan
-		return ret, nil
+		return readSyncResponse(stream)
 	}
 	return nil, errors.New("could not find peer")
 }
@@ -212,3 +224,8 @@ func (n *Local) BroadcastMainTopic(msg *proto.SignedMessage) error {
 func (n *Local) SubscribeToMainTopic() error {
 	return nil
 }
+
+// SubscribeToDecidedTopics implementation
+func (n *Local) SubscribeToDecidedTopics() error {
+	return nil
+}
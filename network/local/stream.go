@@ -15,9 +15,10 @@ type Stream struct {
 // NewLocalStream returs a stream instance
 func NewLocalStream(From string, To string) network.SyncStream {
 	return &Stream{
-		From:        From,
-		To:          To,
-		ReceiveChan: make(chan *network.SyncMessage),
+		From: From,
+		To:   To,
+		// buffered so a response that arrives after the request timed out doesn't block the responder
+		ReceiveChan: make(chan *network.SyncMessage, 1),
 	}
 }
 
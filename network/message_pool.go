@@ -0,0 +1,21 @@
+package network
+
+import "sync"
+
+var messagePool = sync.Pool{
+	New: func() interface{} {
+		return &Message{}
+	},
+}
+
+// AcquireMessage returns an empty message from the pool
+func AcquireMessage() *Message {
+	return messagePool.Get().(*Message)
+}
+
+// ReleaseMessage resets the given message and puts it back in the pool,
+// the message must not be used once released
+func ReleaseMessage(msg *Message) {
+	*msg = Message{}
+	messagePool.Put(msg)
+}
@@ -0,0 +1,74 @@
+package network
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/pkg/errors"
+)
+
+// ValidateMessage checks that a decoded message has the content its type requires,
+// messages are coming from untrusted peers and must be validated before they are used
+func ValidateMessage(msg *Message) error {
+	if msg == nil {
+		return errors.New("message is nil")
+	}
+	if err := ValidateVersion(msg.Version); err != nil {
+		return err
+	}
+	switch msg.Type {
+	case NetworkMsg_IBFTType, NetworkMsg_DecidedType, NetworkMsg_SignatureType, NetworkMsg_HighestDecidedType:
+		return validateSignedMessage(msg.SignedMessage)
+	case NetworkMsg_SyncType:
+		return validateSyncMessage(msg.SyncMessage)
+	case NetworkMsg_OperatorHeartbeatType:
+		return validateOperatorHeartbeat(msg.OperatorHeartbeat)
+	case NetworkMsg_ControlType:
+		return validateControlMessage(msg.ControlMessage)
+	default:
+		return errors.Errorf("unknown message type %d", msg.Type)
+	}
+}
+
+func validateSyncMessage(msg *SyncMessage) error {
+	if msg == nil {
+		return errors.New("sync message is nil")
+	}
+	for _, sm := range msg.SignedMessages {
+		if err := validateSignedMessage(sm); err != nil {
+			return errors.Wrap(err, "invalid signed message in sync message")
+		}
+	}
+	return nil
+}
+
+func validateSignedMessage(msg *proto.SignedMessage) error {
+	if msg == nil {
+		return errors.New("signed message is nil")
+	}
+	if msg.Message == nil {
+		return errors.New("signed message has no content")
+	}
+	return nil
+}
+
+func validateOperatorHeartbeat(hb *OperatorHeartbeat) error {
+	if hb == nil {
+		return errors.New("operator heartbeat is nil")
+	}
+	if len(hb.OperatorPubKey) == 0 {
+		return errors.New("operator heartbeat has no sender")
+	}
+	if len(hb.Signature) == 0 {
+		return errors.New("operator heartbeat is not signed")
+	}
+	return nil
+}
+
+func validateControlMessage(msg *ControlMessage) error {
+	if msg == nil {
+		return errors.New("control message is nil")
+	}
+	if len(msg.Signature) == 0 {
+		return errors.New("control message is not signed")
+	}
+	return nil
+}
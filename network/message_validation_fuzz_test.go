@@ -0,0 +1,42 @@
+//go:build go1.18
+// +build go1.18
+
+package network
+
+import (
+	"encoding/json"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"testing"
+)
+
+func FuzzSyncMessage_Decode(f *testing.F) {
+	seed, err := json.Marshal(&SyncMessage{
+		SignedMessages: []*proto.SignedMessage{newTestSignedMessage()},
+		Params:         []uint64{1, 10},
+		Lambda:         []byte("lambda_ATTESTER"),
+		Type:           Sync_GetInstanceRange,
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(seed)
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"SignedMessages":[null]}`))
+	f.Add([]byte(`{"SignedMessages":[{}],"params":[10,1]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg := &SyncMessage{}
+		if err := json.Unmarshal(data, msg); err != nil {
+			return
+		}
+		if err := ValidateMessage(&Message{SyncMessage: msg, Type: NetworkMsg_SyncType}); err != nil {
+			return
+		}
+		for _, sm := range msg.SignedMessages {
+			if _, err := sm.Message.SigningRoot(); err != nil {
+				t.Fatalf("could not get signing root of a valid message: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package network
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newTestSignedMessage() *proto.SignedMessage {
+	return &proto.SignedMessage{
+		Message: &proto.Message{
+			Type:      proto.RoundState_Commit,
+			Round:     1,
+			Lambda:    []byte("lambda_ATTESTER"),
+			SeqNumber: 12,
+			Value:     []byte("value"),
+		},
+		Signature: make([]byte, 96),
+		SignerIds: []uint64{1, 2, 3},
+	}
+}
+
+func TestValidateMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		msg           *Message
+		expectedError string
+	}{
+		{"nil message", nil, "message is nil"},
+		{"valid ibft message", &Message{SignedMessage: newTestSignedMessage(), Type: NetworkMsg_IBFTType}, ""},
+		{"ibft message w/o signed message", &Message{Type: NetworkMsg_IBFTType}, "signed message is nil"},
+		{"decided message w/o content", &Message{SignedMessage: &proto.SignedMessage{}, Type: NetworkMsg_DecidedType},
+			"signed message has no content"},
+		{"unknown version", &Message{Version: 2, SignedMessage: newTestSignedMessage(), Type: NetworkMsg_IBFTType},
+			"unknown message version 2"},
+		{"unknown type", &Message{SignedMessage: newTestSignedMessage(), Type: NetworkMsg(100)}, "unknown message type 100"},
+		{"valid sync message", &Message{SyncMessage: &SyncMessage{
+			SignedMessages: []*proto.SignedMessage{newTestSignedMessage()},
+		}, Type: NetworkMsg_SyncType}, ""},
+		{"sync message w/o content", &Message{Type: NetworkMsg_SyncType}, "sync message is nil"},
+		{"sync message with nil signed message", &Message{SyncMessage: &SyncMessage{
+			SignedMessages: []*proto.SignedMessage{newTestSignedMessage(), nil},
+		}, Type: NetworkMsg_SyncType}, "invalid signed message in sync message: signed message is nil"},
+		{"valid operator heartbeat", &Message{OperatorHeartbeat: &OperatorHeartbeat{
+			OperatorPubKey: "pk", Timestamp: 1, Signature: []byte("sig"),
+		}, Type: NetworkMsg_OperatorHeartbeatType}, ""},
+		{"operator heartbeat w/o content", &Message{Type: NetworkMsg_OperatorHeartbeatType}, "operator heartbeat is nil"},
+		{"operator heartbeat w/o signature", &Message{OperatorHeartbeat: &OperatorHeartbeat{OperatorPubKey: "pk"},
+			Type: NetworkMsg_OperatorHeartbeatType}, "operator heartbeat is not signed"},
+		{"valid control message", &Message{ControlMessage: &ControlMessage{Nonce: 1, Signature: []byte{1}},
+			Type: NetworkMsg_ControlType}, ""},
+		{"control message w/o content", &Message{Type: NetworkMsg_ControlType}, "control message is nil"},
+		{"control message w/o signature", &Message{ControlMessage: &ControlMessage{Nonce: 1},
+			Type: NetworkMsg_ControlType}, "control message is not signed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateMessage(test.msg)
+			if len(test.expectedError) > 0 {
+				require.EqualError(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateVersion(t *testing.T) {
+	require.NoError(t, ValidateVersion(MessageVersionV0))
+	require.NoError(t, ValidateVersion(CurrentMessageVersion))
+
+	err := ValidateVersion(MessageVersion(7))
+	require.True(t, errors.Is(err, ErrUnknownMessageVersion))
+	var versionErr *UnknownVersionError
+	require.True(t, errors.As(err, &versionErr))
+	require.Equal(t, MessageVersion(7), versionErr.Version)
+}
@@ -0,0 +1,48 @@
+package network
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+// MessageVersion is the version of the message envelope format
+type MessageVersion uint32
+
+const (
+	// MessageVersionV0 is the genesis envelope, messages without an explicit version are considered v0
+	MessageVersionV0 MessageVersion = 0
+	// CurrentMessageVersion is the latest version, the version of outgoing messages is declared by the active fork
+	CurrentMessageVersion = MessageVersionV0
+)
+
+// supportedMessageVersions are the versions that can be processed by this node
+var supportedMessageVersions = map[MessageVersion]bool{
+	MessageVersionV0: true,
+}
+
+// ErrUnknownMessageVersion is the base error of messages with an unknown version
+var ErrUnknownMessageVersion = errors.New("unknown message version")
+
+// UnknownVersionError is returned for messages with an unknown version,
+// errors.Is(err, ErrUnknownMessageVersion) can be used to check for it
+type UnknownVersionError struct {
+	Version MessageVersion
+}
+
+// Error implements error
+func (e *UnknownVersionError) Error() string {
+	return fmt.Sprintf("%s %d", ErrUnknownMessageVersion.Error(), e.Version)
+}
+
+// Is returns true for ErrUnknownMessageVersion
+func (e *UnknownVersionError) Is(target error) bool {
+	return target == ErrUnknownMessageVersion
+}
+
+// ValidateVersion returns an UnknownVersionError if the given version is not supported
+func ValidateVersion(version MessageVersion) error {
+	if !supportedMessageVersions[version] {
+		return &UnknownVersionError{Version: version}
+	}
+	return nil
+}
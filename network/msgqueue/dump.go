@@ -0,0 +1,77 @@
+package msgqueue
+
+import (
+	"encoding/json"
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// DumpRecord is a single message of a queue dump, dumps are written as a json record per line
+type DumpRecord struct {
+	// Time is the time (unix milliseconds) the message was added to the queue
+	Time    int64            `json:"time"`
+	Message *network.Message `json:"message"`
+}
+
+// Dumper writes the consensus messages that are added to queues, in order to replay them later (see ibft/replay).
+// a single dumper can be shared by multiple queues
+type Dumper struct {
+	lock sync.Mutex
+	enc  *json.Encoder
+}
+
+// NewDumper creates a dumper that writes to the given writer
+func NewDumper(w io.Writer) *Dumper {
+	return &Dumper{enc: json.NewEncoder(w)}
+}
+
+// NewFileDumper creates a dumper that appends to the given file
+func NewFileDumper(path string) (*Dumper, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open dump file")
+	}
+	return NewDumper(f), nil
+}
+
+// Dump writes the given message, messages w/o a signed message (e.g. sync requests) are skipped
+func (d *Dumper) Dump(msg *network.Message) error {
+	if msg == nil || msg.SignedMessage == nil {
+		return nil
+	}
+	record := DumpRecord{
+		Time: time.Now().UnixNano() / int64(time.Millisecond),
+		Message: &network.Message{
+			Version:       msg.Version,
+			SignedMessage: msg.SignedMessage,
+			Type:          msg.Type,
+		},
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.enc.Encode(&record)
+}
+
+// ReadDump reads the records of the given dump in order
+func ReadDump(r io.Reader, handler func(record *DumpRecord) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		record := new(DumpRecord)
+		if err := dec.Decode(record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "could not decode dump record")
+		}
+		if record.Message == nil {
+			continue
+		}
+		if err := handler(record); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package msgqueue
+
+import (
+	"bytes"
+	"github.com/bloxapp/ssv/network"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDumper(t *testing.T) {
+	buf := &bytes.Buffer{}
+	msgQ := NewWithOptions(Options{Dumper: NewDumper(buf)})
+	msgQ.AddMessage(newNetMsg([]byte{1, 2, 3, 4}, 1, 1, network.NetworkMsg_IBFTType))
+	msgQ.AddMessage(&network.Message{SyncMessage: &network.SyncMessage{}, Type: network.NetworkMsg_SyncType})
+	msgQ.AddMessage(newNetMsg([]byte{1, 2, 3, 4}, 2, 1, network.NetworkMsg_DecidedType))
+
+	var records []*DumpRecord
+	require.NoError(t, ReadDump(buf, func(record *DumpRecord) error {
+		records = append(records, record)
+		return nil
+	}))
+	// sync messages are not dumped
+	require.Len(t, records, 2)
+	require.Equal(t, network.NetworkMsg_IBFTType, records[0].Message.Type)
+	require.EqualValues(t, 1, records[0].Message.SignedMessage.Message.Round)
+	require.Equal(t, network.NetworkMsg_DecidedType, records[1].Message.Type)
+	require.EqualValues(t, 2, records[1].Message.SignedMessage.Message.Round)
+	require.Greater(t, records[0].Time, int64(0))
+}
@@ -2,13 +2,13 @@ package msgqueue
 
 import (
 	"encoding/hex"
-	"fmt"
 	"github.com/bloxapp/ssv/network"
+	"strconv"
 )
 
 // IBFTMessageIndexKey is the ibft index key
 func IBFTMessageIndexKey(lambda []byte, seqNumber uint64) string {
-	return fmt.Sprintf("lambda_%s_seqNumber_%d", hex.EncodeToString(lambda), seqNumber)
+	return "lambda_" + hex.EncodeToString(lambda) + "_seqNumber_" + strconv.FormatUint(seqNumber, 10)
 }
 
 func iBFTMessageIndex() IndexFunc {
@@ -31,7 +31,7 @@ func iBFTMessageIndex() IndexFunc {
 
 // SigRoundIndexKey is the SSV node signature collection index key
 func SigRoundIndexKey(lambda []byte, seqNumber uint64) string {
-	return fmt.Sprintf("sig_lambda_%s_seqNumber_%d", hex.EncodeToString(lambda), seqNumber)
+	return "sig_lambda_" + hex.EncodeToString(lambda) + "_seqNumber_" + strconv.FormatUint(seqNumber, 10)
 }
 func sigMessageIndex() IndexFunc {
 	return func(msg *network.Message) []string {
@@ -53,7 +53,7 @@ func sigMessageIndex() IndexFunc {
 
 // DecidedIndexKey is the ibft decisions index key
 func DecidedIndexKey(lambda []byte) string {
-	return fmt.Sprintf("decided_lambda_%s", hex.EncodeToString(lambda))
+	return "decided_lambda_" + hex.EncodeToString(lambda)
 }
 func decidedMessageIndex() IndexFunc {
 	return func(msg *network.Message) []string {
@@ -75,7 +75,7 @@ func decidedMessageIndex() IndexFunc {
 
 // SyncIndexKey is the ibft sync index key
 func SyncIndexKey(lambda []byte) string {
-	return fmt.Sprintf("sync_lambda_%s", hex.EncodeToString(lambda))
+	return "sync_lambda_" + hex.EncodeToString(lambda)
 }
 func syncMessageIndex() IndexFunc {
 	return func(msg *network.Message) []string {
@@ -219,3 +219,11 @@ func TestDecidedMessageIndex(t *testing.T) {
 	})
 
 }
+
+func BenchmarkIBFTMessageIndexKey(b *testing.B) {
+	lambda := []byte("a7d52a1f2c06b5cc2a0c7d6e7a55d1cbeb2a7f3a_ATTESTER")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = IBFTMessageIndexKey(lambda, uint64(i))
+	}
+}
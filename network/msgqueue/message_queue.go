@@ -4,10 +4,19 @@ import (
 	"github.com/bloxapp/ssv/network"
 	"github.com/google/uuid"
 	"github.com/patrickmn/go-cache"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	// DefaultTTL is the default time messages are kept in the queue
+	DefaultTTL = time.Minute * 10
+	// DefaultCleanupInterval is the default interval for removing expired messages
+	DefaultCleanupInterval = time.Minute * 11
+)
+
 // IndexFunc is the function that indexes messages to be later pulled by those indexes
 type IndexFunc func(msg *network.Message) []string
 
@@ -15,6 +24,17 @@ type messageContainer struct {
 	id      string
 	msg     *network.Message
 	indexes []string
+	added   time.Time
+}
+
+// Options holds the configuration of a MessageQueue
+type Options struct {
+	// TTL is the time messages are kept in the queue
+	TTL time.Duration
+	// CleanupInterval is the interval for removing expired messages
+	CleanupInterval time.Duration
+	// Dumper writes the added messages for later replay, disabled if nil
+	Dumper *Dumper
 }
 
 // MessageQueue is a broker of messages for the IBFT instance to process.
@@ -26,14 +46,30 @@ type MessageQueue struct {
 	indexFuncs  []IndexFunc
 	queue       *cache.Cache
 	allMessages *cache.Cache
+	ttl         time.Duration
+	expired     uint64
+	dumper      *Dumper
 }
 
-// New is the constructor of MessageQueue
+// New is the constructor of MessageQueue, using the default TTL and cleanup interval
 func New() *MessageQueue {
-	return &MessageQueue{
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions creates a new MessageQueue with the given options, missing values are set to defaults
+func NewWithOptions(opts Options) *MessageQueue {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = DefaultCleanupInterval
+	}
+	q := &MessageQueue{
 		msgMutex:    sync.RWMutex{},
-		queue:       cache.New(time.Minute*10, time.Minute*11),
-		allMessages: cache.New(time.Minute*10, time.Minute*11),
+		queue:       cache.New(opts.TTL, opts.CleanupInterval),
+		allMessages: cache.New(opts.TTL, opts.CleanupInterval),
+		ttl:         opts.TTL,
+		dumper:      opts.Dumper,
 		indexFuncs: []IndexFunc{
 			iBFTMessageIndex(),
 			sigMessageIndex(),
@@ -41,6 +77,21 @@ func New() *MessageQueue {
 			syncMessageIndex(),
 		},
 	}
+	q.allMessages.OnEvicted(q.onMessageEvicted)
+	return q
+}
+
+// ExpiredCount returns the number of messages that expired before they were processed
+func (q *MessageQueue) ExpiredCount() uint64 {
+	return atomic.LoadUint64(&q.expired)
+}
+
+// onMessageEvicted is called upon removal of messages, explicit deletions are ignored
+func (q *MessageQueue) onMessageEvicted(id string, raw interface{}) {
+	if msgContainer, ok := raw.(messageContainer); ok && time.Since(msgContainer.added) >= q.ttl {
+		atomic.AddUint64(&q.expired, 1)
+		metricsExpiredMessages.Inc()
+	}
 }
 
 // AddIndexFunc adds an index function that will be activated every new message the queue receives
@@ -51,11 +102,16 @@ func (q *MessageQueue) AddIndexFunc(f IndexFunc) {
 // AddMessage adds a message the queue based on the message round.
 // AddMessage is thread safe
 func (q *MessageQueue) AddMessage(msg *network.Message) {
+	if q.dumper != nil {
+		// dumping is best effort, it should never affect the flow of messages
+		_ = q.dumper.Dump(msg)
+	}
+
 	q.msgMutex.Lock()
 	defer q.msgMutex.Unlock()
 
 	// index msg
-	indexes := make([]string, 0)
+	indexes := make([]string, 0, len(q.indexFuncs))
 	for _, f := range q.indexFuncs {
 		indexes = append(indexes, f(msg)...)
 	}
@@ -65,6 +121,7 @@ func (q *MessageQueue) AddMessage(msg *network.Message) {
 		id:      uuid.New().String(),
 		msg:     msg,
 		indexes: indexes,
+		added:   time.Now(),
 	}
 
 	for _, idx := range indexes {
@@ -160,6 +217,43 @@ func (q *MessageQueue) deleteMessageFromAllIndexes(indexes []string, id string)
 	q.allMessages.Delete(id)
 }
 
+// IndexStats holds information about the messages of a single index
+type IndexStats struct {
+	Index            string
+	Count            int
+	OldestMessageAge time.Duration
+}
+
+// Stats returns information about all the active (non-empty) indexes, sorted by index
+func (q *MessageQueue) Stats() []IndexStats {
+	q.msgMutex.RLock()
+	defer q.msgMutex.RUnlock()
+
+	now := time.Now()
+	stats := make([]IndexStats, 0)
+	for idx, item := range q.queue.Items() {
+		msgContainers, ok := item.Object.([]messageContainer)
+		if !ok || len(msgContainers) == 0 {
+			continue
+		}
+		oldest := msgContainers[0].added
+		for _, c := range msgContainers {
+			if c.added.Before(oldest) {
+				oldest = c.added
+			}
+		}
+		stats = append(stats, IndexStats{
+			Index:            idx,
+			Count:            len(msgContainers),
+			OldestMessageAge: now.Sub(oldest),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Index < stats[j].Index
+	})
+	return stats
+}
+
 // PurgeIndexedMessages will delete all indexed messages for the given index
 func (q *MessageQueue) PurgeIndexedMessages(index string) {
 	q.msgMutex.Lock()
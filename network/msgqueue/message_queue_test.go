@@ -5,6 +5,7 @@ import (
 	"github.com/bloxapp/ssv/network"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestMessageQueue_PurgeAllIndexedMessages(t *testing.T) {
@@ -118,3 +119,58 @@ func newNetMsg(lambda []byte, round, seq uint64, t network.NetworkMsg) *network.
 		Type: t,
 	}
 }
+
+func TestMessageQueue_TTL(t *testing.T) {
+	msgQ := NewWithOptions(Options{
+		TTL:             time.Millisecond * 50,
+		CleanupInterval: time.Millisecond * 10,
+	})
+	msgQ.AddMessage(newNetMsg([]byte{1, 2, 3, 4}, 1, 1, network.NetworkMsg_IBFTType))
+	msgQ.AddMessage(newNetMsg([]byte{1, 2, 3, 4}, 2, 1, network.NetworkMsg_IBFTType))
+	require.Equal(t, 2, msgQ.MsgCount(IBFTMessageIndexKey([]byte{1, 2, 3, 4}, 1)))
+
+	// popped messages are not counted as expired
+	require.NotNil(t, msgQ.PopMessage(IBFTMessageIndexKey([]byte{1, 2, 3, 4}, 1)))
+
+	time.Sleep(time.Millisecond * 150)
+	require.Equal(t, 0, msgQ.MsgCount(IBFTMessageIndexKey([]byte{1, 2, 3, 4}, 1)))
+	require.EqualValues(t, 1, msgQ.ExpiredCount())
+}
+
+func TestMessageQueue_DefaultOptions(t *testing.T) {
+	msgQ := NewWithOptions(Options{TTL: -1})
+	require.Equal(t, DefaultTTL, msgQ.ttl)
+}
+
+func TestMessageQueue_Stats(t *testing.T) {
+	msgQ := New()
+	require.Len(t, msgQ.Stats(), 0)
+
+	msgQ.AddMessage(newNetMsg([]byte{1, 2, 3, 4}, 1, 1, network.NetworkMsg_IBFTType))
+	time.Sleep(time.Millisecond * 10)
+	msgQ.AddMessage(newNetMsg([]byte{1, 2, 3, 4}, 2, 1, network.NetworkMsg_IBFTType))
+	msgQ.AddMessage(newNetMsg([]byte{1, 2, 3, 4}, 1, 1, network.NetworkMsg_SignatureType))
+
+	stats := msgQ.Stats()
+	require.Len(t, stats, 2)
+	require.Equal(t, "lambda_01020304_seqNumber_1", stats[0].Index)
+	require.Equal(t, 2, stats[0].Count)
+	require.GreaterOrEqual(t, stats[0].OldestMessageAge, time.Millisecond*10)
+	require.Equal(t, "sig_lambda_01020304_seqNumber_1", stats[1].Index)
+	require.Equal(t, 1, stats[1].Count)
+
+	// empty indexes are not listed
+	msgQ.PurgeIndexedMessages(SigRoundIndexKey([]byte{1, 2, 3, 4}, 1))
+	require.Len(t, msgQ.Stats(), 1)
+}
+
+func BenchmarkMessageQueue_AddMessage(b *testing.B) {
+	msgQ := New()
+	msg := newNetMsg([]byte{1, 2, 3, 4}, 1, 1, network.NetworkMsg_IBFTType)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msgQ.AddMessage(msg)
+		msgQ.PopMessage(IBFTMessageIndexKey([]byte{1, 2, 3, 4}, 1))
+	}
+}
@@ -0,0 +1,20 @@
+package msgqueue
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsExpiredMessages = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv:network:msgqueue:expired_messages",
+		Help: "Count messages that expired in the message queues before they were processed",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsExpiredMessages); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
@@ -9,16 +9,23 @@ import (
 
 // Message is a container for network messages.
 type Message struct {
+	// Version is the version of the envelope, omitted for v0 to stay compatible with older peers
+	Version       MessageVersion `json:",omitempty"`
 	SignedMessage *proto.SignedMessage
 	SyncMessage   *SyncMessage
 	Stream        SyncStream
 	Type          NetworkMsg
+	// OperatorHeartbeat is set only for heartbeat messages, omitted otherwise to stay compatible with older peers
+	OperatorHeartbeat *OperatorHeartbeat `json:",omitempty"`
+	// ControlMessage is set only for control messages, omitted otherwise to stay compatible with older peers
+	ControlMessage *ControlMessage `json:",omitempty"`
 }
 
 // SyncChanObj is a wrapper object for streaming of sync messages
 type SyncChanObj struct {
-	Msg    *SyncMessage
-	Stream SyncStream
+	Msg     *SyncMessage
+	Stream  SyncStream
+	Version MessageVersion
 }
 
 // SyncStream is a interface for all stream related functions for the sync process.
@@ -62,6 +69,8 @@ type Reader interface {
 	AllPeers(validatorPk []byte) ([]string, error)
 	// SubscribeToMainTopic subscribes to main topic
 	SubscribeToMainTopic() error
+	// SubscribeToDecidedTopics subscribes to the decided topics of all validators
+	SubscribeToDecidedTopics() error
 	// MaxBatch returns the maximum batch size for network responses
 	MaxBatch() uint64
 }
@@ -74,12 +83,19 @@ type Broadcaster interface {
 	BroadcastSignature(topicName []byte, msg *proto.SignedMessage) error
 	// BroadcastDecided broadcasts a decided instance with collected signatures
 	BroadcastDecided(topicName []byte, msg *proto.SignedMessage) error
-	// BroadcastMainTopic broadcasts the given msg on main channel
+	// BroadcastMainTopic broadcasts the given decided msg on the decided topic of its validator
 	BroadcastMainTopic(msg *proto.SignedMessage) error
 	// MaxBatch returns the maximum batch size for network responses
 	MaxBatch() uint64
 }
 
+// DeadlineBroadcaster is implemented by networks that retry failed broadcasts,
+// the retries are bounded by the given deadline (e.g. the deadline of the duty)
+type DeadlineBroadcaster interface {
+	// BroadcastWithDeadline propagates a signed message to all peers, retrying until the given deadline
+	BroadcastWithDeadline(topicName []byte, msg *proto.SignedMessage, deadline time.Time) error
+}
+
 // Syncer represents the needed functionality for performing sync
 type Syncer interface {
 	// GetHighestDecidedInstance sends a highest decided request to peers and returns answers.
@@ -97,6 +113,139 @@ type Syncer interface {
 	RespondToLastChangeRoundMsg(stream SyncStream, msg *SyncMessage) error
 }
 
+// DecidedHistoryRoots is implemented by networks that support requesting the decided history roots of peers,
+// nodes can compare a single root in order to detect divergence instead of exchanging decided ranges
+type DecidedHistoryRoots interface {
+	// GetDecidedHistoryRoot asks the given peer for the merkle root over its decided history
+	GetDecidedHistoryRoot(peerStr string, msg *SyncMessage) (*SyncMessage, error)
+	// RespondToDecidedHistoryRoot responds to a GetDecidedHistoryRoot
+	RespondToDecidedHistoryRoot(stream SyncStream, msg *SyncMessage) error
+}
+
+// ValidatorSubscriptions is implemented by networks that track the live subscriptions of validator topics
+type ValidatorSubscriptions interface {
+	// IsSubscribedToValidatorNetwork returns true if the topic of the given validator has a live subscription
+	IsSubscribedToValidatorNetwork(validatorPk *bls.PublicKey) bool
+}
+
+// PeersLatency is implemented by networks that measure the round-trip time of sync requests
+type PeersLatency interface {
+	// PeerLatency returns the average round-trip time of the given peer, false if unknown
+	PeerLatency(peerStr string) (time.Duration, bool)
+}
+
+// OperatorPeers is implemented by networks that map operators to their peers
+type OperatorPeers interface {
+	// OperatorPeer returns the peer of the operator with the given public key, false if unknown
+	OperatorPeer(operatorPubKey string) (string, bool)
+}
+
+// CommitteeResolver returns the public keys of the committee operators of the validator of the given identifier (lambda),
+// false if the validator is unknown
+type CommitteeResolver func(identifier []byte) ([]string, bool)
+
+// SyncAuthorizer is implemented by networks that restrict which peers can sync from the node
+type SyncAuthorizer interface {
+	// UseCommitteeResolver sets the resolver of committees, used to authorize sync requests of committee members
+	UseCommitteeResolver(resolver CommitteeResolver)
+}
+
+// PeersVersions is implemented by networks that index the build information of connected peers
+type PeersVersions interface {
+	// PeersVersions returns the build information of the connected peers that were indexed
+	PeersVersions() []PeerVersion
+}
+
+// PeerVersion is the build information of a peer, as announced in its user agent
+type PeerVersion struct {
+	PeerID  string `json:"peerId"`
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	ForkID  string `json:"forkId,omitempty"`
+}
+
+// PeersRecords is implemented by networks that persist the records of the peers that were indexed
+type PeersRecords interface {
+	// PeersRecords returns the records of the peers that were seen since the given time
+	PeersRecords(since time.Time) []PeerRecord
+}
+
+// PeerRecord is the persisted identification data of a peer
+type PeerRecord struct {
+	PeerID    string `json:"peerId"`
+	UserAgent string `json:"userAgent"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	ForkID    string `json:"forkId,omitempty"`
+	// Operator is the hash of the operator public key, empty for nodes w/o operator key
+	Operator string `json:"operator,omitempty"`
+	// IP is the remote address of the last connection
+	IP string `json:"ip,omitempty"`
+	// LastSeen is in unix milliseconds
+	LastSeen int64 `json:"lastSeen"`
+	// Score is the last gossipsub score of the peer, available only if score inspection is enabled
+	Score float64 `json:"score"`
+	// Connected is true if the peer is currently connected
+	Connected bool `json:"connected"`
+}
+
+// GossipInspector is implemented by networks that expose gossipsub scores and mesh state for diagnostics
+type GossipInspector interface {
+	// GossipStats returns the gossip stats of the known peers, false if inspection is disabled
+	GossipStats() ([]PeerGossipStats, bool)
+}
+
+// PeerGossipStats holds the gossipsub score and mesh state of a peer
+type PeerGossipStats struct {
+	PeerID             string             `json:"peerId"`
+	UserAgent          string             `json:"userAgent,omitempty"`
+	Score              float64            `json:"score"`
+	AppSpecificScore   float64            `json:"appSpecificScore"`
+	IPColocationFactor float64            `json:"ipColocationFactor"`
+	BehaviourPenalty   float64            `json:"behaviourPenalty"`
+	Topics             []TopicGossipStats `json:"topics"`
+}
+
+// TopicGossipStats holds the gossipsub score counters and mesh state of a peer in a topic
+type TopicGossipStats struct {
+	Topic  string `json:"topic"`
+	InMesh bool   `json:"inMesh"`
+	// TimeInMesh is in milliseconds
+	TimeInMesh               int64   `json:"timeInMesh"`
+	FirstMessageDeliveries   float64 `json:"firstMessageDeliveries"`
+	MeshMessageDeliveries    float64 `json:"meshMessageDeliveries"`
+	InvalidMessageDeliveries float64 `json:"invalidMessageDeliveries"`
+	Grafts                   uint64  `json:"grafts"`
+	Prunes                   uint64  `json:"prunes"`
+}
+
+// MessageAuditor is implemented by networks that sample raw inbound messages, in order to investigate incidents after the fact
+type MessageAuditor interface {
+	// AuditedMessages returns the sampled messages that match the given filter, false if auditing is disabled
+	AuditedMessages(filter AuditFilter) ([]*AuditRecord, bool, error)
+}
+
+// AuditRecord is a raw inbound message that was sampled for auditing
+type AuditRecord struct {
+	// Time is in unix milliseconds
+	Time   int64  `json:"time"`
+	PeerID string `json:"peerId"`
+	// Topic is the pubsub topic of the message, or "direct" for direct messages
+	Topic string `json:"topic"`
+	// Data is the raw (undecoded) message
+	Data []byte `json:"data"`
+}
+
+// AuditFilter filters audit records, empty fields match all records
+type AuditFilter struct {
+	From   time.Time
+	To     time.Time
+	PeerID string
+	Topic  string
+	// Limit is the max number of (oldest) records to return, 0 means no limit
+	Limit int
+}
+
 // Network represents the behavior of the network
 type Network interface {
 	Reader
@@ -32,6 +32,12 @@ const (
 	NetworkMsg_SignatureType NetworkMsg = 2
 	// SyncType is an SSV iBFT specific message that a node uses to sync up with other nodes
 	NetworkMsg_SyncType NetworkMsg = 3
+	// HighestDecidedType is an announcement of the highest decided instance of a validator, broadcasted periodically on the decided topic of the validator
+	NetworkMsg_HighestDecidedType NetworkMsg = 4
+	// OperatorHeartbeatType is a signed liveness announcement of an operator, broadcasted periodically on the main topic
+	NetworkMsg_OperatorHeartbeatType NetworkMsg = 5
+	// ControlType is a control message that is signed by the admin key of the exporters, broadcasted on the main topic
+	NetworkMsg_ControlType NetworkMsg = 6
 )
 
 var NetworkMsg_name = map[int32]string{
@@ -39,13 +45,19 @@ var NetworkMsg_name = map[int32]string{
 	1: "DecidedType",
 	2: "SignatureType",
 	3: "SyncType",
+	4: "HighestDecidedType",
+	5: "OperatorHeartbeatType",
+	6: "ControlType",
 }
 
 var NetworkMsg_value = map[string]int32{
-	"IBFTType":      0,
-	"DecidedType":   1,
-	"SignatureType": 2,
-	"SyncType":      3,
+	"IBFTType":              0,
+	"DecidedType":           1,
+	"SignatureType":         2,
+	"SyncType":              3,
+	"HighestDecidedType":    4,
+	"OperatorHeartbeatType": 5,
+	"ControlType":           6,
 }
 
 func (x NetworkMsg) String() string {
@@ -65,18 +77,22 @@ const (
 	Sync_GetInstanceRange Sync = 1
 	// GetCurrentInstance is a request from peers to return their current running instance details
 	Sync_GetLatestChangeRound Sync = 2
+	// GetDecidedHistoryRoot is a request from peers to return the merkle root over their decided history
+	Sync_GetDecidedHistoryRoot Sync = 3
 )
 
 var Sync_name = map[int32]string{
 	0: "GetHighestType",
 	1: "GetInstanceRange",
 	2: "GetLatestChangeRound",
+	3: "GetDecidedHistoryRoot",
 }
 
 var Sync_value = map[string]int32{
-	"GetHighestType":       0,
-	"GetInstanceRange":     1,
-	"GetLatestChangeRound": 2,
+	"GetHighestType":        0,
+	"GetInstanceRange":      1,
+	"GetLatestChangeRound":  2,
+	"GetDecidedHistoryRoot": 3,
 }
 
 func (x Sync) String() string {
@@ -94,6 +110,10 @@ type SyncMessage struct {
 	Lambda               []byte                  `protobuf:"bytes,4,opt,name=Lambda,proto3" json:"Lambda,omitempty"`
 	Type                 Sync                    `protobuf:"varint,5,opt,name=Type,proto3,enum=network.Sync" json:"Type,omitempty"`
 	Error                string                  `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	MaxBatch             uint64                  `protobuf:"varint,7,opt,name=MaxBatch,proto3" json:"MaxBatch,omitempty"`
+	PageToken            []byte                  `protobuf:"bytes,8,opt,name=PageToken,proto3" json:"PageToken,omitempty"`
+	Signature            []byte                  `protobuf:"bytes,9,opt,name=Signature,proto3" json:"Signature,omitempty"`
+	HistoryRoot          []byte                  `protobuf:"bytes,10,opt,name=HistoryRoot,proto3" json:"HistoryRoot,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
 	XXX_unrecognized     []byte                  `json:"-"`
 	XXX_sizecache        int32                   `json:"-"`
@@ -166,6 +186,34 @@ func (m *SyncMessage) GetError() string {
 	return ""
 }
 
+func (m *SyncMessage) GetMaxBatch() uint64 {
+	if m != nil {
+		return m.MaxBatch
+	}
+	return 0
+}
+
+func (m *SyncMessage) GetPageToken() []byte {
+	if m != nil {
+		return m.PageToken
+	}
+	return nil
+}
+
+func (m *SyncMessage) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SyncMessage) GetHistoryRoot() []byte {
+	if m != nil {
+		return m.HistoryRoot
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("network.NetworkMsg", NetworkMsg_name, NetworkMsg_value)
 	proto.RegisterEnum("network.Sync", Sync_name, Sync_value)
@@ -177,25 +225,31 @@ func init() {
 }
 
 var fileDescriptor_a755f4b722170306 = []byte{
-	// 307 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0x4d, 0x4f, 0xc2, 0x40,
-	0x10, 0x86, 0x2d, 0x2d, 0x88, 0xc3, 0x87, 0x38, 0x69, 0xcc, 0xc6, 0x83, 0xa9, 0x9e, 0x1a, 0x0e,
-	0x35, 0xc1, 0xab, 0x27, 0x24, 0x20, 0x06, 0x8c, 0x59, 0x38, 0x79, 0x31, 0x0b, 0x9d, 0x14, 0x62,
-	0xba, 0x25, 0xbb, 0x4b, 0x0c, 0xff, 0xd3, 0x1f, 0x64, 0x76, 0xd9, 0xc4, 0x8f, 0xe3, 0xf3, 0xcc,
-	0xdb, 0x99, 0xbe, 0x0b, 0x28, 0xc9, 0x7c, 0x56, 0xea, 0xe3, 0xbd, 0xd4, 0x85, 0xce, 0x76, 0xaa,
-	0x32, 0x15, 0x9e, 0x7a, 0x77, 0x05, 0x3f, 0xf2, 0xf6, 0x2b, 0x80, 0xd6, 0xe2, 0x20, 0xd7, 0x73,
-	0xd2, 0x5a, 0x14, 0x84, 0x0f, 0xd0, 0x5d, 0x6c, 0x0b, 0x49, 0xb9, 0x17, 0x9a, 0x05, 0x49, 0x98,
-	0xb6, 0x06, 0xf1, 0x31, 0x9f, 0xfd, 0x19, 0xf2, 0x7f, 0x59, 0xbc, 0x06, 0x18, 0xab, 0xaa, 0x7c,
-	0x25, 0x52, 0xd3, 0x11, 0xab, 0x25, 0x41, 0x7a, 0xc6, 0x7f, 0x19, 0xbc, 0x84, 0xc6, 0x4e, 0x28,
-	0x51, 0x6a, 0x16, 0x26, 0x61, 0x1a, 0x71, 0x4f, 0xd6, 0xcf, 0x44, 0xb9, 0xca, 0x05, 0x8b, 0x92,
-	0x20, 0x6d, 0x73, 0x4f, 0x78, 0x03, 0xd1, 0xf2, 0xb0, 0x23, 0x56, 0x4f, 0x82, 0xb4, 0x3b, 0xe8,
-	0x64, 0xbe, 0x41, 0x66, 0xff, 0x98, 0xbb, 0x11, 0xc6, 0x50, 0x27, 0xa5, 0x2a, 0xc5, 0x1a, 0xee,
-	0xda, 0x11, 0xfa, 0x33, 0x80, 0x97, 0x63, 0x76, 0xae, 0x0b, 0x6c, 0x43, 0x73, 0x3a, 0x1c, 0x2f,
-	0x6d, 0xbe, 0x77, 0x82, 0xe7, 0xd0, 0x1a, 0xd1, 0x7a, 0x9b, 0x53, 0xee, 0x44, 0x80, 0x17, 0xd0,
-	0xb1, 0x3d, 0x84, 0xd9, 0x2b, 0x72, 0xaa, 0x66, 0xbf, 0xb0, 0x37, 0x1c, 0x85, 0xfd, 0x67, 0x88,
-	0x2c, 0x21, 0x42, 0x77, 0x42, 0xe6, 0x69, 0x5b, 0x6c, 0x48, 0x1b, 0xbf, 0x2d, 0x86, 0xde, 0x84,
-	0xcc, 0x54, 0x6a, 0x23, 0xe4, 0x9a, 0xb8, 0x90, 0x85, 0x5d, 0xc9, 0x20, 0x9e, 0x90, 0x99, 0x09,
-	0x43, 0xda, 0x3c, 0x6e, 0xac, 0xe4, 0xd5, 0x5e, 0xe6, 0xbd, 0xda, 0x10, 0xde, 0x9a, 0x77, 0xbe,
-	0xc6, 0xaa, 0xe1, 0xde, 0xf4, 0xfe, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x6c, 0xbd, 0xf0, 0xb7, 0xae,
-	0x01, 0x00, 0x00,
+	// 410 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0x41, 0x6f, 0x1a, 0x31,
+	0x10, 0x85, 0xbb, 0xec, 0x42, 0x60, 0x48, 0x28, 0x1d, 0xd1, 0xc8, 0x8d, 0xaa, 0x6a, 0xdb, 0xd3,
+	0x2a, 0x07, 0x2a, 0xa5, 0xd7, 0x9e, 0x48, 0x14, 0x40, 0x0a, 0x6d, 0xe4, 0x70, 0xea, 0xa5, 0x32,
+	0xec, 0x68, 0x41, 0x29, 0x36, 0xb2, 0x27, 0x6a, 0xf9, 0x17, 0xfd, 0x65, 0xfd, 0x4d, 0x95, 0x8d,
+	0x0b, 0xdb, 0x1c, 0xdf, 0xf7, 0x3c, 0x33, 0xef, 0x69, 0x17, 0x50, 0x13, 0xff, 0x34, 0xf6, 0xf1,
+	0xfb, 0xc6, 0x55, 0x6e, 0xb8, 0xb5, 0x86, 0x0d, 0x9e, 0x44, 0x76, 0x01, 0x47, 0xf8, 0xe1, 0x4f,
+	0x03, 0xba, 0x0f, 0x3b, 0xbd, 0x9c, 0x91, 0x73, 0xaa, 0x22, 0xfc, 0x0c, 0xbd, 0x87, 0x75, 0xa5,
+	0xa9, 0x8c, 0xc0, 0x89, 0x24, 0x4f, 0x8b, 0xee, 0xd5, 0x60, 0xff, 0x7e, 0xf8, 0x9f, 0x29, 0x9f,
+	0xbd, 0xc5, 0x77, 0x00, 0xb7, 0xd6, 0x6c, 0xee, 0x89, 0xec, 0xf4, 0x46, 0x34, 0xf2, 0xa4, 0xe8,
+	0xc8, 0x1a, 0xc1, 0x73, 0x68, 0x6d, 0x95, 0x55, 0x1b, 0x27, 0xd2, 0x3c, 0x2d, 0x32, 0x19, 0x95,
+	0xe7, 0x77, 0x6a, 0xb3, 0x28, 0x95, 0xc8, 0xf2, 0xa4, 0x38, 0x95, 0x51, 0xe1, 0x7b, 0xc8, 0xe6,
+	0xbb, 0x2d, 0x89, 0x66, 0x9e, 0x14, 0xbd, 0xab, 0xb3, 0x61, 0x6c, 0x30, 0xf4, 0x89, 0x65, 0xb0,
+	0x70, 0x00, 0x4d, 0xb2, 0xd6, 0x58, 0xd1, 0x0a, 0xd7, 0xf6, 0x02, 0x2f, 0xa0, 0x3d, 0x53, 0xbf,
+	0x46, 0x8a, 0x97, 0x2b, 0x71, 0x92, 0x27, 0x45, 0x26, 0x0f, 0x1a, 0xdf, 0x42, 0xe7, 0x5e, 0x55,
+	0x34, 0x37, 0x8f, 0xa4, 0x45, 0x3b, 0xdc, 0x3b, 0x02, 0xef, 0xfa, 0x52, 0x8a, 0x9f, 0x2c, 0x89,
+	0xce, 0xde, 0x3d, 0x00, 0xcc, 0xa1, 0x3b, 0x59, 0x3b, 0x36, 0x76, 0x27, 0x8d, 0x61, 0x01, 0xc1,
+	0xaf, 0xa3, 0xcb, 0xdf, 0x09, 0xc0, 0x97, 0x7d, 0xcc, 0x99, 0xab, 0xf0, 0x14, 0xda, 0xd3, 0xd1,
+	0xed, 0xdc, 0x47, 0xed, 0xbf, 0xc0, 0x97, 0xd0, 0xbd, 0xa1, 0xe5, 0xba, 0xa4, 0x32, 0x80, 0x04,
+	0x5f, 0xc1, 0xd9, 0x61, 0x79, 0x40, 0x0d, 0x3f, 0xe1, 0xeb, 0x05, 0x95, 0xe2, 0x39, 0xe0, 0x64,
+	0x5d, 0xad, 0xc8, 0x71, 0x7d, 0x30, 0xc3, 0x37, 0xf0, 0xfa, 0xeb, 0x96, 0xac, 0x62, 0x63, 0x27,
+	0xa4, 0x2c, 0x2f, 0x48, 0x71, 0xb0, 0x9a, 0xfe, 0xc8, 0xb5, 0xd1, 0x6c, 0xcd, 0x8f, 0x00, 0x5a,
+	0x97, 0x04, 0x99, 0xdf, 0x88, 0x08, 0xbd, 0x31, 0x71, 0x5c, 0x17, 0x13, 0x0d, 0xa0, 0x3f, 0x26,
+	0x9e, 0x6a, 0xc7, 0x4a, 0x2f, 0x49, 0x2a, 0x5d, 0xf9, 0x58, 0x02, 0x06, 0x63, 0xe2, 0x3b, 0xc5,
+	0xe4, 0xf8, 0x7a, 0xe5, 0xa1, 0x34, 0x4f, 0xba, 0xec, 0x37, 0xfc, 0xdd, 0x31, 0xfd, 0xcb, 0x52,
+	0xeb, 0xdd, 0x4f, 0x47, 0xf0, 0xad, 0xfd, 0x31, 0x7e, 0xa0, 0x45, 0x2b, 0xfc, 0x2d, 0x9f, 0xfe,
+	0x0e, 0x00, 0xa0, 0x69, 0x44, 0xb4, 0x88, 0x02, 0x00, 0x00,
 }
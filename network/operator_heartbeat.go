@@ -0,0 +1,41 @@
+package network
+
+// OperatorHeartbeat is broadcasted periodically by operators on the main topic,
+// exporters aggregate heartbeats into a liveness view of the operators
+type OperatorHeartbeat struct {
+	// OperatorPubKey is the operator public key (base64) of the sender
+	OperatorPubKey string `json:"operatorPubKey"`
+	// Version is the build version of the sender
+	Version string `json:"version"`
+	// Commit is the commit hash of the sender's build, omitted if unknown
+	Commit string `json:"commit,omitempty"`
+	// ForkID is the network fork of the sender, omitted if unknown
+	ForkID string `json:"forkId,omitempty"`
+	// Timestamp is the unix time (milliseconds) of the heartbeat
+	Timestamp int64 `json:"timestamp"`
+	// Resources is the resource usage of the sender, omitted unless resource reporting is enabled
+	Resources *ResourceUsage `json:"resources,omitempty"`
+	// Signature is the signature of the sender's operator key on the heartbeat
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// ResourceUsage is a sample of the resources that are used by an operator node
+type ResourceUsage struct {
+	// CPUPercent is the cpu usage of the process since the previous sample, 100 is a single fully used core
+	CPUPercent float64 `json:"cpuPercent"`
+	// HeapBytes is the size of the allocated heap objects
+	HeapBytes uint64 `json:"heapBytes"`
+	// SysBytes is the total memory that was obtained from the OS
+	SysBytes   uint64 `json:"sysBytes"`
+	Goroutines int    `json:"goroutines"`
+	// DBSizeBytes is the size of the db on disk, omitted if unknown
+	DBSizeBytes int64 `json:"dbSizeBytes,omitempty"`
+}
+
+// OperatorHeartbeats is the interface for broadcasting and receiving operator heartbeats on the main topic
+type OperatorHeartbeats interface {
+	// BroadcastOperatorHeartbeat broadcasts the given heartbeat on the main topic
+	BroadcastOperatorHeartbeat(hb *OperatorHeartbeat) error
+	// ReceivedOperatorHeartbeatChan returns the channel for operator heartbeats
+	ReceivedOperatorHeartbeatChan() <-chan *OperatorHeartbeat
+}
@@ -7,9 +7,9 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
-	"github.com/prysmaticlabs/prysm/network"
 	"go.uber.org/zap"
 	"net"
+	"sort"
 	"time"
 )
 
@@ -38,13 +38,52 @@ func parseENRs(enrs []string, enforceTCP bool) ([]*enode.Node, error) {
 	return nodes, nil
 }
 
-// ipAddr returns the external IP address
+// ipAddr returns the external IP address, i.e. the first address (IPv4 preferred) of an active
+// non-loopback interface, or localhost if no such address was found
 func ipAddr() (net.IP, error) {
-	ip, err := network.ExternalIP()
+	ips, err := externalIPs()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get IPv4 address")
 	}
-	return net.ParseIP(ip), nil
+	if len(ips) == 0 {
+		return net.IPv4(127, 0, 0, 1), nil
+	}
+	return ips[0], nil
+}
+
+// externalIPs returns the addresses of active non-loopback interfaces, IPv4 addresses come first
+func externalIPs() ([]net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			var ip net.IP
+			switch v := addr.(type) {
+			case *net.IPNet:
+				ip = v.IP
+			case *net.IPAddr:
+				ip = v.IP
+			}
+			if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				continue
+			}
+			ips = append(ips, ip)
+		}
+	}
+	sort.SliceStable(ips, func(i, j int) bool {
+		return ips[i].To4() != nil && ips[j].To4() == nil
+	})
+	return ips, nil
 }
 
 // checkAddress checks that some address is accessible and returns error accordingly
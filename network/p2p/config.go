@@ -4,15 +4,17 @@ import (
 	"crypto/ecdsa"
 	"crypto/rsa"
 	"github.com/bloxapp/ssv/network/forks"
+	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"strings"
 	"time"
 )
 
 // Config - describe the config options for p2p network
 type Config struct {
 	// yaml/env arguments
-	Enr              string        `yaml:"Enr" env:"ENR_KEY" env-description:"enr used in discovery" env-default:""`
+	Enr              string        `yaml:"Enr" env:"ENR_KEY" env-description:"comma separated bootnodes ENRs used in discovery, defaults to the bootnodes of the selected network" env-default:""`
 	DiscoveryType    string        `yaml:"DiscoveryType" env:"DISCOVERY_TYPE_KEY" env-description:"Method to use in discovery" env-default:"discv5"`
 	TCPPort          int           `yaml:"TcpPort" env:"TCP_PORT" env-default:"13000"`
 	UDPPort          int           `yaml:"UdpPort" env:"UDP_PORT" env-default:"12000"`
@@ -23,11 +25,48 @@ type Config struct {
 	PubSubTraceOut   string        `yaml:"PubSubTraceOut" env:"PUBSUB_TRACE_OUT" env-description:"File path to hold collected pubsub traces"`
 	//PubSubTracer     string        `yaml:"PubSubTracer" env:"PUBSUB_TRACER" env-description:"A remote tracer that collects pubsub traces"`
 
+	PubSubTraceFormat   string        `yaml:"PubSubTraceFormat" env:"PUBSUB_TRACE_FORMAT" env-default:"pb" env-description:"Format of pubsub traces: pb or json"`
+	PubSubTraceMaxSize  int64         `yaml:"PubSubTraceMaxSize" env:"PUBSUB_TRACE_MAX_SIZE" env-default:"104857600" env-description:"Max size in bytes of a pubsub trace file before it gets rotated (0 disables)"`
+	PubSubTraceRotation time.Duration `yaml:"PubSubTraceRotation" env:"PUBSUB_TRACE_ROTATION" env-default:"1h" env-description:"Max age of a pubsub trace file before it gets rotated (0 disables)"`
+
+	PubSubScoreInspect bool `yaml:"PubSubScoreInspect" env:"PUBSUB_SCORE_INSPECT" env-description:"A boolean flag to collect gossipsub peer scores and mesh stats for diagnostics, peers are not penalized"`
+
 	NetworkTrace bool `yaml:"NetworkTrace" env:"NETWORK_TRACE" env-description:"A boolean flag to turn on network debugging"`
 
+	MsgRateLimit     int           `yaml:"MsgRateLimit" env:"P2P_MSG_RATE_LIMIT" env-default:"0" env-description:"max messages per second authored by a single peer on a validator topic, peers that exceed it are grey-listed on that topic (0 disables)"`
+	GreyListDuration time.Duration `yaml:"GreyListDuration" env:"P2P_GREY_LIST_DURATION" env-default:"10m" env-description:"how long a peer that exceeded the message rate limit of a topic stays grey-listed on it"`
+
+	MaxSyncStreams        int `yaml:"MaxSyncStreams" env:"P2P_MAX_SYNC_STREAMS" env-default:"64" env-description:"max concurrent outbound sync requests, further requests are queued (0 disables)"`
+	MaxSyncStreamsPerPeer int `yaml:"MaxSyncStreamsPerPeer" env:"P2P_MAX_SYNC_STREAMS_PER_PEER" env-default:"4" env-description:"max concurrent outbound sync requests to a single peer, further requests are queued (0 disables)"`
+
+	MaxPubSubMsgSize int `yaml:"MaxPubSubMsgSize" env:"P2P_MAX_PUBSUB_MSG_SIZE" env-default:"262144" env-description:"max size in bytes of inbound pubsub and direct messages, larger messages are rejected before decoding and their senders are penalized (0 disables)"`
+	MaxSyncMsgSize   int `yaml:"MaxSyncMsgSize" env:"P2P_MAX_SYNC_MSG_SIZE" env-default:"2097152" env-description:"max size in bytes of inbound sync requests and responses, larger payloads are rejected before decoding and their senders are penalized (0 disables)"`
+
+	DirectMessaging bool `yaml:"DirectMessaging" env:"P2P_DIRECT_MESSAGING" env-description:"A boolean flag to send consensus messages also directly to the peers of the validator topic, in addition to gossip"`
+
+	BroadcastRetryWindow  time.Duration `yaml:"BroadcastRetryWindow" env:"P2P_BROADCAST_RETRY_WINDOW" env-default:"3s" env-description:"how long a failed broadcast is retried in the background, re-joining the topic if needed (0 disables retries), bounded by the deadline of the duty"`
+	BroadcastRetryBackoff time.Duration `yaml:"BroadcastRetryBackoff" env:"P2P_BROADCAST_RETRY_BACKOFF" env-default:"100ms" env-description:"initial backoff between broadcast attempts, doubled after each attempt up to 1s"`
+	BroadcastMinMeshPeers int           `yaml:"BroadcastMinMeshPeers" env:"P2P_BROADCAST_MIN_MESH_PEERS" env-default:"1" env-description:"min peers in the local mesh of a validator topic before broadcasting consensus messages, the broadcast is retried until reached (0 requires only topic peers)"`
+
+	AuditSampleRate float64       `yaml:"AuditSampleRate" env:"P2P_AUDIT_SAMPLE_RATE" env-default:"0" env-description:"fraction (0-1) of raw inbound messages that are stored with their peer, topic and time for auditing (0 disables)"`
+	AuditDir        string        `yaml:"AuditDir" env:"P2P_AUDIT_DIR" env-default:"./data/audit" env-description:"directory of the audited messages"`
+	AuditRetention  time.Duration `yaml:"AuditRetention" env:"P2P_AUDIT_RETENTION" env-default:"24h" env-description:"how long audited messages are kept"`
+	AuditMaxSize    int64         `yaml:"AuditMaxSize" env:"P2P_AUDIT_MAX_SIZE" env-default:"1073741824" env-description:"max size in bytes of the audited messages, the oldest messages are removed once exceeded (0 disables)"`
+
+	DNSDiscoveryURLs []string `yaml:"DNSDiscoveryURLs" env:"P2P_DNS_DISCOVERY_URLS" env-description:"comma separated enrtree:// URLs of signed DNS node lists (EIP-1459), used alongside discv5"`
+
+	ExternalIPInterval time.Duration `yaml:"ExternalIPInterval" env:"P2P_EXTERNAL_IP_INTERVAL" env-default:"5m" env-description:"interval of external IP detection, the ENR is updated once the address changes (0 disables)"`
+
+	SyncPolicy    string   `yaml:"SyncPolicy" env:"P2P_SYNC_POLICY" env-default:"everyone" env-description:"who can sync from the node: everyone, committee (committee members of the requested validator, authenticated by their operator key, and allowlisted peers) or allowlist (allowlisted peers only)"`
+	SyncAllowlist []string `yaml:"SyncAllowlist" env:"P2P_SYNC_ALLOWLIST" env-description:"comma separated peer ids that can sync from the node regardless of the sync policy (e.g. exporters), the exporter peer is always allowed"`
+
+	RequireSignedSyncResponses bool `yaml:"RequireSignedSyncResponses" env:"P2P_REQUIRE_SIGNED_SYNC_RESPONSES" env-default:"false" env-description:"reject sync responses that are not signed by the serving peer (e.g. of older versions)"`
+
 	ExporterPeerID string `yaml:"ExporterPeerID" env:"EXPORTER_PEER_ID"  env-default:"16Uiu2HAkvaBh2xjstjs1koEx3jpBn5Hsnz7Bv8pE4SuwFySkiAuf"  env-description:"peer id of exporter"`
 
 	Fork forks.Fork
+	// DB is optional, used to persist the mapping of operators to peers
+	DB basedb.IDb
 
 	// objects / instances
 	HostID        peer.ID
@@ -42,7 +81,7 @@ type Config struct {
 	ReportLastMsg bool
 }
 
-// TransformEnr converts defaults enr value and convert it to slice
+// TransformEnr converts defaults enr value and convert it to slice, multiple ENRs are separated by comma
 func TransformEnr(enr string) []string {
 	if len(enr) == 0 {
 		// stage enr
@@ -54,9 +93,13 @@ func TransformEnr(enr string) []string {
 		//external ip
 		enr = "enr:-LK4QMmL9hLJ1csDN4rQoSjlJGE2SvsXOETfcLH8uAVrxlHaELF0u3NeKCTY2eO_X1zy5eEKcHruyaAsGNiyyG4QWUQBh2F0dG5ldHOIAAAAAAAAAACEZXRoMpD1pf1CAAAAAP__________gmlkgnY0gmlwhCLdu_SJc2VjcDI1NmsxoQO8KQz5L1UEXzEr-CXFFq1th0eG6gopbdul2OQVMuxfMoN0Y3CCE4iDdWRwgg-g"
 	}
-	return []string{
-		enr,
+	var enrs []string
+	for _, e := range strings.Split(enr, ",") {
+		if e = strings.TrimSpace(e); len(e) > 0 {
+			enrs = append(enrs, e)
+		}
 	}
+	return enrs
 }
 
 //
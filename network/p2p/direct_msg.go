@@ -0,0 +1,227 @@
+package p2p
+
+import (
+	"context"
+	"crypto/sha256"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
+	lru "github.com/hashicorp/golang-lru"
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+)
+
+const (
+	// directMsgStream is the name of the stream that is used to send consensus messages directly to committee peers
+	directMsgStream = "direct_msg"
+	// seenMsgsCacheSize is the max number of recently received message hashes, used to dedup direct and gossip messages
+	seenMsgsCacheSize = 4096
+	// directMsgWorkers is the number of workers that send (or handle) direct messages
+	directMsgWorkers = 16
+	// directMsgQueueSize is the max number of pending direct messages, new messages are dropped once it's full
+	directMsgQueueSize = 1024
+)
+
+var (
+	metricsDirectMsgs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:direct_msgs",
+		Help: "Count consensus messages that were sent or received directly (outside of gossip)",
+	}, []string{"direction", "result"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsDirectMsgs); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// seenMsgs tracks recently received messages, so messages that arrive both on gossip and directly
+// are propagated only once
+type seenMsgs struct {
+	cache *lru.Cache
+}
+
+func newSeenMsgs(size int) *seenMsgs {
+	// an error is returned only for non-positive sizes
+	cache, _ := lru.New(size)
+	return &seenMsgs{cache: cache}
+}
+
+// markSeen marks the given (encoded) message as seen, returns false if it was seen already
+func (s *seenMsgs) markSeen(data []byte) bool {
+	key := sha256.Sum256(data)
+	seen, _ := s.cache.ContainsOrAdd(key, struct{}{})
+	return !seen
+}
+
+// workerPool runs jobs on a fixed number of workers, jobs are dropped if the queue is full
+type workerPool struct {
+	jobs chan func()
+}
+
+// newWorkerPool creates a pool and starts its workers, the workers stop once the context is done
+func newWorkerPool(ctx context.Context, workers, queueSize int) *workerPool {
+	wp := &workerPool{jobs: make(chan func(), queueSize)}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-wp.jobs:
+					job()
+				}
+			}
+		}()
+	}
+	return wp
+}
+
+// submit queues the given job, returns false if the queue is full
+func (wp *workerPool) submit(job func()) bool {
+	select {
+	case wp.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// sendDirect sends the given (encoded) consensus message to the committee peers of the given validator,
+// i.e. the peers of the validator topic. it is used in addition to gossip, failures are only logged
+func (n *p2pNetwork) sendDirect(validatorPK []byte, msgBytes []byte) {
+	topic, err := n.getTopic(validatorPK)
+	if err != nil {
+		n.logger.Debug("could not get topic for direct message", zap.Error(err))
+		return
+	}
+	for _, p := range n.allPeersOfTopic(topic) {
+		pid, err := peerFromString(p)
+		if err != nil {
+			continue
+		}
+		ok := n.directMsgsOut.submit(func() {
+			if err := n.sendDirectToPeer(pid, msgBytes); err != nil {
+				metricsDirectMsgs.WithLabelValues("out", "failed").Inc()
+				n.trace("could not send direct message", zap.String("peer", pid.String()), zap.Error(err))
+				return
+			}
+			metricsDirectMsgs.WithLabelValues("out", "sent").Inc()
+		})
+		if !ok {
+			metricsDirectMsgs.WithLabelValues("out", "dropped").Inc()
+		}
+	}
+}
+
+// sendDirectToPeer sends the given (encoded) message to the given peer over a dedicated stream
+func (n *p2pNetwork) sendDirectToPeer(pid peer.ID, msgBytes []byte) error {
+	s, err := n.host.NewStream(n.ctx, pid, n.syncProtocol(directMsgStream))
+	if err != nil {
+		return errors.Wrap(err, "could not open stream")
+	}
+	stream := NewSyncStream(s)
+	defer func() {
+		if err := stream.Close(); err != nil {
+			n.trace("could not close direct message stream", zap.Error(err))
+		}
+	}()
+	return n.writeSyncMessage(stream, msgBytes)
+}
+
+// setDirectMsgStreamHandler handles consensus messages that were sent directly by committee peers.
+// peers are rate-limited (and grey-listed) as in gossip, messages are handled by a bounded pool of workers
+func (n *p2pNetwork) setDirectMsgStreamHandler() {
+	n.setSyncStreamHandler(directMsgStream, func(stream core.Stream) {
+		s := newLimitedSyncStream(stream, n.cfg.MaxPubSubMsgSize)
+		defer func() {
+			if err := s.Close(); err != nil {
+				n.trace("could not close direct message stream", zap.Error(err))
+			}
+		}()
+		pid := stream.Conn().RemotePeer()
+		if !n.msgRates.onMessage(directMsgAuditTopic, pid, true) {
+			metricsDirectMsgs.WithLabelValues("in", "rate_limited").Inc()
+			n.trace("dropping direct message of grey-listed peer", zap.String("peer", pid.String()))
+			return
+		}
+		data, err := s.ReadWithTimeout(n.cfg.RequestTimeout)
+		if err != nil {
+			n.reportOversizedMsg(s.RemotePeer(), err)
+			n.trace("could not read direct message", zap.Error(err))
+			return
+		}
+		if n.msgAuditor != nil {
+			n.msgAuditor.capture(pid, directMsgAuditTopic, data)
+		}
+		ok := n.directMsgsIn.submit(func() {
+			if err := n.handleDirectMsg(pid.String(), data); err != nil {
+				metricsDirectMsgs.WithLabelValues("in", "rejected").Inc()
+				n.logger.Debug("dropping invalid direct message", zap.String("peer", pid.String()), zap.Error(err))
+			}
+		})
+		if !ok {
+			metricsDirectMsgs.WithLabelValues("in", "dropped").Inc()
+		}
+	})
+}
+
+// handleDirectMsg decodes and validates a direct message of the given peer, and propagates it
+// if the peer is a committee operator of the validator. messages that were seen already are skipped
+func (n *p2pNetwork) handleDirectMsg(pid string, data []byte) error {
+	cm := network.AcquireMessage()
+	defer network.ReleaseMessage(cm)
+	if err := n.fork.DecodeNetworkMsgInto(data, cm); err != nil {
+		network.ReportRejectedMessage(errs.Mark(err, errs.ErrDecode))
+		return errors.Wrap(err, "could not decode direct message")
+	}
+	switch cm.Type {
+	case network.NetworkMsg_IBFTType, network.NetworkMsg_SignatureType, network.NetworkMsg_DecidedType:
+	default:
+		return errors.Errorf("unexpected direct message type: %s", cm.Type.String())
+	}
+	if err := n.fork.ValidateNetworkMsg(cm); err != nil {
+		network.ReportRejectedMessage(err)
+		return errors.Wrap(err, "invalid direct message")
+	}
+	if cm.SignedMessage == nil || cm.SignedMessage.Message == nil {
+		return errors.New("direct message is empty")
+	}
+	if !n.isCommitteePeer(pid, cm.SignedMessage.Message.Lambda) {
+		return errors.New("sender is not a committee operator")
+	}
+	if !n.seenMsgs.markSeen(data) {
+		metricsDirectMsgs.WithLabelValues("in", "duplicate").Inc()
+		return nil
+	}
+	metricsDirectMsgs.WithLabelValues("in", "propagated").Inc()
+	n.propagateSignedMsg(cm)
+	return nil
+}
+
+// isCommitteePeer returns true if the given peer belongs to a committee operator of the validator
+// of the given identifier (lambda). operators are mapped to peers by their user agent
+func (n *p2pNetwork) isCommitteePeer(pid string, identifier []byte) bool {
+	operators, found := n.syncPolicy.committeeOf(identifier)
+	if !found {
+		return false
+	}
+	var peerOperator string
+	if n.peersIndex != nil {
+		peerOperator = n.peersIndex.GetPeerData(pid, OperatorKey)
+	}
+	for _, operator := range operators {
+		hash := pubKeyHash(operator)
+		if hash == peerOperator {
+			return true
+		}
+		if p, ok := n.operatorPeers.get(hash); ok && p == pid {
+			return true
+		}
+	}
+	return false
+}
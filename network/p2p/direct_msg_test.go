@@ -0,0 +1,116 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSeenMsgs(t *testing.T) {
+	seen := newSeenMsgs(2)
+	require.True(t, seen.markSeen([]byte("a")))
+	require.False(t, seen.markSeen([]byte("a")))
+	require.True(t, seen.markSeen([]byte("b")))
+	require.True(t, seen.markSeen([]byte("c")))
+	// "a" was evicted
+	require.True(t, seen.markSeen([]byte("a")))
+}
+
+func TestWorkerPool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wp := newWorkerPool(ctx, 1, 1)
+	release := make(chan struct{})
+	done := make(chan struct{}, 2)
+	job := func() {
+		<-release
+		done <- struct{}{}
+	}
+	require.True(t, wp.submit(job))
+	// the first job is taken by the worker, the second is queued
+	require.Eventually(t, func() bool {
+		return wp.submit(job)
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, wp.submit(job))
+	close(release)
+	<-done
+	<-done
+}
+
+func TestDirectMsg(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sp, err := newSyncPolicy(SyncPolicyEveryone, nil)
+	require.NoError(t, err)
+	sp.setCommitteeResolver(func(identifier []byte) ([]string, bool) {
+		if string(identifier) != "test-lambda" {
+			return nil, false
+		}
+		return []string{"operator-1", "operator-2"}, true
+	})
+	n := &p2pNetwork{
+		cfg:           &Config{},
+		logger:        logger,
+		fork:          testFork(),
+		listenersLock: &sync.Mutex{},
+		seenMsgs:      newSeenMsgs(seenMsgsCacheSize),
+		syncPolicy:    sp,
+		operatorPeers: newOperatorPeers(logger, nil),
+	}
+	n.operatorPeers.set(pubKeyHash("operator-2"), "peer-2")
+	msgChan := n.ReceivedMsgChan()
+
+	signed := &proto.SignedMessage{
+		Message: &proto.Message{
+			Type:   proto.RoundState_Prepare,
+			Round:  1,
+			Lambda: []byte("test-lambda"),
+			Value:  []byte("test-value"),
+		},
+		Signature: []byte("sig"),
+		SignerIds: []uint64{1},
+	}
+	msgBytes, err := json.Marshal(&network.Message{
+		SignedMessage: signed,
+		Type:          network.NetworkMsg_IBFTType,
+	})
+	require.NoError(t, err)
+
+	t.Run("not a committee peer", func(t *testing.T) {
+		require.EqualError(t, n.handleDirectMsg("peer-3", msgBytes), "sender is not a committee operator")
+	})
+
+	t.Run("propagated", func(t *testing.T) {
+		require.NoError(t, n.handleDirectMsg("peer-2", msgBytes))
+		select {
+		case msg := <-msgChan:
+			require.Equal(t, signed, msg)
+		case <-time.After(time.Second):
+			t.Fatal("direct message was not propagated")
+		}
+	})
+
+	t.Run("duplicate is dropped", func(t *testing.T) {
+		require.NoError(t, n.handleDirectMsg("peer-2", msgBytes))
+		select {
+		case <-msgChan:
+			t.Fatal("duplicated direct message was propagated")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("unexpected type", func(t *testing.T) {
+		data, err := json.Marshal(&network.Message{
+			SignedMessage: signed,
+			Type:          network.NetworkMsg_SyncType,
+		})
+		require.NoError(t, err)
+		require.EqualError(t, n.handleDirectMsg("peer-2", data), "unexpected direct message type: SyncType")
+	})
+}
@@ -33,7 +33,11 @@ func (n *p2pNetwork) startDiscovery() error {
 	if err := n.connectToBootnodes(); err != nil {
 		return errors.Wrap(err, "could not connect to bootnodes")
 	}
-	go n.listenForNewNodes()
+	iterator, err := n.nodesIterator()
+	if err != nil {
+		return errors.Wrap(err, "could not create nodes iterator")
+	}
+	go n.listenForNewNodes(iterator)
 	return nil
 }
 
@@ -1,7 +1,9 @@
 package p2p
 
 import (
+	"bytes"
 	"crypto/ecdsa"
+	"github.com/bloxapp/ssv/network/p2p/peers"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/p2p/enode"
@@ -9,8 +11,6 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/go-bitfield"
-	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
-	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers/scorers"
 	"go.uber.org/zap"
 	"net"
 	"time"
@@ -30,14 +30,10 @@ type discv5Listener interface {
 
 // setupDiscV5 creates all the required objects for discv5
 func (n *p2pNetwork) setupDiscV5() (*discover.UDPv5, error) {
-	n.peers = peers.NewStatus(n.ctx, &peers.StatusConfig{
-		PeerLimit: maxPeers,
-		ScorerParams: &scorers.Config{
-			BadResponsesScorerConfig: &scorers.BadResponsesScorerConfig{
-				Threshold:     5,
-				DecayInterval: time.Hour,
-			},
-		},
+	n.peers = peers.New(n.ctx, peers.Config{
+		PeerLimit:             maxPeers,
+		BadResponsesThreshold: 5,
+		DecayInterval:         time.Hour,
 	})
 	ip, err := ipAddr()
 	if err != nil {
@@ -101,6 +97,7 @@ func (n *p2pNetwork) createListener(ipAddr net.IP) (*discover.UDPv5, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read bootstrap addresses")
 	}
+	dv5Cfg.Bootnodes = append(dv5Cfg.Bootnodes, n.dnsBootnodes()...)
 	// create discv5 listener
 	listener, err := discover.ListenV5(conn, localNode, dv5Cfg)
 	if err != nil {
@@ -187,9 +184,8 @@ func createLocalNode(privKey *ecdsa.PrivateKey, ipAddr net.IP, udpPort, tcpPort
 }
 
 // listenForNewNodes watches for new nodes in the network and connects to unknown peers.
-func (n *p2pNetwork) listenForNewNodes() {
+func (n *p2pNetwork) listenForNewNodes(iterator enode.Iterator) {
 	defer n.logger.Debug("done listening for new nodes")
-	iterator := n.dv5Listener.RandomNodes()
 	//iterator = enode.Filter(iterator, s.filterPeer)
 	defer iterator.Close()
 	n.logger.Debug("starting to listen for new nodes")
@@ -276,3 +272,23 @@ func extractOperatorPubKeyEntry(record *enr.Record) ([]byte, error) {
 	}
 	return bitL.Bytes(), nil
 }
+
+// VerifyOperatorENR checks that the given ENR holds the public key entry ('pk') of the given operator public key
+func VerifyOperatorENR(record string, operatorPubKey string) error {
+	node, err := enode.Parse(enode.ValidSchemes, record)
+	if err != nil {
+		return errors.Wrap(err, "could not parse ENR")
+	}
+	pkHashRecord, err := extractOperatorPubKeyEntry(node.Record())
+	if err != nil {
+		return errors.Wrap(err, "could not find operator public key entry")
+	}
+	expected, err := bitfield.NewBitlist64FromBytes(64, []byte(pubKeyHash(operatorPubKey)))
+	if err != nil {
+		return errors.Wrap(err, "could not create public key entry")
+	}
+	if !bytes.Equal(pkHashRecord, expected.ToBitlist().Bytes()) {
+		return errors.New("operator public key doesn't match the ENR")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package p2p
+
+import (
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/rand"
+	"time"
+)
+
+const (
+	// dnsDiscoveryMixTimeout is the time to wait for a source before taking a node from other sources
+	dnsDiscoveryMixTimeout = 5 * time.Second
+	// dnsBootnodesLimit is the max number of nodes from DNS trees that are used to bootstrap discv5
+	dnsBootnodesLimit = 16
+)
+
+// nodesIterator returns the iterator of discovered nodes, i.e. discv5 random nodes,
+// mixed with nodes from the configured DNS trees if exist
+func (n *p2pNetwork) nodesIterator() (enode.Iterator, error) {
+	iterator := n.dv5Listener.RandomNodes()
+	if len(n.cfg.DNSDiscoveryURLs) == 0 {
+		return iterator, nil
+	}
+	dnsIterator, err := n.newDNSIterator()
+	if err != nil {
+		iterator.Close()
+		return nil, err
+	}
+	n.logger.Info("using DNS discovery", zap.Strings("urls", n.cfg.DNSDiscoveryURLs))
+	mix := enode.NewFairMix(dnsDiscoveryMixTimeout)
+	mix.AddSource(iterator)
+	mix.AddSource(dnsIterator)
+	return mix, nil
+}
+
+// dnsBootnodes returns (up to dnsBootnodesLimit) random nodes from the configured DNS trees,
+// used to bootstrap discv5 so discovery doesn't depend only on the availability of bootnodes
+func (n *p2pNetwork) dnsBootnodes() []*enode.Node {
+	if len(n.cfg.DNSDiscoveryURLs) == 0 {
+		return nil
+	}
+	client := dnsdisc.NewClient(n.dnsClientConfig())
+	var nodes []*enode.Node
+	for _, url := range n.cfg.DNSDiscoveryURLs {
+		tree, err := client.SyncTree(url)
+		if err != nil {
+			n.logger.Warn("could not read bootnodes from DNS", zap.String("url", url), zap.Error(err))
+			continue
+		}
+		for _, node := range tree.Nodes() {
+			if hasTCPEntry(node) {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	rand.Shuffle(len(nodes), func(i, j int) {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	})
+	if len(nodes) > dnsBootnodesLimit {
+		nodes = nodes[:dnsBootnodesLimit]
+	}
+	n.logger.Debug("read bootnodes from DNS", zap.Int("count", len(nodes)))
+	return nodes
+}
+
+// newDNSIterator creates an iterator of the nodes in the configured DNS trees (EIP-1459),
+// the trees are verified by the public key in the URL and refreshed periodically by the client
+func (n *p2pNetwork) newDNSIterator() (enode.Iterator, error) {
+	client := dnsdisc.NewClient(n.dnsClientConfig())
+	iterator, err := client.NewIterator(n.cfg.DNSDiscoveryURLs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create DNS discovery iterator")
+	}
+	return enode.Filter(iterator, hasTCPEntry), nil
+}
+
+func (n *p2pNetwork) dnsClientConfig() dnsdisc.Config {
+	cfg := dnsdisc.Config{Resolver: n.dnsResolver}
+	if n.cfg.NetworkTrace {
+		logger := log.New()
+		logger.SetHandler(&dv5Logger{n.logger.With(zap.String("who", "dnsDiscLogger"))})
+		cfg.Logger = logger
+	}
+	return cfg
+}
+
+// hasTCPEntry returns true if the node can be dialed by libp2p
+func hasTCPEntry(node *enode.Node) bool {
+	return node.IP() != nil && node.TCP() != 0
+}
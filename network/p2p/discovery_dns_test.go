@@ -0,0 +1,76 @@
+package p2p
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"net"
+	"testing"
+)
+
+// mapResolver is a dnsdisc.Resolver that serves the given records
+type mapResolver map[string]string
+
+func (mr mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if record, ok := mr[name]; ok {
+		return []string{record}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestDNSBootnodes(t *testing.T) {
+	var nodes []*enode.Node
+	for i := 0; i < 3; i++ {
+		localNode, err := createLocalNode(testPrivKey(t), net.IPv4(34, 1, 1, byte(i+1)), 12000, 13000)
+		require.NoError(t, err)
+		nodes = append(nodes, localNode.Node())
+	}
+	// node w/o tcp entry is filtered
+	localNode, err := createLocalNode(testPrivKey(t), net.IPv4(34, 1, 1, 4), 12000, 0)
+	require.NoError(t, err)
+	nodes = append(nodes, localNode.Node())
+
+	tree, err := dnsdisc.MakeTree(1, nodes, nil)
+	require.NoError(t, err)
+	url, err := tree.Sign(testPrivKey(t), "nodes.ssv.test")
+	require.NoError(t, err)
+
+	n := &p2pNetwork{
+		cfg:         &Config{DNSDiscoveryURLs: []string{url}},
+		logger:      zaptest.NewLogger(t),
+		dnsResolver: mapResolver(tree.ToTXT("nodes.ssv.test")),
+	}
+	found := make(map[enode.ID]bool)
+	for _, node := range n.dnsBootnodes() {
+		require.NotZero(t, node.TCP())
+		found[node.ID()] = true
+	}
+	require.Len(t, found, 3)
+	require.False(t, found[localNode.ID()])
+
+	iterator, err := n.newDNSIterator()
+	require.NoError(t, err)
+	defer iterator.Close()
+	for i := 0; i < 5; i++ {
+		require.True(t, iterator.Next())
+		require.True(t, found[iterator.Node().ID()])
+	}
+
+	t.Run("invalid url", func(t *testing.T) {
+		n := &p2pNetwork{
+			cfg:    &Config{DNSDiscoveryURLs: []string{"enrtree://invalid"}},
+			logger: zaptest.NewLogger(t),
+		}
+		require.Len(t, n.dnsBootnodes(), 0)
+		_, err := n.newDNSIterator()
+		require.Error(t, err)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		n := &p2pNetwork{cfg: &Config{}, logger: zaptest.NewLogger(t)}
+		require.Nil(t, n.dnsBootnodes())
+	})
+}
@@ -38,3 +38,21 @@ func genPublicKey() *bls.PublicKey {
 
 	return sk.GetPublicKey()
 }
+
+func TestVerifyOperatorENR(t *testing.T) {
+	priv, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+	require.NoError(t, err)
+	ip, err := ipAddr()
+	require.NoError(t, err)
+	node, err := createLocalNode(convertFromInterfacePrivKey(priv), ip, 12000, 13000)
+	require.NoError(t, err)
+
+	operatorPubKey := "b3BlcmF0b3ItcHVibGljLWtleQ=="
+	require.Error(t, VerifyOperatorENR(node.Node().String(), operatorPubKey))
+	node, err = addOperatorPubKeyEntry(node, []byte(pubKeyHash(operatorPubKey)))
+	require.NoError(t, err)
+
+	require.NoError(t, VerifyOperatorENR(node.Node().String(), operatorPubKey))
+	require.Error(t, VerifyOperatorENR(node.Node().String(), "other"))
+	require.Error(t, VerifyOperatorENR("enr:invalid", operatorPubKey))
+}
@@ -0,0 +1,123 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net"
+)
+
+// watchExternalIP periodically detects the external IP of the node,
+// once the address changes the ENR is updated and re-announced
+func (n *p2pNetwork) watchExternalIP() {
+	if n.cfg.ExternalIPInterval == 0 || n.dv5Listener == nil {
+		return
+	}
+	tasks.RunEvery(n.ctx, n.cfg.ExternalIPInterval, n.checkExternalIP)
+}
+
+// checkExternalIP updates the ENR if the detected external IP is different than the current one
+func (n *p2pNetwork) checkExternalIP() {
+	ip, err := n.detectExternalIP()
+	if err != nil {
+		n.logger.Debug("could not detect external IP", zap.Error(err))
+		return
+	}
+	localNode := n.dv5Listener.LocalNode()
+	prev := localNode.Node().IP()
+	if !updateLocalNodeIP(localNode, ip) {
+		return
+	}
+	n.setHostAddress(ip.String())
+	metricsExternalIPChanges.Inc()
+	n.logger.Info("external IP has changed, ENR was updated", zap.String("prev", prev.String()),
+		zap.String("ip", ip.String()), zap.String("enr", localNode.Node().String()))
+	n.announceENR()
+}
+
+// detectExternalIP resolves the configured host DNS if exist,
+// otherwise the addresses observed by peers are used
+func (n *p2pNetwork) detectExternalIP() (net.IP, error) {
+	if len(n.cfg.HostDNS) > 0 {
+		ips, err := net.LookupIP(n.cfg.HostDNS)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not resolve host address")
+		}
+		if len(ips) == 0 {
+			return nil, errors.New("host address was not resolved")
+		}
+		return ips[0], nil
+	}
+	if n.idService == nil {
+		return nil, errors.New("identify service is not available")
+	}
+	ip := observedIP(n.idService.OwnObservedAddrs())
+	if ip == nil {
+		return nil, errors.New("no observed public address")
+	}
+	return ip, nil
+}
+
+// announceENR pings the bootnodes so they will request the updated record,
+// other nodes will get it once they interact with this node
+func (n *p2pNetwork) announceENR() {
+	nodes, err := parseENRs(n.cfg.BootnodesENRs, false)
+	if err != nil {
+		n.logger.Warn("could not parse bootnodes ENRs", zap.Error(err))
+		return
+	}
+	for _, node := range nodes {
+		if err := n.dv5Listener.Ping(node); err != nil {
+			n.logger.Debug("could not ping bootnode", zap.String("enr", node.String()), zap.Error(err))
+		}
+	}
+}
+
+// hostAddress returns the external address that is announced by libp2p
+func (n *p2pNetwork) hostAddress() string {
+	n.hostAddressLock.RLock()
+	defer n.hostAddressLock.RUnlock()
+
+	return n.cfg.HostAddress
+}
+
+func (n *p2pNetwork) setHostAddress(addr string) {
+	n.hostAddressLock.Lock()
+	defer n.hostAddressLock.Unlock()
+
+	n.cfg.HostAddress = addr
+}
+
+// updateLocalNodeIP sets the given IP in the local node, returns false if the IP didn't change
+func updateLocalNodeIP(localNode *enode.LocalNode, ip net.IP) bool {
+	if ip.Equal(localNode.Node().IP()) {
+		return false
+	}
+	localNode.SetStaticIP(ip)
+	localNode.SetFallbackIP(ip)
+	return true
+}
+
+// observedIP returns the most common public IP in the given (observed) addresses, nil if there is no such address
+func observedIP(addrs []ma.Multiaddr) net.IP {
+	counts := make(map[string]int)
+	var res net.IP
+	for _, addr := range addrs {
+		if !manet.IsPublicAddr(addr) {
+			continue
+		}
+		ip, err := manet.ToIP(addr)
+		if err != nil {
+			continue
+		}
+		key := ip.String()
+		counts[key]++
+		if res == nil || counts[key] > counts[res.String()] {
+			res = ip
+		}
+	}
+	return res
+}
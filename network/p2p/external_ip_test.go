@@ -0,0 +1,44 @@
+package p2p
+
+import (
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+	"net"
+	"testing"
+)
+
+func TestObservedIP(t *testing.T) {
+	addrs := func(strs ...string) []ma.Multiaddr {
+		var res []ma.Multiaddr
+		for _, s := range strs {
+			addr, err := ma.NewMultiaddr(s)
+			require.NoError(t, err)
+			res = append(res, addr)
+		}
+		return res
+	}
+
+	require.Nil(t, observedIP(nil))
+	// private addresses are ignored
+	require.Nil(t, observedIP(addrs("/ip4/192.168.1.2/tcp/13001", "/ip4/10.0.0.1/tcp/13001")))
+	require.Equal(t, "34.1.1.1", observedIP(addrs(
+		"/ip4/192.168.1.2/tcp/13001",
+		"/ip4/35.2.2.2/tcp/13001",
+		"/ip4/34.1.1.1/tcp/13001",
+		"/ip4/34.1.1.1/tcp/13002",
+	)).String())
+}
+
+func TestUpdateLocalNodeIP(t *testing.T) {
+	localNode, err := createLocalNode(testPrivKey(t), net.ParseIP("34.1.1.1"), 12000, 13000)
+	require.NoError(t, err)
+	localNode.SetStaticIP(net.ParseIP("34.1.1.1"))
+	seq := localNode.Node().Seq()
+
+	require.False(t, updateLocalNodeIP(localNode, net.ParseIP("34.1.1.1")))
+	require.Equal(t, seq, localNode.Node().Seq())
+
+	require.True(t, updateLocalNodeIP(localNode, net.ParseIP("35.2.2.2")))
+	require.Equal(t, "35.2.2.2", localNode.Node().IP().String())
+	require.Greater(t, localNode.Node().Seq(), seq)
+}
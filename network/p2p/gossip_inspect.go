@@ -0,0 +1,269 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// gossipInspectInterval is the interval of peer scores snapshots
+	gossipInspectInterval = 10 * time.Second
+)
+
+// meshStats holds the mesh state of a peer in some topic
+type meshStats struct {
+	inMesh bool
+	grafts uint64
+	prunes uint64
+}
+
+// gossipInspector collects gossipsub peer scores and mesh state (grafts / prunes) for diagnostics.
+// it implements pubsub.RawTracer, and receives score snapshots periodically from the gossipsub router
+type gossipInspector struct {
+	lock   sync.RWMutex
+	scores map[peer.ID]*pubsub.PeerScoreSnapshot
+	// mesh holds the mesh state by topic and peer
+	mesh map[string]map[peer.ID]*meshStats
+}
+
+func newGossipInspector() *gossipInspector {
+	return &gossipInspector{
+		scores: make(map[peer.ID]*pubsub.PeerScoreSnapshot),
+		mesh:   make(map[string]map[peer.ID]*meshStats),
+	}
+}
+
+// inspectionScoreParams returns score params that are used for inspection only,
+// all the weights are non-negative so peers never get a negative score, i.e. never penalized
+func inspectionScoreParams() (*pubsub.PeerScoreParams, *pubsub.PeerScoreThresholds) {
+	return &pubsub.PeerScoreParams{
+		Topics:           make(map[string]*pubsub.TopicScoreParams),
+		AppSpecificScore: func(p peer.ID) float64 { return 0 },
+		DecayInterval:    pubsub.DefaultDecayInterval,
+		DecayToZero:      pubsub.DefaultDecayToZero,
+		RetainScore:      10 * time.Minute,
+	}, &pubsub.PeerScoreThresholds{}
+}
+
+// inspectionTopicScoreParams returns the score params of validator topics, see inspectionScoreParams
+func inspectionTopicScoreParams() *pubsub.TopicScoreParams {
+	return &pubsub.TopicScoreParams{
+		TopicWeight:                   1,
+		TimeInMeshWeight:              0.01,
+		TimeInMeshQuantum:             time.Second,
+		TimeInMeshCap:                 3600,
+		FirstMessageDeliveriesWeight:  1,
+		FirstMessageDeliveriesDecay:   pubsub.ScoreParameterDecay(10 * time.Minute),
+		FirstMessageDeliveriesCap:     100,
+		MeshMessageDeliveriesWindow:   10 * time.Millisecond,
+		InvalidMessageDeliveriesDecay: pubsub.ScoreParameterDecay(10 * time.Minute),
+	}
+}
+
+// pubsubOptions returns the pubsub options that enables inspection
+func (gi *gossipInspector) pubsubOptions() []pubsub.Option {
+	params, thresholds := inspectionScoreParams()
+	return []pubsub.Option{
+		pubsub.WithPeerScore(params, thresholds),
+		pubsub.WithPeerScoreInspect(pubsub.ExtendedPeerScoreInspectFn(gi.inspect), gossipInspectInterval),
+		pubsub.WithRawTracer(gi),
+	}
+}
+
+// inspect is called by the gossipsub router with the current scores
+func (gi *gossipInspector) inspect(scores map[peer.ID]*pubsub.PeerScoreSnapshot) {
+	gi.lock.Lock()
+	defer gi.lock.Unlock()
+
+	gi.scores = scores
+}
+
+// peersScores returns the current scores of the known peers
+func (gi *gossipInspector) peersScores() map[string]float64 {
+	gi.lock.RLock()
+	defer gi.lock.RUnlock()
+
+	res := make(map[string]float64, len(gi.scores))
+	for pid, snapshot := range gi.scores {
+		res[pid.String()] = snapshot.Score
+	}
+	return res
+}
+
+// stats returns the stats of the known peers, sorted by score
+func (gi *gossipInspector) stats(userAgent func(pid string) string) []network.PeerGossipStats {
+	gi.lock.RLock()
+	defer gi.lock.RUnlock()
+
+	byPeer := make(map[peer.ID]*network.PeerGossipStats)
+	getPeer := func(pid peer.ID) *network.PeerGossipStats {
+		ps, ok := byPeer[pid]
+		if !ok {
+			ps = &network.PeerGossipStats{PeerID: pid.String(), UserAgent: userAgent(pid.String())}
+			byPeer[pid] = ps
+		}
+		return ps
+	}
+	for pid, snapshot := range gi.scores {
+		ps := getPeer(pid)
+		ps.Score = snapshot.Score
+		ps.AppSpecificScore = snapshot.AppSpecificScore
+		ps.IPColocationFactor = snapshot.IPColocationFactor
+		ps.BehaviourPenalty = snapshot.BehaviourPenalty
+		for topic, ts := range snapshot.Topics {
+			tgs := network.TopicGossipStats{
+				Topic:                    topic,
+				TimeInMesh:               ts.TimeInMesh.Milliseconds(),
+				FirstMessageDeliveries:   ts.FirstMessageDeliveries,
+				MeshMessageDeliveries:    ts.MeshMessageDeliveries,
+				InvalidMessageDeliveries: ts.InvalidMessageDeliveries,
+			}
+			if ms, ok := gi.mesh[topic][pid]; ok {
+				tgs.InMesh, tgs.Grafts, tgs.Prunes = ms.inMesh, ms.grafts, ms.prunes
+			}
+			ps.Topics = append(ps.Topics, tgs)
+		}
+	}
+	// topics w/o score params (or peers w/o score snapshot yet)
+	for topic, peers := range gi.mesh {
+		for pid, ms := range peers {
+			if snapshot, ok := gi.scores[pid]; ok {
+				if _, scored := snapshot.Topics[topic]; scored {
+					continue
+				}
+			}
+			ps := getPeer(pid)
+			ps.Topics = append(ps.Topics, network.TopicGossipStats{
+				Topic:  topic,
+				InMesh: ms.inMesh,
+				Grafts: ms.grafts,
+				Prunes: ms.prunes,
+			})
+		}
+	}
+
+	res := make([]network.PeerGossipStats, 0, len(byPeer))
+	for _, ps := range byPeer {
+		sort.Slice(ps.Topics, func(i, j int) bool {
+			return ps.Topics[i].Topic < ps.Topics[j].Topic
+		})
+		res = append(res, *ps)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score == res[j].Score {
+			return res[i].PeerID < res[j].PeerID
+		}
+		return res[i].Score > res[j].Score
+	})
+	return res
+}
+
+func (gi *gossipInspector) meshStatsNotSafe(p peer.ID, topic string) *meshStats {
+	peers, ok := gi.mesh[topic]
+	if !ok {
+		peers = make(map[peer.ID]*meshStats)
+		gi.mesh[topic] = peers
+	}
+	ms, ok := peers[p]
+	if !ok {
+		ms = &meshStats{}
+		peers[p] = ms
+	}
+	return ms
+}
+
+// Graft implements pubsub.RawTracer
+func (gi *gossipInspector) Graft(p peer.ID, topic string) {
+	gi.lock.Lock()
+	defer gi.lock.Unlock()
+
+	ms := gi.meshStatsNotSafe(p, topic)
+	ms.inMesh = true
+	ms.grafts++
+}
+
+// Prune implements pubsub.RawTracer
+func (gi *gossipInspector) Prune(p peer.ID, topic string) {
+	gi.lock.Lock()
+	defer gi.lock.Unlock()
+
+	ms := gi.meshStatsNotSafe(p, topic)
+	ms.inMesh = false
+	ms.prunes++
+}
+
+// RemovePeer implements pubsub.RawTracer
+func (gi *gossipInspector) RemovePeer(p peer.ID) {
+	gi.lock.Lock()
+	defer gi.lock.Unlock()
+
+	for _, peers := range gi.mesh {
+		delete(peers, p)
+	}
+}
+
+// Leave implements pubsub.RawTracer
+func (gi *gossipInspector) Leave(topic string) {
+	gi.lock.Lock()
+	defer gi.lock.Unlock()
+
+	delete(gi.mesh, topic)
+}
+
+// AddPeer implements pubsub.RawTracer
+func (gi *gossipInspector) AddPeer(p peer.ID, proto protocol.ID) {}
+
+// Join implements pubsub.RawTracer
+func (gi *gossipInspector) Join(topic string) {}
+
+// ValidateMessage implements pubsub.RawTracer
+func (gi *gossipInspector) ValidateMessage(msg *pubsub.Message) {}
+
+// DeliverMessage implements pubsub.RawTracer
+func (gi *gossipInspector) DeliverMessage(msg *pubsub.Message) {}
+
+// RejectMessage implements pubsub.RawTracer
+func (gi *gossipInspector) RejectMessage(msg *pubsub.Message, reason string) {}
+
+// DuplicateMessage implements pubsub.RawTracer
+func (gi *gossipInspector) DuplicateMessage(msg *pubsub.Message) {}
+
+// ThrottlePeer implements pubsub.RawTracer
+func (gi *gossipInspector) ThrottlePeer(p peer.ID) {}
+
+// RecvRPC implements pubsub.RawTracer
+func (gi *gossipInspector) RecvRPC(rpc *pubsub.RPC) {}
+
+// SendRPC implements pubsub.RawTracer
+func (gi *gossipInspector) SendRPC(rpc *pubsub.RPC, p peer.ID) {}
+
+// DropRPC implements pubsub.RawTracer
+func (gi *gossipInspector) DropRPC(rpc *pubsub.RPC, p peer.ID) {}
+
+// UndeliverableMessage implements pubsub.RawTracer
+func (gi *gossipInspector) UndeliverableMessage(msg *pubsub.Message) {}
+
+// GossipStats returns the gossip stats of the known peers, false if inspection is disabled
+func (n *p2pNetwork) GossipStats() ([]network.PeerGossipStats, bool) {
+	if n.gossipInspector == nil {
+		return nil, false
+	}
+	return n.gossipInspector.stats(func(pid string) string {
+		return n.peersIndex.GetPeerData(pid, UserAgentKey)
+	}), true
+}
+
+// reportPeersScores sets the current gossipsub scores in the peers index, if inspection is enabled
+func (n *p2pNetwork) reportPeersScores() {
+	if n.gossipInspector == nil {
+		return
+	}
+	for pid, score := range n.gossipInspector.peersScores() {
+		n.peersIndex.ReportScore(pid, score)
+	}
+}
@@ -0,0 +1,78 @@
+package p2p
+
+import (
+	"context"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestGossipInspector_Options(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	host, err := libp2p.New(ctx, libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	require.NoError(t, err)
+	defer func() {
+		_ = host.Close()
+	}()
+
+	gi := newGossipInspector()
+	ps, err := pubsub.NewGossipSub(ctx, host, gi.pubsubOptions()...)
+	require.NoError(t, err)
+	topic, err := ps.Join(getTopicName("xxx"))
+	require.NoError(t, err)
+	require.NoError(t, topic.SetScoreParams(inspectionTopicScoreParams()))
+}
+
+func TestGossipInspector_Stats(t *testing.T) {
+	gi := newGossipInspector()
+	peerA := peer.ID("A")
+	peerB := peer.ID("B")
+	topicA := getTopicName("aaa")
+	topicB := getTopicName("bbb")
+
+	gi.Graft(peerA, topicA)
+	gi.Prune(peerA, topicA)
+	gi.Graft(peerA, topicA)
+	gi.Graft(peerB, topicA)
+	gi.Graft(peerB, topicB)
+	gi.inspect(map[peer.ID]*pubsub.PeerScoreSnapshot{
+		peerA: {
+			Score: 12,
+			Topics: map[string]*pubsub.TopicScoreSnapshot{
+				topicA: {TimeInMesh: 2 * time.Second, FirstMessageDeliveries: 10},
+			},
+		},
+		peerB: {Score: 20, Topics: map[string]*pubsub.TopicScoreSnapshot{}},
+	})
+
+	userAgent := func(pid string) string {
+		return "ua:" + pid
+	}
+	stats := gi.stats(userAgent)
+	require.Len(t, stats, 2)
+	// sorted by score
+	require.Equal(t, peerB.String(), stats[0].PeerID)
+	require.Equal(t, "ua:"+peerB.String(), stats[0].UserAgent)
+	require.Len(t, stats[0].Topics, 2)
+	require.True(t, stats[0].Topics[0].InMesh)
+
+	require.Equal(t, peerA.String(), stats[1].PeerID)
+	require.Len(t, stats[1].Topics, 1)
+	ts := stats[1].Topics[0]
+	require.Equal(t, topicA, ts.Topic)
+	require.True(t, ts.InMesh)
+	require.Equal(t, uint64(2), ts.Grafts)
+	require.Equal(t, uint64(1), ts.Prunes)
+	require.Equal(t, int64(2000), ts.TimeInMesh)
+	require.Equal(t, float64(10), ts.FirstMessageDeliveries)
+
+	gi.RemovePeer(peerB)
+	gi.Leave(topicA)
+	gi.inspect(map[peer.ID]*pubsub.PeerScoreSnapshot{})
+	require.Len(t, gi.stats(userAgent), 0)
+}
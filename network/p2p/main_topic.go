@@ -1,14 +1,25 @@
 package p2p
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/format"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/pkg/errors"
 )
 
-// BroadcastMainTopic broadcasts the given msg on main channel
+// mainTopic is the topic of operator heartbeats, and of decided messages in forks that don't shard them
+const mainTopic = "main"
+
+// multiForkDecidedTopics is implemented by forks that are aware of the decided topics of other forks
+type multiForkDecidedTopics interface {
+	DecidedTopicIDsOf(pk []byte) []string
+}
+
+// BroadcastMainTopic broadcasts the given decided msg on the decided topic of its validator
 func (n *p2pNetwork) BroadcastMainTopic(msg *proto.SignedMessage) error {
 	msgBytes, err := json.Marshal(network.Message{
 		SignedMessage: msg,
@@ -17,42 +28,101 @@ func (n *p2pNetwork) BroadcastMainTopic(msg *proto.SignedMessage) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal message")
 	}
-	topic, err := n.getMainTopic()
+	topic, err := n.getDecidedTopic(msg)
 	if err != nil {
-		return errors.Wrap(err, "failed to get main topic")
+		return errors.Wrap(err, "failed to get decided topic")
 	}
 	if err := topic.Publish(n.ctx, msgBytes); err != nil {
-		return errors.Wrap(err, "failed to publish on main topic")
+		return errors.Wrap(err, "failed to publish on decided topic")
 	}
 	return nil
 }
 
 // SubscribeToMainTopic subscribes to main topic
 func (n *p2pNetwork) SubscribeToMainTopic() error {
-	topic, err := n.getMainTopic()
+	return n.subscribeSharedTopic(mainTopic)
+}
+
+// SubscribeToDecidedTopics subscribes to the decided topics of all validators
+func (n *p2pNetwork) SubscribeToDecidedTopics() error {
+	for _, name := range n.fork.DecidedTopicIDs() {
+		if err := n.subscribeSharedTopic(name); err != nil {
+			return errors.Wrapf(err, "failed to subscribe to decided topic %s", name)
+		}
+	}
+	return nil
+}
+
+// subscribeValidatorDecidedTopics subscribes to the decided topics of the given validator,
+// if the fork is aware of other forks, the topics of all the forks are subscribed
+func (n *p2pNetwork) subscribeValidatorDecidedTopics(pk []byte) error {
+	names := []string{n.fork.DecidedTopicID(pk)}
+	if mf, ok := n.fork.(multiForkDecidedTopics); ok {
+		names = mf.DecidedTopicIDsOf(pk)
+	}
+	for _, name := range names {
+		if err := n.subscribeSharedTopic(name); err != nil {
+			return errors.Wrapf(err, "failed to subscribe to decided topic %s", name)
+		}
+	}
+	return nil
+}
+
+// subscribeSharedTopic subscribes to a topic that is not bound to a single validator (e.g. main or decided topics),
+// the subscription is kept as long as the node is running, subscribing again has no effect
+func (n *p2pNetwork) subscribeSharedTopic(name string) error {
+	topic, err := n.getSharedTopic(name)
 	if err != nil {
 		return err
 	}
+
+	n.psTopicsLock.Lock()
+	defer n.psTopicsLock.Unlock()
+
+	if _, ok := n.psSubs[name]; ok {
+		return nil
+	}
 	sub, err := topic.Subscribe()
 	if err != nil {
 		return errors.Wrap(err, "failed to subscribe on Topic")
 	}
-	go n.listen(n.ctx, sub)
+	ctx, cancel := context.WithCancel(n.ctx)
+	n.psSubs[name] = cancel
+	go n.listen(ctx, sub, false)
 
 	return nil
 }
 
-// getTopic return topic by validator public key
+// getMainTopic returns the main topic
 func (n *p2pNetwork) getMainTopic() (*pubsub.Topic, error) {
-	n.psTopicsLock.RLock()
-	defer n.psTopicsLock.RUnlock()
+	return n.getSharedTopic(mainTopic)
+}
+
+// getDecidedTopic returns the decided topic of the validator of the given message, according to the current fork
+func (n *p2pNetwork) getDecidedTopic(msg *proto.SignedMessage) (*pubsub.Topic, error) {
+	if msg == nil || msg.Message == nil {
+		return nil, errors.New("message is nil")
+	}
+	pkHex, _ := format.IdentifierUnformat(string(msg.Message.Lambda))
+	pk, err := hex.DecodeString(pkHex)
+	if err != nil || len(pk) == 0 {
+		return nil, errors.Errorf("could not parse validator public key of lambda %s", string(msg.Message.Lambda))
+	}
+	return n.getSharedTopic(n.fork.DecidedTopicID(pk))
+}
+
+// getSharedTopic returns the given topic, the topic is joined if needed
+func (n *p2pNetwork) getSharedTopic(name string) (*pubsub.Topic, error) {
+	n.psTopicsLock.Lock()
+	defer n.psTopicsLock.Unlock()
 
-	name := "main"
 	if _, ok := n.cfg.Topics[name]; !ok {
-		topic, err := n.pubsub.Join(getTopicName(name))
+		topicName := getTopicName(name)
+		topic, err := n.pubsub.Join(topicName)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to join main topic")
+			return nil, errors.Wrapf(err, "failed to join %s topic", name)
 		}
+		n.registerMsgSizeValidator(topicName)
 		n.cfg.Topics[name] = topic
 	}
 	return n.cfg.Topics[name], nil
@@ -0,0 +1,101 @@
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"sync"
+)
+
+// meshTracker tracks the peers in the local mesh of each topic, based on grafts / prunes of the gossipsub router.
+// it implements pubsub.RawTracer
+type meshTracker struct {
+	lock sync.RWMutex
+	mesh map[string]map[peer.ID]bool
+}
+
+func newMeshTracker() *meshTracker {
+	return &meshTracker{
+		mesh: make(map[string]map[peer.ID]bool),
+	}
+}
+
+// meshPeers returns the number of mesh peers in the given topic
+func (mt *meshTracker) meshPeers(topic string) int {
+	mt.lock.RLock()
+	defer mt.lock.RUnlock()
+
+	return len(mt.mesh[topic])
+}
+
+// Graft implements pubsub.RawTracer
+func (mt *meshTracker) Graft(p peer.ID, topic string) {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+
+	peers, ok := mt.mesh[topic]
+	if !ok {
+		peers = make(map[peer.ID]bool)
+		mt.mesh[topic] = peers
+	}
+	peers[p] = true
+}
+
+// Prune implements pubsub.RawTracer
+func (mt *meshTracker) Prune(p peer.ID, topic string) {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+
+	delete(mt.mesh[topic], p)
+}
+
+// RemovePeer implements pubsub.RawTracer
+func (mt *meshTracker) RemovePeer(p peer.ID) {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+
+	for _, peers := range mt.mesh {
+		delete(peers, p)
+	}
+}
+
+// Leave implements pubsub.RawTracer
+func (mt *meshTracker) Leave(topic string) {
+	mt.lock.Lock()
+	defer mt.lock.Unlock()
+
+	delete(mt.mesh, topic)
+}
+
+// AddPeer implements pubsub.RawTracer
+func (mt *meshTracker) AddPeer(p peer.ID, proto protocol.ID) {}
+
+// Join implements pubsub.RawTracer
+func (mt *meshTracker) Join(topic string) {}
+
+// ValidateMessage implements pubsub.RawTracer
+func (mt *meshTracker) ValidateMessage(msg *pubsub.Message) {}
+
+// DeliverMessage implements pubsub.RawTracer
+func (mt *meshTracker) DeliverMessage(msg *pubsub.Message) {}
+
+// RejectMessage implements pubsub.RawTracer
+func (mt *meshTracker) RejectMessage(msg *pubsub.Message, reason string) {}
+
+// DuplicateMessage implements pubsub.RawTracer
+func (mt *meshTracker) DuplicateMessage(msg *pubsub.Message) {}
+
+// ThrottlePeer implements pubsub.RawTracer
+func (mt *meshTracker) ThrottlePeer(p peer.ID) {}
+
+// RecvRPC implements pubsub.RawTracer
+func (mt *meshTracker) RecvRPC(rpc *pubsub.RPC) {}
+
+// SendRPC implements pubsub.RawTracer
+func (mt *meshTracker) SendRPC(rpc *pubsub.RPC, p peer.ID) {}
+
+// DropRPC implements pubsub.RawTracer
+func (mt *meshTracker) DropRPC(rpc *pubsub.RPC, p peer.ID) {}
+
+// UndeliverableMessage implements pubsub.RawTracer
+func (mt *meshTracker) UndeliverableMessage(msg *pubsub.Message) {}
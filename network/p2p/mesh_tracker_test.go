@@ -0,0 +1,30 @@
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMeshTracker(t *testing.T) {
+	mt := newMeshTracker()
+	p1, p2 := peer.ID("p1"), peer.ID("p2")
+
+	mt.Graft(p1, "topic1")
+	mt.Graft(p2, "topic1")
+	mt.Graft(p1, "topic2")
+	require.Equal(t, 2, mt.meshPeers("topic1"))
+	require.Equal(t, 1, mt.meshPeers("topic2"))
+	require.Equal(t, 0, mt.meshPeers("topic3"))
+
+	mt.Prune(p2, "topic1")
+	require.Equal(t, 1, mt.meshPeers("topic1"))
+
+	mt.RemovePeer(p1)
+	require.Equal(t, 0, mt.meshPeers("topic1"))
+	require.Equal(t, 0, mt.meshPeers("topic2"))
+
+	mt.Graft(p2, "topic2")
+	mt.Leave("topic2")
+	require.Equal(t, 0, mt.meshPeers("topic2"))
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
 	"log"
-	"strings"
 	"time"
 )
 
@@ -24,10 +23,30 @@ var (
 		Name: "ssv:network:peers_identity",
 		Help: "Peers identity",
 	}, []string{"pubKey", "v", "pid"})
+	metricsPeersVersions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:network:peers_versions",
+		Help: "Count connected peers by version, commit and fork",
+	}, []string{"version", "commit", "fork"})
 	metricsPeerLastMsg = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "ssv:network:peer_last_msg",
 		Help: "Timestamps of last messages",
 	}, []string{"pid"})
+	metricsOutboxRetried = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:outbox_retried",
+		Help: "Count messages that were broadcasted after retries",
+	}, []string{"type"})
+	metricsOutboxFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:outbox_failed",
+		Help: "Count messages that failed to broadcast within the retry window",
+	}, []string{"type"})
+	metricsExternalIPChanges = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv:network:external_ip_changes",
+		Help: "Count changes of the external IP that were announced in the ENR",
+	})
+	metricsSyncRequestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:sync_request_errors",
+		Help: "Count failed sync requests by error class",
+	}, []string{"class"})
 )
 
 func init() {
@@ -37,12 +56,27 @@ func init() {
 	if err := prometheus.Register(metricsPeersIdentity); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricsPeersVersions); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 	if err := prometheus.Register(metricsPeerLastMsg); err != nil {
 		log.Println("could not register prometheus collector")
 	}
 	if err := prometheus.Register(metricsConnectedPeers); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricsOutboxRetried); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsOutboxFailed); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsExternalIPChanges); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsSyncRequestErrors); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
 
 func reportAllConnections(n *p2pNetwork) {
@@ -53,6 +87,7 @@ func reportAllConnections(n *p2pNetwork) {
 		ids = append(ids, pid)
 		reportPeerIdentity(n, pid)
 	}
+	reportPeersVersions(n)
 	var peersActiveDisv5 []peer.ID
 	if n.peers != nil {
 		peersActiveDisv5 = n.peers.Active()
@@ -74,9 +109,16 @@ func reportTopicPeers(n *p2pNetwork, name string, topic *pubsub.Topic) {
 func reportPeerIdentity(n *p2pNetwork, pid string) {
 	ua := n.peersIndex.GetPeerData(pid, UserAgentKey)
 	n.logger.Debug("peer identity", zap.String("peer", pid), zap.String("ua", ua))
-	uaParts := strings.Split(ua, ":")
-	if len(uaParts) > 2 {
-		metricsPeersIdentity.WithLabelValues(uaParts[2], uaParts[1], pid).Set(1)
+	parsed := parseUserAgent(ua)
+	if len(parsed.Operator) > 0 {
+		metricsPeersIdentity.WithLabelValues(parsed.Operator, parsed.version(), pid).Set(1)
+	}
+}
+
+func reportPeersVersions(n *p2pNetwork) {
+	metricsPeersVersions.Reset()
+	for _, pv := range n.PeersVersions() {
+		metricsPeersVersions.WithLabelValues(pv.Version, pv.Commit, pv.ForkID).Inc()
 	}
 }
 
@@ -0,0 +1,302 @@
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bloxapp/ssv/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// auditSegmentDuration is the time span of a single audit file
+	auditSegmentDuration = time.Hour
+	// auditSegmentExt is the extension of audit files, which are named by the start time (unix seconds) of the segment
+	auditSegmentExt = ".jsonl"
+	// auditSegmentsPerMaxSize is the min number of segments within the max size, segments are rotated once they
+	// reach their share of the max size so the oldest records can be removed
+	auditSegmentsPerMaxSize = 10
+	// directMsgAuditTopic is the topic of direct messages in audit records
+	directMsgAuditTopic = "direct"
+)
+
+var (
+	metricsAuditedMsgs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:audit:sampled_msgs",
+		Help: "Count of raw inbound messages that were sampled for auditing",
+	}, []string{"status"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsAuditedMsgs); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// msgAuditor samples raw inbound messages into a ring buffer on disk.
+// the buffer is made of (up to) hourly segment files, segments older than the retention are removed,
+// as well as the oldest segments once the total size exceeds the max size
+type msgAuditor struct {
+	logger *zap.Logger
+	lock   sync.Mutex
+
+	dir        string
+	sampleRate float64
+	retention  time.Duration
+	maxSize    int64
+
+	segmentStart time.Time
+	segment      *os.File
+	segmentSize  int64
+	enc          *json.Encoder
+
+	random func() float64
+	now    func() time.Time
+}
+
+// newMsgAuditor creates a new auditor that stores a fraction (sampleRate) of the inbound messages in the given directory,
+// the size of the directory is limited by maxSize (bytes, 0 disables)
+func newMsgAuditor(logger *zap.Logger, dir string, sampleRate float64, retention time.Duration, maxSize int64) (*msgAuditor, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create audit dir")
+	}
+	return &msgAuditor{
+		logger:     logger.With(zap.String("who", "msgAuditor")),
+		dir:        dir,
+		sampleRate: sampleRate,
+		retention:  retention,
+		maxSize:    maxSize,
+		random:     rand.Float64,
+		now:        time.Now,
+	}, nil
+}
+
+// capture stores the given message if it was sampled
+func (ma *msgAuditor) capture(pid peer.ID, topic string, data []byte) {
+	if ma.random() >= ma.sampleRate {
+		return
+	}
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	now := ma.now()
+	if err := ma.rotateNotSafe(now); err != nil {
+		metricsAuditedMsgs.WithLabelValues("failed").Inc()
+		ma.logger.Warn("could not rotate audit segment", zap.Error(err))
+		return
+	}
+	record := network.AuditRecord{
+		Time:   now.UnixNano() / int64(time.Millisecond),
+		PeerID: pid.String(),
+		Topic:  topic,
+		Data:   data,
+	}
+	if err := ma.enc.Encode(&record); err != nil {
+		metricsAuditedMsgs.WithLabelValues("failed").Inc()
+		ma.logger.Warn("could not write audit record", zap.Error(err))
+		return
+	}
+	metricsAuditedMsgs.WithLabelValues("stored").Inc()
+}
+
+// rotateNotSafe opens a new segment once the hour has changed or the current segment is full,
+// and removes expired segments (or the oldest segments if the max size was exceeded)
+func (ma *msgAuditor) rotateNotSafe(now time.Time) error {
+	hourStart := now.Truncate(auditSegmentDuration)
+	full := ma.maxSize > 0 && ma.segmentSize >= ma.maxSize/auditSegmentsPerMaxSize
+	if ma.segment != nil && !ma.segmentStart.Before(hourStart) && !full {
+		return nil
+	}
+	start := hourStart
+	if ma.segment != nil {
+		if err := ma.segment.Close(); err != nil {
+			ma.logger.Debug("could not close audit segment", zap.Error(err))
+		}
+		ma.segment, ma.enc = nil, nil
+		if full {
+			start = now.Truncate(time.Second)
+		}
+	} else if segments, err := ma.segments(); err == nil && len(segments) > 0 {
+		// continue the last segment of the hour (e.g. after a restart)
+		if last := segments[len(segments)-1]; !last.Before(hourStart) {
+			start = last
+		}
+	}
+	f, err := os.OpenFile(ma.segmentPath(start), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not open audit segment")
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "could not stat audit segment")
+	}
+	ma.segmentStart, ma.segment, ma.segmentSize = start, f, info.Size()
+	ma.enc = json.NewEncoder(&countingWriter{w: f, n: &ma.segmentSize})
+	return ma.pruneNotSafe(now)
+}
+
+// pruneNotSafe removes the segments that ended before the retention,
+// and the oldest segments (except for the current one) while the total size exceeds the max size
+func (ma *msgAuditor) pruneNotSafe(now time.Time) error {
+	segments, err := ma.segments()
+	if err != nil {
+		return err
+	}
+	var total int64
+	sizes := make([]int64, len(segments))
+	for i, start := range segments {
+		if info, err := os.Stat(ma.segmentPath(start)); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+	for i, start := range segments {
+		if start.Equal(ma.segmentStart) {
+			break
+		}
+		expired := !start.Add(auditSegmentDuration).After(now.Add(-ma.retention))
+		exceeded := ma.maxSize > 0 && total > ma.maxSize
+		if !expired && !exceeded {
+			continue
+		}
+		if err := os.Remove(ma.segmentPath(start)); err != nil {
+			return errors.Wrap(err, "could not remove audit segment")
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
+
+// countingWriter counts the bytes that were written
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	*cw.n += int64(n)
+	return n, err
+}
+
+// segments returns the start times of the existing segments in chronological order
+func (ma *msgAuditor) segments() ([]time.Time, error) {
+	files, err := ioutil.ReadDir(ma.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list audit segments")
+	}
+	var segments []time.Time
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), auditSegmentExt) {
+			continue
+		}
+		ts, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), auditSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, time.Unix(ts, 0))
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Before(segments[j])
+	})
+	return segments, nil
+}
+
+func (ma *msgAuditor) segmentPath(start time.Time) string {
+	return filepath.Join(ma.dir, fmt.Sprintf("%d%s", start.Unix(), auditSegmentExt))
+}
+
+// query returns the stored records that match the given filter in chronological order.
+// only the list of segments is taken under the lock, so captures are not blocked while reading
+func (ma *msgAuditor) query(filter network.AuditFilter) ([]*network.AuditRecord, error) {
+	ma.lock.Lock()
+	segments, err := ma.segments()
+	ma.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var from, to int64
+	if !filter.From.IsZero() {
+		from = filter.From.UnixNano() / int64(time.Millisecond)
+	}
+	if !filter.To.IsZero() {
+		to = filter.To.UnixNano() / int64(time.Millisecond)
+	}
+	results := make([]*network.AuditRecord, 0)
+	for _, start := range segments {
+		if !filter.From.IsZero() && !start.Add(auditSegmentDuration).After(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && start.After(filter.To) {
+			break
+		}
+		err := ma.readSegment(start, func(record *network.AuditRecord) bool {
+			if (from > 0 && record.Time < from) || (to > 0 && record.Time > to) ||
+				(len(filter.PeerID) > 0 && record.PeerID != filter.PeerID) ||
+				(len(filter.Topic) > 0 && record.Topic != filter.Topic) {
+				return true
+			}
+			results = append(results, record)
+			return filter.Limit <= 0 || len(results) < filter.Limit
+		})
+		if err != nil {
+			return nil, err
+		}
+		if filter.Limit > 0 && len(results) >= filter.Limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// readSegment decodes the records of the given segment until the handler returns false
+func (ma *msgAuditor) readSegment(start time.Time, handler func(record *network.AuditRecord) bool) error {
+	f, err := os.Open(ma.segmentPath(start))
+	if os.IsNotExist(err) {
+		// removed by pruning
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "could not open audit segment")
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	dec := json.NewDecoder(f)
+	for {
+		record := new(network.AuditRecord)
+		if err := dec.Decode(record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			// a partially written record (e.g. on crash) ends the segment
+			ma.logger.Debug("could not decode audit record", zap.Error(err))
+			return nil
+		}
+		if !handler(record) {
+			return nil
+		}
+	}
+}
+
+// AuditedMessages returns the sampled messages that match the given filter, false if auditing is disabled
+func (n *p2pNetwork) AuditedMessages(filter network.AuditFilter) ([]*network.AuditRecord, bool, error) {
+	if n.msgAuditor == nil {
+		return nil, false, nil
+	}
+	records, err := n.msgAuditor.query(filter)
+	return records, true, err
+}
@@ -0,0 +1,120 @@
+package p2p
+
+import (
+	"fmt"
+	"github.com/bloxapp/ssv/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMsgAuditor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	ma, err := newMsgAuditor(zap.L(), dir, 0.5, 2*time.Hour, 0)
+	require.NoError(t, err)
+	now := time.Unix(1637000000, 0).Truncate(time.Hour)
+	ma.now = func() time.Time {
+		return now
+	}
+	p1, p2 := peer.ID("p1"), peer.ID("p2")
+
+	t.Run("sampling", func(t *testing.T) {
+		ma.random = func() float64 { return 0.7 }
+		ma.capture(p1, "topic1", []byte("skipped"))
+		ma.random = func() float64 { return 0.2 }
+		ma.capture(p1, "topic1", []byte("garbage"))
+		ma.capture(p2, directMsgAuditTopic, []byte("direct"))
+
+		records, err := ma.query(network.AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, p1.String(), records[0].PeerID)
+		require.Equal(t, "topic1", records[0].Topic)
+		require.Equal(t, []byte("garbage"), records[0].Data)
+		require.Equal(t, now.UnixNano()/int64(time.Millisecond), records[0].Time)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		records, err := ma.query(network.AuditFilter{PeerID: p2.String()})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, []byte("direct"), records[0].Data)
+
+		records, err = ma.query(network.AuditFilter{Topic: "topic1"})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+
+		records, err = ma.query(network.AuditFilter{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+
+		records, err = ma.query(network.AuditFilter{From: now.Add(time.Minute)})
+		require.NoError(t, err)
+		require.Len(t, records, 0)
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		ma.capture(p1, "topic1", []byte("second hour"))
+		records, err := ma.query(network.AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+
+		now = now.Add(2 * time.Hour)
+		ma.capture(p1, "topic1", []byte("fourth hour"))
+		segments, err := ma.segments()
+		require.NoError(t, err)
+		require.Len(t, segments, 2)
+		records, err = ma.query(network.AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, []byte("second hour"), records[0].Data)
+	})
+}
+
+func TestMsgAuditor_MaxSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	// segments are rotated every ~100 bytes
+	ma, err := newMsgAuditor(zap.L(), dir, 1, 24*time.Hour, 1000)
+	require.NoError(t, err)
+	now := time.Unix(1637000000, 0).Truncate(time.Hour)
+	ma.now = func() time.Time {
+		return now
+	}
+	ma.random = func() float64 { return 0 }
+
+	for i := 0; i < 100; i++ {
+		now = now.Add(time.Second)
+		ma.capture(peer.ID("p1"), "topic1", []byte(fmt.Sprintf("msg-%d", i)))
+	}
+	segments, err := ma.segments()
+	require.NoError(t, err)
+	var total int64
+	for _, start := range segments {
+		info, err := os.Stat(ma.segmentPath(start))
+		require.NoError(t, err)
+		total += info.Size()
+	}
+	// the cap is exceeded by the current segment at most
+	require.LessOrEqual(t, total, int64(1000)+ma.segmentSize)
+
+	records, err := ma.query(network.AuditFilter{})
+	require.NoError(t, err)
+	require.Less(t, len(records), 100)
+	// the newest records were kept
+	require.Equal(t, []byte("msg-99"), records[len(records)-1].Data)
+}
@@ -0,0 +1,147 @@
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// msgRateInterval is the window in which inbound messages are counted
+	msgRateInterval = time.Second
+)
+
+var (
+	metricsTopicMsgRate = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv:network:pubsub:topic_msg_rate",
+		Help:    "Inbound messages per second of a topic, by topic kind (validator, decided, main or direct)",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"topic"})
+	metricsPeerMsgRate = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ssv:network:pubsub:peer_msg_rate",
+		Help:    "Inbound messages per second of a single origin peer on a topic",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	metricsGreyListedPeers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:network:pubsub:grey_listed_peers",
+		Help: "Count of peers that are currently grey-listed, a peer is counted once per topic",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsTopicMsgRate); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsPeerMsgRate); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsGreyListedPeers); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// msgRateTracker counts inbound messages per topic and origin peer (the author of the message, not the relaying peer).
+// peers that exceed the limit on a validator topic are grey-listed on that topic, i.e. their messages are dropped for a while
+type msgRateTracker struct {
+	lock sync.Mutex
+
+	limit            int
+	greyListDuration time.Duration
+
+	topicCounts map[string]int
+	peerCounts  map[string]map[peer.ID]int
+	greyList    map[greyListKey]time.Time
+
+	now func() time.Time
+}
+
+// greyListKey is a peer that is grey-listed on a topic
+type greyListKey struct {
+	topic string
+	pid   peer.ID
+}
+
+// newMsgRateTracker creates a new tracker, grey-listing is disabled if limit is 0
+func newMsgRateTracker(limit int, greyListDuration time.Duration) *msgRateTracker {
+	return &msgRateTracker{
+		limit:            limit,
+		greyListDuration: greyListDuration,
+		topicCounts:      make(map[string]int),
+		peerCounts:       make(map[string]map[peer.ID]int),
+		greyList:         make(map[greyListKey]time.Time),
+		now:              time.Now,
+	}
+}
+
+// onMessage counts a message of the given origin, returns false if the message should be dropped
+// as the origin is grey-listed on the topic
+func (t *msgRateTracker) onMessage(topic string, origin peer.ID, validatorTopic bool) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.topicCounts[topicLabel(topic, validatorTopic)]++
+	peers, ok := t.peerCounts[topic]
+	if !ok {
+		peers = make(map[peer.ID]int)
+		t.peerCounts[topic] = peers
+	}
+	peers[origin]++
+
+	key := greyListKey{topic: topic, pid: origin}
+	if until, found := t.greyList[key]; found {
+		if t.now().Before(until) {
+			return false
+		}
+		delete(t.greyList, key)
+		metricsGreyListedPeers.Set(float64(len(t.greyList)))
+	}
+	if validatorTopic && t.limit > 0 && peers[origin] > t.limit {
+		t.greyList[key] = t.now().Add(t.greyListDuration)
+		metricsGreyListedPeers.Set(float64(len(t.greyList)))
+		return false
+	}
+	return true
+}
+
+// flush reports the rates of the last window, resets the counters and removes expired grey-listed peers
+func (t *msgRateTracker) flush() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for label, count := range t.topicCounts {
+		metricsTopicMsgRate.WithLabelValues(label).Observe(float64(count) / msgRateInterval.Seconds())
+	}
+	for _, peers := range t.peerCounts {
+		for _, count := range peers {
+			metricsPeerMsgRate.Observe(float64(count) / msgRateInterval.Seconds())
+		}
+	}
+	t.topicCounts = make(map[string]int)
+	t.peerCounts = make(map[string]map[peer.ID]int)
+
+	now := t.now()
+	for key, until := range t.greyList {
+		if !now.Before(until) {
+			delete(t.greyList, key)
+		}
+	}
+	metricsGreyListedPeers.Set(float64(len(t.greyList)))
+}
+
+// topicLabel returns the kind of the given topic, used as a bounded metrics label
+func topicLabel(topic string, validatorTopic bool) string {
+	switch {
+	case topic == directMsgAuditTopic:
+		return "direct"
+	case validatorTopic:
+		return "validator"
+	case strings.Contains(topic, "decided"):
+		return "decided"
+	default:
+		return "main"
+	}
+}
@@ -0,0 +1,64 @@
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMsgRateTracker(t *testing.T) {
+	now := time.Now()
+	tracker := newMsgRateTracker(3, time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	spammer := peer.ID("spammer")
+	honest := peer.ID("honest")
+
+	t.Run("grey-list peer that exceeds the limit", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.True(t, tracker.onMessage("topic", spammer, true))
+		}
+		require.False(t, tracker.onMessage("topic", spammer, true))
+		require.True(t, tracker.onMessage("topic", honest, true))
+		require.Equal(t, 4, tracker.peerCounts["topic"][spammer])
+		// topics are counted by kind to keep the metrics labels bounded
+		require.Equal(t, 5, tracker.topicCounts["validator"])
+	})
+
+	t.Run("grey-listed peer is dropped after flush", func(t *testing.T) {
+		tracker.flush()
+		require.Len(t, tracker.topicCounts, 0)
+		require.False(t, tracker.onMessage("topic", spammer, true))
+		// peers are grey-listed per topic
+		require.True(t, tracker.onMessage("other-topic", spammer, true))
+		require.True(t, tracker.onMessage("main", spammer, false))
+	})
+
+	t.Run("grey-listing expires", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		require.True(t, tracker.onMessage("topic", spammer, true))
+		tracker.flush()
+		require.Len(t, tracker.greyList, 0)
+	})
+
+	t.Run("non validator topic", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			require.True(t, tracker.onMessage("main", spammer, false))
+		}
+	})
+}
+
+func TestTopicLabel(t *testing.T) {
+	require.Equal(t, "validator", topicLabel("bloxstaking.ssv.abcd", true))
+	require.Equal(t, "decided", topicLabel("bloxstaking.ssv.decided.3", false))
+	require.Equal(t, "main", topicLabel("bloxstaking.ssv.main", false))
+	require.Equal(t, "direct", topicLabel(directMsgAuditTopic, true))
+}
+
+func TestMsgRateTracker_Disabled(t *testing.T) {
+	tracker := newMsgRateTracker(0, time.Minute)
+	for i := 0; i < 100; i++ {
+		require.True(t, tracker.onMessage("topic", peer.ID("peer"), true))
+	}
+}
@@ -0,0 +1,69 @@
+package p2p
+
+import (
+	"context"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// oversizedMsgReason is the reason of reporting peers that sent oversized messages
+	oversizedMsgReason = "message_too_large"
+)
+
+// checkMsgSize returns an error if the given size exceeds the max size, 0 means no limit
+func checkMsgSize(size, maxSize int) error {
+	if maxSize > 0 && size > maxSize {
+		return errs.Mark(errors.Errorf("message of %d bytes exceeds the max size of %d bytes", size, maxSize), errs.ErrMessageTooLarge)
+	}
+	return nil
+}
+
+// msgSizeValidator returns a pubsub validator that rejects oversized messages before they are decoded.
+// rejected messages are not propagated and count as invalid deliveries of the sender
+func (n *p2pNetwork) msgSizeValidator() pubsub.ValidatorEx {
+	return func(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+		if err := checkMsgSize(len(msg.Data), n.cfg.MaxPubSubMsgSize); err != nil {
+			// messages of this node are not reported
+			if pid != n.host.ID() {
+				n.reportOversizedMsg(pid.String(), err)
+			}
+			return pubsub.ValidationReject
+		}
+		return pubsub.ValidationAccept
+	}
+}
+
+// registerMsgSizeValidator registers the size validator of the given topic, if a limit was configured
+func (n *p2pNetwork) registerMsgSizeValidator(topicName string) {
+	if n.cfg.MaxPubSubMsgSize <= 0 {
+		return
+	}
+	if err := n.pubsub.RegisterTopicValidator(topicName, n.msgSizeValidator()); err != nil {
+		// the validator is registered already if the topic was rejoined w/o being closed
+		n.trace("could not register message size validator", zap.String("topic", topicName), zap.Error(err))
+	}
+}
+
+// unregisterMsgSizeValidator removes the size validator of the given topic, so it can be registered again once rejoined
+func (n *p2pNetwork) unregisterMsgSizeValidator(topicName string) {
+	if n.cfg.MaxPubSubMsgSize <= 0 {
+		return
+	}
+	if err := n.pubsub.UnregisterTopicValidator(topicName); err != nil {
+		n.trace("could not unregister message size validator", zap.String("topic", topicName), zap.Error(err))
+	}
+}
+
+// reportOversizedMsg penalizes the given peer if the given error was caused by an oversized message
+func (n *p2pNetwork) reportOversizedMsg(peerStr string, err error) {
+	if !errors.Is(err, errs.ErrMessageTooLarge) {
+		return
+	}
+	network.ReportRejectedMessage(err)
+	n.ReportBadPeer(peerStr, oversizedMsgReason)
+}
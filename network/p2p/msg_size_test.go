@@ -0,0 +1,17 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCheckMsgSize(t *testing.T) {
+	require.NoError(t, checkMsgSize(10, 10))
+	require.NoError(t, checkMsgSize(1<<30, 0))
+
+	err := checkMsgSize(11, 10)
+	require.EqualError(t, err, "message of 11 bytes exceeds the max size of 10 bytes")
+	require.True(t, errors.Is(err, errs.ErrMessageTooLarge))
+}
@@ -0,0 +1,83 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/storage/basedb"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// operatorPeersPrefix is the db prefix of the mapping of operators to peers
+var operatorPeersPrefix = []byte("p2p-operator-peer-")
+
+// operatorPeers maps operators (by the hash of their public key) to their peers.
+// the mapping is taken from the user agent of connected peers, and is persisted (if a db was provided)
+// so committee peers can be preferred right after a restart, before the peers were indexed
+type operatorPeers struct {
+	logger *zap.Logger
+	db     basedb.IDb
+
+	lock  sync.RWMutex
+	peers map[string]string
+}
+
+// newOperatorPeers creates a new instance and loads the persisted mapping
+func newOperatorPeers(logger *zap.Logger, db basedb.IDb) *operatorPeers {
+	op := &operatorPeers{
+		logger: logger,
+		db:     db,
+		peers:  make(map[string]string),
+	}
+	if db == nil {
+		return op
+	}
+	objs, err := db.GetAllByCollection(operatorPeersPrefix)
+	if err != nil {
+		logger.Warn("could not load operator peers", zap.Error(err))
+		return op
+	}
+	for _, obj := range objs {
+		op.peers[string(obj.Key)] = string(obj.Value)
+	}
+	return op
+}
+
+// get returns the peer of the given operator (hash)
+func (op *operatorPeers) get(operator string) (string, bool) {
+	op.lock.RLock()
+	defer op.lock.RUnlock()
+
+	pid, found := op.peers[operator]
+	return pid, found
+}
+
+// set maps the given operator (hash) to the given peer, the mapping is persisted only if it was changed
+func (op *operatorPeers) set(operator, pid string) {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+
+	if op.peers[operator] == pid {
+		return
+	}
+	op.peers[operator] = pid
+	if op.db == nil {
+		return
+	}
+	if err := op.db.Set(operatorPeersPrefix, []byte(operator), []byte(pid)); err != nil {
+		op.logger.Warn("could not save operator peer", zap.String("peer", pid), zap.Error(err))
+	}
+}
+
+// OperatorPeer returns the peer of the operator with the given public key, false if unknown
+func (n *p2pNetwork) OperatorPeer(operatorPubKey string) (string, bool) {
+	return n.operatorPeers.get(pubKeyHash(operatorPubKey))
+}
+
+// indexOperatorPeers maps the operators of the connected peers that were indexed
+func (n *p2pNetwork) indexOperatorPeers() {
+	for _, pid := range n.host.Network().Peers() {
+		p := pid.String()
+		if operator := n.peersIndex.GetPeerData(p, OperatorKey); len(operator) > 0 {
+			n.operatorPeers.set(operator, p)
+		}
+	}
+}
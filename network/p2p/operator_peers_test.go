@@ -0,0 +1,50 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOperatorPeers(t *testing.T) {
+	logger := zap.L()
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: logger,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	op := newOperatorPeers(logger, db)
+	_, found := op.get(pubKeyHash("operator-1"))
+	require.False(t, found)
+
+	op.set(pubKeyHash("operator-1"), "peer-1")
+	op.set(pubKeyHash("operator-2"), "peer-2")
+	// the operator was moved to another peer
+	op.set(pubKeyHash("operator-2"), "peer-3")
+
+	pid, found := op.get(pubKeyHash("operator-2"))
+	require.True(t, found)
+	require.Equal(t, "peer-3", pid)
+
+	// the mapping is loaded after a restart
+	loaded := newOperatorPeers(logger, db)
+	pid, found = loaded.get(pubKeyHash("operator-1"))
+	require.True(t, found)
+	require.Equal(t, "peer-1", pid)
+	pid, found = loaded.get(pubKeyHash("operator-2"))
+	require.True(t, found)
+	require.Equal(t, "peer-3", pid)
+
+	// w/o db the mapping is kept in memory
+	inMem := newOperatorPeers(logger, nil)
+	inMem.set(pubKeyHash("operator-1"), "peer-1")
+	n := &p2pNetwork{operatorPeers: inMem}
+	pid, found = n.OperatorPeer("operator-1")
+	require.True(t, found)
+	require.Equal(t, "peer-1", pid)
+}
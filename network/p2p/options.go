@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"fmt"
+	"github.com/bloxapp/ssv/network/p2p/tracing"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/peer"
 	noise "github.com/libp2p/go-libp2p-noise"
@@ -9,7 +10,6 @@ import (
 	libp2ptcp "github.com/libp2p/go-tcp-transport"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
-	"github.com/prysmaticlabs/prysm/config/features"
 	"go.uber.org/zap"
 	"time"
 )
@@ -96,7 +96,7 @@ func (n *p2pNetwork) configureAddrs() ([]libp2p.Option, error) {
 	// AddrFactory for host address if provided
 	if n.cfg.HostAddress != "" {
 		opts = append(opts, libp2p.AddrsFactory(func(addrs []ma.Multiaddr) []ma.Multiaddr {
-			external, err := buildMultiAddress(n.cfg.HostAddress, uint(n.cfg.TCPPort))
+			external, err := buildMultiAddress(n.hostAddress(), uint(n.cfg.TCPPort))
 			if err != nil {
 				n.logger.Error("Unable to create external multiaddress", zap.Error(err))
 			} else {
@@ -146,7 +146,13 @@ func (n *p2pNetwork) newGossipPubsub(cfg *Config) (*pubsub.PubSub, error) {
 	}
 
 	if len(cfg.PubSubTraceOut) > 0 {
-		tracer, err := pubsub.NewPBTracer(cfg.PubSubTraceOut)
+		tracer, err := tracing.NewTracer(n.ctx, tracing.Options{
+			Logger:           n.logger,
+			Path:             cfg.PubSubTraceOut,
+			Format:           cfg.PubSubTraceFormat,
+			MaxSize:          cfg.PubSubTraceMaxSize,
+			RotationInterval: cfg.PubSubTraceRotation,
+		})
 		if err != nil {
 			return nil, errors.Wrap(err, "could not create pubsub tracer")
 		}
@@ -154,6 +160,14 @@ func (n *p2pNetwork) newGossipPubsub(cfg *Config) (*pubsub.PubSub, error) {
 		psOpts = append(psOpts, pubsub.WithEventTracer(tracer))
 	}
 
+	n.meshTracker = newMeshTracker()
+	psOpts = append(psOpts, pubsub.WithRawTracer(n.meshTracker))
+
+	if cfg.PubSubScoreInspect {
+		n.gossipInspector = newGossipInspector()
+		psOpts = append(psOpts, n.gossipInspector.pubsubOptions()...)
+	}
+
 	setGlobalPubSubParameters()
 
 	// Create a new PubSub service using the GossipSub router
@@ -168,15 +182,6 @@ func pubsubGossipParam() pubsub.GossipSubParams {
 	gParams.HeartbeatInterval = gossipSubHeartbeatInterval
 	gParams.HistoryLength = gossipSubMcacheLen
 	gParams.HistoryGossip = gossipSubMcacheGossip
-
-	// Set a larger gossip history to ensure that slower
-	// messages have a longer time to be propagated. This
-	// comes with the tradeoff of larger memory usage and
-	// size of the seen message cache.
-	if features.Get().EnableLargerGossipHistory {
-		gParams.HistoryLength = 12
-		gParams.HistoryGossip = 5
-	}
 	return gParams
 }
 
@@ -0,0 +1,146 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/utils/errs"
+	"time"
+
+	"github.com/herumi/bls-eth-go-binary/bls"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	outboxInitialBackoff = 100 * time.Millisecond
+	outboxMaxBackoff     = time.Second
+	// outboxRetryWorkers is the number of workers that retry failed broadcasts
+	outboxRetryWorkers = 16
+	// outboxRetryQueueSize is the max number of failed broadcasts that are waiting for a retry worker
+	outboxRetryQueueSize = 1024
+)
+
+var (
+	errNoPeers     = errs.Mark(errors.New("no peers in topic"), errs.ErrNoPeers)
+	errNoMeshPeers = errs.Mark(errors.New("not enough mesh peers in topic"), errs.ErrNoPeers)
+)
+
+// publish is the outbox of validator topics, it publishes the given message on the topic of the validator.
+// failed attempts (e.g. closed topic or no peers yet) are queued and retried in the background
+// until the retry window (BroadcastRetryWindow) is over, the topic is re-joined if needed.
+// an error is returned only if the message can't be retried (retries are disabled or the retry queue is full).
+// if DirectMessaging is enabled, the message is also sent directly to the peers of the validator topic
+func (n *p2pNetwork) publish(validatorPK []byte, msgBytes []byte, msgType string) error {
+	return n.publishWithDeadline(validatorPK, msgBytes, msgType, time.Time{})
+}
+
+// publishWithDeadline is the same as publish, while retries are also bounded by the given deadline (unless zero)
+func (n *p2pNetwork) publishWithDeadline(validatorPK []byte, msgBytes []byte, msgType string, dutyDeadline time.Time) error {
+	if n.cfg.DirectMessaging {
+		n.sendDirect(validatorPK, msgBytes)
+	}
+	err := n.tryPublish(validatorPK, msgBytes)
+	if err == nil {
+		return nil
+	}
+	deadline := time.Now().Add(n.cfg.BroadcastRetryWindow)
+	if !dutyDeadline.IsZero() && dutyDeadline.Before(deadline) {
+		deadline = dutyDeadline
+	}
+	backoff := n.cfg.BroadcastRetryBackoff
+	if backoff <= 0 {
+		backoff = outboxInitialBackoff
+	}
+	if time.Now().Add(backoff).After(deadline) {
+		metricsOutboxFailed.WithLabelValues(msgType).Inc()
+		return errors.Wrapf(err, "failed to broadcast %s message", msgType)
+	}
+	// retries are done off the caller (e.g. the event loop of an instance)
+	queued := n.outboxRetries.submit(func() {
+		n.retryPublish(validatorPK, msgBytes, msgType, backoff, deadline)
+	})
+	if !queued {
+		metricsOutboxFailed.WithLabelValues(msgType).Inc()
+		return errors.Wrapf(err, "failed to broadcast %s message, retry queue is full", msgType)
+	}
+	n.logger.Debug("could not broadcast, queued for retry", zap.String("type", msgType), zap.Error(err))
+	return nil
+}
+
+// retryPublish retries a failed broadcast with an exponential backoff until it succeeds or the deadline is reached
+func (n *p2pNetwork) retryPublish(validatorPK []byte, msgBytes []byte, msgType string, backoff time.Duration, deadline time.Time) {
+	attempts := 1
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		attempts++
+		err := n.tryPublish(validatorPK, msgBytes)
+		if err == nil {
+			metricsOutboxRetried.WithLabelValues(msgType).Inc()
+			return
+		}
+		backoff *= 2
+		if backoff > outboxMaxBackoff {
+			backoff = outboxMaxBackoff
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			metricsOutboxFailed.WithLabelValues(msgType).Inc()
+			n.logger.Warn("failed to broadcast message", zap.String("type", msgType),
+				zap.Int("attempts", attempts), zap.Error(err))
+			return
+		}
+		n.logger.Debug("could not broadcast, retrying", zap.String("type", msgType),
+			zap.Int("attempts", attempts), zap.Error(err))
+	}
+}
+
+// tryPublish makes a single attempt to publish the message
+func (n *p2pNetwork) tryPublish(validatorPK []byte, msgBytes []byte) error {
+	topic, err := n.getTopic(validatorPK)
+	if err != nil {
+		if topic, err = n.rejoinTopic(validatorPK); err != nil {
+			return err
+		}
+	}
+	if len(topic.ListPeers()) == 0 {
+		return errNoPeers
+	}
+	// a message that is published before the local mesh was formed might not propagate
+	if n.cfg.BroadcastMinMeshPeers > 0 && n.meshTracker != nil &&
+		n.meshTracker.meshPeers(topic.String()) < n.cfg.BroadcastMinMeshPeers {
+		return errNoMeshPeers
+	}
+	if err := topic.Publish(n.ctx, msgBytes); err != nil {
+		if err == pubsub.ErrTopicClosed {
+			n.forgetTopic(validatorPK, topic)
+		}
+		return errors.Wrap(err, "failed to publish")
+	}
+	return nil
+}
+
+// rejoinTopic joins (and subscribes) the topic of the given validator
+func (n *p2pNetwork) rejoinTopic(validatorPK []byte) (*pubsub.Topic, error) {
+	pk := &bls.PublicKey{}
+	if err := pk.Deserialize(validatorPK); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize validator public key")
+	}
+	n.logger.Debug("re-joining validator topic", zap.String("pubKey", pk.SerializeToHexStr()))
+	if err := n.SubscribeToValidatorNetwork(pk); err != nil {
+		return nil, errors.Wrap(err, "failed to re-join topic")
+	}
+	return n.getTopic(validatorPK)
+}
+
+// forgetTopic removes a closed topic, so it will be re-joined in the next attempt
+func (n *p2pNetwork) forgetTopic(validatorPK []byte, topic *pubsub.Topic) {
+	n.psTopicsLock.Lock()
+	defer n.psTopicsLock.Unlock()
+
+	pk := n.fork.ValidatorTopicID(validatorPK)
+	if n.cfg.Topics[pk] == topic {
+		delete(n.cfg.Topics, pk)
+	}
+}
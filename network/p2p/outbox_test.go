@@ -0,0 +1,84 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloxapp/ssv/fixtures"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestP2PNetworker_BroadcastRetry(t *testing.T) {
+	threshold.Init()
+	logger := zaptest.NewLogger(t)
+
+	peer1, peer2 := testPeers(t, logger)
+
+	pk := &bls.PublicKey{}
+	require.NoError(t, pk.Deserialize(fixtures.RefPk))
+	// peer1 didn't join the topic, it should be joined by the outbox
+	require.NoError(t, peer2.SubscribeToValidatorNetwork(pk))
+	peer2Chan := peer2.ReceivedMsgChan()
+
+	msg := &proto.SignedMessage{
+		Message: &proto.Message{
+			Type:   proto.RoundState_Prepare,
+			Round:  1,
+			Lambda: []byte("test-lambda"),
+			Value:  []byte("test-value"),
+		},
+	}
+
+	t.Run("no retries", func(t *testing.T) {
+		sk := &bls.SecretKey{}
+		sk.SetByCSPRNG()
+		// no one is subscribed to the topic of this validator
+		err := peer1.Broadcast(sk.GetPublicKey().Serialize(), msg)
+		require.EqualError(t, err, "failed to broadcast ibft message: no peers in topic")
+		require.True(t, errors.Is(err, errs.ErrNoPeers))
+	})
+
+	t.Run("broadcast after retries", func(t *testing.T) {
+		peer1.(*p2pNetwork).cfg.BroadcastRetryWindow = 5 * time.Second
+		require.NoError(t, peer1.Broadcast(pk.Serialize(), msg))
+		select {
+		case received := <-peer2Chan:
+			require.Equal(t, msg, received)
+		case <-time.After(5 * time.Second):
+			t.Fatal("message wasn't received")
+		}
+	})
+
+	t.Run("mesh peers precondition", func(t *testing.T) {
+		peer1.(*p2pNetwork).cfg.BroadcastRetryWindow = 0
+		peer1.(*p2pNetwork).cfg.BroadcastMinMeshPeers = 2
+		err := peer1.Broadcast(pk.Serialize(), msg)
+		require.EqualError(t, err, "failed to broadcast ibft message: not enough mesh peers in topic")
+
+		peer1.(*p2pNetwork).cfg.BroadcastMinMeshPeers = 1
+		require.Eventually(t, func() bool {
+			return peer1.Broadcast(pk.Serialize(), msg) == nil
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("retries bounded by deadline", func(t *testing.T) {
+		peer1.(*p2pNetwork).cfg.BroadcastRetryWindow = 10 * time.Second
+		sk := &bls.SecretKey{}
+		sk.SetByCSPRNG()
+		failed := testutil.ToFloat64(metricsOutboxFailed.WithLabelValues("ibft"))
+		start := time.Now()
+		// no peers yet, the message is queued for retries instead of blocking the caller
+		require.NoError(t, peer1.(*p2pNetwork).BroadcastWithDeadline(sk.GetPublicKey().Serialize(), msg, start.Add(500*time.Millisecond)))
+		require.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(metricsOutboxFailed.WithLabelValues("ibft")) == failed+1
+		}, 2*time.Second, 50*time.Millisecond)
+	})
+}
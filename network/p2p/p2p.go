@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rsa"
-	"fmt"
 	"github.com/bloxapp/ssv/network/forks"
+	"github.com/bloxapp/ssv/network/p2p/peers"
 	"github.com/bloxapp/ssv/utils/commons"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
-	"github.com/prysmaticlabs/prysm/async"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	lru "github.com/hashicorp/golang-lru"
 	"sync"
 	"time"
 
@@ -18,7 +20,6 @@ import (
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/p2p/protocol/identify"
 	"github.com/pkg/errors"
-	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv/ibft/proto"
@@ -39,10 +40,14 @@ const (
 )
 
 const (
-	baseSyncStream           = "/sync/"
-	highestDecidedStream     = baseSyncStream + "highest_decided"
-	decidedByRangeStream     = baseSyncStream + "decided_by_range"
-	lastChangeRoundMsgStream = baseSyncStream + "last_change_round"
+	// sync streams names, the protocol ids are provided by the fork
+	highestDecidedStream     = "highest_decided"
+	decidedByRangeStream     = "decided_by_range"
+	lastChangeRoundMsgStream = "last_change_round"
+	decidedHistoryRootStream = "decided_history_root"
+
+	// syncResponsesCacheSize is the max number of encoded decided range responses that are kept in memory
+	syncResponsesCacheSize = 128
 )
 
 type listener struct {
@@ -50,9 +55,16 @@ type listener struct {
 	sigCh     chan *proto.SignedMessage
 	decidedCh chan *proto.SignedMessage
 	syncCh    chan *network.SyncChanObj
+	dkgCh     chan *network.DKGMessage
+
+	heartbeatCh         chan *network.FailoverHeartbeat
+	highestDecidedCh    chan *proto.SignedMessage
+	operatorHeartbeatCh chan *network.OperatorHeartbeat
+	controlMsgCh        chan *network.ControlMessage
 }
 
-// p2pNetwork implements network.Network interface using P2P
+// p2pNetwork implements network.Network, network.DKG, network.Failover, network.HighestDecided,
+// network.OperatorHeartbeats and network.ControlMessages interfaces using P2P
 type p2pNetwork struct {
 	ctx             context.Context
 	cfg             *Config
@@ -61,17 +73,43 @@ type p2pNetwork struct {
 	listeners       []listener
 	logger          *zap.Logger
 	privKey         *ecdsa.PrivateKey
-	peers           *peers.Status
+	peers           peers.Status
 	host            p2pHost.Host
 	pubsub          *pubsub.PubSub
 	peersIndex      PeersIndex
+	idService       *identify.IDService
 	operatorPrivKey *rsa.PrivateKey
 	fork            forks.Fork
 
 	psSubs       map[string]context.CancelFunc
 	psTopicsLock *sync.RWMutex
 
-	reportLastMsg bool
+	reportLastMsg   bool
+	msgRates        *msgRateTracker
+	syncLimiter     *syncLimiter
+	gossipInspector *gossipInspector
+	// meshTracker tracks the local mesh of topics, used as a precondition for broadcasting
+	meshTracker *meshTracker
+	// syncResponses is an LRU cache of encoded decided range responses
+	syncResponses *lru.Cache
+	// seenMsgs dedups consensus messages that are received both on gossip and directly,
+	// nil if direct messaging is disabled
+	seenMsgs *seenMsgs
+	// directMsgsOut and directMsgsIn are the workers of outbound and inbound direct messages
+	directMsgsOut *workerPool
+	directMsgsIn  *workerPool
+	// outboxRetries are the workers that retry failed broadcasts
+	outboxRetries *workerPool
+	// operatorPeers maps operators to their peers, used to prefer committee peers in sync
+	operatorPeers *operatorPeers
+	// msgAuditor samples raw inbound messages, nil if auditing is disabled
+	msgAuditor *msgAuditor
+	// syncPolicy authorizes inbound sync requests
+	syncPolicy *syncPolicy
+
+	hostAddressLock sync.RWMutex
+	// dnsResolver is used by DNS discovery, system DNS is used if nil
+	dnsResolver dnsdisc.Resolver
 }
 
 // New is the constructor of p2pNetworker
@@ -91,6 +129,29 @@ func New(ctx context.Context, logger *zap.Logger, cfg *Config) (network.Network,
 		psTopicsLock:    &sync.RWMutex{},
 		reportLastMsg:   cfg.ReportLastMsg,
 		fork:            cfg.Fork,
+		msgRates:        newMsgRateTracker(cfg.MsgRateLimit, cfg.GreyListDuration),
+		syncLimiter:     newSyncLimiter(cfg.MaxSyncStreams, cfg.MaxSyncStreamsPerPeer),
+	}
+	// an error is returned only for non-positive sizes
+	n.syncResponses, _ = lru.New(syncResponsesCacheSize)
+	n.outboxRetries = newWorkerPool(ctx, outboxRetryWorkers, outboxRetryQueueSize)
+	if cfg.DirectMessaging {
+		n.seenMsgs = newSeenMsgs(seenMsgsCacheSize)
+		n.directMsgsOut = newWorkerPool(ctx, directMsgWorkers, directMsgQueueSize)
+		n.directMsgsIn = newWorkerPool(ctx, directMsgWorkers, directMsgQueueSize)
+	}
+	n.operatorPeers = newOperatorPeers(logger, cfg.DB)
+	syncPolicy, err := newSyncPolicy(cfg.SyncPolicy, append([]string{cfg.ExporterPeerID}, cfg.SyncAllowlist...))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create sync policy")
+	}
+	n.syncPolicy = syncPolicy
+	if cfg.AuditSampleRate > 0 {
+		auditor, err := newMsgAuditor(logger, cfg.AuditDir, cfg.AuditSampleRate, cfg.AuditRetention, cfg.AuditMaxSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create messages auditor")
+		}
+		n.msgAuditor = auditor
 	}
 
 	if cfg.NetworkPrivateKey != nil {
@@ -130,7 +191,8 @@ func New(ctx context.Context, logger *zap.Logger, cfg *Config) (network.Network,
 		}
 		n.logger.Info("libp2p User Agent", zap.String("value", ua))
 	}
-	n.peersIndex = NewPeersIndex(n.host, ids, n.logger)
+	n.idService = ids
+	n.peersIndex = NewPeersIndex(n.host, ids, n.logger, cfg.DB)
 
 	n.host.Network().Notify(n.notifee())
 
@@ -151,6 +213,8 @@ func New(ctx context.Context, logger *zap.Logger, cfg *Config) (network.Network,
 	n.setStreamHandlers()
 
 	n.watchPeers()
+	n.watchMsgRates()
+	n.watchExternalIP()
 
 	return n, nil
 }
@@ -159,10 +223,13 @@ func (n *p2pNetwork) setStreamHandlers() {
 	n.setHighestDecidedStreamHandler()
 	n.setDecidedByRangeStreamHandler()
 	n.setLastChangeRoundStreamHandler()
+	n.setDecidedHistoryRootStreamHandler()
+	if n.cfg.DirectMessaging {
+		n.setDirectMsgStreamHandler()
+	}
 }
 
 func (n *p2pNetwork) notifee() *libp2pnetwork.NotifyBundle {
-	// TODO: add connection state
 	return &libp2pnetwork.NotifyBundle{
 		ConnectedF: func(net libp2pnetwork.Network, conn libp2pnetwork.Conn) {
 			if conn == nil || conn.RemoteMultiaddr() == nil {
@@ -173,7 +240,9 @@ func (n *p2pNetwork) notifee() *libp2pnetwork.NotifyBundle {
 					zap.String("conn", conn.ID()),
 					zap.String("multiaddr", conn.RemoteMultiaddr().String()),
 					zap.String("peerID", conn.RemotePeer().String()))
-				// TODO: add connection states management
+				if n.peers != nil {
+					n.peers.SetConnected(conn.RemotePeer())
+				}
 			}()
 		},
 		DisconnectedF: func(net libp2pnetwork.Network, conn libp2pnetwork.Conn) {
@@ -189,16 +258,21 @@ func (n *p2pNetwork) notifee() *libp2pnetwork.NotifyBundle {
 					zap.String("conn", conn.ID()),
 					zap.String("multiaddr", conn.RemoteMultiaddr().String()),
 					zap.String("peerID", conn.RemotePeer().String()))
+				if n.peers != nil {
+					n.peers.SetDisconnected(conn.RemotePeer())
+				}
 			}()
 		},
 	}
 }
 
 func (n *p2pNetwork) watchPeers() {
-	async.RunEvery(n.ctx, 1*time.Minute, func() {
+	tasks.RunEvery(n.ctx, 1*time.Minute, func() {
 		// index all peers and report
 		go func() {
 			n.peersIndex.Run()
+			n.reportPeersScores()
+			n.indexOperatorPeers()
 			reportAllConnections(n)
 		}()
 
@@ -211,20 +285,31 @@ func (n *p2pNetwork) watchPeers() {
 	})
 }
 
+// watchMsgRates reports and resets inbound message rates every interval
+func (n *p2pNetwork) watchMsgRates() {
+	tasks.RunEvery(n.ctx, msgRateInterval, n.msgRates.flush)
+}
+
 func (n *p2pNetwork) MaxBatch() uint64 {
 	return n.cfg.MaxBatchResponse
 }
 
 func (n *p2pNetwork) getUserAgent() string {
-	ua := commons.GetBuildData()
+	ua := userAgent{
+		BuildData: commons.GetBuildData(),
+		Commit:    commons.GetCommit(),
+	}
+	if n.fork != nil {
+		ua.ForkID = n.fork.ID()
+	}
 	if n.operatorPrivKey != nil {
 		operatorPubKey, err := rsaencryption.ExtractPublicKey(n.operatorPrivKey)
 		if err != nil || len(operatorPubKey) == 0 {
 			n.logger.Error("could not extract operator public key", zap.Error(err))
 		}
-		ua = fmt.Sprintf("%s:%s", ua, pubKeyHash(operatorPubKey))
+		ua.Operator = pubKeyHash(operatorPubKey)
 	}
-	return ua
+	return ua.String()
 }
 
 func (n *p2pNetwork) getOperatorPubKey() (string, error) {
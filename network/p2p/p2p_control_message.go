@@ -0,0 +1,49 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// BroadcastControlMessage broadcasts the given control message on the main topic
+func (n *p2pNetwork) BroadcastControlMessage(msg *network.ControlMessage) error {
+	msgBytes, err := n.fork.EncodeNetworkMsg(&network.Message{
+		Version:        n.fork.MessageVersion(),
+		ControlMessage: msg,
+		Type:           network.NetworkMsg_ControlType,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+	topic, err := n.getMainTopic()
+	if err != nil {
+		return errors.Wrap(err, "failed to get main topic")
+	}
+	n.trace("broadcasting control message", zap.Uint64("nonce", msg.Nonce))
+	if err := topic.Publish(n.ctx, msgBytes); err != nil {
+		return errors.Wrap(err, "failed to publish on main topic")
+	}
+	return nil
+}
+
+// ReceivedControlMessageChan returns the channel for control messages
+func (n *p2pNetwork) ReceivedControlMessageChan() <-chan *network.ControlMessage {
+	ls := listener{
+		controlMsgCh: make(chan *network.ControlMessage, MsgChanSize),
+	}
+
+	n.listenersLock.Lock()
+	n.listeners = append(n.listeners, ls)
+	n.listenersLock.Unlock()
+
+	return ls.controlMsgCh
+}
+
+func propagateControlMessage(listeners []listener, msg *network.ControlMessage) {
+	for _, ls := range listeners {
+		if ls.controlMsgCh != nil {
+			ls.controlMsgCh <- msg
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 // BroadcastDecided broadcasts a decided instance with collected signatures
 func (n *p2pNetwork) BroadcastDecided(topicName []byte, msg *proto.SignedMessage) error {
 	msgBytes, err := n.fork.EncodeNetworkMsg(&network.Message{
+		Version:       n.fork.MessageVersion(),
 		SignedMessage: msg,
 		Type:          network.NetworkMsg_DecidedType,
 	})
@@ -17,23 +18,18 @@ func (n *p2pNetwork) BroadcastDecided(topicName []byte, msg *proto.SignedMessage
 		return errors.Wrap(err, "failed to marshal message")
 	}
 
-	topic, err := n.getTopic(topicName)
-	if err != nil {
-		return errors.Wrap(err, "failed to get topic")
-	}
-
-	n.logger.Debug("Broadcasting decided message", zap.String("lambda", string(msg.Message.Lambda)), zap.Any("topic", topic), zap.Any("peers", topic.ListPeers()))
+	n.logger.Debug("Broadcasting decided message", zap.String("lambda", string(msg.Message.Lambda)))
 
-	// publishing on main topic as well
+	// publishing on the decided topic of the validator as well
 	go func() {
-		if mainTopic, err := n.getMainTopic(); err != nil {
-			n.logger.Error("failed to get main topic")
-		} else if err := mainTopic.Publish(n.ctx, msgBytes[:]); err != nil {
-			n.logger.Error("failed to publish on main topic")
+		if decidedTopic, err := n.getSharedTopic(n.fork.DecidedTopicID(topicName)); err != nil {
+			n.logger.Error("failed to get decided topic", zap.Error(err))
+		} else if err := decidedTopic.Publish(n.ctx, msgBytes[:]); err != nil {
+			n.logger.Error("failed to publish on decided topic", zap.Error(err))
 		}
 	}()
 
-	return topic.Publish(n.ctx, msgBytes)
+	return n.publish(topicName, msgBytes, "decided")
 }
 
 // ReceivedDecidedChan returns the channel for decided messages
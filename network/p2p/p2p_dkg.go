@@ -0,0 +1,97 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/bloxapp/ssv/network"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const dkgTopicName = "dkg"
+
+// BroadcastDKG broadcasts the given msg on the dkg topic
+func (n *p2pNetwork) BroadcastDKG(msg *network.DKGMessage) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+	topic, err := n.getDKGTopic()
+	if err != nil {
+		return errors.Wrap(err, "failed to get dkg topic")
+	}
+	n.trace("broadcasting dkg msg", zap.String("ceremonyID", msg.CeremonyID),
+		zap.String("type", msg.Type.String()))
+	return topic.Publish(n.ctx, msgBytes)
+}
+
+// SubscribeToDKGTopic subscribes to the dkg topic
+func (n *p2pNetwork) SubscribeToDKGTopic() error {
+	topic, err := n.getDKGTopic()
+	if err != nil {
+		return err
+	}
+	sub, err := topic.Subscribe()
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe on dkg topic")
+	}
+	go n.listenDKG(n.ctx, sub)
+
+	return nil
+}
+
+// ReceivedDKGMsgChan returns the channel for dkg messages
+func (n *p2pNetwork) ReceivedDKGMsgChan() <-chan *network.DKGMessage {
+	ls := listener{
+		dkgCh: make(chan *network.DKGMessage, MsgChanSize),
+	}
+
+	n.listenersLock.Lock()
+	n.listeners = append(n.listeners, ls)
+	n.listenersLock.Unlock()
+
+	return ls.dkgCh
+}
+
+// getDKGTopic returns the dkg topic, joins the topic if needed
+func (n *p2pNetwork) getDKGTopic() (*pubsub.Topic, error) {
+	n.psTopicsLock.Lock()
+	defer n.psTopicsLock.Unlock()
+
+	if _, ok := n.cfg.Topics[dkgTopicName]; !ok {
+		topic, err := n.pubsub.Join(getTopicName(dkgTopicName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to join dkg topic")
+		}
+		n.cfg.Topics[dkgTopicName] = topic
+	}
+	return n.cfg.Topics[dkgTopicName], nil
+}
+
+// listenDKG listens on the given dkg subscription
+func (n *p2pNetwork) listenDKG(ctx context.Context, sub *pubsub.Subscription) {
+	defer sub.Cancel()
+	n.logger.Info("start listen to dkg topic")
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			n.logger.Error("failed to get message from dkg subscription", zap.Error(err))
+			return
+		}
+		dkgMsg := &network.DKGMessage{}
+		if err := json.Unmarshal(msg.Data, dkgMsg); err != nil {
+			n.logger.Error("failed to un-marshal dkg message", zap.Error(err))
+			continue
+		}
+		go propagateDKGMessage(n.listeners, dkgMsg)
+	}
+}
+
+func propagateDKGMessage(listeners []listener, msg *network.DKGMessage) {
+	for _, ls := range listeners {
+		if ls.dkgCh != nil {
+			ls.dkgCh <- msg
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/bloxapp/ssv/network"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const failoverTopicName = "failover"
+
+// BroadcastHeartbeat broadcasts the given heartbeat on the failover topic
+func (n *p2pNetwork) BroadcastHeartbeat(hb *network.FailoverHeartbeat) error {
+	msgBytes, err := json.Marshal(hb)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal heartbeat")
+	}
+	topic, err := n.getFailoverTopic()
+	if err != nil {
+		return errors.Wrap(err, "failed to get failover topic")
+	}
+	n.trace("broadcasting failover heartbeat", zap.String("instanceID", hb.InstanceID))
+	return topic.Publish(n.ctx, msgBytes)
+}
+
+// SubscribeToFailoverTopic subscribes to the failover topic
+func (n *p2pNetwork) SubscribeToFailoverTopic() error {
+	topic, err := n.getFailoverTopic()
+	if err != nil {
+		return err
+	}
+	sub, err := topic.Subscribe()
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe on failover topic")
+	}
+	go n.listenFailover(n.ctx, sub)
+
+	return nil
+}
+
+// ReceivedHeartbeatChan returns the channel for failover heartbeats
+func (n *p2pNetwork) ReceivedHeartbeatChan() <-chan *network.FailoverHeartbeat {
+	ls := listener{
+		heartbeatCh: make(chan *network.FailoverHeartbeat, MsgChanSize),
+	}
+
+	n.listenersLock.Lock()
+	n.listeners = append(n.listeners, ls)
+	n.listenersLock.Unlock()
+
+	return ls.heartbeatCh
+}
+
+// getFailoverTopic returns the failover topic, joins the topic if needed
+func (n *p2pNetwork) getFailoverTopic() (*pubsub.Topic, error) {
+	n.psTopicsLock.Lock()
+	defer n.psTopicsLock.Unlock()
+
+	if _, ok := n.cfg.Topics[failoverTopicName]; !ok {
+		topic, err := n.pubsub.Join(getTopicName(failoverTopicName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to join failover topic")
+		}
+		n.cfg.Topics[failoverTopicName] = topic
+	}
+	return n.cfg.Topics[failoverTopicName], nil
+}
+
+// listenFailover listens on the given failover subscription
+func (n *p2pNetwork) listenFailover(ctx context.Context, sub *pubsub.Subscription) {
+	defer sub.Cancel()
+	n.logger.Info("start listen to failover topic")
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			n.logger.Error("failed to get message from failover subscription", zap.Error(err))
+			return
+		}
+		hb := &network.FailoverHeartbeat{}
+		if err := json.Unmarshal(msg.Data, hb); err != nil {
+			n.logger.Error("failed to un-marshal failover heartbeat", zap.Error(err))
+			continue
+		}
+		go propagateHeartbeat(n.listeners, hb)
+	}
+}
+
+func propagateHeartbeat(listeners []listener, hb *network.FailoverHeartbeat) {
+	for _, ls := range listeners {
+		if ls.heartbeatCh != nil {
+			ls.heartbeatCh <- hb
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// BroadcastHighestDecided broadcasts the given highest decided message on the decided topic of its validator
+func (n *p2pNetwork) BroadcastHighestDecided(msg *proto.SignedMessage) error {
+	msgBytes, err := n.fork.EncodeNetworkMsg(&network.Message{
+		Version:       n.fork.MessageVersion(),
+		SignedMessage: msg,
+		Type:          network.NetworkMsg_HighestDecidedType,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+	topic, err := n.getDecidedTopic(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to get decided topic")
+	}
+	n.trace("broadcasting highest decided", zap.String("lambda", string(msg.Message.Lambda)),
+		zap.Uint64("seq", msg.Message.SeqNumber))
+	if err := topic.Publish(n.ctx, msgBytes); err != nil {
+		return errors.Wrap(err, "failed to publish on decided topic")
+	}
+	return nil
+}
+
+// ReceivedHighestDecidedChan returns the channel for highest decided announcements
+func (n *p2pNetwork) ReceivedHighestDecidedChan() <-chan *proto.SignedMessage {
+	ls := listener{
+		highestDecidedCh: make(chan *proto.SignedMessage, MsgChanSize),
+	}
+
+	n.listenersLock.Lock()
+	n.listeners = append(n.listeners, ls)
+	n.listenersLock.Unlock()
+
+	return ls.highestDecidedCh
+}
+
+func propagateHighestDecided(listeners []listener, msg *proto.SignedMessage) {
+	for _, ls := range listeners {
+		if ls.highestDecidedCh != nil {
+			ls.highestDecidedCh <- msg
+		}
+	}
+}
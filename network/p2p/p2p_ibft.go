@@ -5,11 +5,18 @@ import (
 	"github.com/bloxapp/ssv/network"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"time"
 )
 
 // Broadcast propagates a signed message to all peers
 func (n *p2pNetwork) Broadcast(topicName []byte, msg *proto.SignedMessage) error {
+	return n.BroadcastWithDeadline(topicName, msg, time.Time{})
+}
+
+// BroadcastWithDeadline implements network.DeadlineBroadcaster, retries are bounded by the given deadline (unless zero)
+func (n *p2pNetwork) BroadcastWithDeadline(topicName []byte, msg *proto.SignedMessage, deadline time.Time) error {
 	msgBytes, err := n.fork.EncodeNetworkMsg(&network.Message{
+		Version:       n.fork.MessageVersion(),
 		SignedMessage: msg,
 		Type:          network.NetworkMsg_IBFTType,
 	})
@@ -17,15 +24,9 @@ func (n *p2pNetwork) Broadcast(topicName []byte, msg *proto.SignedMessage) error
 		return errors.Wrap(err, "failed to marshal message")
 	}
 
-	topic, err := n.getTopic(topicName)
-	if err != nil {
-		return errors.Wrap(err, "failed to get topic")
-	}
-
-	n.logger.Debug("broadcasting ibft msg", zap.String("lambda", string(msg.Message.Lambda)),
-		zap.Any("topic", topic), zap.Any("peers", topic.ListPeers()))
+	n.logger.Debug("broadcasting ibft msg", zap.String("lambda", string(msg.Message.Lambda)))
 
-	return topic.Publish(n.ctx, msgBytes)
+	return n.publishWithDeadline(topicName, msgBytes, "ibft", deadline)
 }
 
 // ReceivedMsgChan return a channel with messages
@@ -0,0 +1,49 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// BroadcastOperatorHeartbeat broadcasts the given heartbeat on the main topic
+func (n *p2pNetwork) BroadcastOperatorHeartbeat(hb *network.OperatorHeartbeat) error {
+	msgBytes, err := n.fork.EncodeNetworkMsg(&network.Message{
+		Version:           n.fork.MessageVersion(),
+		OperatorHeartbeat: hb,
+		Type:              network.NetworkMsg_OperatorHeartbeatType,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+	topic, err := n.getMainTopic()
+	if err != nil {
+		return errors.Wrap(err, "failed to get main topic")
+	}
+	n.trace("broadcasting operator heartbeat", zap.Int64("timestamp", hb.Timestamp))
+	if err := topic.Publish(n.ctx, msgBytes); err != nil {
+		return errors.Wrap(err, "failed to publish on main topic")
+	}
+	return nil
+}
+
+// ReceivedOperatorHeartbeatChan returns the channel for operator heartbeats
+func (n *p2pNetwork) ReceivedOperatorHeartbeatChan() <-chan *network.OperatorHeartbeat {
+	ls := listener{
+		operatorHeartbeatCh: make(chan *network.OperatorHeartbeat, MsgChanSize),
+	}
+
+	n.listenersLock.Lock()
+	n.listeners = append(n.listeners, ls)
+	n.listenersLock.Unlock()
+
+	return ls.operatorHeartbeatCh
+}
+
+func propagateOperatorHeartbeat(listeners []listener, hb *network.OperatorHeartbeat) {
+	for _, ls := range listeners {
+		if ls.operatorHeartbeatCh != nil {
+			ls.operatorHeartbeatCh <- hb
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/pkg/errors"
@@ -28,8 +29,27 @@ func (n *p2pNetwork) UnSubscribeValidatorNetwork(validatorPk *bls.PublicKey) err
 	return nil
 }
 
-// SubscribeToValidatorNetwork  for new validator create new topic, subscribe and start listen
+// IsSubscribedToValidatorNetwork returns true if the topic of the given validator has a live subscription,
+// a subscription is removed once its listener stops (e.g. on error)
+func (n *p2pNetwork) IsSubscribedToValidatorNetwork(validatorPk *bls.PublicKey) bool {
+	n.psTopicsLock.RLock()
+	defer n.psTopicsLock.RUnlock()
+
+	_, ok := n.psSubs[validatorPk.SerializeToHexStr()]
+	return ok
+}
+
+// SubscribeToValidatorNetwork  for new validator create new topic, subscribe and start listen.
+// the decided topics of the validator are subscribed as well
 func (n *p2pNetwork) SubscribeToValidatorNetwork(validatorPk *bls.PublicKey) error {
+	if err := n.subscribeToValidatorTopic(validatorPk); err != nil {
+		return err
+	}
+	return n.subscribeValidatorDecidedTopics(validatorPk.Serialize())
+}
+
+// subscribeToValidatorTopic joins and subscribes the topic of the given validator
+func (n *p2pNetwork) subscribeToValidatorTopic(validatorPk *bls.PublicKey) error {
 	n.psTopicsLock.Lock()
 	defer n.psTopicsLock.Unlock()
 
@@ -60,7 +80,7 @@ func (n *p2pNetwork) SubscribeToValidatorNetwork(validatorPk *bls.PublicKey) err
 		n.psSubs[pubKey] = cacnel
 		go func() {
 			topicName := sub.Topic()
-			n.listen(ctx, sub)
+			n.listen(ctx, sub, true)
 			if err := n.closeTopic(topicName); err != nil {
 				n.logger.Error("failed to close topic", zap.String("topic", topicName), zap.Error(err))
 			}
@@ -87,10 +107,17 @@ func (n *p2pNetwork) AllPeers(validatorPk []byte) ([]string, error) {
 // joinTopic joins to the given topic and mark it in topics map
 // this method is not thread-safe - should be called after psTopicsLock was acquired
 func (n *p2pNetwork) joinTopic(pubKey string) error {
-	topic, err := n.pubsub.Join(getTopicName(pubKey))
+	topicName := getTopicName(pubKey)
+	topic, err := n.pubsub.Join(topicName)
 	if err != nil {
 		return errors.Wrap(err, "failed to join to topic")
 	}
+	n.registerMsgSizeValidator(topicName)
+	if n.gossipInspector != nil {
+		if err := topic.SetScoreParams(inspectionTopicScoreParams()); err != nil {
+			n.logger.Warn("could not set topic score params", zap.Error(err))
+		}
+	}
 	n.cfg.Topics[pubKey] = topic
 	return nil
 }
@@ -103,6 +130,7 @@ func (n *p2pNetwork) closeTopic(topicName string) error {
 	pk := unwrapTopicName(topicName)
 	if t, ok := n.cfg.Topics[pk]; ok {
 		delete(n.cfg.Topics, pk)
+		n.unregisterMsgSizeValidator(topicName)
 		return t.Close()
 	}
 	return nil
@@ -140,8 +168,8 @@ func (n *p2pNetwork) allPeersOfTopic(topic *pubsub.Topic) []string {
 	return ret
 }
 
-// listen listens on the given subscription
-func (n *p2pNetwork) listen(ctx context.Context, sub *pubsub.Subscription) {
+// listen listens on the given subscription, validatorTopic is false for shared topics (main and decided topics)
+func (n *p2pNetwork) listen(ctx context.Context, sub *pubsub.Subscription, validatorTopic bool) {
 	t := sub.Topic()
 	defer sub.Cancel()
 	n.logger.Info("start listen to topic", zap.String("topic", t))
@@ -156,16 +184,48 @@ func (n *p2pNetwork) listen(ctx context.Context, sub *pubsub.Subscription) {
 				n.logger.Error("failed to get message from subscription Topics", zap.Error(err))
 				return
 			}
+			// rates are tracked by the origin of the message, as honest peers relay messages of others
+			if origin := msg.GetFrom(); origin != n.host.ID() && !n.msgRates.onMessage(t, origin, validatorTopic) {
+				n.trace("dropping message of grey-listed peer", zap.String("topic", t),
+					zap.String("peer", origin.String()), zap.String("receivedFrom", msg.ReceivedFrom.String()))
+				continue
+			}
+			if n.msgAuditor != nil {
+				n.msgAuditor.capture(msg.ReceivedFrom, t, msg.Data)
+			}
 			n.trace("received raw network msg", zap.ByteString("network.Message bytes", msg.Data))
-			cm, err := n.fork.DecodeNetworkMsg(msg.Data)
-			if err != nil {
+			// the network message is only a wrapper, its content is propagated so it can be reused
+			cm := network.AcquireMessage()
+			if err := n.fork.DecodeNetworkMsgInto(msg.Data, cm); err != nil {
+				network.ReleaseMessage(cm)
+				network.ReportRejectedMessage(errs.Mark(err, errs.ErrDecode))
 				n.logger.Error("failed to un-marshal message", zap.Error(err))
 				continue
 			}
+			if err := n.fork.ValidateNetworkMsg(cm); err != nil {
+				network.ReleaseMessage(cm)
+				network.ReportRejectedMessage(err)
+				n.logger.Debug("dropping invalid message", zap.String("topic", t),
+					zap.String("peer", msg.ReceivedFrom.String()), zap.Error(err))
+				continue
+			}
+			if validatorTopic && n.seenMsgs != nil && !n.seenMsgs.markSeen(msg.Data) {
+				// the message was received directly already
+				network.ReleaseMessage(cm)
+				continue
+			}
 			if n.reportLastMsg && len(msg.ReceivedFrom) > 0 {
 				reportLastMsg(msg.ReceivedFrom.String())
 			}
-			n.propagateSignedMsg(cm)
+			switch cm.Type {
+			case network.NetworkMsg_OperatorHeartbeatType:
+				go propagateOperatorHeartbeat(n.listeners, cm.OperatorHeartbeat)
+			case network.NetworkMsg_ControlType:
+				go propagateControlMessage(n.listeners, cm.ControlMessage)
+			default:
+				n.propagateSignedMsg(cm)
+			}
+			network.ReleaseMessage(cm)
 		}
 	}
 }
@@ -187,6 +247,8 @@ func (n *p2pNetwork) propagateSignedMsg(cm *network.Message) {
 		go propagateSigMessage(n.listeners, cm.SignedMessage)
 	case network.NetworkMsg_DecidedType:
 		go propagateDecidedMessage(n.listeners, cm.SignedMessage)
+	case network.NetworkMsg_HighestDecidedType:
+		go propagateHighestDecided(n.listeners, cm.SignedMessage)
 	default:
 		n.logger.Error("received unsupported message", zap.Int32("msg type", int32(cm.Type)))
 	}
@@ -10,19 +10,15 @@ import (
 // BroadcastSignature broadcasts the given signature for the given lambda
 func (n *p2pNetwork) BroadcastSignature(topicName []byte, msg *proto.SignedMessage) error {
 	msgBytes, err := n.fork.EncodeNetworkMsg(&network.Message{
+		Version:       n.fork.MessageVersion(),
 		SignedMessage: msg,
 		Type:          network.NetworkMsg_SignatureType,
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal message")
 	}
-	topic, err := n.getTopic(topicName)
-	if err != nil {
-		return errors.Wrap(err, "failed to get topic")
-	}
-
-	n.logger.Debug("Broadcasting signature message", zap.String("lambda", string(msg.Message.Lambda)), zap.Any("topic", topic), zap.Any("peers", topic.ListPeers()))
-	return topic.Publish(n.ctx, msgBytes)
+	n.logger.Debug("Broadcasting signature message", zap.String("lambda", string(msg.Message.Lambda)))
+	return n.publish(topicName, msgBytes, "signature")
 }
 
 // ReceivedSignatureChan returns the channel with signatures
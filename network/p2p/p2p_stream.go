@@ -2,18 +2,21 @@ package p2p
 
 import (
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/protocol"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
 func (n *p2pNetwork) preStreamHandler(stream core.Stream) (*network.Message, network.SyncStream, error) {
 	n.logger.Debug("syncStreamHandler start")
-	netSyncStream := NewSyncStream(stream)
+	netSyncStream := newLimitedSyncStream(stream, n.cfg.MaxSyncMsgSize)
 
 	// read msg
 	buf, err := netSyncStream.ReadWithTimeout(n.cfg.RequestTimeout)
 	if err != nil {
+		n.reportOversizedMsg(netSyncStream.RemotePeer(), err)
 		return nil, nil, errors.Wrap(err, "could not read incoming sync stream")
 	}
 
@@ -21,14 +24,22 @@ func (n *p2pNetwork) preStreamHandler(stream core.Stream) (*network.Message, net
 
 	cm, err := n.fork.DecodeNetworkMsg(buf)
 	if err != nil {
+		network.ReportRejectedMessage(errs.Mark(err, errs.ErrDecode))
 		return nil, nil, errors.Wrap(err, "could not parse stream")
 	}
+	if cm.Type != network.NetworkMsg_SyncType {
+		return nil, nil, errors.Errorf("unexpected message type on stream: %s", cm.Type.String())
+	}
+	if err := n.fork.ValidateNetworkMsg(cm); err != nil {
+		network.ReportRejectedMessage(err)
+		return nil, nil, errors.Wrap(err, "invalid stream message")
+	}
 	n.logger.Debug("syncStreamHandler decoded", zap.Any("cm", cm))
 	return cm, netSyncStream, nil
 }
 
 func (n *p2pNetwork) setHighestDecidedStreamHandler() {
-	n.host.SetStreamHandler(highestDecidedStream, func(stream core.Stream) {
+	n.setSyncStreamHandler(highestDecidedStream, func(stream core.Stream) {
 		cm, s, err := n.preStreamHandler(stream)
 		if err != nil {
 			n.logger.Error(" highest decided preStreamHandler failed", zap.Error(err))
@@ -39,7 +50,7 @@ func (n *p2pNetwork) setHighestDecidedStreamHandler() {
 }
 
 func (n *p2pNetwork) setDecidedByRangeStreamHandler() {
-	n.host.SetStreamHandler(decidedByRangeStream, func(stream core.Stream) {
+	n.setSyncStreamHandler(decidedByRangeStream, func(stream core.Stream) {
 		cm, s, err := n.preStreamHandler(stream)
 		if err != nil {
 			n.logger.Error("decided by range preStreamHandler failed", zap.Error(err))
@@ -50,7 +61,7 @@ func (n *p2pNetwork) setDecidedByRangeStreamHandler() {
 }
 
 func (n *p2pNetwork) setLastChangeRoundStreamHandler() {
-	n.host.SetStreamHandler(lastChangeRoundMsgStream, func(stream core.Stream) {
+	n.setSyncStreamHandler(lastChangeRoundMsgStream, func(stream core.Stream) {
 		cm, s, err := n.preStreamHandler(stream)
 		if err != nil {
 			n.logger.Error("last change round preStreamHandler failed", zap.Error(err))
@@ -60,15 +71,30 @@ func (n *p2pNetwork) setLastChangeRoundStreamHandler() {
 	})
 }
 
+func (n *p2pNetwork) setDecidedHistoryRootStreamHandler() {
+	n.setSyncStreamHandler(decidedHistoryRootStream, func(stream core.Stream) {
+		cm, s, err := n.preStreamHandler(stream)
+		if err != nil {
+			n.logger.Error("decided history root preStreamHandler failed", zap.Error(err))
+			return
+		}
+		n.propagateSyncMsg(cm, s)
+	})
+}
+
 // propagateSyncMsg takes an incoming sync message and propagates it on the internal sync channel
 func (n *p2pNetwork) propagateSyncMsg(cm *network.Message, netSyncStream network.SyncStream) {
 	logger := n.logger.With(zap.String("func", "propagateSyncMsg"))
 	// TODO: find a better way to deal with nil message
 	// 	i.e. avoid sending nil messages in the network
-	if netSyncStream == nil || cm == nil {
+	if netSyncStream == nil || cm == nil || cm.SyncMessage == nil {
 		logger.Debug("could not propagate nil message")
 		return
 	}
+	if !n.syncPolicy.authorize(netSyncStream.RemotePeer(), cm.SyncMessage) {
+		n.rejectSyncRequest(netSyncStream, cm.SyncMessage)
+		return
+	}
 	cm.SyncMessage.FromPeerID = netSyncStream.RemotePeer()
 	for _, ls := range n.listeners {
 		go func(ls listener, nm network.Message) {
@@ -76,11 +102,34 @@ func (n *p2pNetwork) propagateSyncMsg(cm *network.Message, netSyncStream network
 			case network.NetworkMsg_SyncType:
 				if ls.syncCh != nil {
 					ls.syncCh <- &network.SyncChanObj{
-						Msg:    nm.SyncMessage,
-						Stream: netSyncStream,
+						Msg:     nm.SyncMessage,
+						Stream:  netSyncStream,
+						Version: nm.Version,
 					}
 				}
 			}
 		}(ls, *cm)
 	}
 }
+
+// multiForkSyncProtocols is implemented by forks that are aware of the protocol ids of other forks
+type multiForkSyncProtocols interface {
+	SyncProtocolIDs(stream string) []string
+}
+
+// syncProtocol returns the protocol id of the given sync stream according to the current fork
+func (n *p2pNetwork) syncProtocol(stream string) protocol.ID {
+	return protocol.ID(n.fork.SyncProtocolID(stream))
+}
+
+// setSyncStreamHandler sets the handler for the protocol ids of the given sync stream,
+// if the fork is aware of other forks, the protocol ids of all the forks are handled
+func (n *p2pNetwork) setSyncStreamHandler(stream string, handler func(stream core.Stream)) {
+	ids := []string{n.fork.SyncProtocolID(stream)}
+	if mf, ok := n.fork.(multiForkSyncProtocols); ok {
+		ids = mf.SyncProtocolIDs(stream)
+	}
+	for _, id := range ids {
+		n.host.SetStreamHandler(protocol.ID(id), handler)
+	}
+}
@@ -1,11 +1,14 @@
 package p2p
 
 import (
+	"fmt"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"time"
 )
 
 func peerToString(peerID peer.ID) string {
@@ -29,31 +32,63 @@ func (n *p2pNetwork) sendSyncMessage(stream network.SyncStream, peer peer.ID, pr
 		if err != nil {
 			return nil, err
 		}
-		stream = NewSyncStream(s)
+		stream = newLimitedSyncStream(s, n.cfg.MaxSyncMsgSize)
 	}
 
-	// message to bytes
+	msgBytes, err := n.encodeSyncMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.writeSyncMessage(stream, msgBytes); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// encodeSyncMessage encodes the given sync message into bytes
+func (n *p2pNetwork) encodeSyncMessage(msg *network.SyncMessage) ([]byte, error) {
 	msgBytes, err := n.fork.EncodeNetworkMsg(&network.Message{
+		Version:     n.fork.MessageVersion(),
 		SyncMessage: msg,
 		Type:        network.NetworkMsg_SyncType,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal message")
 	}
+	return msgBytes, nil
+}
 
+// writeSyncMessage writes the given encoded sync message and closes the stream for writing
+func (n *p2pNetwork) writeSyncMessage(stream network.SyncStream, msgBytes []byte) error {
 	if err := stream.WriteWithTimeout(msgBytes, n.cfg.RequestTimeout); err != nil {
-		return nil, errors.Wrap(err, "could not write to stream")
+		return errors.Wrap(err, "could not write to stream")
 	}
 	if err := stream.CloseWrite(); err != nil {
-		return nil, errors.Wrap(err, "could not close write stream")
+		return errors.Wrap(err, "could not close write stream")
 	}
-	return stream, nil
+	return nil
 }
 
 // sendAndReadResponse sends a reques sync msg, waits to a response and parses it. Includes timeout as well
-func (n *p2pNetwork) sendAndReadSyncResponse(peer peer.ID, protocol protocol.ID, msg *network.SyncMessage) (*network.Message, error) {
-	var err error
-	stream, err := n.sendSyncMessage(nil, peer, protocol, msg)
+func (n *p2pNetwork) sendAndReadSyncResponse(peer peer.ID, protocol protocol.ID, msg *network.SyncMessage) (res *network.Message, err error) {
+	if n.syncLimiter != nil {
+		release, err := n.syncLimiter.acquire(n.ctx, peer)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	start := time.Now()
+	defer func() {
+		n.reportSyncLatency(peer, time.Since(start), err)
+	}()
+
+	req, err := n.signSyncRequest(msg)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := n.sendSyncMessage(nil, peer, protocol, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not send sync msg")
 	}
@@ -67,10 +102,12 @@ func (n *p2pNetwork) sendAndReadSyncResponse(peer peer.ID, protocol protocol.ID,
 
 	resByts, err := stream.ReadWithTimeout(n.cfg.RequestTimeout)
 	if err != nil {
+		n.reportOversizedMsg(peerToString(peer), err)
 		return nil, errors.Wrap(err, "could not read sync msg")
 	}
 	resMsg, err := n.fork.DecodeNetworkMsg(resByts)
 	if err != nil {
+		network.ReportRejectedMessage(errs.Mark(err, errs.ErrDecode))
 		return nil, errors.Wrap(err, "could not decode stream sync msg")
 	}
 
@@ -78,12 +115,46 @@ func (n *p2pNetwork) sendAndReadSyncResponse(peer peer.ID, protocol protocol.ID,
 	if resMsg.SyncMessage == nil {
 		return nil, errors.New("no response for sync request")
 	}
+	if err := n.fork.ValidateNetworkMsg(resMsg); err != nil {
+		network.ReportRejectedMessage(err)
+		return nil, errors.Wrap(err, "invalid sync response")
+	}
+	if err := verifySyncResponse(peer, resMsg.SyncMessage, n.cfg.RequireSignedSyncResponses); err != nil {
+		network.ReportRejectedMessage(err)
+		// unsigned responses are served by older versions, the peer is not penalized for them
+		if err != errUnsignedSyncResponse {
+			n.ReportBadPeer(peerToString(peer), "invalid_signature")
+		}
+		return nil, errors.Wrap(err, "could not verify sync response")
+	}
 	n.logger.Debug("got sync response",
 		zap.String("FromPeerID", resMsg.SyncMessage.GetFromPeerID()))
 
 	return resMsg, nil
 }
 
+// reportSyncLatency reports the round-trip time of a sync request, failed requests are reported with the request timeout
+func (n *p2pNetwork) reportSyncLatency(peer peer.ID, rtt time.Duration, err error) {
+	if err != nil {
+		metricsSyncRequestErrors.WithLabelValues(errs.Label(err)).Inc()
+	}
+	if n.peersIndex == nil {
+		return
+	}
+	if err != nil && rtt < n.cfg.RequestTimeout {
+		rtt = n.cfg.RequestTimeout
+	}
+	n.peersIndex.ReportLatency(peerToString(peer), rtt)
+}
+
+// PeerLatency returns the average round-trip time of sync requests to the given peer, false if unknown
+func (n *p2pNetwork) PeerLatency(peerStr string) (time.Duration, bool) {
+	if n.peersIndex == nil {
+		return 0, false
+	}
+	return n.peersIndex.GetPeerLatency(peerStr)
+}
+
 // GetHighestDecidedInstance asks peers for SyncMessage
 func (n *p2pNetwork) GetHighestDecidedInstance(peerStr string, msg *network.SyncMessage) (*network.SyncMessage, error) {
 	peerID, err := peerFromString(peerStr)
@@ -91,7 +162,7 @@ func (n *p2pNetwork) GetHighestDecidedInstance(peerStr string, msg *network.Sync
 		return nil, err
 	}
 
-	res, err := n.sendAndReadSyncResponse(peerID, highestDecidedStream, msg)
+	res, err := n.sendAndReadSyncResponse(peerID, n.syncProtocol(highestDecidedStream), msg)
 	if err != nil || res == nil {
 		return nil, err
 	}
@@ -101,7 +172,10 @@ func (n *p2pNetwork) GetHighestDecidedInstance(peerStr string, msg *network.Sync
 // RespondToHighestDecidedInstance responds to a GetHighestDecidedInstance
 func (n *p2pNetwork) RespondToHighestDecidedInstance(stream network.SyncStream, msg *network.SyncMessage) error {
 	msg.FromPeerID = n.host.ID().Pretty() // critical
-	_, err := n.sendSyncMessage(stream, "", highestDecidedStream, msg)
+	if err := n.signSyncResponse(msg); err != nil {
+		return err
+	}
+	_, err := n.sendSyncMessage(stream, "", n.syncProtocol(highestDecidedStream), msg)
 	return err
 }
 
@@ -112,17 +186,33 @@ func (n *p2pNetwork) GetDecidedByRange(peerStr string, msg *network.SyncMessage)
 		return nil, err
 	}
 
-	res, err := n.sendAndReadSyncResponse(peerID, decidedByRangeStream, msg)
+	res, err := n.sendAndReadSyncResponse(peerID, n.syncProtocol(decidedByRangeStream), msg)
 	if err != nil {
 		return nil, err
 	}
 	return res.SyncMessage, nil
 }
 
+// RespondToGetDecidedByRange responds to a GetDecidedByRange, encoded responses of complete ranges are cached
 func (n *p2pNetwork) RespondToGetDecidedByRange(stream network.SyncStream, msg *network.SyncMessage) error {
 	msg.FromPeerID = n.host.ID().Pretty() // critical
-	_, err := n.sendSyncMessage(stream, "", decidedByRangeStream, msg)
-	return err
+	key, cacheable := decidedRangeResponseKey(msg)
+	if cacheable {
+		if msgBytes, ok := n.syncResponses.Get(key); ok {
+			return n.writeSyncMessage(stream, msgBytes.([]byte))
+		}
+	}
+	if err := n.signSyncResponse(msg); err != nil {
+		return err
+	}
+	msgBytes, err := n.encodeSyncMessage(msg)
+	if err != nil {
+		return err
+	}
+	if cacheable {
+		n.syncResponses.Add(key, msgBytes)
+	}
+	return n.writeSyncMessage(stream, msgBytes)
 }
 
 // GetLastChangeRoundMsg returns the latest change round msg for a running instance, could return nil
@@ -132,7 +222,7 @@ func (n *p2pNetwork) GetLastChangeRoundMsg(peerStr string, msg *network.SyncMess
 		return nil, err
 	}
 
-	res, err := n.sendAndReadSyncResponse(peerID, lastChangeRoundMsgStream, msg)
+	res, err := n.sendAndReadSyncResponse(peerID, n.syncProtocol(lastChangeRoundMsgStream), msg)
 	if err != nil || res == nil {
 		return nil, err
 	}
@@ -142,7 +232,34 @@ func (n *p2pNetwork) GetLastChangeRoundMsg(peerStr string, msg *network.SyncMess
 // RespondToLastChangeRoundMsg responds to a GetLastChangeRoundMsg
 func (n *p2pNetwork) RespondToLastChangeRoundMsg(stream network.SyncStream, msg *network.SyncMessage) error {
 	msg.FromPeerID = n.host.ID().Pretty() // critical
-	_, err := n.sendSyncMessage(stream, "", lastChangeRoundMsgStream, msg)
+	if err := n.signSyncResponse(msg); err != nil {
+		return err
+	}
+	_, err := n.sendSyncMessage(stream, "", n.syncProtocol(lastChangeRoundMsgStream), msg)
+	return err
+}
+
+// GetDecidedHistoryRoot asks the given peer for the merkle root over its decided history
+func (n *p2pNetwork) GetDecidedHistoryRoot(peerStr string, msg *network.SyncMessage) (*network.SyncMessage, error) {
+	peerID, err := peerFromString(peerStr)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := n.sendAndReadSyncResponse(peerID, n.syncProtocol(decidedHistoryRootStream), msg)
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return res.SyncMessage, nil
+}
+
+// RespondToDecidedHistoryRoot responds to a GetDecidedHistoryRoot
+func (n *p2pNetwork) RespondToDecidedHistoryRoot(stream network.SyncStream, msg *network.SyncMessage) error {
+	msg.FromPeerID = n.host.ID().Pretty() // critical
+	if err := n.signSyncResponse(msg); err != nil {
+		return err
+	}
+	_, err := n.sendSyncMessage(stream, "", n.syncProtocol(decidedHistoryRootStream), msg)
 	return err
 }
 
@@ -158,3 +275,21 @@ func (n *p2pNetwork) ReceivedSyncMsgChan() <-chan *network.SyncChanObj {
 
 	return ls.syncCh
 }
+
+// decidedRangeResponseKey returns the cache key of the given decided range response,
+// false is returned if the response can't be cached (an error or a range that is not fully decided)
+func decidedRangeResponseKey(msg *network.SyncMessage) (string, bool) {
+	count := len(msg.SignedMessages)
+	if len(msg.Error) > 0 || count == 0 {
+		return "", false
+	}
+	first, last := msg.SignedMessages[0], msg.SignedMessages[count-1]
+	if first == nil || first.Message == nil || last == nil || last.Message == nil {
+		return "", false
+	}
+	from, to := first.Message.SeqNumber, last.Message.SeqNumber
+	if to < from || to-from+1 != uint64(count) {
+		return "", false
+	}
+	return fmt.Sprintf("%x/%d/%d/%d/%x", msg.Lambda, from, to, msg.MaxBatch, msg.PageToken), true
+}
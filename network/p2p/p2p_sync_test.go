@@ -3,9 +3,12 @@ package p2p
 import (
 	"encoding/hex"
 	"encoding/json"
+	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/bloxapp/ssv/utils/logex"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -25,6 +28,18 @@ func (v0 *testingFork) ValidatorTopicID(pkByts []byte) string {
 	return hex.EncodeToString(pkByts)
 }
 
+func (v0 *testingFork) DecidedTopicID(pkByts []byte) string {
+	return "main"
+}
+
+func (v0 *testingFork) MessageVersion() network.MessageVersion {
+	return network.MessageVersionV0
+}
+
+func (v0 *testingFork) DecidedTopicIDs() []string {
+	return []string{"main"}
+}
+
 func (v0 *testingFork) EncodeNetworkMsg(msg *network.Message) ([]byte, error) {
 	return json.Marshal(msg)
 }
@@ -35,6 +50,22 @@ func (v0 *testingFork) DecodeNetworkMsg(data []byte) (*network.Message, error) {
 	return ret, err
 }
 
+func (v0 *testingFork) DecodeNetworkMsgInto(data []byte, msg *network.Message) error {
+	return json.Unmarshal(data, msg)
+}
+
+func (v0 *testingFork) ValidateNetworkMsg(msg *network.Message) error {
+	return network.ValidateMessage(msg)
+}
+
+func (v0 *testingFork) ID() string {
+	return "testing"
+}
+
+func (v0 *testingFork) SyncProtocolID(stream string) string {
+	return "/sync/" + stream
+}
+
 func TestSyncMessageBroadcastingTimeout(t *testing.T) {
 	logger := logex.Build("test", zap.DebugLevel, nil)
 
@@ -49,6 +80,7 @@ func TestSyncMessageBroadcastingTimeout(t *testing.T) {
 	peerID := peer.Encode(peer2.(*p2pNetwork).host.ID())
 	res, err := peer1.GetHighestDecidedInstance(peerID, messageToBroadcast)
 	require.EqualError(t, err, "could not read sync msg: i/o deadline reached")
+	require.True(t, errors.Is(err, errs.ErrSyncTimeout))
 	time.Sleep(time.Millisecond * 100)
 	require.Nil(t, res)
 }
@@ -93,9 +125,78 @@ func TestSyncMessageBroadcasting(t *testing.T) {
 	require.NotNil(t, res)
 	require.EqualValues(t, peer2.(*p2pNetwork).host.ID().String(), res.FromPeerID)
 	require.EqualValues(t, network.Sync_GetHighestType, res.Type)
+	require.NotEmpty(t, res.Signature)
 
 	// verify stream closed
 	require.NotNil(t, receivedStream)
 	err = receivedStream.WriteWithTimeout([]byte{1}, time.Second*10)
 	require.EqualError(t, err, "writen bytes to sync stream doesnt match input data")
 }
+
+func TestSignSyncResponse(t *testing.T) {
+	logger := logex.Build("test", zapcore.InfoLevel, nil)
+	peer1, peer2 := testPeers(t, logger)
+	n := peer1.(*p2pNetwork)
+
+	msg := &network.SyncMessage{
+		FromPeerID: n.host.ID().Pretty(),
+		Lambda:     []byte{1, 2},
+		Params:     []uint64{1, 2},
+		Type:       network.Sync_GetInstanceRange,
+	}
+	// unsigned responses are accepted unless signed responses are required
+	require.NoError(t, verifySyncResponse(n.host.ID(), msg, false))
+	require.EqualError(t, verifySyncResponse(n.host.ID(), msg, true), "sync response is not signed")
+
+	require.NoError(t, n.signSyncResponse(msg))
+	require.NotEmpty(t, msg.Signature)
+	require.NoError(t, verifySyncResponse(n.host.ID(), msg, false))
+	require.NoError(t, verifySyncResponse(n.host.ID(), msg, true))
+	// signed by another peer
+	require.EqualError(t, verifySyncResponse(peer2.(*p2pNetwork).host.ID(), msg, false), "invalid signature")
+	// tampered response
+	msg.Params = []uint64{1, 3}
+	require.EqualError(t, verifySyncResponse(n.host.ID(), msg, false), "invalid signature")
+}
+
+func TestDecidedRangeResponseKey(t *testing.T) {
+	decided := func(seq uint64) *proto.SignedMessage {
+		return &proto.SignedMessage{Message: &proto.Message{SeqNumber: seq}}
+	}
+	tests := []struct {
+		name        string
+		msg         *network.SyncMessage
+		expectedKey string
+	}{
+		{"complete range", &network.SyncMessage{
+			SignedMessages: []*proto.SignedMessage{decided(3), decided(4), decided(5)},
+			Lambda:         []byte{1, 2},
+		}, "0102/3/5/0/"},
+		{"page of a range", &network.SyncMessage{
+			SignedMessages: []*proto.SignedMessage{decided(3), decided(4), decided(5)},
+			Lambda:         []byte{1, 2},
+			MaxBatch:       2,
+			PageToken:      network.EncodePageToken(6),
+		}, "0102/3/5/2/0000000000000006"},
+		{"single decided", &network.SyncMessage{
+			SignedMessages: []*proto.SignedMessage{decided(7)},
+			Lambda:         []byte{1, 2},
+		}, "0102/7/7/0/"},
+		{"missing sequence", &network.SyncMessage{
+			SignedMessages: []*proto.SignedMessage{decided(3), decided(5)},
+		}, ""},
+		{"empty response", &network.SyncMessage{}, ""},
+		{"error response", &network.SyncMessage{
+			SignedMessages: []*proto.SignedMessage{decided(3)},
+			Error:          "error",
+		}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, ok := decidedRangeResponseKey(test.msg)
+			require.Equal(t, len(test.expectedKey) > 0, ok)
+			require.Equal(t, test.expectedKey, key)
+		})
+	}
+}
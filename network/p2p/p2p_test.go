@@ -85,4 +85,11 @@ func TestP2pNetwork_GetUserAgent(t *testing.T) {
 		n := p2pNetwork{}
 		require.Equal(t, "ssvtest:v0.x.x", n.getUserAgent())
 	})
+
+	t.Run("with commit and fork", func(t *testing.T) {
+		commons.SetCommit("abc1234")
+		defer commons.SetCommit("")
+		n := p2pNetwork{fork: testFork()}
+		require.Equal(t, "ssvtest:v0.x.x::abc1234:testing", n.getUserAgent())
+	})
 }
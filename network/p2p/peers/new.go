@@ -0,0 +1,11 @@
+//go:build !prysm_peers
+// +build !prysm_peers
+
+package peers
+
+import "context"
+
+// New creates a new peers status, a prysm based implementation is used when building with prysm_peers tag
+func New(ctx context.Context, cfg Config) Status {
+	return newStatus(ctx, cfg)
+}
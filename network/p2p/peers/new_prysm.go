@@ -0,0 +1,57 @@
+//go:build prysm_peers
+// +build prysm_peers
+
+package peers
+
+import (
+	"context"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers/scorers"
+)
+
+// New creates a new peers status that is backed by prysm
+func New(ctx context.Context, cfg Config) Status {
+	cfg.defaults()
+	return &prysmStatus{
+		status: peers.NewStatus(ctx, &peers.StatusConfig{
+			PeerLimit: cfg.PeerLimit,
+			ScorerParams: &scorers.Config{
+				BadResponsesScorerConfig: &scorers.BadResponsesScorerConfig{
+					Threshold:     cfg.BadResponsesThreshold,
+					DecayInterval: cfg.DecayInterval,
+				},
+			},
+		}),
+	}
+}
+
+// prysmStatus adapts prysm's peers status to Status
+type prysmStatus struct {
+	status *peers.Status
+}
+
+// IncrementBadResponses implements Scorer
+func (ps *prysmStatus) IncrementBadResponses(pid peer.ID) {
+	ps.status.Scorers().BadResponsesScorer().Increment(pid)
+}
+
+// IsBad implements Scorer
+func (ps *prysmStatus) IsBad(pid peer.ID) bool {
+	return ps.status.IsBad(pid)
+}
+
+// SetConnected implements Status
+func (ps *prysmStatus) SetConnected(pid peer.ID) {
+	ps.status.SetConnectionState(pid, peers.PeerConnected)
+}
+
+// SetDisconnected implements Status
+func (ps *prysmStatus) SetDisconnected(pid peer.ID) {
+	ps.status.SetConnectionState(pid, peers.PeerDisconnected)
+}
+
+// Active implements Status
+func (ps *prysmStatus) Active() []peer.ID {
+	return ps.status.Active()
+}
@@ -0,0 +1,51 @@
+package peers
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+	"time"
+)
+
+const (
+	// DefaultBadResponsesThreshold is the number of bad responses after which a peer is considered bad
+	DefaultBadResponsesThreshold = 5
+	// DefaultDecayInterval is the interval of decaying bad responses
+	DefaultDecayInterval = time.Hour
+)
+
+// Scorer scores peers according to their behavior
+type Scorer interface {
+	// IncrementBadResponses increments the number of bad responses of the given peer
+	IncrementBadResponses(pid peer.ID)
+	// IsBad returns true if the given peer is considered bad
+	IsBad(pid peer.ID) bool
+}
+
+// Status tracks the connection state and score of peers
+type Status interface {
+	Scorer
+	// SetConnected marks the given peer as connected
+	SetConnected(pid peer.ID)
+	// SetDisconnected marks the given peer as disconnected
+	SetDisconnected(pid peer.ID)
+	// Active returns the connected peers
+	Active() []peer.ID
+}
+
+// Config holds the configuration of peers status
+type Config struct {
+	// PeerLimit is the max number of peers
+	PeerLimit int
+	// BadResponsesThreshold is the number of bad responses after which a peer is considered bad
+	BadResponsesThreshold int
+	// DecayInterval is the interval of decaying bad responses
+	DecayInterval time.Duration
+}
+
+func (cfg *Config) defaults() {
+	if cfg.BadResponsesThreshold <= 0 {
+		cfg.BadResponsesThreshold = DefaultBadResponsesThreshold
+	}
+	if cfg.DecayInterval <= 0 {
+		cfg.DecayInterval = DefaultDecayInterval
+	}
+}
@@ -0,0 +1,126 @@
+package peers
+
+import (
+	"context"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"sync"
+	"time"
+)
+
+type peerData struct {
+	connected    bool
+	badResponses int
+}
+
+// status is a lightweight implementation of Status
+type status struct {
+	cfg Config
+
+	lock  sync.RWMutex
+	peers map[peer.ID]*peerData
+}
+
+// newStatus creates a new status, bad responses are decayed until the context is done
+func newStatus(ctx context.Context, cfg Config) *status {
+	cfg.defaults()
+	s := &status{
+		cfg:   cfg,
+		peers: make(map[peer.ID]*peerData),
+	}
+	go s.decayLoop(ctx)
+	return s
+}
+
+// IncrementBadResponses increments the number of bad responses of the given peer
+func (s *status) IncrementBadResponses(pid peer.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.peer(pid).badResponses++
+}
+
+// IsBad returns true if the bad responses of the given peer reached the threshold
+func (s *status) IsBad(pid peer.ID) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if pd, ok := s.peers[pid]; ok {
+		return pd.badResponses >= s.cfg.BadResponsesThreshold
+	}
+	return false
+}
+
+// SetConnected marks the given peer as connected
+func (s *status) SetConnected(pid peer.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.peer(pid).connected = true
+}
+
+// SetDisconnected marks the given peer as disconnected, peers w/o bad responses are removed
+func (s *status) SetDisconnected(pid peer.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	pd, ok := s.peers[pid]
+	if !ok {
+		return
+	}
+	pd.connected = false
+	if pd.badResponses == 0 {
+		delete(s.peers, pid)
+	}
+}
+
+// Active returns the connected peers
+func (s *status) Active() []peer.ID {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var res []peer.ID
+	for pid, pd := range s.peers {
+		if pd.connected {
+			res = append(res, pid)
+		}
+	}
+	return res
+}
+
+// peer returns the data of the given peer, a new entry is created if not exist. the lock must be held by the caller
+func (s *status) peer(pid peer.ID) *peerData {
+	pd, ok := s.peers[pid]
+	if !ok {
+		pd = &peerData{}
+		s.peers[pid] = pd
+	}
+	return pd
+}
+
+// decay decrements the bad responses of all peers, disconnected peers w/o bad responses are removed
+func (s *status) decay() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for pid, pd := range s.peers {
+		if pd.badResponses > 0 {
+			pd.badResponses--
+		}
+		if pd.badResponses == 0 && !pd.connected {
+			delete(s.peers, pid)
+		}
+	}
+}
+
+func (s *status) decayLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.DecayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.decay()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package peers
+
+import (
+	"context"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStatus_Connections(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newStatus(ctx, Config{})
+
+	s.SetConnected("a")
+	s.SetConnected("b")
+	require.ElementsMatch(t, []peer.ID{"a", "b"}, s.Active())
+
+	s.SetDisconnected("a")
+	s.SetDisconnected("unknown")
+	require.ElementsMatch(t, []peer.ID{"b"}, s.Active())
+	// disconnected peers w/o bad responses are not kept
+	require.Len(t, s.peers, 1)
+}
+
+func TestStatus_BadResponses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newStatus(ctx, Config{BadResponsesThreshold: 2, DecayInterval: time.Hour})
+
+	require.False(t, s.IsBad("a"))
+	s.IncrementBadResponses("a")
+	require.False(t, s.IsBad("a"))
+	s.IncrementBadResponses("a")
+	require.True(t, s.IsBad("a"))
+
+	// bad peers are kept after disconnection
+	s.SetConnected("a")
+	s.SetDisconnected("a")
+	require.True(t, s.IsBad("a"))
+
+	s.decay()
+	require.False(t, s.IsBad("a"))
+	s.decay()
+	require.Len(t, s.peers, 0)
+}
+
+func TestStatus_DecayLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newStatus(ctx, Config{BadResponsesThreshold: 1, DecayInterval: 10 * time.Millisecond})
+
+	s.IncrementBadResponses("a")
+	require.True(t, s.IsBad("a"))
+	require.Eventually(t, func() bool {
+		return !s.IsBad("a")
+	}, time.Second, 10*time.Millisecond)
+}
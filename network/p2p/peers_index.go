@@ -1,28 +1,62 @@
 package p2p
 
 import (
+	"encoding/json"
+	ssvnetwork "github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p/p2p/protocol/identify"
+	manet "github.com/multiformats/go-multiaddr/net"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"sort"
 	"sync"
+	"time"
 )
 
 const (
+	// latencyWeight is the weight of a new sample in the moving average of peers latency
+	latencyWeight  = 0.3
 	libp2pAgentKey = "AgentVersion"
 	// UserAgentKey is the key for storing to the user agent value
 	UserAgentKey = "user-agent"
+	// VersionKey is the key of the build version (<app>:<version>), parsed from the user agent
+	VersionKey = "version"
+	// CommitKey is the key of the build commit hash, parsed from the user agent
+	CommitKey = "commit"
+	// ForkIDKey is the key of the network fork of the peer, parsed from the user agent
+	ForkIDKey = "fork-id"
+	// OperatorKey is the key of the hash of the operator public key of the peer, parsed from the user agent
+	OperatorKey = "operator"
+
+	// peerIdentifyInterval is the interval of re-identifying a connected peer that was identified already
+	peerIdentifyInterval = 30 * time.Minute
+	// peerRecordSaveInterval is the min interval of persisting the record of a peer whose identity wasn't changed
+	peerRecordSaveInterval = 10 * time.Minute
+	// peerRecordsRetention is how long records of peers that were not seen are kept
+	peerRecordsRetention = 7 * 24 * time.Hour
 )
 
+// peerRecordsPrefix is the db prefix of the persisted peer records
+var peerRecordsPrefix = []byte("p2p-peer-record-")
+
 // IndexData is the type of stored data
 type IndexData map[string]string
 
-// PeersIndex is responsible for indexing peers information
-// index data is not persisted at the moment
+// PeersIndex is responsible for indexing peers information,
+// the records of indexed peers are persisted (if a db was provided) and loaded on startup
 type PeersIndex interface {
 	Run()
 	GetPeerData(pid, key string) string
+	// ReportLatency adds a round-trip time sample of the given peer
+	ReportLatency(pid string, rtt time.Duration)
+	// GetPeerLatency returns the average round-trip time of the given peer, false if no samples were reported
+	GetPeerLatency(pid string) (time.Duration, bool)
+	// ReportScore sets the gossipsub score of the given peer
+	ReportScore(pid string, score float64)
+	// Records returns the records of the peers that were seen since the given time, sorted by peer id
+	Records(since time.Time) []ssvnetwork.PeerRecord
 }
 
 // peersIndex implements PeersIndex
@@ -33,27 +67,53 @@ type peersIndex struct {
 	ids  *identify.IDService
 
 	index *sync.Map
+
+	latencyLock sync.RWMutex
+	latency     map[string]time.Duration
+
+	db          basedb.IDb
+	recordsLock sync.RWMutex
+	records     map[string]*ssvnetwork.PeerRecord
+	// identified is the last identification time of peers, since startup
+	identified map[string]time.Time
+	// saved is the last time the record of a peer was persisted
+	saved map[string]time.Time
+
+	now func() time.Time
 }
 
-// NewPeersIndex creates a new instance
-func NewPeersIndex(host host.Host, ids *identify.IDService, logger *zap.Logger) PeersIndex {
+// NewPeersIndex creates a new instance, records are persisted if a db was provided
+func NewPeersIndex(host host.Host, ids *identify.IDService, logger *zap.Logger, db basedb.IDb) PeersIndex {
 	pi := peersIndex{
-		host:   host,
-		ids:    ids,
-		index:  new(sync.Map),
-		logger: logger,
+		host:       host,
+		ids:        ids,
+		index:      new(sync.Map),
+		logger:     logger,
+		latency:    make(map[string]time.Duration),
+		db:         db,
+		records:    make(map[string]*ssvnetwork.PeerRecord),
+		identified: make(map[string]time.Time),
+		saved:      make(map[string]time.Time),
+		now:        time.Now,
 	}
+	pi.loadRecords()
 
 	return &pi
 }
 
-// Run tries to index data on all available peers
+// Run tries to index data on all available peers,
+// peers that were identified recently are only marked as seen
 func (pi *peersIndex) Run() {
 	if pi.ids == nil {
 		return
 	}
 	conns := pi.host.Network().Conns()
 	for _, conn := range conns {
+		pid := conn.RemotePeer().String()
+		if last, ok := pi.lastIdentified(pid); ok && pi.now().Sub(last) < peerIdentifyInterval {
+			pi.updateRecord(pid, conn, "")
+			continue
+		}
 		if err := pi.indexPeerConnection(conn); err != nil {
 			pi.logger.Warn("failed to report peer identity")
 		}
@@ -76,6 +136,26 @@ func (pi *peersIndex) GetPeerData(pid, key string) string {
 	return ""
 }
 
+// ReportLatency adds a round-trip time sample of the given peer to its moving average
+func (pi *peersIndex) ReportLatency(pid string, rtt time.Duration) {
+	pi.latencyLock.Lock()
+	defer pi.latencyLock.Unlock()
+
+	if avg, found := pi.latency[pid]; found {
+		rtt = time.Duration(latencyWeight*float64(rtt) + (1-latencyWeight)*float64(avg))
+	}
+	pi.latency[pid] = rtt
+}
+
+// GetPeerLatency returns the average round-trip time of the given peer, false if no samples were reported
+func (pi *peersIndex) GetPeerLatency(pid string) (time.Duration, bool) {
+	pi.latencyLock.RLock()
+	defer pi.latencyLock.RUnlock()
+
+	rtt, found := pi.latency[pid]
+	return rtt, found
+}
+
 // indexPeerConnection indexes the given peer / connection
 func (pi *peersIndex) indexPeerConnection(conn network.Conn) error {
 	pid := conn.RemotePeer()
@@ -99,6 +179,144 @@ func (pi *peersIndex) indexPeerConnection(conn network.Conn) error {
 		data = IndexData{}
 	}
 	data[UserAgentKey] = av
+	ua := parseUserAgent(av)
+	data[VersionKey] = ua.BuildData
+	data[CommitKey] = ua.Commit
+	data[ForkIDKey] = ua.ForkID
+	data[OperatorKey] = ua.Operator
 	pi.index.Store(pid.String(), data)
+	pi.updateRecord(pid.String(), conn, av)
 	return nil
 }
+
+// lastIdentified returns the last identification time of the given peer
+func (pi *peersIndex) lastIdentified(pid string) (time.Time, bool) {
+	pi.recordsLock.RLock()
+	defer pi.recordsLock.RUnlock()
+
+	t, ok := pi.identified[pid]
+	return t, ok
+}
+
+// ReportScore sets the gossipsub score of the given peer, the score is persisted with the next update of the record
+func (pi *peersIndex) ReportScore(pid string, score float64) {
+	pi.recordsLock.Lock()
+	defer pi.recordsLock.Unlock()
+
+	if r, ok := pi.records[pid]; ok {
+		r.Score = score
+	}
+}
+
+// Records returns the records of the peers that were seen since the given time, sorted by peer id
+func (pi *peersIndex) Records(since time.Time) []ssvnetwork.PeerRecord {
+	connected := make(map[string]bool)
+	if pi.host != nil {
+		for _, pid := range pi.host.Network().Peers() {
+			connected[pid.String()] = true
+		}
+	}
+	sinceMs := since.UnixNano() / int64(time.Millisecond)
+
+	pi.recordsLock.RLock()
+	defer pi.recordsLock.RUnlock()
+
+	res := make([]ssvnetwork.PeerRecord, 0, len(pi.records))
+	for pid, r := range pi.records {
+		if r.LastSeen < sinceMs {
+			continue
+		}
+		record := *r
+		record.Connected = connected[pid]
+		res = append(res, record)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PeerID < res[j].PeerID
+	})
+	return res
+}
+
+// updateRecord marks the given peer as seen, and sets its identity if a user agent was provided.
+// the record is persisted if its identity was changed or it wasn't saved recently
+func (pi *peersIndex) updateRecord(pid string, conn network.Conn, userAgent string) {
+	now := pi.now()
+
+	pi.recordsLock.Lock()
+	defer pi.recordsLock.Unlock()
+
+	r, ok := pi.records[pid]
+	if !ok {
+		r = &ssvnetwork.PeerRecord{PeerID: pid}
+		pi.records[pid] = r
+	}
+	changed := !ok
+	if len(userAgent) > 0 {
+		pi.identified[pid] = now
+		if r.UserAgent != userAgent {
+			ua := parseUserAgent(userAgent)
+			r.UserAgent, r.Version, r.Commit, r.ForkID, r.Operator = userAgent, ua.BuildData, ua.Commit, ua.ForkID, ua.Operator
+			changed = true
+		}
+	}
+	if conn != nil {
+		if ip, err := manet.ToIP(conn.RemoteMultiaddr()); err == nil && r.IP != ip.String() {
+			r.IP = ip.String()
+			changed = true
+		}
+	}
+	r.LastSeen = now.UnixNano() / int64(time.Millisecond)
+	if !changed && now.Sub(pi.saved[pid]) < peerRecordSaveInterval {
+		return
+	}
+	pi.saveRecordNotSafe(r)
+	pi.saved[pid] = now
+}
+
+// saveRecordNotSafe persists the given record
+func (pi *peersIndex) saveRecordNotSafe(r *ssvnetwork.PeerRecord) {
+	if pi.db == nil {
+		return
+	}
+	raw, err := json.Marshal(r)
+	if err != nil {
+		pi.logger.Warn("could not marshal peer record", zap.Error(err))
+		return
+	}
+	if err := pi.db.Set(peerRecordsPrefix, []byte(r.PeerID), raw); err != nil {
+		pi.logger.Warn("could not save peer record", zap.String("peer", r.PeerID), zap.Error(err))
+	}
+}
+
+// loadRecords loads the persisted records and their index data, expired records are removed
+func (pi *peersIndex) loadRecords() {
+	if pi.db == nil {
+		return
+	}
+	objs, err := pi.db.GetAllByCollection(peerRecordsPrefix)
+	if err != nil {
+		pi.logger.Warn("could not load peer records", zap.Error(err))
+		return
+	}
+	expiry := pi.now().Add(-peerRecordsRetention).UnixNano() / int64(time.Millisecond)
+	for _, obj := range objs {
+		r := new(ssvnetwork.PeerRecord)
+		if err := json.Unmarshal(obj.Value, r); err != nil || r.LastSeen < expiry {
+			if err := pi.db.Delete(peerRecordsPrefix, obj.Key); err != nil {
+				pi.logger.Debug("could not delete peer record", zap.Error(err))
+			}
+			continue
+		}
+		r.Connected = false
+		pi.records[r.PeerID] = r
+		if len(r.UserAgent) > 0 {
+			pi.index.Store(r.PeerID, IndexData{
+				UserAgentKey: r.UserAgent,
+				VersionKey:   r.Version,
+				CommitKey:    r.Commit,
+				ForkIDKey:    r.ForkID,
+				OperatorKey:  r.Operator,
+			})
+		}
+	}
+	pi.logger.Debug("loaded peer records", zap.Int("count", len(pi.records)))
+}
@@ -2,6 +2,8 @@ package p2p
 
 import (
 	"context"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -44,6 +46,11 @@ func TestNewPeersIndex(t *testing.T) {
 		// get peer 1 data from peers index 2
 		require.Equal(t, ua+"1", pi2.GetPeerData(host1.ID().String(), UserAgentKey))
 	})
+
+	t.Run("parsed user agent", func(t *testing.T) {
+		require.Equal(t, "test:0.0.0", pi1.GetPeerData(host2.ID().String(), VersionKey))
+		require.Equal(t, "", pi1.GetPeerData(host2.ID().String(), CommitKey))
+	})
 }
 
 func newHostWithPeersIndex(ctx context.Context, t *testing.T, ua string) (host.Host, PeersIndex) {
@@ -54,7 +61,7 @@ func newHostWithPeersIndex(ctx context.Context, t *testing.T, ua string) (host.H
 	require.NoError(t, err)
 	ids, err := identify.NewIDService(host, identify.UserAgent(ua))
 	require.NoError(t, err)
-	pi := NewPeersIndex(host, ids, zap.L())
+	pi := NewPeersIndex(host, ids, zap.L(), nil)
 
 	return host, pi
 }
@@ -70,3 +77,68 @@ func wait(duration time.Duration) {
 	}()
 	wg.Wait()
 }
+
+func TestPeersIndex_Latency(t *testing.T) {
+	pi := NewPeersIndex(nil, nil, zap.L(), nil)
+
+	_, found := pi.GetPeerLatency("xxx")
+	require.False(t, found)
+
+	pi.ReportLatency("xxx", 100*time.Millisecond)
+	rtt, found := pi.GetPeerLatency("xxx")
+	require.True(t, found)
+	require.Equal(t, 100*time.Millisecond, rtt)
+
+	// moving average
+	pi.ReportLatency("xxx", 200*time.Millisecond)
+	rtt, _ = pi.GetPeerLatency("xxx")
+	require.Equal(t, 130*time.Millisecond, rtt)
+}
+
+func TestPeersIndex_Records(t *testing.T) {
+	logger := zap.L()
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: logger,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	pi := NewPeersIndex(nil, nil, logger, db).(*peersIndex)
+	now := time.Now()
+	pi.now = func() time.Time {
+		return now
+	}
+	pi.updateRecord("peer-1", nil, "SSV-Node:v0.1.1:xxx:1a2b3c4:v0")
+	pi.ReportScore("peer-1", 12.5)
+	pi.updateRecord("peer-2", nil, "SSV-Node:v0.1.0")
+	// the score is persisted once the record is saved again
+	now = now.Add(peerRecordSaveInterval)
+	pi.updateRecord("peer-1", nil, "")
+
+	records := pi.Records(now.Add(-time.Minute))
+	require.Len(t, records, 1)
+	require.Equal(t, "peer-1", records[0].PeerID)
+	require.Equal(t, "SSV-Node:v0.1.1", records[0].Version)
+	require.Equal(t, "xxx", records[0].Operator)
+	require.Equal(t, 12.5, records[0].Score)
+	require.Len(t, pi.Records(time.Time{}), 2)
+
+	// records and index data are loaded after a restart
+	loaded := NewPeersIndex(nil, nil, logger, db)
+	records = loaded.Records(time.Time{})
+	require.Len(t, records, 2)
+	require.Equal(t, 12.5, records[0].Score)
+	require.Equal(t, "v0", records[0].ForkID)
+	require.Equal(t, "SSV-Node:v0.1.0", loaded.GetPeerData("peer-2", UserAgentKey))
+	require.Equal(t, "xxx", loaded.GetPeerData("peer-1", OperatorKey))
+
+	// expired records are removed
+	expired := pi.records["peer-1"]
+	expired.LastSeen = time.Now().Add(-peerRecordsRetention-time.Minute).UnixNano() / int64(time.Millisecond)
+	pi.saveRecordNotSafe(expired)
+	loaded = NewPeersIndex(nil, nil, logger, db)
+	records = loaded.Records(time.Time{})
+	require.Len(t, records, 1)
+	require.Equal(t, "peer-2", records[0].PeerID)
+}
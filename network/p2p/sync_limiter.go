@@ -0,0 +1,136 @@
+package p2p
+
+import (
+	"context"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+	"sync"
+)
+
+var (
+	metricsSyncStreamsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:network:sync_streams_active",
+		Help: "Count of outbound sync requests that are in progress",
+	})
+	metricsSyncStreamsWaiting = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:network:sync_streams_waiting",
+		Help: "Count of outbound sync requests that are waiting for a free stream",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsSyncStreamsActive); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsSyncStreamsWaiting); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// peerSlots are the stream slots of a single peer, removed once no request is using or waiting for them
+type peerSlots struct {
+	slots chan struct{}
+	refs  int
+}
+
+// syncLimiter caps the number of concurrent outbound sync streams, globally and per peer.
+// a request takes a slot of its peer before taking a global slot, so a peer with a long backlog doesn't hold
+// global slots while waiting, waiting requests are served in order (FIFO)
+type syncLimiter struct {
+	global  chan struct{}
+	perPeer int
+
+	lock  sync.Mutex
+	peers map[peer.ID]*peerSlots
+}
+
+// newSyncLimiter creates a new limiter, zero limits are disabled
+func newSyncLimiter(maxStreams, maxStreamsPerPeer int) *syncLimiter {
+	l := &syncLimiter{
+		perPeer: maxStreamsPerPeer,
+		peers:   make(map[peer.ID]*peerSlots),
+	}
+	if maxStreams > 0 {
+		l.global = make(chan struct{}, maxStreams)
+	}
+	return l
+}
+
+// acquire blocks until a stream to the given peer can be opened, the returned function must be called
+// once the request is done
+func (l *syncLimiter) acquire(ctx context.Context, pid peer.ID) (func(), error) {
+	metricsSyncStreamsWaiting.Inc()
+	defer metricsSyncStreamsWaiting.Dec()
+
+	ps := l.peerSlots(pid)
+	if ps != nil {
+		select {
+		case ps.slots <- struct{}{}:
+		case <-ctx.Done():
+			l.releasePeer(pid, false)
+			return nil, errors.Wrap(ctx.Err(), "could not acquire peer sync stream")
+		}
+	}
+	if l.global != nil {
+		select {
+		case l.global <- struct{}{}:
+		case <-ctx.Done():
+			if ps != nil {
+				l.releasePeer(pid, true)
+			}
+			return nil, errors.Wrap(ctx.Err(), "could not acquire sync stream")
+		}
+	}
+	metricsSyncStreamsActive.Inc()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			metricsSyncStreamsActive.Dec()
+			if l.global != nil {
+				<-l.global
+			}
+			if ps != nil {
+				l.releasePeer(pid, true)
+			}
+		})
+	}, nil
+}
+
+// peerSlots returns the slots of the given peer and adds a reference, nil is returned if per peer limit is disabled
+func (l *syncLimiter) peerSlots(pid peer.ID) *peerSlots {
+	if l.perPeer <= 0 {
+		return nil
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	ps, ok := l.peers[pid]
+	if !ok {
+		ps = &peerSlots{slots: make(chan struct{}, l.perPeer)}
+		l.peers[pid] = ps
+	}
+	ps.refs++
+	return ps
+}
+
+// releasePeer removes a reference to the slots of the given peer, and frees the slot if it was taken
+func (l *syncLimiter) releasePeer(pid peer.ID, taken bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	ps, ok := l.peers[pid]
+	if !ok {
+		return
+	}
+	if taken {
+		<-ps.slots
+	}
+	ps.refs--
+	if ps.refs == 0 {
+		delete(l.peers, pid)
+	}
+}
@@ -0,0 +1,107 @@
+package p2p
+
+import (
+	"context"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncLimiter_PerPeer(t *testing.T) {
+	l := newSyncLimiter(0, 2)
+	ctx := context.Background()
+
+	release1, err := l.acquire(ctx, "a")
+	require.NoError(t, err)
+	release2, err := l.acquire(ctx, "a")
+	require.NoError(t, err)
+	// other peers are not affected
+	releaseB, err := l.acquire(ctx, "b")
+	require.NoError(t, err)
+
+	// third request to the same peer is blocked
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(timeoutCtx, "a")
+	require.Error(t, err)
+
+	release1()
+	release1() // releasing twice has no effect
+	release3, err := l.acquire(ctx, "a")
+	require.NoError(t, err)
+
+	release2()
+	release3()
+	releaseB()
+	require.Len(t, l.peers, 0)
+}
+
+func TestSyncLimiter_Global(t *testing.T) {
+	l := newSyncLimiter(2, 1)
+	ctx := context.Background()
+
+	releaseA, err := l.acquire(ctx, "a")
+	require.NoError(t, err)
+	releaseB, err := l.acquire(ctx, "b")
+	require.NoError(t, err)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(timeoutCtx, "c")
+	require.Error(t, err)
+	// the peer slot of the canceled request was freed
+	require.Len(t, l.peers, 2)
+
+	releaseA()
+	releaseC, err := l.acquire(ctx, "c")
+	require.NoError(t, err)
+	releaseB()
+	releaseC()
+	require.Len(t, l.peers, 0)
+}
+
+func TestSyncLimiter_Concurrency(t *testing.T) {
+	l := newSyncLimiter(4, 2)
+	ctx := context.Background()
+	peers := []peer.ID{"a", "b", "c"}
+
+	var lock sync.Mutex
+	active := map[peer.ID]int{}
+	total, maxTotal := 0, 0
+	maxPerPeer := map[peer.ID]int{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 60; i++ {
+		wg.Add(1)
+		go func(pid peer.ID) {
+			defer wg.Done()
+			release, err := l.acquire(ctx, pid)
+			require.NoError(t, err)
+			lock.Lock()
+			active[pid]++
+			total++
+			if active[pid] > maxPerPeer[pid] {
+				maxPerPeer[pid] = active[pid]
+			}
+			if total > maxTotal {
+				maxTotal = total
+			}
+			lock.Unlock()
+			time.Sleep(time.Millisecond)
+			lock.Lock()
+			active[pid]--
+			total--
+			lock.Unlock()
+			release()
+		}(peers[i%len(peers)])
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, maxTotal, 4)
+	for _, pid := range peers {
+		require.LessOrEqual(t, maxPerPeer[pid], 2)
+	}
+	require.Len(t, l.peers, 0)
+}
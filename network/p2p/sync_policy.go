@@ -0,0 +1,182 @@
+package p2p
+
+import (
+	"crypto/rsa"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+	"sync"
+)
+
+const (
+	// SyncPolicyEveryone allows every peer to sync from the node
+	SyncPolicyEveryone = "everyone"
+	// SyncPolicyCommittee allows only committee members of the requested validator and allowlisted peers,
+	// committee members are authenticated by requests that are signed with their operator key
+	SyncPolicyCommittee = "committee"
+	// SyncPolicyAllowlist allows only allowlisted peers
+	SyncPolicyAllowlist = "allowlist"
+)
+
+// errSyncNotAuthorized is returned to peers that are not allowed to sync from the node
+const errSyncNotAuthorized = "sync request is not authorized"
+
+var (
+	metricsSyncRequestsDenied = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:sync_requests_denied",
+		Help: "Count of inbound sync requests that were denied by the sync policy",
+	}, []string{"policy"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsSyncRequestsDenied); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// syncPolicy authorizes inbound sync requests, committee members prove their operator key by signing
+// their requests (see signSyncRequest). the signature covers the id of the requesting peer, which is authenticated
+// by the secured connection, therefore a signed request can't be used by other peers
+type syncPolicy struct {
+	policy    string
+	allowlist map[string]bool
+
+	lock      sync.RWMutex
+	committee network.CommitteeResolver
+	// operatorKeys caches the parsed operator public keys
+	operatorKeys map[string]*rsa.PublicKey
+}
+
+// newSyncPolicy creates a new policy, an error is returned for unknown policies
+func newSyncPolicy(policy string, allowlist []string) (*syncPolicy, error) {
+	switch policy {
+	case "":
+		policy = SyncPolicyEveryone
+	case SyncPolicyEveryone, SyncPolicyCommittee, SyncPolicyAllowlist:
+	default:
+		return nil, errors.Errorf("unknown sync policy %q", policy)
+	}
+	sp := &syncPolicy{
+		policy:       policy,
+		allowlist:    make(map[string]bool),
+		operatorKeys: make(map[string]*rsa.PublicKey),
+	}
+	for _, pid := range allowlist {
+		if len(pid) > 0 {
+			sp.allowlist[pid] = true
+		}
+	}
+	return sp, nil
+}
+
+// setCommitteeResolver sets the resolver of committees
+func (sp *syncPolicy) setCommitteeResolver(resolver network.CommitteeResolver) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	sp.committee = resolver
+}
+
+// committeeOf returns the committee operators of the validator of the given identifier (lambda),
+// false if the committee is unknown or no resolver was set
+func (sp *syncPolicy) committeeOf(identifier []byte) ([]string, bool) {
+	if sp == nil {
+		return nil, false
+	}
+	sp.lock.RLock()
+	resolver := sp.committee
+	sp.lock.RUnlock()
+	if resolver == nil {
+		return nil, false
+	}
+	return resolver(identifier)
+}
+
+// authorize returns true if the given peer is allowed to sync the identifier (lambda) of the given request,
+// a nil policy allows everyone
+func (sp *syncPolicy) authorize(pid string, msg *network.SyncMessage) bool {
+	if sp == nil || sp.policy == SyncPolicyEveryone || sp.allowlist[pid] {
+		return true
+	}
+	if sp.policy != SyncPolicyCommittee {
+		return false
+	}
+	// the request must be signed by the peer of the stream
+	if msg.FromPeerID != pid || len(msg.Signature) == 0 {
+		return false
+	}
+	operators, found := sp.committeeOf(msg.Lambda)
+	if !found {
+		return false
+	}
+	root, err := msg.SigningRoot()
+	if err != nil {
+		return false
+	}
+	for _, operator := range operators {
+		pk, err := sp.operatorKey(operator)
+		if err != nil {
+			continue
+		}
+		if rsaencryption.VerifySignedData(pk, root, msg.Signature) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// operatorKey returns the parsed public key of the given operator (base64 encoded pem)
+func (sp *syncPolicy) operatorKey(operator string) (*rsa.PublicKey, error) {
+	sp.lock.RLock()
+	pk, ok := sp.operatorKeys[operator]
+	sp.lock.RUnlock()
+	if ok {
+		return pk, nil
+	}
+	pk, err := rsaencryption.ConvertEncodedPemToPublicKey(operator)
+	if err != nil {
+		return nil, err
+	}
+	sp.lock.Lock()
+	sp.operatorKeys[operator] = pk
+	sp.lock.Unlock()
+	return pk, nil
+}
+
+// UseCommitteeResolver sets the resolver of committees, used to authorize sync requests of committee members
+func (n *p2pNetwork) UseCommitteeResolver(resolver network.CommitteeResolver) {
+	n.syncPolicy.setCommitteeResolver(resolver)
+}
+
+// rejectSyncRequest responds to a sync request that was denied by the sync policy
+func (n *p2pNetwork) rejectSyncRequest(stream network.SyncStream, msg *network.SyncMessage) {
+	metricsSyncRequestsDenied.WithLabelValues(n.syncPolicy.policy).Inc()
+	n.logger.Debug("sync request was denied", zap.String("peer", stream.RemotePeer()),
+		zap.String("policy", n.syncPolicy.policy))
+
+	defer func() {
+		_ = stream.Close()
+	}()
+	res := &network.SyncMessage{
+		Lambda:     msg.Lambda,
+		Type:       msg.Type,
+		Error:      errSyncNotAuthorized,
+		FromPeerID: n.host.ID().Pretty(),
+	}
+	if err := n.signSyncResponse(res); err != nil {
+		n.logger.Debug("could not sign sync rejection", zap.Error(err))
+		return
+	}
+	msgBytes, err := n.encodeSyncMessage(res)
+	if err != nil {
+		n.logger.Debug("could not encode sync rejection", zap.Error(err))
+		return
+	}
+	if err := n.writeSyncMessage(stream, msgBytes); err != nil {
+		n.logger.Debug("could not write sync rejection", zap.Error(err))
+	}
+}
@@ -0,0 +1,74 @@
+package p2p
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSyncPolicy(t *testing.T) {
+	// operator 1 is a committee member, operator 2 is not
+	sk1, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pk1, err := rsaencryption.ExtractPublicKey(sk1)
+	require.NoError(t, err)
+	sk2, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	resolver := func(identifier []byte) ([]string, bool) {
+		if string(identifier) != "xxx_ATTESTER" {
+			return nil, false
+		}
+		return []string{"invalid", pk1}, true
+	}
+	identifier := []byte("xxx_ATTESTER")
+	// request returns a request of the given peer, signed by the given operator key
+	request := func(pid string, sk *rsa.PrivateKey, identifier []byte) *network.SyncMessage {
+		n := &p2pNetwork{operatorPrivKey: sk}
+		msg, err := n.signSyncRequest(&network.SyncMessage{Lambda: identifier, FromPeerID: pid, Type: network.Sync_GetHighestType})
+		require.NoError(t, err)
+		return msg
+	}
+
+	t.Run("everyone", func(t *testing.T) {
+		sp, err := newSyncPolicy("", nil)
+		require.NoError(t, err)
+		require.True(t, sp.authorize("c", request("c", nil, identifier)))
+	})
+
+	t.Run("committee", func(t *testing.T) {
+		sp, err := newSyncPolicy(SyncPolicyCommittee, []string{"exporter"})
+		require.NoError(t, err)
+		// committees are unknown until a resolver is set
+		require.False(t, sp.authorize("a", request("a", sk1, identifier)))
+		require.True(t, sp.authorize("exporter", request("exporter", nil, identifier)))
+
+		sp.setCommitteeResolver(resolver)
+		require.True(t, sp.authorize("a", request("a", sk1, identifier)))
+		// not a committee member
+		require.False(t, sp.authorize("b", request("b", sk2, identifier)))
+		// unsigned request
+		require.False(t, sp.authorize("a", request("a", nil, identifier)))
+		// a request of another peer
+		require.False(t, sp.authorize("c", request("a", sk1, identifier)))
+		// tampered request
+		tampered := request("a", sk1, identifier)
+		tampered.Params = []uint64{1}
+		require.False(t, sp.authorize("a", tampered))
+		require.False(t, sp.authorize("a", request("a", sk1, []byte("yyy_ATTESTER"))))
+		require.True(t, sp.authorize("exporter", request("exporter", nil, []byte("yyy_ATTESTER"))))
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		sp, err := newSyncPolicy(SyncPolicyAllowlist, []string{"exporter"})
+		require.NoError(t, err)
+		sp.setCommitteeResolver(resolver)
+		require.False(t, sp.authorize("a", request("a", sk1, identifier)))
+		require.True(t, sp.authorize("exporter", request("exporter", nil, identifier)))
+	})
+
+	_, err = newSyncPolicy("x", nil)
+	require.EqualError(t, err, "unknown sync policy \"x\"")
+}
@@ -0,0 +1,105 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+)
+
+var (
+	metricsBadPeerReports = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:bad_peer_reports",
+		Help: "Count of peers that were penalized for serving invalid data",
+	}, []string{"reason"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsBadPeerReports); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// signSyncResponse signs the given sync response with the network key of this node,
+// so the requester can prove which peer served the response
+func (n *p2pNetwork) signSyncResponse(msg *network.SyncMessage) error {
+	root, err := msg.SigningRoot()
+	if err != nil {
+		return err
+	}
+	sig, err := n.host.Peerstore().PrivKey(n.host.ID()).Sign(root)
+	if err != nil {
+		return errors.Wrap(err, "could not sign sync response")
+	}
+	msg.Signature = sig
+	return nil
+}
+
+// signSyncRequest returns a copy of the given sync request that is signed with the operator key of this node,
+// the request is returned as is if there is no operator key (e.g. exporter)
+func (n *p2pNetwork) signSyncRequest(msg *network.SyncMessage) (*network.SyncMessage, error) {
+	if n.operatorPrivKey == nil {
+		return msg, nil
+	}
+	signed := *msg
+	root, err := signed.SigningRoot()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := rsaencryption.SignData(n.operatorPrivKey, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign sync request")
+	}
+	signed.Signature = sig
+	return &signed, nil
+}
+
+// errUnsignedSyncResponse is returned for responses w/o a signature if signed responses are required
+var errUnsignedSyncResponse = errors.New("sync response is not signed")
+
+// verifySyncResponse verifies the signature of a sync response with the public key of the given peer,
+// responses w/o a signature (i.e. of peers that don't sign responses) are accepted unless requireSigned is set
+func verifySyncResponse(pid peer.ID, msg *network.SyncMessage, requireSigned bool) error {
+	if len(msg.Signature) == 0 {
+		if requireSigned {
+			return errUnsignedSyncResponse
+		}
+		return nil
+	}
+	pk, err := pid.ExtractPublicKey()
+	if err != nil {
+		return errors.Wrap(err, "could not extract peer public key")
+	}
+	root, err := msg.SigningRoot()
+	if err != nil {
+		return err
+	}
+	ok, err := pk.Verify(root, msg.Signature)
+	if err != nil {
+		return errors.Wrap(err, "could not verify signature")
+	}
+	if !ok {
+		return errs.Mark(errors.New("invalid signature"), errs.ErrInvalidSignature)
+	}
+	return nil
+}
+
+// ReportBadPeer penalizes the given peer by increasing its bad responses score,
+// peers that reach the threshold are considered bad and new connections with them are refused
+func (n *p2pNetwork) ReportBadPeer(peerStr string, reason string) {
+	metricsBadPeerReports.WithLabelValues(reason).Inc()
+	n.logger.Warn("reporting bad peer", zap.String("peer", peerStr), zap.String("reason", reason))
+	pid, err := peerFromString(peerStr)
+	if err != nil {
+		n.logger.Debug("could not parse reported peer", zap.Error(err))
+		return
+	}
+	if n.peers != nil {
+		n.peers.IncrementBadResponses(pid)
+	}
+}
@@ -2,21 +2,32 @@ package p2p
 
 import (
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	core "github.com/libp2p/go-libp2p-core"
 	"github.com/pkg/errors"
+	"io"
 	"io/ioutil"
+	"net"
 	"time"
 )
 
 // syncStream is a wrapper struct for the core.Stream interface to match the network.SyncStream interface
 type syncStream struct {
 	stream core.Stream
+	// maxSize is the max size in bytes of the data that is read from the stream, 0 means no limit
+	maxSize int
 }
 
 // NewSyncStream returns a new instance of syncStream
 func NewSyncStream(stream core.Stream) network.SyncStream {
+	return newLimitedSyncStream(stream, 0)
+}
+
+// newLimitedSyncStream returns a new instance of syncStream that reads up to maxSize bytes
+func newLimitedSyncStream(stream core.Stream, maxSize int) network.SyncStream {
 	return &syncStream{
-		stream: stream,
+		stream:  stream,
+		maxSize: maxSize,
 	}
 }
 
@@ -35,12 +46,30 @@ func (s *syncStream) RemotePeer() string {
 	return s.stream.Conn().RemotePeer().String()
 }
 
-// ReadWithTimeout reads with timeout
+// ReadWithTimeout reads with timeout, data that exceeds the max size is rejected before it is returned
 func (s *syncStream) ReadWithTimeout(timeout time.Duration) ([]byte, error) {
 	if err := s.stream.SetReadDeadline(time.Now().Add(timeout)); err != nil {
 		return nil, errors.Wrap(err, "could not set read deadline")
 	}
-	return ioutil.ReadAll(s.stream)
+	var r io.Reader = s.stream
+	if s.maxSize > 0 {
+		// an additional byte is read to detect data that exceeds the limit
+		r = io.LimitReader(s.stream, int64(s.maxSize)+1)
+	}
+	data, err := ioutil.ReadAll(r)
+	if isTimeout(err) {
+		return data, errs.Mark(err, errs.ErrSyncTimeout)
+	}
+	if err == nil && s.maxSize > 0 && len(data) > s.maxSize {
+		return nil, errs.Mark(errors.Errorf("stream data exceeds the max size of %d bytes", s.maxSize), errs.ErrMessageTooLarge)
+	}
+	return data, err
+}
+
+// isTimeout returns true if the given error was caused by a deadline
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
 }
 
 // WriteWithTimeout reads with timeout
@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/bloxapp/ssv/utils/logex"
 	"github.com/bloxapp/ssv/utils/threadsafe"
 	core "github.com/libp2p/go-libp2p-core"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"testing"
@@ -53,13 +55,14 @@ func testPeers(t *testing.T, logger *zap.Logger) (network.Network, network.Netwo
 func TestSyncStream_ReadWithTimeout(t *testing.T) {
 	logger := logex.Build("test", zap.DebugLevel, nil)
 	peer1, peer2 := testPeers(t, logger)
-	s, err := peer1.(*p2pNetwork).host.NewStream(context.Background(), peer2.(*p2pNetwork).host.ID(), highestDecidedStream)
+	s, err := peer1.(*p2pNetwork).host.NewStream(context.Background(), peer2.(*p2pNetwork).host.ID(), peer1.(*p2pNetwork).syncProtocol(highestDecidedStream))
 	require.NoError(t, err)
 
 	strm := NewSyncStream(s)
 
 	byts, err := strm.ReadWithTimeout(time.Second)
 	require.EqualError(t, err, "i/o deadline reached")
+	require.True(t, errors.Is(err, errs.ErrSyncTimeout))
 	require.Len(t, byts, 0)
 }
 
@@ -68,7 +71,7 @@ func TestSyncStream_ReadWithoutTimeout(t *testing.T) {
 	peer1, peer2 := testPeers(t, logger)
 
 	readByts := threadsafe.Bool()
-	peer2.(*p2pNetwork).host.SetStreamHandler(highestDecidedStream, func(stream core.Stream) {
+	peer2.(*p2pNetwork).host.SetStreamHandler(peer2.(*p2pNetwork).syncProtocol(highestDecidedStream), func(stream core.Stream) {
 		netSyncStream := NewSyncStream(stream)
 
 		// read msg
@@ -79,7 +82,7 @@ func TestSyncStream_ReadWithoutTimeout(t *testing.T) {
 		readByts.Set(true)
 	})
 
-	s, err := peer1.(*p2pNetwork).host.NewStream(context.Background(), peer2.(*p2pNetwork).host.ID(), highestDecidedStream)
+	s, err := peer1.(*p2pNetwork).host.NewStream(context.Background(), peer2.(*p2pNetwork).host.ID(), peer1.(*p2pNetwork).syncProtocol(highestDecidedStream))
 	require.NoError(t, err)
 	strm := NewSyncStream(s)
 	err = strm.WriteWithTimeout(make([]byte, 10), time.Millisecond*100)
@@ -89,3 +92,30 @@ func TestSyncStream_ReadWithoutTimeout(t *testing.T) {
 	time.Sleep(time.Millisecond * 300)
 	require.True(t, readByts.Get())
 }
+
+func TestSyncStream_ReadExceedsMaxSize(t *testing.T) {
+	logger := logex.Build("test", zap.DebugLevel, nil)
+	peer1, peer2 := testPeers(t, logger)
+
+	readErr := make(chan error, 1)
+	peer2.(*p2pNetwork).host.SetStreamHandler(peer2.(*p2pNetwork).syncProtocol(highestDecidedStream), func(stream core.Stream) {
+		netSyncStream := newLimitedSyncStream(stream, 8)
+		buf, err := netSyncStream.ReadWithTimeout(time.Millisecond * 100)
+		require.Nil(t, buf)
+		readErr <- err
+	})
+
+	s, err := peer1.(*p2pNetwork).host.NewStream(context.Background(), peer2.(*p2pNetwork).host.ID(), peer1.(*p2pNetwork).syncProtocol(highestDecidedStream))
+	require.NoError(t, err)
+	strm := NewSyncStream(s)
+	require.NoError(t, strm.WriteWithTimeout(make([]byte, 10), time.Millisecond*100))
+	require.NoError(t, strm.CloseWrite())
+
+	select {
+	case err := <-readErr:
+		require.EqualError(t, err, "stream data exceeds the max size of 8 bytes")
+		require.True(t, errors.Is(err, errs.ErrMessageTooLarge))
+	case <-time.After(time.Second):
+		t.Fatal("stream was not read")
+	}
+}
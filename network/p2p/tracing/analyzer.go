@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxEventSize protects from reading corrupted files
+const maxEventSize = 1 << 22
+
+// TopicSummary holds the mesh related counters of a single topic
+type TopicSummary struct {
+	Grafts     int
+	Prunes     int
+	Published  int
+	Delivered  int
+	Duplicates int
+	Rejected   int
+}
+
+// DuplicateRatio returns the number of duplicates per delivered message
+func (ts *TopicSummary) DuplicateRatio() float64 {
+	if ts.Delivered == 0 {
+		return 0
+	}
+	return float64(ts.Duplicates) / float64(ts.Delivered)
+}
+
+// Summary describes the mesh health as seen in the traces
+type Summary struct {
+	Events       int
+	From         time.Time
+	To           time.Time
+	PeersAdded   int
+	PeersRemoved int
+	TopicSummary
+	Topics map[string]*TopicSummary
+	// RejectReasons counts rejected messages by reason
+	RejectReasons map[string]int
+}
+
+// NewSummary creates an empty summary
+func NewSummary() *Summary {
+	return &Summary{
+		Topics:        map[string]*TopicSummary{},
+		RejectReasons: map[string]int{},
+	}
+}
+
+// Analyze reads the given trace files and summarizes them
+func Analyze(paths ...string) (*Summary, error) {
+	s := NewSummary()
+	for _, path := range paths {
+		if err := ReadFile(path, s.Add); err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", path)
+		}
+	}
+	return s, nil
+}
+
+// Add adds the given event to the summary
+func (s *Summary) Add(evt *pb.TraceEvent) error {
+	s.Events++
+	if evt.Timestamp != nil {
+		ts := time.Unix(0, evt.GetTimestamp())
+		if s.From.IsZero() || ts.Before(s.From) {
+			s.From = ts
+		}
+		if ts.After(s.To) {
+			s.To = ts
+		}
+	}
+	switch evt.GetType() {
+	case pb.TraceEvent_GRAFT:
+		s.Grafts++
+		s.topic(evt.GetGraft().GetTopic()).Grafts++
+	case pb.TraceEvent_PRUNE:
+		s.Prunes++
+		s.topic(evt.GetPrune().GetTopic()).Prunes++
+	case pb.TraceEvent_PUBLISH_MESSAGE:
+		s.Published++
+		s.topic(evt.GetPublishMessage().GetTopic()).Published++
+	case pb.TraceEvent_DELIVER_MESSAGE:
+		s.Delivered++
+		s.topic(evt.GetDeliverMessage().GetTopic()).Delivered++
+	case pb.TraceEvent_DUPLICATE_MESSAGE:
+		s.Duplicates++
+		s.topic(evt.GetDuplicateMessage().GetTopic()).Duplicates++
+	case pb.TraceEvent_REJECT_MESSAGE:
+		s.Rejected++
+		s.topic(evt.GetRejectMessage().GetTopic()).Rejected++
+		s.RejectReasons[evt.GetRejectMessage().GetReason()]++
+	case pb.TraceEvent_ADD_PEER:
+		s.PeersAdded++
+	case pb.TraceEvent_REMOVE_PEER:
+		s.PeersRemoved++
+	}
+	return nil
+}
+
+func (s *Summary) topic(name string) *TopicSummary {
+	ts, ok := s.Topics[name]
+	if !ok {
+		ts = &TopicSummary{}
+		s.Topics[name] = ts
+	}
+	return ts
+}
+
+// String returns a human readable report
+func (s *Summary) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("events: %d, from: %s, to: %s\n", s.Events, s.From.UTC(), s.To.UTC()))
+	sb.WriteString(fmt.Sprintf("peers added: %d, removed: %d\n", s.PeersAdded, s.PeersRemoved))
+	sb.WriteString(fmt.Sprintf("%-50s %8s %8s %10s %10s %10s %10s %8s\n",
+		"topic", "grafts", "prunes", "published", "delivered", "duplicate", "rejected", "dup/msg"))
+	topics := make([]string, 0, len(s.Topics))
+	for name := range s.Topics {
+		topics = append(topics, name)
+	}
+	sort.Strings(topics)
+	for _, name := range topics {
+		writeTopicLine(&sb, name, s.Topics[name])
+	}
+	writeTopicLine(&sb, "total", &s.TopicSummary)
+	reasons := make([]string, 0, len(s.RejectReasons))
+	for reason := range s.RejectReasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		sb.WriteString(fmt.Sprintf("rejected (%s): %d\n", reason, s.RejectReasons[reason]))
+	}
+	return sb.String()
+}
+
+func writeTopicLine(sb *strings.Builder, name string, ts *TopicSummary) {
+	sb.WriteString(fmt.Sprintf("%-50s %8d %8d %10d %10d %10d %10d %8.2f\n", name, ts.Grafts, ts.Prunes,
+		ts.Published, ts.Delivered, ts.Duplicates, ts.Rejected, ts.DuplicateRatio()))
+}
+
+// ReadFile reads the events in the given trace file, the format (pb or json) is detected by content
+func ReadFile(path string, handler func(evt *pb.TraceEvent) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	r := bufio.NewReader(f)
+	first, err := r.Peek(1)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if first[0] == '{' {
+		return readJSON(r, handler)
+	}
+	return readPB(r, handler)
+}
+
+func readJSON(r io.Reader, handler func(evt *pb.TraceEvent) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		evt := new(pb.TraceEvent)
+		if err := dec.Decode(evt); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "could not decode trace event")
+		}
+		if err := handler(evt); err != nil {
+			return err
+		}
+	}
+}
+
+func readPB(r *bufio.Reader, handler func(evt *pb.TraceEvent) error) error {
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "could not read event size")
+		}
+		if size > maxEventSize {
+			return errors.Errorf("event size %d is too big", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return errors.Wrap(err, "could not read event")
+		}
+		evt := new(pb.TraceEvent)
+		if err := evt.Unmarshal(data); err != nil {
+			return errors.Wrap(err, "could not unmarshal trace event")
+		}
+		if err := handler(evt); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// FormatPB writes varint delimited protobuf events, compatible with pubsub.PBTracer
+	FormatPB = "pb"
+	// FormatJSON writes an event per line in json, compatible with pubsub.JSONTracer
+	FormatJSON = "json"
+
+	// rotatedTimeLayout is used to name rotated files
+	rotatedTimeLayout = "20060102T150405"
+
+	tracerQueueSize = 4096
+	flushInterval   = time.Second
+)
+
+// Options holds the configuration of the tracer
+type Options struct {
+	Logger *zap.Logger
+	// Path is the file that holds the current traces
+	Path string
+	// Format is either FormatPB (default) or FormatJSON
+	Format string
+	// MaxSize is the max size in bytes of a single file, 0 disables size based rotation
+	MaxSize int64
+	// RotationInterval is the max age of a single file, 0 disables time based rotation
+	RotationInterval time.Duration
+}
+
+// rotatingTracer is a pubsub.EventTracer that writes events to a file and rotates it by size or time.
+// events are written asynchronously, if the queue is full the event is dropped so pubsub is never blocked
+type rotatingTracer struct {
+	ctx    context.Context
+	logger *zap.Logger
+	opts   Options
+
+	events  chan *pb.TraceEvent
+	dropped uint64
+
+	file   *os.File
+	w      *bufio.Writer
+	size   int64
+	opened time.Time
+
+	now func() time.Time
+}
+
+// NewTracer creates a new rotating tracer that writes until the given context is done
+func NewTracer(ctx context.Context, opts Options) (pubsub.EventTracer, error) {
+	if len(opts.Format) == 0 {
+		opts.Format = FormatPB
+	}
+	if opts.Format != FormatPB && opts.Format != FormatJSON {
+		return nil, errors.Errorf("unknown trace format %s", opts.Format)
+	}
+	t := &rotatingTracer{
+		ctx:    ctx,
+		logger: opts.Logger.With(zap.String("component", "p2p/tracer"), zap.String("path", opts.Path)),
+		opts:   opts,
+		events: make(chan *pb.TraceEvent, tracerQueueSize),
+		now:    time.Now,
+	}
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+	go t.run()
+	return t, nil
+}
+
+// Trace implements pubsub.EventTracer
+func (t *rotatingTracer) Trace(evt *pb.TraceEvent) {
+	select {
+	case t.events <- evt:
+	default:
+		if atomic.AddUint64(&t.dropped, 1)%1000 == 1 {
+			t.logger.Warn("trace queue is full, dropping events", zap.Uint64("dropped", atomic.LoadUint64(&t.dropped)))
+		}
+	}
+}
+
+func (t *rotatingTracer) run() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	defer t.close()
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.w.Flush(); err != nil {
+				t.logger.Warn("could not flush traces", zap.Error(err))
+			}
+			if t.shouldRotate(0) {
+				t.rotate()
+			}
+		case evt := <-t.events:
+			if err := t.write(evt); err != nil {
+				t.logger.Warn("could not write trace event", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (t *rotatingTracer) write(evt *pb.TraceEvent) error {
+	data, err := encodeEvent(evt, t.opts.Format)
+	if err != nil {
+		return err
+	}
+	if t.shouldRotate(int64(len(data))) {
+		t.rotate()
+	}
+	n, err := t.w.Write(data)
+	t.size += int64(n)
+	return err
+}
+
+// shouldRotate returns true if the current file is too old, or too big to hold n more bytes
+func (t *rotatingTracer) shouldRotate(n int64) bool {
+	if t.size == 0 {
+		return false
+	}
+	if t.opts.MaxSize > 0 && t.size+n > t.opts.MaxSize {
+		return true
+	}
+	return t.opts.RotationInterval > 0 && t.now().Sub(t.opened) >= t.opts.RotationInterval
+}
+
+// rotate closes the current file, renames it with a timestamp suffix and opens a new one
+func (t *rotatingTracer) rotate() {
+	t.close()
+	base := RotatedPath(t.opts.Path, t.now())
+	rotated := base
+	// avoid overriding a file that was rotated in the same second
+	for i := 1; fileExists(rotated); i++ {
+		ext := filepath.Ext(base)
+		rotated = fmt.Sprintf("%s.%d%s", strings.TrimSuffix(base, ext), i, ext)
+	}
+	if err := os.Rename(t.opts.Path, rotated); err != nil {
+		t.logger.Warn("could not rename trace file", zap.Error(err))
+	} else {
+		t.logger.Debug("rotated trace file", zap.String("rotated", rotated))
+	}
+	if err := t.open(); err != nil {
+		t.logger.Error("could not open trace file", zap.Error(err))
+	}
+}
+
+func (t *rotatingTracer) open() error {
+	f, err := os.OpenFile(t.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrap(err, "could not open trace file")
+	}
+	t.file = f
+	t.w = bufio.NewWriter(f)
+	t.size = 0
+	t.opened = t.now()
+	return nil
+}
+
+func (t *rotatingTracer) close() {
+	if err := t.w.Flush(); err != nil {
+		t.logger.Warn("could not flush traces", zap.Error(err))
+	}
+	if err := t.file.Close(); err != nil {
+		t.logger.Warn("could not close trace file", zap.Error(err))
+	}
+}
+
+// RotatedPath returns the name of a rotated file, e.g. trace.pb -> trace-20210101T000000.pb
+func RotatedPath(path string, ts time.Time) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(path, ext), ts.UTC().Format(rotatedTimeLayout), ext)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// encodeEvent encodes the given event according to the format
+func encodeEvent(evt *pb.TraceEvent, format string) ([]byte, error) {
+	if format == FormatJSON {
+		data, err := json.Marshal(evt)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal trace event")
+		}
+		return append(data, '\n'), nil
+	}
+	data, err := evt.Marshal()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal trace event")
+	}
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(data))
+	n := binary.PutUvarint(buf, uint64(len(data)))
+	return append(buf[:n], data...), nil
+}
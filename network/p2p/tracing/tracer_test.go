@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestTracer(t *testing.T, opts Options, now *time.Time) *rotatingTracer {
+	opts.Logger = zap.L()
+	tr := &rotatingTracer{
+		logger: opts.Logger,
+		opts:   opts,
+		now:    func() time.Time { return *now },
+	}
+	require.NoError(t, tr.open())
+	return tr
+}
+
+func traceEvent(typ pb.TraceEvent_Type, topic string, ts time.Time) *pb.TraceEvent {
+	nanos := ts.UnixNano()
+	evt := &pb.TraceEvent{Type: &typ, Timestamp: &nanos}
+	switch typ {
+	case pb.TraceEvent_GRAFT:
+		evt.Graft = &pb.TraceEvent_Graft{Topic: &topic}
+	case pb.TraceEvent_PRUNE:
+		evt.Prune = &pb.TraceEvent_Prune{Topic: &topic}
+	case pb.TraceEvent_DELIVER_MESSAGE:
+		evt.DeliverMessage = &pb.TraceEvent_DeliverMessage{Topic: &topic}
+	case pb.TraceEvent_DUPLICATE_MESSAGE:
+		evt.DuplicateMessage = &pb.TraceEvent_DuplicateMessage{Topic: &topic}
+	}
+	return evt
+}
+
+func TestTracer_RotateAndAnalyze(t *testing.T) {
+	for _, format := range []string{FormatPB, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			now := time.Unix(1630000000, 0)
+			tr := newTestTracer(t, Options{
+				Path:             filepath.Join(dir, "trace."+format),
+				Format:           format,
+				MaxSize:          300,
+				RotationInterval: time.Minute,
+			}, &now)
+
+			events := []*pb.TraceEvent{
+				traceEvent(pb.TraceEvent_GRAFT, "a", now),
+				traceEvent(pb.TraceEvent_GRAFT, "b", now),
+				traceEvent(pb.TraceEvent_PRUNE, "a", now),
+			}
+			for i := 0; i < 10; i++ {
+				events = append(events, traceEvent(pb.TraceEvent_DELIVER_MESSAGE, "a", now))
+				events = append(events, traceEvent(pb.TraceEvent_DUPLICATE_MESSAGE, "a", now))
+				events = append(events, traceEvent(pb.TraceEvent_DUPLICATE_MESSAGE, "a", now))
+			}
+			for _, evt := range events {
+				require.NoError(t, tr.write(evt))
+			}
+			// time based rotation
+			now = now.Add(2 * time.Minute)
+			require.True(t, tr.shouldRotate(0))
+			require.NoError(t, tr.write(traceEvent(pb.TraceEvent_PRUNE, "b", now)))
+			tr.close()
+
+			files, err := filepath.Glob(filepath.Join(dir, "trace*"))
+			require.NoError(t, err)
+			require.Greater(t, len(files), 2)
+
+			s, err := Analyze(files...)
+			require.NoError(t, err)
+			require.Equal(t, len(events)+1, s.Events)
+			require.Equal(t, 2, s.Grafts)
+			require.Equal(t, 2, s.Prunes)
+			require.Equal(t, 10, s.Delivered)
+			require.Equal(t, 20, s.Duplicates)
+			require.Equal(t, 1, s.Topics["a"].Prunes)
+			require.Equal(t, 2.0, s.Topics["a"].DuplicateRatio())
+			require.Equal(t, 2*time.Minute, s.To.Sub(s.From))
+			require.Contains(t, s.String(), "total")
+		})
+	}
+}
+
+func TestRotatedPath(t *testing.T) {
+	ts := time.Date(2021, 9, 1, 12, 30, 0, 0, time.UTC)
+	require.Equal(t, "/tmp/trace-20210901T123000.pb", RotatedPath("/tmp/trace.pb", ts))
+	require.Equal(t, "/tmp/trace-20210901T123000", RotatedPath("/tmp/trace", ts))
+}
@@ -0,0 +1,79 @@
+package p2p
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"sort"
+	"strings"
+	"time"
+)
+
+// userAgent holds the fields of the user agent of ssv nodes, formatted as <app>:<version>:<operator>:<commit>:<fork id>
+// where operator is the hash of the operator public key (empty for nodes w/o operator key, e.g. exporter).
+// older nodes announce only <app>:<version>[:<operator>]
+type userAgent struct {
+	// BuildData is <app>:<version>
+	BuildData string
+	Operator  string
+	Commit    string
+	ForkID    string
+}
+
+// String returns the formatted user agent, trailing empty fields are omitted
+func (ua userAgent) String() string {
+	return strings.TrimRight(strings.Join([]string{ua.BuildData, ua.Operator, ua.Commit, ua.ForkID}, ":"), ":")
+}
+
+// version returns the version part of the build data
+func (ua userAgent) version() string {
+	if i := strings.Index(ua.BuildData, ":"); i >= 0 {
+		return ua.BuildData[i+1:]
+	}
+	return ""
+}
+
+// parseUserAgent parses the given user agent, missing fields are left empty
+func parseUserAgent(s string) userAgent {
+	parts := strings.Split(s, ":")
+	var ua userAgent
+	if len(parts) < 2 {
+		ua.BuildData = s
+		return ua
+	}
+	ua.BuildData = parts[0] + ":" + parts[1]
+	if len(parts) > 2 {
+		ua.Operator = parts[2]
+	}
+	if len(parts) > 3 {
+		ua.Commit = parts[3]
+	}
+	if len(parts) > 4 {
+		ua.ForkID = parts[4]
+	}
+	return ua
+}
+
+// PeersVersions returns the build information of the connected peers that were indexed, sorted by peer id
+func (n *p2pNetwork) PeersVersions() []network.PeerVersion {
+	var res []network.PeerVersion
+	for _, pid := range n.host.Network().Peers() {
+		p := pid.String()
+		if len(n.peersIndex.GetPeerData(p, UserAgentKey)) == 0 {
+			continue
+		}
+		res = append(res, network.PeerVersion{
+			PeerID:  p,
+			Version: n.peersIndex.GetPeerData(p, VersionKey),
+			Commit:  n.peersIndex.GetPeerData(p, CommitKey),
+			ForkID:  n.peersIndex.GetPeerData(p, ForkIDKey),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PeerID < res[j].PeerID
+	})
+	return res
+}
+
+// PeersRecords returns the records of the peers that were seen since the given time, sorted by peer id
+func (n *p2pNetwork) PeersRecords(since time.Time) []network.PeerRecord {
+	return n.peersIndex.Records(since)
+}
@@ -0,0 +1,31 @@
+package p2p
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	ua := userAgent{BuildData: "SSV-Node:v0.1.0", Operator: "xxx", Commit: "abc1234", ForkID: "v0"}
+	require.Equal(t, "SSV-Node:v0.1.0:xxx:abc1234:v0", ua.String())
+	require.Equal(t, ua, parseUserAgent(ua.String()))
+	require.Equal(t, "v0.1.0", ua.version())
+
+	t.Run("w/o operator", func(t *testing.T) {
+		ua := userAgent{BuildData: "SSV-Node:v0.1.0", Commit: "abc1234", ForkID: "v0"}
+		require.Equal(t, "SSV-Node:v0.1.0::abc1234:v0", ua.String())
+		require.Equal(t, ua, parseUserAgent(ua.String()))
+	})
+
+	t.Run("trailing empty fields", func(t *testing.T) {
+		require.Equal(t, "SSV-Node:v0.1.0:xxx", userAgent{BuildData: "SSV-Node:v0.1.0", Operator: "xxx"}.String())
+		require.Equal(t, "SSV-Node:v0.1.0", userAgent{BuildData: "SSV-Node:v0.1.0"}.String())
+	})
+
+	t.Run("older nodes", func(t *testing.T) {
+		require.Equal(t, userAgent{BuildData: "SSV-Node:v0.0.9", Operator: "xxx"}, parseUserAgent("SSV-Node:v0.0.9:xxx"))
+		require.Equal(t, userAgent{BuildData: "SSV-Node:v0.0.9"}, parseUserAgent("SSV-Node:v0.0.9"))
+		require.Equal(t, userAgent{BuildData: "go-ipfs"}, parseUserAgent("go-ipfs"))
+		require.Equal(t, "", parseUserAgent("go-ipfs").version())
+	})
+}
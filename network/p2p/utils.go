@@ -9,7 +9,8 @@ import (
 	gcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/crypto"
-	"github.com/prysmaticlabs/prysm/io/file"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 )
@@ -32,7 +33,7 @@ func privKey(saveToFile bool) (*ecdsa.PrivateKey, error) {
 		}
 		dst := make([]byte, hex.EncodedLen(len(rawbytes)))
 		hex.Encode(dst, rawbytes)
-		if err := file.WriteFile(defaultKeyPath, dst); err != nil {
+		if err := ioutil.WriteFile(defaultKeyPath, dst, 0600); err != nil {
 			return nil, err
 		}
 	}
@@ -69,8 +70,8 @@ func convertFromInterfacePrivKey(privkey crypto.PrivKey) *ecdsa.PrivateKey {
 
 func defaultDataDir() string {
 	// Try to place the data folder in the user's home dir
-	home := file.HomeDir()
-	if home != "" {
+	home, err := os.UserHomeDir()
+	if err == nil && home != "" {
 		if runtime.GOOS == "darwin" {
 			return filepath.Join(home, "Library", "Eth2")
 		} else if runtime.GOOS == "windows" {
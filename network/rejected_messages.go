@@ -0,0 +1,49 @@
+package network
+
+import (
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsRejectedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:rejected_messages",
+		Help: "Count inbound messages that were rejected by reason",
+	}, []string{"reason"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsRejectedMessages); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// rejectionReasons maps error classes to rejection reasons
+var rejectionReasons = map[string]string{
+	"invalid_signature": "bad_signature",
+	"unknown_validator": "unknown_validator",
+	"wrong_committee":   "wrong_committee",
+	"stale_message":     "stale_seq",
+	"decode_error":      "decode_error",
+	"message_too_large": "oversized",
+}
+
+// RejectionReason returns the reason of rejecting an inbound message because of the given error,
+// errors w/o a known class are considered "invalid"
+func RejectionReason(err error) string {
+	if reason, ok := rejectionReasons[errs.Label(err)]; ok {
+		return reason
+	}
+	return "invalid"
+}
+
+// ReportRejectedMessage counts an inbound message that was rejected because of the given error,
+// all the layers that reject messages (decoding, network validation, consensus pipelines) report here
+func ReportRejectedMessage(err error) {
+	if err == nil {
+		return
+	}
+	metricsRejectedMessages.WithLabelValues(RejectionReason(err)).Inc()
+}
@@ -0,0 +1,30 @@
+package network
+
+import (
+	"github.com/bloxapp/ssv/utils/errs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRejectionReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+	}{
+		{"bad signature", errs.Mark(errors.New("x"), errs.ErrInvalidSignature), "bad_signature"},
+		{"unknown validator", errs.Mark(errors.New("x"), errs.ErrUnknownValidator), "unknown_validator"},
+		{"wrong committee", errors.Wrap(errs.Mark(errors.New("x"), errs.ErrWrongCommittee), "y"), "wrong_committee"},
+		{"stale seq", errs.Mark(errors.New("x"), errs.ErrStaleMessage), "stale_seq"},
+		{"decode error", errs.Mark(errors.New("x"), errs.ErrDecode), "decode_error"},
+		{"oversized", errs.Mark(errors.New("x"), errs.ErrMessageTooLarge), "oversized"},
+		{"unclassified", ValidateMessage(nil), "invalid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.reason, RejectionReason(test.err))
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package network
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// EncodePageToken returns a page token that points to the given sequence
+func EncodePageToken(seq uint64) []byte {
+	token := make([]byte, 8)
+	binary.BigEndian.PutUint64(token, seq)
+	return token
+}
+
+// DecodePageToken returns the sequence that the given page token points to
+func DecodePageToken(token []byte) (uint64, error) {
+	if len(token) != 8 {
+		return 0, errors.New("invalid page token")
+	}
+	return binary.BigEndian.Uint64(token), nil
+}
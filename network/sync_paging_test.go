@@ -0,0 +1,15 @@
+package network
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPageToken(t *testing.T) {
+	seq, err := DecodePageToken(EncodePageToken(1234))
+	require.NoError(t, err)
+	require.EqualValues(t, 1234, seq)
+
+	_, err = DecodePageToken([]byte{1, 2})
+	require.EqualError(t, err, "invalid page token")
+}
@@ -0,0 +1,26 @@
+package network
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// PeersReporter is implemented by networks that can penalize peers which served invalid data
+type PeersReporter interface {
+	// ReportBadPeer penalizes the given peer, the reason is used for logging and metrics
+	ReportBadPeer(peerStr string, reason string)
+}
+
+// SigningRoot returns the root of the sync message that is signed by the responder, the signature is excluded
+func (m *SyncMessage) SigningRoot() ([]byte, error) {
+	cp := *m
+	cp.Signature = nil
+	byts, err := json.Marshal(&cp)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal sync message")
+	}
+	root := sha256.Sum256(byts)
+	return root[:], nil
+}
@@ -0,0 +1,589 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/dkg"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/utils/httpauth"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/bloxapp/ssv/validator"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidatorsController is the interface of the validators controller that is used by admin requests
+type ValidatorsController interface {
+	// ExitValidator triggers voluntary exit of the given validator
+	ExitValidator(pubKey string) error
+	// RemoveValidator stops the given validator and replaces its share with a tombstone of the given reason
+	RemoveValidator(pubKey string, reason string) error
+	// GetTombstones returns the tombstones of the removed validators
+	GetTombstones() ([]*validatorstorage.Tombstone, error)
+	// DeleteTombstone deletes the tombstone of the given validator, so it can be added again
+	DeleteTombstone(pubKey string) error
+	// GetMsgQueueStats returns the stats of the message queue of the given validator
+	GetMsgQueueStats(pubKey string) ([]msgqueue.IndexStats, error)
+	// PauseValidator pauses duties execution of the given validator
+	PauseValidator(pubKey string) error
+	// ResumeValidator resumes duties execution of the given validator
+	ResumeValidator(pubKey string) error
+	// GetValidatorStatus returns the operational status of the given validator
+	GetValidatorStatus(pubKey string) (*validator.Status, error)
+	// RefreshValidatorMetadata fetches the metadata of the given validator from beacon immediately
+	RefreshValidatorMetadata(pubKey string) (*beacon.ValidatorMetadata, error)
+	// GetDeadLetters returns the background tasks that failed after all retries
+	GetDeadLetters() []tasks.DeadLetter
+}
+
+// StorageInspector is the interface of the node storage that is used by admin requests
+type StorageInspector interface {
+	// DiskUsage returns the estimated disk usage of the node storage, broken down by collection
+	DiskUsage() ([]storage.CollectionUsage, error)
+}
+
+// EventsQuarantine is the interface of the quarantine of malformed contract events that is used by admin requests
+type EventsQuarantine interface {
+	// GetQuarantinedEvents returns the quarantined events of the given reason, or all events if reason is empty
+	GetQuarantinedEvents(reason eth1.QuarantineReason) ([]*eth1.QuarantinedEvent, error)
+	// ReplayQuarantinedEvents decodes the quarantined events of the given reason (or all if empty) once again,
+	// returns the number of replayed events
+	ReplayQuarantinedEvents(reason eth1.QuarantineReason) (int, error)
+}
+
+// DKGController is the interface of the dkg controller that is used by admin requests
+type DKGController interface {
+	// StartCeremony initiates a new ceremony with the given operators
+	StartCeremony(id string, operators []dkg.Operator, threshold uint64) error
+	// GetCeremony returns the record of the given ceremony
+	GetCeremony(id string) (*dkg.CeremonyRecord, bool, error)
+}
+
+// defaultAuditLimit is the max number of audited messages in a response, unless a limit was requested
+const defaultAuditLimit = 1000
+
+// Handler handles incoming admin requests
+type Handler interface {
+	// Start starts an http server, listening to admin requests
+	Start(mux *http.ServeMux, addr string) error
+}
+
+// validatorRequest is the body of requests that refer a specific validator (exit, pause, resume, remove)
+type validatorRequest struct {
+	PublicKey string `json:"publicKey"`
+	// Reason is the reason of removal requests
+	Reason string `json:"reason,omitempty"`
+}
+
+// indexStats is the response item of msg queue requests
+type indexStats struct {
+	Index            string `json:"index"`
+	Count            int    `json:"count"`
+	OldestMessageAge string `json:"oldestMessageAge"`
+}
+
+// validatorStatus is the response of validator status requests
+type validatorStatus struct {
+	PublicKey string                `json:"publicKey"`
+	Paused    bool                  `json:"paused"`
+	Committee committeeConnectivity `json:"committee"`
+}
+
+// committeeConnectivity describes the connectivity of the node to the committee peers of a validator
+type committeeConnectivity struct {
+	ConnectedPeers int    `json:"connectedPeers"`
+	RequiredPeers  int    `json:"requiredPeers"`
+	Ready          bool   `json:"ready"`
+	CheckedAt      string `json:"checkedAt,omitempty"`
+}
+
+// validatorMetadata is the response of metadata refresh requests
+type validatorMetadata struct {
+	PublicKey string `json:"publicKey"`
+	Index     uint64 `json:"index"`
+	Status    string `json:"status"`
+	Balance   uint64 `json:"balance"`
+}
+
+// ceremonyRequest is the body of dkg ceremony start requests
+type ceremonyRequest struct {
+	ID        string         `json:"id"`
+	Operators []dkg.Operator `json:"operators"`
+	Threshold uint64         `json:"threshold"`
+}
+
+// quarantineReplay is the response of quarantine replay requests
+type quarantineReplay struct {
+	Replayed int `json:"replayed"`
+}
+
+type adminHandler struct {
+	logger     *zap.Logger
+	token      string
+	validators ValidatorsController
+	storage    StorageInspector
+	quarantine EventsQuarantine
+	auditor    network.MessageAuditor
+	dkg        DKGController
+}
+
+// NewAdminHandler creates a new instance, requests are authenticated with the given bearer token.
+// storage, quarantine, auditor and dkg are optional, the corresponding requests are not available w/o them
+func NewAdminHandler(logger *zap.Logger, token string, validators ValidatorsController, storage StorageInspector,
+	quarantine EventsQuarantine, auditor network.MessageAuditor, dkg DKGController) Handler {
+	return &adminHandler{
+		logger:     logger.With(zap.String("component", "admin/handler")),
+		token:      token,
+		validators: validators,
+		storage:    storage,
+		quarantine: quarantine,
+		auditor:    auditor,
+		dkg:        dkg,
+	}
+}
+
+func (ah *adminHandler) Start(mux *http.ServeMux, addr string) error {
+	if len(ah.token) == 0 {
+		return errors.New("admin api token is required")
+	}
+	ah.logger.Info("setup admin api", zap.String("addr", addr))
+	ah.registerRoutes(mux)
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			ah.logger.Error("failed to start admin http end-point", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// registerRoutes registers the authenticated admin routes on the given mux
+func (ah *adminHandler) registerRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/validators/exit", ah.authenticated(ah.handleExit))
+	mux.HandleFunc("/validators/pause", ah.authenticated(ah.handlePause))
+	mux.HandleFunc("/validators/resume", ah.authenticated(ah.handleResume))
+	mux.HandleFunc("/validators/remove", ah.authenticated(ah.handleRemove))
+	mux.HandleFunc("/validators/tombstones", ah.authenticated(ah.handleTombstones))
+	mux.HandleFunc("/validators/tombstones/delete", ah.authenticated(ah.handleDeleteTombstone))
+	mux.HandleFunc("/validators/status", ah.authenticated(ah.handleStatus))
+	mux.HandleFunc("/validators/metadata/refresh", ah.authenticated(ah.handleRefreshMetadata))
+	mux.HandleFunc("/debug/msgqueue", ah.authenticated(ah.handleMsgQueue))
+	mux.HandleFunc("/debug/dead-letters", ah.authenticated(ah.handleDeadLetters))
+	mux.HandleFunc("/debug/storage", ah.authenticated(ah.handleStorage))
+	mux.HandleFunc("/debug/audit", ah.authenticated(ah.handleAudit))
+	mux.HandleFunc("/eth1/quarantine", ah.authenticated(ah.handleQuarantine))
+	mux.HandleFunc("/eth1/quarantine/replay", ah.authenticated(ah.handleQuarantineReplay))
+	mux.HandleFunc("/dkg/ceremonies", ah.authenticated(ah.handleCeremony))
+	mux.HandleFunc("/dkg/ceremonies/start", ah.authenticated(ah.handleStartCeremony))
+}
+
+// authenticated wraps the given handler with bearer token authentication
+func (ah *adminHandler) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return httpauth.Bearer(ah.logger, ah.token, next)
+}
+
+func (ah *adminHandler) handleExit(res http.ResponseWriter, req *http.Request) {
+	pk, ok := ah.parseValidatorRequest(res, req)
+	if !ok {
+		return
+	}
+	ah.logger.Info("validator exit was requested", zap.String("pubKey", pk))
+	if err := ah.validators.ExitValidator(pk); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ah.writeEmpty(res, http.StatusAccepted)
+}
+
+// handlePause pauses duties execution of the given validator
+func (ah *adminHandler) handlePause(res http.ResponseWriter, req *http.Request) {
+	pk, ok := ah.parseValidatorRequest(res, req)
+	if !ok {
+		return
+	}
+	ah.logger.Info("validator pause was requested", zap.String("pubKey", pk))
+	if err := ah.validators.PauseValidator(pk); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ah.writeEmpty(res, http.StatusOK)
+}
+
+// handleResume resumes duties execution of the given validator
+func (ah *adminHandler) handleResume(res http.ResponseWriter, req *http.Request) {
+	pk, ok := ah.parseValidatorRequest(res, req)
+	if !ok {
+		return
+	}
+	ah.logger.Info("validator resume was requested", zap.String("pubKey", pk))
+	if err := ah.validators.ResumeValidator(pk); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ah.writeEmpty(res, http.StatusOK)
+}
+
+// handleRemove removes the given validator, a tombstone with the (optional) reason is kept instead of its share
+func (ah *adminHandler) handleRemove(res http.ResponseWriter, req *http.Request) {
+	body, ok := ah.decodeValidatorRequest(res, req)
+	if !ok {
+		return
+	}
+	ah.logger.Info("validator removal was requested", zap.String("pubKey", body.PublicKey),
+		zap.String("reason", body.Reason))
+	if err := ah.validators.RemoveValidator(body.PublicKey, body.Reason); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ah.writeEmpty(res, http.StatusOK)
+}
+
+// handleTombstones lists the tombstones of the removed validators
+func (ah *adminHandler) handleTombstones(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	result, err := ah.validators.GetTombstones()
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result == nil {
+		result = []*validatorstorage.Tombstone{}
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleDeleteTombstone deletes the tombstone of the given validator,
+// it is required in order to add a manually removed validator again
+func (ah *adminHandler) handleDeleteTombstone(res http.ResponseWriter, req *http.Request) {
+	pk, ok := ah.parseValidatorRequest(res, req)
+	if !ok {
+		return
+	}
+	ah.logger.Info("validator tombstone deletion was requested", zap.String("pubKey", pk))
+	if err := ah.validators.DeleteTombstone(pk); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ah.writeEmpty(res, http.StatusOK)
+}
+
+// handleStatus returns the status of the validator in the "pubkey" query param
+func (ah *adminHandler) handleStatus(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	pk := strings.TrimPrefix(req.URL.Query().Get("pubkey"), "0x")
+	if len(pk) == 0 {
+		http.Error(res, "missing public key", http.StatusBadRequest)
+		return
+	}
+	status, err := ah.validators.GetValidatorStatus(pk)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusNotFound)
+		return
+	}
+	result := validatorStatus{
+		PublicKey: pk,
+		Paused:    status.Paused,
+		Committee: committeeConnectivity{
+			ConnectedPeers: status.Committee.ConnectedPeers,
+			RequiredPeers:  status.Committee.RequiredPeers,
+			Ready:          status.Committee.Ready(),
+		},
+	}
+	if !status.Committee.CheckedAt.IsZero() {
+		result.Committee.CheckedAt = status.Committee.CheckedAt.UTC().Format(time.RFC3339)
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleRefreshMetadata fetches the metadata of the given validator from beacon, bypassing the update interval
+func (ah *adminHandler) handleRefreshMetadata(res http.ResponseWriter, req *http.Request) {
+	pk, ok := ah.parseValidatorRequest(res, req)
+	if !ok {
+		return
+	}
+	ah.logger.Info("validator metadata refresh was requested", zap.String("pubKey", pk))
+	meta, err := ah.validators.RefreshValidatorMetadata(pk)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result := validatorMetadata{
+		PublicKey: pk,
+		Index:     uint64(meta.Index),
+		Status:    strings.ToLower(meta.Status.String()),
+		Balance:   uint64(meta.Balance),
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// parseValidatorRequest parses the public key of a POST request, an error response is written if the request is invalid
+func (ah *adminHandler) parseValidatorRequest(res http.ResponseWriter, req *http.Request) (string, bool) {
+	body, ok := ah.decodeValidatorRequest(res, req)
+	if !ok {
+		return "", false
+	}
+	return body.PublicKey, true
+}
+
+// decodeValidatorRequest decodes the body of a POST request, an error response is written if the request is invalid
+func (ah *adminHandler) decodeValidatorRequest(res http.ResponseWriter, req *http.Request) (*validatorRequest, bool) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	var body validatorRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(res, "could not parse request", http.StatusBadRequest)
+		return nil, false
+	}
+	body.PublicKey = strings.TrimPrefix(body.PublicKey, "0x")
+	if len(body.PublicKey) == 0 {
+		http.Error(res, "missing public key", http.StatusBadRequest)
+		return nil, false
+	}
+	return &body, true
+}
+
+func (ah *adminHandler) writeEmpty(res http.ResponseWriter, status int) {
+	res.WriteHeader(status)
+	if _, err := fmt.Fprintln(res, ""); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleMsgQueue lists the active indexes in the message queue of the validator in the "pubkey" query param
+func (ah *adminHandler) handleMsgQueue(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	pk := strings.TrimPrefix(req.URL.Query().Get("pubkey"), "0x")
+	if len(pk) == 0 {
+		http.Error(res, "missing public key", http.StatusBadRequest)
+		return
+	}
+	stats, err := ah.validators.GetMsgQueueStats(pk)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusNotFound)
+		return
+	}
+	result := make([]indexStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, indexStats{
+			Index:            s.Index,
+			Count:            s.Count,
+			OldestMessageAge: s.OldestMessageAge.String(),
+		})
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleDeadLetters lists the background tasks (e.g. metadata updates) that failed after all retries
+func (ah *adminHandler) handleDeadLetters(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	result := ah.validators.GetDeadLetters()
+	if result == nil {
+		result = []tasks.DeadLetter{}
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleStorage returns the estimated disk usage of the node storage, broken down by collection
+func (ah *adminHandler) handleStorage(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ah.storage == nil {
+		http.Error(res, "storage is not available", http.StatusNotFound)
+		return
+	}
+	result, err := ah.storage.DiskUsage()
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleQuarantine lists the contract events that were quarantined, the optional "reason" query param
+// filters the events, e.g. "pending-decrypt" lists the validators with shares that couldn't be decrypted
+func (ah *adminHandler) handleQuarantine(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ah.quarantine == nil {
+		http.Error(res, "events quarantine is not available", http.StatusNotFound)
+		return
+	}
+	result, err := ah.quarantine.GetQuarantinedEvents(eth1.QuarantineReason(req.URL.Query().Get("reason")))
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleQuarantineReplay replays the quarantined events (of the optional "reason" query param),
+// e.g. after a decoder fix
+func (ah *adminHandler) handleQuarantineReplay(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ah.quarantine == nil {
+		http.Error(res, "events quarantine is not available", http.StatusNotFound)
+		return
+	}
+	reason := eth1.QuarantineReason(req.URL.Query().Get("reason"))
+	ah.logger.Info("quarantined events replay was requested", zap.String("reason", string(reason)))
+	replayed, err := ah.quarantine.ReplayQuarantinedEvents(reason)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(quarantineReplay{Replayed: replayed}); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleStartCeremony initiates a dkg ceremony with the operators and threshold in the request
+func (ah *adminHandler) handleStartCeremony(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ah.dkg == nil {
+		http.Error(res, "dkg is not available", http.StatusNotFound)
+		return
+	}
+	var body ceremonyRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(res, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	ah.logger.Info("dkg ceremony was requested", zap.String("ceremonyID", body.ID),
+		zap.Int("operators", len(body.Operators)), zap.Uint64("threshold", body.Threshold))
+	if err := ah.dkg.StartCeremony(body.ID, body.Operators, body.Threshold); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ah.writeEmpty(res, http.StatusOK)
+}
+
+// handleCeremony returns the record of the dkg ceremony in the "id" query param
+func (ah *adminHandler) handleCeremony(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ah.dkg == nil {
+		http.Error(res, "dkg is not available", http.StatusNotFound)
+		return
+	}
+	record, found, err := ah.dkg.GetCeremony(req.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(res, "ceremony not found", http.StatusNotFound)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(record); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// handleAudit lists the sampled raw inbound messages, filtered by the optional query params:
+// "peer", "topic", "from" and "to" (RFC3339) and "limit"
+func (ah *adminHandler) handleAudit(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ah.auditor == nil {
+		http.Error(res, "message audit is not available", http.StatusNotFound)
+		return
+	}
+	query := req.URL.Query()
+	filter := network.AuditFilter{
+		PeerID: query.Get("peer"),
+		Topic:  query.Get("topic"),
+		Limit:  defaultAuditLimit,
+	}
+	var err error
+	if from := query.Get("from"); len(from) > 0 {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			http.Error(res, "invalid from", http.StatusBadRequest)
+			return
+		}
+	}
+	if to := query.Get("to"); len(to) > 0 {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			http.Error(res, "invalid to", http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := query.Get("limit"); len(limit) > 0 {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			http.Error(res, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	result, enabled, err := ah.auditor.AuditedMessages(filter)
+	if !enabled {
+		http.Error(res, "message audit is disabled", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		ah.logger.Warn("could not write response", zap.Error(err))
+	}
+}
@@ -0,0 +1,519 @@
+package admin
+
+import (
+	"encoding/json"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/dkg"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/msgqueue"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/bloxapp/ssv/validator"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockValidators struct {
+	exited     []string
+	paused     map[string]bool
+	tombstones []*validatorstorage.Tombstone
+}
+
+func (m *mockValidators) GetMsgQueueStats(pubKey string) ([]msgqueue.IndexStats, error) {
+	if pubKey == "unknown" {
+		return nil, errors.New("validator not found")
+	}
+	return []msgqueue.IndexStats{{Index: "lambda_01020304_seqNumber_1", Count: 2, OldestMessageAge: time.Second}}, nil
+}
+
+func (m *mockValidators) ExitValidator(pubKey string) error {
+	if pubKey == "unknown" {
+		return errors.New("validator not found")
+	}
+	m.exited = append(m.exited, pubKey)
+	return nil
+}
+
+func (m *mockValidators) RemoveValidator(pubKey string, reason string) error {
+	if pubKey == "unknown" {
+		return errors.New("validator not found")
+	}
+	m.tombstones = append(m.tombstones, &validatorstorage.Tombstone{PublicKey: pubKey, Reason: reason,
+		RemovedAt: time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)})
+	return nil
+}
+
+func (m *mockValidators) GetTombstones() ([]*validatorstorage.Tombstone, error) {
+	return m.tombstones, nil
+}
+
+func (m *mockValidators) DeleteTombstone(pubKey string) error {
+	for i, tombstone := range m.tombstones {
+		if tombstone.PublicKey == pubKey {
+			m.tombstones = append(m.tombstones[:i], m.tombstones[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("tombstone not found")
+}
+
+func (m *mockValidators) PauseValidator(pubKey string) error {
+	return m.setPaused(pubKey, true)
+}
+
+func (m *mockValidators) ResumeValidator(pubKey string) error {
+	return m.setPaused(pubKey, false)
+}
+
+func (m *mockValidators) GetValidatorStatus(pubKey string) (*validator.Status, error) {
+	if pubKey == "unknown" {
+		return nil, errors.New("validator not found")
+	}
+	return &validator.Status{
+		Paused: m.paused[pubKey],
+		Committee: validator.CommitteeConnectivity{
+			ConnectedPeers: 1,
+			RequiredPeers:  2,
+			CheckedAt:      time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}, nil
+}
+
+func (m *mockValidators) RefreshValidatorMetadata(pubKey string) (*beacon.ValidatorMetadata, error) {
+	if pubKey == "unknown" {
+		return nil, errors.New("validator not found")
+	}
+	return &beacon.ValidatorMetadata{Balance: 32000000000, Status: v1.ValidatorStateActiveOngoing, Index: 12}, nil
+}
+
+func (m *mockValidators) GetDeadLetters() []tasks.DeadLetter {
+	return []tasks.DeadLetter{{Name: "update metadata batch [0:25]", Error: "task timed out", Attempts: 3,
+		FailedAt: time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)}}
+}
+
+func (m *mockValidators) setPaused(pubKey string, paused bool) error {
+	if pubKey == "unknown" {
+		return errors.New("validator not found")
+	}
+	if m.paused == nil {
+		m.paused = map[string]bool{}
+	}
+	m.paused[pubKey] = paused
+	return nil
+}
+
+func TestAdminHandler_Exit(t *testing.T) {
+	validators := &mockValidators{}
+	ah := NewAdminHandler(zap.L(), "secret", validators, nil, nil, nil, nil).(*adminHandler)
+	handler := ah.authenticated(ah.handleExit)
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		body           string
+		expectedStatus int
+	}{
+		{"valid request", http.MethodPost, "secret", `{"publicKey":"0xabcd"}`, http.StatusAccepted},
+		{"unauthorized", http.MethodPost, "wrong", `{"publicKey":"abcd"}`, http.StatusUnauthorized},
+		{"missing token", http.MethodPost, "", `{"publicKey":"abcd"}`, http.StatusUnauthorized},
+		{"wrong method", http.MethodGet, "secret", ``, http.StatusMethodNotAllowed},
+		{"missing public key", http.MethodPost, "secret", `{}`, http.StatusBadRequest},
+		{"unknown validator", http.MethodPost, "secret", `{"publicKey":"unknown"}`, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/validators/exit", strings.NewReader(test.body))
+			if len(test.token) > 0 {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			require.Equal(t, test.expectedStatus, rec.Code)
+		})
+	}
+	require.Equal(t, []string{"abcd"}, validators.exited)
+}
+
+func TestAdminHandler_PauseResume(t *testing.T) {
+	validators := &mockValidators{}
+	ah := NewAdminHandler(zap.L(), "secret", validators, nil, nil, nil, nil).(*adminHandler)
+
+	send := func(handler http.HandlerFunc, method, body string) int {
+		req := httptest.NewRequest(method, "/validators/pause", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+	pause := ah.authenticated(ah.handlePause)
+	resume := ah.authenticated(ah.handleResume)
+
+	require.Equal(t, http.StatusOK, send(pause, http.MethodPost, `{"publicKey":"0xabcd"}`))
+	require.True(t, validators.paused["abcd"])
+	require.Equal(t, http.StatusOK, send(resume, http.MethodPost, `{"publicKey":"abcd"}`))
+	require.False(t, validators.paused["abcd"])
+
+	require.Equal(t, http.StatusMethodNotAllowed, send(pause, http.MethodGet, ``))
+	require.Equal(t, http.StatusBadRequest, send(pause, http.MethodPost, `{}`))
+	require.Equal(t, http.StatusBadRequest, send(resume, http.MethodPost, `{"publicKey":"unknown"}`))
+}
+
+func TestAdminHandler_RemoveValidator(t *testing.T) {
+	validators := &mockValidators{}
+	ah := NewAdminHandler(zap.L(), "secret", validators, nil, nil, nil, nil).(*adminHandler)
+
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/validators/tombstones", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		ah.authenticated(ah.handleTombstones)(rec, req)
+		return rec
+	}
+	rec := get()
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[]`, rec.Body.String())
+
+	send := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/validators/remove", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		ah.authenticated(ah.handleRemove)(rec, req)
+		return rec.Code
+	}
+	require.Equal(t, http.StatusOK, send(`{"publicKey":"0xabcd","reason":"migrated"}`))
+	require.Equal(t, http.StatusBadRequest, send(`{"publicKey":"unknown"}`))
+	require.Equal(t, http.StatusBadRequest, send(`{}`))
+
+	rec = get()
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"publicKey":"abcd","reason":"migrated","removedAt":"2021-10-01T12:00:00Z"}]`, rec.Body.String())
+
+	deleteTombstone := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/validators/tombstones/delete", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		ah.authenticated(ah.handleDeleteTombstone)(rec, req)
+		return rec.Code
+	}
+	require.Equal(t, http.StatusOK, deleteTombstone(`{"publicKey":"0xabcd"}`))
+	require.Equal(t, http.StatusBadRequest, deleteTombstone(`{"publicKey":"0xabcd"}`))
+
+	rec = get()
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[]`, rec.Body.String())
+}
+
+func TestAdminHandler_MsgQueue(t *testing.T) {
+	ah := NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, nil, nil).(*adminHandler)
+	handler := ah.authenticated(ah.handleMsgQueue)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/msgqueue?pubkey=abcd", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"index":"lambda_01020304_seqNumber_1","count":2,"oldestMessageAge":"1s"}]`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/msgqueue?pubkey=unknown", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/msgqueue?pubkey=abcd", nil)
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAdminHandler_DeadLetters(t *testing.T) {
+	ah := NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, nil, nil).(*adminHandler)
+	handler := ah.authenticated(ah.handleDeadLetters)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/dead-letters", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"name":"update metadata batch [0:25]","error":"task timed out","attempts":3,"failedAt":"2021-10-01T12:00:00Z"}]`,
+		rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/debug/dead-letters", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestAdminHandler_Status(t *testing.T) {
+	validators := &mockValidators{paused: map[string]bool{"abcd": true}}
+	ah := NewAdminHandler(zap.L(), "secret", validators, nil, nil, nil, nil).(*adminHandler)
+	handler := ah.authenticated(ah.handleStatus)
+
+	req := httptest.NewRequest(http.MethodGet, "/validators/status?pubkey=0xabcd", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"publicKey":"abcd","paused":true,"committee":{"connectedPeers":1,"requiredPeers":2,"ready":false,"checkedAt":"2021-10-01T12:00:00Z"}}`,
+		rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/validators/status?pubkey=unknown", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/validators/status?pubkey=abcd", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestAdminHandler_RefreshMetadata(t *testing.T) {
+	ah := NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, nil, nil).(*adminHandler)
+	handler := ah.authenticated(ah.handleRefreshMetadata)
+
+	req := httptest.NewRequest(http.MethodPost, "/validators/metadata/refresh", strings.NewReader(`{"publicKey":"0xabcd"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"publicKey":"abcd","index":12,"status":"active_ongoing","balance":32000000000}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/validators/metadata/refresh", strings.NewReader(`{"publicKey":"unknown"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+type mockStorage struct{}
+
+func (m *mockStorage) DiskUsage() ([]storage.CollectionUsage, error) {
+	return []storage.CollectionUsage{{Name: "shares", SizeBytes: 1024}, {Name: "decided", SizeBytes: 4096}}, nil
+}
+
+func TestAdminHandler_Storage(t *testing.T) {
+	ah := NewAdminHandler(zap.L(), "secret", &mockValidators{}, &mockStorage{}, nil, nil, nil).(*adminHandler)
+	handler := ah.authenticated(ah.handleStorage)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/storage", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"name":"shares","sizeBytes":1024},{"name":"decided","sizeBytes":4096}]`, rec.Body.String())
+
+	// storage is not available
+	ah = NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, nil, nil).(*adminHandler)
+	rec = httptest.NewRecorder()
+	ah.authenticated(ah.handleStorage)(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+type mockQuarantine struct {
+	events []*eth1.QuarantinedEvent
+}
+
+func (m *mockQuarantine) GetQuarantinedEvents(reason eth1.QuarantineReason) ([]*eth1.QuarantinedEvent, error) {
+	var res []*eth1.QuarantinedEvent
+	for _, e := range m.events {
+		if len(reason) == 0 || e.Reason == reason {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockQuarantine) ReplayQuarantinedEvents(reason eth1.QuarantineReason) (int, error) {
+	var remaining []*eth1.QuarantinedEvent
+	for _, e := range m.events {
+		if len(reason) > 0 && e.Reason != reason {
+			remaining = append(remaining, e)
+		}
+	}
+	n := len(m.events) - len(remaining)
+	m.events = remaining
+	return n, nil
+}
+
+func TestAdminHandler_Quarantine(t *testing.T) {
+	quarantine := &mockQuarantine{events: []*eth1.QuarantinedEvent{{
+		Log:           types.Log{Topics: []common.Hash{{}}, BlockNumber: 12},
+		Reason:        eth1.QuarantineMalformed,
+		Name:          "ValidatorAdded",
+		Error:         "invalid ValidatorAdded event: empty oess list",
+		QuarantinedAt: 1633089600,
+	}, {
+		Log:           types.Log{Topics: []common.Hash{{}}, BlockNumber: 14},
+		Reason:        eth1.QuarantinePendingDecrypt,
+		Name:          "ValidatorAdded",
+		Error:         "failed to decrypt share private key",
+		QuarantinedAt: 1633089600,
+		PublicKey:     "0102",
+	}}}
+	ah := NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, quarantine, nil, nil).(*adminHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/eth1/quarantine", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	ah.authenticated(ah.handleQuarantine)(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var events []*eth1.QuarantinedEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	require.Len(t, events, 2)
+
+	req = httptest.NewRequest(http.MethodGet, "/eth1/quarantine?reason=pending-decrypt", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	ah.authenticated(ah.handleQuarantine)(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	require.Equal(t, "0102", events[0].PublicKey)
+	require.EqualValues(t, 14, events[0].Log.BlockNumber)
+
+	req = httptest.NewRequest(http.MethodPost, "/eth1/quarantine/replay?reason=malformed", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	ah.authenticated(ah.handleQuarantineReplay)(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"replayed":1}`, rec.Body.String())
+	require.Len(t, quarantine.events, 1)
+	require.Equal(t, eth1.QuarantinePendingDecrypt, quarantine.events[0].Reason)
+
+	// quarantine is not available
+	ah = NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, nil, nil).(*adminHandler)
+	rec = httptest.NewRecorder()
+	ah.authenticated(ah.handleQuarantineReplay)(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+type mockAuditor struct {
+	enabled bool
+	filter  network.AuditFilter
+}
+
+func (m *mockAuditor) AuditedMessages(filter network.AuditFilter) ([]*network.AuditRecord, bool, error) {
+	m.filter = filter
+	if !m.enabled {
+		return nil, false, nil
+	}
+	return []*network.AuditRecord{{Time: 1637000000000, PeerID: "16Uiu2", Topic: "direct", Data: []byte{1, 2}}}, true, nil
+}
+
+func TestAdminHandler_Audit(t *testing.T) {
+	auditor := &mockAuditor{enabled: true}
+	ah := NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, auditor, nil).(*adminHandler)
+	handler := ah.authenticated(ah.handleAudit)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/audit?peer=16Uiu2&from=2021-11-15T18:00:00Z&limit=10", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"time":1637000000000,"peerId":"16Uiu2","topic":"direct","data":"AQI="}]`, rec.Body.String())
+	require.Equal(t, "16Uiu2", auditor.filter.PeerID)
+	require.Equal(t, 10, auditor.filter.Limit)
+	require.Equal(t, time.Date(2021, 11, 15, 18, 0, 0, 0, time.UTC), auditor.filter.From.UTC())
+	require.True(t, auditor.filter.To.IsZero())
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/audit?to=yesterday", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// auditing is disabled
+	auditor.enabled = false
+	req = httptest.NewRequest(http.MethodGet, "/debug/audit", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, 1000, auditor.filter.Limit)
+}
+
+type mockDKG struct {
+	records map[string]*dkg.CeremonyRecord
+}
+
+func (m *mockDKG) StartCeremony(id string, operators []dkg.Operator, threshold uint64) error {
+	if _, found := m.records[id]; found {
+		return errors.New("ceremony already exist")
+	}
+	m.records[id] = &dkg.CeremonyRecord{ID: id, Operators: operators, Threshold: threshold}
+	return nil
+}
+
+func (m *mockDKG) GetCeremony(id string) (*dkg.CeremonyRecord, bool, error) {
+	record, found := m.records[id]
+	return record, found, nil
+}
+
+func TestAdminHandler_DKG(t *testing.T) {
+	ctrl := &mockDKG{records: map[string]*dkg.CeremonyRecord{}}
+	ah := NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, nil, ctrl).(*adminHandler)
+	mux := http.NewServeMux()
+	ah.registerRoutes(mux)
+
+	do := func(method, target, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if len(token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"id":"c1","operators":[{"id":1,"publicKey":"a"},{"id":2,"publicKey":"b"},{"id":3,"publicKey":"c"},{"id":4,"publicKey":"d"}],"threshold":3}`
+
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/dkg/ceremonies/start", body, "").Code)
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/dkg/ceremonies/start", body, "wrong").Code)
+	require.Len(t, ctrl.records, 0)
+	require.Equal(t, http.StatusMethodNotAllowed, do(http.MethodGet, "/dkg/ceremonies/start", body, "secret").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/dkg/ceremonies/start", "{", "secret").Code)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/dkg/ceremonies/start", body, "secret").Code)
+	require.Len(t, ctrl.records, 1)
+	require.Equal(t, uint64(3), ctrl.records["c1"].Threshold)
+	require.Len(t, ctrl.records["c1"].Operators, 4)
+
+	rec := do(http.MethodPost, "/dkg/ceremonies/start", body, "secret")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "ceremony already exist\n", rec.Body.String())
+
+	rec = do(http.MethodGet, "/dkg/ceremonies?id=c1", "", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var record dkg.CeremonyRecord
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &record))
+	require.Equal(t, "c1", record.ID)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/dkg/ceremonies?id=c2", "", "secret").Code)
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/dkg/ceremonies?id=c1", "", "").Code)
+
+	// dkg is not enabled
+	ah = NewAdminHandler(zap.L(), "secret", &mockValidators{}, nil, nil, nil, nil).(*adminHandler)
+	mux = http.NewServeMux()
+	ah.registerRoutes(mux)
+	require.Equal(t, http.StatusNotFound, do(http.MethodPost, "/dkg/ceremonies/start", body, "secret").Code)
+}
+
+func TestAdminHandler_StartWithoutToken(t *testing.T) {
+	ah := NewAdminHandler(zap.L(), "", &mockValidators{}, nil, nil, nil, nil)
+	require.EqualError(t, ah.Start(http.NewServeMux(), ":0"), "admin api token is required")
+}
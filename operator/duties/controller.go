@@ -5,8 +5,9 @@ import (
 	"encoding/hex"
 	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/operator/failover"
+	"github.com/bloxapp/ssv/utils/blscache"
 	"github.com/bloxapp/ssv/validator"
-	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
 	types "github.com/prysmaticlabs/eth2-types"
 	"github.com/prysmaticlabs/prysm/time/slots"
@@ -33,6 +34,12 @@ type ControllerOptions struct {
 	ValidatorController validator.IController
 	GenesisEpoch        uint64
 	DutyLimit           uint64
+	// SyncDistanceTolerance is the max sync distance (in slots) of the beacon node to execute duties
+	SyncDistanceTolerance uint64
+	// PauseWhileSyncing pauses duties execution (and fetching) while the beacon node is syncing
+	PauseWhileSyncing bool
+	// Failover decides whether this instance is active, duties are executed only by the active instance
+	Failover failover.Coordinator
 }
 
 // dutyController internal implementation of DutyController
@@ -46,6 +53,13 @@ type dutyController struct {
 	validatorController validator.IController
 	genesisEpoch        uint64
 	dutyLimit           uint64
+	// beaconClient is used to check the sync state before executing duties, the check is skipped if nil
+	beaconClient          beacon.Beacon
+	syncDistanceTolerance uint64
+	pauseWhileSyncing     bool
+	paused                bool
+	// failover is used to skip duties while this instance is a standby, the check is skipped if nil
+	failover failover.Coordinator
 }
 
 var secPerSlot int64 = 12
@@ -62,6 +76,11 @@ func NewDutyController(opts *ControllerOptions) DutyController {
 		genesisEpoch:        opts.GenesisEpoch,
 		dutyLimit:           opts.DutyLimit,
 		executor:            nil,
+
+		beaconClient:          opts.BeaconClient,
+		syncDistanceTolerance: opts.SyncDistanceTolerance,
+		pauseWhileSyncing:     opts.PauseWhileSyncing,
+		failover:              opts.Failover,
 	}
 	return &dc
 }
@@ -84,11 +103,16 @@ func (dc *dutyController) ExecuteDuty(duty *beacon.Duty) error {
 		return dc.executor.ExecuteDuty(duty)
 	}
 	logger := dc.loggerWithDutyContext(dc.logger, duty)
-	pubKey := &bls.PublicKey{}
-	if err := pubKey.Deserialize(duty.PubKey[:]); err != nil {
+	pubKey, err := blscache.DeserializePubKey(duty.PubKey[:])
+	if err != nil {
 		return errors.Wrap(err, "failed to deserialize pubkey from duty")
 	}
 	if v, ok := dc.validatorController.GetValidator(pubKey.SerializeToHexStr()); ok {
+		if !v.Share.RoleEnabled(duty.Type) {
+			metricsDutiesRoleDisabled.WithLabelValues(duty.Type.String()).Inc()
+			logger.Debug("duty role is disabled for validator, ignoring duty", zap.String("role", duty.Type.String()))
+			return nil
+		}
 		logger.Info("starting duty processing start for slot")
 		go v.ExecuteDuty(dc.ctx, uint64(duty.Slot), duty)
 	} else {
@@ -101,19 +125,66 @@ func (dc *dutyController) ExecuteDuty(duty *beacon.Duty) error {
 func (dc *dutyController) listenToTicker(slots <-chan types.Slot) {
 	for currentSlot := range slots {
 		dc.logger.Debug("slot ticker", zap.Uint64("slot", uint64(currentSlot)))
+		if dc.failover != nil && !dc.failover.IsActive() {
+			dc.logger.Debug("standby instance, skipping duties", zap.Uint64("slot", uint64(currentSlot)))
+			continue
+		}
+		syncErr := dc.checkBeaconSync()
+		if dc.shouldPause(syncErr) {
+			continue
+		}
 		duties, err := dc.fetcher.GetDuties(uint64(currentSlot))
 		if err != nil {
 			dc.logger.Error("failed to get duties", zap.Error(err))
 		}
 		for i := range duties {
-			go dc.onDuty(&duties[i])
+			go dc.onDuty(&duties[i], syncErr)
+		}
+	}
+}
+
+// checkBeaconSync returns an error if the beacon node is syncing and its sync distance exceeds the tolerance
+func (dc *dutyController) checkBeaconSync() error {
+	if dc.beaconClient == nil {
+		return nil
+	}
+	syncState, err := dc.beaconClient.GetSyncState()
+	if err != nil {
+		// not blocking duties as the sync state is unknown
+		dc.logger.Warn("could not get beacon node sync state", zap.Error(err))
+		return nil
+	}
+	if syncState.IsSyncing && uint64(syncState.SyncDistance) > dc.syncDistanceTolerance {
+		return errors.Errorf("beacon node is not synced: head=%d, distance=%d",
+			syncState.HeadSlot, syncState.SyncDistance)
+	}
+	return nil
+}
+
+// shouldPause returns true if duties should be paused as the beacon node is syncing, logs only on state changes
+func (dc *dutyController) shouldPause(syncErr error) bool {
+	paused := dc.pauseWhileSyncing && syncErr != nil
+	if paused != dc.paused {
+		if paused {
+			dc.logger.Warn("pausing duties while beacon node is syncing", zap.Error(syncErr))
+			metricsDutiesPaused.Set(1)
+		} else {
+			dc.logger.Info("beacon node is synced, resuming duties")
+			metricsDutiesPaused.Set(0)
 		}
+		dc.paused = paused
 	}
+	return paused
 }
 
-// onDuty handles next duty
-func (dc *dutyController) onDuty(duty *beacon.Duty) {
+// onDuty handles next duty, the duty fails fast if the beacon node is not synced
+func (dc *dutyController) onDuty(duty *beacon.Duty, syncErr error) {
 	logger := dc.loggerWithDutyContext(dc.logger, duty)
+	if syncErr != nil {
+		metricsDutiesBeaconNotSynced.WithLabelValues(duty.Type.String()).Inc()
+		logger.Error("could not execute duty", zap.Error(syncErr))
+		return
+	}
 	if dc.shouldExecute(duty) {
 		logger.Debug("duty was sent to execution")
 		if err := dc.ExecuteDuty(duty); err != nil {
@@ -2,9 +2,11 @@ package duties
 
 import (
 	"context"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
+	"github.com/pkg/errors"
 	types "github.com/prysmaticlabs/eth2-types"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -46,6 +48,67 @@ func TestDutyController_ListenToTicker(t *testing.T) {
 	wg.Wait()
 }
 
+func TestDutyController_BeaconSync(t *testing.T) {
+	bc := &syncStateBeacon{state: &v1.SyncState{}}
+	ctrl := &dutyController{
+		logger: zap.L(), ethNetwork: core.PraterNetwork, beaconClient: bc, syncDistanceTolerance: 4,
+	}
+
+	t.Run("synced", func(t *testing.T) {
+		require.NoError(t, ctrl.checkBeaconSync())
+		require.False(t, ctrl.shouldPause(ctrl.checkBeaconSync()))
+	})
+
+	t.Run("syncing within tolerance", func(t *testing.T) {
+		bc.state = &v1.SyncState{IsSyncing: true, SyncDistance: 4}
+		require.NoError(t, ctrl.checkBeaconSync())
+	})
+
+	t.Run("not synced", func(t *testing.T) {
+		bc.state = &v1.SyncState{IsSyncing: true, SyncDistance: 100}
+		syncErr := ctrl.checkBeaconSync()
+		require.Error(t, syncErr)
+		// duties are not paused by default
+		require.False(t, ctrl.shouldPause(syncErr))
+		ctrl.pauseWhileSyncing = true
+		require.True(t, ctrl.shouldPause(syncErr))
+		require.True(t, ctrl.paused)
+	})
+
+	t.Run("resume once synced", func(t *testing.T) {
+		bc.state = &v1.SyncState{}
+		require.False(t, ctrl.shouldPause(ctrl.checkBeaconSync()))
+		require.False(t, ctrl.paused)
+	})
+
+	t.Run("unknown sync state", func(t *testing.T) {
+		bc.err = errors.New("test error")
+		require.NoError(t, ctrl.checkBeaconSync())
+	})
+}
+
+func TestDutyController_ListenToTickerWhileSyncing(t *testing.T) {
+	f := fetcherMock{}
+	var wg sync.WaitGroup
+	bc := &syncStateBeacon{state: &v1.SyncState{IsSyncing: true, SyncDistance: 100}}
+	ctrl := &dutyController{
+		logger: zap.L(), ctx: context.Background(), ethNetwork: core.PraterNetwork,
+		executor: execWithWaitGroup(t, &wg), fetcher: &f, genesisEpoch: 0, dutyLimit: 32,
+		beaconClient: bc, syncDistanceTolerance: 4,
+	}
+	currentSlot := types.Slot(ctrl.getCurrentSlot())
+	f.results = map[types.Slot][]beacon.Duty{
+		currentSlot: {{Slot: spec.Slot(currentSlot), PubKey: spec.BLSPubKey{}}},
+	}
+	cn := make(chan types.Slot)
+	go ctrl.listenToTicker(cn)
+	// duties should fail fast, hence the executor is not called
+	cn <- currentSlot
+	close(cn)
+	time.Sleep(100 * time.Millisecond)
+	wg.Wait()
+}
+
 func TestDutyController_ShouldExecute(t *testing.T) {
 	ctrl := dutyController{logger: zap.L(), ethNetwork: core.NetworkFromString("prater")}
 	currentSlot := uint64(ctrl.getCurrentSlot())
@@ -92,6 +155,16 @@ func execWithWaitGroup(t *testing.T, wg *sync.WaitGroup) dutyExecutor {
 	return &executorMock{t, wg}
 }
 
+type syncStateBeacon struct {
+	beacon.Beacon
+	state *v1.SyncState
+	err   error
+}
+
+func (b *syncStateBeacon) GetSyncState() (*v1.SyncState, error) {
+	return b.state, b.err
+}
+
 type fetcherMock struct {
 	results map[types.Slot][]beacon.Duty
 }
@@ -0,0 +1,34 @@
+package duties
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsDutiesBeaconNotSynced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:duties:beacon_not_synced",
+		Help: "Count of duties that were not executed as the beacon node is not synced",
+	}, []string{"role"})
+	metricsDutiesRoleDisabled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:duties:role_disabled",
+		Help: "Count of duties that were not executed as their role is disabled for the validator",
+	}, []string{"role"})
+	metricsDutiesPaused = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:duties:paused",
+		Help: "Indicates whether duties execution is paused while the beacon node is syncing",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsDutiesBeaconNotSynced); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsDutiesRoleDisabled); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsDutiesPaused); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
@@ -0,0 +1,325 @@
+package failover
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+	"sync"
+	"time"
+)
+
+// Mode is the failover mode of the instance
+type Mode string
+
+const (
+	// ModeDisabled is the default mode, where the instance is always active
+	ModeDisabled Mode = ""
+	// ModePrimary is the mode of the preferred instance, it becomes active if no other instance is active
+	ModePrimary Mode = "primary"
+	// ModeStandby is the mode of a hot spare, it becomes active only once the active instance stopped sending heartbeats
+	ModeStandby Mode = "standby"
+)
+
+var (
+	metricsFailoverActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:failover:active",
+		Help: "Indicates whether this instance is the active one (1) or a standby (0)",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsFailoverActive); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// Options holds the needed dependencies for the failover coordinator
+type Options struct {
+	Context                    context.Context
+	Logger                     *zap.Logger
+	Network                    network.Failover
+	ShareEncryptionKeyProvider eth1.ShareEncryptionKeyProvider
+
+	Mode              Mode          `yaml:"Mode" env:"FAILOVER_MODE" env-description:"Failover mode of redundant instances that share the same keys: primary, standby or empty (disabled)"`
+	InstanceID        string        `yaml:"InstanceID" env:"FAILOVER_INSTANCE_ID" env-description:"Unique id of this instance, random if not provided"`
+	HeartbeatInterval time.Duration `yaml:"HeartbeatInterval" env:"FAILOVER_HEARTBEAT_INTERVAL" env-default:"2s" env-description:"Interval of heartbeats sent by the active instance"`
+	Timeout           time.Duration `yaml:"Timeout" env:"FAILOVER_TIMEOUT" env-default:"30s" env-description:"Time w/o heartbeats after which a standby instance becomes active"`
+}
+
+// Coordinator decides whether this instance is the active one that should sign, or a standby
+type Coordinator interface {
+	// Start starts to exchange heartbeats
+	Start() error
+	// IsActive returns true if this instance should execute duties
+	IsActive() bool
+}
+
+// NewCoordinator creates a new coordinator, returns a coordinator that is always active if failover is disabled
+func NewCoordinator(opts Options) Coordinator {
+	if opts.Mode == ModeDisabled {
+		return &alwaysActive{}
+	}
+	instanceID := opts.InstanceID
+	if len(instanceID) == 0 {
+		instanceID = randomInstanceID()
+	}
+	interval := opts.HeartbeatInterval
+	if interval == 0 {
+		interval = 2 * time.Second
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	return &coordinator{
+		ctx: opts.Context,
+		logger: opts.Logger.With(zap.String("component", "failover/coordinator"),
+			zap.String("mode", string(opts.Mode)), zap.String("instanceID", instanceID)),
+		network:     opts.Network,
+		keyProvider: opts.ShareEncryptionKeyProvider,
+		mode:        opts.Mode,
+		instanceID:  instanceID,
+		interval:    interval,
+		timeout:     timeout,
+		lock:        sync.RWMutex{},
+		now:         time.Now,
+	}
+}
+
+type coordinator struct {
+	ctx         context.Context
+	logger      *zap.Logger
+	network     network.Failover
+	keyProvider eth1.ShareEncryptionKeyProvider
+
+	mode       Mode
+	instanceID string
+	interval   time.Duration
+	timeout    time.Duration
+
+	lock    sync.RWMutex
+	active  bool
+	started time.Time
+	// lastSeen is the time of the last valid heartbeat of another active instance
+	lastSeen time.Time
+	// lastPeer is the id of the instance that sent the last valid heartbeat
+	lastPeer string
+
+	now func() time.Time
+}
+
+// Start starts to exchange heartbeats
+func (c *coordinator) Start() error {
+	sk, err := c.operatorKey()
+	if err != nil {
+		return err
+	}
+	self, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		return errors.Wrap(err, "could not extract operator public key")
+	}
+	cn := c.network.ReceivedHeartbeatChan()
+	if err := c.network.SubscribeToFailoverTopic(); err != nil {
+		return errors.Wrap(err, "could not subscribe to failover topic")
+	}
+	c.lock.Lock()
+	c.started = c.now()
+	c.lock.Unlock()
+
+	go func() {
+		for hb := range cn {
+			if err := c.handleHeartbeat(hb, self, &sk.PublicKey); err != nil {
+				c.logger.Debug("could not handle heartbeat", zap.String("sender", hb.InstanceID), zap.Error(err))
+			}
+		}
+	}()
+	go c.run(sk, self)
+
+	c.logger.Info("failover coordinator started, waiting for heartbeats before becoming active")
+	return nil
+}
+
+// IsActive returns true if this instance should execute duties
+func (c *coordinator) IsActive() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.active
+}
+
+// run checks the state and broadcasts heartbeats while active
+func (c *coordinator) run(sk *rsa.PrivateKey, self string) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !c.tick() {
+			continue
+		}
+		hb, err := newHeartbeat(sk, self, c.instanceID, c.mode == ModePrimary, c.now())
+		if err != nil {
+			c.logger.Warn("could not create heartbeat", zap.Error(err))
+			continue
+		}
+		if err := c.network.BroadcastHeartbeat(hb); err != nil {
+			c.logger.Warn("could not broadcast heartbeat", zap.Error(err))
+		}
+	}
+}
+
+// tick promotes this instance if the active instance is gone, returns true if this instance is active
+func (c *coordinator) tick() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.active {
+		return true
+	}
+	now := c.now()
+	// a standby waits longer so a primary that starts at the same time takes precedence
+	timeout := c.timeout
+	if c.mode == ModeStandby {
+		timeout *= 2
+	}
+	if now.Sub(c.started) < timeout || now.Sub(c.lastSeen) < timeout {
+		return false
+	}
+	c.setActiveNotSafe(true)
+	c.logger.Warn("no heartbeats from an active instance, becoming active",
+		zap.String("lastActive", c.lastPeer), zap.Time("lastSeen", c.lastSeen))
+	return true
+}
+
+// handleHeartbeat tracks heartbeats of other instances of this operator, steps down if another instance should be active
+func (c *coordinator) handleHeartbeat(hb *network.FailoverHeartbeat, self string, pk *rsa.PublicKey) error {
+	if hb == nil || hb.Sender != self || hb.InstanceID == c.instanceID {
+		// heartbeats of other operators or own heartbeats
+		return nil
+	}
+	if err := verifyHeartbeat(hb, pk); err != nil {
+		return err
+	}
+	sent := time.Unix(0, hb.Timestamp*int64(time.Millisecond))
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.now().Sub(sent) > c.timeout {
+		return errors.New("stale heartbeat")
+	}
+	c.lastSeen = c.now()
+	c.lastPeer = hb.InstanceID
+	if c.active && c.shouldYieldNotSafe(hb) {
+		c.setActiveNotSafe(false)
+		c.logger.Warn("another instance is active, stepping down", zap.String("other", hb.InstanceID))
+	}
+	return nil
+}
+
+// shouldYieldNotSafe decides which of two active instances remains active,
+// a primary takes precedence over a standby, otherwise the lower instance id wins
+func (c *coordinator) shouldYieldNotSafe(hb *network.FailoverHeartbeat) bool {
+	primary := c.mode == ModePrimary
+	if primary != hb.Primary {
+		return hb.Primary
+	}
+	return hb.InstanceID < c.instanceID
+}
+
+func (c *coordinator) setActiveNotSafe(active bool) {
+	c.active = active
+	if active {
+		metricsFailoverActive.Set(1)
+	} else {
+		metricsFailoverActive.Set(0)
+	}
+}
+
+func (c *coordinator) operatorKey() (*rsa.PrivateKey, error) {
+	sk, found, err := c.keyProvider()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get operator private key")
+	}
+	if !found {
+		return nil, errors.New("operator private key not found")
+	}
+	return sk, nil
+}
+
+// newHeartbeat creates a signed heartbeat
+func newHeartbeat(sk *rsa.PrivateKey, sender, instanceID string, primary bool, ts time.Time) (*network.FailoverHeartbeat, error) {
+	hb := &network.FailoverHeartbeat{
+		Sender:     sender,
+		InstanceID: instanceID,
+		Primary:    primary,
+		Timestamp:  ts.UnixNano() / int64(time.Millisecond),
+	}
+	root, err := signingRoot(hb)
+	if err != nil {
+		return nil, err
+	}
+	hb.Signature, err = rsaencryption.SignData(sk, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign heartbeat")
+	}
+	return hb, nil
+}
+
+// verifyHeartbeat verifies the signature of the given heartbeat against the operator key
+func verifyHeartbeat(hb *network.FailoverHeartbeat, pk *rsa.PublicKey) error {
+	if len(hb.Signature) == 0 {
+		return errors.New("missing signature")
+	}
+	root, err := signingRoot(hb)
+	if err != nil {
+		return err
+	}
+	if err := rsaencryption.VerifySignedData(pk, root, hb.Signature); err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	return nil
+}
+
+// signingRoot returns the bytes that are signed by the sender, i.e. the heartbeat w/o signature
+func signingRoot(hb *network.FailoverHeartbeat) ([]byte, error) {
+	toSign := *hb
+	toSign.Signature = nil
+	data, err := json.Marshal(&toSign)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal heartbeat")
+	}
+	return data, nil
+}
+
+func randomInstanceID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// alwaysActive is used when failover is disabled
+type alwaysActive struct{}
+
+// Start does nothing
+func (a *alwaysActive) Start() error {
+	return nil
+}
+
+// IsActive always returns true
+func (a *alwaysActive) IsActive() bool {
+	return true
+}
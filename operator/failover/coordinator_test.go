@@ -0,0 +1,106 @@
+package failover
+
+import (
+	"context"
+	"crypto/rsa"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newTestCoordinator(t *testing.T, mode Mode, instanceID string, now *time.Time) *coordinator {
+	c := NewCoordinator(Options{
+		Context:    context.Background(),
+		Logger:     zap.L(),
+		Mode:       mode,
+		InstanceID: instanceID,
+		Timeout:    10 * time.Second,
+	}).(*coordinator)
+	c.now = func() time.Time { return *now }
+	c.started = *now
+	return c
+}
+
+func newTestKey(t *testing.T) (*rsa.PrivateKey, string) {
+	_, skPem, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+	require.NoError(t, err)
+	self, err := rsaencryption.ExtractPublicKey(sk)
+	require.NoError(t, err)
+	return sk, self
+}
+
+func TestCoordinator_Disabled(t *testing.T) {
+	c := NewCoordinator(Options{Mode: ModeDisabled})
+	require.NoError(t, c.Start())
+	require.True(t, c.IsActive())
+}
+
+func TestCoordinator_Failover(t *testing.T) {
+	sk, self := newTestKey(t)
+	now := time.Now()
+	primary := newTestCoordinator(t, ModePrimary, "a", &now)
+	standby := newTestCoordinator(t, ModeStandby, "b", &now)
+
+	t.Run("wait for heartbeats on start", func(t *testing.T) {
+		now = now.Add(5 * time.Second)
+		require.False(t, primary.tick())
+		require.False(t, standby.tick())
+	})
+
+	t.Run("primary becomes active first", func(t *testing.T) {
+		now = now.Add(5 * time.Second)
+		require.True(t, primary.tick())
+		require.True(t, primary.IsActive())
+
+		hb, err := newHeartbeat(sk, self, primary.instanceID, true, now)
+		require.NoError(t, err)
+		require.NoError(t, standby.handleHeartbeat(hb, self, &sk.PublicKey))
+		now = now.Add(15 * time.Second)
+		require.False(t, standby.tick())
+		require.False(t, standby.IsActive())
+	})
+
+	t.Run("standby takes over once heartbeats stop", func(t *testing.T) {
+		now = now.Add(10 * time.Second)
+		require.True(t, standby.tick())
+		require.True(t, standby.IsActive())
+	})
+
+	t.Run("standby steps down for an active primary", func(t *testing.T) {
+		hb, err := newHeartbeat(sk, self, primary.instanceID, true, now)
+		require.NoError(t, err)
+		require.NoError(t, standby.handleHeartbeat(hb, self, &sk.PublicKey))
+		require.False(t, standby.IsActive())
+
+		// the primary keeps its state upon a heartbeat of the standby
+		hb, err = newHeartbeat(sk, self, standby.instanceID, false, now)
+		require.NoError(t, err)
+		require.NoError(t, primary.handleHeartbeat(hb, self, &sk.PublicKey))
+		require.True(t, primary.IsActive())
+	})
+
+	t.Run("ignore invalid heartbeats", func(t *testing.T) {
+		other, otherPk := newTestKey(t)
+		standby.lastSeen = time.Time{}
+
+		// heartbeat of another operator
+		hb, err := newHeartbeat(other, otherPk, "c", true, now)
+		require.NoError(t, err)
+		require.NoError(t, standby.handleHeartbeat(hb, self, &sk.PublicKey))
+		require.True(t, standby.lastSeen.IsZero())
+
+		// forged heartbeat
+		hb.Sender = self
+		require.Error(t, standby.handleHeartbeat(hb, self, &sk.PublicKey))
+
+		// stale heartbeat
+		hb, err = newHeartbeat(sk, self, "c", true, now.Add(-time.Minute))
+		require.NoError(t, err)
+		require.Error(t, standby.handleHeartbeat(hb, self, &sk.PublicKey))
+		require.True(t, standby.lastSeen.IsZero())
+	})
+}
@@ -0,0 +1,143 @@
+package heartbeat
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Options holds the needed dependencies for the heartbeat publisher
+type Options struct {
+	Context                    context.Context
+	Logger                     *zap.Logger
+	Network                    network.OperatorHeartbeats
+	ShareEncryptionKeyProvider eth1.ShareEncryptionKeyProvider
+	// Version and Commit are the build information that is announced in heartbeats
+	Version string
+	Commit  string
+	// ForkID returns the current network fork, optional
+	ForkID func() string
+	// Resources returns the last resource usage sample that is reported in heartbeats, optional
+	Resources func() *network.ResourceUsage
+
+	Interval time.Duration `yaml:"Interval" env:"OPERATOR_HEARTBEAT_INTERVAL" env-default:"1m" env-description:"Interval of operator heartbeats on the main topic, disabled if zero"`
+}
+
+// Publisher broadcasts signed heartbeats of the operator on the main topic
+type Publisher struct {
+	ctx         context.Context
+	logger      *zap.Logger
+	network     network.OperatorHeartbeats
+	keyProvider eth1.ShareEncryptionKeyProvider
+	version     string
+	commit      string
+	forkID      func() string
+	resources   func() *network.ResourceUsage
+	interval    time.Duration
+}
+
+// NewPublisher creates a new heartbeat publisher
+func NewPublisher(opts Options) *Publisher {
+	return &Publisher{
+		ctx:         opts.Context,
+		logger:      opts.Logger.With(zap.String("component", "operator/heartbeat")),
+		network:     opts.Network,
+		keyProvider: opts.ShareEncryptionKeyProvider,
+		version:     opts.Version,
+		commit:      opts.Commit,
+		forkID:      opts.ForkID,
+		resources:   opts.Resources,
+		interval:    opts.Interval,
+	}
+}
+
+// Start starts to broadcast heartbeats, does nothing if the interval is zero
+func (p *Publisher) Start() error {
+	if p.interval <= 0 {
+		p.logger.Info("operator heartbeats are disabled")
+		return nil
+	}
+	sk, found, err := p.keyProvider()
+	if err != nil {
+		return errors.Wrap(err, "could not get operator private key")
+	}
+	if !found {
+		return errors.New("operator private key not found")
+	}
+	pk, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		return errors.Wrap(err, "could not extract operator public key")
+	}
+	tasks.RunEvery(p.ctx, p.interval, func() {
+		hb := &network.OperatorHeartbeat{
+			OperatorPubKey: pk,
+			Version:        p.version,
+			Commit:         p.commit,
+			Timestamp:      time.Now().UnixNano() / int64(time.Millisecond),
+		}
+		if p.forkID != nil {
+			hb.ForkID = p.forkID()
+		}
+		if p.resources != nil {
+			hb.Resources = p.resources()
+		}
+		if err := Sign(sk, hb); err != nil {
+			p.logger.Warn("could not sign heartbeat", zap.Error(err))
+			return
+		}
+		if err := p.network.BroadcastOperatorHeartbeat(hb); err != nil {
+			p.logger.Debug("could not broadcast heartbeat", zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// Sign signs the given heartbeat with the operator key
+func Sign(sk *rsa.PrivateKey, hb *network.OperatorHeartbeat) error {
+	root, err := signingRoot(hb)
+	if err != nil {
+		return err
+	}
+	hb.Signature, err = rsaencryption.SignData(sk, root)
+	if err != nil {
+		return errors.Wrap(err, "could not sign heartbeat")
+	}
+	return nil
+}
+
+// Verify verifies the signature of the given heartbeat against the public key of the sender
+func Verify(hb *network.OperatorHeartbeat) error {
+	if len(hb.Signature) == 0 {
+		return errors.New("missing signature")
+	}
+	pk, err := rsaencryption.ConvertEncodedPemToPublicKey(hb.OperatorPubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not decode operator public key")
+	}
+	root, err := signingRoot(hb)
+	if err != nil {
+		return err
+	}
+	if err := rsaencryption.VerifySignedData(pk, root, hb.Signature); err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	return nil
+}
+
+// signingRoot returns the bytes that are signed by the sender, i.e. the heartbeat w/o signature
+func signingRoot(hb *network.OperatorHeartbeat) ([]byte, error) {
+	toSign := *hb
+	toSign.Signature = nil
+	data, err := json.Marshal(&toSign)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal heartbeat")
+	}
+	return data, nil
+}
@@ -0,0 +1,63 @@
+package heartbeat
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	_, skPem, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+	require.NoError(t, err)
+	pk, err := rsaencryption.ExtractPublicKey(sk)
+	require.NoError(t, err)
+
+	hb := &network.OperatorHeartbeat{
+		OperatorPubKey: pk,
+		Version:        "SSV-Node:v0.1.0",
+		Commit:         "abc1234",
+		ForkID:         "v0",
+		Timestamp:      time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	require.NoError(t, Sign(sk, hb))
+	require.NoError(t, Verify(hb))
+
+	t.Run("tampered heartbeat", func(t *testing.T) {
+		tampered := *hb
+		tampered.Version = "SSV-Node:v0.2.0"
+		require.EqualError(t, Verify(&tampered), "invalid signature: crypto/rsa: verification error")
+	})
+
+	t.Run("tampered fork", func(t *testing.T) {
+		tampered := *hb
+		tampered.ForkID = "v1"
+		require.Error(t, Verify(&tampered))
+	})
+
+	t.Run("tampered resources", func(t *testing.T) {
+		withResources := *hb
+		withResources.Resources = &network.ResourceUsage{CPUPercent: 12.5, HeapBytes: 1 << 20, Goroutines: 100}
+		require.NoError(t, Sign(sk, &withResources))
+		require.NoError(t, Verify(&withResources))
+
+		tampered := withResources
+		tampered.Resources = &network.ResourceUsage{CPUPercent: 1, HeapBytes: 1 << 20, Goroutines: 100}
+		require.Error(t, Verify(&tampered))
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		unsigned := *hb
+		unsigned.Signature = nil
+		require.EqualError(t, Verify(&unsigned), "missing signature")
+	})
+
+	t.Run("bad public key", func(t *testing.T) {
+		other := *hb
+		other.OperatorPubKey = "xxx"
+		require.Error(t, Verify(&other))
+	})
+}
@@ -8,11 +8,13 @@ import (
 	"github.com/bloxapp/ssv/monitoring/metrics"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/operator/duties"
+	"github.com/bloxapp/ssv/operator/failover"
 	"github.com/bloxapp/ssv/operator/forks"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/utils/tasks"
 	"github.com/bloxapp/ssv/validator"
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/async"
 	"go.uber.org/zap"
 )
 
@@ -38,6 +40,12 @@ type Options struct {
 	DutyLimit        uint64                      `yaml:"DutyLimit" env:"DUTY_LIMIT" env-default:"32" env-description:"max slots to wait for duty to start"`
 	ValidatorOptions validator.ControllerOptions `yaml:"ValidatorOptions"`
 	Fork             forks.Fork
+
+	// beacon node sync state gating of duties
+	SyncDistanceTolerance   uint64 `yaml:"SyncDistanceTolerance" env:"SYNC_DISTANCE_TOLERANCE" env-default:"4" env-description:"max sync distance (slots) of beacon node to execute duties"`
+	PauseDutiesWhileSyncing bool   `yaml:"PauseDutiesWhileSyncing" env:"PAUSE_DUTIES_WHILE_SYNCING" env-default:"false" env-description:"pause duties while beacon node is syncing"`
+	// Failover is optional, used to run redundant instances where only the active one executes duties
+	Failover failover.Coordinator
 }
 
 // operatorNode implements Node interface
@@ -49,6 +57,7 @@ type operatorNode struct {
 	beacon         beacon.Beacon
 	net            network.Network
 	storage        Storage
+	db             basedb.IDb
 	eth1Client     eth1.Client
 	dutyCtrl       duties.DutyController
 	fork           forks.Fork
@@ -65,6 +74,7 @@ func New(opts Options) Node {
 		net:            opts.Network,
 		eth1Client:     opts.Eth1Client,
 		storage:        NewOperatorNodeStorage(opts.DB, opts.Logger),
+		db:             opts.DB,
 
 		dutyCtrl: duties.NewDutyController(&duties.ControllerOptions{
 			Logger:              opts.Logger,
@@ -74,6 +84,10 @@ func New(opts Options) Node {
 			ValidatorController: opts.ValidatorController,
 			GenesisEpoch:        opts.GenesisEpoch,
 			DutyLimit:           opts.DutyLimit,
+
+			SyncDistanceTolerance: opts.SyncDistanceTolerance,
+			PauseWhileSyncing:     opts.PauseDutiesWhileSyncing,
+			Failover:              opts.Failover,
 		}),
 
 		fork: opts.Fork,
@@ -103,6 +117,13 @@ func (n *operatorNode) Start() error {
 		n.logger.Error("failed to subscribe to main topic", zap.Error(err))
 	}
 	go n.validatorsCtrl.UpdateValidatorMetaDataLoop()
+	go n.validatorsCtrl.CommitteeConnectivityLoop()
+	go n.validatorsCtrl.SubscriptionsReconcileLoop()
+	go n.validatorsCtrl.ActivationWatcherLoop()
+	go n.validatorsCtrl.AttestationInclusionLoop()
+	if n.context != nil {
+		async.RunEvery(n.context, diskUsageInterval, n.reportDiskUsage)
+	}
 	n.dutyCtrl.Start()
 
 	return nil
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package resources
+
+import (
+	"github.com/pkg/errors"
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user and system cpu time of the process
+func processCPUTime() (time.Duration, error) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, errors.Wrap(err, "could not get rusage")
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano()), nil
+}
@@ -0,0 +1,11 @@
+package resources
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+// processCPUTime is not supported on windows
+func processCPUTime() (time.Duration, error) {
+	return 0, errors.New("not supported")
+}
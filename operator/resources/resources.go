@@ -0,0 +1,155 @@
+package resources
+
+import (
+	"context"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/tasks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"log"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var (
+	metricsCPUPercent = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:resources:cpu_percent",
+		Help: "CPU usage of the node since the previous sample (100 is a single fully used core)",
+	})
+	metricsHeapBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:resources:heap_bytes",
+		Help: "Size of the allocated heap objects",
+	})
+	metricsSysBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:resources:sys_bytes",
+		Help: "Total memory that was obtained from the OS",
+	})
+	metricsGoroutines = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:resources:goroutines",
+		Help: "Number of goroutines",
+	})
+	metricsDBSizeBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:resources:db_size_bytes",
+		Help: "Size of the db on disk",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsCPUPercent); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsHeapBytes); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsSysBytes); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsGoroutines); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsDBSizeBytes); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// Options holds the needed dependencies for resource usage reporting
+type Options struct {
+	Context context.Context
+	Logger  *zap.Logger
+	// DBSize returns the size of the db on disk, optional
+	DBSize func() (int64, error)
+
+	Enabled  bool          `yaml:"Enabled" env:"RESOURCE_REPORTING" env-default:"false" env-description:"A boolean flag to sample cpu, memory, goroutines and db size, and report them in metrics and operator heartbeats"`
+	Interval time.Duration `yaml:"Interval" env:"RESOURCE_REPORTING_INTERVAL" env-default:"30s" env-description:"Interval of resource usage sampling"`
+}
+
+// Sampler samples the resource usage of the node periodically
+type Sampler struct {
+	ctx      context.Context
+	logger   *zap.Logger
+	dbSize   func() (int64, error)
+	interval time.Duration
+
+	lock sync.RWMutex
+	last *network.ResourceUsage
+	// prevCPU and prevTime are the cpu time and the wall time of the previous sample
+	prevCPU  time.Duration
+	prevTime time.Time
+}
+
+// NewSampler creates a new sampler
+func NewSampler(opts Options) *Sampler {
+	return &Sampler{
+		ctx:      opts.Context,
+		logger:   opts.Logger.With(zap.String("component", "operator/resources")),
+		dbSize:   opts.DBSize,
+		interval: opts.Interval,
+	}
+}
+
+// Start samples the resource usage every interval
+func (s *Sampler) Start() {
+	s.Sample()
+	tasks.RunEvery(s.ctx, s.interval, func() {
+		s.Sample()
+	})
+}
+
+// Last returns the last sample, nil if no sample was taken
+func (s *Sampler) Last() *network.ResourceUsage {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.last == nil {
+		return nil
+	}
+	res := *s.last
+	return &res
+}
+
+// Sample samples the current resource usage and updates the metrics
+func (s *Sampler) Sample() *network.ResourceUsage {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	usage := &network.ResourceUsage{
+		HeapBytes:  mem.HeapAlloc,
+		SysBytes:   mem.Sys,
+		Goroutines: runtime.NumGoroutine(),
+	}
+	if s.dbSize != nil {
+		size, err := s.dbSize()
+		if err != nil {
+			s.logger.Debug("could not get db size", zap.Error(err))
+		} else {
+			usage.DBSizeBytes = size
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	cpu, err := processCPUTime()
+	if err != nil {
+		s.logger.Debug("could not get cpu time", zap.Error(err))
+	} else {
+		if !s.prevTime.IsZero() {
+			if elapsed := now.Sub(s.prevTime); elapsed > 0 {
+				usage.CPUPercent = float64(cpu-s.prevCPU) / float64(elapsed) * 100
+			}
+		}
+		s.prevCPU, s.prevTime = cpu, now
+	}
+	s.last = usage
+
+	metricsCPUPercent.Set(usage.CPUPercent)
+	metricsHeapBytes.Set(float64(usage.HeapBytes))
+	metricsSysBytes.Set(float64(usage.SysBytes))
+	metricsGoroutines.Set(float64(usage.Goroutines))
+	metricsDBSizeBytes.Set(float64(usage.DBSizeBytes))
+
+	res := *usage
+	return &res
+}
@@ -0,0 +1,36 @@
+package resources
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSampler_Sample(t *testing.T) {
+	s := NewSampler(Options{
+		Context:  context.Background(),
+		Logger:   zap.L(),
+		DBSize:   func() (int64, error) { return 1024, nil },
+		Interval: time.Second,
+	})
+	require.Nil(t, s.Last())
+
+	first := s.Sample()
+	require.NotNil(t, first)
+	require.Greater(t, first.HeapBytes, uint64(0))
+	require.Greater(t, first.SysBytes, uint64(0))
+	require.Greater(t, first.Goroutines, 0)
+	require.EqualValues(t, 1024, first.DBSizeBytes)
+	// no previous sample to compare with
+	require.Zero(t, first.CPUPercent)
+	require.Equal(t, first, s.Last())
+
+	// burn some cpu
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for time.Now().Before(deadline) {
+	}
+	second := s.Sample()
+	require.Greater(t, second.CPUPercent, float64(0))
+}
@@ -14,24 +14,33 @@ import (
 var (
 	prefix        = []byte("operator-")
 	syncOffsetKey = []byte("syncOffset")
+	// quarantinePrefix is under the operator prefix, so quarantined events are part of the registry data
+	quarantinePrefix = []byte("operator-eth1-quarantine-")
 )
 
 // Storage represents the interface for ssv node storage
 type Storage interface {
 	eth1.SyncOffsetStorage
+	eth1.QuarantineStorage
 
 	GetPrivateKey() (*rsa.PrivateKey, bool, error)
 	SetupPrivateKey(operatorKey string) error
 }
 
 type storage struct {
+	eth1.QuarantineStorage
+
 	db     basedb.IDb
 	logger *zap.Logger
 }
 
 // NewOperatorNodeStorage creates a new instance of Storage
 func NewOperatorNodeStorage(db basedb.IDb, logger *zap.Logger) Storage {
-	es := storage{db, logger}
+	es := storage{
+		QuarantineStorage: eth1.NewQuarantineStorage(db, quarantinePrefix),
+		db:                db,
+		logger:            logger,
+	}
 	return &es
 }
 
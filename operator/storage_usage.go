@@ -0,0 +1,38 @@
+package operator
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	ssvstorage "github.com/bloxapp/ssv/storage"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"go.uber.org/zap"
+	"time"
+)
+
+// diskUsageInterval is the interval of disk usage reporting
+const diskUsageInterval = 10 * time.Minute
+
+// storageCollections returns the collections of the operator node storage
+func storageCollections() []ssvstorage.Collection {
+	return []ssvstorage.Collection{
+		{Name: "shares", Prefixes: validatorstorage.CollectionPrefixes()},
+		{Name: "decided", Prefixes: [][]byte{
+			// ibft storage is namespaced by role
+			[]byte(beacon.RoleTypeAttester.String()),
+			[]byte(beacon.RoleTypeAggregator.String()),
+			[]byte(beacon.RoleTypeProposer.String()),
+		}},
+		{Name: "registry", Prefixes: [][]byte{prefix}},
+	}
+}
+
+// DiskUsage returns the estimated disk usage of the node storage, broken down by collection
+func (n *operatorNode) DiskUsage() ([]ssvstorage.CollectionUsage, error) {
+	return ssvstorage.DiskUsage(n.db, storageCollections())
+}
+
+// reportDiskUsage updates the disk usage metrics
+func (n *operatorNode) reportDiskUsage() {
+	if _, err := n.DiskUsage(); err != nil {
+		n.logger.Warn("could not report disk usage", zap.Error(err))
+	}
+}
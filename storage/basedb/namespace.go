@@ -0,0 +1,20 @@
+package basedb
+
+// namespaceSeparator separates the parts of a namespace
+const namespaceSeparator = '/'
+
+// Namespace returns the prefix of a namespace that is composed of the given parts,
+// the parts are separated so the prefix of a namespace never matches keys of a sibling namespace.
+// objects under a namespace can be dropped at once with RemoveAllByCollection
+func Namespace(parts ...[]byte) []byte {
+	size := 0
+	for _, p := range parts {
+		size += len(p) + 1
+	}
+	ns := make([]byte, 0, size)
+	for _, p := range parts {
+		ns = append(ns, p...)
+		ns = append(ns, namespaceSeparator)
+	}
+	return ns
+}
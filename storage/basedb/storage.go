@@ -3,15 +3,29 @@ package basedb
 import (
 	"context"
 	"go.uber.org/zap"
+	"time"
 )
 
 // Options for creating all db type
 type Options struct {
-	Type      string `yaml:"Type" env:"DB_TYPE" env-default:"badger-db" env-description:"Type of db badger-db or badger-memory"`
+	Type      string `yaml:"Type" env:"DB_TYPE" env-default:"badger-db" env-description:"Type of db badger-db, badger-memory or remote-db (a shared db server, Path is its url)"`
 	Path      string `yaml:"Path" env:"DB_PATH" env-default:"./data/db" env-description:"Path for storage"`
 	Reporting bool   `yaml:"Reporting" env:"DB_REPORTING" env-default:"false" env-description:"Flag to run on-off db size reporting"`
-	Logger    *zap.Logger
-	Ctx       context.Context
+	Token     string `yaml:"Token" env:"DB_TOKEN" env-description:"Bearer token of the db server, used by remote-db"`
+	// GCInterval is the interval of value log garbage collection, which compacts the value log files
+	GCInterval time.Duration `yaml:"GCInterval" env:"DB_GC_INTERVAL" env-default:"10m" env-description:"Interval of db garbage collection (value log compaction), 0 to disable"`
+	// EncryptionKey is a hex encoded AES key (16, 24 or 32 bytes) that the db is encrypted with at rest, disabled if empty
+	EncryptionKey string `yaml:"EncryptionKey" env:"DB_ENCRYPTION_KEY" env-description:"Hex encoded AES key (16, 24 or 32 bytes) to encrypt the db at rest"`
+	// EncryptionKeyFile is a path to a file that holds the hex encoded key, e.g. a secret that was mounted by a KMS
+	EncryptionKeyFile string `yaml:"EncryptionKeyFile" env:"DB_ENCRYPTION_KEY_FILE" env-description:"Path to a file with the hex encoded db encryption key, used instead of EncryptionKey"`
+	// PrevEncryptionKey is the key that the db was encrypted with before, the db is rotated to EncryptionKey on startup
+	PrevEncryptionKey string `yaml:"PrevEncryptionKey" env:"DB_PREV_ENCRYPTION_KEY" env-description:"Hex encoded previous db encryption key, set in order to rotate the key"`
+	// EncryptionKeyRotation is the interval of data keys rotation, data keys are encrypted by the encryption key
+	EncryptionKeyRotation time.Duration `yaml:"EncryptionKeyRotation" env:"DB_ENCRYPTION_KEY_ROTATION" env-default:"240h" env-description:"Interval of db data keys rotation"`
+	// ReadOnly opens the db w/o write access, e.g. in order to serve data from an existing db
+	ReadOnly bool
+	Logger   *zap.Logger
+	Ctx      context.Context
 }
 
 // IDb interface for all db kind
@@ -21,10 +35,24 @@ type IDb interface {
 	Delete(prefix []byte, key []byte) error
 	GetAllByCollection(prefix []byte) ([]Obj, error)
 	CountByCollection(prefix []byte) (int64, error)
+	SizeByCollection(prefix []byte) (int64, error)
 	RemoveAllByCollection(prefix []byte) error
 	Close()
 }
 
+// Sizer is implemented by dbs that can report their size on disk
+type Sizer interface {
+	// Size returns the total size of the db on disk in bytes
+	Size() (int64, error)
+}
+
+// CompareAndSwapper is implemented by dbs that can atomically replace a value
+type CompareAndSwapper interface {
+	// CompareAndSwap sets the value of the given key if its current value equals old (nil if the key shouldn't exist),
+	// a nil value deletes the key. returns false if the current value is different
+	CompareAndSwap(prefix []byte, key []byte, old []byte, value []byte) (bool, error)
+}
+
 // Obj struct for getting key/value from storage
 type Obj struct {
 	Key   []byte
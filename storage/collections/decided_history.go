@@ -0,0 +1,149 @@
+package collections
+
+import (
+	"encoding/json"
+	"github.com/bloxapp/ssv/utils/merkle"
+	"github.com/pkg/errors"
+)
+
+// decidedHistoryCheckpointInterval is the interval (in sequences) of the persisted checkpoints of the decided history,
+// a root over a shorter history is re-built from the preceding checkpoint
+const decidedHistoryCheckpointInterval = 1 << 10
+
+// DecidedHistoryRoot is the merkle root over the decided history [0, Count) of an identifier,
+// only the sequence numbers and values are merkleized as the signers of a decided instance might differ between nodes
+type DecidedHistoryRoot struct {
+	Count uint64 `json:"count"`
+	Root  []byte `json:"root"`
+}
+
+// GetDecidedHistoryRoot returns the root over the first count decided instances of the given identifier,
+// or over the whole (contiguous) history if count is zero. not found if the history is shorter than count
+func (i *IbftStorage) GetDecidedHistoryRoot(identifier []byte, count uint64) (*DecidedHistoryRoot, bool, error) {
+	// checkpoints might be saved while re-building
+	i.historyLock.Lock()
+	defer i.historyLock.Unlock()
+
+	acc, err := i.getDecidedHistory(identifier)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := i.catchUpDecidedHistory(identifier, acc, count); err != nil {
+		return nil, false, err
+	}
+	if count > 0 && acc.Count > count {
+		// the accumulator keeps only the latest branch, a shorter history is re-built from the preceding checkpoint
+		if acc, err = i.getDecidedHistoryCheckpoint(identifier, count-count%decidedHistoryCheckpointInterval); err != nil {
+			return nil, false, err
+		}
+		if _, err := i.catchUpDecidedHistory(identifier, acc, count); err != nil {
+			return nil, false, err
+		}
+	}
+	if acc.Count == 0 || (count > 0 && acc.Count != count) {
+		return nil, false, nil
+	}
+	root := acc.Root()
+	return &DecidedHistoryRoot{Count: acc.Count, Root: root[:]}, true, nil
+}
+
+// updateDecidedHistory appends the decided instances that were not added yet to the history of the given identifier
+func (i *IbftStorage) updateDecidedHistory(identifier []byte) error {
+	i.historyLock.Lock()
+	defer i.historyLock.Unlock()
+
+	acc, err := i.getDecidedHistory(identifier)
+	if err != nil {
+		return err
+	}
+	appended, err := i.catchUpDecidedHistory(identifier, acc, 0)
+	if err != nil || !appended {
+		return err
+	}
+	value, err := json.Marshal(acc)
+	if err != nil {
+		return errors.Wrap(err, "marshaling error")
+	}
+	return i.save(value, "history", identifier)
+}
+
+// catchUpDecidedHistory appends the next decided instances to the given accumulator until a gap,
+// or until it holds the given count (unless zero). returns true if any instance was appended.
+// a checkpoint is saved whenever the accumulator reaches decidedHistoryCheckpointInterval
+func (i *IbftStorage) catchUpDecidedHistory(identifier []byte, acc *merkle.Accumulator, count uint64) (bool, error) {
+	appended := false
+	for count == 0 || acc.Count < count {
+		decided, found, err := i.GetDecided(identifier, acc.Count)
+		if err != nil {
+			return appended, errors.Wrap(err, "could not get decided")
+		}
+		if !found {
+			break
+		}
+		if err := acc.Append(merkle.Leaf(acc.Count, decided.Message.Value)); err != nil {
+			return appended, err
+		}
+		appended = true
+		if acc.Count%decidedHistoryCheckpointInterval == 0 {
+			if err := i.saveDecidedHistoryCheckpoint(identifier, acc); err != nil {
+				return appended, err
+			}
+		}
+	}
+	return appended, nil
+}
+
+// saveDecidedHistoryCheckpoint saves the given accumulator as the checkpoint of its count
+func (i *IbftStorage) saveDecidedHistoryCheckpoint(identifier []byte, acc *merkle.Accumulator) error {
+	value, err := json.Marshal(acc)
+	if err != nil {
+		return errors.Wrap(err, "marshaling error")
+	}
+	return i.save(value, "history-checkpoint", identifier, uInt64ToByteSlice(acc.Count))
+}
+
+// getDecidedHistoryCheckpoint returns the checkpoint of the given count, or an empty accumulator if not found
+func (i *IbftStorage) getDecidedHistoryCheckpoint(identifier []byte, count uint64) (*merkle.Accumulator, error) {
+	acc := &merkle.Accumulator{}
+	if count == 0 {
+		return acc, nil
+	}
+	val, found, err := i.get("history-checkpoint", identifier, uInt64ToByteSlice(count))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return acc, nil
+	}
+	if err := json.Unmarshal(val, acc); err != nil {
+		return nil, errors.Wrap(err, "un-marshaling error")
+	}
+	return acc, nil
+}
+
+// deleteDecidedHistoryCheckpoints deletes the checkpoints that include the given sequence number,
+// up to the given count of the history
+func (i *IbftStorage) deleteDecidedHistoryCheckpoints(identifier []byte, seqNumber uint64, count uint64) error {
+	first := seqNumber - seqNumber%decidedHistoryCheckpointInterval + decidedHistoryCheckpointInterval
+	for c := first; c <= count; c += decidedHistoryCheckpointInterval {
+		if err := i.db.Delete(i.namespace(identifier), i.key("history-checkpoint", uInt64ToByteSlice(c))); err != nil {
+			return errors.Wrap(err, "could not delete decided history checkpoint")
+		}
+	}
+	return nil
+}
+
+func (i *IbftStorage) getDecidedHistory(identifier []byte) (*merkle.Accumulator, error) {
+	acc := &merkle.Accumulator{}
+	val, found, err := i.get("history", identifier)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return acc, nil
+	}
+	if err := json.Unmarshal(val, acc); err != nil {
+		return nil, errors.Wrap(err, "un-marshaling error")
+	}
+	return acc, nil
+}
@@ -1,6 +1,7 @@
 package collections
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"github.com/bloxapp/ssv/ibft/proto"
@@ -11,6 +12,7 @@ import (
 	"go.uber.org/zap"
 	"log"
 	"strings"
+	"sync"
 )
 
 // Iibft is an interface for persisting chain data
@@ -27,6 +29,29 @@ type Iibft interface {
 	SaveHighestDecidedInstance(signedMsg *proto.SignedMessage) error
 	// GetHighestDecidedInstance gets a signed message for an ibft instance which is the highest
 	GetHighestDecidedInstance(identifier []byte) (*proto.SignedMessage, bool, error)
+	// SaveDecidedAndHighest saves a decided message and marks it as the highest decided
+	SaveDecidedAndHighest(signedMsg *proto.SignedMessage) error
+	// SaveDecidedProvenance saves the provenance of a decided message that was synced from a peer
+	SaveDecidedProvenance(identifier []byte, seqNumber uint64, p *DecidedProvenance) error
+	// GetDecidedProvenance returns the provenance of a synced decided message
+	GetDecidedProvenance(identifier []byte, seqNumber uint64) (*DecidedProvenance, bool, error)
+	// GetDecidedHistoryRoot returns the merkle root over the first count decided instances, or over the whole history if zero
+	GetDecidedHistoryRoot(identifier []byte, count uint64) (*DecidedHistoryRoot, bool, error)
+	// CleanAll removes all the data of the given identifier (current instance, decided, highest, provenance and history)
+	CleanAll(identifier []byte) error
+	// MigrateLegacyKeys moves the data of the given identifier from the legacy flat keys into its namespace
+	MigrateLegacyKeys(identifier []byte) (int, error)
+}
+
+// DecidedProvenance holds the origin of a decided message that was synced from a peer,
+// the signature of the peer over the sync response proves which peer served the message.
+// Verified is false for unsigned responses (e.g. of older versions), the peer id of those can't be proven
+type DecidedProvenance struct {
+	PeerID    string `json:"peer_id"`
+	Root      []byte `json:"root"`
+	Signature []byte `json:"signature"`
+	Verified  bool   `json:"verified"`
+	SyncedAt  int64  `json:"synced_at"`
 }
 
 var (
@@ -48,14 +73,17 @@ type IbftStorage struct {
 	prefix []byte
 	db     basedb.IDb
 	logger *zap.Logger
+	// historyLock serializes the updates of decided history roots
+	historyLock *sync.Mutex
 }
 
 // NewIbft create new ibft storage
 func NewIbft(db basedb.IDb, logger *zap.Logger, instanceType string) IbftStorage {
 	ibft := IbftStorage{
-		prefix: []byte(instanceType),
-		db:     db,
-		logger: logger,
+		prefix:      []byte(instanceType),
+		db:          db,
+		logger:      logger,
+		historyLock: &sync.Mutex{},
 	}
 	return ibft
 }
@@ -91,7 +119,10 @@ func (i *IbftStorage) SaveDecided(signedMsg *proto.SignedMessage) error {
 	if err != nil {
 		return errors.Wrap(err, "marshaling error")
 	}
-	return i.save(value, "decided", signedMsg.Message.Lambda, uInt64ToByteSlice(signedMsg.Message.SeqNumber))
+	if err := i.save(value, "decided", signedMsg.Message.Lambda, uInt64ToByteSlice(signedMsg.Message.SeqNumber)); err != nil {
+		return err
+	}
+	return i.updateDecidedHistory(signedMsg.Message.Lambda)
 }
 
 // GetDecided returns a signed message for an ibft instance which decided by identifier
@@ -125,6 +156,48 @@ func (i *IbftStorage) SaveHighestDecidedInstance(signedMsg *proto.SignedMessage)
 	return nil
 }
 
+// SaveDecidedAndHighest saves a decided message and marks it as the highest decided, the message is marshaled once
+func (i *IbftStorage) SaveDecidedAndHighest(signedMsg *proto.SignedMessage) error {
+	value, err := json.Marshal(signedMsg)
+	if err != nil {
+		return errors.Wrap(err, "marshaling error")
+	}
+	if err := i.save(value, "decided", signedMsg.Message.Lambda, uInt64ToByteSlice(signedMsg.Message.SeqNumber)); err != nil {
+		return err
+	}
+	if err := i.save(value, "highest", signedMsg.Message.Lambda); err != nil {
+		return err
+	}
+	reportHighestDecided(signedMsg)
+
+	return i.updateDecidedHistory(signedMsg.Message.Lambda)
+}
+
+// SaveDecidedProvenance saves the provenance of a decided message that was synced from a peer
+func (i *IbftStorage) SaveDecidedProvenance(identifier []byte, seqNumber uint64, p *DecidedProvenance) error {
+	value, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "marshaling error")
+	}
+	return i.save(value, "provenance", identifier, uInt64ToByteSlice(seqNumber))
+}
+
+// GetDecidedProvenance returns the provenance of a synced decided message
+func (i *IbftStorage) GetDecidedProvenance(identifier []byte, seqNumber uint64) (*DecidedProvenance, bool, error) {
+	val, found, err := i.get("provenance", identifier, uInt64ToByteSlice(seqNumber))
+	if !found {
+		return nil, found, nil
+	}
+	if err != nil {
+		return nil, found, err
+	}
+	ret := &DecidedProvenance{}
+	if err := json.Unmarshal(val, ret); err != nil {
+		return nil, false, errors.Wrap(err, "un-marshaling error")
+	}
+	return ret, found, nil
+}
+
 func reportHighestDecided(signedMsg *proto.SignedMessage) {
 	l := string(signedMsg.Message.Lambda)
 	// in order to extract the public key, the role (e.g. '_ATTESTER') is removed
@@ -151,16 +224,115 @@ func (i *IbftStorage) GetHighestDecidedInstance(identifier []byte) (*proto.Signe
 	return ret, found, nil
 }
 
+// DeleteDecided removes the decided message (and its provenance) of the given sequence number.
+// the highest decided is moved below the deleted sequence number (to the previous decided message, if any),
+// so the history sync (which starts after the highest decided) fetches the deleted message once again.
+// the decided history (and its checkpoints that include the deleted message) is dropped and re-built up to the first gap
+func (i *IbftStorage) DeleteDecided(identifier []byte, seqNumber uint64) error {
+	ns := i.namespace(identifier)
+	if err := i.db.Delete(ns, i.key("decided", uInt64ToByteSlice(seqNumber))); err != nil {
+		return errors.Wrap(err, "could not delete decided")
+	}
+	if err := i.db.Delete(ns, i.key("provenance", uInt64ToByteSlice(seqNumber))); err != nil {
+		return errors.Wrap(err, "could not delete decided provenance")
+	}
+	if err := i.resetHighestDecided(identifier, seqNumber); err != nil {
+		return err
+	}
+
+	i.historyLock.Lock()
+	defer i.historyLock.Unlock()
+	acc, err := i.getDecidedHistory(identifier)
+	if err != nil {
+		return err
+	}
+	if err := i.deleteDecidedHistoryCheckpoints(identifier, seqNumber, acc.Count); err != nil {
+		return err
+	}
+	if err := i.db.Delete(ns, i.key("history")); err != nil {
+		return errors.Wrap(err, "could not delete decided history")
+	}
+	return nil
+}
+
+// resetHighestDecided moves the highest decided to the decided message that precedes the given (deleted) sequence number,
+// nothing is done if the highest decided is below it
+func (i *IbftStorage) resetHighestDecided(identifier []byte, seqNumber uint64) error {
+	highest, found, err := i.GetHighestDecidedInstance(identifier)
+	if err != nil {
+		// the highest decided might be corrupted
+		i.logger.Warn("could not get highest decided", zap.Error(err))
+	} else if !found || highest.Message.SeqNumber < seqNumber {
+		return nil
+	}
+	for seq := seqNumber; seq > 0; seq-- {
+		prev, found, err := i.GetDecided(identifier, seq-1)
+		if err != nil {
+			return errors.Wrap(err, "could not get decided")
+		}
+		if found {
+			return i.SaveHighestDecidedInstance(prev)
+		}
+	}
+	if err := i.db.Delete(i.namespace(identifier), i.key("highest")); err != nil {
+		return errors.Wrap(err, "could not delete highest decided")
+	}
+	return nil
+}
+
+// CleanAll removes all the data of the given identifier by dropping its namespace
+func (i *IbftStorage) CleanAll(identifier []byte) error {
+	return i.db.RemoveAllByCollection(i.namespace(identifier))
+}
+
+// MigrateLegacyKeys moves the data of the given identifier from the legacy flat keys (instance type + identifier + key)
+// into the identifier namespace, returns the number of migrated objects
+func (i *IbftStorage) MigrateLegacyKeys(identifier []byte) (int, error) {
+	legacyPrefix := i.legacyPrefix(identifier)
+	objs, err := i.db.GetAllByCollection(legacyPrefix)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get legacy objects")
+	}
+	ns := i.namespace(identifier)
+	migrated := 0
+	for _, obj := range objs {
+		// legacy keys of other identifiers might share the prefix
+		if !isIbftStorageKey(obj.Key) {
+			continue
+		}
+		if err := i.db.Set(ns, obj.Key, obj.Value); err != nil {
+			return migrated, errors.Wrap(err, "failed to save migrated object")
+		}
+		if err := i.db.Delete(legacyPrefix, obj.Key); err != nil {
+			return migrated, errors.Wrap(err, "failed to remove legacy object")
+		}
+		migrated++
+	}
+	if migrated > 0 {
+		i.logger.Info("migrated ibft storage keys", zap.String("identifier", string(identifier)),
+			zap.Int("count", migrated))
+	}
+	return migrated, nil
+}
+
+// isIbftStorageKey returns true if the given key (w/o prefix) is one of the keys of this storage
+func isIbftStorageKey(key []byte) bool {
+	for _, id := range []string{"current", "decided", "highest", "provenance"} {
+		if bytes.HasPrefix(key, []byte(id)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *IbftStorage) save(value []byte, id string, pk []byte, keyParams ...[]byte) error {
-	prefix := append(i.prefix, pk...)
 	key := i.key(id, keyParams...)
-	return i.db.Set(prefix, key, value)
+	return i.db.Set(i.namespace(pk), key, value)
 }
 
 func (i *IbftStorage) get(id string, pk []byte, keyParams ...[]byte) ([]byte, bool, error) {
-	prefix := append(i.prefix, pk...)
 	key := i.key(id, keyParams...)
-	obj, found, err := i.db.Get(prefix, key)
+	obj, found, err := i.db.Get(i.namespace(pk), key)
 	if !found {
 		return nil, found, nil
 	}
@@ -170,6 +342,18 @@ func (i *IbftStorage) get(id string, pk []byte, keyParams ...[]byte) ([]byte, bo
 	return obj.Value, found, nil
 }
 
+// namespace returns the prefix of all the data of the given identifier
+func (i *IbftStorage) namespace(identifier []byte) []byte {
+	return basedb.Namespace(i.prefix, identifier)
+}
+
+// legacyPrefix returns the prefix that was used for the data of the given identifier before namespaces
+func (i *IbftStorage) legacyPrefix(identifier []byte) []byte {
+	prefix := make([]byte, 0, len(i.prefix)+len(identifier))
+	prefix = append(prefix, i.prefix...)
+	return append(prefix, identifier...)
+}
+
 func (i *IbftStorage) key(id string, params ...[]byte) []byte {
 	ret := make([]byte, 0)
 	ret = append(ret, []byte(id)...)
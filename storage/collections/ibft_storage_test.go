@@ -1,9 +1,11 @@
 package collections
 
 import (
+	"encoding/json"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/kv"
+	"github.com/bloxapp/ssv/utils/merkle"
 	"github.com/bloxapp/ssv/utils/threadsafe"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -37,6 +39,102 @@ func TestIbftStorage_SaveDecided(t *testing.T) {
 	require.False(t, found)
 }
 
+func TestIbftStorage_DecidedProvenance(t *testing.T) {
+	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
+	err := storage.SaveDecidedProvenance([]byte{1, 2, 3, 4}, 3, &DecidedProvenance{
+		PeerID:    "peer1",
+		Root:      []byte{1, 1},
+		Signature: []byte{2, 2},
+		SyncedAt:  100,
+	})
+	require.NoError(t, err)
+
+	p, found, err := storage.GetDecidedProvenance([]byte{1, 2, 3, 4}, 3)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, "peer1", p.PeerID)
+	require.EqualValues(t, []byte{1, 1}, p.Root)
+	require.EqualValues(t, []byte{2, 2}, p.Signature)
+	require.EqualValues(t, 100, p.SyncedAt)
+
+	// not found
+	_, found, err = storage.GetDecidedProvenance([]byte{1, 2, 3, 4}, 4)
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestIbftStorage_CleanAll(t *testing.T) {
+	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
+	for _, identifier := range [][]byte{[]byte("id_1"), []byte("id_11")} {
+		msg := &proto.SignedMessage{
+			Message: &proto.Message{
+				Type:      proto.RoundState_Decided,
+				Lambda:    identifier,
+				SeqNumber: 1,
+			},
+		}
+		require.NoError(t, storage.SaveDecidedAndHighest(msg))
+	}
+
+	require.NoError(t, storage.CleanAll([]byte("id_1")))
+	_, found, err := storage.GetDecided([]byte("id_1"), 1)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = storage.GetHighestDecidedInstance([]byte("id_1"))
+	require.NoError(t, err)
+	require.False(t, found)
+	// other identifiers are not affected, even if the identifier is a prefix of theirs
+	_, found, err = storage.GetDecided([]byte("id_11"), 1)
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestIbftStorage_MigrateLegacyKeys(t *testing.T) {
+	db := newInMemDb()
+	storage := NewIbft(db, zap.L(), "attestation")
+	identifier := []byte("id_1")
+	msg := &proto.SignedMessage{
+		Message: &proto.Message{
+			Type:      proto.RoundState_Decided,
+			Lambda:    identifier,
+			SeqNumber: 2,
+		},
+	}
+	value, err := json.Marshal(msg)
+	require.NoError(t, err)
+	legacyPrefix := append([]byte("attestation"), identifier...)
+	require.NoError(t, db.Set(legacyPrefix, storage.key("decided", uInt64ToByteSlice(2)), value))
+	require.NoError(t, db.Set(legacyPrefix, storage.key("highest"), value))
+	// legacy keys of another identifier with the same prefix
+	otherPrefix := append([]byte("attestation"), []byte("id_11")...)
+	require.NoError(t, db.Set(otherPrefix, storage.key("highest"), value))
+
+	migrated, err := storage.MigrateLegacyKeys(identifier)
+	require.NoError(t, err)
+	require.Equal(t, 2, migrated)
+
+	decided, found, err := storage.GetDecided(identifier, 2)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 2, decided.Message.SeqNumber)
+	highest, found, err := storage.GetHighestDecidedInstance(identifier)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 2, highest.Message.SeqNumber)
+	// only the legacy keys of the other identifier are left
+	count, err := db.CountByCollection(legacyPrefix)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+	count, err = db.CountByCollection(otherPrefix)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	// nothing left to migrate
+	migrated, err = storage.MigrateLegacyKeys(identifier)
+	require.NoError(t, err)
+	require.Equal(t, 0, migrated)
+}
+
 func TestIbftStorage_SaveCurrentInstance(t *testing.T) {
 	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
 	err := storage.SaveCurrentInstance([]byte{1, 2, 3, 4}, &proto.State{
@@ -87,6 +185,60 @@ func TestIbftStorage_GetHighestDecidedInstance(t *testing.T) {
 	require.False(t, found)
 }
 
+func TestIbftStorage_SaveDecidedAndHighest(t *testing.T) {
+	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
+	require.NoError(t, storage.SaveDecidedAndHighest(newDecidedMsg(3)))
+
+	decided, found, err := storage.GetDecided([]byte{1, 2, 3, 4}, 3)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 3, decided.Message.SeqNumber)
+
+	highest, found, err := storage.GetHighestDecidedInstance([]byte{1, 2, 3, 4})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, decided, highest)
+}
+
+func BenchmarkIbftStorage_SaveDecided(b *testing.B) {
+	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
+
+	b.Run("separately", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			msg := newDecidedMsg(uint64(i))
+			if err := storage.SaveDecided(msg); err != nil {
+				b.Fatal(err)
+			}
+			if err := storage.SaveHighestDecidedInstance(msg); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("combined", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := storage.SaveDecidedAndHighest(newDecidedMsg(uint64(i))); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func newDecidedMsg(seq uint64) *proto.SignedMessage {
+	return &proto.SignedMessage{
+		Message: &proto.Message{
+			Type:      proto.RoundState_Decided,
+			Round:     2,
+			Lambda:    []byte{1, 2, 3, 4},
+			SeqNumber: seq,
+		},
+		Signature: []byte{1, 2, 3, 4},
+		SignerIds: []uint64{1, 2, 3},
+	}
+}
+
 func newInMemDb() basedb.IDb {
 	db, _ := kv.New(basedb.Options{
 		Type:   "badger-memory",
@@ -95,3 +247,176 @@ func newInMemDb() basedb.IDb {
 	})
 	return db
 }
+
+func TestIbftStorage_DecidedHistoryRoot(t *testing.T) {
+	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
+	identifier := []byte{1, 2, 3, 4}
+	decided := func(seq uint64, signers ...uint64) *proto.SignedMessage {
+		return &proto.SignedMessage{
+			Message: &proto.Message{
+				Type:      proto.RoundState_Commit,
+				Round:     1,
+				Lambda:    identifier,
+				SeqNumber: seq,
+				Value:     []byte{byte(seq)},
+			},
+			Signature: []byte{1, 2, 3, 4},
+			SignerIds: signers,
+		}
+	}
+	expectedRoot := func(count uint64) []byte {
+		acc := &merkle.Accumulator{}
+		for seq := uint64(0); seq < count; seq++ {
+			require.NoError(t, acc.Append(merkle.Leaf(seq, []byte{byte(seq)})))
+		}
+		root := acc.Root()
+		return root[:]
+	}
+
+	_, found, err := storage.GetDecidedHistoryRoot(identifier, 0)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, storage.SaveDecided(decided(0, 1, 2, 3)))
+	// a gap, seq 2 is added once seq 1 is saved
+	require.NoError(t, storage.SaveDecidedAndHighest(decided(2, 1, 2, 3)))
+	root, found, err := storage.GetDecidedHistoryRoot(identifier, 0)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 1, root.Count)
+	require.Equal(t, expectedRoot(1), root.Root)
+
+	require.NoError(t, storage.SaveDecided(decided(1, 2, 3, 4)))
+	root, found, err = storage.GetDecidedHistoryRoot(identifier, 0)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 3, root.Count)
+	require.Equal(t, expectedRoot(3), root.Root)
+
+	// the signers of a decided instance are not merkleized
+	require.NoError(t, storage.SaveDecided(decided(1, 1, 2, 3, 4)))
+	again, _, err := storage.GetDecidedHistoryRoot(identifier, 0)
+	require.NoError(t, err)
+	require.Equal(t, root, again)
+
+	t.Run("by count", func(t *testing.T) {
+		root, found, err := storage.GetDecidedHistoryRoot(identifier, 2)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.EqualValues(t, 2, root.Count)
+		require.Equal(t, expectedRoot(2), root.Root)
+
+		_, found, err = storage.GetDecidedHistoryRoot(identifier, 4)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+}
+
+func TestIbftStorage_DecidedHistoryCheckpoints(t *testing.T) {
+	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
+	identifier := []byte{1, 2, 3, 4}
+	count := uint64(decidedHistoryCheckpointInterval + 10)
+	acc := &merkle.Accumulator{}
+	roots := make(map[uint64][]byte)
+	for seq := uint64(0); seq < count; seq++ {
+		value := []byte{byte(seq), byte(seq >> 8)}
+		require.NoError(t, storage.SaveDecidedAndHighest(&proto.SignedMessage{
+			Message: &proto.Message{
+				Type:      proto.RoundState_Commit,
+				Round:     1,
+				Lambda:    identifier,
+				SeqNumber: seq,
+				Value:     value,
+			},
+			Signature: []byte{1, 2, 3, 4},
+			SignerIds: []uint64{1, 2, 3},
+		}))
+		require.NoError(t, acc.Append(merkle.Leaf(seq, value)))
+		root := acc.Root()
+		roots[acc.Count] = root[:]
+	}
+
+	checkpoint, err := storage.getDecidedHistoryCheckpoint(identifier, decidedHistoryCheckpointInterval)
+	require.NoError(t, err)
+	require.EqualValues(t, decidedHistoryCheckpointInterval, checkpoint.Count)
+
+	// shorter histories are re-built from the preceding checkpoint (if any)
+	for _, c := range []uint64{1, 10, decidedHistoryCheckpointInterval - 1, decidedHistoryCheckpointInterval,
+		decidedHistoryCheckpointInterval + 5} {
+		root, found, err := storage.GetDecidedHistoryRoot(identifier, c)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.EqualValues(t, c, root.Count)
+		require.Equal(t, roots[c], root.Root, "count %d", c)
+	}
+
+	// checkpoints that include a deleted message are dropped
+	require.NoError(t, storage.DeleteDecided(identifier, 100))
+	checkpoint, err = storage.getDecidedHistoryCheckpoint(identifier, decidedHistoryCheckpointInterval)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, checkpoint.Count)
+	root, found, err := storage.GetDecidedHistoryRoot(identifier, 0)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 100, root.Count)
+}
+
+func TestIbftStorage_DeleteDecided(t *testing.T) {
+	storage := NewIbft(newInMemDb(), zap.L(), "attestation")
+	identifier := []byte{1, 2, 3, 4}
+	decided := func(seq uint64) *proto.SignedMessage {
+		return &proto.SignedMessage{
+			Message: &proto.Message{
+				Type:      proto.RoundState_Commit,
+				Round:     1,
+				Lambda:    identifier,
+				SeqNumber: seq,
+				Value:     []byte{byte(seq)},
+			},
+			Signature: []byte{1, 2, 3, 4},
+			SignerIds: []uint64{1, 2, 3},
+		}
+	}
+	for seq := uint64(0); seq <= 3; seq++ {
+		require.NoError(t, storage.SaveDecidedAndHighest(decided(seq)))
+	}
+	require.NoError(t, storage.SaveDecidedProvenance(identifier, 1, &DecidedProvenance{PeerID: "peer1"}))
+
+	// the highest is moved below the deleted sequence, so it will be synced once again
+	require.NoError(t, storage.DeleteDecided(identifier, 2))
+	_, found, err := storage.GetDecided(identifier, 2)
+	require.NoError(t, err)
+	require.False(t, found)
+	highest, found, err := storage.GetHighestDecidedInstance(identifier)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 1, highest.Message.SeqNumber)
+	// the history is re-built up to the gap
+	root, found, err := storage.GetDecidedHistoryRoot(identifier, 0)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 2, root.Count)
+
+	// deleting above the highest doesn't move the highest
+	require.NoError(t, storage.DeleteDecided(identifier, 3))
+	highest, found, err = storage.GetHighestDecidedInstance(identifier)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 1, highest.Message.SeqNumber)
+
+	// the highest is moved to the previous decided, skipping gaps
+	require.NoError(t, storage.SaveDecidedAndHighest(decided(3)))
+	require.NoError(t, storage.DeleteDecided(identifier, 1))
+	_, found, err = storage.GetDecidedProvenance(identifier, 1)
+	require.NoError(t, err)
+	require.False(t, found)
+	highest, found, err = storage.GetHighestDecidedInstance(identifier)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 0, highest.Message.SeqNumber)
+
+	require.NoError(t, storage.DeleteDecided(identifier, 0))
+	_, found, err = storage.GetHighestDecidedInstance(identifier)
+	require.NoError(t, err)
+	require.False(t, found)
+}
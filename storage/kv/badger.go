@@ -31,10 +31,29 @@ func New(options basedb.Options) (basedb.IDb, error) {
 		opt.InMemory = true
 		opt.Dir = ""
 		opt.ValueDir = ""
+	} else if options.ReadOnly {
+		opt.ReadOnly = true
 	}
 
 	opt.ValueLogFileSize = 1024 * 1024 * 100 // TODO:need to set the vlog proper (max) size
 
+	key, prevKey, err := encryptionKeys(options)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) > 0 {
+		opt.EncryptionKey = key
+		opt.IndexCacheSize = indexCacheSize
+		if options.EncryptionKeyRotation > 0 {
+			opt.EncryptionKeyRotationDuration = options.EncryptionKeyRotation
+		}
+		if !opt.InMemory && !opt.ReadOnly {
+			if err := rotateEncryptionKey(options.Logger, opt, prevKey); err != nil {
+				return nil, errors.Wrap(err, "failed to rotate encryption key")
+			}
+		}
+	}
+
 	db, err := badger.Open(opt)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to open badger")
@@ -47,6 +66,10 @@ func New(options basedb.Options) (basedb.IDb, error) {
 	if options.Reporting && options.Ctx != nil {
 		async.RunEvery(options.Ctx, 1*time.Minute, _db.report)
 	}
+	// value log gc is not supported in memory
+	if options.GCInterval > 0 && options.Ctx != nil && !opt.InMemory && !opt.ReadOnly {
+		async.RunEvery(options.Ctx, options.GCInterval, _db.runGC)
+	}
 
 	options.Logger.Info("Badger db initialized")
 	return &_db, nil
@@ -91,6 +114,43 @@ func (b *BadgerDb) Delete(prefix []byte, key []byte) error {
 	})
 }
 
+// CompareAndSwap implements basedb.CompareAndSwapper, the value is read and written in a single transaction,
+// which fails on a conflict with a concurrent transaction
+func (b *BadgerDb) CompareAndSwap(prefix []byte, key []byte, old []byte, value []byte) (bool, error) {
+	swapped := false
+	err := b.db.Update(func(txn *badger.Txn) error {
+		k := append(prefix, key...)
+		var current []byte
+		item, err := txn.Get(k)
+		if err != nil && err != badger.ErrKeyNotFound {
+			return err
+		}
+		if err == nil {
+			if current, err = item.ValueCopy(nil); err != nil {
+				return err
+			}
+			if current == nil {
+				current = []byte{}
+			}
+		}
+		if (old == nil) != (current == nil) || !bytes.Equal(old, current) {
+			return nil
+		}
+		swapped = true
+		if value == nil {
+			return txn.Delete(k)
+		}
+		return txn.Set(k, value)
+	})
+	if err == badger.ErrConflict {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return swapped, nil
+}
+
 // GetAllByCollection return all array of Obj for all keys under specified prefix(bucket)
 func (b *BadgerDb) GetAllByCollection(prefix []byte) ([]basedb.Obj, error) {
 	var res []basedb.Obj
@@ -122,6 +182,23 @@ func (b *BadgerDb) CountByCollection(prefix []byte) (int64, error) {
 	return res, err
 }
 
+// SizeByCollection return the estimated size in bytes of all the objects under specified prefix(bucket)
+func (b *BadgerDb) SizeByCollection(prefix []byte) (int64, error) {
+	var res int64
+	err := b.db.View(func(txn *badger.Txn) error {
+		opt := badger.DefaultIteratorOptions
+		opt.Prefix = prefix
+		opt.PrefetchValues = false
+		it := txn.NewIterator(opt)
+		defer it.Close()
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			res += it.Item().EstimatedSize()
+		}
+		return nil
+	})
+	return res, err
+}
+
 // RemoveAllByCollection cleans all items in a collection
 func (b *BadgerDb) RemoveAllByCollection(prefix []byte) error {
 	return b.db.DropPrefix(prefix)
@@ -134,6 +211,12 @@ func (b *BadgerDb) Close() {
 	}
 }
 
+// Size implements basedb.Sizer, it returns the size of the lsm tree and the value log
+func (b *BadgerDb) Size() (int64, error) {
+	lsm, vlog := b.db.Size()
+	return lsm + vlog, nil
+}
+
 // report the db size and metrics
 func (b *BadgerDb) report() {
 	logger := b.logger.With(zap.String("who", "BadgerDBReporting"))
@@ -141,11 +224,36 @@ func (b *BadgerDb) report() {
 	blockCache := b.db.BlockCacheMetrics()
 	indexCache := b.db.IndexCacheMetrics()
 
+	reportSize(lsm, vlog)
+
 	logger.Debug("BadgerDBReport", zap.Int64("lsm", lsm), zap.Int64("vlog", vlog),
 		zap.String("BlockCacheMetrics", blockCache.String()),
 		zap.String("IndexCacheMetrics", indexCache.String()))
 }
 
+// runGC runs value log garbage collection until there is nothing left to rewrite
+func (b *BadgerDb) runGC() {
+	start := time.Now()
+	rewrites := 0
+	for {
+		err := b.db.RunValueLogGC(gcDiscardRatio)
+		if err == nil {
+			rewrites++
+			continue
+		}
+		if err != badger.ErrNoRewrite {
+			metricsGCRuns.WithLabelValues("failed").Inc()
+			b.logger.Warn("db gc failed", zap.Error(err))
+			return
+		}
+		break
+	}
+	metricsGCRuns.WithLabelValues("ok").Inc()
+	metricsGCRewrites.Add(float64(rewrites))
+	reportSize(b.db.Size())
+	b.logger.Debug("db gc done", zap.Int("rewrites", rewrites), zap.Duration("took", time.Since(start)))
+}
+
 func (b *BadgerDb) getAll(rawKeys [][]byte, prefix []byte, txn *badger.Txn) []basedb.Obj {
 	var res []basedb.Obj
 
@@ -128,3 +128,94 @@ func getAllByCollectionTest(t *testing.T, n int, db basedb.IDb) {
 	require.Equal(t, n, len(visited))
 	require.NoError(t, db.RemoveAllByCollection(prefix))
 }
+
+func TestBadgerDb_SizeByCollection(t *testing.T) {
+	db, err := New(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.Set([]byte("prefix1"), []byte("key1"), make([]byte, 100)))
+	require.NoError(t, db.Set([]byte("prefix1"), []byte("key2"), make([]byte, 100)))
+	require.NoError(t, db.Set([]byte("prefix2"), []byte("key1"), make([]byte, 10)))
+
+	size1, err := db.SizeByCollection([]byte("prefix1"))
+	require.NoError(t, err)
+	size2, err := db.SizeByCollection([]byte("prefix2"))
+	require.NoError(t, err)
+	require.Greater(t, size1, int64(200))
+	require.Greater(t, size2, int64(10))
+	require.Less(t, size2, size1)
+
+	size, err := db.SizeByCollection([]byte("prefix3"))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, size)
+}
+
+func TestBadgerDb_ReadOnly(t *testing.T) {
+	options := basedb.Options{
+		Type:   "badger-db",
+		Logger: zap.L(),
+		Path:   t.TempDir(),
+	}
+	db, err := New(options)
+	require.NoError(t, err)
+	require.NoError(t, db.Set([]byte("prefix1"), []byte("key1"), []byte("value")))
+	db.Close()
+
+	options.ReadOnly = true
+	db, err = New(options)
+	require.NoError(t, err)
+	defer db.Close()
+
+	obj, found, err := db.Get([]byte("prefix1"), []byte("key1"))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, []byte("value"), obj.Value)
+	require.Error(t, db.Set([]byte("prefix1"), []byte("key2"), []byte("value")))
+}
+
+func TestBadgerDb_CompareAndSwap(t *testing.T) {
+	db, err := New(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	testCompareAndSwap(t, db.(basedb.CompareAndSwapper), db)
+}
+
+// testCompareAndSwap tests the compare and swap of the given db
+func testCompareAndSwap(t *testing.T, cas basedb.CompareAndSwapper, db basedb.IDb) {
+	prefix, key := []byte("prefix1"), []byte("key1")
+	// the key doesn't exist
+	swapped, err := cas.CompareAndSwap(prefix, key, []byte("a"), []byte("b"))
+	require.NoError(t, err)
+	require.False(t, swapped)
+	swapped, err = cas.CompareAndSwap(prefix, key, nil, []byte("a"))
+	require.NoError(t, err)
+	require.True(t, swapped)
+	// the key exists
+	swapped, err = cas.CompareAndSwap(prefix, key, nil, []byte("b"))
+	require.NoError(t, err)
+	require.False(t, swapped)
+	swapped, err = cas.CompareAndSwap(prefix, key, []byte("a"), []byte("b"))
+	require.NoError(t, err)
+	require.True(t, swapped)
+	obj, found, err := db.Get(prefix, key)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, []byte("b"), obj.Value)
+	// delete
+	swapped, err = cas.CompareAndSwap(prefix, key, []byte("b"), nil)
+	require.NoError(t, err)
+	require.True(t, swapped)
+	_, found, err = db.Get(prefix, key)
+	require.NoError(t, err)
+	require.False(t, found)
+}
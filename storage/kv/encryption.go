@@ -0,0 +1,106 @@
+package kv
+
+import (
+	"encoding/hex"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// indexCacheSize is required by badger once encryption is enabled, in order to cache the decrypted indices
+	indexCacheSize = 100 << 20
+)
+
+// encryptionKeys returns the current and previous keys of the db, the current key is nil if encryption is disabled
+func encryptionKeys(options basedb.Options) ([]byte, []byte, error) {
+	encoded := options.EncryptionKey
+	if len(options.EncryptionKeyFile) > 0 {
+		raw, err := ioutil.ReadFile(options.EncryptionKeyFile)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not read encryption key file")
+		}
+		encoded = string(raw)
+	}
+	key, err := decodeEncryptionKey(encoded)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid encryption key")
+	}
+	prevKey, err := decodeEncryptionKey(options.PrevEncryptionKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid previous encryption key")
+	}
+	if len(key) == 0 && len(prevKey) > 0 {
+		return nil, nil, errors.New("previous encryption key was provided w/o an encryption key")
+	}
+	return key, prevKey, nil
+}
+
+// decodeEncryptionKey decodes the given hex key, returns nil if empty
+func decodeEncryptionKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimPrefix(strings.TrimSpace(encoded), "0x")
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, errors.Errorf("key length should be 16, 24 or 32 bytes, got %d", len(key))
+	}
+}
+
+// rotateEncryptionKey re-encrypts the key registry of an existing db with the given key,
+// in case it is encrypted with the previous key or not encrypted at all.
+// the registry holds the data keys that encrypt the actual data, therefore the data itself is not rewritten
+func rotateEncryptionKey(logger *zap.Logger, opt badger.Options, prevKey []byte) error {
+	if _, err := os.Stat(filepath.Join(opt.Dir, badger.KeyRegistryFileName)); os.IsNotExist(err) {
+		// a new db
+		return nil
+	}
+	regOpts := badger.KeyRegistryOptions{
+		Dir:                           opt.Dir,
+		ReadOnly:                      true,
+		EncryptionKey:                 opt.EncryptionKey,
+		EncryptionKeyRotationDuration: opt.EncryptionKeyRotationDuration,
+	}
+	kr, err := badger.OpenKeyRegistry(regOpts)
+	if err == nil {
+		// already encrypted with the current key
+		return kr.Close()
+	}
+	if !errors.Is(err, badger.ErrEncryptionKeyMismatch) {
+		return errors.Wrap(err, "could not open key registry")
+	}
+	// an empty key is used for a db that was not encrypted before
+	candidates := [][]byte{{}}
+	if len(prevKey) > 0 {
+		candidates = [][]byte{prevKey, {}}
+	}
+	for _, candidate := range candidates {
+		prevOpts := regOpts
+		prevOpts.EncryptionKey = candidate
+		kr, err := badger.OpenKeyRegistry(prevOpts)
+		if errors.Is(err, badger.ErrEncryptionKeyMismatch) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not open key registry")
+		}
+		if err := badger.WriteKeyRegistry(kr, regOpts); err != nil {
+			return errors.Wrap(err, "could not write key registry")
+		}
+		logger.Info("rotated db encryption key", zap.Bool("wasEncrypted", len(candidate) > 0))
+		return kr.Close()
+	}
+	return errors.Wrap(badger.ErrEncryptionKeyMismatch, "db is encrypted with an unknown key")
+}
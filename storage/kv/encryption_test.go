@@ -0,0 +1,84 @@
+package kv
+
+import (
+	"encoding/hex"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeEncryptionKey(t *testing.T) {
+	key, err := decodeEncryptionKey("")
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	key, err = decodeEncryptionKey(" 0x000102030405060708090a0b0c0d0e0f\n")
+	require.NoError(t, err)
+	require.Len(t, key, 16)
+
+	_, err = decodeEncryptionKey("0001")
+	require.Error(t, err)
+	_, err = decodeEncryptionKey("xyz")
+	require.Error(t, err)
+}
+
+func TestBadgerEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "badger-encryption")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key1 := hex.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	key2 := hex.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(key2), 0600))
+
+	open := func(options basedb.Options) (basedb.IDb, error) {
+		options.Type = "badger-db"
+		options.Path = filepath.Join(dir, "db")
+		options.Logger = zap.L()
+		return New(options)
+	}
+	requireValue := func(db basedb.IDb, key string) {
+		obj, found, err := db.Get([]byte("prefix"), []byte(key))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, []byte("value"), obj.Value)
+	}
+
+	// plain db is encrypted once a key is provided
+	db, err := open(basedb.Options{})
+	require.NoError(t, err)
+	require.NoError(t, db.Set([]byte("prefix"), []byte("plain"), []byte("value")))
+	db.Close()
+
+	db, err = open(basedb.Options{EncryptionKey: key1})
+	require.NoError(t, err)
+	requireValue(db, "plain")
+	require.NoError(t, db.Set([]byte("prefix"), []byte("encrypted"), []byte("value")))
+	db.Close()
+
+	// the key is required once encrypted
+	_, err = open(basedb.Options{})
+	require.Error(t, err)
+	_, err = open(basedb.Options{EncryptionKeyFile: keyFile})
+	require.Error(t, err)
+
+	// rotation to the key from file
+	db, err = open(basedb.Options{EncryptionKeyFile: keyFile, PrevEncryptionKey: key1})
+	require.NoError(t, err)
+	requireValue(db, "plain")
+	requireValue(db, "encrypted")
+	db.Close()
+
+	db, err = open(basedb.Options{EncryptionKeyFile: keyFile})
+	require.NoError(t, err)
+	requireValue(db, "encrypted")
+	db.Close()
+
+	_, err = open(basedb.Options{EncryptionKey: key1})
+	require.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package kv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+// gcDiscardRatio is the ratio of stale data in a value log file that triggers a rewrite of the file
+const gcDiscardRatio = 0.5
+
+var (
+	metricsGCRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:storage:db_gc_runs",
+		Help: "Count of db garbage collection runs",
+	}, []string{"result"})
+	metricsGCRewrites = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv:storage:db_gc_rewrites",
+		Help: "Count of value log files that were rewritten by db garbage collection",
+	})
+	metricsDBSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:storage:db_size_bytes",
+		Help: "The size of the db files on disk",
+	}, []string{"type"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsGCRuns); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsGCRewrites); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsDBSize); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+func reportSize(lsm, vlog int64) {
+	metricsDBSize.WithLabelValues("lsm").Set(float64(lsm))
+	metricsDBSize.WithLabelValues("vlog").Set(float64(vlog))
+}
@@ -0,0 +1,144 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// remoteTimeout is the timeout of a single request to the db server
+const remoteTimeout = 30 * time.Second
+
+// RemoteDb is a client of a shared db that is served by another process (see NewServer)
+type RemoteDb struct {
+	url    string
+	token  string
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewRemote creates a new instance of a remote db, options.Path is the url of the db server
+func NewRemote(options basedb.Options) (basedb.IDb, error) {
+	if len(options.Path) == 0 {
+		return nil, errors.New("db server url was not provided")
+	}
+	db := &RemoteDb{
+		url:    strings.TrimSuffix(options.Path, "/"),
+		token:  options.Token,
+		client: &http.Client{Timeout: remoteTimeout},
+		logger: options.Logger,
+	}
+	// make sure the server is reachable
+	if _, err := db.CountByCollection([]byte{}); err != nil {
+		return nil, errors.Wrap(err, "could not reach db server")
+	}
+	options.Logger.Info("remote db initialized", zap.String("url", db.url))
+	return db, nil
+}
+
+// Set save value with key to storage
+func (r *RemoteDb) Set(prefix []byte, key []byte, value []byte) error {
+	_, err := r.do(opSet, &remoteRequest{Prefix: prefix, Key: key, Value: value})
+	return err
+}
+
+// Get return value for specified key
+func (r *RemoteDb) Get(prefix []byte, key []byte) (basedb.Obj, bool, error) {
+	res, err := r.do(opGet, &remoteRequest{Prefix: prefix, Key: key})
+	if err != nil {
+		return basedb.Obj{}, true, err
+	}
+	if !res.Found {
+		return basedb.Obj{}, false, nil
+	}
+	return basedb.Obj{Key: key, Value: res.Value}, true, nil
+}
+
+// Delete key in specific prefix
+func (r *RemoteDb) Delete(prefix []byte, key []byte) error {
+	_, err := r.do(opDelete, &remoteRequest{Prefix: prefix, Key: key})
+	return err
+}
+
+// GetAllByCollection return all array of Obj for all keys under specified prefix(bucket)
+func (r *RemoteDb) GetAllByCollection(prefix []byte) ([]basedb.Obj, error) {
+	res, err := r.do(opGetAll, &remoteRequest{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	return res.Objs, nil
+}
+
+// CountByCollection return the object count for all keys under specified prefix(bucket)
+func (r *RemoteDb) CountByCollection(prefix []byte) (int64, error) {
+	res, err := r.do(opCount, &remoteRequest{Prefix: prefix})
+	if err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// SizeByCollection return the estimated size in bytes of all the objects under specified prefix(bucket)
+func (r *RemoteDb) SizeByCollection(prefix []byte) (int64, error) {
+	res, err := r.do(opSize, &remoteRequest{Prefix: prefix})
+	if err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// RemoveAllByCollection cleans all items in a collection
+func (r *RemoteDb) RemoveAllByCollection(prefix []byte) error {
+	_, err := r.do(opRemoveAll, &remoteRequest{Prefix: prefix})
+	return err
+}
+
+// CompareAndSwap implements basedb.CompareAndSwapper, the swap is done atomically by the db server
+func (r *RemoteDb) CompareAndSwap(prefix []byte, key []byte, old []byte, value []byte) (bool, error) {
+	res, err := r.do(opCAS, &remoteRequest{Prefix: prefix, Key: key, Old: old, Value: value})
+	if err != nil {
+		return false, err
+	}
+	return res.Swapped, nil
+}
+
+// Close closes idle connections, the db itself is closed by the server
+func (r *RemoteDb) Close() {
+	r.client.CloseIdleConnections()
+}
+
+func (r *RemoteDb) do(op string, req *remoteRequest) (*remoteResponse, error) {
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode db request")
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, r.url+"/db/"+op, bytes.NewReader(raw))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create db request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+r.token)
+	httpRes, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrapf(err, "db request %s failed", op)
+	}
+	defer func() {
+		_ = httpRes.Body.Close()
+	}()
+	if httpRes.StatusCode == http.StatusUnauthorized {
+		return nil, errors.Errorf("db request %s is unauthorized", op)
+	}
+	var res remoteResponse
+	if err := json.NewDecoder(httpRes.Body).Decode(&res); err != nil {
+		return nil, errors.Wrapf(err, "could not decode db response (status %d)", httpRes.StatusCode)
+	}
+	if httpRes.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("db request %s failed: %s", op, res.Error)
+	}
+	return &res, nil
+}
@@ -0,0 +1,111 @@
+package kv
+
+import (
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRemoteDb(t *testing.T) {
+	db, err := New(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	handler, err := NewServer(zap.L(), db, ServerOptions{Token: "secret", Writable: true})
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	remote, err := NewRemote(basedb.Options{Logger: zap.L(), Path: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+	defer remote.Close()
+
+	require.NoError(t, remote.Set([]byte("prefix1"), []byte("key1"), []byte("value1")))
+	require.NoError(t, remote.Set([]byte("prefix1"), []byte("key2"), []byte("value2")))
+	obj, found, err := db.Get([]byte("prefix1"), []byte("key1"))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, []byte("value1"), obj.Value)
+
+	obj, found, err = remote.Get([]byte("prefix1"), []byte("key2"))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, []byte("key2"), obj.Key)
+	require.EqualValues(t, []byte("value2"), obj.Value)
+	_, found, err = remote.Get([]byte("prefix1"), []byte("key3"))
+	require.NoError(t, err)
+	require.False(t, found)
+
+	objs, err := remote.GetAllByCollection([]byte("prefix1"))
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	count, err := remote.CountByCollection([]byte("prefix1"))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	require.NoError(t, remote.Delete([]byte("prefix1"), []byte("key1")))
+	count, err = remote.CountByCollection([]byte("prefix1"))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+	require.NoError(t, remote.RemoveAllByCollection([]byte("prefix1")))
+	count, err = remote.CountByCollection([]byte("prefix1"))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, count)
+
+	testCompareAndSwap(t, remote.(basedb.CompareAndSwapper), remote)
+
+	_, err = NewRemote(basedb.Options{Logger: zap.L(), Path: "http://127.0.0.1:1"})
+	require.Error(t, err)
+}
+
+func TestRemoteDb_ReadOnly(t *testing.T) {
+	db, err := New(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Set([]byte("prefix1"), []byte("key1"), []byte("value1")))
+
+	_, err = NewServer(zap.L(), db, ServerOptions{})
+	require.EqualError(t, err, "db server token is required")
+	handler, err := NewServer(zap.L(), db, ServerOptions{Token: "secret", CASPrefixes: [][]byte{[]byte("lease")}})
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	_, err = NewRemote(basedb.Options{Logger: zap.L(), Path: srv.URL, Token: "wrong"})
+	require.EqualError(t, err, "could not reach db server: db request count is unauthorized")
+
+	remote, err := NewRemote(basedb.Options{Logger: zap.L(), Path: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+	defer remote.Close()
+
+	obj, found, err := remote.Get([]byte("prefix1"), []byte("key1"))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, []byte("value1"), obj.Value)
+
+	// write operations are not allowed
+	require.EqualError(t, remote.Set([]byte("prefix1"), []byte("key2"), []byte("value2")),
+		"db request set failed: operation is not allowed on a read only db server")
+	require.Error(t, remote.Delete([]byte("prefix1"), []byte("key1")))
+	require.Error(t, remote.RemoveAllByCollection([]byte("prefix1")))
+	count, err := remote.CountByCollection([]byte("prefix1"))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	// compare and swap is allowed only on the given prefixes
+	cas := remote.(basedb.CompareAndSwapper)
+	_, err = cas.CompareAndSwap([]byte("prefix1"), []byte("key1"), []byte("value1"), []byte("value2"))
+	require.Error(t, err)
+	swapped, err := cas.CompareAndSwap([]byte("lease"), []byte("key1"), nil, []byte("holder"))
+	require.NoError(t, err)
+	require.True(t, swapped)
+}
@@ -0,0 +1,141 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/httpauth"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// remote operations, served under /db/<op>
+const (
+	opSet       = "set"
+	opGet       = "get"
+	opDelete    = "delete"
+	opGetAll    = "get-all"
+	opCount     = "count"
+	opSize      = "size"
+	opRemoveAll = "remove-all"
+	opCAS       = "cas"
+)
+
+// remoteRequest is the body of a request to the db server
+type remoteRequest struct {
+	Prefix []byte `json:"prefix"`
+	Key    []byte `json:"key,omitempty"`
+	// Value and Old are not omitted as a nil value is different than an empty one (see CompareAndSwap)
+	Value []byte `json:"value"`
+	Old   []byte `json:"old"`
+}
+
+// remoteResponse is the body of a response of the db server
+type remoteResponse struct {
+	Found   bool         `json:"found,omitempty"`
+	Value   []byte       `json:"value,omitempty"`
+	Objs    []basedb.Obj `json:"objs,omitempty"`
+	Count   int64        `json:"count,omitempty"`
+	Swapped bool         `json:"swapped,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// ServerOptions are the options of the db server
+type ServerOptions struct {
+	// Token is the bearer token that requests are authenticated with, required
+	Token string
+	// Writable allows the write operations (set, delete, remove-all and cas on any key)
+	Writable bool
+	// CASPrefixes are the prefixes that allow compare and swap when the server is not writable,
+	// e.g. the lease of the exporter leader election
+	CASPrefixes [][]byte
+}
+
+// NewServer returns an http handler that serves the given db to remote dbs (see NewRemote),
+// it allows multiple processes (e.g. exporter replicas) to share a single db.
+// requests are authenticated with a bearer token, only read operations are allowed unless opts.Writable is set.
+// compare and swap is supported only if the db implements basedb.CompareAndSwapper
+func NewServer(logger *zap.Logger, db basedb.IDb, opts ServerOptions) (http.Handler, error) {
+	if len(opts.Token) == 0 {
+		return nil, errors.New("db server token is required")
+	}
+	mux := http.NewServeMux()
+	for _, op := range []string{opSet, opGet, opDelete, opGetAll, opCount, opSize, opRemoveAll, opCAS} {
+		op := op
+		mux.HandleFunc("/db/"+op, httpauth.Bearer(logger, opts.Token, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			var req remoteRequest
+			var res remoteResponse
+			status := http.StatusOK
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				status = http.StatusBadRequest
+				res.Error = err.Error()
+			} else if !opts.allowed(op, req.Prefix) {
+				status = http.StatusForbidden
+				res.Error = "operation is not allowed on a read only db server"
+			} else if err := serveOp(db, op, &req, &res); err != nil {
+				status = http.StatusInternalServerError
+				res.Error = err.Error()
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			if err := json.NewEncoder(w).Encode(&res); err != nil {
+				logger.Debug("could not write db response", zap.String("op", op), zap.Error(err))
+			}
+		}))
+	}
+	return mux, nil
+}
+
+// allowed returns true if the given operation is allowed on the given prefix
+func (opts ServerOptions) allowed(op string, prefix []byte) bool {
+	switch op {
+	case opGet, opGetAll, opCount, opSize:
+		return true
+	case opCAS:
+		if opts.Writable {
+			return true
+		}
+		for _, p := range opts.CASPrefixes {
+			if bytes.Equal(p, prefix) {
+				return true
+			}
+		}
+		return false
+	default:
+		return opts.Writable
+	}
+}
+
+func serveOp(db basedb.IDb, op string, req *remoteRequest, res *remoteResponse) error {
+	var err error
+	switch op {
+	case opSet:
+		err = db.Set(req.Prefix, req.Key, req.Value)
+	case opGet:
+		var obj basedb.Obj
+		obj, res.Found, err = db.Get(req.Prefix, req.Key)
+		res.Value = obj.Value
+	case opDelete:
+		err = db.Delete(req.Prefix, req.Key)
+	case opGetAll:
+		res.Objs, err = db.GetAllByCollection(req.Prefix)
+	case opCount:
+		res.Count, err = db.CountByCollection(req.Prefix)
+	case opSize:
+		res.Count, err = db.SizeByCollection(req.Prefix)
+	case opRemoveAll:
+		err = db.RemoveAllByCollection(req.Prefix)
+	case opCAS:
+		cas, ok := db.(basedb.CompareAndSwapper)
+		if !ok {
+			return errors.New("compare and swap is not supported")
+		}
+		res.Swapped, err = cas.CompareAndSwap(req.Prefix, req.Key, req.Old, req.Value)
+	}
+	return err
+}
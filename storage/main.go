@@ -15,6 +15,9 @@ func GetStorageFactory(options basedb.Options) (basedb.IDb, error) {
 	case "badger-memory":
 		db, err := kv.New(options)
 		return db, err
+	case "remote-db":
+		db, err := kv.NewRemote(options)
+		return db, err
 	}
 	return nil, fmt.Errorf("unsupported storage type passed")
 }
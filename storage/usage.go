@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsCollectionSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:storage:collection_size_bytes",
+		Help: "The estimated size of a db collection",
+	}, []string{"collection"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsCollectionSize); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// Collection is a named group of db prefixes, e.g. all the prefixes of the decided messages
+type Collection struct {
+	Name     string
+	Prefixes [][]byte
+}
+
+// CollectionUsage is the estimated disk usage of a collection
+type CollectionUsage struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+// DiskUsage returns the estimated disk usage of the given collections, the results are also reported as metrics
+func DiskUsage(db basedb.IDb, collections []Collection) ([]CollectionUsage, error) {
+	res := make([]CollectionUsage, 0, len(collections))
+	for _, c := range collections {
+		usage := CollectionUsage{Name: c.Name}
+		for _, prefix := range c.Prefixes {
+			size, err := db.SizeByCollection(prefix)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not get size of collection %s", c.Name)
+			}
+			usage.SizeBytes += size
+		}
+		metricsCollectionSize.WithLabelValues(c.Name).Set(float64(usage.SizeBytes))
+		res = append(res, usage)
+	}
+	return res, nil
+}
@@ -0,0 +1,157 @@
+package batchverifier
+
+import (
+	"context"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// DefaultInterval is the default max time a signature waits for its batch
+	DefaultInterval = 5 * time.Millisecond
+	// DefaultBatchSize is the default max number of signatures in a batch
+	DefaultBatchSize = 64
+)
+
+// Options defines the required parameters to create a verifier
+type Options struct {
+	Ctx    context.Context
+	Logger *zap.Logger
+	// Interval is the max time that a signature waits for its batch
+	Interval time.Duration `yaml:"Interval" env:"BATCH_VERIFIER_INTERVAL" env-default:"5ms" env-description:"Max time a signature waits for a batch verification"`
+	// BatchSize is the max number of signatures that are verified together
+	BatchSize int `yaml:"BatchSize" env:"BATCH_VERIFIER_SIZE" env-default:"64" env-description:"Max number of signatures in a batch verification"`
+}
+
+// Verifier verifies BLS signatures in batches, using a single aggregated pairing check per batch.
+// once a batch fails, each signature in it is verified individually
+type Verifier interface {
+	// Start starts to collect and verify batches, blocks until the context is done
+	Start()
+	// Verify blocks until the given signature was verified
+	Verify(sig *bls.Sign, pk *bls.PublicKey, root []byte) bool
+}
+
+type request struct {
+	sig  bls.Sign
+	pk   bls.PublicKey
+	root []byte
+	res  chan bool
+}
+
+type verifier struct {
+	ctx       context.Context
+	logger    *zap.Logger
+	interval  time.Duration
+	batchSize int
+	requests  chan *request
+}
+
+// New creates a new verifier
+func New(opts Options) Verifier {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	ctx := opts.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &verifier{
+		ctx:       ctx,
+		logger:    logger.With(zap.String("component", "batchVerifier")),
+		interval:  interval,
+		batchSize: batchSize,
+		requests:  make(chan *request, batchSize*4),
+	}
+}
+
+// Start starts to collect and verify batches
+func (v *verifier) Start() {
+	batch := make([]*request, 0, v.batchSize)
+	timer := time.NewTimer(v.interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-v.ctx.Done():
+			return
+		case req := <-v.requests:
+			if len(batch) == 0 {
+				resetTimer(timer, v.interval)
+			}
+			batch = append(batch, req)
+			if len(batch) < v.batchSize {
+				continue
+			}
+		case <-timer.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		verifyBatch(batch)
+		batch = make([]*request, 0, v.batchSize)
+	}
+}
+
+// Verify adds the signature to the next batch and waits for the result.
+// roots that can't be batched (not 32 bytes) are verified directly
+func (v *verifier) Verify(sig *bls.Sign, pk *bls.PublicKey, root []byte) bool {
+	if len(root) != 32 {
+		return sig.VerifyByte(pk, root)
+	}
+	req := &request{sig: *sig, pk: *pk, root: root, res: make(chan bool, 1)}
+	select {
+	case v.requests <- req:
+	case <-v.ctx.Done():
+		return sig.VerifyByte(pk, root)
+	}
+	select {
+	case res := <-req.res:
+		return res
+	case <-v.ctx.Done():
+		return sig.VerifyByte(pk, root)
+	}
+}
+
+// verifyBatch verifies the given batch with an aggregated pairing check,
+// falls back to individual verification if the batch is invalid
+func verifyBatch(batch []*request) {
+	if len(batch) > 1 {
+		sigs := make([]bls.Sign, len(batch))
+		pks := make([]bls.PublicKey, len(batch))
+		roots := make([]byte, 0, len(batch)*32)
+		for i, req := range batch {
+			sigs[i] = req.sig
+			pks[i] = req.pk
+			roots = append(roots, req.root...)
+		}
+		if bls.MultiVerify(sigs, pks, roots) {
+			for _, req := range batch {
+				req.res <- true
+			}
+			return
+		}
+	}
+	for _, req := range batch {
+		req.res <- req.sig.VerifyByte(&req.pk, req.root)
+	}
+}
+
+func resetTimer(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(d)
+}
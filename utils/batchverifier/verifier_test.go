@@ -0,0 +1,113 @@
+package batchverifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSig struct {
+	sig  *bls.Sign
+	pk   *bls.PublicKey
+	root []byte
+}
+
+func generateSigs(n int) []*testSig {
+	threshold.Init()
+	sigs := make([]*testSig, n)
+	for i := 0; i < n; i++ {
+		sk := &bls.SecretKey{}
+		sk.SetByCSPRNG()
+		root := sha256.Sum256([]byte(fmt.Sprintf("message %d", i)))
+		sigs[i] = &testSig{sig: sk.SignByte(root[:]), pk: sk.GetPublicKey(), root: root[:]}
+	}
+	return sigs
+}
+
+func verifyConcurrently(v Verifier, sigs []*testSig) []bool {
+	results := make([]bool, len(sigs))
+	var wg sync.WaitGroup
+	for i, s := range sigs {
+		wg.Add(1)
+		go func(i int, s *testSig) {
+			defer wg.Done()
+			results[i] = v.Verify(s.sig, s.pk, s.root)
+		}(i, s)
+	}
+	wg.Wait()
+	return results
+}
+
+func TestVerifier(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	v := New(Options{Ctx: ctx, Interval: 10 * time.Millisecond, BatchSize: 8})
+	go v.Start()
+
+	t.Run("valid signatures", func(t *testing.T) {
+		for _, res := range verifyConcurrently(v, generateSigs(20)) {
+			require.True(t, res)
+		}
+	})
+
+	t.Run("invalid signatures fall back to individual verification", func(t *testing.T) {
+		sigs := generateSigs(20)
+		// swap the public keys of two signatures
+		sigs[3].pk, sigs[11].pk = sigs[11].pk, sigs[3].pk
+		results := verifyConcurrently(v, sigs)
+		for i, res := range results {
+			require.Equal(t, i != 3 && i != 11, res)
+		}
+	})
+
+	t.Run("root that can't be batched", func(t *testing.T) {
+		sk := &bls.SecretKey{}
+		sk.SetByCSPRNG()
+		msg := []byte("short")
+		require.True(t, v.Verify(sk.SignByte(msg), sk.GetPublicKey(), msg))
+	})
+}
+
+func TestVerifier_Done(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	v := New(Options{Ctx: ctx})
+	cancel()
+	// verifier was not started, signatures are verified directly once the context is done
+	s := generateSigs(1)[0]
+	require.True(t, v.Verify(s.sig, s.pk, s.root))
+}
+
+func BenchmarkVerifyIndividually(b *testing.B) {
+	sigs := generateSigs(64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range sigs {
+			if !s.sig.VerifyByte(s.pk, s.root) {
+				b.Fatal("invalid signature")
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	sigs := generateSigs(64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch := make([]*request, len(sigs))
+		for j, s := range sigs {
+			batch[j] = &request{sig: *s.sig, pk: *s.pk, root: s.root, res: make(chan bool, 1)}
+		}
+		verifyBatch(batch)
+		for _, req := range batch {
+			if !<-req.res {
+				b.Fatal("invalid signature")
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package blscache
+
+import (
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/herumi/bls-eth-go-binary/bls"
+)
+
+// DefaultSize is the default max number of public keys in the cache
+const DefaultSize = 8192
+
+var defaultCache = New(DefaultSize)
+
+// PubKeyCache is a bounded LRU cache of deserialized bls public keys, keyed by their serialized bytes
+type PubKeyCache struct {
+	cache *lru.Cache
+}
+
+// New creates a new cache with the given size
+func New(size int) *PubKeyCache {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	// an error is returned only for non-positive sizes
+	cache, _ := lru.New(size)
+	return &PubKeyCache{cache: cache}
+}
+
+// Deserialize returns the public key of the given bytes, deserialized keys are kept in the cache.
+// a copy is returned as callers might mutate the key (e.g. aggregation)
+func (c *PubKeyCache) Deserialize(pkBytes []byte) (*bls.PublicKey, error) {
+	key := string(pkBytes)
+	if cached, ok := c.cache.Get(key); ok {
+		pk := *cached.(*bls.PublicKey)
+		return &pk, nil
+	}
+	pk := &bls.PublicKey{}
+	if err := pk.Deserialize(pkBytes); err != nil {
+		return nil, err
+	}
+	cp := *pk
+	c.cache.Add(key, &cp)
+	return pk, nil
+}
+
+// Len returns the number of cached keys
+func (c *PubKeyCache) Len() int {
+	return c.cache.Len()
+}
+
+// DeserializePubKey deserializes the given public key using the shared cache
+func DeserializePubKey(pkBytes []byte) (*bls.PublicKey, error) {
+	return defaultCache.Deserialize(pkBytes)
+}
@@ -0,0 +1,47 @@
+package blscache
+
+import (
+	"github.com/bloxapp/ssv/utils/threshold"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPubKeyCache(t *testing.T) {
+	threshold.Init()
+	c := New(2)
+
+	var keys [][]byte
+	for i := 0; i < 3; i++ {
+		sk := &bls.SecretKey{}
+		sk.SetByCSPRNG()
+		keys = append(keys, sk.GetPublicKey().Serialize())
+	}
+
+	pk, err := c.Deserialize(keys[0])
+	require.NoError(t, err)
+	require.Equal(t, keys[0], pk.Serialize())
+	require.Equal(t, 1, c.Len())
+
+	t.Run("cached key is a copy", func(t *testing.T) {
+		other, err := c.Deserialize(keys[1])
+		require.NoError(t, err)
+		pk.Add(other)
+
+		cached, err := c.Deserialize(keys[0])
+		require.NoError(t, err)
+		require.Equal(t, keys[0], cached.Serialize())
+	})
+
+	t.Run("bounded size", func(t *testing.T) {
+		_, err := c.Deserialize(keys[2])
+		require.NoError(t, err)
+		require.Equal(t, 2, c.Len())
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := c.Deserialize([]byte{1, 2, 3})
+		require.Error(t, err)
+		require.Equal(t, 2, c.Len())
+	})
+}
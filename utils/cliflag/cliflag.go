@@ -33,3 +33,8 @@ func AddPersistentIntFlag(c *cobra.Command, flag string, value uint64, descripti
 		_ = c.MarkPersistentFlagRequired(flag)
 	}
 }
+
+// AddPersistentBoolFlag adds a bool flag to the command
+func AddPersistentBoolFlag(c *cobra.Command, flag string, value bool, description string) {
+	c.PersistentFlags().Bool(flag, value, description)
+}
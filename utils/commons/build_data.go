@@ -5,6 +5,7 @@ import "fmt"
 var (
 	appName = "SSV-Node"
 	version = "latest"
+	commit  = ""
 )
 
 // SetBuildData updates local vars for build data
@@ -13,7 +14,17 @@ func SetBuildData(app string, ver string) {
 	version = ver
 }
 
+// SetCommit updates the commit hash of the build
+func SetCommit(c string) {
+	commit = c
+}
+
 // GetBuildData returns build data
 func GetBuildData() string {
 	return fmt.Sprintf("%s:%s", appName, version)
 }
+
+// GetCommit returns the commit hash of the build, empty if unknown
+func GetCommit() string {
+	return commit
+}
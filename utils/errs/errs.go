@@ -0,0 +1,89 @@
+package errs
+
+import (
+	"github.com/pkg/errors"
+)
+
+// error classes that are shared across network, consensus and exporter,
+// callers can branch on the class with errors.Is
+var (
+	// ErrNoPeers is the class of errors caused by missing peers
+	ErrNoPeers = errors.New("no peers")
+	// ErrInvalidSignature is the class of errors caused by signatures that could not be verified
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrStaleMessage is the class of errors caused by old or out of sequence messages
+	ErrStaleMessage = errors.New("stale message")
+	// ErrSyncTimeout is the class of errors caused by sync requests that timed out
+	ErrSyncTimeout = errors.New("sync timeout")
+	// ErrUnknownValidator is the class of errors caused by messages of an unexpected validator
+	ErrUnknownValidator = errors.New("unknown validator")
+	// ErrWrongCommittee is the class of errors caused by signers that are not part of the committee
+	ErrWrongCommittee = errors.New("wrong committee")
+	// ErrDecode is the class of errors caused by messages that could not be decoded
+	ErrDecode = errors.New("decode error")
+	// ErrMessageTooLarge is the class of errors caused by messages that exceed the max size
+	ErrMessageTooLarge = errors.New("message too large")
+)
+
+// classes are the known classes with their (metrics) labels
+var classes = []struct {
+	err   error
+	label string
+}{
+	{ErrNoPeers, "no_peers"},
+	{ErrInvalidSignature, "invalid_signature"},
+	{ErrStaleMessage, "stale_message"},
+	{ErrSyncTimeout, "sync_timeout"},
+	{ErrUnknownValidator, "unknown_validator"},
+	{ErrWrongCommittee, "wrong_committee"},
+	{ErrDecode, "decode_error"},
+	{ErrMessageTooLarge, "message_too_large"},
+}
+
+// classifiedError is an error that belongs to a class, while keeping its original message
+type classifiedError struct {
+	err   error
+	class error
+}
+
+// Error returns the message of the original error
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the original error
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// Cause returns the original error, to support errors.Cause
+func (e *classifiedError) Cause() error {
+	return e.err
+}
+
+// Is returns true if the target is the class of the error
+func (e *classifiedError) Is(target error) bool {
+	return target == e.class
+}
+
+// Mark marks the given error as belonging to the given class, the message of the error is kept as is
+func Mark(err error, class error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err, class: class}
+}
+
+// Label returns the label of the class of the given error, to be used in metrics and logs.
+// "unknown" is returned for errors w/o a known class, and an empty string for nil
+func Label(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, c := range classes {
+		if errors.Is(err, c.err) {
+			return c.label
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,36 @@
+package errs
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMark(t *testing.T) {
+	require.Nil(t, Mark(nil, ErrNoPeers))
+
+	original := errors.New("could not find peers")
+	err := Mark(original, ErrNoPeers)
+	require.EqualError(t, err, "could not find peers")
+	require.True(t, errors.Is(err, ErrNoPeers))
+	require.True(t, errors.Is(err, original))
+	require.False(t, errors.Is(err, ErrSyncTimeout))
+
+	// the class is kept when wrapped
+	wrapped := errors.Wrap(err, "failed to sync")
+	require.EqualError(t, wrapped, "failed to sync: could not find peers")
+	require.True(t, errors.Is(wrapped, ErrNoPeers))
+}
+
+func TestLabel(t *testing.T) {
+	require.Equal(t, "", Label(nil))
+	require.Equal(t, "unknown", Label(errors.New("some error")))
+	require.Equal(t, "no_peers", Label(ErrNoPeers))
+	require.Equal(t, "invalid_signature", Label(errors.Wrap(Mark(errors.New("x"), ErrInvalidSignature), "y")))
+	require.Equal(t, "stale_message", Label(Mark(errors.New("x"), ErrStaleMessage)))
+	require.Equal(t, "sync_timeout", Label(Mark(errors.New("x"), ErrSyncTimeout)))
+	require.Equal(t, "unknown_validator", Label(Mark(errors.New("x"), ErrUnknownValidator)))
+	require.Equal(t, "wrong_committee", Label(Mark(errors.New("x"), ErrWrongCommittee)))
+	require.Equal(t, "decode_error", Label(Mark(errors.New("x"), ErrDecode)))
+	require.Equal(t, "message_too_large", Label(Mark(errors.New("x"), ErrMessageTooLarge)))
+}
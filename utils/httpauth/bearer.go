@@ -0,0 +1,23 @@
+package httpauth
+
+import (
+	"crypto/subtle"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// Bearer wraps the given handler with bearer token authentication,
+// requests w/o the given token are rejected with 401
+func Bearer(logger *zap.Logger, token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		reqToken := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if len(token) == 0 || subtle.ConstantTimeCompare([]byte(reqToken), []byte(token)) != 1 {
+			logger.Warn("unauthorized request", zap.String("path", req.URL.Path),
+				zap.String("remote", req.RemoteAddr))
+			http.Error(res, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(res, req)
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
+	"github.com/bloxapp/ssv/storage/basedb"
 	gcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
+var (
+	networkKeyPrefix = []byte("network-")
+	networkKeyKey    = []byte("private-key")
+)
+
 // ECDSAPrivateKey extracts the ecdsa.PrivateKey from the given string or generate a new key
 func ECDSAPrivateKey(logger *zap.Logger, privateKey string) *ecdsa.PrivateKey {
 	var privKey *ecdsa.PrivateKey
@@ -39,3 +46,33 @@ func ECDSAPrivateKey(logger *zap.Logger, privateKey string) *ecdsa.PrivateKey {
 
 	return privKey
 }
+
+// NetworkPrivateKey returns the network key from config if provided, otherwise the key that was persisted in db.
+// a new key is generated and persisted on first start, so the peer id and ENR are kept across restarts
+func NetworkPrivateKey(logger *zap.Logger, db basedb.IDb, privateKey string) (*ecdsa.PrivateKey, error) {
+	if privateKey != "" {
+		return ECDSAPrivateKey(logger, privateKey), nil
+	}
+	obj, found, err := db.Get(networkKeyPrefix, networkKeyKey)
+	if found {
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read network key")
+		}
+		logger.Debug("using persisted network key")
+		return ECDSAPrivateKey(logger, string(obj.Value)), nil
+	}
+	privInterfaceKey, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate network key")
+	}
+	raw, err := privInterfaceKey.Raw()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode network key")
+	}
+	encoded := hex.EncodeToString(raw)
+	if err := db.Set(networkKeyPrefix, networkKeyKey, []byte(encoded)); err != nil {
+		return nil, errors.Wrap(err, "could not save network key")
+	}
+	logger.Info("generated a new network key")
+	return ECDSAPrivateKey(logger, encoded), nil
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"encoding/hex"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+	gcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestNetworkPrivateKey(t *testing.T) {
+	db, err := kv.New(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	generated, err := NetworkPrivateKey(zap.L(), db, "")
+	require.NoError(t, err)
+	require.NotNil(t, generated)
+
+	t.Run("reuse persisted key", func(t *testing.T) {
+		persisted, err := NetworkPrivateKey(zap.L(), db, "")
+		require.NoError(t, err)
+		require.Equal(t, gcrypto.FromECDSA(generated), gcrypto.FromECDSA(persisted))
+	})
+
+	t.Run("config overrides persisted key", func(t *testing.T) {
+		fromConfig := "b4ea3d1a9b2e3ef2d5b2c1d3c9b0b5c7dbb6b5c7f5e2c6b8e4a2d3f6b1c7e8d9"
+		sk, err := NetworkPrivateKey(zap.L(), db, fromConfig)
+		require.NoError(t, err)
+		require.Equal(t, fromConfig, hex.EncodeToString(gcrypto.FromECDSA(sk)))
+	})
+}
@@ -0,0 +1,168 @@
+package keystore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/pkg/errors"
+)
+
+const version = 1
+
+var (
+	// scryptN and scryptP are the parameters of the key derivation function
+	scryptN = keystore.StandardScryptN
+	scryptP = keystore.StandardScryptP
+)
+
+// Options holds the configuration of encrypted keys, a key that is loaded from a keystore
+// can't be configured in plaintext as well
+type Options struct {
+	OperatorKeyPath      string `yaml:"OperatorKeyPath" env:"OPERATOR_KEYSTORE" env-description:"Path to an encrypted keystore of the operator private key"`
+	NetworkKeyPath       string `yaml:"NetworkKeyPath" env:"NETWORK_KEYSTORE" env-description:"Path to an encrypted keystore of the network private key"`
+	Password             string `yaml:"Password" env:"KEYSTORE_PASSWORD" env-description:"Passphrase of the keystores"`
+	PasswordFile         string `yaml:"PasswordFile" env:"KEYSTORE_PASSWORD_FILE" env-description:"Path to a file that holds the passphrase of the keystores"`
+	VaultAddr            string `yaml:"VaultAddr" env:"VAULT_ADDR" env-description:"Address of the Vault server that holds the passphrase of the keystores"`
+	VaultToken           string `yaml:"VaultToken" env:"VAULT_TOKEN" env-description:"Token of the Vault server"`
+	VaultSecretPath      string `yaml:"VaultSecretPath" env:"KEYSTORE_VAULT_SECRET_PATH" env-description:"Path of the Vault kv secret that holds the passphrase, e.g. secret/data/ssv"`
+	VaultSecretField     string `yaml:"VaultSecretField" env:"KEYSTORE_VAULT_SECRET_FIELD" env-default:"passphrase" env-description:"Field of the passphrase in the Vault secret"`
+	PasswordCommand      string `yaml:"PasswordCommand" env:"KEYSTORE_PASSWORD_COMMAND" env-description:"Command that prints the passphrase of the keystores, runs only if AllowPasswordCommand is set"`
+	AllowPasswordCommand bool   `yaml:"AllowPasswordCommand" env:"KEYSTORE_ALLOW_PASSWORD_COMMAND" env-default:"false" env-description:"Allow running PasswordCommand with sh -c"`
+}
+
+// Enabled returns true if any key should be loaded from a keystore
+func (opts *Options) Enabled() bool {
+	return len(opts.OperatorKeyPath) > 0 || len(opts.NetworkKeyPath) > 0
+}
+
+// Validate fails if a key is configured both in plaintext and in a keystore
+func (opts *Options) Validate(operatorKey, networkKey string) error {
+	if len(opts.OperatorKeyPath) > 0 && len(operatorKey) > 0 {
+		return errors.New("operator key is configured both in plaintext and in a keystore")
+	}
+	if len(opts.NetworkKeyPath) > 0 && len(networkKey) > 0 {
+		return errors.New("network key is configured both in plaintext and in a keystore")
+	}
+	return nil
+}
+
+// Prompt asks the user for a passphrase
+type Prompt func(msg string) (string, error)
+
+// encryptedKey is the json structure of a keystore file
+type encryptedKey struct {
+	Version int                 `json:"version"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+}
+
+// Encrypt encrypts the given secret with the passphrase, returns the keystore json
+func Encrypt(secret []byte, password string) ([]byte, error) {
+	cj, err := keystore.EncryptDataV3(secret, []byte(password), scryptN, scryptP)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt secret")
+	}
+	return json.MarshalIndent(&encryptedKey{Version: version, Crypto: cj}, "", "  ")
+}
+
+// Decrypt decrypts the given keystore json with the passphrase
+func Decrypt(raw []byte, password string) ([]byte, error) {
+	var ek encryptedKey
+	if err := json.Unmarshal(raw, &ek); err != nil {
+		return nil, errors.Wrap(err, "could not parse keystore")
+	}
+	if ek.Version != version {
+		return nil, errors.Errorf("unsupported keystore version %d", ek.Version)
+	}
+	secret, err := keystore.DecryptDataV3(ek.Crypto, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt keystore")
+	}
+	return secret, nil
+}
+
+// WriteFile encrypts the given secret into a new keystore file that is readable only by the owner,
+// fails if the file already exists
+func WriteFile(path string, secret []byte, password string) error {
+	raw, err := Encrypt(secret, password)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not create keystore file")
+	}
+	if _, err := f.Write(raw); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "could not write keystore file")
+	}
+	return f.Close()
+}
+
+// LoadKeys decrypts the configured keystores, returns the operator and network keys in the format of the plaintext config.
+// an empty key is returned if its keystore is not configured
+func LoadKeys(opts Options, prompt Prompt) (string, string, error) {
+	password, err := opts.passphrase(prompt)
+	if err != nil {
+		return "", "", err
+	}
+	operatorKey, err := loadKey(opts.OperatorKeyPath, password)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not load operator key")
+	}
+	networkKey, err := loadKey(opts.NetworkKeyPath, password)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not load network key")
+	}
+	return operatorKey, networkKey, nil
+}
+
+func loadKey(path string, password string) (string, error) {
+	if len(path) == 0 {
+		return "", nil
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read keystore")
+	}
+	secret, err := Decrypt(raw, password)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// passphrase resolves the passphrase from config, a file, vault, a command (if allowed) or finally by prompting the user
+func (opts *Options) passphrase(prompt Prompt) (string, error) {
+	switch {
+	case len(opts.Password) > 0:
+		return opts.Password, nil
+	case len(opts.PasswordFile) > 0:
+		raw, err := ioutil.ReadFile(opts.PasswordFile)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read passphrase file")
+		}
+		return strings.TrimRight(string(raw), "\r\n"), nil
+	case len(opts.VaultSecretPath) > 0:
+		password, err := opts.vaultPassphrase()
+		if err != nil {
+			return "", errors.Wrap(err, "could not read passphrase from vault")
+		}
+		return password, nil
+	case len(opts.PasswordCommand) > 0:
+		if !opts.AllowPasswordCommand {
+			return "", errors.New("passphrase command is configured but AllowPasswordCommand is not set")
+		}
+		out, err := exec.Command("sh", "-c", opts.PasswordCommand).Output()
+		if err != nil {
+			return "", errors.Wrap(err, "could not run passphrase command")
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	case prompt != nil:
+		return prompt("Keystore passphrase: ")
+	}
+	return "", errors.New("keystore passphrase was not provided")
+}
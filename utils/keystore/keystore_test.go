@@ -0,0 +1,100 @@
+package keystore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	scryptN = keystore.LightScryptN
+	scryptP = keystore.LightScryptP
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	raw, err := Encrypt([]byte("secret"), "passphrase")
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "secret")
+
+	secret, err := Decrypt(raw, "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(secret))
+
+	_, err = Decrypt(raw, "wrong")
+	require.Error(t, err)
+	_, err = Decrypt([]byte(`{"version":2}`), "passphrase")
+	require.EqualError(t, err, "unsupported keystore version 2")
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operator.json")
+	require.NoError(t, WriteFile(path, []byte("secret"), "passphrase"))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	operatorKey, _, err := LoadKeys(Options{OperatorKeyPath: path, Password: "passphrase"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "secret", operatorKey)
+
+	// existing files are not overridden
+	require.Error(t, WriteFile(path, []byte("other"), "passphrase"))
+	operatorKey, _, err = LoadKeys(Options{OperatorKeyPath: path, Password: "passphrase"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "secret", operatorKey)
+}
+
+func TestLoadKeys(t *testing.T) {
+	dir := t.TempDir()
+	operatorKeyPath := filepath.Join(dir, "operator.json")
+	raw, err := Encrypt([]byte("LS0tLS1CRUdJTi"), "passphrase")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(operatorKeyPath, raw, 0600))
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("passphrase\n"), 0600))
+
+	tests := []struct {
+		name   string
+		opts   Options
+		prompt Prompt
+	}{
+		{"password", Options{Password: "passphrase"}, nil},
+		{"password file", Options{PasswordFile: passwordFile}, nil},
+		{"password command", Options{PasswordCommand: "echo passphrase", AllowPasswordCommand: true}, nil},
+		{"prompt", Options{}, func(string) (string, error) { return "passphrase", nil }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.opts.OperatorKeyPath = operatorKeyPath
+			require.True(t, test.opts.Enabled())
+			operatorKey, networkKey, err := LoadKeys(test.opts, test.prompt)
+			require.NoError(t, err)
+			require.Equal(t, "LS0tLS1CRUdJTi", operatorKey)
+			require.Empty(t, networkKey)
+		})
+	}
+
+	t.Run("password command is not allowed", func(t *testing.T) {
+		_, _, err := LoadKeys(Options{OperatorKeyPath: operatorKeyPath, PasswordCommand: "echo passphrase"}, nil)
+		require.EqualError(t, err, "passphrase command is configured but AllowPasswordCommand is not set")
+	})
+
+	t.Run("missing passphrase", func(t *testing.T) {
+		_, _, err := LoadKeys(Options{OperatorKeyPath: operatorKeyPath}, nil)
+		require.EqualError(t, err, "keystore passphrase was not provided")
+	})
+}
+
+func TestOptions_Validate(t *testing.T) {
+	opts := Options{OperatorKeyPath: "operator.json"}
+	require.NoError(t, opts.Validate("", "network key"))
+	require.EqualError(t, opts.Validate("operator key", ""), "operator key is configured both in plaintext and in a keystore")
+
+	opts = Options{NetworkKeyPath: "network.json"}
+	require.NoError(t, opts.Validate("operator key", ""))
+	require.EqualError(t, opts.Validate("", "network key"), "network key is configured both in plaintext and in a keystore")
+}
@@ -0,0 +1,55 @@
+package keystore
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultVaultSecretField = "passphrase"
+
+var vaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// vaultPassphrase reads the passphrase from a Vault kv secret, both kv v1 and v2 are supported
+func (opts *Options) vaultPassphrase() (string, error) {
+	if len(opts.VaultAddr) == 0 {
+		return "", errors.New("vault address is required")
+	}
+	url := strings.TrimRight(opts.VaultAddr, "/") + "/v1/" + strings.TrimLeft(opts.VaultSecretPath, "/")
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "could not create vault request")
+	}
+	req.Header.Set("X-Vault-Token", opts.VaultToken)
+	res, err := vaultClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "vault request failed")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", errors.Errorf("vault responded with status %d", res.StatusCode)
+	}
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&secret); err != nil {
+		return "", errors.Wrap(err, "could not parse vault secret")
+	}
+	data := secret.Data
+	// kv v2 nests the secret under data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	field := opts.VaultSecretField
+	if len(field) == 0 {
+		field = defaultVaultSecretField
+	}
+	password, ok := data[field].(string)
+	if !ok || len(password) == 0 {
+		return "", errors.Errorf("vault secret has no %s field", field)
+	}
+	return password, nil
+}
@@ -0,0 +1,49 @@
+package keystore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultPassphrase(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Vault-Token") != "token" {
+			res.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch req.URL.Path {
+		case "/v1/secret/data/ssv":
+			_, _ = res.Write([]byte(`{"data":{"data":{"passphrase":"v2-passphrase"},"metadata":{"version":1}}}`))
+		case "/v1/kv/ssv":
+			_, _ = res.Write([]byte(`{"data":{"keystore":"v1-passphrase"}}`))
+		default:
+			res.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	opts := Options{VaultAddr: server.URL + "/", VaultToken: "token", VaultSecretPath: "secret/data/ssv"}
+	password, err := opts.passphrase(nil)
+	require.NoError(t, err)
+	require.Equal(t, "v2-passphrase", password)
+
+	opts = Options{VaultAddr: server.URL, VaultToken: "token", VaultSecretPath: "/kv/ssv", VaultSecretField: "keystore"}
+	password, err = opts.passphrase(nil)
+	require.NoError(t, err)
+	require.Equal(t, "v1-passphrase", password)
+
+	opts.VaultSecretField = "passphrase"
+	_, err = opts.passphrase(nil)
+	require.EqualError(t, err, "could not read passphrase from vault: vault secret has no passphrase field")
+
+	opts.VaultToken = "wrong"
+	_, err = opts.passphrase(nil)
+	require.EqualError(t, err, "could not read passphrase from vault: vault responded with status 403")
+
+	opts.VaultAddr = ""
+	_, err = opts.passphrase(nil)
+	require.EqualError(t, err, "could not read passphrase from vault: vault address is required")
+}
@@ -0,0 +1,318 @@
+package loadgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/utils/format"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Options defines the parameters of the load generator
+type Options struct {
+	Logger  *zap.Logger
+	Network network.Network
+	// Registry is the storage that the validators are registered in (as shares) before publishing,
+	// so an exporter that uses the same db reads their messages. registration is skipped if nil
+	Registry validatorstorage.ICollection
+
+	Validators     int           `yaml:"Validators" env:"LOADGEN_VALIDATORS" env-default:"100" env-description:"number of fake validators that publish messages"`
+	CommitteeSize  int           `yaml:"CommitteeSize" env:"LOADGEN_COMMITTEE_SIZE" env-default:"4" env-description:"number of operators in the committee of each validator"`
+	Interval       time.Duration `yaml:"Interval" env:"LOADGEN_INTERVAL" env-default:"12s" env-description:"interval between ibft instances (duties) of a validator"`
+	Duration       time.Duration `yaml:"Duration" env:"LOADGEN_DURATION" env-description:"how long to generate traffic (0 runs until stopped)"`
+	Seed           string        `yaml:"Seed" env:"LOADGEN_SEED" env-description:"seed of the validators keys, so the same validators are generated on every run (random if empty)"`
+	ReportInterval time.Duration `yaml:"ReportInterval" env:"LOADGEN_REPORT_INTERVAL" env-default:"10s" env-description:"interval of stats reports"`
+}
+
+// Stats holds the counters of published messages
+type Stats struct {
+	Instances uint64
+	Sent      uint64
+	Failed    uint64
+}
+
+// fakeValidator is a generated validator with the secret keys of its committee
+type fakeValidator struct {
+	pk         *bls.PublicKey
+	identifier []byte
+	committee  map[uint64]*bls.SecretKey
+	seq        uint64
+}
+
+// Generator spins up fake validators that publish signed ibft and decided messages on their topics,
+// used to benchmark exporter throughput
+type Generator struct {
+	logger     *zap.Logger
+	network    network.Network
+	opts       Options
+	validators []*fakeValidator
+
+	stats Stats
+}
+
+// New creates a new generator with random (or seeded) validators and committees
+func New(opts Options) (*Generator, error) {
+	if opts.CommitteeSize < 1 {
+		opts.CommitteeSize = 4
+	}
+	validators := make([]*fakeValidator, opts.Validators)
+	for i := range validators {
+		v, err := newFakeValidator(opts.Seed, i, opts.CommitteeSize)
+		if err != nil {
+			return nil, err
+		}
+		validators[i] = v
+	}
+	return &Generator{
+		logger:     opts.Logger.With(zap.String("component", "loadgen")),
+		network:    opts.Network,
+		opts:       opts,
+		validators: validators,
+	}, nil
+}
+
+func newFakeValidator(seed string, index int, committeeSize int) (*fakeValidator, error) {
+	sk, err := newSecretKey(seed, fmt.Sprintf("%d", index))
+	if err != nil {
+		return nil, err
+	}
+	pk := sk.GetPublicKey()
+	committee := make(map[uint64]*bls.SecretKey, committeeSize)
+	for id := uint64(1); id <= uint64(committeeSize); id++ {
+		if committee[id], err = newSecretKey(seed, fmt.Sprintf("%d/%d", index, id)); err != nil {
+			return nil, err
+		}
+	}
+	return &fakeValidator{
+		pk:         pk,
+		identifier: []byte(format.IdentifierFormat(pk.Serialize(), beacon.RoleTypeAttester.String())),
+		committee:  committee,
+	}, nil
+}
+
+// newSecretKey returns a random key, or the key of the given path if a seed was provided
+func newSecretKey(seed string, path string) (*bls.SecretKey, error) {
+	sk := &bls.SecretKey{}
+	if len(seed) == 0 {
+		sk.SetByCSPRNG()
+		return sk, nil
+	}
+	h := sha256.Sum256([]byte(seed + "/" + path))
+	if err := sk.SetLittleEndianMod(h[:]); err != nil {
+		return nil, errors.Wrap(err, "could not derive secret key")
+	}
+	return sk, nil
+}
+
+// Register saves the shares of the validators in the registry, so their messages are read by an exporter
+// that uses the same db. NOTE: the exporter reads the registered validators once it (re)starts
+func (g *Generator) Register() error {
+	for _, v := range g.validators {
+		committee := make(map[uint64]*proto.Node, len(v.committee))
+		for id, sk := range v.committee {
+			committee[id] = &proto.Node{IbftId: id, Pk: sk.GetPublicKey().Serialize()}
+		}
+		if err := g.opts.Registry.SaveValidatorShare(&validatorstorage.Share{
+			NodeID:    1,
+			PublicKey: v.pk,
+			Committee: committee,
+		}); err != nil {
+			return errors.Wrap(err, "could not register validator")
+		}
+	}
+	g.logger.Info("validators were registered", zap.Int("validators", len(g.validators)))
+	return nil
+}
+
+// Stats returns a snapshot of the counters
+func (g *Generator) Stats() Stats {
+	return Stats{
+		Instances: atomic.LoadUint64(&g.stats.Instances),
+		Sent:      atomic.LoadUint64(&g.stats.Sent),
+		Failed:    atomic.LoadUint64(&g.stats.Failed),
+	}
+}
+
+// Start subscribes to the topics of the validators and generates traffic until the context is done
+// or the configured duration is over, blocking
+func (g *Generator) Start(ctx context.Context) error {
+	if g.opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.opts.Duration)
+		defer cancel()
+	}
+	if g.opts.Registry != nil {
+		if err := g.Register(); err != nil {
+			return err
+		}
+	}
+	for _, v := range g.validators {
+		if err := g.network.SubscribeToValidatorNetwork(v.pk); err != nil {
+			return errors.Wrap(err, "failed to subscribe validator topic")
+		}
+	}
+	g.logger.Info("generating traffic", zap.Int("validators", len(g.validators)),
+		zap.Int("committeeSize", g.opts.CommitteeSize), zap.Duration("interval", g.opts.Interval))
+
+	go g.report(ctx)
+
+	var wg sync.WaitGroup
+	for i, v := range g.validators {
+		wg.Add(1)
+		// spread the instances of the validators over the interval
+		delay := g.opts.Interval * time.Duration(i) / time.Duration(len(g.validators))
+		go func(v *fakeValidator, delay time.Duration) {
+			defer wg.Done()
+			g.run(ctx, v, delay)
+		}(v, delay)
+	}
+	wg.Wait()
+	g.logStats()
+	return nil
+}
+
+// run publishes an ibft instance of the given validator on every interval
+func (g *Generator) run(ctx context.Context, v *fakeValidator, delay time.Duration) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(delay):
+	}
+	ticker := time.NewTicker(g.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := g.publishInstance(v); err != nil {
+			g.logger.Debug("failed to publish instance", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishInstance publishes the messages of a single (successful) ibft instance:
+// prepare and commit of every operator followed by the decided message, i.e. a commit message signed by a quorum
+func (g *Generator) publishInstance(v *fakeValidator) error {
+	seq := v.seq
+	v.seq++
+	atomic.AddUint64(&g.stats.Instances, 1)
+
+	value, err := attestationValue(seq)
+	if err != nil {
+		return err
+	}
+	topic := v.pk.Serialize()
+	for _, t := range []proto.RoundState{proto.RoundState_Prepare, proto.RoundState_Commit} {
+		for id := range v.committee {
+			signed, err := v.sign(&proto.Message{
+				Type:      t,
+				Round:     1,
+				Lambda:    v.identifier,
+				SeqNumber: seq,
+				Value:     value,
+			}, id)
+			if err != nil {
+				return err
+			}
+			g.count(g.network.Broadcast(topic, signed))
+		}
+	}
+	decided, err := v.sign(&proto.Message{
+		Type:      proto.RoundState_Commit,
+		Round:     1,
+		Lambda:    v.identifier,
+		SeqNumber: seq,
+		Value:     value,
+	}, v.quorum()...)
+	if err != nil {
+		return err
+	}
+	g.count(g.network.BroadcastDecided(topic, decided))
+	return nil
+}
+
+func (g *Generator) count(err error) {
+	if err != nil {
+		atomic.AddUint64(&g.stats.Failed, 1)
+		metricsMessages.WithLabelValues("failed").Inc()
+		return
+	}
+	atomic.AddUint64(&g.stats.Sent, 1)
+	metricsMessages.WithLabelValues("sent").Inc()
+}
+
+func (g *Generator) report(ctx context.Context) {
+	if g.opts.ReportInterval == 0 {
+		return
+	}
+	ticker := time.NewTicker(g.opts.ReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.logStats()
+		}
+	}
+}
+
+func (g *Generator) logStats() {
+	stats := g.Stats()
+	g.logger.Info("loadgen stats", zap.Uint64("instances", stats.Instances),
+		zap.Uint64("sent", stats.Sent), zap.Uint64("failed", stats.Failed))
+}
+
+// quorum returns the ids of the first 2f+1 operators
+func (v *fakeValidator) quorum() []uint64 {
+	threshold := (len(v.committee)*2 + 2) / 3
+	ids := make([]uint64, threshold)
+	for i := range ids {
+		ids[i] = uint64(i + 1)
+	}
+	return ids
+}
+
+// sign signs the message with the shares of the given operators and aggregates the signatures
+func (v *fakeValidator) sign(msg *proto.Message, ids ...uint64) (*proto.SignedMessage, error) {
+	var agg *bls.Sign
+	for _, id := range ids {
+		sig, err := msg.Sign(v.committee[id])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to sign message")
+		}
+		if agg == nil {
+			agg = sig
+		} else {
+			agg.Add(sig)
+		}
+	}
+	return &proto.SignedMessage{
+		Message:   msg,
+		Signature: agg.Serialize(),
+		SignerIds: ids,
+	}, nil
+}
+
+// attestationValue returns a realistic (attestation data) value
+func attestationValue(seq uint64) ([]byte, error) {
+	data := &spec.AttestationData{
+		Slot:   spec.Slot(seq),
+		Source: &spec.Checkpoint{Epoch: spec.Epoch(seq / 32)},
+		Target: &spec.Checkpoint{Epoch: spec.Epoch(seq/32 + 1)},
+	}
+	return data.MarshalSSZ()
+}
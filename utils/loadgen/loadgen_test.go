@@ -0,0 +1,119 @@
+package loadgen
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/threshold"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGenerator(t *testing.T) {
+	threshold.Init()
+	network := local.NewLocalNetwork()
+	decidedCn := network.ReceivedDecidedChan()
+
+	g, err := New(Options{
+		Logger:        zap.L(),
+		Network:       network,
+		Validators:    2,
+		CommitteeSize: 4,
+		Interval:      50 * time.Millisecond,
+		Duration:      180 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	var lock sync.Mutex
+	decided := make([]*proto.SignedMessage, 0)
+	go func() {
+		for msg := range decidedCn {
+			lock.Lock()
+			decided = append(decided, msg)
+			lock.Unlock()
+		}
+	}()
+
+	require.NoError(t, g.Start(context.Background()))
+	stats := g.Stats()
+	require.GreaterOrEqual(t, stats.Instances, uint64(6))
+	// prepare + commit of every operator and a decided message per instance
+	require.Equal(t, stats.Instances*9, stats.Sent)
+	require.Zero(t, stats.Failed)
+
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	require.NotEmpty(t, decided)
+	for _, msg := range decided {
+		var v *fakeValidator
+		for _, fv := range g.validators {
+			if string(fv.identifier) == string(msg.Message.Lambda) {
+				v = fv
+			}
+		}
+		require.NotNil(t, v)
+		require.Equal(t, proto.RoundState_Commit, msg.Message.Type)
+		require.Equal(t, []uint64{1, 2, 3}, msg.SignerIds)
+		pks := make([]*bls.PublicKey, 0)
+		for _, id := range msg.SignerIds {
+			pks = append(pks, v.committee[id].GetPublicKey())
+		}
+		ok, err := msg.VerifyAggregatedSig(pks)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+}
+
+func TestGenerator_Register(t *testing.T) {
+	threshold.Init()
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	registry := validatorstorage.NewCollection(validatorstorage.CollectionOptions{DB: db, Logger: zap.L()})
+
+	opts := Options{
+		Logger:        zap.L(),
+		Registry:      registry,
+		Seed:          "test",
+		Validators:    2,
+		CommitteeSize: 4,
+	}
+	g, err := New(opts)
+	require.NoError(t, err)
+	require.NoError(t, g.Register())
+
+	// the same validators are generated with the same seed
+	g2, err := New(opts)
+	require.NoError(t, err)
+	for i, v := range g2.validators {
+		require.True(t, v.pk.IsEqual(g.validators[i].pk))
+		share, found, err := registry.GetValidatorShare(v.pk.Serialize())
+		require.NoError(t, err)
+		require.True(t, found)
+		// the registered share verifies the decided messages of the validator
+		value, err := attestationValue(1)
+		require.NoError(t, err)
+		decided, err := v.sign(&proto.Message{
+			Type:      proto.RoundState_Commit,
+			Round:     1,
+			Lambda:    v.identifier,
+			SeqNumber: 1,
+			Value:     value,
+		}, v.quorum()...)
+		require.NoError(t, err)
+		require.NoError(t, share.VerifySignedMessage(decided))
+	}
+}
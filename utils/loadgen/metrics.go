@@ -0,0 +1,20 @@
+package loadgen
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log"
+)
+
+var (
+	metricsMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:loadgen:messages",
+		Help: "Count messages that were published by the load generator",
+	}, []string{"status"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsMessages); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
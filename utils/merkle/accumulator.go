@@ -0,0 +1,78 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/pkg/errors"
+)
+
+// Depth is the depth of the accumulator tree, i.e. it holds up to 2^Depth leaves
+const Depth = 32
+
+// zeroHashes are the roots of empty subtrees by height
+var zeroHashes = func() [Depth + 1][32]byte {
+	var res [Depth + 1][32]byte
+	for h := 1; h <= Depth; h++ {
+		res[h] = hash(res[h-1], res[h-1])
+	}
+	return res
+}()
+
+// Accumulator is an append-only merkle tree that keeps only a single branch (the left siblings of the next leaf),
+// its root equals to the SSZ hash tree root of a list of 32 bytes leaves with a limit of 2^Depth
+type Accumulator struct {
+	Count  uint64     `json:"count"`
+	Branch [][32]byte `json:"branch"`
+}
+
+// Append adds the given leaf to the tree
+func (a *Accumulator) Append(leaf [32]byte) error {
+	if a.Count >= 1<<Depth {
+		return errors.New("accumulator is full")
+	}
+	if len(a.Branch) != Depth {
+		branch := make([][32]byte, Depth)
+		copy(branch, a.Branch)
+		a.Branch = branch
+	}
+	a.Count++
+	node := leaf
+	size := a.Count
+	for h := 0; h < Depth; h++ {
+		if size&1 == 1 {
+			a.Branch[h] = node
+			return nil
+		}
+		node = hash(a.Branch[h], node)
+		size >>= 1
+	}
+	return nil
+}
+
+// Root returns the root of the tree, mixed in with the number of leaves
+func (a *Accumulator) Root() [32]byte {
+	var node [32]byte
+	size := a.Count
+	for h := 0; h < Depth; h++ {
+		if size&1 == 1 {
+			node = hash(a.Branch[h], node)
+		} else {
+			node = hash(node, zeroHashes[h])
+		}
+		size >>= 1
+	}
+	var length [32]byte
+	binary.LittleEndian.PutUint64(length[:8], a.Count)
+	return hash(node, length)
+}
+
+// Leaf returns the leaf of a sequence number and a value, i.e. the hash of the sequence number chunk and the hash of the value
+func Leaf(seq uint64, value []byte) [32]byte {
+	var seqChunk [32]byte
+	binary.LittleEndian.PutUint64(seqChunk[:8], seq)
+	return hash(seqChunk, sha256.Sum256(value))
+}
+
+func hash(a, b [32]byte) [32]byte {
+	return sha256.Sum256(append(a[:], b[:]...))
+}
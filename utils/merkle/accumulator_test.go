@@ -0,0 +1,64 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// naiveRoot merkleizes the given leaves as a list with a limit of 2^Depth
+func naiveRoot(leaves [][32]byte) [32]byte {
+	layer := append([][32]byte{}, leaves...)
+	for h := 0; h < Depth; h++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[h])
+		}
+		next := make([][32]byte, 0, len(layer)/2)
+		for i := 0; i < len(layer); i += 2 {
+			next = append(next, hash(layer[i], layer[i+1]))
+		}
+		layer = next
+	}
+	root := zeroHashes[Depth]
+	if len(layer) > 0 {
+		root = layer[0]
+	}
+	var length [32]byte
+	binary.LittleEndian.PutUint64(length[:8], uint64(len(leaves)))
+	return hash(root, length)
+}
+
+func TestAccumulator(t *testing.T) {
+	acc := &Accumulator{}
+	require.Equal(t, naiveRoot(nil), acc.Root())
+
+	var leaves [][32]byte
+	for i := 0; i < 33; i++ {
+		leaf := sha256.Sum256([]byte{byte(i)})
+		leaves = append(leaves, leaf)
+		require.NoError(t, acc.Append(leaf))
+		require.EqualValues(t, i+1, acc.Count)
+		require.Equal(t, naiveRoot(leaves), acc.Root(), "leaves: %d", i+1)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		raw, err := json.Marshal(acc)
+		require.NoError(t, err)
+		restored := &Accumulator{}
+		require.NoError(t, json.Unmarshal(raw, restored))
+		require.Equal(t, acc.Root(), restored.Root())
+
+		leaf := Leaf(33, []byte("value"))
+		require.NoError(t, acc.Append(leaf))
+		require.NoError(t, restored.Append(leaf))
+		require.Equal(t, acc.Root(), restored.Root())
+	})
+}
+
+func TestLeaf(t *testing.T) {
+	require.Equal(t, Leaf(1, []byte("value")), Leaf(1, []byte("value")))
+	require.NotEqual(t, Leaf(1, []byte("value")), Leaf(2, []byte("value")))
+	require.NotEqual(t, Leaf(1, []byte("value")), Leaf(1, []byte("other")))
+}
@@ -1,8 +1,10 @@
 package rsaencryption
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
@@ -51,9 +53,37 @@ func DecodeKey(sk *rsa.PrivateKey, hashBase64 string) (string, error) {
 	return string(decryptedKey), nil
 }
 
+// EncodeKey encrypts the given key with the public key, returns the encrypted key as base64 string
+func EncodeKey(pk *rsa.PublicKey, key string) (string, error) {
+	encryptedKey, err := rsa.EncryptPKCS1v15(rand.Reader, pk, []byte(key))
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to encrypt key")
+	}
+	return base64.StdEncoding.EncodeToString(encryptedKey), nil
+}
+
+// SignData signs the sha256 hash of the given data with the private key
+func SignData(sk *rsa.PrivateKey, data []byte) ([]byte, error) {
+	hash := sha256.Sum256(data)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, sk, crypto.SHA256, hash[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to sign data")
+	}
+	return sig, nil
+}
+
+// VerifySignedData verifies a signature that was created with SignData
+func VerifySignedData(pk *rsa.PublicKey, data []byte, sig []byte) error {
+	hash := sha256.Sum256(data)
+	return rsa.VerifyPKCS1v15(pk, crypto.SHA256, hash[:], sig)
+}
+
 // ConvertPemToPrivateKey return rsa private key from secret key
 func ConvertPemToPrivateKey(skPem string) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode([]byte(skPem))
+	if block == nil {
+		return nil, errors.New("Failed to decode private key pem")
+	}
 	enc := x509.IsEncryptedPEMBlock(block)
 	b := block.Bytes
 	if enc {
@@ -70,6 +100,36 @@ func ConvertPemToPrivateKey(skPem string) (*rsa.PrivateKey, error) {
 	return parsedSk, nil
 }
 
+// ConvertEncodedPemToPrivateKey return rsa private key from a base64 encoded pem (the format of the operator key config)
+func ConvertEncodedPemToPrivateKey(skBase64 string) (*rsa.PrivateKey, error) {
+	skPem, err := base64.StdEncoding.DecodeString(skBase64)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode private key")
+	}
+	return ConvertPemToPrivateKey(string(skPem))
+}
+
+// ConvertEncodedPemToPublicKey return rsa public key from a base64 encoded pem (the format of ExtractPublicKey)
+func ConvertEncodedPemToPublicKey(pkBase64 string) (*rsa.PublicKey, error) {
+	pkPem, err := base64.StdEncoding.DecodeString(pkBase64)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode public key")
+	}
+	block, _ := pem.Decode(pkPem)
+	if block == nil {
+		return nil, errors.New("Failed to decode public key pem")
+	}
+	parsedPk, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse public key")
+	}
+	pk, ok := parsedPk.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("Failed to parse public key: not an rsa key")
+	}
+	return pk, nil
+}
+
 // PrivateKeyToByte converts privateKey to []byte
 func PrivateKeyToByte(sk *rsa.PrivateKey) []byte {
 	return pem.EncodeToMemory(
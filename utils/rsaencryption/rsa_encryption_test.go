@@ -1,6 +1,7 @@
 package rsaencryption
 
 import (
+	"encoding/base64"
 	testingspace "github.com/bloxapp/ssv/utils/rsaencryption/testingspace"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -42,3 +43,48 @@ func TestPrivateKeyToByte(t *testing.T) {
 	require.NotNil(t, b)
 	require.Greater(t, len(b), 1024)
 }
+
+func TestConvertEncodedPemToPrivateKey(t *testing.T) {
+	sk, err := ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+
+	decoded, err := ConvertEncodedPemToPrivateKey(base64.StdEncoding.EncodeToString(PrivateKeyToByte(sk)))
+	require.NoError(t, err)
+	require.True(t, sk.Equal(decoded))
+
+	_, err = ConvertEncodedPemToPrivateKey("not base64")
+	require.Error(t, err)
+	_, err = ConvertPemToPrivateKey("not a pem")
+	require.EqualError(t, err, "Failed to decode private key pem")
+}
+
+func TestEncodeKey(t *testing.T) {
+	sk, err := ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+	encrypted, err := EncodeKey(&sk.PublicKey, "626d6a13ae5b1458c310700941764f3841f279f9c8de5f4ba94abd01dc082517")
+	require.NoError(t, err)
+	key, err := DecodeKey(sk, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "626d6a13ae5b1458c310700941764f3841f279f9c8de5f4ba94abd01dc082517", key)
+}
+
+func TestSignData(t *testing.T) {
+	sk, err := ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+	sig, err := SignData(sk, []byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, VerifySignedData(&sk.PublicKey, []byte("data"), sig))
+	require.Error(t, VerifySignedData(&sk.PublicKey, []byte("other data"), sig))
+}
+
+func TestConvertEncodedPemToPublicKey(t *testing.T) {
+	sk, err := ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+	encoded, err := ExtractPublicKey(sk)
+	require.NoError(t, err)
+	pk, err := ConvertEncodedPemToPublicKey(encoded)
+	require.NoError(t, err)
+	require.True(t, sk.PublicKey.Equal(pk))
+	_, err = ConvertEncodedPemToPublicKey("not-base64!")
+	require.Error(t, err)
+}
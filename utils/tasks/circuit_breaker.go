@@ -0,0 +1,117 @@
+package tasks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCircuitOpen is returned when a call is rejected as the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int32
+
+const (
+	// CircuitClosed means that calls are allowed
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means that calls are rejected until the cooldown is over
+	CircuitOpen
+	// CircuitHalfOpen means that a single trial call is allowed, its result closes or re-opens the circuit
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops calls to a failing dependency after a number of consecutive failures,
+// calls are allowed again (a single trial) once the cooldown is over
+type CircuitBreaker struct {
+	lock sync.Mutex
+
+	threshold int
+	cooldown  time.Duration
+
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// onStateChange is called (under lock) when the state changes
+	onStateChange func(state CircuitState)
+
+	now func() time.Time
+}
+
+// NewCircuitBreaker creates a new circuit breaker, it never trips if threshold is not positive
+func NewCircuitBreaker(threshold int, cooldown time.Duration, onStateChange func(state CircuitState)) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold:     threshold,
+		cooldown:      cooldown,
+		onStateChange: onStateChange,
+		now:           time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen if the call should be rejected
+func (cb *CircuitBreaker) Allow() error {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return ErrCircuitOpen
+		}
+		cb.setStateNotSafe(CircuitHalfOpen)
+		return nil
+	case CircuitHalfOpen:
+		// a trial call is in progress
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+// Done reports the result of an allowed call
+func (cb *CircuitBreaker) Done(err error) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	if err == nil {
+		cb.failures = 0
+		cb.setStateNotSafe(CircuitClosed)
+		return
+	}
+	cb.failures++
+	if cb.state == CircuitHalfOpen || (cb.threshold > 0 && cb.failures >= cb.threshold) {
+		cb.openedAt = cb.now()
+		cb.setStateNotSafe(CircuitOpen)
+	}
+}
+
+// State returns the current state and the number of consecutive failures
+func (cb *CircuitBreaker) State() (CircuitState, int) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	return cb.state, cb.failures
+}
+
+func (cb *CircuitBreaker) setStateNotSafe(state CircuitState) {
+	if cb.state == state {
+		return
+	}
+	cb.state = state
+	if cb.onStateChange != nil {
+		cb.onStateChange(state)
+	}
+}
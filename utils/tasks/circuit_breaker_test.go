@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var states []CircuitState
+	cb := NewCircuitBreaker(2, time.Minute, func(state CircuitState) {
+		states = append(states, state)
+	})
+	now := time.Now()
+	cb.now = func() time.Time {
+		return now
+	}
+	testErr := errors.New("test-error")
+
+	require.NoError(t, cb.Allow())
+	cb.Done(testErr)
+	state, failures := cb.State()
+	require.Equal(t, CircuitClosed, state)
+	require.Equal(t, 1, failures)
+
+	// a success resets the failures
+	cb.Done(nil)
+	_, failures = cb.State()
+	require.Equal(t, 0, failures)
+
+	cb.Done(testErr)
+	cb.Done(testErr)
+	state, _ = cb.State()
+	require.Equal(t, CircuitOpen, state)
+	require.Equal(t, ErrCircuitOpen, cb.Allow())
+
+	// a single trial is allowed after the cooldown
+	now = now.Add(time.Minute)
+	require.NoError(t, cb.Allow())
+	require.Equal(t, ErrCircuitOpen, cb.Allow())
+	cb.Done(testErr)
+	state, _ = cb.State()
+	require.Equal(t, CircuitOpen, state)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, cb.Allow())
+	cb.Done(nil)
+	state, _ = cb.State()
+	require.Equal(t, CircuitClosed, state)
+	require.NoError(t, cb.Allow())
+
+	require.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}, states)
+
+	t.Run("disabled", func(t *testing.T) {
+		cb := NewCircuitBreaker(0, time.Minute, nil)
+		for i := 0; i < 10; i++ {
+			require.NoError(t, cb.Allow())
+			cb.Done(testErr)
+		}
+	})
+}
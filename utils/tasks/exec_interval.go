@@ -1,6 +1,9 @@
 package tasks
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // StoppableFunc represents a function that returns two boolean to help with its execution
 // stop will stop the interval, while continue will make the interval value to remain the same
@@ -28,3 +31,19 @@ func ExecWithInterval(fn StoppableFunc, start, limit time.Duration) {
 		}
 	}
 }
+
+// RunEvery runs the given function periodically in a goroutine, until the context is done
+func RunEvery(ctx context.Context, period time.Duration, fn func()) {
+	ticker := time.NewTicker(period)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fn()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -1,8 +1,10 @@
 package tasks
 
 import (
+	"context"
 	"github.com/stretchr/testify/require"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -38,3 +40,18 @@ func TestExecWithInterval(t *testing.T) {
 	wg.Wait()
 	require.Equal(t, 10, len(list))
 }
+
+func TestRunEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var count int64
+	RunEvery(ctx, 10*time.Millisecond, func() {
+		atomic.AddInt64(&count, 1)
+	})
+	time.Sleep(55 * time.Millisecond)
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	stopped := atomic.LoadInt64(&count)
+	require.GreaterOrEqual(t, stopped, int64(3))
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, stopped, atomic.LoadInt64(&count))
+}
@@ -1,10 +1,14 @@
 package tasks
 
 import (
+	"context"
 	"sync"
 	"time"
 )
 
+// deadLettersLimit is the max number of dead letters to keep, the oldest are dropped
+const deadLettersLimit = 100
+
 // Fn represents a function to execute
 type Fn func() error
 
@@ -14,8 +18,10 @@ type Queue interface {
 	Stop()
 	Queue(fn Fn)
 	QueueDistinct(Fn, string)
+	QueueWithPolicy(fn Fn, name string, policy RetryPolicy)
 	Wait()
 	Errors() []error
+	DeadLetters() []DeadLetter
 }
 
 // executionQueue implements Queue interface
@@ -31,6 +37,10 @@ type executionQueue struct {
 	errs []error
 
 	interval time.Duration
+
+	ctx         context.Context
+	cancel      context.CancelFunc
+	deadLetters []DeadLetter
 }
 
 // NewExecutionQueue creates a new instance
@@ -45,6 +55,7 @@ func NewExecutionQueue(interval time.Duration) Queue {
 		visited:  &sync.Map{},
 		errs:     []error{},
 		interval: interval,
+		ctx:      context.Background(),
 	}
 	return &q
 }
@@ -55,12 +66,17 @@ func (eq *executionQueue) Stop() {
 	defer eq.lock.Unlock()
 
 	eq.stopped = true
+	if eq.cancel != nil {
+		eq.cancel()
+	}
 }
 
 // Start starts to execute events
 func (eq *executionQueue) Start() {
 	eq.lock.Lock()
 	eq.stopped = false
+	// the context is used to stop retries once the queue is stopped
+	eq.ctx, eq.cancel = context.WithCancel(context.Background())
 	eq.lock.Unlock()
 
 	for {
@@ -102,6 +118,27 @@ func (eq *executionQueue) Queue(fn Fn) {
 	eq.waiting = append(eq.waiting, fn)
 }
 
+// QueueWithPolicy adds an event to the queue, the event is retried according to the given policy.
+// an event that fails all its attempts is added to the dead letters list
+func (eq *executionQueue) QueueWithPolicy(fn Fn, name string, policy RetryPolicy) {
+	eq.Queue(func() error {
+		eq.lock.RLock()
+		ctx := eq.ctx
+		eq.lock.RUnlock()
+
+		attempts, err := RetryWithPolicy(ctx, fn, policy)
+		if err != nil {
+			eq.addDeadLetter(DeadLetter{
+				Name:     name,
+				Error:    err.Error(),
+				Attempts: attempts,
+				FailedAt: time.Now(),
+			})
+		}
+		return err
+	})
+}
+
 // Wait waits until all events were executed
 func (eq *executionQueue) Wait() {
 	eq.wg.Wait()
@@ -115,6 +152,26 @@ func (eq *executionQueue) Errors() []error {
 	return eq.errs
 }
 
+// DeadLetters returns the events that failed after all attempts
+func (eq *executionQueue) DeadLetters() []DeadLetter {
+	eq.lock.RLock()
+	defer eq.lock.RUnlock()
+
+	res := make([]DeadLetter, len(eq.deadLetters))
+	copy(res, eq.deadLetters)
+	return res
+}
+
+func (eq *executionQueue) addDeadLetter(dl DeadLetter) {
+	eq.lock.Lock()
+	defer eq.lock.Unlock()
+
+	eq.deadLetters = append(eq.deadLetters, dl)
+	if len(eq.deadLetters) > deadLettersLimit {
+		eq.deadLetters = eq.deadLetters[len(eq.deadLetters)-deadLettersLimit:]
+	}
+}
+
 func (eq *executionQueue) exec(fn Fn) {
 	defer eq.wg.Done()
 
@@ -0,0 +1,93 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTimeout is returned when an attempt didn't complete within the timeout of the policy
+var ErrTimeout = errors.New("task timed out")
+
+// RetryPolicy describes how a task should be retried on failure
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, a single attempt is made if not positive
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it is doubled on every retry
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts (ignored if zero)
+	MaxBackoff time.Duration
+	// Timeout is the timeout of a single attempt (ignored if zero),
+	// note that a timed out function is not interrupted but its result is ignored
+	Timeout time.Duration
+}
+
+// delay returns the delay before the given retry (starting from 1)
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < retry; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// DeadLetter describes a task that failed after all the attempts of its policy
+type DeadLetter struct {
+	Name     string    `json:"name"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// RetryWithPolicy executes a function according to the given policy, until successful or the context is done.
+// returns the number of attempts and the last error
+func RetryWithPolicy(ctx context.Context, fn Fn, policy RetryPolicy) (int, error) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	var err error
+	attempts := 0
+	for attempts < maxAttempts {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return attempts, err
+			case <-time.After(policy.delay(attempts)):
+			}
+		}
+		attempts++
+		if err = execAttempt(fn, policy.Timeout); err == nil {
+			return attempts, nil
+		}
+	}
+	return attempts, err
+}
+
+func execAttempt(fn Fn, timeout time.Duration) error {
+	if timeout == 0 {
+		return fn()
+	}
+	c := make(chan error, 1)
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				c <- errors.Errorf("panic: %s", err)
+			}
+		}()
+		c <- fn()
+	}()
+	select {
+	case err := <-c:
+		return err
+	case <-time.After(timeout):
+		return ErrTimeout
+	}
+}
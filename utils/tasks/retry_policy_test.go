@@ -0,0 +1,85 @@
+package tasks
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryWithPolicy(t *testing.T) {
+	var i int64
+	inc := func() error {
+		if atomic.AddInt64(&i, 1) < 3 {
+			return errors.New("test-error")
+		}
+		return nil
+	}
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	attempts, err := RetryWithPolicy(context.Background(), inc, policy)
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+
+	atomic.StoreInt64(&i, 0)
+	policy.MaxAttempts = 2
+	attempts, err = RetryWithPolicy(context.Background(), inc, policy)
+	require.EqualError(t, err, "test-error")
+	require.Equal(t, 2, attempts)
+
+	t.Run("timeout", func(t *testing.T) {
+		attempts, err := RetryWithPolicy(context.Background(), func() error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}, RetryPolicy{MaxAttempts: 2, Timeout: 5 * time.Millisecond})
+		require.Equal(t, ErrTimeout, err)
+		require.Equal(t, 2, attempts)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		attempts, err := RetryWithPolicy(ctx, func() error {
+			return errors.New("test-error")
+		}, RetryPolicy{MaxAttempts: 5, Backoff: time.Second})
+		require.EqualError(t, err, "test-error")
+		require.Equal(t, 1, attempts)
+	})
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	require.Equal(t, time.Second, policy.delay(1))
+	require.Equal(t, 2*time.Second, policy.delay(2))
+	require.Equal(t, 4*time.Second, policy.delay(3))
+	require.Equal(t, 5*time.Second, policy.delay(4))
+	require.Equal(t, 5*time.Second, policy.delay(100))
+}
+
+func TestExecQueue_DeadLetters(t *testing.T) {
+	var i int64
+	q := NewExecutionQueue(1 * time.Millisecond)
+	go q.Start()
+	defer q.Stop()
+
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	q.QueueWithPolicy(func() error {
+		if atomic.AddInt64(&i, 1) < 2 {
+			return errors.New("transient-error")
+		}
+		return nil
+	}, "recovered", policy)
+	q.QueueWithPolicy(func() error {
+		return errors.New("test-error")
+	}, "failed", policy)
+	q.Wait()
+
+	require.Len(t, q.Errors(), 1)
+	deadLetters := q.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, "failed", deadLetters[0].Name)
+	require.Equal(t, "test-error", deadLetters[0].Error)
+	require.Equal(t, 3, deadLetters[0].Attempts)
+}
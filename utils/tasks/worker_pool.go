@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"sync"
+)
+
+// WorkerPool executes jobs on a fixed number of goroutines,
+// jobs are expected to be short so a worker won't be held for long
+type WorkerPool struct {
+	workers int
+	jobs    chan func()
+	stopped chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewWorkerPool creates a new pool with the given number of workers and size of the jobs buffer
+func NewWorkerPool(workers, queueSize int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &WorkerPool{
+		workers: workers,
+		jobs:    make(chan func(), queueSize),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Start spawns the workers
+func (wp *WorkerPool) Start() {
+	wp.startOnce.Do(func() {
+		wp.wg.Add(wp.workers)
+		for i := 0; i < wp.workers; i++ {
+			go wp.work()
+		}
+	})
+}
+
+// Stop stops the workers and waits for running jobs, jobs that are still in the buffer are dropped
+func (wp *WorkerPool) Stop() {
+	wp.stopOnce.Do(func() {
+		close(wp.stopped)
+	})
+	wp.wg.Wait()
+}
+
+// Queue adds a job, blocks while the buffer is full. returns false if the pool was stopped
+func (wp *WorkerPool) Queue(job func()) bool {
+	select {
+	case <-wp.stopped:
+		return false
+	default:
+	}
+	select {
+	case wp.jobs <- job:
+		return true
+	case <-wp.stopped:
+		return false
+	}
+}
+
+// Pending returns the number of jobs that are waiting for a worker
+func (wp *WorkerPool) Pending() int {
+	return len(wp.jobs)
+}
+
+func (wp *WorkerPool) work() {
+	defer wp.wg.Done()
+	for {
+		select {
+		case job := <-wp.jobs:
+			job()
+		case <-wp.stopped:
+			return
+		}
+	}
+}
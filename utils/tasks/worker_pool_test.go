@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	wp := NewWorkerPool(4, 8)
+	wp.Start()
+
+	var running, maxRunning, done int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		require.True(t, wp.Queue(func() {
+			defer wg.Done()
+			n := atomic.AddInt64(&running, 1)
+			for {
+				max := atomic.LoadInt64(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt64(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&running, -1)
+			atomic.AddInt64(&done, 1)
+		}))
+	}
+	wg.Wait()
+	require.Equal(t, int64(50), atomic.LoadInt64(&done))
+	require.LessOrEqual(t, atomic.LoadInt64(&maxRunning), int64(4))
+
+	wp.Stop()
+	require.False(t, wp.Queue(func() {}))
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ActivationWatcherLoop polls the status of validators that are not active yet, so their duties
+// are started as soon as they become active, regardless of the metadata update interval
+func (c *controller) ActivationWatcherLoop() {
+	interval := c.activationPollInterval
+	if interval == 0 {
+		interval = time.Minute
+	}
+	for {
+		time.Sleep(interval)
+		c.checkPendingActivations()
+	}
+}
+
+// checkPendingActivations fetches fresh metadata of validators that are pending activation
+func (c *controller) checkPendingActivations() {
+	pending := make(map[string]*beacon.ValidatorMetadata)
+	var pks [][]byte
+	_ = c.validatorsMap.ForEach(func(v *Validator) error {
+		if pendingActivation(v.Share.Metadata) {
+			pk := v.Share.PublicKey.SerializeToHexStr()
+			pending[pk] = v.Share.Metadata
+			pks = append(pks, v.Share.PublicKey.Serialize())
+		}
+		return nil
+	})
+	if len(pks) == 0 {
+		return
+	}
+	results, err := beacon.FetchValidatorsMetadata(c.beacon, pks)
+	if err != nil {
+		c.logger.Warn("could not fetch metadata of pending validators", zap.Error(err))
+		return
+	}
+	for pk, meta := range results {
+		prev, ok := pending[pk]
+		if !ok || (prev != nil && prev.Equals(meta)) {
+			continue
+		}
+		if !pendingActivation(meta) {
+			c.logger.Info("validator was activated", zap.String("pubKey", pk),
+				zap.Uint64("index", uint64(meta.Index)), zap.Uint64("activationEpoch", uint64(meta.ActivationEpoch)))
+		}
+		if err := c.UpdateValidatorMetadata(pk, meta); err != nil {
+			c.logger.Warn("could not update metadata of pending validator", zap.String("pubKey", pk), zap.Error(err))
+			continue
+		}
+		c.onMetadataUpdated(pk, meta)
+	}
+}
+
+// pendingActivation returns true if the validator is unknown to beacon or pending activation
+func pendingActivation(meta *beacon.ValidatorMetadata) bool {
+	return meta == nil || meta.Index == 0 || !meta.Activated()
+}
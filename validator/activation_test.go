@@ -0,0 +1,28 @@
+package validator
+
+import (
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPendingActivation(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    *beacon.ValidatorMetadata
+		pending bool
+	}{
+		{"no metadata", nil, true},
+		{"unknown", &beacon.ValidatorMetadata{Status: v1.ValidatorStateUnknown}, true},
+		{"pending queued", &beacon.ValidatorMetadata{Status: v1.ValidatorStatePendingQueued, Index: 10}, true},
+		{"active w/o index", &beacon.ValidatorMetadata{Status: v1.ValidatorStateActiveOngoing}, true},
+		{"active", &beacon.ValidatorMetadata{Status: v1.ValidatorStateActiveOngoing, Index: 10}, false},
+		{"exited", &beacon.ValidatorMetadata{Status: v1.ValidatorStateExitedUnslashed, Index: 10}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.pending, pendingActivation(test.meta))
+		})
+	}
+}
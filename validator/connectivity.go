@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+// CommitteeConnectivity describes the connectivity of the node to the committee peers of a validator
+type CommitteeConnectivity struct {
+	// ConnectedPeers is the amount of peers that are connected on the validator topic
+	ConnectedPeers int `json:"connectedPeers"`
+	// RequiredPeers is the amount of peers that are needed to reach a quorum, excluding this node
+	RequiredPeers int `json:"requiredPeers"`
+	// CheckedAt is the time of the last check, zero if the connectivity was not checked yet
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Ready returns true if the node is connected to enough committee peers to reach a quorum
+func (cc CommitteeConnectivity) Ready() bool {
+	return cc.ConnectedPeers >= cc.RequiredPeers
+}
+
+// Connectivity returns the result of the last connectivity check
+func (v *Validator) Connectivity() CommitteeConnectivity {
+	v.connectivityLock.RLock()
+	defer v.connectivityLock.RUnlock()
+
+	return v.connectivity
+}
+
+// checkConnectivity counts the connected peers on the validator topic and reports the result
+func (v *Validator) checkConnectivity() (CommitteeConnectivity, error) {
+	peers, err := v.network.AllPeers(v.Share.PublicKey.Serialize())
+	if err != nil {
+		return CommitteeConnectivity{}, errors.Wrap(err, "could not get peers of validator topic")
+	}
+	cc := CommitteeConnectivity{
+		ConnectedPeers: len(peers),
+		RequiredPeers:  v.Share.ThresholdSize() - 1,
+		CheckedAt:      time.Now(),
+	}
+
+	v.connectivityLock.Lock()
+	v.connectivity = cc
+	v.connectivityLock.Unlock()
+
+	reportCommitteeConnectivity(v.Share.PublicKey.SerializeToHexStr(), cc)
+	return cc, nil
+}
@@ -0,0 +1,29 @@
+package validator
+
+import (
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValidator_CheckConnectivity(t *testing.T) {
+	v := testingValidator(t, true, 4, []byte{1, 2, 3, 4})
+	net := local.NewLocalNetwork()
+	v.network = net
+	require.True(t, v.Connectivity().CheckedAt.IsZero())
+
+	// 4 operators -> a quorum of 3 requires 2 peers
+	net.CopyWithLocalNodeID(peer.ID("1")).ReceivedSyncMsgChan()
+	cc, err := v.checkConnectivity()
+	require.NoError(t, err)
+	require.Equal(t, 1, cc.ConnectedPeers)
+	require.Equal(t, 2, cc.RequiredPeers)
+	require.False(t, cc.Ready())
+
+	net.CopyWithLocalNodeID(peer.ID("2")).ReceivedSyncMsgChan()
+	cc, err = v.checkConnectivity()
+	require.NoError(t, err)
+	require.True(t, cc.Ready())
+	require.Equal(t, cc, v.Connectivity())
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/hex"
 	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/beacon/keymanager"
 	"github.com/bloxapp/ssv/eth1"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/bloxapp/ssv/operator/forks"
 	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/format"
 	"github.com/bloxapp/ssv/utils/tasks"
 	validatorstorage "github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
@@ -30,15 +33,43 @@ type ControllerOptions struct {
 	DB                         basedb.IDb
 	Logger                     *zap.Logger
 	SignatureCollectionTimeout time.Duration `yaml:"SignatureCollectionTimeout" env:"SIGNATURE_COLLECTION_TIMEOUT" env-default:"5s" env-description:"Timeout for signature collection after consensus"`
-	MetadataUpdateInterval     time.Duration `yaml:"MetadataUpdateInterval" env:"METADATA_UPDATE_INTERVAL" env-default:"12m" env-description:"Interval for updating metadata"`
+	MetadataUpdateInterval     time.Duration `yaml:"MetadataUpdateInterval" env:"METADATA_UPDATE_INTERVAL" env-default:"12m" env-description:"Min interval for updating metadata of all validators, extended if the beacon request budget doesn't allow it"`
+	MetadataRequestsPerMinute  int           `yaml:"MetadataRequestsPerMinute" env:"METADATA_REQUESTS_PER_MINUTE" env-default:"10" env-description:"Max beacon requests per minute of periodic metadata updates (0 is unlimited), the batches are spread evenly across the update interval"`
+	MsgQueueTTL                time.Duration `yaml:"MsgQueueTTL" env:"MSG_QUEUE_TTL" env-default:"10m" env-description:"Time messages are kept in the validator message queue"`
+	MsgQueueCleanupInterval    time.Duration `yaml:"MsgQueueCleanupInterval" env:"MSG_QUEUE_CLEANUP_INTERVAL" env-default:"11m" env-description:"Interval for removing expired messages from the validator message queue"`
+	MsgQueueDumpPath           string        `yaml:"MsgQueueDumpPath" env:"MSG_QUEUE_DUMP_PATH" env-description:"File that incoming consensus messages are appended to, in order to replay them for debugging (disabled if empty)"`
 	ETHNetwork                 *core.Network
 	Network                    network.Network
 	Beacon                     beacon.Beacon
 	Shares                     []validatorstorage.ShareOptions `yaml:"Shares"`
+	DutyRoles                  map[string][]string             `yaml:"DutyRoles" env:"DUTY_ROLES" env-description:"Duty roles to execute per validator public key (hex), all roles if not set"`
 	ShareEncryptionKeyProvider eth1.ShareEncryptionKeyProvider
 	CleanRegistryData          bool
 	Fork                       forks.Fork
 	KeyManager                 beacon.KeyManager
+	DryRun                     bool `yaml:"DryRun" env:"DRY_RUN" env-default:"false" env-description:"Run duties consensus w/o signing, broadcasting signatures or submitting to the beacon node"`
+
+	CommitteeConnectivityInterval time.Duration `yaml:"CommitteeConnectivityInterval" env:"COMMITTEE_CONNECTIVITY_INTERVAL" env-default:"1m" env-description:"Interval for checking the connectivity to the committee peers of each validator"`
+
+	SubscriptionsCheckInterval time.Duration `yaml:"SubscriptionsCheckInterval" env:"SUBSCRIPTIONS_CHECK_INTERVAL" env-default:"1m" env-description:"Interval for checking the subscriptions of validator topics, lost subscriptions are resubscribed"`
+
+	ActivationPollInterval time.Duration `yaml:"ActivationPollInterval" env:"ACTIVATION_POLL_INTERVAL" env-default:"1m" env-description:"Interval for checking the status of validators that are pending activation"`
+
+	DutyDeadlineSlots uint64 `yaml:"DutyDeadlineSlots" env:"DUTY_DEADLINE_SLOTS" env-default:"1" env-description:"Number of slots from the start of an attestation duty's slot after which its consensus is aborted as late (0 disables)"`
+
+	DutyTimeout time.Duration `yaml:"DutyTimeout" env:"DUTY_TIMEOUT" env-default:"1m" env-description:"Max duration of duties w/o a slot deadline (e.g. voluntary exit), pending network and beacon calls are canceled afterwards (0 disables)"`
+
+	AttestationInclusionCheck bool `yaml:"AttestationInclusionCheck" env:"ATTESTATION_INCLUSION_CHECK" env-default:"true" env-description:"Verify in subsequent blocks whether submitted attestations were included"`
+
+	StartupBatchSize     int           `yaml:"StartupBatchSize" env:"STARTUP_BATCH_SIZE" env-default:"100" env-description:"Number of validators that are started together on startup, active validators first and exited last (0 starts all at once)"`
+	StartupBatchInterval time.Duration `yaml:"StartupBatchInterval" env:"STARTUP_BATCH_INTERVAL" env-default:"1s" env-description:"Pause between batches of validators that are started on startup"`
+
+	ConsensusSeqWindow uint64 `yaml:"ConsensusSeqWindow" env:"CONSENSUS_SEQ_WINDOW" env-default:"32" env-description:"Max distance of an incoming consensus message seq from the highest decided, further messages are dropped, decided messages are not limited (0 disables)"`
+
+	// Hooks are called on lifecycle events of the validators, DefaultHooks is used if not provided
+	Hooks *HookRegistry
+	// Failover is used to skip duties while this instance is a standby, optional
+	Failover Failover
 }
 
 // IController represent the validators controller,
@@ -50,6 +81,26 @@ type IController interface {
 	GetValidatorsIndices() []spec.ValidatorIndex
 	GetValidator(pubKey string) (*Validator, bool)
 	UpdateValidatorMetaDataLoop()
+	ExitValidator(pubKey string) error
+	RemoveValidator(pubKey string, reason string) error
+	GetTombstones() ([]*validatorstorage.Tombstone, error)
+	DeleteTombstone(pubKey string) error
+	PauseValidator(pubKey string) error
+	ResumeValidator(pubKey string) error
+	GetMsgQueueStats(pubKey string) ([]msgqueue.IndexStats, error)
+	GetValidatorStatus(pubKey string) (*Status, error)
+	RefreshValidatorMetadata(pubKey string) (*beacon.ValidatorMetadata, error)
+	GetDeadLetters() []tasks.DeadLetter
+	CommitteeConnectivityLoop()
+	SubscriptionsReconcileLoop()
+	ActivationWatcherLoop()
+	AttestationInclusionLoop()
+}
+
+// Status holds the operational status of a validator
+type Status struct {
+	Paused    bool
+	Committee CommitteeConnectivity
 }
 
 // controller implements IController
@@ -59,6 +110,8 @@ type controller struct {
 	logger     *zap.Logger
 	beacon     beacon.Beacon
 	keyManager beacon.KeyManager
+	ethNetwork *core.Network
+	network    network.Network
 
 	shareEncryptionKeyProvider eth1.ShareEncryptionKeyProvider
 
@@ -66,6 +119,23 @@ type controller struct {
 
 	metadataUpdateQueue    tasks.Queue
 	metadataUpdateInterval time.Duration
+	// metadataRequestsPerMinute is the beacon request budget of periodic metadata updates
+	metadataRequestsPerMinute int
+
+	connectivityInterval time.Duration
+
+	subscriptionsCheckInterval time.Duration
+
+	activationPollInterval time.Duration
+
+	startupBatchSize     int
+	startupBatchInterval time.Duration
+
+	// dutyRoles are the configured duty roles per validator public key
+	dutyRoles map[string][]beacon.RoleType
+
+	// inclusionTracker verifies the inclusion of submitted attestations, nil if disabled
+	inclusionTracker *inclusionTracker
 }
 
 // NewController creates a new validator controller instance
@@ -75,6 +145,28 @@ func NewController(options ControllerOptions) IController {
 		Logger: options.Logger,
 	})
 
+	hooks := options.Hooks
+	if hooks == nil {
+		hooks = DefaultHooks
+	}
+
+	var dumper *msgqueue.Dumper
+	if len(options.MsgQueueDumpPath) > 0 {
+		d, err := msgqueue.NewFileDumper(options.MsgQueueDumpPath)
+		if err != nil {
+			options.Logger.Warn("could not create messages dumper", zap.Error(err))
+		} else {
+			options.Logger.Warn("consensus messages are dumped, the file grows w/o limits",
+				zap.String("path", options.MsgQueueDumpPath))
+			dumper = d
+		}
+	}
+
+	var tracker *inclusionTracker
+	if options.AttestationInclusionCheck && !options.DryRun {
+		tracker = newInclusionTracker(options.Logger, options.Beacon, collection, options.ETHNetwork)
+	}
+
 	ctrl := controller{
 		collection:                 collection,
 		context:                    options.Context,
@@ -82,6 +174,8 @@ func NewController(options ControllerOptions) IController {
 		beacon:                     options.Beacon,
 		shareEncryptionKeyProvider: options.ShareEncryptionKeyProvider,
 		keyManager:                 options.KeyManager,
+		ethNetwork:                 options.ETHNetwork,
+		network:                    options.Network,
 
 		validatorsMap: newValidatorsMap(options.Context, options.Logger, &Options{
 			Context:                    options.Context,
@@ -93,16 +187,48 @@ func NewController(options ControllerOptions) IController {
 			DB:                         options.DB,
 			Fork:                       options.Fork,
 			Signer:                     options.KeyManager,
+			MsgQueueOptions: msgqueue.Options{
+				TTL:             options.MsgQueueTTL,
+				CleanupInterval: options.MsgQueueCleanupInterval,
+				Dumper:          dumper,
+			},
+			DryRun:             options.DryRun,
+			DutyDeadlineSlots:  options.DutyDeadlineSlots,
+			DutyTimeout:        options.DutyTimeout,
+			ConsensusSeqWindow: options.ConsensusSeqWindow,
+			Hooks:              hooks,
+			Failover:           options.Failover,
+			inclusionTracker:   tracker,
 		}),
 
-		metadataUpdateQueue:    tasks.NewExecutionQueue(10 * time.Millisecond),
-		metadataUpdateInterval: options.MetadataUpdateInterval,
+		metadataUpdateQueue:       tasks.NewExecutionQueue(10 * time.Millisecond),
+		metadataUpdateInterval:    options.MetadataUpdateInterval,
+		metadataRequestsPerMinute: options.MetadataRequestsPerMinute,
+
+		connectivityInterval: options.CommitteeConnectivityInterval,
+
+		subscriptionsCheckInterval: options.SubscriptionsCheckInterval,
+
+		activationPollInterval: options.ActivationPollInterval,
+
+		startupBatchSize:     options.StartupBatchSize,
+		startupBatchInterval: options.StartupBatchInterval,
+
+		inclusionTracker: tracker,
+	}
+
+	if options.DryRun {
+		ctrl.logger.Warn("running in dry-run mode, duties won't be signed or submitted to the beacon node")
 	}
 
 	if err := ctrl.initShares(options); err != nil {
 		ctrl.logger.Panic("could not initialize shares", zap.Error(err))
 	}
 
+	if sa, ok := options.Network.(network.SyncAuthorizer); ok {
+		sa.UseCommitteeResolver(ctrl.committeeOf)
+	}
+
 	return &ctrl
 }
 
@@ -127,17 +253,245 @@ func (c *controller) ListenToEth1Events(feed *event.Feed) {
 func (c *controller) ProcessEth1Event(e eth1.Event) error {
 	if validatorAddedEvent, ok := e.Data.(eth1.ValidatorAddedEvent); ok {
 		pubKey := hex.EncodeToString(validatorAddedEvent.PublicKey)
-		if err := c.handleValidatorAddedEvent(validatorAddedEvent); err != nil {
+		if err := c.handleValidatorAddedEvent(validatorAddedEvent, e.Log.BlockNumber); err != nil {
 			c.logger.Error("could not process validator",
 				zap.String("pubkey", pubKey), zap.Error(err))
 			return err
 		}
 	}
+	if exitEvent, ok := e.Data.(eth1.ValidatorExitRequestedEvent); ok {
+		pubKey := hex.EncodeToString(exitEvent.PublicKey)
+		if err := c.handleValidatorExitRequestedEvent(exitEvent, e.Log.BlockNumber); err != nil {
+			c.logger.Error("could not exit validator",
+				zap.String("pubkey", pubKey), zap.Error(err))
+			return err
+		}
+	}
+	if removedEvent, ok := e.Data.(eth1.ValidatorRemovedEvent); ok {
+		pubKey := hex.EncodeToString(removedEvent.PublicKey)
+		if err := c.handleValidatorRemovedEvent(removedEvent, e.Log.BlockNumber); err != nil {
+			c.logger.Error("could not remove validator",
+				zap.String("pubkey", pubKey), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMsgQueueStats returns the stats of the message queue of the given validator
+func (c *controller) GetMsgQueueStats(pubKey string) ([]msgqueue.IndexStats, error) {
+	v, found := c.validatorsMap.GetValidator(pubKey)
+	if !found {
+		return nil, errors.New("validator not found")
+	}
+	return v.msgQueue.Stats(), nil
+}
+
+// GetValidatorStatus returns the operational status of the given validator
+func (c *controller) GetValidatorStatus(pubKey string) (*Status, error) {
+	v, found := c.validatorsMap.GetValidator(pubKey)
+	if !found {
+		return nil, errors.New("validator not found")
+	}
+	return &Status{
+		Paused:    v.IsPaused(),
+		Committee: v.Connectivity(),
+	}, nil
+}
+
+// RefreshValidatorMetadata fetches the metadata of the given validator from beacon immediately,
+// regardless of the update interval, and returns the fresh metadata
+func (c *controller) RefreshValidatorMetadata(pubKey string) (*beacon.ValidatorMetadata, error) {
+	v, found := c.validatorsMap.GetValidator(pubKey)
+	if !found {
+		return nil, errors.New("validator not found")
+	}
+	results, err := beacon.FetchValidatorsMetadata(c.beacon, [][]byte{v.Share.PublicKey.Serialize()})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch validator metadata")
+	}
+	meta, ok := results[pubKey]
+	if !ok {
+		return nil, errors.New("validator was not found on beacon")
+	}
+	if err := c.UpdateValidatorMetadata(pubKey, meta); err != nil {
+		return nil, errors.Wrap(err, "could not update validator metadata")
+	}
+	c.onMetadataUpdated(pubKey, meta)
+	return meta, nil
+}
+
+// GetDeadLetters returns the metadata update tasks that failed after all retries
+func (c *controller) GetDeadLetters() []tasks.DeadLetter {
+	return c.metadataUpdateQueue.DeadLetters()
+}
+
+// ExitValidator starts the flow of a threshold-signed voluntary exit for the given validator.
+// the exit is decided and signed by the committee, therefore it must be triggered by enough operators.
+func (c *controller) ExitValidator(pubKey string) error {
+	v, found := c.validatorsMap.GetValidator(pubKey)
+	if !found {
+		return errors.New("validator not found")
+	}
+	if !v.Share.HasMetadata() || v.Share.Metadata.Index == 0 {
+		return errors.New("could not exit validator: index not found")
+	}
+	if v.IsPaused() {
+		return errors.New("could not exit validator: validator is paused")
+	}
+	if v.isStandby() {
+		return errors.New("could not exit validator: this instance is a failover standby")
+	}
+	if v.Share.Metadata.Exiting() {
+		c.logger.Debug("validator is exiting already", zap.String("pubKey", pubKey))
+		return nil
+	}
+	if !v.IsStarted() {
+		return errors.New("could not exit validator: validator is not started")
+	}
+	slot := spec.Slot(c.ethNetwork.EstimatedCurrentSlot())
+	duty := &beacon.Duty{
+		Type:           beacon.RoleTypeVoluntaryExit,
+		Slot:           slot,
+		ValidatorIndex: v.Share.Metadata.Index,
+	}
+	copy(duty.PubKey[:], v.Share.PublicKey.Serialize())
+	c.logger.Info("starting voluntary exit", zap.String("pubKey", pubKey), zap.Uint64("slot", uint64(slot)))
+	go func() {
+		if err := v.ensureExitIbft(); err != nil {
+			c.logger.Error("could not exit validator", zap.String("pubKey", pubKey), zap.Error(err))
+			return
+		}
+		v.ExecuteDuty(c.context, uint64(slot), duty)
+	}()
+	return nil
+}
+
+// RemoveValidator removes the given validator, its share is replaced with a tombstone of the given reason
+// (defaults to manual) so it won't be added again by replayed contract events
+func (c *controller) RemoveValidator(pubKey string, reason string) error {
+	if len(reason) == 0 {
+		reason = validatorstorage.TombstoneReasonManual
+	}
+	return c.removeValidator(pubKey, &validatorstorage.Tombstone{Reason: reason})
+}
+
+// removeValidator stops the given validator and saves the given tombstone instead of its share.
+// the share key is kept in the key manager, as it holds the slashing protection data
+func (c *controller) removeValidator(pubKey string, tombstone *validatorstorage.Tombstone) error {
+	pk, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	if err := c.collection.RemoveValidatorShare(pk, tombstone); err != nil {
+		return errors.Wrap(err, "could not remove validator share")
+	}
+	c.invalidateKeyManager(pk)
+	if v, found := c.validatorsMap.RemoveValidator(pubKey); found {
+		// pausing makes sure that duties which were already scheduled won't be executed
+		v.SetPaused(true)
+		// closing stops the iBFT controllers, otherwise a re-added validator would run a second set of them
+		v.Close()
+		if err := c.network.UnSubscribeValidatorNetwork(v.Share.PublicKey); err != nil {
+			c.logger.Warn("could not unsubscribe from validator network", zap.String("pubKey", pubKey),
+				zap.Error(err))
+		}
+	}
+	c.reportValidatorsCount()
+	metricsValidatorStatus.DeleteLabelValues(pubKey)
+	c.logger.Info("validator was removed", zap.String("pubKey", pubKey), zap.String("reason", tombstone.Reason))
+	return nil
+}
+
+// GetTombstones returns the tombstones of the removed validators
+func (c *controller) GetTombstones() ([]*validatorstorage.Tombstone, error) {
+	return c.collection.GetAllTombstones()
+}
+
+// DeleteTombstone deletes the tombstone of the given (removed) validator,
+// so it can be added again by a ValidatorAdded contract event
+func (c *controller) DeleteTombstone(pubKey string) error {
+	pk, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	if _, found, err := c.collection.GetTombstone(pk); err != nil {
+		return errors.Wrap(err, "could not get tombstone")
+	} else if !found {
+		return errors.New("tombstone not found")
+	}
+	if err := c.collection.DeleteTombstone(pk); err != nil {
+		return errors.Wrap(err, "could not delete tombstone")
+	}
+	c.logger.Info("validator tombstone was deleted", zap.String("pubKey", pubKey))
+	return nil
+}
+
+// PauseValidator pauses duties execution of the given validator, the state is persisted across restarts
+func (c *controller) PauseValidator(pubKey string) error {
+	return c.setValidatorPaused(pubKey, true)
+}
+
+// ResumeValidator resumes duties execution of the given (paused) validator
+func (c *controller) ResumeValidator(pubKey string) error {
+	return c.setValidatorPaused(pubKey, false)
+}
+
+func (c *controller) setValidatorPaused(pubKey string, paused bool) error {
+	v, found := c.validatorsMap.GetValidator(pubKey)
+	if !found {
+		return errors.New("validator not found")
+	}
+	if err := c.collection.SaveValidatorPaused(v.Share.PublicKey.Serialize(), paused); err != nil {
+		return errors.Wrap(err, "could not save validator paused state")
+	}
+	v.SetPaused(paused)
+	if paused {
+		metricsValidatorStatus.WithLabelValues(pubKey).Set(float64(validatorStatusPaused))
+	} else {
+		ReportValidatorStatus(pubKey, v.Share.Metadata, c.logger)
+	}
+	c.logger.Info("validator paused state was updated", zap.String("pubKey", pubKey), zap.Bool("paused", paused))
 	return nil
 }
 
+// getOrCreateValidator returns the validator of the given share, the persisted paused state is loaded into new validators
+func (c *controller) getOrCreateValidator(share *validatorstorage.Share) *Validator {
+	if roles, ok := c.dutyRoles[share.PublicKey.SerializeToHexStr()]; ok {
+		share.Roles = roles
+	}
+	v := c.validatorsMap.GetOrCreateValidator(share)
+	c.reportValidatorsCount()
+	paused, err := c.collection.IsValidatorPaused(share.PublicKey.Serialize())
+	if err != nil {
+		c.logger.Warn("could not get validator paused state", zap.String("pubKey", share.PublicKey.SerializeToHexStr()),
+			zap.Error(err))
+	} else if paused {
+		c.logger.Info("validator is paused", zap.String("pubKey", share.PublicKey.SerializeToHexStr()))
+		v.SetPaused(true)
+	}
+	return v
+}
+
+// reportValidatorsCount sets the validators gauges from the validators map, called on every add, start and remove
+func (c *controller) reportValidatorsCount() {
+	total, started := 0, 0
+	_ = c.validatorsMap.ForEach(func(v *Validator) error {
+		total++
+		if v.IsStarted() {
+			started++
+		}
+		return nil
+	})
+	metricsValidatorsTotal.Set(float64(total))
+	metricsValidatorsStarted.Set(float64(started))
+}
+
 // StartValidators loads all persisted shares and setup the corresponding validators
 func (c *controller) StartValidators() {
+	if _, err := c.collection.MigrateShares(); err != nil {
+		c.logger.Error("failed to migrate shares", zap.Error(err))
+	}
 	shares, err := c.collection.GetAllValidatorsShare()
 	if err != nil {
 		c.logger.Fatal("failed to get validators shares", zap.Error(err))
@@ -149,34 +503,30 @@ func (c *controller) StartValidators() {
 	c.setupValidators(shares)
 }
 
-// setupValidators setup and starts validators from the given shares
+// setupValidators setup and starts validators from the given shares, according to their startup priority.
+// validators are created immediately while their start is batched in the background.
 // shares w/o validator's metadata won't start, but the metadata will be fetched and the validator will start afterwards
 func (c *controller) setupValidators(shares []*validatorstorage.Share) {
 	c.logger.Info("starting validators setup...", zap.Int("shares count", len(shares)))
-	var started int
-	var errs []error
+	sortByStartupPriority(shares)
+	var toStart []*Validator
 	var fetchMetadata [][]byte
 	for _, validatorShare := range shares {
-		v := c.validatorsMap.GetOrCreateValidator(validatorShare)
-		pk := v.Share.PublicKey.SerializeToHexStr()
-		logger := c.logger.With(zap.String("pubkey", pk))
+		v := c.getOrCreateValidator(validatorShare)
 		if !v.Share.HasMetadata() { // fetching index and status in case not exist
 			fetchMetadata = append(fetchMetadata, v.Share.PublicKey.Serialize())
-			logger.Warn("could not start validator as metadata not found")
+			c.logger.Warn("could not start validator as metadata not found",
+				zap.String("pubkey", v.Share.PublicKey.SerializeToHexStr()))
 			continue
 		}
-		if err := c.startValidator(v); err != nil {
-			logger.Warn("could not start validator", zap.Error(err))
-			errs = append(errs, err)
-		} else {
-			started++
-		}
+		toStart = append(toStart, v)
 	}
 	c.logger.Info("setup validators done", zap.Int("map size", c.validatorsMap.Size()),
-		zap.Int("failures", len(errs)), zap.Int("missing metadata", len(fetchMetadata)),
-		zap.Int("shares count", len(shares)), zap.Int("started", started))
+		zap.Int("missing metadata", len(fetchMetadata)), zap.Int("shares count", len(shares)),
+		zap.Int("to start", len(toStart)), zap.Int("batch size", c.startupBatchSize))
 
-	go c.updateValidatorsMetadata(fetchMetadata)
+	go c.startValidatorsInBatches(toStart)
+	c.fetchMissingMetadata(fetchMetadata)
 }
 
 // updateValidatorsMetadata updates metadata of the given public keys.
@@ -197,10 +547,15 @@ func (c *controller) UpdateValidatorMetadata(pk string, metadata *beacon.Validat
 		return errors.New("could not update empty metadata")
 	}
 	if v, found := c.validatorsMap.GetValidator(pk); found {
-		v.Share.Metadata = metadata
-		if err := c.collection.SaveValidatorShare(v.Share); err != nil {
-			return err
+		updated, err := c.collection.UpdateValidatorShare(v.Share.PublicKey.Serialize(), func(share *validatorstorage.Share) error {
+			share.Metadata = metadata
+			return nil
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not update share")
 		}
+		v.Share.Metadata = metadata
+		v.Share.Version = updated.Version
 		if err := c.startValidator(v); err != nil {
 			c.logger.Error("could not start validator", zap.Error(err))
 		}
@@ -213,6 +568,16 @@ func (c *controller) GetValidator(pubKey string) (*Validator, bool) {
 	return c.validatorsMap.GetValidator(pubKey)
 }
 
+// committeeOf returns the committee operators of the validator of the given identifier (lambda)
+func (c *controller) committeeOf(identifier []byte) ([]string, bool) {
+	pubKey, _ := format.IdentifierUnformat(string(identifier))
+	v, found := c.validatorsMap.GetValidator(pubKey)
+	if !found {
+		return nil, false
+	}
+	return v.Share.CommitteeOperators(), true
+}
+
 // GetValidatorsIndices returns a list of all the active validators indices
 // and fetch indices for missing once (could be first time attesting or non active once)
 func (c *controller) GetValidatorsIndices() []spec.ValidatorIndex {
@@ -235,10 +600,26 @@ func (c *controller) GetValidatorsIndices() []spec.ValidatorIndex {
 	return indices
 }
 
-// handleValidatorAddedEvent handles registry contract event for validator added
-func (c *controller) handleValidatorAddedEvent(validatorAddedEvent eth1.ValidatorAddedEvent) error {
+// handleValidatorAddedEvent handles registry contract event for validator added,
+// events that precede the removal of the validator are ignored
+func (c *controller) handleValidatorAddedEvent(validatorAddedEvent eth1.ValidatorAddedEvent, blockNumber uint64) error {
 	pubKey := hex.EncodeToString(validatorAddedEvent.PublicKey[:])
 	logger := c.logger.With(zap.String("pubKey", pubKey))
+	tombstone, found, err := c.collection.GetTombstone(validatorAddedEvent.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get validator tombstone")
+	}
+	if found {
+		if tombstone.Covers(blockNumber) {
+			logger.Debug("validator was removed, ignoring stale event", zap.Uint64("block", blockNumber),
+				zap.String("reason", tombstone.Reason))
+			return nil
+		}
+		// the validator was added again after it was removed
+		if err := c.collection.DeleteTombstone(validatorAddedEvent.PublicKey); err != nil {
+			return errors.Wrap(err, "could not delete validator tombstone")
+		}
+	}
 	// if exist -> do nothing
 	if _, ok := c.validatorsMap.GetValidator(pubKey); ok {
 		logger.Debug("validator was loaded already")
@@ -256,15 +637,16 @@ func (c *controller) handleValidatorAddedEvent(validatorAddedEvent eth1.Validato
 		if err != nil {
 			return errors.Wrap(err, "failed to create share")
 		}
-		if err := c.onNewShare(newValShare, share); err != nil {
+		newValShare.BlockNumber = blockNumber
+		validatorShare, err = c.onNewShare(newValShare, share)
+		if err != nil {
 			metricsValidatorStatus.WithLabelValues(pubKey).Set(float64(validatorStatusError))
 			return err
 		}
-		validatorShare = newValShare
 		logger.Debug("new validator share was created and saved")
 	}
 
-	v := c.validatorsMap.GetOrCreateValidator(validatorShare)
+	v := c.getOrCreateValidator(validatorShare)
 	if err := c.startValidator(v); err != nil {
 		logger.Warn("could not start validator", zap.Error(err))
 	}
@@ -272,6 +654,54 @@ func (c *controller) handleValidatorAddedEvent(validatorAddedEvent eth1.Validato
 	return nil
 }
 
+// handleValidatorExitRequestedEvent handles registry contract event for validator exit requested.
+// validators that don't belong to this operator and exit requests that were handled already (e.g. replayed by sync) are ignored.
+// a failed exit doesn't fail the event, as it would fail the eth1 sync, it can be triggered once again with the admin api
+func (c *controller) handleValidatorExitRequestedEvent(exitEvent eth1.ValidatorExitRequestedEvent, blockNumber uint64) error {
+	pubKey := hex.EncodeToString(exitEvent.PublicKey)
+	logger := c.logger.With(zap.String("pubKey", pubKey))
+	_, found, err := c.collection.GetValidatorShare(exitEvent.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get validator share")
+	}
+	if !found {
+		logger.Debug("ignoring exit request of unknown validator")
+		return nil
+	}
+	handledBlock, found, err := c.collection.GetExitRequest(exitEvent.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get exit request")
+	}
+	if found && blockNumber <= handledBlock {
+		logger.Debug("exit request was handled already", zap.Uint64("block", blockNumber))
+		return nil
+	}
+	if err := c.ExitValidator(pubKey); err != nil {
+		logger.Warn("could not exit validator", zap.Uint64("block", blockNumber), zap.Error(err))
+		return nil
+	}
+	if err := c.collection.SaveExitRequest(exitEvent.PublicKey, blockNumber); err != nil {
+		return errors.Wrap(err, "could not save exit request")
+	}
+	return nil
+}
+
+// handleValidatorRemovedEvent handles registry contract event for validator removed,
+// validators that don't belong to this operator are ignored
+func (c *controller) handleValidatorRemovedEvent(removedEvent eth1.ValidatorRemovedEvent, blockNumber uint64) error {
+	_, found, err := c.collection.GetValidatorShare(removedEvent.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get validator share")
+	}
+	if !found {
+		return nil
+	}
+	return c.removeValidator(hex.EncodeToString(removedEvent.PublicKey), &validatorstorage.Tombstone{
+		Reason:      validatorstorage.TombstoneReasonRemovedEvent,
+		BlockNumber: blockNumber,
+	})
+}
+
 // onMetadataUpdated is called when validator's metadata was updated
 func (c *controller) onMetadataUpdated(pk string, meta *beacon.ValidatorMetadata) {
 	if meta == nil {
@@ -296,8 +726,9 @@ func (c *controller) onMetadataUpdated(pk string, meta *beacon.ValidatorMetadata
 }
 
 // onNewShare is called when a new validator was added or during registry sync
-// if the validator was persisted already, this function won't be called
-func (c *controller) onNewShare(share *validatorstorage.Share, shareSecret *bls.SecretKey) error {
+// if the validator was persisted already, this function won't be called.
+// returns the stored share, which is the share of a newer event in case it was saved in the meantime
+func (c *controller) onNewShare(share *validatorstorage.Share, shareSecret *bls.SecretKey) (*validatorstorage.Share, error) {
 	logger := c.logger.With(zap.String("pubKey", share.PublicKey.SerializeToHexStr()))
 	if updated, err := updateShareMetadata(share, c.beacon); err != nil {
 		logger.Warn("could not add validator metadata", zap.Error(err))
@@ -308,20 +739,43 @@ func (c *controller) onNewShare(share *validatorstorage.Share, shareSecret *bls.
 	}
 	// save secret key
 	if err := c.keyManager.AddShare(shareSecret); err != nil {
-		return errors.Wrap(err, "failed to save new share secret to key manager")
+		return nil, errors.Wrap(err, "failed to save new share secret to key manager")
 	}
 	logger.Info("share was added successfully to key manager")
 
-	// save validator data
-	if err := c.collection.SaveValidatorShare(share); err != nil {
-		return errors.Wrap(err, "failed to save new share")
+	// save validator data, merged with a share that might have been saved in the meantime
+	saved, err := c.collection.MergeValidatorShare(share)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save new share")
+	}
+	c.invalidateKeyManager(share.PublicKey.Serialize())
+	if !saved {
+		logger.Debug("share of a newer event was saved already", zap.Uint64("block", share.BlockNumber))
+		stored, found, err := c.collection.GetValidatorShare(share.PublicKey.Serialize())
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get stored share")
+		}
+		if !found {
+			return nil, errors.New("stored share was not found")
+		}
+		return stored, nil
+	}
+	return share, nil
+}
+
+// invalidateKeyManager drops the cached key manager backend of the given share, as the share was saved or removed
+func (c *controller) invalidateKeyManager(pk []byte) {
+	if invalidator, ok := c.keyManager.(keymanager.Invalidator); ok {
+		invalidator.Invalidate(pk)
 	}
-	return nil
 }
 
 // startValidator will start the given validator if applicable
 func (c *controller) startValidator(v *Validator) error {
 	ReportValidatorStatus(v.Share.PublicKey.SerializeToHexStr(), v.Share.Metadata, c.logger)
+	if v.IsPaused() {
+		metricsValidatorStatus.WithLabelValues(v.Share.PublicKey.SerializeToHexStr()).Set(float64(validatorStatusPaused))
+	}
 	if !v.Share.HasMetadata() {
 		return errors.New("could not start validator: metadata not found")
 	}
@@ -332,27 +786,71 @@ func (c *controller) startValidator(v *Validator) error {
 		metricsValidatorStatus.WithLabelValues(v.Share.PublicKey.SerializeToHexStr()).Set(float64(validatorStatusError))
 		return errors.Wrap(err, "could not start validator")
 	}
+	c.reportValidatorsCount()
 	return nil
 }
 
-// UpdateValidatorMetaDataLoop updates metadata of validators in an interval
+// UpdateValidatorMetaDataLoop updates metadata of validators in rounds, the interval of a round is computed
+// from the number of validators and the beacon request budget (see beacon.NewMetadataSchedule)
 func (c *controller) UpdateValidatorMetaDataLoop() {
 	go c.metadataUpdateQueue.Start()
 
 	for {
-		time.Sleep(c.metadataUpdateInterval)
-
 		shares, err := c.collection.GetAllValidatorsShare()
 		if err != nil {
 			c.logger.Error("could not get validators shares for metadata update", zap.Error(err))
+			time.Sleep(c.metadataUpdateInterval)
 			continue
 		}
 		var pks [][]byte
 		for _, share := range shares {
 			pks = append(pks, share.PublicKey.Serialize())
 		}
-		c.logger.Debug("updating metadata in loop", zap.Int("shares count", len(shares)))
-		beacon.UpdateValidatorsMetadataBatch(pks, c.metadataUpdateQueue, c,
-			c.beacon, c.onMetadataUpdated, metadataBatchSize)
+		schedule := beacon.NewMetadataSchedule(len(pks), metadataBatchSize, c.metadataUpdateInterval,
+			c.metadataRequestsPerMinute)
+		c.logger.Debug("updating metadata in loop", zap.Int("shares count", len(shares)),
+			zap.Duration("interval", schedule.Interval), zap.Duration("batch interval", schedule.BatchInterval))
+		beacon.UpdateValidatorsMetadataRound(pks, c.metadataUpdateQueue, c,
+			c.beacon, c.onMetadataUpdated, metadataBatchSize, schedule)
+	}
+}
+
+// AttestationInclusionLoop verifies the inclusion of submitted attestations, returns immediately if disabled
+func (c *controller) AttestationInclusionLoop() {
+	if c.inclusionTracker == nil {
+		return
+	}
+	c.inclusionTracker.start()
+}
+
+// CommitteeConnectivityLoop checks the connectivity of validators to their committee peers in an interval
+func (c *controller) CommitteeConnectivityLoop() {
+	interval := c.connectivityInterval
+	if interval == 0 {
+		interval = time.Minute
+	}
+	for {
+		time.Sleep(interval)
+		_ = c.validatorsMap.ForEach(func(v *Validator) error {
+			c.checkCommitteeConnectivity(v)
+			return nil
+		})
+	}
+}
+
+// checkCommitteeConnectivity checks the connectivity of the given validator, changes of readiness are logged
+func (c *controller) checkCommitteeConnectivity(v *Validator) {
+	logger := c.logger.With(zap.String("pubKey", v.Share.PublicKey.SerializeToHexStr()))
+	prev := v.Connectivity()
+	cc, err := v.checkConnectivity()
+	if err != nil {
+		logger.Debug("could not check committee connectivity", zap.Error(err))
+		return
+	}
+	if !cc.Ready() && (prev.CheckedAt.IsZero() || prev.Ready()) {
+		logger.Warn("not enough committee peers are connected to reach a quorum",
+			zap.Int("connected", cc.ConnectedPeers), zap.Int("required", cc.RequiredPeers))
+	} else if cc.Ready() && !prev.CheckedAt.IsZero() && !prev.Ready() {
+		logger.Info("committee connectivity was restored", zap.Int("connected", cc.ConnectedPeers))
 	}
 }
@@ -1,10 +1,12 @@
 package validator
 
 import (
+	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"strings"
 )
 
 // initShares initializes shares, should be called upon creation of controller
@@ -19,9 +21,28 @@ func (c *controller) initShares(options ControllerOptions) error {
 	if len(options.Shares) > 0 {
 		c.loadSharesFromConfig(options.Shares)
 	}
+
+	dutyRoles, err := parseDutyRoles(options.DutyRoles)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse duty roles")
+	}
+	c.dutyRoles = dutyRoles
 	return nil
 }
 
+// parseDutyRoles parses the configured duty roles of each validator public key
+func parseDutyRoles(items map[string][]string) (map[string][]beacon.RoleType, error) {
+	dutyRoles := make(map[string][]beacon.RoleType, len(items))
+	for pk, names := range items {
+		roles, err := storage.ParseRoles(names)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid roles of %s", pk)
+		}
+		dutyRoles[strings.ToLower(strings.TrimPrefix(pk, "0x"))] = roles
+	}
+	return dutyRoles, nil
+}
+
 func (c *controller) loadSharesFromConfig(items []storage.ShareOptions) {
 	var addedValidators []string
 	if len(items) > 0 {
@@ -51,12 +72,14 @@ func (c *controller) loadShare(options storage.ShareOptions) (string, error) {
 		return "", errors.Wrap(err, "failed to set hex private key")
 	}
 	if share != nil {
-		if err := c.keyManager.AddShare(shareKey); err != nil {
-			return "", errors.Wrap(err, "could not save share key from share options")
-		}
+		// the share is saved first so the key manager resolves the backend of the share
 		if err := c.collection.SaveValidatorShare(share); err != nil {
 			return "", errors.Wrap(err, "could not save share from share options")
 		}
+		c.invalidateKeyManager(share.PublicKey.Serialize())
+		if err := c.keyManager.AddShare(shareKey); err != nil {
+			return "", errors.Wrap(err, "could not save share key from share options")
+		}
 		return options.PublicKey, err
 	}
 
@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"context"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/ibft"
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestController_RemoveValidator(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := validatorstorage.NewCollection(validatorstorage.CollectionOptions{DB: db, Logger: zap.L()})
+
+	v := testingValidator(t, true, 4, []byte{1, 2, 3, 4})
+	require.NoError(t, collection.SaveValidatorShare(v.Share))
+	pk := v.Share.PublicKey.SerializeToHexStr()
+	c := &controller{
+		logger:        zap.L(),
+		collection:    collection,
+		network:       local.NewLocalNetwork(),
+		validatorsMap: &validatorsMap{validatorsMap: map[string]*Validator{pk: v}},
+	}
+	atomic.StoreUint32(&v.started, 1)
+	c.reportValidatorsCount()
+	require.EqualValues(t, 1, testutil.ToFloat64(metricsValidatorsTotal))
+	require.EqualValues(t, 1, testutil.ToFloat64(metricsValidatorsStarted))
+
+	removed := eth1.Event{Log: types.Log{BlockNumber: 20}, Data: eth1.ValidatorRemovedEvent{PublicKey: v.Share.PublicKey.Serialize()}}
+	require.NoError(t, c.ProcessEth1Event(removed))
+	require.True(t, v.IsPaused())
+	// the iBFT controllers of a removed validator are closed
+	require.True(t, v.ibfts[beacon.RoleTypeAttester].(*testIBFT).closed)
+	_, found := c.GetValidator(pk)
+	require.False(t, found)
+	require.EqualValues(t, 0, testutil.ToFloat64(metricsValidatorsTotal))
+	require.EqualValues(t, 0, testutil.ToFloat64(metricsValidatorsStarted))
+	_, found, err = collection.GetValidatorShare(v.Share.PublicKey.Serialize())
+	require.NoError(t, err)
+	require.False(t, found)
+	tombstones, err := c.GetTombstones()
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+	require.Equal(t, validatorstorage.TombstoneReasonRemovedEvent, tombstones[0].Reason)
+	require.EqualValues(t, 20, tombstones[0].BlockNumber)
+
+	// a replayed event of a removed validator is ignored, the share won't be created again
+	added := eth1.Event{Log: types.Log{BlockNumber: 10}, Data: eth1.ValidatorAddedEvent{PublicKey: v.Share.PublicKey.Serialize()}}
+	require.NoError(t, c.ProcessEth1Event(added))
+	_, found = c.GetValidator(pk)
+	require.False(t, found)
+
+	// events of unknown validators are ignored
+	require.NoError(t, c.ProcessEth1Event(removed))
+	require.EqualError(t, c.RemoveValidator(pk, ""), "could not remove validator share: share not found")
+
+	// deleting the tombstone allows to add the validator again
+	require.NoError(t, c.DeleteTombstone(pk))
+	tombstones, err = c.GetTombstones()
+	require.NoError(t, err)
+	require.Len(t, tombstones, 0)
+	require.EqualError(t, c.DeleteTombstone(pk), "tombstone not found")
+}
+
+func TestController_ExitRequestedEvent(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := validatorstorage.NewCollection(validatorstorage.CollectionOptions{DB: db, Logger: zap.L()})
+
+	v := testingValidator(t, true, 4, []byte{1, 2, 3, 4})
+	v.Share.Metadata = &beacon.ValidatorMetadata{Index: 10, Status: v1.ValidatorStateExitedUnslashed}
+	pk := v.Share.PublicKey.Serialize()
+	c := &controller{
+		logger:        zap.L(),
+		collection:    collection,
+		network:       local.NewLocalNetwork(),
+		validatorsMap: &validatorsMap{validatorsMap: map[string]*Validator{v.Share.PublicKey.SerializeToHexStr(): v}},
+	}
+	exitEvent := func(block uint64) eth1.Event {
+		return eth1.Event{Log: types.Log{BlockNumber: block}, Data: eth1.ValidatorExitRequestedEvent{PublicKey: pk}}
+	}
+
+	// validators of other operators are ignored
+	require.NoError(t, c.ProcessEth1Event(exitEvent(20)))
+	_, found, err := collection.GetExitRequest(pk)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, collection.SaveValidatorShare(v.Share))
+	require.NoError(t, c.ProcessEth1Event(exitEvent(20)))
+	block, found, err := collection.GetExitRequest(pk)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 20, block)
+
+	// replayed requests are ignored
+	v.SetPaused(true)
+	require.NoError(t, c.ProcessEth1Event(exitEvent(20)))
+	// a failed exit doesn't fail the event and is not recorded
+	require.NoError(t, c.ProcessEth1Event(exitEvent(30)))
+	block, _, err = collection.GetExitRequest(pk)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, block)
+
+	v.SetPaused(false)
+	require.NoError(t, c.ProcessEth1Event(exitEvent(30)))
+	block, _, err = collection.GetExitRequest(pk)
+	require.NoError(t, err)
+	require.EqualValues(t, 30, block)
+}
+
+func TestController_ExitValidator(t *testing.T) {
+	v := testingValidator(t, true, 4, []byte{1, 2, 3, 4})
+	ethNetwork := core.PraterNetwork
+	v.ethNetwork = &ethNetwork
+	v.Share.Metadata = &beacon.ValidatorMetadata{Index: 10, Status: v1.ValidatorStateActiveOngoing}
+	exitIbft := &testIBFT{decided: false, identifier: []byte{5, 6, 7, 8}}
+	v.newIbft = func(role beacon.RoleType) ibft.Controller {
+		return exitIbft
+	}
+	failed := make(chan error, 1)
+	v.hooks = NewHookRegistry()
+	v.hooks.Register(Hooks{OnDutyFailed: func(share *validatorstorage.Share, duty *beacon.Duty, err error) {
+		failed <- err
+	}})
+	failover := &mockFailover{}
+	v.failover = failover
+	c := &controller{
+		context:       context.Background(),
+		logger:        zap.L(),
+		ethNetwork:    &ethNetwork,
+		validatorsMap: &validatorsMap{validatorsMap: map[string]*Validator{v.Share.PublicKey.SerializeToHexStr(): v}},
+	}
+	pk := v.Share.PublicKey.SerializeToHexStr()
+
+	require.EqualError(t, c.ExitValidator(pk), "could not exit validator: this instance is a failover standby")
+	failover.active = true
+	require.EqualError(t, c.ExitValidator(pk), "could not exit validator: validator is not started")
+	_, found := v.getIbft(beacon.RoleTypeVoluntaryExit)
+	require.False(t, found)
+
+	// the exit controller is created once the exit is requested
+	atomic.StoreUint32(&v.started, 1)
+	require.NoError(t, c.ExitValidator(pk))
+	select {
+	case err := <-failed:
+		require.EqualError(t, err, "instance did not decide")
+	case <-time.After(time.Second * 5):
+		t.Fatal("voluntary exit was not executed")
+	}
+	ib, found := v.getIbft(beacon.RoleTypeVoluntaryExit)
+	require.True(t, found)
+	require.Equal(t, exitIbft, ib)
+}
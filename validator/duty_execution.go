@@ -3,9 +3,10 @@ package validator
 import (
 	"context"
 	"encoding/hex"
-	ibftvalcheck "github.com/bloxapp/ssv/ibft/valcheck"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/pkg/errors"
+	types "github.com/prysmaticlabs/eth2-types"
 	"time"
 
 	"github.com/bloxapp/ssv/beacon"
@@ -15,7 +16,8 @@ import (
 )
 
 // waitForSignatureCollection waits for inbound signatures, collects them or times out if not.
-func (v *Validator) waitForSignatureCollection(logger *zap.Logger, identifier []byte, seqNumber uint64, sigRoot []byte, signaturesCount int, committiee map[uint64]*proto.Node) (map[uint64][]byte, error) {
+// the collection is aborted once the duty context is done
+func (v *Validator) waitForSignatureCollection(ctx context.Context, logger *zap.Logger, identifier []byte, seqNumber uint64, sigRoot []byte, signaturesCount int, committiee map[uint64]*proto.Node) (map[uint64][]byte, error) {
 	// Collect signatures from other nodes
 	// TODO - change signature count to min threshold
 	signatures := make(map[uint64][]byte, signaturesCount)
@@ -29,6 +31,10 @@ SigCollectionLoop:
 		case <-timer.C:
 			err = errors.Errorf("timed out waiting for post consensus signatures, received %d", len(signedIndxes))
 			break SigCollectionLoop
+		case <-ctx.Done():
+			timer.Stop()
+			err = errors.Wrapf(ctx.Err(), "duty was canceled while waiting for post consensus signatures, received %d", len(signedIndxes))
+			break SigCollectionLoop
 		default:
 			if msg := v.msgQueue.PopMessage(msgqueue.SigRoundIndexKey(identifier, seqNumber)); msg != nil {
 				if len(msg.SignedMessage.SignerIds) == 0 { // no KeyManager, empty sig
@@ -77,13 +83,31 @@ func (v *Validator) postConsensusDutyExecution(
 	signaturesCount int,
 	duty *beacon.Duty,
 ) error {
+	// signing would write slashing protection records and the signature would be submitted by other operators
+	if v.dryRun {
+		root, err := v.dutySigningRoot(decidedValue, duty)
+		if err != nil {
+			return errors.Wrap(err, "failed to compute signing root")
+		}
+		logger.Info("dry-run: skipping duty signing and submission", zap.String("root", hex.EncodeToString(root)))
+		return nil
+	}
+
+	// the instance might have become a standby during consensus
+	if v.isStandby() {
+		return errors.New("standby instance, the duty was not signed")
+	}
 	// sign input value and broadcast
 	sig, root, valueStruct, err := v.signDuty(decidedValue, duty)
 	if err != nil {
 		return errors.Wrap(err, "failed to sign input data")
 	}
 
-	identifier := v.ibfts[duty.Type].GetIdentifier()
+	ib, found := v.getIbft(duty.Type)
+	if !found {
+		return errors.Errorf("no ibft for this role [%s]", duty.Type.String())
+	}
+	identifier := ib.GetIdentifier()
 	// TODO - should we construct it better?
 	if err := v.network.BroadcastSignature(v.Share.PublicKey.Serialize(), &proto.SignedMessage{
 		Message: &proto.Message{
@@ -97,7 +121,7 @@ func (v *Validator) postConsensusDutyExecution(
 	}
 	logger.Info("broadcasting partial signature post consensus")
 
-	signatures, err := v.waitForSignatureCollection(logger, identifier, seqNumber, root, signaturesCount, v.Share.Committee)
+	signatures, err := v.waitForSignatureCollection(ctx, logger, identifier, seqNumber, root, signaturesCount, v.Share.Committee)
 
 	// clean queue for messages, we don't need them anymore.
 	v.msgQueue.PurgeIndexedMessages(msgqueue.SigRoundIndexKey(identifier, seqNumber))
@@ -108,25 +132,26 @@ func (v *Validator) postConsensusDutyExecution(
 	logger.Info("collected enough signature to reconstruct...", zap.Int("signatures", len(signatures)))
 
 	// Reconstruct signatures
-	if err := v.reconstructAndBroadcastSignature(logger, signatures, root, valueStruct, duty); err != nil {
+	if err := v.reconstructAndBroadcastSignature(ctx, logger, signatures, root, valueStruct, duty); err != nil {
 		return errors.Wrap(err, "failed to reconstruct and broadcast signature")
 	}
 	logger.Info("Successfully submitted role!")
+	v.hooks.signed(logger, v.Share, duty, valueStruct)
 	return nil
 }
 
-func (v *Validator) comeToConsensusOnInputValue(logger *zap.Logger, duty *beacon.Duty) (int, []byte, uint64, error) {
+func (v *Validator) comeToConsensusOnInputValue(ctx context.Context, logger *zap.Logger, duty *beacon.Duty, deadline time.Time) (int, []byte, uint64, error) {
 	var inputByts []byte
 	var err error
-	var valCheckInstance ibftvalcheck.ValueCheck
 
-	if _, ok := v.ibfts[duty.Type]; !ok {
+	ib, found := v.getIbft(duty.Type)
+	if !found {
 		return 0, nil, 0, errors.Errorf("no ibft for this role [%s]", duty.Type.String())
 	}
 
 	switch duty.Type {
 	case beacon.RoleTypeAttester:
-		attData, err := v.beacon.GetAttestationData(duty.Slot, duty.CommitteeIndex)
+		attData, err := v.beacon.GetAttestationData(ctx, duty.Slot, duty.CommitteeIndex)
 		if err != nil {
 			return 0, nil, 0, errors.Wrap(err, "failed to get attestation data")
 		}
@@ -135,7 +160,15 @@ func (v *Validator) comeToConsensusOnInputValue(logger *zap.Logger, duty *beacon
 		if err != nil {
 			return 0, nil, 0, errors.Errorf("failed to marshal on attestation role: %s", duty.Type.String())
 		}
-		valCheckInstance = v.valueCheck.AttestationSlashingProtector()
+	case beacon.RoleTypeVoluntaryExit:
+		exit := &spec.VoluntaryExit{
+			Epoch:          spec.Epoch(v.ethNetwork.EstimatedEpochAtSlot(types.Slot(duty.Slot))),
+			ValidatorIndex: duty.ValidatorIndex,
+		}
+		inputByts, err = exit.MarshalSSZ()
+		if err != nil {
+			return 0, nil, 0, errors.Errorf("failed to marshal on voluntary exit role: %s", duty.Type.String())
+		}
 	//case beacon.RoleTypeAggregator:
 	//	aggData, err := v.beacon.GetAggregationData(ctx, duty, v.Share.PublicKey, v.Share.ShareKey)
 	//	if err != nil {
@@ -171,23 +204,25 @@ func (v *Validator) comeToConsensusOnInputValue(logger *zap.Logger, duty *beacon
 	// do a value check before instance starts to prevent a dead lock if all SSV instances start
 	// an iBFT instance with values which are invalid which will result in them getting "stuck"
 	// in infinite round changes
+	valCheckInstance := v.valueCheck.ValueCheck(duty)
 	if err := valCheckInstance.Check(inputByts); err != nil {
 		return 0, nil, 0, errors.Wrap(err, "input value failed pre-consensus check")
 	}
 
 	// calculate next seq
-	seqNumber, err := v.ibfts[duty.Type].NextSeqNumber()
+	seqNumber, err := ib.NextSeqNumber()
 	if err != nil {
 		return 0, nil, 0, errors.Wrap(err, "failed to calculate next sequence number")
 	}
 
-	result, err := v.ibfts[duty.Type].StartInstance(ibft.ControllerStartInstanceOptions{
+	result, err := ib.StartInstance(ibft.ControllerStartInstanceOptions{
 		ValidatorShare:  v.Share,
 		Logger:          logger,
 		ValueCheck:      valCheckInstance,
 		SeqNumber:       seqNumber,
 		Value:           inputByts,
 		RequireMinPeers: true,
+		Deadline:        deadline,
 	})
 	if err != nil {
 		return 0, nil, 0, errors.WithMessage(err, "ibft instance failed")
@@ -202,23 +237,51 @@ func (v *Validator) comeToConsensusOnInputValue(logger *zap.Logger, duty *beacon
 	return len(result.Msg.SignerIds), result.Msg.Message.Value, seqNumber, nil
 }
 
-// ExecuteDuty executes the given duty
+// ExecuteDuty executes the given duty, network and beacon calls of the duty are canceled once it is no longer useful
 func (v *Validator) ExecuteDuty(ctx context.Context, slot uint64, duty *beacon.Duty) {
 	logger := v.logger.With(zap.Time("start_time", v.getSlotStartTime(slot)),
 		zap.Uint64("committee_index", uint64(duty.CommitteeIndex)),
 		zap.Uint64("slot", slot),
 		zap.String("duty_type", duty.Type.String()))
 
+	if v.IsPaused() {
+		logger.Info("validator is paused, skipping duty")
+		return
+	}
+	if v.isStandby() {
+		logger.Info("standby instance, skipping duty")
+		return
+	}
+
 	// reporting metrics
 	done := v.reportDutyExecutionMetrics(duty)
 	defer done()
 
+	deadline := v.dutyDeadline(duty)
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		logger.Warn("late duty, skipping", zap.Time("deadline", deadline))
+		metricsLateDuties.WithLabelValues(v.Share.PublicKey.SerializeToHexStr(), duty.Type.String()).Inc()
+		v.hooks.dutyFailed(logger, v.Share, duty, errors.Wrap(ibft.ErrDeadlineExceeded, "late duty"))
+		return
+	}
+
+	ctx, cancel := v.dutyContext(ctx, duty)
+	defer cancel()
+
 	logger.Debug("executing duty...")
-	signaturesCount, decidedValue, seqNumber, err := v.comeToConsensusOnInputValue(logger, duty)
+	signaturesCount, decidedValue, seqNumber, err := v.comeToConsensusOnInputValue(ctx, logger, duty, deadline)
+	if errors.Cause(err) == ibft.ErrDeadlineExceeded {
+		logger.Warn("late duty, consensus was not reached before the deadline", zap.Time("deadline", deadline))
+		metricsLateDuties.WithLabelValues(v.Share.PublicKey.SerializeToHexStr(), duty.Type.String()).Inc()
+		v.hooks.dutyFailed(logger, v.Share, duty, err)
+		return
+	}
 	if err != nil {
 		logger.Error("could not come to consensus", zap.Error(err))
+		v.hooks.dutyFailed(logger, v.Share, duty, err)
 		return
 	}
+	v.hooks.decided(logger, v.Share, duty, seqNumber, decidedValue)
 
 	// Here we ensure at least 2/3 instances got a val so we can sign data and broadcast signatures
 	logger.Info("GOT CONSENSUS", zap.Any("inputValueHex", hex.EncodeToString(decidedValue)))
@@ -233,6 +296,7 @@ func (v *Validator) ExecuteDuty(ctx context.Context, slot uint64, duty *beacon.D
 		duty,
 	); err != nil {
 		logger.Error("could not execute duty", zap.Error(err))
+		v.hooks.dutyFailed(logger, v.Share, duty, err)
 		return
 	}
 }
@@ -3,8 +3,10 @@ package validator
 import (
 	"context"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -76,7 +78,7 @@ func TestConsensusOnInputValue(t *testing.T) {
 			&spec.AttestationData{
 				Slot: 100,
 			},
-			"input value failed pre-consensus check: TEST - failed on slot 100",
+			"input value failed pre-consensus check: attestation data slot 100 doesn't match duty slot 0",
 		},
 	}
 
@@ -100,7 +102,7 @@ func TestConsensusOnInputValue(t *testing.T) {
 				ValidatorCommitteeIndex: 0,
 			}
 
-			signaturesCount, decidedByts, _, err := node.comeToConsensusOnInputValue(node.logger, duty)
+			signaturesCount, decidedByts, _, err := node.comeToConsensusOnInputValue(context.Background(), node.logger, duty, time.Time{})
 			if !test.decided {
 				require.EqualError(t, err, test.expectedError)
 				return
@@ -207,3 +209,224 @@ func TestPostConsensusSignatureAndAggregation(t *testing.T) {
 		})
 	}
 }
+
+func TestVoluntaryExitExecution(t *testing.T) {
+	identifier := []byte("voluntary-exit-identifier")
+	validator := testingValidator(t, true, 3, _byteArray("6139636633363061613135666231643164333065653262353738646335383834383233633139363631383836616538623839323737356363623362643936623764373334353536396132616130623134653464303135633534613661306335345f4154544553544552"))
+	validator.ibfts[beacon.RoleTypeVoluntaryExit] = &testIBFT{decided: true, signaturesCount: 3, identifier: identifier}
+	ethNetwork := core.PraterNetwork
+	validator.ethNetwork = &ethNetwork
+	var signed *beacon.DutyData
+	validator.hooks = NewHookRegistry()
+	validator.hooks.Register(Hooks{OnSigned: func(share *storage.Share, duty *beacon.Duty, data *beacon.DutyData) {
+		signed = data
+	}})
+	// wait for for listeners to spin up
+	time.Sleep(time.Millisecond * 100)
+
+	duty := &beacon.Duty{
+		Type:           beacon.RoleTypeVoluntaryExit,
+		Slot:           spec.Slot(ethNetwork.SlotsPerEpoch() * 2),
+		ValidatorIndex: 10,
+	}
+
+	signaturesCount, decidedByts, seqNumber, err := validator.comeToConsensusOnInputValue(context.Background(), validator.logger, duty, time.Time{})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, signaturesCount)
+	exit := &spec.VoluntaryExit{}
+	require.NoError(t, exit.UnmarshalSSZ(decidedByts))
+	require.EqualValues(t, 2, exit.Epoch)
+	require.EqualValues(t, 10, exit.ValidatorIndex)
+
+	// send sigs
+	for i := 0; i < 3; i++ {
+		sk := &bls.SecretKey{}
+		require.NoError(t, sk.Deserialize(refSplitShares[i]))
+		require.NoError(t, validator.network.BroadcastSignature(nil, &proto.SignedMessage{
+			Message: &proto.Message{
+				Lambda:    identifier,
+				SeqNumber: seqNumber,
+			},
+			Signature: sk.SignByte(refSigRoot).Serialize(),
+			SignerIds: []uint64{uint64(i + 1)},
+		}))
+	}
+
+	require.NoError(t, validator.postConsensusDutyExecution(context.Background(), validator.logger, seqNumber, decidedByts, signaturesCount, duty))
+	submitted := validator.beacon.(*testBeacon).LastSubmittedVoluntaryExit
+	require.NotNil(t, submitted)
+	require.Equal(t, exit, submitted.Message)
+	sig := &bls.Sign{}
+	require.NoError(t, sig.Deserialize(append([]byte{}, submitted.Signature[:]...)))
+	require.True(t, sig.VerifyByte(validator.Share.PublicKey, refSigRoot))
+	require.NotNil(t, signed)
+	require.Equal(t, submitted, signed.GetVoluntaryExit())
+}
+
+func TestVoluntaryExitExecution_DryRun(t *testing.T) {
+	identifier := []byte("voluntary-exit-identifier")
+	validator := testingValidator(t, true, 3, _byteArray("6139636633363061613135666231643164333065653262353738646335383834383233633139363631383836616538623839323737356363623362643936623764373334353536396132616130623134653464303135633534613661306335345f4154544553544552"))
+	validator.ibfts[beacon.RoleTypeVoluntaryExit] = &testIBFT{decided: true, signaturesCount: 3, identifier: identifier}
+	validator.dryRun = true
+	net := validator.network.(*signatureCountingNetwork)
+	ethNetwork := core.PraterNetwork
+	validator.ethNetwork = &ethNetwork
+	var signed bool
+	validator.hooks = NewHookRegistry()
+	validator.hooks.Register(Hooks{OnSigned: func(share *storage.Share, duty *beacon.Duty, data *beacon.DutyData) {
+		signed = true
+	}})
+
+	duty := &beacon.Duty{
+		Type:           beacon.RoleTypeVoluntaryExit,
+		Slot:           spec.Slot(ethNetwork.SlotsPerEpoch() * 2),
+		ValidatorIndex: 10,
+	}
+
+	signaturesCount, decidedByts, seqNumber, err := validator.comeToConsensusOnInputValue(context.Background(), validator.logger, duty, time.Time{})
+	require.NoError(t, err)
+
+	// the decided value is not signed, broadcasted or submitted
+	require.NoError(t, validator.postConsensusDutyExecution(context.Background(), validator.logger, seqNumber, decidedByts, signaturesCount, duty))
+	require.Equal(t, 0, validator.beacon.(*testBeacon).Signed)
+	require.Equal(t, 0, net.signatures)
+	require.Nil(t, validator.beacon.(*testBeacon).LastSubmittedVoluntaryExit)
+	require.False(t, signed)
+}
+
+func TestAttestationExecution_DryRun(t *testing.T) {
+	validator := testingValidator(t, true, 3, []byte{1, 2, 3, 4})
+	validator.dryRun = true
+	net := validator.network.(*signatureCountingNetwork)
+	duty := &beacon.Duty{
+		Type:           beacon.RoleTypeAttester,
+		PubKey:         spec.BLSPubKey{},
+		Slot:           0,
+		ValidatorIndex: 0,
+		CommitteeIndex: 0,
+	}
+
+	signaturesCount, decidedByts, seqNumber, err := validator.comeToConsensusOnInputValue(context.Background(), validator.logger, duty, time.Time{})
+	require.NoError(t, err)
+
+	// the decided value is not signed, broadcasted or submitted
+	require.NoError(t, validator.postConsensusDutyExecution(context.Background(), validator.logger, seqNumber, decidedByts, signaturesCount, duty))
+	require.Equal(t, 0, validator.beacon.(*testBeacon).Signed)
+	require.Equal(t, 0, net.signatures)
+	require.Nil(t, validator.beacon.(*testBeacon).LastSubmittedAttestation)
+
+	// invalid decided value
+	require.EqualError(t, validator.postConsensusDutyExecution(context.Background(), validator.logger, seqNumber, []byte{1, 2}, signaturesCount, duty),
+		"failed to compute signing root: failed to marshal attestation: incorrect size")
+
+	// not in dry-run, the decided value is signed
+	validator.dryRun = false
+	validator.signatureCollectionTimeout = time.Millisecond * 100
+	require.Error(t, validator.postConsensusDutyExecution(context.Background(), validator.logger, seqNumber, decidedByts, signaturesCount, duty))
+	require.Equal(t, 1, validator.beacon.(*testBeacon).Signed)
+	require.Equal(t, 1, net.signatures)
+}
+
+func TestVoluntaryExitValueCheck(t *testing.T) {
+	validator := testingValidator(t, true, 3, nil)
+	validator.ibfts[beacon.RoleTypeVoluntaryExit] = &testIBFT{decided: true, signaturesCount: 3}
+	ethNetwork := core.PraterNetwork
+	validator.ethNetwork = &ethNetwork
+
+	exit := &spec.VoluntaryExit{Epoch: 1, ValidatorIndex: 11}
+	byts, err := exit.MarshalSSZ()
+	require.NoError(t, err)
+	duty := &beacon.Duty{Type: beacon.RoleTypeVoluntaryExit, ValidatorIndex: 10}
+	require.EqualError(t, validator.valueCheck.ValueCheck(duty).Check(byts),
+		"voluntary exit of a different validator (11)")
+	duty.ValidatorIndex = 11
+	require.NoError(t, validator.valueCheck.ValueCheck(duty).Check(byts))
+}
+
+func TestDutyDeadline(t *testing.T) {
+	ethNetwork := core.PraterNetwork
+	v := &Validator{ethNetwork: &ethNetwork, dutyDeadlineSlots: 2}
+
+	duty := &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 10}
+	require.Equal(t, v.getSlotStartTime(12), v.dutyDeadline(duty))
+
+	// voluntary exits are not bound to a slot
+	require.True(t, v.dutyDeadline(&beacon.Duty{Type: beacon.RoleTypeVoluntaryExit, Slot: 10}).IsZero())
+
+	v.dutyDeadlineSlots = 0
+	require.True(t, v.dutyDeadline(duty).IsZero())
+}
+
+func TestDutyContext(t *testing.T) {
+	ethNetwork := core.PraterNetwork
+	v := &Validator{ethNetwork: &ethNetwork, dutyDeadlineSlots: 2, signatureCollectionTimeout: time.Second}
+
+	duty := &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 10}
+	ctx, cancel := v.dutyContext(context.Background(), duty)
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.Equal(t, v.dutyDeadline(duty).Add(time.Second), deadline)
+	cancel()
+	require.Error(t, ctx.Err())
+
+	// voluntary exits are bound to the duty timeout
+	exit := &beacon.Duty{Type: beacon.RoleTypeVoluntaryExit, Slot: 10}
+	v.dutyTimeout = time.Minute
+	ctx, cancel = v.dutyContext(context.Background(), exit)
+	defer cancel()
+	_, ok = ctx.Deadline()
+	require.True(t, ok)
+
+	v.dutyTimeout = 0
+	ctx, cancel = v.dutyContext(context.Background(), exit)
+	defer cancel()
+	_, ok = ctx.Deadline()
+	require.False(t, ok)
+}
+
+type mockFailover struct {
+	active bool
+}
+
+func (m *mockFailover) IsActive() bool {
+	return m.active
+}
+
+func TestExecuteDuty_Standby(t *testing.T) {
+	validator := testingValidator(t, true, 3, []byte{1, 2, 3, 4})
+	ethNetwork := core.PraterNetwork
+	validator.ethNetwork = &ethNetwork
+	validator.signatureCollectionTimeout = time.Millisecond * 100
+	failover := &mockFailover{}
+	validator.failover = failover
+	var decided, failed int
+	validator.hooks = NewHookRegistry()
+	validator.hooks.Register(Hooks{
+		OnDecided: func(share *storage.Share, duty *beacon.Duty, seqNumber uint64, value []byte) {
+			decided++
+		},
+		OnDutyFailed: func(share *storage.Share, duty *beacon.Duty, err error) {
+			failed++
+		},
+	})
+	duty := &beacon.Duty{Type: beacon.RoleTypeAttester}
+
+	// standby instances don't start consensus
+	validator.ExecuteDuty(context.Background(), 0, duty)
+	require.Equal(t, 0, decided)
+	require.Equal(t, 0, failed)
+
+	// the instance became a standby during consensus
+	signaturesCount, decidedByts, seqNumber, err := validator.comeToConsensusOnInputValue(context.Background(), validator.logger, duty, time.Time{})
+	require.NoError(t, err)
+	require.EqualError(t, validator.postConsensusDutyExecution(context.Background(), validator.logger, seqNumber, decidedByts, signaturesCount, duty),
+		"standby instance, the duty was not signed")
+	require.Equal(t, 0, validator.beacon.(*testBeacon).Signed)
+
+	// the active instance signs, the duty fails as no other signatures are collected
+	failover.active = true
+	validator.ExecuteDuty(context.Background(), 0, duty)
+	require.Equal(t, 1, decided)
+	require.Equal(t, 1, failed)
+	require.Equal(t, 1, validator.beacon.(*testBeacon).Signed)
+}
@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/validator/storage"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// Hooks are callbacks of validator lifecycle events, nil callbacks are skipped.
+// callbacks are invoked synchronously by the validator, therefore should return quickly
+type Hooks struct {
+	// OnStarted is called once the validator was started
+	OnStarted func(share *storage.Share)
+	// OnDecided is called once consensus was reached on the value of a duty
+	OnDecided func(share *storage.Share, duty *beacon.Duty, seqNumber uint64, value []byte)
+	// OnSigned is called once the post consensus signature of a duty was reconstructed and submitted,
+	// the given duty data holds the signed object
+	OnSigned func(share *storage.Share, duty *beacon.Duty, data *beacon.DutyData)
+	// OnDutyFailed is called when a duty could not be executed, including late duties
+	OnDutyFailed func(share *storage.Share, duty *beacon.Duty, err error)
+}
+
+// HookRegistry holds the hooks that are called on events of all validators
+type HookRegistry struct {
+	lock  sync.RWMutex
+	hooks []Hooks
+}
+
+// NewHookRegistry creates an empty registry
+func NewHookRegistry() *HookRegistry {
+	return &HookRegistry{}
+}
+
+// DefaultHooks is the registry of the validators controller, unless other registry was provided
+var DefaultHooks = NewHookRegistry()
+
+// RegisterHooks adds the given hooks to the default registry,
+// plugins that are compiled into the binary should register in init()
+func RegisterHooks(hooks Hooks) {
+	DefaultHooks.Register(hooks)
+}
+
+// Register adds the given hooks, hooks are called in registration order
+func (r *HookRegistry) Register(hooks Hooks) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.hooks = append(r.hooks, hooks)
+}
+
+// each calls the given function with all the registered hooks,
+// a panic in a hook is recovered so it won't affect the validator
+func (r *HookRegistry) each(logger *zap.Logger, event string, f func(h Hooks)) {
+	if r == nil {
+		return
+	}
+	r.lock.RLock()
+	hooks := make([]Hooks, len(r.hooks))
+	copy(hooks, r.hooks)
+	r.lock.RUnlock()
+
+	for _, h := range hooks {
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					logger.Error("validator hook panicked", zap.String("event", event), zap.Any("err", err))
+				}
+			}()
+			f(h)
+		}()
+	}
+}
+
+// started calls OnStarted hooks
+func (r *HookRegistry) started(logger *zap.Logger, share *storage.Share) {
+	r.each(logger, "started", func(h Hooks) {
+		if h.OnStarted != nil {
+			h.OnStarted(share)
+		}
+	})
+}
+
+// decided calls OnDecided hooks
+func (r *HookRegistry) decided(logger *zap.Logger, share *storage.Share, duty *beacon.Duty, seqNumber uint64, value []byte) {
+	r.each(logger, "decided", func(h Hooks) {
+		if h.OnDecided != nil {
+			h.OnDecided(share, duty, seqNumber, value)
+		}
+	})
+}
+
+// signed calls OnSigned hooks
+func (r *HookRegistry) signed(logger *zap.Logger, share *storage.Share, duty *beacon.Duty, data *beacon.DutyData) {
+	r.each(logger, "signed", func(h Hooks) {
+		if h.OnSigned != nil {
+			h.OnSigned(share, duty, data)
+		}
+	})
+}
+
+// dutyFailed calls OnDutyFailed hooks
+func (r *HookRegistry) dutyFailed(logger *zap.Logger, share *storage.Share, duty *beacon.Duty, err error) {
+	r.each(logger, "duty_failed", func(h Hooks) {
+		if h.OnDutyFailed != nil {
+			h.OnDutyFailed(share, duty, err)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/utils/logex"
+	"github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestHookRegistry(t *testing.T) {
+	logger := logex.Build("test", zap.DebugLevel, nil)
+	share := &storage.Share{NodeID: 1}
+	duty := &beacon.Duty{Type: beacon.RoleTypeAttester, Slot: 10}
+
+	var calls []string
+	r := NewHookRegistry()
+	r.Register(Hooks{
+		OnStarted: func(s *storage.Share) {
+			require.Equal(t, share, s)
+			calls = append(calls, "started")
+		},
+		OnDecided: func(s *storage.Share, d *beacon.Duty, seqNumber uint64, value []byte) {
+			require.Equal(t, duty, d)
+			require.EqualValues(t, 3, seqNumber)
+			require.Equal(t, []byte("value"), value)
+			calls = append(calls, "decided")
+		},
+		OnDutyFailed: func(s *storage.Share, d *beacon.Duty, err error) {
+			require.EqualError(t, err, "failed")
+			calls = append(calls, "failed")
+		},
+	})
+	// a panic in a hook doesn't affect other hooks
+	r.Register(Hooks{OnStarted: func(s *storage.Share) {
+		panic("boom")
+	}})
+	r.Register(Hooks{OnStarted: func(s *storage.Share) {
+		calls = append(calls, "started-2")
+	}})
+
+	r.started(logger, share)
+	r.decided(logger, share, duty, 3, []byte("value"))
+	// hooks w/o OnSigned are skipped
+	r.signed(logger, share, duty, &beacon.DutyData{})
+	r.dutyFailed(logger, share, duty, errors.New("failed"))
+	require.Equal(t, []string{"started", "started-2", "decided", "failed"}, calls)
+
+	// a nil registry is a no-op
+	var nilRegistry *HookRegistry
+	nilRegistry.started(logger, share)
+}
@@ -0,0 +1,236 @@
+package validator
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/ssv/beacon"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxInclusionDistance is the number of slots in which an attestation can be included
+	maxInclusionDistance = 32
+	// inclusionRetentionSlots is the number of slots (~1 day) for which inclusion results are kept
+	inclusionRetentionSlots = 32 * 225
+	// inclusionPruneIntervalSlots is the number of slots (~1 hour) between prunes of the inclusion results
+	inclusionPruneIntervalSlots = 300
+)
+
+// trackedAttestation is a submitted attestation that is pending inclusion
+type trackedAttestation struct {
+	pubKey                  []byte
+	dataRoot                [32]byte
+	validatorCommitteeIndex uint64
+	slot                    spec.Slot
+	committeeIndex          spec.CommitteeIndex
+}
+
+// inclusionTracker verifies in subsequent blocks whether submitted attestations were included
+type inclusionTracker struct {
+	logger     *zap.Logger
+	provider   beacon.BlockAttestationsProvider
+	collection validatorstorage.ICollection
+	ethNetwork *core.Network
+
+	// lock protects pending, which is accessed by the duties (track) and the checks
+	lock    sync.Mutex
+	pending []*trackedAttestation
+	// lastCheckedSlot is the slot of the last block that was checked
+	lastCheckedSlot spec.Slot
+	// lastPrunedSlot is the slot of the last prune of the inclusion results
+	lastPrunedSlot spec.Slot
+	// results holds the count of included and missed attestations of each validator
+	results map[string]*inclusionResults
+}
+
+// inclusionResults is the count of included and missed attestations of a validator
+type inclusionResults struct {
+	included uint64
+	missed   uint64
+}
+
+// newInclusionTracker creates a tracker, returns nil if the beacon client can't provide blocks attestations
+func newInclusionTracker(logger *zap.Logger, bc beacon.Beacon, collection validatorstorage.ICollection, ethNetwork *core.Network) *inclusionTracker {
+	provider, ok := bc.(beacon.BlockAttestationsProvider)
+	if !ok {
+		return nil
+	}
+	return &inclusionTracker{
+		logger:     logger.With(zap.String("component", "inclusionTracker")),
+		provider:   provider,
+		collection: collection,
+		ethNetwork: ethNetwork,
+		results:    map[string]*inclusionResults{},
+	}
+}
+
+// track adds a submitted attestation to be verified
+func (it *inclusionTracker) track(pubKey []byte, duty *beacon.Duty, attestation *spec.Attestation) {
+	root, err := attestation.Data.HashTreeRoot()
+	if err != nil {
+		it.logger.Warn("could not get attestation data root", zap.Error(err))
+		return
+	}
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	it.pending = append(it.pending, &trackedAttestation{
+		pubKey:                  pubKey,
+		dataRoot:                root,
+		validatorCommitteeIndex: duty.ValidatorCommitteeIndex,
+		slot:                    attestation.Data.Slot,
+		committeeIndex:          attestation.Data.Index,
+	})
+}
+
+// start checks the blocks of every slot, once the slot is over
+func (it *inclusionTracker) start() {
+	it.lastCheckedSlot = spec.Slot(it.ethNetwork.EstimatedCurrentSlot())
+	for {
+		time.Sleep(it.ethNetwork.SlotDurationSec())
+		it.check(spec.Slot(it.ethNetwork.EstimatedCurrentSlot()))
+	}
+}
+
+// check verifies the pending attestations against the blocks that were proposed before the given slot,
+// attestations that were not included within maxInclusionDistance are considered as missed.
+// blocks are fetched w/o holding the lock, so tracking new attestations is not blocked by the beacon node
+func (it *inclusionTracker) check(currentSlot spec.Slot) {
+	for slot := it.lastCheckedSlot + 1; slot < currentSlot; slot++ {
+		if it.pendingCount() > 0 {
+			if err := it.checkBlock(slot); err != nil {
+				it.logger.Debug("could not check block attestations", zap.Uint64("slot", uint64(slot)), zap.Error(err))
+				// the block will be checked again in the next round
+				return
+			}
+		}
+		it.lastCheckedSlot = slot
+	}
+	it.lock.Lock()
+	var missed []*trackedAttestation
+	pending := it.pending[:0]
+	for _, att := range it.pending {
+		if it.lastCheckedSlot >= att.slot+maxInclusionDistance {
+			missed = append(missed, att)
+			continue
+		}
+		pending = append(pending, att)
+	}
+	it.pending = pending
+	it.lock.Unlock()
+
+	for _, att := range missed {
+		it.onResult(att, &validatorstorage.AttestationInclusion{
+			Slot:           uint64(att.slot),
+			CommitteeIndex: uint64(att.committeeIndex),
+		})
+	}
+	if currentSlot >= it.lastPrunedSlot+inclusionPruneIntervalSlots {
+		it.prune(currentSlot)
+	}
+}
+
+// pendingCount returns the number of attestations that are pending inclusion
+func (it *inclusionTracker) pendingCount() int {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+
+	return len(it.pending)
+}
+
+// checkBlock matches the attestations of the block in the given slot with the pending attestations
+func (it *inclusionTracker) checkBlock(slot spec.Slot) error {
+	attestations, found, err := it.provider.GetBlockAttestations(slot)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	roots := make([][32]byte, len(attestations))
+	for i, att := range attestations {
+		if roots[i], err = att.Data.HashTreeRoot(); err != nil {
+			return err
+		}
+	}
+	it.lock.Lock()
+	var included []*trackedAttestation
+	pending := it.pending[:0]
+	for _, tracked := range it.pending {
+		if tracked.slot < slot && isIncluded(tracked, attestations, roots) {
+			included = append(included, tracked)
+			continue
+		}
+		pending = append(pending, tracked)
+	}
+	it.pending = pending
+	it.lock.Unlock()
+
+	for _, tracked := range included {
+		it.onResult(tracked, &validatorstorage.AttestationInclusion{
+			Slot:           uint64(tracked.slot),
+			CommitteeIndex: uint64(tracked.committeeIndex),
+			Included:       true,
+			InclusionSlot:  uint64(slot),
+			Distance:       uint64(slot - tracked.slot),
+		})
+	}
+	return nil
+}
+
+// prune deletes the inclusion results that are older than inclusionRetentionSlots
+func (it *inclusionTracker) prune(currentSlot spec.Slot) {
+	it.lastPrunedSlot = currentSlot
+	if currentSlot <= inclusionRetentionSlots {
+		return
+	}
+	pruned, err := it.collection.PruneAttestationInclusions(uint64(currentSlot - inclusionRetentionSlots))
+	if err != nil {
+		it.logger.Warn("could not prune attestation inclusions", zap.Error(err))
+		return
+	}
+	if pruned > 0 {
+		it.logger.Debug("pruned attestation inclusions", zap.Int("count", pruned))
+	}
+}
+
+// isIncluded returns true if one of the given attestations has the data and the aggregation bit of tracked
+func isIncluded(tracked *trackedAttestation, attestations []*spec.Attestation, roots [][32]byte) bool {
+	for i, att := range attestations {
+		if roots[i] == tracked.dataRoot && tracked.validatorCommitteeIndex < att.AggregationBits.Len() &&
+			att.AggregationBits.BitAt(tracked.validatorCommitteeIndex) {
+			return true
+		}
+	}
+	return false
+}
+
+// onResult saves and reports the inclusion result of an attestation, called only by the checks
+func (it *inclusionTracker) onResult(att *trackedAttestation, inclusion *validatorstorage.AttestationInclusion) {
+	pk := hex.EncodeToString(att.pubKey)
+	logger := it.logger.With(zap.String("pubKey", pk), zap.Uint64("slot", inclusion.Slot))
+	if err := it.collection.SaveAttestationInclusion(att.pubKey, inclusion); err != nil {
+		logger.Warn("could not save attestation inclusion", zap.Error(err))
+	}
+	results, ok := it.results[pk]
+	if !ok {
+		results = &inclusionResults{}
+		it.results[pk] = results
+	}
+	if inclusion.Included {
+		results.included++
+		metricsAttestationInclusion.WithLabelValues(pk, "included").Inc()
+		metricsAttestationInclusionDistance.Observe(float64(inclusion.Distance))
+		logger.Debug("attestation was included", zap.Uint64("distance", inclusion.Distance))
+	} else {
+		results.missed++
+		metricsAttestationInclusion.WithLabelValues(pk, "missed").Inc()
+		logger.Warn("attestation was not included")
+	}
+	metricsAttestationInclusionRate.WithLabelValues(pk).Set(float64(results.included) / float64(results.included+results.missed))
+}
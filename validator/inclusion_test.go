@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"testing"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testBlocksProvider returns the attestations of the given blocks, slots w/o blocks are missing
+type testBlocksProvider struct {
+	blocks map[spec.Slot][]*spec.Attestation
+	failAt spec.Slot
+}
+
+func (p *testBlocksProvider) GetBlockAttestations(slot spec.Slot) ([]*spec.Attestation, bool, error) {
+	if slot == p.failAt {
+		return nil, false, errors.New("test error")
+	}
+	atts, found := p.blocks[slot]
+	return atts, found, nil
+}
+
+func testAttestation(slot spec.Slot, bit uint64) *spec.Attestation {
+	bits := bitfield.NewBitlist(8)
+	bits.SetBitAt(bit, true)
+	return &spec.Attestation{
+		AggregationBits: bits,
+		Data: &spec.AttestationData{
+			Slot:   slot,
+			Index:  3,
+			Source: &spec.Checkpoint{},
+			Target: &spec.Checkpoint{Epoch: 1},
+		},
+	}
+}
+
+func TestInclusionTracker(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := validatorstorage.NewCollection(validatorstorage.CollectionOptions{DB: db, Logger: zap.L()})
+
+	provider := &testBlocksProvider{
+		blocks: map[spec.Slot][]*spec.Attestation{
+			// other validators attestations
+			11: {testAttestation(10, 1)},
+			12: {testAttestation(10, 2), testAttestation(10, 5)},
+		},
+		failAt: 13,
+	}
+	it := &inclusionTracker{
+		logger:          zap.L(),
+		provider:        provider,
+		collection:      collection,
+		results:         map[string]*inclusionResults{},
+		lastCheckedSlot: 10,
+	}
+	pkIncluded, pkMissed := []byte{1, 2, 3}, []byte{4, 5, 6}
+	it.track(pkIncluded, &beacon.Duty{ValidatorCommitteeIndex: 2}, testAttestation(10, 2))
+	it.track(pkMissed, &beacon.Duty{ValidatorCommitteeIndex: 4}, testAttestation(10, 4))
+
+	it.check(15)
+	// blocks are checked until the failure
+	require.EqualValues(t, 12, it.lastCheckedSlot)
+	require.Len(t, it.pending, 1)
+	inclusion, found, err := collection.GetAttestationInclusion(pkIncluded, 10)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, &validatorstorage.AttestationInclusion{
+		Slot:           10,
+		CommitteeIndex: 3,
+		Included:       true,
+		InclusionSlot:  12,
+		Distance:       2,
+	}, inclusion)
+
+	provider.failAt = 0
+	it.check(43)
+	require.EqualValues(t, 42, it.lastCheckedSlot)
+	require.Len(t, it.pending, 0)
+	inclusion, found, err = collection.GetAttestationInclusion(pkMissed, 10)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.False(t, inclusion.Included)
+
+	_, found, err = collection.GetAttestationInclusion(pkMissed, 11)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// results are pruned once out of the retention
+	it.prune(10 + inclusionRetentionSlots)
+	_, found, err = collection.GetAttestationInclusion(pkMissed, 10)
+	require.NoError(t, err)
+	require.True(t, found)
+	it.prune(11 + inclusionRetentionSlots)
+	for _, pk := range [][]byte{pkIncluded, pkMissed} {
+		_, found, err = collection.GetAttestationInclusion(pk, 10)
+		require.NoError(t, err)
+		require.False(t, found)
+	}
+}
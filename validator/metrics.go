@@ -25,6 +25,43 @@ var (
 		Name: "ssv:validator:status",
 		Help: "Validator status",
 	}, []string{"pubKey"})
+	metricsValidatorsTotal = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:validator:validators_total",
+		Help: "Count of the validators that were loaded from shares",
+	})
+	metricsValidatorsStarted = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:validator:validators_started",
+		Help: "Count of the validators that were started, the readiness of the node is validators_started / validators_total",
+	})
+	metricsCommitteePeers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:validator:committee_peers",
+		Help: "Count of connected peers on the validator topic",
+	}, []string{"pubKey"})
+	metricsCommitteeReady = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:validator:committee_ready",
+		Help: "Indicates whether enough committee peers are connected to reach a quorum (1) or not (0)",
+	}, []string{"pubKey"})
+	metricsTopicResubscriptions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:topic_resubscriptions",
+		Help: "Count resubscriptions to validator topics whose subscription was lost, by result (success or failed)",
+	}, []string{"pubKey", "result"})
+	metricsLateDuties = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:late_duties",
+		Help: "Count duties that didn't reach consensus before their deadline",
+	}, []string{"pubKey", "role"})
+	metricsAttestationInclusion = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:attestation_inclusion",
+		Help: "Count submitted attestations by inclusion result (included or missed)",
+	}, []string{"pubKey", "result"})
+	metricsAttestationInclusionRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:validator:attestation_inclusion_rate",
+		Help: "Ratio of submitted attestations that were included since the node started",
+	}, []string{"pubKey"})
+	metricsAttestationInclusionDistance = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ssv:validator:attestation_inclusion_distance",
+		Help:    "Distance in slots between the attestation slot and its inclusion slot",
+		Buckets: []float64{1, 2, 3, 4, 8, 16, 32},
+	})
 )
 
 func init() {
@@ -40,6 +77,33 @@ func init() {
 	if err := prometheus.Register(metricsValidatorStatus); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricsValidatorsTotal); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsValidatorsStarted); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsCommitteePeers); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsCommitteeReady); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsTopicResubscriptions); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsLateDuties); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsAttestationInclusion); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsAttestationInclusionRate); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsAttestationInclusionDistance); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
 
 // reportDutyExecutionMetrics reports duty execution metrics, returns done function to be called once duty is done
@@ -99,6 +163,16 @@ func ReportValidatorStatus(pk string, meta *beacon.ValidatorMetadata, logger *za
 	}
 }
 
+// reportCommitteeConnectivity reports the connectivity of the validator to its committee peers
+func reportCommitteeConnectivity(pk string, cc CommitteeConnectivity) {
+	metricsCommitteePeers.WithLabelValues(pk).Set(float64(cc.ConnectedPeers))
+	if cc.Ready() {
+		metricsCommitteeReady.WithLabelValues(pk).Set(1)
+	} else {
+		metricsCommitteeReady.WithLabelValues(pk).Set(0)
+	}
+}
+
 type validatorStatus int32
 type ibftStatus int32
 
@@ -112,6 +186,7 @@ var (
 	validatorStatusSlashed      validatorStatus = 6
 	validatorStatusNotFound     validatorStatus = 7
 	validatorStatusPending      validatorStatus = 8
+	validatorStatusPaused       validatorStatus = 9
 )
 
 var (
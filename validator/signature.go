@@ -1,10 +1,12 @@
 package validator
 
 import (
+	"context"
 	"encoding/base64"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/utils/blscache"
 	"github.com/bloxapp/ssv/utils/threshold"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
@@ -13,8 +15,8 @@ import (
 
 func (v *Validator) verifyPartialSignature(signature []byte, root []byte, ibftID uint64, committiee map[uint64]*proto.Node) error {
 	if val, found := committiee[ibftID]; found {
-		pk := &bls.PublicKey{}
-		if err := pk.Deserialize(val.Pk); err != nil {
+		pk, err := blscache.DeserializePubKey(val.Pk)
+		if err != nil {
 			return errors.Wrap(err, "could not deserialized pk")
 		}
 		sig := &bls.Sign{}
@@ -62,6 +64,18 @@ func (v *Validator) signDuty(decidedValue []byte, duty *beacon.Duty) ([]byte, []
 		retValueStruct.GetAttestation().AggregationBits = signedAttestation.AggregationBits
 		sig = signedAttestation.Signature[:]
 		root = ensureRoot(r)
+	case beacon.RoleTypeVoluntaryExit:
+		s := &spec.VoluntaryExit{}
+		if err := s.UnmarshalSSZ(decidedValue); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to marshal voluntary exit")
+		}
+		signature, r, err := v.signer.SignVoluntaryExit(s, pk.Serialize())
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to sign voluntary exit")
+		}
+		retValueStruct.SignedData = &beacon.InputValueVoluntaryExit{VoluntaryExit: &spec.SignedVoluntaryExit{Message: s}}
+		sig = signature
+		root = ensureRoot(r)
 	//case beacon.RoleTypeAggregator:
 	//	s := &proto.InputValue_Aggregation{}
 	//	if err := json.Unmarshal(decidedValue, s); err != nil {
@@ -98,9 +112,45 @@ func (v *Validator) signDuty(decidedValue []byte, duty *beacon.Duty) ([]byte, []
 	return sig, root, retValueStruct, err
 }
 
+// dutySigningRoot computes the signing root of the decided value w/o signing it
+func (v *Validator) dutySigningRoot(decidedValue []byte, duty *beacon.Duty) ([]byte, error) {
+	switch duty.Type {
+	case beacon.RoleTypeAttester:
+		data := &spec.AttestationData{}
+		if err := data.UnmarshalSSZ(decidedValue); err != nil {
+			return nil, errors.Wrap(err, "failed to marshal attestation")
+		}
+		domain, err := v.beacon.GetDomain(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get attestation domain")
+		}
+		root, err := v.beacon.ComputeSigningRoot(data, domain)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute attestation signing root")
+		}
+		return root[:], nil
+	case beacon.RoleTypeVoluntaryExit:
+		exit := &spec.VoluntaryExit{}
+		if err := exit.UnmarshalSSZ(decidedValue); err != nil {
+			return nil, errors.Wrap(err, "failed to marshal voluntary exit")
+		}
+		domain, err := v.beacon.GetVoluntaryExitDomain(exit.Epoch)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get voluntary exit domain")
+		}
+		root, err := v.beacon.ComputeSigningRoot(exit, domain)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute voluntary exit signing root")
+		}
+		return root[:], nil
+	default:
+		return nil, errors.New("unsupported role, can't compute signing root")
+	}
+}
+
 // reconstructAndBroadcastSignature reconstructs the received signatures from other
 // nodes and broadcasts the reconstructed signature to the beacon-chain
-func (v *Validator) reconstructAndBroadcastSignature(logger *zap.Logger, signatures map[uint64][]byte, root []byte, inputValue *beacon.DutyData, duty *beacon.Duty) error {
+func (v *Validator) reconstructAndBroadcastSignature(ctx context.Context, logger *zap.Logger, signatures map[uint64][]byte, root []byte, inputValue *beacon.DutyData, duty *beacon.Duty) error {
 	// Reconstruct signatures
 	signature, err := threshold.ReconstructSignatures(signatures)
 	if err != nil {
@@ -116,13 +166,30 @@ func (v *Validator) reconstructAndBroadcastSignature(logger *zap.Logger, signatu
 	// Submit validation to beacon node
 	switch duty.Type {
 	case beacon.RoleTypeAttester:
-		logger.Debug("submitting attestation")
+		logger.Debug("submitting attestation", zap.Bool("dryRun", v.dryRun))
 		blsSig := spec.BLSSignature{}
 		copy(blsSig[:], signature.Serialize()[:])
 		inputValue.GetAttestation().Signature = blsSig
-		if err := v.beacon.SubmitAttestation(inputValue.GetAttestation()); err != nil {
+		if v.dryRun {
+			logger.Info("dry-run: skipping attestation submission", zap.Any("attestation", inputValue.GetAttestation()))
+			break
+		}
+		if err := v.beacon.SubmitAttestation(ctx, inputValue.GetAttestation()); err != nil {
 			return errors.Wrap(err, "failed to broadcast attestation")
 		}
+		if v.inclusionTracker != nil {
+			v.inclusionTracker.track(v.Share.PublicKey.Serialize(), duty, inputValue.GetAttestation())
+		}
+	case beacon.RoleTypeVoluntaryExit:
+		logger.Debug("submitting voluntary exit", zap.Bool("dryRun", v.dryRun))
+		copy(inputValue.GetVoluntaryExit().Signature[:], signature.Serialize()[:])
+		if v.dryRun {
+			logger.Info("dry-run: skipping voluntary exit submission", zap.Any("exit", inputValue.GetVoluntaryExit()))
+			break
+		}
+		if err := v.beacon.SubmitVoluntaryExit(ctx, inputValue.GetVoluntaryExit()); err != nil {
+			return errors.Wrap(err, "failed to broadcast voluntary exit")
+		}
 	//case beacon.RoleTypeAggregator:
 	//	inputValue.GetAggregation().Signature = signature.Serialize()
 	//	if err := v.beacon.SubmitAggregation(ctx, inputValue.GetAggregation()); err != nil {
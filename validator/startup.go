@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"github.com/bloxapp/ssv/beacon"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+// startupPriority returns the priority of the given share on startup (lower starts first):
+// active validators, pending validators, validators w/o metadata and then exited validators
+func startupPriority(share *validatorstorage.Share) int {
+	switch {
+	case !share.HasMetadata():
+		return 2
+	case share.Metadata.Exiting():
+		return 3
+	case share.Metadata.Status.IsActive():
+		return 0
+	default:
+		return 1
+	}
+}
+
+// sortByStartupPriority sorts the given shares according to their startup priority
+func sortByStartupPriority(shares []*validatorstorage.Share) {
+	sort.SliceStable(shares, func(i, j int) bool {
+		return startupPriority(shares[i]) < startupPriority(shares[j])
+	})
+}
+
+// startupBatches splits the given validators into batches of the given size, a single batch is returned if size is not positive
+func startupBatches(validators []*Validator, size int) [][]*Validator {
+	if size <= 0 || size >= len(validators) {
+		return [][]*Validator{validators}
+	}
+	var batches [][]*Validator
+	for start := 0; start < len(validators); start += size {
+		end := start + size
+		if end > len(validators) {
+			end = len(validators)
+		}
+		batches = append(batches, validators[start:end])
+	}
+	return batches
+}
+
+// startValidatorsInBatches starts the given validators in batches, with a pause between batches
+// in order to avoid bursts of requests to the beacon node and subscriptions on startup
+func (c *controller) startValidatorsInBatches(validators []*Validator) {
+	start := time.Now()
+	var started, failed int
+	for i, batch := range startupBatches(validators, c.startupBatchSize) {
+		if i > 0 {
+			time.Sleep(c.startupBatchInterval)
+		}
+		for _, v := range batch {
+			if err := c.startValidator(v); err != nil {
+				c.logger.Warn("could not start validator", zap.String("pubkey", v.Share.PublicKey.SerializeToHexStr()),
+					zap.Error(err))
+				failed++
+				continue
+			}
+			started++
+		}
+	}
+	c.logger.Info("validators startup done", zap.Int("started", started), zap.Int("failures", failed),
+		zap.Duration("took", time.Since(start)))
+}
+
+// fetchMissingMetadata fetches the metadata of the given public keys in batches, using the metadata update queue.
+// validators are started once their metadata was updated (see onMetadataUpdated)
+func (c *controller) fetchMissingMetadata(pubKeys [][]byte) {
+	if len(pubKeys) == 0 {
+		return
+	}
+	c.logger.Debug("fetching missing metadata", zap.Int("count", len(pubKeys)))
+	beacon.UpdateValidatorsMetadataBatch(pubKeys, c.metadataUpdateQueue, c, c.beacon, c.onMetadataUpdated, metadataBatchSize)
+}
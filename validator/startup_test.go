@@ -0,0 +1,49 @@
+package validator
+
+import (
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/bloxapp/ssv/beacon"
+	validatorstorage "github.com/bloxapp/ssv/validator/storage"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSortByStartupPriority(t *testing.T) {
+	newShare := func(nodeID uint64, status v1.ValidatorState) *validatorstorage.Share {
+		share := &validatorstorage.Share{NodeID: nodeID}
+		if status != v1.ValidatorStateUnknown {
+			share.Metadata = &beacon.ValidatorMetadata{Status: status, Index: 10}
+		}
+		return share
+	}
+	shares := []*validatorstorage.Share{
+		newShare(1, v1.ValidatorStateExitedUnslashed),
+		newShare(2, v1.ValidatorStateUnknown),
+		newShare(3, v1.ValidatorStatePendingQueued),
+		newShare(4, v1.ValidatorStateActiveOngoing),
+		// slashed validators have duties until they exit
+		newShare(5, v1.ValidatorStateActiveSlashed),
+		newShare(6, v1.ValidatorStateActiveOngoing),
+	}
+	sortByStartupPriority(shares)
+	var order []uint64
+	for _, share := range shares {
+		order = append(order, share.NodeID)
+	}
+	require.Equal(t, []uint64{4, 5, 6, 3, 2, 1}, order)
+}
+
+func TestStartupBatches(t *testing.T) {
+	validators := make([]*Validator, 5)
+	for i := range validators {
+		validators[i] = &Validator{}
+	}
+
+	batches := startupBatches(validators, 2)
+	require.Len(t, batches, 3)
+	require.Len(t, batches[0], 2)
+	require.Len(t, batches[2], 1)
+
+	require.Len(t, startupBatches(validators, 0), 1)
+	require.Len(t, startupBatches(validators, 10), 1)
+}
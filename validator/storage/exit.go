@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"encoding/binary"
+	"github.com/pkg/errors"
+)
+
+// exitRequestPrefix is the prefix of the exit requests (contract events) that were handled
+const exitRequestPrefix = "exit-request-"
+
+// SaveExitRequest saves the block of the exit request event of the given validator that was handled
+func (s *Collection) SaveExitRequest(pubKey []byte, blockNumber uint64) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, blockNumber)
+	return s.db.Set(s.exitPrefix, pubKey, value)
+}
+
+// GetExitRequest returns the block of the last exit request event of the given validator that was handled
+func (s *Collection) GetExitRequest(pubKey []byte) (uint64, bool, error) {
+	obj, found, err := s.db.Get(s.exitPrefix, pubKey)
+	if !found {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, found, err
+	}
+	if len(obj.Value) != 8 {
+		return 0, found, errors.New("invalid exit request value")
+	}
+	return binary.BigEndian.Uint64(obj.Value), found, nil
+}
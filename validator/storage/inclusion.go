@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"github.com/pkg/errors"
+)
+
+// attestationInclusionPrefix is the prefix of the attestations inclusion results
+const attestationInclusionPrefix = "attestation-inclusion-"
+
+// AttestationInclusion is the result of the inclusion verification of a submitted attestation
+type AttestationInclusion struct {
+	Slot           uint64 `json:"slot"`
+	CommitteeIndex uint64 `json:"committeeIndex"`
+	Included       bool   `json:"included"`
+	// InclusionSlot is the slot of the first block that included the attestation
+	InclusionSlot uint64 `json:"inclusionSlot,omitempty"`
+	// Distance is the number of slots between the attestation slot and the inclusion slot
+	Distance uint64 `json:"distance,omitempty"`
+}
+
+// SaveAttestationInclusion saves the inclusion result of an attestation of the given validator
+func (s *Collection) SaveAttestationInclusion(pubKey []byte, inclusion *AttestationInclusion) error {
+	raw, err := json.Marshal(inclusion)
+	if err != nil {
+		return errors.Wrap(err, "could not encode attestation inclusion")
+	}
+	return s.db.Set(s.inclusionPrefix, attestationInclusionKey(pubKey, inclusion.Slot), raw)
+}
+
+// GetAttestationInclusion returns the inclusion result of the attestation of the given validator in the given slot
+func (s *Collection) GetAttestationInclusion(pubKey []byte, slot uint64) (*AttestationInclusion, bool, error) {
+	obj, found, err := s.db.Get(s.inclusionPrefix, attestationInclusionKey(pubKey, slot))
+	if !found {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, found, err
+	}
+	inclusion := &AttestationInclusion{}
+	if err := json.Unmarshal(obj.Value, inclusion); err != nil {
+		return nil, found, errors.Wrap(err, "could not decode attestation inclusion")
+	}
+	return inclusion, found, nil
+}
+
+// PruneAttestationInclusions deletes the inclusion results of attestations before the given slot,
+// returns the number of deleted results
+func (s *Collection) PruneAttestationInclusions(beforeSlot uint64) (int, error) {
+	objs, err := s.db.GetAllByCollection(s.inclusionPrefix)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get attestation inclusions")
+	}
+	pruned := 0
+	for _, obj := range objs {
+		if len(obj.Key) < 8 || binary.BigEndian.Uint64(obj.Key[len(obj.Key)-8:]) >= beforeSlot {
+			continue
+		}
+		if err := s.db.Delete(s.inclusionPrefix, obj.Key); err != nil {
+			return pruned, errors.Wrap(err, "could not delete attestation inclusion")
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// attestationInclusionKey returns the key of an inclusion result, the slot is encoded in big endian for ordering
+func attestationInclusionKey(pubKey []byte, slot uint64) []byte {
+	key := make([]byte, len(pubKey)+8)
+	copy(key, pubKey)
+	binary.BigEndian.PutUint64(key[len(pubKey):], slot)
+	return key
+}
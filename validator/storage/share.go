@@ -3,12 +3,17 @@ package storage
 import (
 	"bytes"
 	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
 	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/blscache"
+	"github.com/bloxapp/ssv/utils/errs"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
 	"math"
+	"sort"
 )
 
 // PubKeys defines the type for public keys object representation
@@ -29,14 +34,51 @@ type Share struct {
 	PublicKey *bls.PublicKey
 	Committee map[uint64]*proto.Node
 	Metadata  *beacon.ValidatorMetadata // pointer in order to support nil
+	// Version is incremented on every save, used for optimistic concurrency
+	Version uint64
+	// Roles are the duty roles that are enabled for the share, all roles are enabled if empty
+	Roles []beacon.RoleType
+	// KeyManager is the key manager backend of the share key, the default backend is used if empty
+	KeyManager string
+	// Operators are the public keys of the committee operators by node id, taken from the registry.
+	// might be missing for shares that were saved before the operators were tracked
+	Operators map[uint64]string
+	// BlockNumber is the block of the registry event that created the share, zero if unknown
+	BlockNumber uint64
 }
 
-//  serializedShare struct
+// shareSchemaVersion is the current version of the share encoding schema
+const shareSchemaVersion = 1
+
+// shareSchema is the persisted representation of a share.
+// it is encoded as JSON with a sorted committee and hex encoded keys, so the encoding is deterministic
+// and can be read by external tools. Schema must be bumped on breaking changes
+type shareSchema struct {
+	Schema      int                       `json:"schema"`
+	Version     uint64                    `json:"version"`
+	NodeID      uint64                    `json:"nodeId"`
+	Committee   []shareSchemaNode         `json:"committee"`
+	Metadata    *beacon.ValidatorMetadata `json:"metadata,omitempty"`
+	Roles       []string                  `json:"roles,omitempty"`
+	KeyManager  string                    `json:"keyManager,omitempty"`
+	BlockNumber uint64                    `json:"blockNumber,omitempty"`
+}
+
+// shareSchemaNode is a committee member in shareSchema
+type shareSchemaNode struct {
+	ID             uint64 `json:"id"`
+	IbftID         uint64 `json:"ibftId"`
+	Pk             string `json:"pk"`
+	OperatorPubKey string `json:"operatorPubKey,omitempty"`
+}
+
+// serializedShare is the legacy (gob) encoding of shares, kept to decode shares that were not migrated yet
 type serializedShare struct {
 	NodeID    uint64
 	ShareKey  []byte
 	Committee map[uint64]*proto.Node
 	Metadata  *beacon.ValidatorMetadata // pointer in order to support nil
+	Version   uint64
 }
 
 // CommitteeSize returns the IBFT committee size
@@ -54,11 +96,30 @@ func (s *Share) PartialThresholdSize() int {
 	return int(math.Ceil(float64(s.CommitteeSize()) * 1 / 3))
 }
 
+// CommitteeOperators returns the public keys of the committee operators (w/o the operator of the share),
+// ordered by node id
+func (s *Share) CommitteeOperators() []string {
+	ids := make([]uint64, 0, len(s.Operators))
+	for id := range s.Operators {
+		if id != s.NodeID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, s.Operators[id])
+	}
+	return res
+}
+
 // OperatorPubKey returns the operator's public key based on the node id
 func (s *Share) OperatorPubKey() (*bls.PublicKey, error) {
 	if val, found := s.Committee[s.NodeID]; found {
-		pk := &bls.PublicKey{}
-		if err := pk.Deserialize(val.Pk); err != nil {
+		pk, err := blscache.DeserializePubKey(val.Pk)
+		if err != nil {
 			return nil, errors.Wrap(err, "failed to deserialize public key")
 		}
 		return pk, nil
@@ -71,13 +132,13 @@ func (s *Share) PubKeysByID(ids []uint64) (PubKeys, error) {
 	ret := make([]*bls.PublicKey, 0)
 	for _, id := range ids {
 		if val, ok := s.Committee[id]; ok {
-			pk := &bls.PublicKey{}
-			if err := pk.Deserialize(val.Pk); err != nil {
+			pk, err := blscache.DeserializePubKey(val.Pk)
+			if err != nil {
 				return ret, errors.Wrap(err, "failed to deserialize public key")
 			}
 			ret = append(ret, pk)
 		} else {
-			return nil, errors.New("pk for id not found")
+			return nil, errs.Mark(errors.New("pk for id not found"), errs.ErrWrongCommittee)
 		}
 	}
 	return ret, nil
@@ -85,6 +146,11 @@ func (s *Share) PubKeysByID(ids []uint64) (PubKeys, error) {
 
 // VerifySignedMessage returns true of signed message verifies against pks
 func (s *Share) VerifySignedMessage(msg *proto.SignedMessage) error {
+	return s.VerifySignedMessageWith(msg, nil)
+}
+
+// VerifySignedMessageWith is the same as VerifySignedMessage but uses the given signature verifier
+func (s *Share) VerifySignedMessageWith(msg *proto.SignedMessage, verify proto.SigVerifier) error {
 	pks, err := s.PubKeysByID(msg.SignerIds)
 	if err != nil {
 		return err
@@ -93,66 +159,152 @@ func (s *Share) VerifySignedMessage(msg *proto.SignedMessage) error {
 		return errors.New("could not find public key")
 	}
 
-	res, err := msg.VerifyAggregatedSig(pks)
+	res, err := msg.VerifyAggregatedSigWith(pks, verify)
 	if err != nil {
 		return err
 	}
 	if !res {
-		return errors.New("could not verify message signature")
+		return errs.Mark(errors.New("could not verify message signature"), errs.ErrInvalidSignature)
 	}
 
 	return nil
 }
 
-// Serialize share to []byte
+// Serialize share to []byte, using the current schema
 func (s *Share) Serialize() ([]byte, error) {
-	value := serializedShare{
-		NodeID:    s.NodeID,
-		Committee: map[uint64]*proto.Node{},
-		Metadata:  s.Metadata,
-	}
-	// copy committee by value
-	for k, n := range s.Committee {
-		value.Committee[k] = &proto.Node{
-			IbftId: n.GetIbftId(),
-			Pk:     n.GetPk()[:],
-		}
+	value := shareSchema{
+		Schema:      shareSchemaVersion,
+		Version:     s.Version,
+		NodeID:      s.NodeID,
+		Committee:   make([]shareSchemaNode, 0, len(s.Committee)),
+		Metadata:    s.Metadata,
+		KeyManager:  s.KeyManager,
+		BlockNumber: s.BlockNumber,
+	}
+	for _, r := range s.Roles {
+		value.Roles = append(value.Roles, r.String())
+	}
+	for id, n := range s.Committee {
+		value.Committee = append(value.Committee, shareSchemaNode{
+			ID:             id,
+			IbftID:         n.GetIbftId(),
+			Pk:             hex.EncodeToString(n.GetPk()),
+			OperatorPubKey: s.Operators[id],
+		})
 	}
-	var b bytes.Buffer
-	e := gob.NewEncoder(&b)
-	if err := e.Encode(value); err != nil {
-		return nil, errors.Wrap(err, "Failed to encode serializedValidator")
+	sort.Slice(value.Committee, func(i, j int) bool {
+		return value.Committee[i].ID < value.Committee[j].ID
+	})
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode share")
 	}
-	return b.Bytes(), nil
+	return b, nil
 }
 
-// Deserialize key/value to Share model
+// Deserialize key/value to Share model, supports both the current schema and the legacy (gob) encoding
 func (s *Share) Deserialize(obj basedb.Obj) (*Share, error) {
-	value := serializedShare{}
-	d := gob.NewDecoder(bytes.NewReader(obj.Value))
-	if err := d.Decode(&value); err != nil {
-		return nil, errors.Wrap(err, "Failed to get val value")
+	pubKey, err := blscache.DeserializePubKey(obj.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get pubkey")
+	}
+	if isLegacyShareEncoding(obj.Value) {
+		return deserializeLegacyShare(pubKey, obj.Value)
+	}
+	value := shareSchema{}
+	if err := json.Unmarshal(obj.Value, &value); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode share")
+	}
+	if value.Schema > shareSchemaVersion {
+		return nil, errors.Errorf("unsupported share schema %d", value.Schema)
 	}
-	shareSecret := &bls.SecretKey{} // need to decode secret separately cause of encoding has private var limit in bls.SecretKey struct
-	// in exporter scenario, share key should be nil
-	if value.ShareKey != nil && len(value.ShareKey) > 0 {
-		if err := shareSecret.Deserialize(value.ShareKey); err != nil {
-			return nil, errors.Wrap(err, "Failed to get key secret")
+	committee := make(map[uint64]*proto.Node, len(value.Committee))
+	var operators map[uint64]string
+	for _, n := range value.Committee {
+		pk, err := hex.DecodeString(n.Pk)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to decode committee public key")
+		}
+		committee[n.ID] = &proto.Node{IbftId: n.IbftID, Pk: pk}
+		if len(n.OperatorPubKey) > 0 {
+			if operators == nil {
+				operators = make(map[uint64]string, len(value.Committee))
+			}
+			operators[n.ID] = n.OperatorPubKey
 		}
 	}
-	pubKey := &bls.PublicKey{}
-	if err := pubKey.Deserialize(obj.Key); err != nil {
-		return nil, errors.Wrap(err, "Failed to get pubkey")
+	roles, err := ParseRoles(value.Roles)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode roles")
+	}
+	return &Share{
+		NodeID:      value.NodeID,
+		PublicKey:   pubKey,
+		Committee:   committee,
+		Metadata:    value.Metadata,
+		Version:     value.Version,
+		Roles:       roles,
+		KeyManager:  value.KeyManager,
+		Operators:   operators,
+		BlockNumber: value.BlockNumber,
+	}, nil
+}
+
+// deserializeLegacyShare decodes a share that was encoded with gob
+func deserializeLegacyShare(pubKey *bls.PublicKey, raw []byte) (*Share, error) {
+	value := serializedShare{}
+	d := gob.NewDecoder(bytes.NewReader(raw))
+	if err := d.Decode(&value); err != nil {
+		return nil, errors.Wrap(err, "Failed to get val value")
 	}
 	return &Share{
 		NodeID:    value.NodeID,
 		PublicKey: pubKey,
 		Committee: value.Committee,
 		Metadata:  value.Metadata,
+		Version:   value.Version,
 	}, nil
 }
 
+// isLegacyShareEncoding returns true if the given value is not encoded with the current schema
+func isLegacyShareEncoding(raw []byte) bool {
+	if len(raw) == 0 || raw[0] != '{' {
+		return true
+	}
+	// a gob message might start with '{' as well, therefore making sure it's a valid schema
+	var header struct {
+		Schema int `json:"schema"`
+	}
+	return json.Unmarshal(raw, &header) != nil || header.Schema == 0
+}
+
 // HasMetadata returns true if the validator metadata was fetched
 func (s *Share) HasMetadata() bool {
 	return s.Metadata != nil
 }
+
+// RoleEnabled returns true if duties of the given role should be executed for this share
+func (s *Share) RoleEnabled(role beacon.RoleType) bool {
+	if len(s.Roles) == 0 {
+		return true
+	}
+	for _, r := range s.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRoles parses the given role names, returns nil (all roles) if empty
+func ParseRoles(names []string) ([]beacon.RoleType, error) {
+	var roles []beacon.RoleType
+	for _, name := range names {
+		r, err := beacon.ParseRoleType(name)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
@@ -9,10 +9,12 @@ import (
 
 // ShareOptions - used to load validator share from config
 type ShareOptions struct {
-	NodeID    uint64         `yaml:"NodeID" env:"NodeID" env-description:"Local share node ID"`
-	PublicKey string         `yaml:"PublicKey" env:"LOCAL_NODE_ID" env-description:"Local validator public key"`
-	ShareKey  string         `yaml:"ShareKey" env:"LOCAL_SHARE_KEY" env-description:"Local share key"`
-	Committee map[string]int `yaml:"Committee" env:"LOCAL_COMMITTEE" env-description:"Local validator committee array"`
+	NodeID     uint64         `yaml:"NodeID" env:"NodeID" env-description:"Local share node ID"`
+	PublicKey  string         `yaml:"PublicKey" env:"LOCAL_NODE_ID" env-description:"Local validator public key"`
+	ShareKey   string         `yaml:"ShareKey" env:"LOCAL_SHARE_KEY" env-description:"Local share key"`
+	Committee  map[string]int `yaml:"Committee" env:"LOCAL_COMMITTEE" env-description:"Local validator committee array"`
+	Roles      []string       `yaml:"Roles" env:"LOCAL_SHARE_ROLES" env-description:"Duty roles to execute for the local share (e.g. ATTESTER), all roles if empty"`
+	KeyManager string         `yaml:"KeyManager" env:"LOCAL_SHARE_KEY_MANAGER" env-description:"Key manager backend of the local share (local, remote or hsm), the default backend if empty"`
 }
 
 // ToShare creates a Share instance from ShareOptions
@@ -44,11 +46,18 @@ func (options *ShareOptions) ToShare() (*Share, error) {
 			return nil, err
 		}
 
+		roles, err := ParseRoles(options.Roles)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse roles")
+		}
+
 		share := Share{
-			NodeID:    options.NodeID,
-			Metadata:  nil,
-			PublicKey: validatorPk,
-			Committee: ibftCommittee,
+			NodeID:     options.NodeID,
+			Metadata:   nil,
+			PublicKey:  validatorPk,
+			Committee:  ibftCommittee,
+			Roles:      roles,
+			KeyManager: options.KeyManager,
 		}
 		return &share, nil
 	}
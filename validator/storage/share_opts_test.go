@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/fixtures"
 	"github.com/bloxapp/ssv/utils/threshold"
 	"github.com/stretchr/testify/require"
@@ -30,6 +31,19 @@ func TestShareOptionsToShare(t *testing.T) {
 		require.Equal(t, share.PublicKey.GetHexString(), origShare.PublicKey.GetHexString())
 	})
 
+	t.Run("ShareOptions with roles", func(t *testing.T) {
+		opts := shareOpts
+		opts.Roles = []string{"attester"}
+		share, err := opts.ToShare()
+		require.NoError(t, err)
+		require.True(t, share.RoleEnabled(beacon.RoleTypeAttester))
+		require.False(t, share.RoleEnabled(beacon.RoleTypeProposer))
+
+		opts.Roles = []string{"sync_committee"}
+		_, err = opts.ToShare()
+		require.EqualError(t, err, "failed to parse roles: unknown role: sync_committee")
+	})
+
 	t.Run("empty ShareOptions", func(t *testing.T) {
 		emptyShareOpts := ShareOptions{}
 		share, err := emptyShareOpts.ToShare()
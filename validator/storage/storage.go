@@ -7,12 +7,33 @@ import (
 	"sync"
 )
 
+// maxUpdateRetries is the number of attempts of UpdateValidatorShare on version conflicts
+const maxUpdateRetries = 5
+
+// ErrShareConflict is returned when a share was modified since it was read
+var ErrShareConflict = errors.New("share version conflict")
+
 // ICollection interface for validator storage
 type ICollection interface {
 	SaveValidatorShare(share *Share) error
+	MergeValidatorShare(share *Share) (bool, error)
+	CompareAndSwapValidatorShare(share *Share) error
+	UpdateValidatorShare(pubKey []byte, update func(share *Share) error) (*Share, error)
 	GetValidatorShare(key []byte) (*Share, bool, error)
 	GetAllValidatorsShare() ([]*Share, error)
 	CleanAllShares() error
+	MigrateShares() (int, error)
+	SaveValidatorPaused(pubKey []byte, paused bool) error
+	IsValidatorPaused(pubKey []byte) (bool, error)
+	SaveAttestationInclusion(pubKey []byte, inclusion *AttestationInclusion) error
+	GetAttestationInclusion(pubKey []byte, slot uint64) (*AttestationInclusion, bool, error)
+	PruneAttestationInclusions(beforeSlot uint64) (int, error)
+	RemoveValidatorShare(pubKey []byte, tombstone *Tombstone) error
+	GetTombstone(pubKey []byte) (*Tombstone, bool, error)
+	GetAllTombstones() ([]*Tombstone, error)
+	DeleteTombstone(pubKey []byte) error
+	SaveExitRequest(pubKey []byte, blockNumber uint64) error
+	GetExitRequest(pubKey []byte) (uint64, bool, error)
 }
 
 // CollectionOptions struct
@@ -23,37 +44,156 @@ type CollectionOptions struct {
 
 // Collection struct
 type Collection struct {
-	db     basedb.IDb
-	logger *zap.Logger
-	lock   sync.RWMutex
-	prefix []byte
+	db              basedb.IDb
+	logger          *zap.Logger
+	lock            sync.RWMutex
+	prefix          []byte
+	pausedPrefix    []byte
+	inclusionPrefix []byte
+	tombstonePrefix []byte
+	exitPrefix      []byte
 }
 
 // NewCollection creates new share storage
 func NewCollection(options CollectionOptions) ICollection {
 	collection := Collection{
-		db:     options.DB,
-		logger: options.Logger,
-		prefix: []byte(getCollectionPrefix()),
-		lock:   sync.RWMutex{},
+		db:              options.DB,
+		logger:          options.Logger,
+		prefix:          []byte(getCollectionPrefix()),
+		pausedPrefix:    []byte(pausedValidatorPrefix),
+		inclusionPrefix: []byte(attestationInclusionPrefix),
+		tombstonePrefix: []byte(tombstonePrefix),
+		exitPrefix:      []byte(exitRequestPrefix),
+		lock:            sync.RWMutex{},
 	}
 	return &collection
 }
+
+// pausedValidatorPrefix is the prefix of the paused state of validators
+const pausedValidatorPrefix = "paused-validator-"
+
+// CollectionPrefixes returns the db prefixes of the shares collection
+func CollectionPrefixes() [][]byte {
+	return [][]byte{[]byte(getCollectionPrefix()), []byte(pausedValidatorPrefix), []byte(attestationInclusionPrefix),
+		[]byte(tombstonePrefix), []byte(exitRequestPrefix)}
+}
+
 func getCollectionPrefix() string {
 	return "share-"
 }
 
-// SaveValidatorShare save validator share to db
+// SaveValidatorShare save validator share to db, overriding the existing share regardless of its version
 func (s *Collection) SaveValidatorShare(validator *Share) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	value, err := validator.Serialize()
+	current, _, err := s.getUnsafe(validator.PublicKey.Serialize())
+	if err != nil {
+		return err
+	}
+	var version uint64
+	if current != nil {
+		version = current.Version
+	}
+	return s.saveUnsafe(validator, version+1)
+}
+
+// MergeValidatorShare saves a share that was created from a registry event (share.BlockNumber).
+// shares of older events are ignored, otherwise the share is merged with the existing one,
+// keeping its metadata, roles, key manager and operators if the given share doesn't have them.
+// returns false if the share was ignored
+func (s *Collection) MergeValidatorShare(share *Share) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, found, err := s.getUnsafe(share.PublicKey.Serialize())
+	if err != nil {
+		return false, err
+	}
+	var version uint64
+	if found {
+		if current.BlockNumber > share.BlockNumber {
+			return false, nil
+		}
+		if share.Metadata == nil {
+			share.Metadata = current.Metadata
+		}
+		if len(share.Roles) == 0 {
+			share.Roles = current.Roles
+		}
+		if len(share.KeyManager) == 0 {
+			share.KeyManager = current.KeyManager
+		}
+		if len(share.Operators) == 0 {
+			share.Operators = current.Operators
+		}
+		version = current.Version
+	}
+	return true, s.saveUnsafe(share, version+1)
+}
+
+// CompareAndSwapValidatorShare saves the share only if the stored version equals share.Version,
+// otherwise ErrShareConflict is returned and the caller should re-read the share and retry.
+// on success, share.Version is incremented
+func (s *Collection) CompareAndSwapValidatorShare(share *Share) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, found, err := s.getUnsafe(share.PublicKey.Serialize())
+	if err != nil {
+		return err
+	}
+	var version uint64
+	if found {
+		version = current.Version
+	}
+	if version != share.Version {
+		return ErrShareConflict
+	}
+	return s.saveUnsafe(share, version+1)
+}
+
+// UpdateValidatorShare reads the share, applies the given update and saves it with compare-and-swap,
+// retrying on version conflicts. returns the updated share
+func (s *Collection) UpdateValidatorShare(pubKey []byte, update func(share *Share) error) (*Share, error) {
+	for i := 0; i < maxUpdateRetries; i++ {
+		share, found, err := s.GetValidatorShare(pubKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get share")
+		}
+		if !found {
+			return nil, errors.New("share not found")
+		}
+		if err := update(share); err != nil {
+			return nil, err
+		}
+		err = s.CompareAndSwapValidatorShare(share)
+		if err == nil {
+			return share, nil
+		}
+		if err != ErrShareConflict {
+			return nil, errors.Wrap(err, "could not save share")
+		}
+		s.logger.Debug("share version conflict, retrying", zap.Int("attempt", i+1))
+	}
+	return nil, errors.Wrap(ErrShareConflict, "exceeded update retries")
+}
+
+// saveUnsafe saves the share with the given version, must be called under lock
+func (s *Collection) saveUnsafe(share *Share, version uint64) error {
+	prev := share.Version
+	share.Version = version
+	value, err := share.Serialize()
 	if err != nil {
+		share.Version = prev
 		s.logger.Error("failed serialized validator", zap.Error(err))
 		return err
 	}
-	return s.db.Set(s.prefix, validator.PublicKey.Serialize(), value)
+	if err := s.db.Set(s.prefix, share.PublicKey.Serialize(), value); err != nil {
+		share.Version = prev
+		return err
+	}
+	return nil
 }
 
 // GetValidatorShare by key
@@ -61,6 +201,11 @@ func (s *Collection) GetValidatorShare(key []byte) (*Share, bool, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
+	return s.getUnsafe(key)
+}
+
+// getUnsafe returns the share of the given key, must be called under lock
+func (s *Collection) getUnsafe(key []byte) (*Share, bool, error) {
 	obj, found, err := s.db.Get(s.prefix, key)
 	if !found {
 		return nil, false, nil
@@ -97,3 +242,56 @@ func (s *Collection) GetAllValidatorsShare() ([]*Share, error) {
 
 	return res, nil
 }
+
+// MigrateShares re-encodes shares that are stored in the legacy encoding with the current schema,
+// returns the number of migrated shares
+func (s *Collection) MigrateShares() (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	objs, err := s.db.GetAllByCollection(s.prefix)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get shares")
+	}
+	migrated := 0
+	for _, obj := range objs {
+		if !isLegacyShareEncoding(obj.Value) {
+			continue
+		}
+		share, err := (&Share{}).Deserialize(obj)
+		if err != nil {
+			return migrated, errors.Wrap(err, "failed to deserialize legacy share")
+		}
+		if err := s.saveUnsafe(share, share.Version); err != nil {
+			return migrated, errors.Wrap(err, "failed to save migrated share")
+		}
+		migrated++
+	}
+	if migrated > 0 {
+		s.logger.Info("migrated shares encoding", zap.Int("count", migrated))
+	}
+	return migrated, nil
+}
+
+// SaveValidatorPaused persists whether duties of the given validator are paused
+func (s *Collection) SaveValidatorPaused(pubKey []byte, paused bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !paused {
+		return s.db.Delete(s.pausedPrefix, pubKey)
+	}
+	return s.db.Set(s.pausedPrefix, pubKey, []byte{1})
+}
+
+// IsValidatorPaused returns whether duties of the given validator are paused
+func (s *Collection) IsValidatorPaused(pubKey []byte) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	_, found, err := s.db.Get(s.pausedPrefix, pubKey)
+	if !found {
+		return false, nil
+	}
+	return found, err
+}
@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"bytes"
+	"encoding/gob"
+	"github.com/bloxapp/ssv/beacon"
 	"github.com/bloxapp/ssv/fixtures"
 	"github.com/bloxapp/ssv/ibft/proto"
 	"github.com/bloxapp/ssv/storage"
@@ -30,6 +33,116 @@ func TestValidatorSerializer(t *testing.T) {
 	require.NotNil(t, v.NodeID)
 }
 
+func TestShareSerializer_Deterministic(t *testing.T) {
+	validatorShare, _ := generateRandomValidatorShare()
+	validatorShare.Metadata = &beacon.ValidatorMetadata{Index: 1, Balance: 32}
+	b1, err := validatorShare.Serialize()
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		b, err := validatorShare.Serialize()
+		require.NoError(t, err)
+		require.Equal(t, b1, b)
+	}
+	require.False(t, isLegacyShareEncoding(b1))
+
+	v, err := validatorShare.Deserialize(basedb.Obj{Key: validatorShare.PublicKey.Serialize(), Value: b1})
+	require.NoError(t, err)
+	require.Equal(t, validatorShare.Committee[2].Pk, v.Committee[2].Pk)
+	require.EqualValues(t, 2, v.Committee[2].IbftId)
+	require.EqualValues(t, 1, v.Metadata.Index)
+}
+
+func TestShareSerializer_Roles(t *testing.T) {
+	validatorShare, _ := generateRandomValidatorShare()
+	require.True(t, validatorShare.RoleEnabled(beacon.RoleTypeProposer))
+
+	validatorShare.Roles = []beacon.RoleType{beacon.RoleTypeAttester}
+	b, err := validatorShare.Serialize()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"roles":["ATTESTER"]`)
+
+	v, err := validatorShare.Deserialize(basedb.Obj{Key: validatorShare.PublicKey.Serialize(), Value: b})
+	require.NoError(t, err)
+	require.Equal(t, []beacon.RoleType{beacon.RoleTypeAttester}, v.Roles)
+	require.True(t, v.RoleEnabled(beacon.RoleTypeAttester))
+	require.False(t, v.RoleEnabled(beacon.RoleTypeProposer))
+}
+
+func TestShareSerializer_Operators(t *testing.T) {
+	validatorShare, _ := generateRandomValidatorShare()
+	b, err := validatorShare.Serialize()
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "operatorPubKey")
+	v, err := validatorShare.Deserialize(basedb.Obj{Key: validatorShare.PublicKey.Serialize(), Value: b})
+	require.NoError(t, err)
+	require.Nil(t, v.Operators)
+	require.Len(t, v.CommitteeOperators(), 0)
+
+	validatorShare.Operators = map[uint64]string{1: "op1", 2: "op2", 3: "op3", 4: "op4"}
+	b, err = validatorShare.Serialize()
+	require.NoError(t, err)
+	v, err = validatorShare.Deserialize(basedb.Obj{Key: validatorShare.PublicKey.Serialize(), Value: b})
+	require.NoError(t, err)
+	require.Equal(t, validatorShare.Operators, v.Operators)
+	// the operator of the share is excluded
+	require.Equal(t, []string{"op2", "op3", "op4"}, v.CommitteeOperators())
+}
+
+func TestShareSerializer_Legacy(t *testing.T) {
+	validatorShare, _ := generateRandomValidatorShare()
+	legacy := encodeLegacyShare(t, validatorShare)
+	require.True(t, isLegacyShareEncoding(legacy))
+
+	v, err := validatorShare.Deserialize(basedb.Obj{Key: validatorShare.PublicKey.Serialize(), Value: legacy})
+	require.NoError(t, err)
+	require.Equal(t, validatorShare.NodeID, v.NodeID)
+	require.Len(t, v.Committee, len(validatorShare.Committee))
+
+	_, err = validatorShare.Deserialize(basedb.Obj{
+		Key:   validatorShare.PublicKey.Serialize(),
+		Value: []byte(`{"schema":100}`),
+	})
+	require.EqualError(t, err, "unsupported share schema 100")
+}
+
+func TestMigrateShares(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := NewCollection(CollectionOptions{DB: db, Logger: zap.L()})
+
+	legacyShare, _ := generateRandomValidatorShare()
+	require.NoError(t, db.Set([]byte(getCollectionPrefix()), legacyShare.PublicKey.Serialize(), encodeLegacyShare(t, legacyShare)))
+	share, _ := generateRandomValidatorShare()
+	require.NoError(t, collection.SaveValidatorShare(share))
+
+	migrated, err := collection.MigrateShares()
+	require.NoError(t, err)
+	require.Equal(t, 1, migrated)
+
+	obj, found, err := db.Get([]byte(getCollectionPrefix()), legacyShare.PublicKey.Serialize())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.False(t, isLegacyShareEncoding(obj.Value))
+
+	migrated, err = collection.MigrateShares()
+	require.NoError(t, err)
+	require.Equal(t, 0, migrated)
+}
+
+func encodeLegacyShare(t *testing.T, share *Share) []byte {
+	var b bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&b).Encode(serializedShare{
+		NodeID:    share.NodeID,
+		Committee: share.Committee,
+		Metadata:  share.Metadata,
+	}))
+	return b.Bytes()
+}
+
 func TestSaveAndGetValidatorStorage(t *testing.T) {
 	options := basedb.Options{
 		Type:   "badger-memory",
@@ -62,6 +175,132 @@ func TestSaveAndGetValidatorStorage(t *testing.T) {
 	require.EqualValues(t, len(validators), 2)
 }
 
+func TestCompareAndSwapValidatorShare(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := NewCollection(CollectionOptions{DB: db, Logger: zap.L()})
+
+	share, _ := generateRandomValidatorShare()
+	require.NoError(t, collection.CompareAndSwapValidatorShare(share))
+	require.EqualValues(t, 1, share.Version)
+
+	first, _, err := collection.GetValidatorShare(share.PublicKey.Serialize())
+	require.NoError(t, err)
+	second, _, err := collection.GetValidatorShare(share.PublicKey.Serialize())
+	require.NoError(t, err)
+
+	first.NodeID = 2
+	require.NoError(t, collection.CompareAndSwapValidatorShare(first))
+	require.EqualValues(t, 2, first.Version)
+	// second was read before first was saved
+	second.NodeID = 3
+	require.Equal(t, ErrShareConflict, collection.CompareAndSwapValidatorShare(second))
+	require.EqualValues(t, 1, second.Version)
+
+	stored, _, err := collection.GetValidatorShare(share.PublicKey.Serialize())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, stored.NodeID)
+	require.EqualValues(t, 2, stored.Version)
+
+	// last-write-wins save bumps the version as well
+	require.NoError(t, collection.SaveValidatorShare(second))
+	require.EqualValues(t, 3, second.Version)
+}
+
+func TestUpdateValidatorShare(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := NewCollection(CollectionOptions{DB: db, Logger: zap.L()})
+
+	share, _ := generateRandomValidatorShare()
+	_, err = collection.UpdateValidatorShare(share.PublicKey.Serialize(), func(share *Share) error {
+		return nil
+	})
+	require.EqualError(t, err, "share not found")
+
+	require.NoError(t, collection.SaveValidatorShare(share))
+
+	attempts := 0
+	updated, err := collection.UpdateValidatorShare(share.PublicKey.Serialize(), func(s *Share) error {
+		attempts++
+		if attempts == 1 {
+			// simulate a concurrent update
+			other, _, err := collection.GetValidatorShare(share.PublicKey.Serialize())
+			require.NoError(t, err)
+			other.NodeID = 4
+			require.NoError(t, collection.SaveValidatorShare(other))
+		}
+		s.Metadata = &beacon.ValidatorMetadata{Index: 10}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+	require.EqualValues(t, 3, updated.Version)
+
+	stored, _, err := collection.GetValidatorShare(share.PublicKey.Serialize())
+	require.NoError(t, err)
+	require.EqualValues(t, 4, stored.NodeID)
+	require.EqualValues(t, 10, stored.Metadata.Index)
+}
+
+func TestMergeValidatorShare(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := NewCollection(CollectionOptions{DB: db, Logger: zap.L()})
+
+	share, _ := generateRandomValidatorShare()
+	share.BlockNumber = 10
+	saved, err := collection.MergeValidatorShare(share)
+	require.NoError(t, err)
+	require.True(t, saved)
+
+	_, err = collection.UpdateValidatorShare(share.PublicKey.Serialize(), func(s *Share) error {
+		s.Metadata = &beacon.ValidatorMetadata{Index: 10}
+		s.KeyManager = "remote"
+		return nil
+	})
+	require.NoError(t, err)
+
+	// the event is replayed (e.g. by sync), the existing metadata and key manager are kept
+	replayed, _ := generateRandomValidatorShare()
+	replayed.PublicKey = share.PublicKey
+	replayed.BlockNumber = 10
+	saved, err = collection.MergeValidatorShare(replayed)
+	require.NoError(t, err)
+	require.True(t, saved)
+	stored, _, err := collection.GetValidatorShare(share.PublicKey.Serialize())
+	require.NoError(t, err)
+	require.EqualValues(t, 10, stored.BlockNumber)
+	require.EqualValues(t, 10, stored.Metadata.Index)
+	require.Equal(t, "remote", stored.KeyManager)
+	require.EqualValues(t, 3, stored.Version)
+
+	// a share of an older event is ignored
+	stale, _ := generateRandomValidatorShare()
+	stale.PublicKey = share.PublicKey
+	stale.NodeID = 2
+	stale.BlockNumber = 5
+	saved, err = collection.MergeValidatorShare(stale)
+	require.NoError(t, err)
+	require.False(t, saved)
+	stored, _, err = collection.GetValidatorShare(share.PublicKey.Serialize())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stored.NodeID)
+	require.EqualValues(t, 3, stored.Version)
+}
+
 func generateRandomValidatorShare() (*Share, *bls.SecretKey) {
 	threshold.Init()
 	sk := bls.SecretKey{}
@@ -92,3 +331,81 @@ func generateRandomValidatorShare() (*Share, *bls.SecretKey) {
 		Committee: ibftCommittee,
 	}, &sk
 }
+
+func TestSaveValidatorPaused(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+		Path:   "",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	collection := NewCollection(CollectionOptions{
+		DB:     db,
+		Logger: zap.L(),
+	})
+	validatorShare, _ := generateRandomValidatorShare()
+	pk := validatorShare.PublicKey.Serialize()
+
+	paused, err := collection.IsValidatorPaused(pk)
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	require.NoError(t, collection.SaveValidatorPaused(pk, true))
+	paused, err = collection.IsValidatorPaused(pk)
+	require.NoError(t, err)
+	require.True(t, paused)
+
+	require.NoError(t, collection.SaveValidatorPaused(pk, false))
+	paused, err = collection.IsValidatorPaused(pk)
+	require.NoError(t, err)
+	require.False(t, paused)
+}
+
+func TestRemoveValidatorShare(t *testing.T) {
+	db, err := storage.GetStorageFactory(basedb.Options{
+		Type:   "badger-memory",
+		Logger: zap.L(),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	collection := NewCollection(CollectionOptions{DB: db, Logger: zap.L()})
+
+	validatorShare, _ := generateRandomValidatorShare()
+	pk := validatorShare.PublicKey.Serialize()
+	require.NoError(t, collection.SaveValidatorShare(validatorShare))
+	require.NoError(t, collection.SaveValidatorPaused(pk, true))
+
+	require.NoError(t, collection.RemoveValidatorShare(pk, &Tombstone{Reason: TombstoneReasonRemovedEvent, BlockNumber: 100}))
+	require.EqualError(t, collection.RemoveValidatorShare(pk, &Tombstone{Reason: TombstoneReasonManual}), "share not found")
+
+	_, found, err := collection.GetValidatorShare(pk)
+	require.NoError(t, err)
+	require.False(t, found)
+	paused, err := collection.IsValidatorPaused(pk)
+	require.NoError(t, err)
+	require.False(t, paused)
+	shares, err := collection.GetAllValidatorsShare()
+	require.NoError(t, err)
+	require.Len(t, shares, 0)
+
+	tombstone, found, err := collection.GetTombstone(pk)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, validatorShare.PublicKey.SerializeToHexStr(), tombstone.PublicKey)
+	require.Equal(t, TombstoneReasonRemovedEvent, tombstone.Reason)
+	require.False(t, tombstone.RemovedAt.IsZero())
+	require.True(t, tombstone.Covers(100))
+	require.False(t, tombstone.Covers(101))
+	require.True(t, (&Tombstone{Reason: TombstoneReasonManual}).Covers(101))
+
+	tombstones, err := collection.GetAllTombstones()
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+
+	require.NoError(t, collection.DeleteTombstone(pk))
+	_, found, err = collection.GetTombstone(pk)
+	require.NoError(t, err)
+	require.False(t, found)
+}
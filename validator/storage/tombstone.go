@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// tombstonePrefix is the prefix of the tombstones of removed validators
+const tombstonePrefix = "tombstone-validator-"
+
+const (
+	// TombstoneReasonManual is the reason of validators that were removed by the operator
+	TombstoneReasonManual = "manual"
+	// TombstoneReasonRemovedEvent is the reason of validators that were removed by a ValidatorRemoved contract event
+	TombstoneReasonRemovedEvent = "removed-event"
+)
+
+// Tombstone is the record of a removed validator share, it is kept instead of the share
+// in order to prevent replayed contract events from adding the validator again
+type Tombstone struct {
+	PublicKey string    `json:"publicKey"`
+	Reason    string    `json:"reason"`
+	RemovedAt time.Time `json:"removedAt"`
+	// BlockNumber is the block of the removal event, 0 for manual removals
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+}
+
+// Covers returns true if an event of the given block precedes the removal, i.e. the event is stale.
+// tombstones of manual removals cover all blocks
+func (t *Tombstone) Covers(blockNumber uint64) bool {
+	return t.BlockNumber == 0 || blockNumber <= t.BlockNumber
+}
+
+// RemoveValidatorShare removes the share (and paused state) of the given validator and saves the given tombstone instead,
+// the public key and removal time are set by the collection
+func (s *Collection) RemoveValidatorShare(pubKey []byte, tombstone *Tombstone) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, found, err := s.getUnsafe(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get share")
+	}
+	if !found {
+		return errors.New("share not found")
+	}
+	tombstone.PublicKey = hex.EncodeToString(pubKey)
+	tombstone.RemovedAt = time.Now().UTC()
+	raw, err := json.Marshal(tombstone)
+	if err != nil {
+		return errors.Wrap(err, "could not encode tombstone")
+	}
+	// the tombstone is saved first so a failure in between won't allow the validator to be added again
+	if err := s.db.Set(s.tombstonePrefix, pubKey, raw); err != nil {
+		return errors.Wrap(err, "could not save tombstone")
+	}
+	if err := s.db.Delete(s.prefix, pubKey); err != nil {
+		return errors.Wrap(err, "could not delete share")
+	}
+	return s.db.Delete(s.pausedPrefix, pubKey)
+}
+
+// GetTombstone returns the tombstone of the given validator
+func (s *Collection) GetTombstone(pubKey []byte) (*Tombstone, bool, error) {
+	obj, found, err := s.db.Get(s.tombstonePrefix, pubKey)
+	if !found {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, found, err
+	}
+	tombstone := &Tombstone{}
+	if err := json.Unmarshal(obj.Value, tombstone); err != nil {
+		return nil, found, errors.Wrap(err, "could not decode tombstone")
+	}
+	return tombstone, found, nil
+}
+
+// GetAllTombstones returns the tombstones of all removed validators
+func (s *Collection) GetAllTombstones() ([]*Tombstone, error) {
+	objs, err := s.db.GetAllByCollection(s.tombstonePrefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tombstones")
+	}
+	tombstones := make([]*Tombstone, 0, len(objs))
+	for _, obj := range objs {
+		tombstone := &Tombstone{}
+		if err := json.Unmarshal(obj.Value, tombstone); err != nil {
+			return nil, errors.Wrap(err, "could not decode tombstone")
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+	return tombstones, nil
+}
+
+// DeleteTombstone deletes the tombstone of the given validator, once it was added again by a newer event
+func (s *Collection) DeleteTombstone(pubKey []byte) error {
+	return s.db.Delete(s.tombstonePrefix, pubKey)
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"github.com/bloxapp/ssv/network"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SubscriptionsReconcileLoop resubscribes the topics of started validators whose subscription was lost in an interval,
+// returns immediately if the network doesn't track its subscriptions
+func (c *controller) SubscriptionsReconcileLoop() {
+	subs, ok := c.network.(network.ValidatorSubscriptions)
+	if !ok {
+		return
+	}
+	interval := c.subscriptionsCheckInterval
+	if interval == 0 {
+		interval = time.Minute
+	}
+	for {
+		time.Sleep(interval)
+		c.reconcileSubscriptions(subs)
+	}
+}
+
+// reconcileSubscriptions compares the started validators with the live subscriptions and resubscribes missing ones
+func (c *controller) reconcileSubscriptions(subs network.ValidatorSubscriptions) {
+	_ = c.validatorsMap.ForEach(func(v *Validator) error {
+		if !v.IsStarted() || subs.IsSubscribedToValidatorNetwork(v.Share.PublicKey) {
+			return nil
+		}
+		pk := v.Share.PublicKey.SerializeToHexStr()
+		c.logger.Warn("subscription of validator topic was lost, resubscribing", zap.String("pubKey", pk))
+		if err := v.resubscribe(); err != nil {
+			metricsTopicResubscriptions.WithLabelValues(pk, "failed").Inc()
+			c.logger.Error("could not resubscribe validator topic", zap.String("pubKey", pk), zap.Error(err))
+			return nil
+		}
+		metricsTopicResubscriptions.WithLabelValues(pk, "success").Inc()
+		return nil
+	})
+}
+
+// resubscribe subscribes the topics of the validator once again
+func (v *Validator) resubscribe() error {
+	if err := v.network.SubscribeToValidatorNetwork(v.Share.PublicKey); err != nil {
+		return errors.Wrap(err, "failed to subscribe topic")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"github.com/bloxapp/ssv/network/local"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sync/atomic"
+	"testing"
+)
+
+// subscriptionsNetwork tracks the subscriptions of validator topics
+type subscriptionsNetwork struct {
+	*local.Local
+	subscribed map[string]bool
+	subscribes int
+}
+
+func (n *subscriptionsNetwork) SubscribeToValidatorNetwork(validatorPk *bls.PublicKey) error {
+	n.subscribes++
+	n.subscribed[validatorPk.SerializeToHexStr()] = true
+	return nil
+}
+
+func (n *subscriptionsNetwork) IsSubscribedToValidatorNetwork(validatorPk *bls.PublicKey) bool {
+	return n.subscribed[validatorPk.SerializeToHexStr()]
+}
+
+func TestController_ReconcileSubscriptions(t *testing.T) {
+	net := &subscriptionsNetwork{Local: local.NewLocalNetwork(), subscribed: map[string]bool{}}
+	v := testingValidator(t, true, 4, []byte{1, 2, 3, 4})
+	v.network = net
+	pk := v.Share.PublicKey.SerializeToHexStr()
+	c := &controller{
+		logger:        zap.L(),
+		network:       net,
+		validatorsMap: &validatorsMap{validatorsMap: map[string]*Validator{pk: v}},
+	}
+
+	// validators that were not started are skipped
+	c.reconcileSubscriptions(net)
+	require.Equal(t, 0, net.subscribes)
+
+	atomic.StoreUint32(&v.started, 1)
+	net.subscribed[pk] = true
+	c.reconcileSubscriptions(net)
+	require.Equal(t, 0, net.subscribes)
+
+	// the subscription was lost
+	delete(net.subscribed, pk)
+	c.reconcileSubscriptions(net)
+	require.Equal(t, 1, net.subscribes)
+	require.True(t, net.subscribed[pk])
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"encoding/hex"
 	api "github.com/attestantio/go-eth2-client/api/v1"
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
@@ -9,6 +10,7 @@ import (
 	"github.com/bloxapp/ssv/fixtures"
 	"github.com/bloxapp/ssv/ibft"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/local"
 	"github.com/bloxapp/ssv/network/msgqueue"
 	"github.com/bloxapp/ssv/utils/threshold"
@@ -52,6 +54,7 @@ type testIBFT struct {
 	decided         bool
 	signaturesCount int
 	identifier      []byte
+	closed          bool
 }
 
 func (t *testIBFT) Init() error {
@@ -102,12 +105,18 @@ func (t *testIBFT) NextSeqNumber() (uint64, error) {
 	return 0, nil
 }
 
+func (t *testIBFT) Close() {
+	t.closed = true
+}
+
 /**
 testBeacon
 */
 type testBeacon struct {
-	refAttestationData       *spec.AttestationData
-	LastSubmittedAttestation *spec.Attestation
+	refAttestationData         *spec.AttestationData
+	LastSubmittedAttestation   *spec.Attestation
+	LastSubmittedVoluntaryExit *spec.SignedVoluntaryExit
+	Signed                     int
 }
 
 func newTestBeacon(t *testing.T) *testBeacon {
@@ -133,11 +142,12 @@ func (b *testBeacon) GetValidatorData(validatorPubKeys []spec.BLSPubKey) (map[sp
 	return nil, nil
 }
 
-func (b *testBeacon) GetAttestationData(slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
+func (b *testBeacon) GetAttestationData(ctx context.Context, slot spec.Slot, committeeIndex spec.CommitteeIndex) (*spec.AttestationData, error) {
 	return b.refAttestationData, nil
 }
 
 func (b *testBeacon) SignAttestation(data *spec.AttestationData, duty *beacon.Duty, pk []byte) (*spec.Attestation, []byte, error) {
+	b.Signed++
 	sig := spec.BLSSignature{}
 	copy(sig[:], refAttestationSplitSigs[0])
 	return &spec.Attestation{
@@ -147,11 +157,29 @@ func (b *testBeacon) SignAttestation(data *spec.AttestationData, duty *beacon.Du
 	}, refSigRoot, nil
 }
 
-func (b *testBeacon) SubmitAttestation(attestation *spec.Attestation) error {
+func (b *testBeacon) SubmitAttestation(ctx context.Context, attestation *spec.Attestation) error {
 	b.LastSubmittedAttestation = attestation
 	return nil
 }
 
+func (b *testBeacon) SignVoluntaryExit(exit *spec.VoluntaryExit, pk []byte) ([]byte, []byte, error) {
+	b.Signed++
+	sk := &bls.SecretKey{}
+	if err := sk.Deserialize(refSplitShares[0]); err != nil {
+		return nil, nil, err
+	}
+	return sk.SignByte(refSigRoot).Serialize(), refSigRoot, nil
+}
+
+func (b *testBeacon) SubmitVoluntaryExit(ctx context.Context, exit *spec.SignedVoluntaryExit) error {
+	b.LastSubmittedVoluntaryExit = exit
+	return nil
+}
+
+func (b *testBeacon) GetSyncState() (*api.SyncState, error) {
+	return &api.SyncState{}, nil
+}
+
 func (b *testBeacon) SubscribeToCommitteeSubnet(subscription []*api.BeaconCommitteeSubscription) error {
 	panic("implement me")
 }
@@ -165,10 +193,26 @@ func (b *testBeacon) SignIBFTMessage(message *proto.Message, pk []byte) ([]byte,
 }
 
 func (b *testBeacon) GetDomain(data *spec.AttestationData) ([]byte, error) {
-	panic("implement")
+	return make([]byte, 32), nil
+}
+func (b *testBeacon) GetVoluntaryExitDomain(epoch spec.Epoch) ([]byte, error) {
+	return make([]byte, 32), nil
 }
 func (b *testBeacon) ComputeSigningRoot(object interface{}, domain []byte) ([32]byte, error) {
-	panic("implement")
+	root := [32]byte{}
+	copy(root[:], refSigRoot)
+	return root, nil
+}
+
+// signatureCountingNetwork counts the broadcasted signatures
+type signatureCountingNetwork struct {
+	network.Network
+	signatures int
+}
+
+func (n *signatureCountingNetwork) BroadcastSignature(topicName []byte, msg *proto.SignedMessage) error {
+	n.signatures++
+	return n.Network.BroadcastSignature(topicName, msg)
 }
 
 func testingValidator(t *testing.T, decided bool, signaturesCount int, identifier []byte) *Validator {
@@ -181,11 +225,11 @@ func testingValidator(t *testing.T, decided bool, signaturesCount int, identifie
 	ret.ibfts[beacon.RoleTypeAttester] = &testIBFT{decided: decided, signaturesCount: signaturesCount}
 	ret.ibfts[beacon.RoleTypeAttester].(*testIBFT).identifier = identifier
 	require.NoError(t, ret.ibfts[beacon.RoleTypeAttester].Init())
-	ret.valueCheck = valcheck.New()
 	ret.signer = ret.beacon
+	ret.valueCheck = valcheck.New(ret.signer, nil)
 
 	// nodes
-	ret.network = local.NewLocalNetwork()
+	ret.network = &signatureCountingNetwork{Network: local.NewLocalNetwork()}
 	ret.msgQueue = msgqueue.New()
 
 	// validatorStorage pk
@@ -1,13 +1,17 @@
 package validator
 
 import (
+	"encoding/hex"
 	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/beacon/keymanager"
 	"github.com/bloxapp/ssv/eth1"
 	"github.com/bloxapp/ssv/ibft/proto"
+	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
 	validatorstorage "github.com/bloxapp/ssv/validator/storage"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 	"strings"
 )
 
@@ -62,6 +66,7 @@ func ShareFromValidatorAddedEvent(validatorAddedEvent eth1.ValidatorAddedEvent,
 	var shareKey *bls.SecretKey
 
 	ibftCommittee := map[uint64]*proto.Node{}
+	operators := map[uint64]string{}
 	for i := range validatorAddedEvent.OessList {
 		oess := validatorAddedEvent.OessList[i]
 		nodeID := oess.Index.Uint64() + 1
@@ -69,6 +74,7 @@ func ShareFromValidatorAddedEvent(validatorAddedEvent eth1.ValidatorAddedEvent,
 			IbftId: nodeID,
 			Pk:     oess.SharedPublicKey,
 		}
+		operators[nodeID] = string(oess.OperatorPublicKey)
 		if strings.EqualFold(string(oess.OperatorPublicKey), operatorPubKey) {
 			ibftCommittee[nodeID].Pk = oess.SharedPublicKey
 			validatorShare.NodeID = nodeID
@@ -80,6 +86,23 @@ func ShareFromValidatorAddedEvent(validatorAddedEvent eth1.ValidatorAddedEvent,
 		}
 	}
 	validatorShare.Committee = ibftCommittee
+	validatorShare.Operators = operators
 
 	return &validatorShare, shareKey, nil
 }
+
+// ShareKeyManagerResolver returns a resolver of the key manager backend that was chosen for each share
+func ShareKeyManagerResolver(db basedb.IDb, logger *zap.Logger) keymanager.ShareBackendResolver {
+	collection := validatorstorage.NewCollection(validatorstorage.CollectionOptions{DB: db, Logger: logger})
+	return func(pk []byte) (keymanager.Backend, bool) {
+		share, found, err := collection.GetValidatorShare(pk)
+		if err != nil {
+			logger.Warn("could not get share key manager", zap.String("pubKey", hex.EncodeToString(pk)), zap.Error(err))
+			return "", false
+		}
+		if !found || len(share.KeyManager) == 0 {
+			return "", false
+		}
+		return keymanager.Backend(share.KeyManager), true
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/bloxapp/ssv/utils/format"
 	"github.com/bloxapp/ssv/validator/storage"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -37,6 +38,21 @@ type Options struct {
 	DB                         basedb.IDb
 	Fork                       forks.Fork
 	Signer                     beacon.Signer
+	MsgQueueOptions            msgqueue.Options
+	// DryRun runs duties consensus w/o signing, broadcasting signatures or submitting to the beacon node
+	DryRun bool
+	// DutyDeadlineSlots is the number of slots from the duty's slot after which attestation consensus is aborted
+	DutyDeadlineSlots uint64
+	// DutyTimeout is the max duration of duties w/o a slot deadline (e.g. voluntary exit), 0 disables the timeout
+	DutyTimeout time.Duration
+	// ConsensusSeqWindow is the max distance of a consensus message seq from the highest decided
+	ConsensusSeqWindow uint64
+	// Failover is used to skip duties while this instance is a standby, optional
+	Failover Failover
+	// Hooks are called on lifecycle events of the validator, optional
+	Hooks *HookRegistry
+	// inclusionTracker verifies the inclusion of submitted attestations, skipped if nil
+	inclusionTracker *inclusionTracker
 }
 
 // Validator struct that manages all ibft wrappers
@@ -47,13 +63,30 @@ type Validator struct {
 	ethNetwork                 *core.Network
 	beacon                     beacon.Beacon
 	ibfts                      map[beacon.RoleType]ibft.Controller
+	ibftsLock                  sync.RWMutex
+	newIbft                    func(role beacon.RoleType) ibft.Controller
 	msgQueue                   *msgqueue.MessageQueue
 	network                    network.Network
 	signatureCollectionTimeout time.Duration
-	valueCheck                 *valcheck.SlashingProtection
+	valueCheck                 *valcheck.Registry
 	startOnce                  sync.Once
 	fork                       forks.Fork
 	signer                     beacon.Signer
+	dryRun                     bool
+	dutyDeadlineSlots          uint64
+	dutyTimeout                time.Duration
+	inclusionTracker           *inclusionTracker
+	hooks                      *HookRegistry
+	failover                   Failover
+	// paused is set (1) when duties of the validator are paused
+	paused uint32
+	// started is set (1) once the validator was started
+	started uint32
+	// closed is set (1) once the validator was closed
+	closed uint32
+
+	connectivity     CommitteeConnectivity
+	connectivityLock sync.RWMutex
 }
 
 // New Validator creation
@@ -61,12 +94,19 @@ func New(opt Options) *Validator {
 	logger := opt.Logger.With(zap.String("pubKey", opt.Share.PublicKey.SerializeToHexStr())).
 		With(zap.Uint64("node_id", opt.Share.NodeID))
 
-	msgQueue := msgqueue.New()
+	msgQueue := msgqueue.NewWithOptions(opt.MsgQueueOptions)
+	newIbft := func(role beacon.RoleType) ibft.Controller {
+		return setupIbftController(role, logger, opt.DB, opt.Network, msgQueue, opt.Share, opt.Fork, opt.Signer, opt.ConsensusSeqWindow)
+	}
 	ibfts := make(map[beacon.RoleType]ibft.Controller)
-	ibfts[beacon.RoleTypeAttester] = setupIbftController(beacon.RoleTypeAttester, logger, opt.DB, opt.Network, msgQueue, opt.Share, opt.Fork, opt.Signer)
+	ibfts[beacon.RoleTypeAttester] = newIbft(beacon.RoleTypeAttester)
+	// the voluntary exit controller is created once an exit is requested, see ensureExitIbft
 	//ibfts[beacon.RoleAggregator] = setupIbftController(beacon.RoleAggregator, logger, db, opt.Network, msgQueue, opt.Share) TODO not supported for now
 	//ibfts[beacon.RoleProposer] = setupIbftController(beacon.RoleProposer, logger, db, opt.Network, msgQueue, opt.Share) TODO not supported for now
 
+	valueCheck := valcheck.New(opt.Signer, operatorPubKey(opt.Share))
+	valueCheck.RegisterCommon(valcheck.DutyRolePlugin(opt.Share.RoleEnabled))
+
 	// updating goclient map
 	if opt.Share.HasMetadata() && opt.Share.Metadata.Index > 0 {
 		blsPubkey := spec.BLSPubKey{}
@@ -82,13 +122,40 @@ func New(opt Options) *Validator {
 		signatureCollectionTimeout: opt.SignatureCollectionTimeout,
 		network:                    opt.Network,
 		ibfts:                      ibfts,
+		newIbft:                    newIbft,
 		ethNetwork:                 opt.ETHNetwork,
 		beacon:                     opt.Beacon,
-		valueCheck:                 valcheck.New(),
+		valueCheck:                 valueCheck,
 		startOnce:                  sync.Once{},
 		fork:                       opt.Fork,
 		signer:                     opt.Signer,
+		dryRun:                     opt.DryRun,
+		dutyDeadlineSlots:          opt.DutyDeadlineSlots,
+		dutyTimeout:                opt.DutyTimeout,
+		inclusionTracker:           opt.inclusionTracker,
+		hooks:                      opt.Hooks,
+		failover:                   opt.Failover,
+	}
+}
+
+// Failover decides whether this instance should sign, implemented by the failover coordinator
+type Failover interface {
+	// IsActive returns true if this instance should execute duties
+	IsActive() bool
+}
+
+// isStandby returns true if this instance is a failover standby that must not sign
+func (v *Validator) isStandby() bool {
+	return v.failover != nil && !v.failover.IsActive()
+}
+
+// operatorPubKey returns the serialized share public key of this operator, or nil if not found
+func operatorPubKey(share *storage.Share) []byte {
+	pk, err := share.OperatorPubKey()
+	if err != nil {
+		return nil
 	}
+	return pk.Serialize()
 }
 
 // Start validator
@@ -100,6 +167,8 @@ func (v *Validator) Start() error {
 	v.startOnce.Do(func() {
 		go v.listenToSignatureMessages()
 
+		v.ibftsLock.RLock()
+		defer v.ibftsLock.RUnlock()
 		for _, ib := range v.ibfts { // init all ibfts
 			go func(ib ibft.Controller) {
 				ReportIBFTStatus(v.Share.PublicKey.SerializeToHexStr(), false, false)
@@ -112,12 +181,62 @@ func (v *Validator) Start() error {
 			}(ib)
 		}
 
+		atomic.StoreUint32(&v.started, 1)
 		v.logger.Debug("validator started")
+		v.hooks.started(v.logger, v.Share)
 	})
 
 	return nil
 }
 
+// IsStarted returns true if the validator was started
+func (v *Validator) IsStarted() bool {
+	return atomic.LoadUint32(&v.started) == 1
+}
+
+// Close stops the iBFT controllers and the processing of signature messages, a closed validator can't be started
+func (v *Validator) Close() {
+	if !atomic.CompareAndSwapUint32(&v.closed, 0, 1) {
+		return
+	}
+	// makes sure a validator that wasn't started yet won't start
+	v.startOnce.Do(func() {})
+	v.ibftsLock.RLock()
+	for _, ib := range v.ibfts {
+		ib.Close()
+	}
+	v.ibftsLock.RUnlock()
+	atomic.StoreUint32(&v.started, 0)
+	v.logger.Debug("validator closed")
+}
+
+// isClosed returns true if the validator was closed
+func (v *Validator) isClosed() bool {
+	return atomic.LoadUint32(&v.closed) == 1
+}
+
+// SetPaused pauses or resumes duties execution of the validator
+func (v *Validator) SetPaused(paused bool) {
+	var val uint32
+	if paused {
+		val = 1
+	}
+	atomic.StoreUint32(&v.paused, val)
+}
+
+// IsPaused returns true if duties execution of the validator is paused
+func (v *Validator) IsPaused() bool {
+	return atomic.LoadUint32(&v.paused) == 1
+}
+
+// messageVersion returns the message version of the fork, the latest version is returned if no fork was set
+func (v *Validator) messageVersion() network.MessageVersion {
+	if v.fork == nil {
+		return network.CurrentMessageVersion
+	}
+	return v.fork.IBFTControllerFork().MessageVersion()
+}
+
 func (v *Validator) listenToSignatureMessages() {
 	sigChan := v.network.ReceivedSignatureChan()
 	for sigMsg := range sigChan {
@@ -126,8 +245,14 @@ func (v *Validator) listenToSignatureMessages() {
 			continue
 		}
 
+		// the channel is drained once closed, otherwise the network would be blocked
+		if v.isClosed() {
+			continue
+		}
+
 		if sigMsg.Message != nil && v.oneOfIBFTIdentifiers(sigMsg.Message.Lambda) {
 			v.msgQueue.AddMessage(&network.Message{
+				Version:       v.messageVersion(),
 				SignedMessage: sigMsg,
 				Type:          network.NetworkMsg_SignatureType,
 			})
@@ -142,6 +267,27 @@ func (v *Validator) getSlotStartTime(slot uint64) time.Time {
 	return start
 }
 
+// dutyDeadline returns the time after which the given duty is late, zero if the duty has no deadline
+func (v *Validator) dutyDeadline(duty *beacon.Duty) time.Time {
+	if v.dutyDeadlineSlots == 0 || duty.Type != beacon.RoleTypeAttester {
+		return time.Time{}
+	}
+	return v.getSlotStartTime(uint64(duty.Slot)).Add(time.Duration(v.dutyDeadlineSlots) * v.ethNetwork.SlotDurationSec())
+}
+
+// dutyContext returns the context of the given duty, which is canceled once the duty is no longer useful:
+// attestations once their consensus deadline and the signatures collection timeout have passed,
+// other duties once the duty timeout has passed (if configured)
+func (v *Validator) dutyContext(parent context.Context, duty *beacon.Duty) (context.Context, context.CancelFunc) {
+	if deadline := v.dutyDeadline(duty); !deadline.IsZero() {
+		return context.WithDeadline(parent, deadline.Add(v.signatureCollectionTimeout))
+	}
+	if v.dutyTimeout > 0 {
+		return context.WithTimeout(parent, v.dutyTimeout)
+	}
+	return context.WithCancel(parent)
+}
+
 func setupIbftController(
 	role beacon.RoleType,
 	logger *zap.Logger,
@@ -151,10 +297,14 @@ func setupIbftController(
 	share *storage.Share,
 	fork forks.Fork,
 	signer beacon.Signer,
+	seqWindow uint64,
 ) ibft.Controller {
 
 	ibftStorage := collections.NewIbft(db, logger, role.String())
 	identifier := []byte(format.IdentifierFormat(share.PublicKey.Serialize(), role.String()))
+	if _, err := ibftStorage.MigrateLegacyKeys(identifier); err != nil {
+		logger.Error("failed to migrate ibft storage keys", zap.Error(err))
+	}
 	return controller2.New(
 		role,
 		identifier,
@@ -165,11 +315,14 @@ func setupIbftController(
 		proto.DefaultConsensusParams(),
 		share,
 		fork.IBFTControllerFork(),
-		signer)
+		signer,
+		seqWindow)
 }
 
 // oneOfIBFTIdentifiers will return true if provided identifier matches one of the iBFT instances.
 func (v *Validator) oneOfIBFTIdentifiers(toMatch []byte) bool {
+	v.ibftsLock.RLock()
+	defer v.ibftsLock.RUnlock()
 	for _, i := range v.ibfts {
 		if bytes.Equal(i.GetIdentifier(), toMatch) {
 			return true
@@ -177,3 +330,39 @@ func (v *Validator) oneOfIBFTIdentifiers(toMatch []byte) bool {
 	}
 	return false
 }
+
+// getIbft returns the iBFT controller of the given role
+func (v *Validator) getIbft(role beacon.RoleType) (ibft.Controller, bool) {
+	v.ibftsLock.RLock()
+	defer v.ibftsLock.RUnlock()
+	ib, found := v.ibfts[role]
+	return ib, found
+}
+
+// ensureExitIbft creates and initializes the voluntary exit iBFT controller if it doesn't exist yet.
+// exits are rare, therefore the controller is not created along with the validator
+func (v *Validator) ensureExitIbft() error {
+	v.ibftsLock.Lock()
+	if _, found := v.ibfts[beacon.RoleTypeVoluntaryExit]; found {
+		v.ibftsLock.Unlock()
+		return nil
+	}
+	// the controllers of a validator that wasn't started are initialized on start, and closed ones are not started again
+	if !v.IsStarted() || v.isClosed() {
+		v.ibftsLock.Unlock()
+		return errors.New("validator is not running")
+	}
+	ib := v.newIbft(beacon.RoleTypeVoluntaryExit)
+	v.ibfts[beacon.RoleTypeVoluntaryExit] = ib
+	v.ibftsLock.Unlock()
+
+	if err := ib.Init(); err != nil {
+		// the controller is removed so it will be created again on the next request
+		v.ibftsLock.Lock()
+		delete(v.ibfts, beacon.RoleTypeVoluntaryExit)
+		v.ibftsLock.Unlock()
+		ib.Close()
+		return errors.Wrap(err, "could not initialize voluntary exit ibft")
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package validator
 
 import (
+	"github.com/bloxapp/ssv/beacon"
+	"github.com/bloxapp/ssv/ibft"
 	"github.com/stretchr/testify/require"
+	"sync/atomic"
 	"testing"
 )
 
@@ -10,3 +13,29 @@ func TestIdentifierTest(t *testing.T) {
 	require.True(t, node.oneOfIBFTIdentifiers([]byte{1, 2, 3, 4}))
 	require.False(t, node.oneOfIBFTIdentifiers([]byte{1, 2, 3, 3}))
 }
+
+func TestEnsureExitIbft(t *testing.T) {
+	node := testingValidator(t, true, 4, []byte{1, 2, 3, 4})
+	created := 0
+	node.newIbft = func(role beacon.RoleType) ibft.Controller {
+		created++
+		require.Equal(t, beacon.RoleTypeVoluntaryExit, role)
+		return &testIBFT{decided: true, signaturesCount: 3, identifier: []byte{5, 6, 7, 8}}
+	}
+	_, found := node.getIbft(beacon.RoleTypeVoluntaryExit)
+	require.False(t, found)
+
+	require.EqualError(t, node.ensureExitIbft(), "validator is not running")
+	require.Equal(t, 0, created)
+
+	atomic.StoreUint32(&node.started, 1)
+	require.NoError(t, node.ensureExitIbft())
+	require.NoError(t, node.ensureExitIbft())
+	require.Equal(t, 1, created)
+	ib, found := node.getIbft(beacon.RoleTypeVoluntaryExit)
+	require.True(t, found)
+	require.True(t, node.oneOfIBFTIdentifiers([]byte{5, 6, 7, 8}))
+
+	node.Close()
+	require.True(t, ib.(*testIBFT).closed)
+}
@@ -78,6 +78,18 @@ func (vm *validatorsMap) GetOrCreateValidator(share *storage.Share) *Validator {
 	return vm.validatorsMap[pubKey]
 }
 
+// RemoveValidator removes the given validator from the map, returns the removed validator
+func (vm *validatorsMap) RemoveValidator(pubKey string) (*Validator, bool) {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	v, ok := vm.validatorsMap[pubKey]
+	if ok {
+		delete(vm.validatorsMap, pubKey)
+	}
+	return v, ok
+}
+
 // Size returns the number of validators in the map
 func (vm *validatorsMap) Size() int {
 	vm.lock.RLock()